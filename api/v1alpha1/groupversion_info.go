@@ -14,9 +14,6 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package v1alpha1 contains API Schema definitions for the dbaas v1alpha1 API group
-//+kubebuilder:object:generate=true
-//+groupName=dbaas.redhat.com
 package v1alpha1
 
 import (
@@ -33,4 +30,13 @@ var (
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
+
+	// SchemeGroupVersion is the group version used by the generated clients in pkg/client
+	SchemeGroupVersion = GroupVersion
 )
+
+// Resource takes an unqualified resource and returns a group qualified GroupResource, it is used by the generated
+// listers in pkg/client
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
@@ -21,8 +21,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+//+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdsconn,categories=dbaas
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Inventory",type="string",JSONPath=".spec.inventoryRef.name"
+//+kubebuilder:printcolumn:name="Service",type="string",JSONPath=".spec.databaseServiceID"
+//+kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.databaseServiceType"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="ReadyForBinding")].status`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // RDSConnection is the Schema for the rdsconnections API
 type RDSConnection struct {
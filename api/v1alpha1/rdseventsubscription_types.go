@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RDSEventSubscriptionSpec defines the desired state of RDSEventSubscription
+type RDSEventSubscriptionSpec struct {
+	// A reference to the Inventory of the event subscription
+	InventoryRef v1beta1.NamespacedName `json:"inventoryRef"`
+
+	// The name of the event subscription, defaults to the name of the RDSEventSubscription
+	// +optional
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+
+	// The ARN of the SNS topic the events are sent to
+	SnsTopicARN string `json:"snsTopicARN"`
+
+	// The type of the sources of the events, the events of all the sources are sent if not set
+	// +kubebuilder:validation:Enum=db-instance;db-cluster;db-parameter-group;db-security-group;db-snapshot;db-cluster-snapshot;db-proxy
+	// +optional
+	SourceType string `json:"sourceType,omitempty"`
+
+	// The categories of the events, for example availability, failover or maintenance, the events of all the
+	// categories of the source type are sent if not set
+	// +optional
+	EventCategories []string `json:"eventCategories,omitempty"`
+
+	// The identifiers of the sources of the events, the events of all the sources of the source type are sent if
+	// neither the sources nor the Instances are set
+	// +optional
+	SourceIDs []string `json:"sourceIDs,omitempty"`
+
+	// The names of the RDSInstances whose DB instances are sources of the events, the source type must be db-instance
+	// +optional
+	InstanceRefs []string `json:"instanceRefs,omitempty"`
+
+	// Whether the events are sent, defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// RDSEventSubscriptionStatus defines the observed state of RDSEventSubscription
+type RDSEventSubscriptionStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The ARN of the event subscription
+	SubscriptionARN string `json:"subscriptionARN,omitempty"`
+
+	// The status of the event subscription in AWS, for example active, no-permission or topic-not-exist
+	SubscriptionStatus string `json:"subscriptionStatus,omitempty"`
+
+	// The identifiers of the sources of the events of the event subscription
+	// +optional
+	SourceIDs []string `json:"sourceIDs,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdses,categories=dbaas
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Source Type",type="string",JSONPath=".spec.sourceType"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.subscriptionStatus"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="EventSubscriptionReady")].status`
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="EventSubscriptionReady")].reason`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RDSEventSubscription is the Schema for the rdseventsubscriptions API
+type RDSEventSubscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSEventSubscriptionSpec   `json:"spec,omitempty"`
+	Status RDSEventSubscriptionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RDSEventSubscriptionList contains a list of RDSEventSubscription
+type RDSEventSubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSEventSubscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RDSEventSubscription{}, &RDSEventSubscriptionList{})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ProvisioningApprovedAnnotation approves the provisioning of an Instance matching the approval policy of its
+// Inventory if true, it can only be set by the users allowed to update the rdsinstances/approval subresource
+const ProvisioningApprovedAnnotation = "rds.dbaas.redhat.com/provisioning-approved"
+
+// the subresource the approvers of the Instances are allowed to update, it is only checked by the webhook
+const instanceApprovalSubresource = "approval"
+
+const instanceApprovalWebhookPath = "/validate-dbaas-redhat-com-v1alpha1-rdsinstance-approval"
+
+//+kubebuilder:webhook:path=/validate-dbaas-redhat-com-v1alpha1-rdsinstance-approval,mutating=false,failurePolicy=fail,sideEffects=None,groups=dbaas.redhat.com,resources=rdsinstances,verbs=create;update,versions=v1alpha1,name=vrdsinstanceapproval.kb.io,admissionReviewVersions=v1
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// instanceApprovalValidator rejects the approvals of Instances by the users that are not allowed to update their
+// approval subresource, the old-style validator of Instance does not get the user of the request
+type instanceApprovalValidator struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+// setupApprovalWebhookWithManager registers the approval webhook of Instance
+func setupApprovalWebhookWithManager(mgr ctrl.Manager) error {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	mgr.GetWebhookServer().Register(instanceApprovalWebhookPath, &webhook.Admission{
+		Handler: &instanceApprovalValidator{client: mgr.GetClient(), decoder: decoder},
+	})
+	return nil
+}
+
+// Handle allows the requests that do not set the approval annotation of the Instance to true
+func (v *instanceApprovalValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	instance := &RDSInstance{}
+	if err := v.decoder.DecodeRaw(req.Object, instance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if instance.Annotations[ProvisioningApprovedAnnotation] != "true" {
+		return admission.Allowed("")
+	}
+	if req.Operation == admissionv1.Update {
+		old := &RDSInstance{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Annotations[ProvisioningApprovedAnnotation] == "true" {
+			return admission.Allowed("")
+		}
+	}
+
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range req.UserInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   req.Namespace,
+				Verb:        "update",
+				Group:       GroupVersion.Group,
+				Resource:    "rdsinstances",
+				Subresource: instanceApprovalSubresource,
+				Name:        req.Name,
+			},
+		},
+	}
+	if err := v.client.Create(ctx, review); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !review.Status.Allowed {
+		return admission.Denied(fmt.Sprintf("user %s is not allowed to approve the provisioning of Instances, "+
+			"annotation %s can only be set by the users allowed to update rdsinstances/%s", req.UserInfo.Username,
+			ProvisioningApprovedAnnotation, instanceApprovalSubresource))
+	}
+	return admission.Allowed("")
+}
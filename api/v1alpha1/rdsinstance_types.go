@@ -21,8 +21,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+//+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdsint,categories=dbaas
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Engine",type="string",JSONPath=".status.instanceInfo.engine"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.instanceInfo.engineVersion",priority=1
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=`.status.instanceInfo.endpoint\.address`
+//+kubebuilder:printcolumn:name="Region",type="string",JSONPath=`.status.instanceInfo.ackResourceMetadata\.region`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // RDSInstance is the Schema for the rdsinstances API
 type RDSInstance struct {
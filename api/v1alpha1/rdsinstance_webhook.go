@@ -0,0 +1,245 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// ExtraParameters is the provisioning parameter of an Instance holding a JSON object of DB Instance spec fields
+// that are passed through to the DB Instance as they are
+const ExtraParameters = "ExtraParameters"
+
+// the provisioning parameter of the engine version of an Instance
+const engineVersionParameter = "EngineVersion"
+
+// the provisioning parameter of the time to live of an Instance
+const ttlParameter = "TTL"
+
+// log is for logging in this package.
+var rdsinstancelog = logf.Log.WithName("rdsinstance-resource")
+
+var instanceWebhookExtraParametersEnabled bool
+
+var instanceWebhookExtraParametersAllowList []string
+
+var instanceWebhookApiClient client.Client
+
+// SetupWebhookWithManager registers the webhooks of Instance, the extra parameters are only accepted
+// if enabled and all of their keys are in the allow-list
+func (r *RDSInstance) SetupWebhookWithManager(mgr ctrl.Manager, extraParametersEnabled bool, extraParametersAllowList []string) error {
+	instanceWebhookExtraParametersEnabled = extraParametersEnabled
+	instanceWebhookExtraParametersAllowList = extraParametersAllowList
+	if instanceWebhookApiClient == nil {
+		instanceWebhookApiClient = mgr.GetClient()
+	}
+	if err := setupApprovalWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-dbaas-redhat-com-v1alpha1-rdsinstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=dbaas.redhat.com,resources=rdsinstances,verbs=create;update,versions=v1alpha1,name=vrdsinstance.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RDSInstance{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RDSInstance) ValidateCreate() error {
+	rdsinstancelog.Info("validate create", "name", r.Name)
+	if err := r.validateInventoryProvisioning(); err != nil {
+		return err
+	}
+	if err := r.validateTTL(); err != nil {
+		return err
+	}
+	return r.validateExtraParameters()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RDSInstance) ValidateUpdate(old runtime.Object) error {
+	rdsinstancelog.Info("validate update", "name", r.Name)
+	o, ok := old.(*RDSInstance)
+	if ok {
+		if err := r.validateModifications(o); err != nil {
+			return err
+		}
+	}
+	if err := r.validateTTL(); err != nil {
+		return err
+	}
+	// do not block the updates of an Instance, e.g. removing its finalizer, on the extra parameters it is created with
+	if ok && o.Spec.ProvisioningParameters[ExtraParameters] == r.Spec.ProvisioningParameters[ExtraParameters] {
+		return nil
+	}
+	return r.validateExtraParameters()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RDSInstance) ValidateDelete() error {
+	rdsinstancelog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+// validateInventoryProvisioning rejects the Instances of the Inventories that do not allow provisioning, the Instances
+// of a missing Inventory are reported by the controller
+func (r *RDSInstance) validateInventoryProvisioning() error {
+	if instanceWebhookApiClient == nil {
+		return nil
+	}
+	inventory := &RDSInventory{}
+	if err := instanceWebhookApiClient.Get(context.TODO(), client.ObjectKey{Namespace: r.Spec.InventoryRef.Namespace,
+		Name: r.Spec.InventoryRef.Name}, inventory); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return inventory.ValidateProvisioning()
+}
+
+// validateTTL rejects the time to live of the Instance that is not a positive duration, e.g. 72h
+func (r *RDSInstance) validateTTL() error {
+	value, ok := r.Spec.ProvisioningParameters[ttlParameter]
+	if !ok {
+		return nil
+	}
+	if ttl, err := time.ParseDuration(value); err != nil || ttl <= 0 {
+		return fmt.Errorf("parameter %s %s is not a positive duration, e.g. 72h", ttlParameter, value)
+	}
+	return nil
+}
+
+func (r *RDSInstance) validateExtraParameters() error {
+	value, ok := r.Spec.ProvisioningParameters[ExtraParameters]
+	if !ok {
+		return nil
+	}
+	if !instanceWebhookExtraParametersEnabled {
+		return fmt.Errorf("parameter %s is not enabled", ExtraParameters)
+	}
+	_, err := ParseExtraParameters(value, instanceWebhookExtraParametersAllowList)
+	return err
+}
+
+// validateModifications rejects the modifications of the provisioning parameters that AWS cannot perform on the
+// provisioned DB instance, which would otherwise leave the DB Instance failing to modify it
+func (r *RDSInstance) validateModifications(old *RDSInstance) error {
+	if len(old.Status.InstanceID) == 0 {
+		return nil
+	}
+	oldParameters := old.Spec.ProvisioningParameters
+	parameters := r.Spec.ProvisioningParameters
+
+	for _, p := range []v1beta1.ProvisioningParameterType{v1beta1.ProvisioningDatabaseType, engineVersionParameter, v1beta1.ProvisioningStorageGib} {
+		if _, ok := oldParameters[p]; ok {
+			if _, ok := parameters[p]; !ok {
+				return fmt.Errorf("parameter %s can not be unset once the DB instance is provisioned with it", p)
+			}
+		}
+	}
+
+	engine := old.Status.InstanceInfo["engine"]
+	if len(engine) == 0 {
+		engine = oldParameters[v1beta1.ProvisioningDatabaseType]
+	}
+	if e, ok := parameters[v1beta1.ProvisioningDatabaseType]; ok && len(engine) > 0 && e != engine {
+		return fmt.Errorf("engine of the DB instance can not be changed from %s to %s, "+
+			"provision a new Instance and migrate the database to it instead", engine, e)
+	}
+
+	version := old.Status.InstanceInfo["engineVersion"]
+	if len(version) == 0 {
+		version = oldParameters[engineVersionParameter]
+	}
+	if v, ok := parameters[engineVersionParameter]; ok && len(version) > 0 && compareEngineVersions(v, version) < 0 {
+		return fmt.Errorf("engine version of the DB instance can not be downgraded from %s to %s, "+
+			"restore a snapshot to a new Instance of the engine version instead", version, v)
+	}
+
+	if s, ok := parameters[v1beta1.ProvisioningStorageGib]; ok {
+		storage, err := strconv.ParseInt(s, 10, 64)
+		oldStorage, oldErr := strconv.ParseInt(oldParameters[v1beta1.ProvisioningStorageGib], 10, 64)
+		if err == nil && oldErr == nil && storage < oldStorage {
+			return fmt.Errorf("allocated storage of the DB instance can not be reduced from %d GiB to %d GiB, "+
+				"keep the allocated storage or migrate the database to a new Instance with less storage", oldStorage, storage)
+		}
+	}
+	return nil
+}
+
+// compareEngineVersions compares the engine versions by their numeric parts, for example 8.0.28 or
+// 19.0.0.0.ru-2022-10.rur-2022-10.r1, and returns -1, 0 or 1 if the version a is lower, equal or greater than b
+func compareEngineVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			// the suffixes of the versions are not ordered
+			return 0
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseExtraParameters parses the JSON object of the extra parameters and verifies that all of its keys are allowed
+func ParseExtraParameters(value string, allowList []string) (map[string]json.RawMessage, error) {
+	parameters := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(value), &parameters); err != nil {
+		return nil, fmt.Errorf("value of parameter %s is not a JSON object: %v", ExtraParameters, err)
+	}
+
+	allowed := map[string]bool{}
+	for _, key := range allowList {
+		allowed[key] = true
+	}
+	var disallowed []string
+	for key := range parameters {
+		if !allowed[key] {
+			disallowed = append(disallowed, key)
+		}
+	}
+	if len(disallowed) > 0 {
+		sort.Strings(disallowed)
+		return nil, fmt.Errorf("keys %s of parameter %s are not allowed", strings.Join(disallowed, ","), ExtraParameters)
+	}
+	return parameters, nil
+}
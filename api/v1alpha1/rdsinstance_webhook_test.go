@@ -0,0 +1,194 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("RDSInstanceWebhook", func() {
+	newInstance := func(name, extraParameters string) *v1alpha1.RDSInstance {
+		return &v1alpha1.RDSInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: dbaasv1beta1.DBaaSInstanceSpec{
+				InventoryRef: dbaasv1beta1.NamespacedName{
+					Name:      "rds-inventory-instance-webhook",
+					Namespace: testNamespace,
+				},
+				ProvisioningParameters: map[dbaasv1beta1.ProvisioningParameterType]string{
+					dbaasv1beta1.ProvisioningName:         name,
+					dbaasv1beta1.ProvisioningDatabaseType: "postgres",
+					v1alpha1.ExtraParameters:              extraParameters,
+				},
+			},
+		}
+	}
+
+	Context("when extra parameters are allowed", func() {
+		It("should allow creating RDSInstance", func() {
+			instance := newInstance("rds-instance-webhook-allowed", `{"multiAZ":true,"performanceInsightsEnabled":true}`)
+			Expect(k8sClient.Create(ctx, instance)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, instance)).Should(Succeed())
+		})
+	})
+
+	Context("when extra parameters are not allowed", func() {
+		It("should not allow creating RDSInstance", func() {
+			instance := newInstance("rds-instance-webhook-not-allowed", `{"multiAZ":true,"masterUsername":"admin","port":5433}`)
+			Expect(k8sClient.Create(ctx, instance)).Should(MatchError("admission webhook \"vrdsinstance.kb.io\" denied the request: " +
+				"keys masterUsername,port of parameter ExtraParameters are not allowed"))
+		})
+	})
+
+	Context("when the time to live is set", func() {
+		It("should only allow a positive duration", func() {
+			instance := newInstance("rds-instance-webhook-ttl", "{}")
+			delete(instance.Spec.ProvisioningParameters, v1alpha1.ExtraParameters)
+			instance.Spec.ProvisioningParameters["TTL"] = "72h"
+			Expect(instance.ValidateCreate()).Should(Succeed())
+			instance.Spec.ProvisioningParameters["TTL"] = "3d"
+			Expect(instance.ValidateCreate()).Should(MatchError(ContainSubstring("parameter TTL 3d is not a positive duration")))
+			instance.Spec.ProvisioningParameters["TTL"] = "-1h"
+			Expect(instance.ValidateUpdate(instance)).ShouldNot(Succeed())
+		})
+	})
+
+	Context("when provisioned RDSInstance is modified", func() {
+		newProvisionedInstance := func(parameters map[dbaasv1beta1.ProvisioningParameterType]string) *v1alpha1.RDSInstance {
+			instance := newInstance("rds-instance-webhook-provisioned", "{}")
+			delete(instance.Spec.ProvisioningParameters, v1alpha1.ExtraParameters)
+			for k, v := range parameters {
+				instance.Spec.ProvisioningParameters[k] = v
+			}
+			instance.Status.InstanceID = "rds-instance-webhook-provisioned"
+			return instance
+		}
+		old := newProvisionedInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+			"EngineVersion":                     "14.5",
+			dbaasv1beta1.ProvisioningStorageGib: "100",
+		})
+
+		It("should allow the modifications AWS can perform", func() {
+			instance := newProvisionedInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+				"EngineVersion":                     "14.6",
+				dbaasv1beta1.ProvisioningStorageGib: "200",
+			})
+			Expect(instance.ValidateUpdate(old)).Should(Succeed())
+			Expect(old.ValidateUpdate(old)).Should(Succeed())
+		})
+
+		It("should not allow downgrading the engine version", func() {
+			instance := newProvisionedInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+				"EngineVersion":                     "13.8",
+				dbaasv1beta1.ProvisioningStorageGib: "100",
+			})
+			Expect(instance.ValidateUpdate(old)).Should(MatchError(ContainSubstring("can not be downgraded from 14.5 to 13.8")))
+		})
+
+		It("should not allow reducing the allocated storage", func() {
+			instance := newProvisionedInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+				"EngineVersion":                     "14.5",
+				dbaasv1beta1.ProvisioningStorageGib: "50",
+			})
+			Expect(instance.ValidateUpdate(old)).Should(MatchError(ContainSubstring("can not be reduced from 100 GiB to 50 GiB")))
+
+			delete(instance.Spec.ProvisioningParameters, dbaasv1beta1.ProvisioningStorageGib)
+			Expect(instance.ValidateUpdate(old)).ShouldNot(Succeed())
+		})
+
+		It("should not allow changing the engine", func() {
+			instance := newProvisionedInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+				dbaasv1beta1.ProvisioningDatabaseType: "mysql",
+				"EngineVersion":                       "14.5",
+				dbaasv1beta1.ProvisioningStorageGib:   "100",
+			})
+			Expect(instance.ValidateUpdate(old)).Should(MatchError(ContainSubstring("can not be changed from postgres to mysql")))
+		})
+	})
+
+	Context("when the Inventory does not allow provisioning", func() {
+		inventory := &v1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "rds-inventory-instance-webhook",
+				Namespace:   testNamespace,
+				Annotations: map[string]string{v1alpha1.AllowProvisioningAnnotation: "false"},
+			},
+			Spec: dbaasv1beta1.DBaaSInventorySpec{
+				CredentialsRef: &dbaasv1beta1.LocalObjectReference{
+					Name: "credentials-ref-instance-webhook",
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, inventory)).Should(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(inventory), &v1alpha1.RDSInventory{})
+			}, timeout).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, inventory)).Should(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(inventory), &v1alpha1.RDSInventory{}))
+			}, timeout).Should(BeTrue())
+		})
+
+		It("should not allow creating RDSInstance", func() {
+			instance := newInstance("rds-instance-webhook-read-only", "{}")
+			Eventually(func() error {
+				return k8sClient.Create(ctx, instance)
+			}, timeout).Should(MatchError(ContainSubstring("provisioning not allowed by Inventory " + testNamespace +
+				"/rds-inventory-instance-webhook")))
+			// the Instance is created if the cache of the webhook does not have the Inventory yet
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, instance))).Should(Succeed())
+		})
+	})
+
+	Context("when the annotation of the Inventory is set", func() {
+		It("should only allow provisioning unless the annotation is false", func() {
+			inventory := &v1alpha1.RDSInventory{}
+			Expect(inventory.IsProvisioningAllowed()).Should(BeTrue())
+			inventory.Annotations = map[string]string{v1alpha1.AllowProvisioningAnnotation: "true"}
+			Expect(inventory.ValidateProvisioning()).Should(Succeed())
+			inventory.Annotations[v1alpha1.AllowProvisioningAnnotation] = "false"
+			Expect(inventory.IsProvisioningAllowed()).Should(BeFalse())
+			Expect(inventory.ValidateProvisioning()).ShouldNot(Succeed())
+			inventory.Annotations[v1alpha1.AllowProvisioningAnnotation] = "no"
+			Expect(inventory.ValidateProvisioning()).Should(MatchError("value no of annotation " +
+				"rds.dbaas.redhat.com/allow-provisioning is invalid"))
+		})
+	})
+
+	Context("when extra parameters are not a JSON object", func() {
+		It("should not allow creating RDSInstance", func() {
+			instance := newInstance("rds-instance-webhook-invalid", `multiAZ=true`)
+			Expect(k8sClient.Create(ctx, instance)).ShouldNot(Succeed())
+		})
+	})
+})
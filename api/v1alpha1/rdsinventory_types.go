@@ -17,12 +17,25 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// AllowProvisioningAnnotation disables the provisioning of Instances against the Inventory if false, the DB services
+// of its AWS account are then only discovered and bound by Connections
+const AllowProvisioningAnnotation = "rds.dbaas.redhat.com/allow-provisioning"
+
+//+genclient
 //+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdsinv,categories=dbaas
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Region",type="string",JSONPath=`.metadata.labels.rds\.dbaas\.redhat\.com/region`
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="SpecSynced")].status`
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="SpecSynced")].reason`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // RDSInventory is the Schema for the rdsinventories API
 type RDSInventory struct {
@@ -42,6 +55,33 @@ type RDSInventoryList struct {
 	Items           []RDSInventory `json:"items"`
 }
 
+// IsProvisioningAllowed returns whether Instances can be provisioned against the Inventory, they can unless its
+// annotation is false
+func (r *RDSInventory) IsProvisioningAllowed() (bool, error) {
+	value, ok := r.Annotations[AllowProvisioningAnnotation]
+	if !ok || len(value) == 0 {
+		return true, nil
+	}
+	allowed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("value %s of annotation %s is invalid", value, AllowProvisioningAnnotation)
+	}
+	return allowed, nil
+}
+
+// ValidateProvisioning returns an error if Instances cannot be provisioned against the Inventory
+func (r *RDSInventory) ValidateProvisioning() error {
+	allowed, err := r.IsProvisioningAllowed()
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("provisioning not allowed by Inventory %s/%s as its annotation %s is false, "+
+			"bind its existing DB services with a Connection instead", r.Namespace, r.Name, AllowProvisioningAnnotation)
+	}
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&RDSInventory{}, &RDSInventoryList{})
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RDSMigrationPhase is the phase of the migration Job of an RDSMigration
+type RDSMigrationPhase string
+
+const (
+	RDSMigrationPhasePending   RDSMigrationPhase = "Pending"
+	RDSMigrationPhaseRunning   RDSMigrationPhase = "Running"
+	RDSMigrationPhaseSucceeded RDSMigrationPhase = "Succeeded"
+	RDSMigrationPhaseFailed    RDSMigrationPhase = "Failed"
+)
+
+// RDSMigrationSpec defines the desired state of RDSMigration
+type RDSMigrationSpec struct {
+	// A reference to the Secret with the host, port, database, username and password keys of the source database,
+	// in the namespace of the RDSMigration
+	SourceSecretRef v1.LocalObjectReference `json:"sourceSecretRef"`
+
+	// A reference to the target Instance, in the namespace of the RDSMigration, the engine of the source database
+	// must be the engine of the Instance
+	TargetInstanceRef v1.LocalObjectReference `json:"targetInstanceRef"`
+
+	// The name of the target database, defaults to the database of the Instance
+	// +optional
+	TargetDatabase string `json:"targetDatabase,omitempty"`
+
+	// Drop the objects of the target database before they are restored
+	// +optional
+	Clean bool `json:"clean,omitempty"`
+
+	// The number of retries of the migration Job before it is failed, defaults to 0
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// The duration in seconds the migration Job may run before it is failed, not limited by default
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// RDSMigrationStatus defines the observed state of RDSMigration
+type RDSMigrationStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The phase of the migration
+	Phase RDSMigrationPhase `json:"phase,omitempty"`
+
+	// The name of the migration Job
+	JobName string `json:"jobName,omitempty"`
+
+	// The engine of the migrated database
+	Engine string `json:"engine,omitempty"`
+
+	// The number of failed attempts of the migration Job
+	FailedAttempts int32 `json:"failedAttempts,omitempty"`
+
+	// The time the migration Job started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time the migration Job finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdsmig,categories=dbaas
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetInstanceRef.name"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Failed Attempts",type="integer",JSONPath=".status.failedAttempts",priority=1
+//+kubebuilder:printcolumn:name="Started",type="date",JSONPath=".status.startTime",priority=1
+//+kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RDSMigration is the Schema for the rdsmigrations API
+type RDSMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSMigrationSpec   `json:"spec,omitempty"`
+	Status RDSMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RDSMigrationList contains a list of RDSMigration
+type RDSMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RDSMigration{}, &RDSMigrationList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RDSOptionGroupSpec defines the desired state of RDSOptionGroup
+type RDSOptionGroupSpec struct {
+	// A reference to the Inventory of the DB option group
+	InventoryRef v1beta1.NamespacedName `json:"inventoryRef"`
+
+	// The name of the DB option group, defaults to the name of the RDSOptionGroup
+	// +optional
+	OptionGroupName string `json:"optionGroupName,omitempty"`
+
+	// The engine of the DB option group, for example oracle-ee or sqlserver-se, it cannot be changed once the DB
+	// option group is created
+	EngineName string `json:"engineName"`
+
+	// The major engine version of the DB option group, for example 19 or 15.00, it cannot be changed once the DB
+	// option group is created
+	MajorEngineVersion string `json:"majorEngineVersion"`
+
+	// The description of the DB option group
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The options of the DB option group, the options not listed are removed from the DB option group
+	// +optional
+	Options []RDSOption `json:"options,omitempty"`
+}
+
+// RDSOption defines an option of a DB option group
+type RDSOption struct {
+	// The name of the option, for example TDE, SQLSERVER_BACKUP_RESTORE or APEX
+	OptionName string `json:"optionName"`
+
+	// The version of the option
+	// +optional
+	OptionVersion string `json:"optionVersion,omitempty"`
+
+	// The port of the option, required by the options listening on a port
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// The VPC security groups allowed to connect to the port of the option
+	// +optional
+	VpcSecurityGroupMemberships []string `json:"vpcSecurityGroupMemberships,omitempty"`
+
+	// The values of the settings of the option by name
+	// +optional
+	OptionSettings map[string]string `json:"optionSettings,omitempty"`
+}
+
+// RDSOptionGroupStatus defines the observed state of RDSOptionGroup
+type RDSOptionGroupStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The ARN of the DB option group
+	OptionGroupARN string `json:"optionGroupARN,omitempty"`
+
+	// The minimum minor engine version of the DB instances using the DB option group, required by its options
+	// +optional
+	MinimumRequiredMinorEngineVersion string `json:"minimumRequiredMinorEngineVersion,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdsog,categories=dbaas
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Engine",type="string",JSONPath=".spec.engineName"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.majorEngineVersion"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="OptionGroupReady")].status`
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="OptionGroupReady")].reason`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RDSOptionGroup is the Schema for the rdsoptiongroups API
+type RDSOptionGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSOptionGroupSpec   `json:"spec,omitempty"`
+	Status RDSOptionGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RDSOptionGroupList contains a list of RDSOptionGroup
+type RDSOptionGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSOptionGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RDSOptionGroup{}, &RDSOptionGroupList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RDSParameterGroupSpec defines the desired state of RDSParameterGroup
+type RDSParameterGroupSpec struct {
+	// A reference to the Inventory of the DB parameter group
+	InventoryRef v1beta1.NamespacedName `json:"inventoryRef"`
+
+	// The name of the DB parameter group, defaults to the name of the RDSParameterGroup
+	// +optional
+	ParameterGroupName string `json:"parameterGroupName,omitempty"`
+
+	// The DB parameter group family, for example postgres14 or mysql8.0, it cannot be changed once the DB
+	// parameter group is created
+	Family string `json:"family"`
+
+	// The description of the DB parameter group
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The values of the parameters by name, the parameters not set keep the default value of the family
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// RDSParameterGroupStatus defines the observed state of RDSParameterGroup
+type RDSParameterGroupStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The ARN of the DB parameter group
+	ParameterGroupARN string `json:"parameterGroupARN,omitempty"`
+
+	// The static parameters modified that are applied once the DB instances are rebooted
+	PendingRebootParameters []string `json:"pendingRebootParameters,omitempty"`
+
+	// The DB instances using the DB parameter group that need to be rebooted to apply the parameters
+	PendingRebootDBInstances []string `json:"pendingRebootDBInstances,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdspg,categories=dbaas
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Family",type="string",JSONPath=".spec.family"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="ParameterGroupReady")].status`
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type=="ParameterGroupReady")].reason`
+//+kubebuilder:printcolumn:name="Pending Reboot",type="string",JSONPath=".status.pendingRebootDBInstances",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RDSParameterGroup is the Schema for the rdsparametergroups API
+type RDSParameterGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSParameterGroupSpec   `json:"spec,omitempty"`
+	Status RDSParameterGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RDSParameterGroupList contains a list of RDSParameterGroup
+type RDSParameterGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSParameterGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RDSParameterGroup{}, &RDSParameterGroupList{})
+}
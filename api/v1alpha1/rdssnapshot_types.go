@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RDSSnapshotSpec defines the desired state of RDSSnapshot
+type RDSSnapshotSpec struct {
+	// A reference to the Inventory of the source DB instance
+	InventoryRef v1beta1.NamespacedName `json:"inventoryRef"`
+
+	// The identifier of the source DB instance
+	DBInstanceID string `json:"dbInstanceID"`
+
+	// The identifier of the manual DB snapshot, defaults to the name of the RDSSnapshot
+	// +optional
+	SnapshotID string `json:"snapshotID,omitempty"`
+
+	// The IDs of the AWS accounts allowed to restore the DB snapshot
+	// +optional
+	ShareWithAccounts []string `json:"shareWithAccounts,omitempty"`
+
+	// Export the DB snapshot to Amazon S3 once it is available
+	// +optional
+	Export *RDSSnapshotExport `json:"export,omitempty"`
+}
+
+// RDSSnapshotExport defines the Amazon S3 export of a DB snapshot
+type RDSSnapshotExport struct {
+	// The name of the Amazon S3 bucket to export the DB snapshot to
+	S3BucketName string `json:"s3BucketName"`
+
+	// The Amazon S3 bucket prefix of the exported files
+	// +optional
+	S3Prefix string `json:"s3Prefix,omitempty"`
+
+	// The ARN of the IAM role used to write to the Amazon S3 bucket
+	IAMRoleARN string `json:"iamRoleARN"`
+
+	// The ID of the AWS KMS key used to encrypt the exported files
+	KMSKeyID string `json:"kmsKeyID"`
+
+	// The databases, schemas or tables to export, all data is exported by default
+	// +optional
+	ExportOnly []string `json:"exportOnly,omitempty"`
+}
+
+// RDSSnapshotStatus defines the observed state of RDSSnapshot
+type RDSSnapshotStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The ARN of the DB snapshot
+	SnapshotARN string `json:"snapshotARN,omitempty"`
+
+	// The status of the DB snapshot
+	SnapshotStatus string `json:"snapshotStatus,omitempty"`
+
+	// The identifier of the export task
+	ExportTaskID string `json:"exportTaskID,omitempty"`
+
+	// The status of the export task
+	ExportStatus string `json:"exportStatus,omitempty"`
+
+	// The progress of the export task as a percentage
+	ExportPercentProgress int32 `json:"exportPercentProgress,omitempty"`
+
+	// The Amazon S3 location of the exported files
+	ExportS3Location string `json:"exportS3Location,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rdssnap,categories=dbaas
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="DB Instance",type="string",JSONPath=".spec.dbInstanceID"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.snapshotStatus"
+//+kubebuilder:printcolumn:name="Export",type="string",JSONPath=".status.exportStatus"
+//+kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.exportPercentProgress",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RDSSnapshot is the Schema for the rdssnapshots API
+type RDSSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RDSSnapshotSpec   `json:"spec,omitempty"`
+	Status RDSSnapshotStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RDSSnapshotList contains a list of RDSSnapshot
+type RDSSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RDSSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RDSSnapshot{}, &RDSSnapshotList{})
+}
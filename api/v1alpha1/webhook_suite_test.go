@@ -109,6 +109,9 @@ var _ = BeforeSuite(func() {
 	err = (&v1alpha1.RDSInventory{}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = (&v1alpha1.RDSInstance{}).SetupWebhookWithManager(mgr, true, []string{"multiAZ", "performanceInsightsEnabled"})
+	Expect(err).NotTo(HaveOccurred())
+
 	//+kubebuilder:scaffold:webhook
 
 	go func() {
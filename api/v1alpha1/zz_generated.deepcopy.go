@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -84,6 +85,128 @@ func (in *RDSConnectionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSEventSubscription) DeepCopyInto(out *RDSEventSubscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSEventSubscription.
+func (in *RDSEventSubscription) DeepCopy() *RDSEventSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSEventSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSEventSubscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSEventSubscriptionList) DeepCopyInto(out *RDSEventSubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSEventSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSEventSubscriptionList.
+func (in *RDSEventSubscriptionList) DeepCopy() *RDSEventSubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSEventSubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSEventSubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSEventSubscriptionSpec) DeepCopyInto(out *RDSEventSubscriptionSpec) {
+	*out = *in
+	out.InventoryRef = in.InventoryRef
+	if in.EventCategories != nil {
+		in, out := &in.EventCategories, &out.EventCategories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceIDs != nil {
+		in, out := &in.SourceIDs, &out.SourceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceRefs != nil {
+		in, out := &in.InstanceRefs, &out.InstanceRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSEventSubscriptionSpec.
+func (in *RDSEventSubscriptionSpec) DeepCopy() *RDSEventSubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSEventSubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSEventSubscriptionStatus) DeepCopyInto(out *RDSEventSubscriptionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SourceIDs != nil {
+		in, out := &in.SourceIDs, &out.SourceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSEventSubscriptionStatus.
+func (in *RDSEventSubscriptionStatus) DeepCopy() *RDSEventSubscriptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSEventSubscriptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RDSInstance) DeepCopyInto(out *RDSInstance) {
 	*out = *in
@@ -201,3 +324,496 @@ func (in *RDSInventoryList) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSMigration) DeepCopyInto(out *RDSMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSMigration.
+func (in *RDSMigration) DeepCopy() *RDSMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSMigrationList) DeepCopyInto(out *RDSMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSMigrationList.
+func (in *RDSMigrationList) DeepCopy() *RDSMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSMigrationSpec) DeepCopyInto(out *RDSMigrationSpec) {
+	*out = *in
+	out.SourceSecretRef = in.SourceSecretRef
+	out.TargetInstanceRef = in.TargetInstanceRef
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSMigrationSpec.
+func (in *RDSMigrationSpec) DeepCopy() *RDSMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSMigrationStatus) DeepCopyInto(out *RDSMigrationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSMigrationStatus.
+func (in *RDSMigrationStatus) DeepCopy() *RDSMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSOption) DeepCopyInto(out *RDSOption) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VpcSecurityGroupMemberships != nil {
+		in, out := &in.VpcSecurityGroupMemberships, &out.VpcSecurityGroupMemberships
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OptionSettings != nil {
+		in, out := &in.OptionSettings, &out.OptionSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSOption.
+func (in *RDSOption) DeepCopy() *RDSOption {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSOptionGroup) DeepCopyInto(out *RDSOptionGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSOptionGroup.
+func (in *RDSOptionGroup) DeepCopy() *RDSOptionGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSOptionGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSOptionGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSOptionGroupList) DeepCopyInto(out *RDSOptionGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSOptionGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSOptionGroupList.
+func (in *RDSOptionGroupList) DeepCopy() *RDSOptionGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSOptionGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSOptionGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSOptionGroupSpec) DeepCopyInto(out *RDSOptionGroupSpec) {
+	*out = *in
+	out.InventoryRef = in.InventoryRef
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]RDSOption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSOptionGroupSpec.
+func (in *RDSOptionGroupSpec) DeepCopy() *RDSOptionGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSOptionGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSOptionGroupStatus) DeepCopyInto(out *RDSOptionGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSOptionGroupStatus.
+func (in *RDSOptionGroupStatus) DeepCopy() *RDSOptionGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSOptionGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSParameterGroup) DeepCopyInto(out *RDSParameterGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSParameterGroup.
+func (in *RDSParameterGroup) DeepCopy() *RDSParameterGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSParameterGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSParameterGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSParameterGroupList) DeepCopyInto(out *RDSParameterGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSParameterGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSParameterGroupList.
+func (in *RDSParameterGroupList) DeepCopy() *RDSParameterGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSParameterGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSParameterGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSParameterGroupSpec) DeepCopyInto(out *RDSParameterGroupSpec) {
+	*out = *in
+	out.InventoryRef = in.InventoryRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSParameterGroupSpec.
+func (in *RDSParameterGroupSpec) DeepCopy() *RDSParameterGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSParameterGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSParameterGroupStatus) DeepCopyInto(out *RDSParameterGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingRebootParameters != nil {
+		in, out := &in.PendingRebootParameters, &out.PendingRebootParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRebootDBInstances != nil {
+		in, out := &in.PendingRebootDBInstances, &out.PendingRebootDBInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSParameterGroupStatus.
+func (in *RDSParameterGroupStatus) DeepCopy() *RDSParameterGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSParameterGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSSnapshot) DeepCopyInto(out *RDSSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSSnapshot.
+func (in *RDSSnapshot) DeepCopy() *RDSSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSSnapshotExport) DeepCopyInto(out *RDSSnapshotExport) {
+	*out = *in
+	if in.ExportOnly != nil {
+		in, out := &in.ExportOnly, &out.ExportOnly
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSSnapshotExport.
+func (in *RDSSnapshotExport) DeepCopy() *RDSSnapshotExport {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSSnapshotExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSSnapshotList) DeepCopyInto(out *RDSSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RDSSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSSnapshotList.
+func (in *RDSSnapshotList) DeepCopy() *RDSSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RDSSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSSnapshotSpec) DeepCopyInto(out *RDSSnapshotSpec) {
+	*out = *in
+	out.InventoryRef = in.InventoryRef
+	if in.ShareWithAccounts != nil {
+		in, out := &in.ShareWithAccounts, &out.ShareWithAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(RDSSnapshotExport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSSnapshotSpec.
+func (in *RDSSnapshotSpec) DeepCopy() *RDSSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSSnapshotStatus) DeepCopyInto(out *RDSSnapshotStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSSnapshotStatus.
+func (in *RDSSnapshotStatus) DeepCopy() *RDSSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+)
+
+const (
+	// the number of AWS API calls made for the Inventory per day (UTC) above which it is synced at the minimum rate,
+	// it overrides the daily budget of the operator and 0 disables the budget of the Inventory
+	inventoryAPIDailyBudgetAnnotation = "rds.dbaas.redhat.com/aws-api-daily-budget"
+
+	inventoryConditionAPIBudgetExceeded = "APIBudgetExceeded"
+
+	apiBudgetReasonExceeded = "BudgetExceeded"
+
+	apiBudgetMessageExceeded = "%d AWS API calls made today for a daily budget of %d, the Inventory is synced every %s until %s"
+)
+
+var (
+	inventoryAPICalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_inventory_aws_api_calls_total",
+			Help: "The number of AWS API calls made for an Inventory, including the retried attempts.",
+		},
+		[]string{"namespace", "inventory", "service", "operation"},
+	)
+	inventoryAPICallsToday = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_inventory_aws_api_calls_today",
+			Help: "The number of AWS API calls made for an Inventory since the start of the day (UTC).",
+		},
+		[]string{"namespace", "inventory"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(inventoryAPICalls, inventoryAPICallsToday)
+}
+
+// APIBudget counts the AWS API calls made for each Inventory per day, once the daily budget of an Inventory is spent
+// it is synced at most once per minimum sync interval until the end of the day
+type APIBudget struct {
+	dailyBudget         int64
+	minimumSyncInterval time.Duration
+
+	mutex  sync.Mutex
+	usages map[string]*apiUsage
+}
+
+type apiUsage struct {
+	day      string
+	calls    int64
+	lastSync time.Time
+}
+
+// NewAPIBudget returns the budget of the Inventories, a daily budget of 0 only counts the calls unless an Inventory
+// sets its own budget
+func NewAPIBudget(dailyBudget int64, minimumSyncInterval time.Duration) *APIBudget {
+	return &APIBudget{
+		dailyBudget:         dailyBudget,
+		minimumSyncInterval: minimumSyncInterval,
+		usages:              map[string]*apiUsage{},
+	}
+}
+
+// withCallRecorder returns a context counting the AWS API calls made with it for the Inventory
+func (b *APIBudget) withCallRecorder(ctx context.Context, namespace, name string) context.Context {
+	if b == nil {
+		return ctx
+	}
+	return controllersrds.WithCallRecorder(ctx, func(service, operation string) {
+		b.record(namespace, name, service, operation, time.Now())
+	})
+}
+
+func (b *APIBudget) record(namespace, name, service, operation string, now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	u := b.usage(namespace, name, now)
+	u.calls++
+	inventoryAPICalls.WithLabelValues(namespace, name, service, operation).Inc()
+	inventoryAPICallsToday.WithLabelValues(namespace, name).Set(float64(u.calls))
+}
+
+// usage returns the usage of the day of the Inventory, the mutex must be held
+func (b *APIBudget) usage(namespace, name string, now time.Time) *apiUsage {
+	key := namespace + "/" + name
+	day := now.UTC().Format("2006-01-02")
+	u, ok := b.usages[key]
+	if !ok {
+		u = &apiUsage{day: day}
+		b.usages[key] = u
+	} else if u.day != day {
+		u.day = day
+		u.calls = 0
+		inventoryAPICallsToday.WithLabelValues(namespace, name).Set(0)
+	}
+	return u
+}
+
+// getDailyBudget returns the daily budget of the Inventory, 0 if it has none
+func (b *APIBudget) getDailyBudget(inventory *rdsdbaasv1alpha1.RDSInventory) (int64, error) {
+	v, ok := inventory.Annotations[inventoryAPIDailyBudgetAnnotation]
+	if !ok {
+		return b.dailyBudget, nil
+	}
+	budget, e := strconv.ParseInt(v, 10, 64)
+	if e != nil || budget < 0 {
+		return 0, fmt.Errorf("value of annotation %s is invalid", inventoryAPIDailyBudgetAnnotation)
+	}
+	return budget, nil
+}
+
+// throttle returns how long the sync of the Inventory is deferred as its daily budget is spent, 0 if it can sync now.
+// The APIBudgetExceeded condition is set while the budget is spent and removed otherwise.
+func (b *APIBudget) throttle(inventory *rdsdbaasv1alpha1.RDSInventory, now time.Time) (time.Duration, error) {
+	if b == nil {
+		return 0, nil
+	}
+	budget, e := b.getDailyBudget(inventory)
+	if e != nil {
+		return 0, e
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	u := b.usage(inventory.Namespace, inventory.Name, now)
+	if budget == 0 || u.calls < budget {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionAPIBudgetExceeded)
+		u.lastSync = now
+		return 0, nil
+	}
+
+	endOfDay := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+		Type:   inventoryConditionAPIBudgetExceeded,
+		Status: metav1.ConditionTrue,
+		Reason: apiBudgetReasonExceeded,
+		Message: fmt.Sprintf(apiBudgetMessageExceeded, u.calls, budget, b.minimumSyncInterval,
+			endOfDay.Format(time.RFC3339)),
+	})
+	next := u.lastSync.Add(b.minimumSyncInterval)
+	if endOfDay.Before(next) {
+		next = endOfDay
+	}
+	if wait := next.Sub(now); wait > 0 {
+		return wait, nil
+	}
+	u.lastSync = now
+	return 0, nil
+}
+
+// deleteAPIUsage removes the usage and the call counters of a deleted Inventory
+func (b *APIBudget) deleteAPIUsage(namespace, name string) {
+	if b != nil {
+		b.mutex.Lock()
+		delete(b.usages, namespace+"/"+name)
+		b.mutex.Unlock()
+	}
+	labels := prometheus.Labels{"namespace": namespace, "inventory": name}
+	inventoryAPICalls.DeletePartialMatch(labels)
+	inventoryAPICallsToday.DeletePartialMatch(labels)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("APIBudget", func() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	newInventory := func(annotations map[string]string) *rdsdbaasv1alpha1.RDSInventory {
+		return &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "api-budget", Name: "inventory", Annotations: annotations},
+		}
+	}
+
+	It("should slow down the sync of an Inventory once its budget is spent", func() {
+		b := NewAPIBudget(2, time.Hour)
+		defer b.deleteAPIUsage("api-budget", "inventory")
+		inventory := newInventory(nil)
+
+		wait, e := b.throttle(inventory, now)
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(wait).Should(BeZero())
+		b.record("api-budget", "inventory", "RDS", "DescribeDBInstances", now)
+		b.record("api-budget", "inventory", "RDS", "DescribeDBClusters", now)
+		Expect(testutil.ToFloat64(inventoryAPICallsToday.WithLabelValues("api-budget", "inventory"))).Should(BeNumerically("==", 2))
+
+		wait, e = b.throttle(inventory, now.Add(10*time.Minute))
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(wait).Should(Equal(50 * time.Minute))
+		Expect(apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionAPIBudgetExceeded)).Should(BeTrue())
+
+		// synced once per minimum interval
+		wait, e = b.throttle(inventory, now.Add(time.Hour))
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(wait).Should(BeZero())
+
+		// the budget is reset at the end of the day
+		wait, e = b.throttle(inventory, now.Add(12*time.Hour))
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(wait).Should(BeZero())
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionAPIBudgetExceeded)).Should(BeNil())
+	})
+
+	It("should use the budget of the Inventory annotation", func() {
+		b := NewAPIBudget(1, time.Hour)
+		defer b.deleteAPIUsage("api-budget", "inventory")
+		b.record("api-budget", "inventory", "RDS", "DescribeDBInstances", now)
+
+		wait, e := b.throttle(newInventory(map[string]string{inventoryAPIDailyBudgetAnnotation: "0"}), now)
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(wait).Should(BeZero())
+
+		_, e = b.throttle(newInventory(map[string]string{inventoryAPIDailyBudgetAnnotation: "-1"}), now)
+		Expect(e).Should(HaveOccurred())
+	})
+})
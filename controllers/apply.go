@@ -0,0 +1,279 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// fieldOwner is the field manager of the fields applied by the operator
+	fieldOwner = "rds-dbaas-operator"
+
+	// the comma-separated fields of an object generated by the operator that are managed by the users, the operator
+	// does not set them so that the values set by the users or by GitOps tools are not reverted, for example
+	// data.port,metadata.labels[app.kubernetes.io/part-of]
+	userManagedFieldsAnnotation = "rds.dbaas.redhat.com/user-managed-fields"
+
+	eventReasonFieldConflict = "FieldConflict"
+)
+
+// the fields identifying the object are always set by the operator
+var identityFields = map[string]bool{"apiVersion": true, "kind": true, "metadata.name": true, "metadata.namespace": true}
+
+// the fields the operator must own, the ownership of these fields is taken over from the other field managers, the
+// owner references garbage collect the objects and the type label makes them visible in the cache of the operator
+var operatorOwnedFields = [][]string{
+	{"metadata", "ownerReferences"},
+	{"metadata", "labels", dbaasv1beta1.TypeLabelKey},
+}
+
+type applyRecorderKey struct{}
+
+// withApplyRecorder returns a context recording the conflicts of the applies made with it as events of the objects
+func withApplyRecorder(ctx context.Context, recorder record.EventRecorder) context.Context {
+	if recorder == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, applyRecorderKey{}, recorder)
+}
+
+// createOrApply is the server-side apply counterpart of controllerutil.CreateOrUpdate. The object only holds its
+// name and namespace when mutate is called, mutate sets all the fields managed by the operator on it and reads the
+// fields of the existing object it depends on from existing, which is nil if the object does not exist yet. The fields
+// that are no longer set by mutate are removed if they are only managed by the operator, the fields set by users or
+// other controllers are kept.
+func createOrApply(ctx context.Context, c client.Client, obj client.Object,
+	mutate func(existing client.Object) error) (controllerutil.OperationResult, error) {
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		existing = nil
+	}
+
+	if err := mutate(existing); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	var userManagedFields [][]string
+	if existing != nil {
+		userManagedFields = parseUserManagedFields(existing.GetAnnotations()[userManagedFieldsAnnotation])
+	}
+	if err := applyObject(ctx, c, obj, userManagedFields...); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if existing == nil {
+		return controllerutil.OperationResultCreated, nil
+	}
+	if existing.GetResourceVersion() != obj.GetResourceVersion() {
+		return controllerutil.OperationResultUpdated, nil
+	}
+	return controllerutil.OperationResultNone, nil
+}
+
+// applyObject applies the fields set on the object, except the fields managed by the users, and replaces it with the
+// object stored in the cluster. The fields in conflict with another field manager are left to it, except the fields
+// the operator must own which are taken over, the conflict is logged and recorded as a warning event of the object.
+func applyObject(ctx context.Context, c client.Client, obj client.Object, userManagedFields ...[]string) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.DeepCopy().Object
+	} else if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+		return err
+	}
+	patch := &unstructured.Unstructured{Object: content}
+	patch.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(patch.Object, "status")
+	for _, field := range []string{"creationTimestamp", "resourceVersion", "uid", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(patch.Object, "metadata", field)
+	}
+	for _, field := range userManagedFields {
+		unstructured.RemoveNestedField(patch.Object, field...)
+	}
+
+	err = c.Patch(ctx, patch, client.Apply, client.FieldOwner(fieldOwner))
+	if errors.IsConflict(err) {
+		err = applyConflictingObject(ctx, c, patch, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		u.Object = patch.Object
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(patch.Object, obj)
+}
+
+// applyConflictingObject applies the patch rejected for the conflict again, without the conflicting fields left to the
+// other field managers and forcing the ownership of the conflicting fields the operator must own
+func applyConflictingObject(ctx context.Context, c client.Client, patch *unstructured.Unstructured, conflict error) error {
+	conflicts := getConflictingFields(conflict)
+	var left, forced []string
+	for _, path := range getFieldPaths(patch.Object, nil) {
+		field := formatFieldPath(path)
+		if !isConflictingField(field, conflicts) {
+			continue
+		}
+		if isOperatorOwnedField(path) {
+			forced = append(forced, field)
+			continue
+		}
+		unstructured.RemoveNestedField(patch.Object, path...)
+		left = append(left, field)
+	}
+	if len(left) == 0 && len(forced) == 0 {
+		return conflict
+	}
+	sort.Strings(left)
+	sort.Strings(forced)
+
+	log.FromContext(ctx).Info("Fields managed by another field manager", "kind", patch.GetKind(),
+		"namespace", patch.GetNamespace(), "name", patch.GetName(), "left", left, "takenOver", forced)
+	options := []client.PatchOption{client.FieldOwner(fieldOwner)}
+	if len(forced) > 0 {
+		options = append(options, client.ForceOwnership)
+	}
+	if err := c.Patch(ctx, patch, client.Apply, options...); err != nil {
+		return err
+	}
+
+	if recorder, ok := ctx.Value(applyRecorderKey{}).(record.EventRecorder); ok {
+		var messages []string
+		if len(left) > 0 {
+			messages = append(messages, "left to the other field managers: "+strings.Join(left, ", "))
+		}
+		if len(forced) > 0 {
+			messages = append(messages, "taken over by the operator: "+strings.Join(forced, ", "))
+		}
+		recorder.Eventf(patch, v1.EventTypeWarning, eventReasonFieldConflict, "Fields in conflict with another field manager %s",
+			strings.Join(messages, ", fields "))
+	}
+	return nil
+}
+
+// getConflictingFields returns the fields of the conflict causes, in the format of the managed fields paths, e.g.
+// .metadata.labels.app.kubernetes.io/part-of
+func getConflictingFields(err error) []string {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return nil
+	}
+	var fields []string
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict && len(cause.Field) > 0 {
+			fields = append(fields, cause.Field)
+		}
+	}
+	return fields
+}
+
+// getFieldPaths returns the paths of the fields set in the object, the lists are not descended into
+func getFieldPaths(object map[string]interface{}, prefix []string) [][]string {
+	var paths [][]string
+	for key, value := range object {
+		path := append(append([]string{}, prefix...), key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			paths = append(paths, getFieldPaths(child, path)...)
+			continue
+		}
+		if !identityFields[strings.Join(path, ".")] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func formatFieldPath(path []string) string {
+	return "." + strings.Join(path, ".")
+}
+
+// isConflictingField returns whether the field or one of its elements is in conflict
+func isConflictingField(field string, conflicts []string) bool {
+	for _, conflict := range conflicts {
+		if conflict == field || strings.HasPrefix(conflict, field+".") || strings.HasPrefix(conflict, field+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func isOperatorOwnedField(path []string) bool {
+	for _, owned := range operatorOwnedFields {
+		if len(path) >= len(owned) && formatFieldPath(path[:len(owned)]) == formatFieldPath(owned) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUserManagedFields returns the paths of the fields of the annotation of the user managed fields, the keys with
+// dots are set in brackets, e.g. metadata.annotations[example.com/key]
+func parseUserManagedFields(value string) [][]string {
+	var fields [][]string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) == 0 || identityFields[field] {
+			continue
+		}
+		var path []string
+		for len(field) > 0 {
+			if strings.HasPrefix(field, "[") {
+				end := strings.Index(field, "]")
+				if end < 0 {
+					path = nil
+					break
+				}
+				path = append(path, field[1:end])
+				field = strings.TrimPrefix(field[end+1:], ".")
+				continue
+			}
+			end := strings.IndexAny(field, ".[")
+			if end < 0 {
+				path = append(path, field)
+				break
+			}
+			path = append(path, field[:end])
+			field = strings.TrimPrefix(field[end:], ".")
+		}
+		if len(path) > 0 {
+			fields = append(fields, path)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apply", func() {
+	It("should parse the user managed fields", func() {
+		Expect(parseUserManagedFields("")).Should(BeEmpty())
+		Expect(parseUserManagedFields("data.port, metadata.labels[app.kubernetes.io/part-of],spec.provisioningParameters[storageGib].displayName")).
+			Should(Equal([][]string{
+				{"data", "port"},
+				{"metadata", "labels", "app.kubernetes.io/part-of"},
+				{"spec", "provisioningParameters", "storageGib", "displayName"},
+			}))
+		// the fields identifying the object and the invalid fields are ignored
+		Expect(parseUserManagedFields("metadata.name,kind,metadata.labels[app")).Should(BeEmpty())
+	})
+
+	Describe("createOrApply", func() {
+		var cli *applyClient
+		var recorder *record.FakeRecorder
+		var ctx context.Context
+
+		BeforeEach(func() {
+			existing := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "apply-test"},
+				Data:       map[string]string{"host": "old", "port": "5432"},
+			}
+			cli = &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(existing).Build()}
+			recorder = record.NewFakeRecorder(10)
+			ctx = withApplyRecorder(context.Background(), recorder)
+		})
+
+		apply := func(name string) (controllerutil.OperationResult, error) {
+			cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: name}}
+			return createOrApply(ctx, cli, cm, func(client.Object) error {
+				cm.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+				cm.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: "owner", UID: "uid"}}
+				cm.Data = map[string]string{"host": "new", "port": "3306"}
+				return nil
+			})
+		}
+
+		get := func(name string) *v1.ConfigMap {
+			cm := &v1.ConfigMap{}
+			Expect(cli.Get(context.Background(), client.ObjectKey{Namespace: "operator", Name: name}, cm)).Should(Succeed())
+			return cm
+		}
+
+		It("should create and update the object without forcing the ownership", func() {
+			result, err := apply("apply-test-new")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result).Should(Equal(controllerutil.OperationResultCreated))
+
+			result, err = apply("apply-test")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result).Should(Equal(controllerutil.OperationResultUpdated))
+			Expect(get("apply-test").Data).Should(Equal(map[string]string{"host": "new", "port": "3306"}))
+			Expect(cli.applies).Should(Equal(2))
+			Expect(cli.forced).Should(BeZero())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("should leave the conflicting fields to the other field manager", func() {
+			cli.conflicts = []string{".data.port"}
+
+			_, err := apply("apply-test")
+			Expect(err).ShouldNot(HaveOccurred())
+			cm := get("apply-test")
+			Expect(cm.Data).Should(HaveKeyWithValue("host", "new"))
+			// the fake client replaces the object, the fields of the other field manager are not kept
+			Expect(cm.Data).ShouldNot(HaveKey("port"))
+			Expect(cli.applies).Should(Equal(2))
+			Expect(cli.forced).Should(BeZero())
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring(eventReasonFieldConflict),
+				ContainSubstring("left to the other field managers: .data.port"))))
+		})
+
+		It("should take over the conflicting fields the operator must own", func() {
+			cli.conflicts = []string{".metadata.labels." + dbaasv1beta1.TypeLabelKey, ".metadata.ownerReferences[uid=\"uid\"]", ".data.host"}
+
+			_, err := apply("apply-test")
+			Expect(err).ShouldNot(HaveOccurred())
+			cm := get("apply-test")
+			Expect(cm.Labels).Should(HaveKeyWithValue(dbaasv1beta1.TypeLabelKey, dbaasv1beta1.TypeLabelValue))
+			Expect(cm.OwnerReferences).Should(HaveLen(1))
+			Expect(cm.Data).Should(Equal(map[string]string{"port": "3306"}))
+			Expect(cli.forced).Should(Equal(1))
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring("left to the other field managers: .data.host"),
+				ContainSubstring("taken over by the operator: .metadata.labels."+dbaasv1beta1.TypeLabelKey+", .metadata.ownerReferences"))))
+		})
+	})
+})
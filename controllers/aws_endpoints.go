@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+)
+
+const (
+	// the RDS endpoints called by the operator for the Inventory in order, the next endpoint is called when an endpoint
+	// cannot be reached, for example rds.us-east-1.amazonaws.com,rds-fips.us-east-1.amazonaws.com
+	inventoryAWSEndpointsAnnotation = "rds.dbaas.redhat.com/aws-endpoints"
+
+	// the STS endpoints used by the RDS controller, the regional endpoints are used unless the credentials of the
+	// Inventory set the legacy global endpoint
+	awsSTSRegionalEndpoints        = "AWS_STS_REGIONAL_ENDPOINTS"
+	defaultAWSSTSRegionalEndpoints = "regional"
+)
+
+// getAWSEndpoints returns the RDS endpoints of the Inventory in order, nil is returned if the Inventory calls the
+// default endpoint of its region
+func getAWSEndpoints(inventory *rdsdbaasv1alpha1.RDSInventory) ([]string, error) {
+	value, ok := inventory.Annotations[inventoryAWSEndpointsAnnotation]
+	if !ok || len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	var endpoints []string
+	for _, e := range strings.Split(value, ",") {
+		endpoint := strings.TrimPrefix(strings.TrimSpace(e), "https://")
+		if errs := validation.IsDNS1123Subdomain(endpoint); len(errs) > 0 {
+			return nil, fmt.Errorf("endpoint %s of annotation %s is invalid: %s", endpoint, inventoryAWSEndpointsAnnotation,
+				strings.Join(errs, ", "))
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// withAWSEndpoints returns a context calling the RDS endpoints of the Inventory
+func withAWSEndpoints(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) (context.Context, error) {
+	endpoints, err := getAWSEndpoints(inventory)
+	if err != nil || len(endpoints) == 0 {
+		return ctx, err
+	}
+	return controllersrds.WithEndpoints(ctx, endpoints), nil
+}
+
+// getSTSRegionalEndpoints returns the STS endpoints setting of the RDS controller from the credentials of the Inventory
+func getSTSRegionalEndpoints(credentials map[string][]byte) (string, error) {
+	value, ok := credentials[awsSTSRegionalEndpoints]
+	if !ok || len(value) == 0 {
+		return defaultAWSSTSRegionalEndpoints, nil
+	}
+	switch v := string(value); v {
+	case "regional", "legacy":
+		return v, nil
+	default:
+		return "", fmt.Errorf("value %s of %s is invalid, the valid values are regional and legacy", v, awsSTSRegionalEndpoints)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("AWSEndpoints", func() {
+	It("should parse the RDS endpoints of the Inventory in order", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(getAWSEndpoints(inventory)).Should(BeNil())
+
+		inventory.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{
+			inventoryAWSEndpointsAnnotation: "rds.us-east-1.amazonaws.com, https://rds-fips.us-east-1.amazonaws.com",
+		}}
+		Expect(getAWSEndpoints(inventory)).Should(Equal([]string{"rds.us-east-1.amazonaws.com", "rds-fips.us-east-1.amazonaws.com"}))
+
+		inventory.Annotations[inventoryAWSEndpointsAnnotation] = "rds.us-east-1.amazonaws.com/path"
+		_, err := getAWSEndpoints(inventory)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should use the regional STS endpoints by default", func() {
+		Expect(getSTSRegionalEndpoints(map[string][]byte{})).Should(Equal("regional"))
+		Expect(getSTSRegionalEndpoints(map[string][]byte{awsSTSRegionalEndpoints: []byte("legacy")})).Should(Equal("legacy"))
+		_, err := getSTSRegionalEndpoints(map[string][]byte{awsSTSRegionalEndpoints: []byte("global")})
+		Expect(err).Should(HaveOccurred())
+	})
+})
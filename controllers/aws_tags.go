@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the AWS tags of the DB instances and clusters reported in the Inventory status and set as labels on the
+	// Connection Secrets, in the format of tagKey1=labelKey1,tagKey2=labelKey2
+	inventoryTagLabelMappingAnnotation = "rds.dbaas.redhat.com/tag-label-mapping"
+
+	// the service info key prefix of the mapped AWS tags
+	serviceInfoTagPrefix = "tag."
+)
+
+// getTagLabelMapping returns the mapping from the AWS tag keys to the label keys of the Inventory,
+// nil is returned if the Inventory has no mapping
+func getTagLabelMapping(inventory *rdsdbaasv1alpha1.RDSInventory) (map[string]string, error) {
+	value, ok := inventory.Annotations[inventoryTagLabelMappingAnnotation]
+	if !ok || len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	mapping, e := parseTagSelector(value)
+	if e != nil {
+		return nil, fmt.Errorf("value of annotation %s is invalid", inventoryTagLabelMappingAnnotation)
+	}
+	for _, labelKey := range mapping {
+		if errs := validation.IsQualifiedName(labelKey); len(errs) > 0 {
+			return nil, fmt.Errorf("label key %s of annotation %s is invalid: %s", labelKey, inventoryTagLabelMappingAnnotation,
+				strings.Join(errs, ", "))
+		}
+	}
+	return mapping, nil
+}
+
+// selectMappedTags returns the values of the tags of the mapping
+func selectMappedTags(tags []rdstypesv2.Tag, mapping map[string]string) map[string]string {
+	if len(mapping) == 0 {
+		return nil
+	}
+	selected := map[string]string{}
+	for _, tag := range tags {
+		if tag.Key == nil || tag.Value == nil {
+			continue
+		}
+		if _, ok := mapping[*tag.Key]; ok {
+			selected[*tag.Key] = *tag.Value
+		}
+	}
+	return selected
+}
+
+// setTagServiceInfo adds the mapped tags of a DB service to its service info
+func setTagServiceInfo(serviceInfo map[string]string, tags map[string]string) {
+	for k, v := range tags {
+		serviceInfo[serviceInfoTagPrefix+k] = v
+	}
+}
+
+// buildTagLabels returns the labels of the mapped tags in the service info of a DB service,
+// the tag values that are not valid label values are skipped
+func buildTagLabels(serviceInfo map[string]string, mapping map[string]string) map[string]string {
+	labels := map[string]string{}
+	for tagKey, labelKey := range mapping {
+		value, ok := serviceInfo[serviceInfoTagPrefix+tagKey]
+		if !ok || len(validation.IsValidLabelValue(value)) > 0 {
+			continue
+		}
+		labels[labelKey] = value
+	}
+	return labels
+}
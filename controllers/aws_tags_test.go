@@ -0,0 +1,67 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("AWSTags", func() {
+	newInventory := func(mapping string) *rdsdbaasv1alpha1.RDSInventory {
+		return &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{inventoryTagLabelMappingAnnotation: mapping},
+			},
+		}
+	}
+
+	It("should map the AWS tags to the labels", func() {
+		mapping, e := getTagLabelMapping(newInventory("Environment=env,Team=example.com/team"))
+		Expect(e).ShouldNot(HaveOccurred())
+
+		tags := selectMappedTags([]rdstypesv2.Tag{
+			{Key: pointer.String("Environment"), Value: pointer.String("prod")},
+			{Key: pointer.String("Team"), Value: pointer.String("data platform")},
+			{Key: pointer.String("CostCenter"), Value: pointer.String("1234")},
+		}, mapping)
+		Expect(tags).Should(Equal(map[string]string{"Environment": "prod", "Team": "data platform"}))
+
+		serviceInfo := map[string]string{}
+		setTagServiceInfo(serviceInfo, tags)
+		Expect(serviceInfo).Should(Equal(map[string]string{"tag.Environment": "prod", "tag.Team": "data platform"}))
+
+		// the tag value with a space is not a valid label value
+		Expect(buildTagLabels(serviceInfo, mapping)).Should(Equal(map[string]string{"env": "prod"}))
+	})
+
+	It("should reject an invalid mapping", func() {
+		_, e := getTagLabelMapping(newInventory("Environment"))
+		Expect(e).Should(HaveOccurred())
+		_, e = getTagLabelMapping(newInventory("Environment=not a label"))
+		Expect(e).Should(HaveOccurred())
+		mapping, e := getTagLabelMapping(&rdsdbaasv1alpha1.RDSInventory{})
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(mapping).Should(BeNil())
+	})
+})
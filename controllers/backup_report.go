@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+)
+
+const (
+	serviceInfoLatestAutomatedSnapshotTime = "latestAutomatedSnapshotTime"
+	serviceInfoLatestManualSnapshotTime    = "latestManualSnapshotTime"
+	serviceInfoLatestBackupTime            = "latestBackupTime"
+
+	dbSnapshotTypeAutomated = "automated"
+)
+
+var lastBackupAge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "rds_last_backup_age_seconds",
+		Help: "The time since the latest backup of a DB instance of an Inventory, either a snapshot or the latest restorable time of the automated backups.",
+	},
+	[]string{"namespace", "inventory", "db_instance"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(lastBackupAge)
+}
+
+// dbSnapshotTimes are the creation times of the latest available snapshots of a DB instance
+type dbSnapshotTimes struct {
+	automated time.Time
+	manual    time.Time
+}
+
+// describeLatestDBSnapshotTimes returns the times of the latest available snapshots of the DB instances of the region by identifier
+func describeLatestDBSnapshotTimes(ctx context.Context, describeDBSnapshots controllersrds.DescribeDBSnapshotsAPI) (map[string]*dbSnapshotTimes, error) {
+	times := map[string]*dbSnapshotTimes{}
+	input := &rds.DescribeDBSnapshotsInput{}
+	for {
+		output, e := describeDBSnapshots.DescribeDBSnapshots(ctx, input)
+		if e != nil {
+			return nil, e
+		}
+		if output == nil {
+			return times, nil
+		}
+		collectLatestDBSnapshotTimes(output.DBSnapshots, times)
+		if output.Marker == nil || len(*output.Marker) == 0 {
+			return times, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+func collectLatestDBSnapshotTimes(snapshots []rdstypesv2.DBSnapshot, times map[string]*dbSnapshotTimes) {
+	for _, snapshot := range snapshots {
+		if snapshot.DBInstanceIdentifier == nil || snapshot.SnapshotCreateTime == nil ||
+			snapshot.Status == nil || *snapshot.Status != "available" {
+			continue
+		}
+		t, ok := times[*snapshot.DBInstanceIdentifier]
+		if !ok {
+			t = &dbSnapshotTimes{}
+			times[*snapshot.DBInstanceIdentifier] = t
+		}
+		if snapshot.SnapshotType != nil && *snapshot.SnapshotType == dbSnapshotTypeAutomated {
+			if snapshot.SnapshotCreateTime.After(t.automated) {
+				t.automated = *snapshot.SnapshotCreateTime
+			}
+		} else if snapshot.SnapshotCreateTime.After(t.manual) {
+			t.manual = *snapshot.SnapshotCreateTime
+		}
+	}
+}
+
+// setBackupServiceInfo adds the backup times of a DB instance to its service info and returns the time of its latest backup
+func setBackupServiceInfo(serviceInfo map[string]string, snapshotTimes *dbSnapshotTimes, latestRestorableTime time.Time) time.Time {
+	latest := latestRestorableTime
+	if snapshotTimes != nil {
+		if !snapshotTimes.automated.IsZero() {
+			serviceInfo[serviceInfoLatestAutomatedSnapshotTime] = snapshotTimes.automated.UTC().Format(time.RFC3339)
+			if snapshotTimes.automated.After(latest) {
+				latest = snapshotTimes.automated
+			}
+		}
+		if !snapshotTimes.manual.IsZero() {
+			serviceInfo[serviceInfoLatestManualSnapshotTime] = snapshotTimes.manual.UTC().Format(time.RFC3339)
+			if snapshotTimes.manual.After(latest) {
+				latest = snapshotTimes.manual
+			}
+		}
+	}
+	if !latest.IsZero() {
+		serviceInfo[serviceInfoLatestBackupTime] = latest.UTC().Format(time.RFC3339)
+	}
+	return latest
+}
+
+// recordLastBackupAge replaces the backup age metric of the DB instances of the Inventory,
+// the DB instances that have never been backed up are not reported
+func recordLastBackupAge(namespace, name string, services []dbaasv1beta1.DatabaseService, now time.Time) {
+	lastBackupAge.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "inventory": name})
+	for _, service := range services {
+		if service.ServiceType == nil || *service.ServiceType != instanceType {
+			continue
+		}
+		latest, e := time.Parse(time.RFC3339, service.ServiceInfo[serviceInfoLatestBackupTime])
+		if e != nil {
+			continue
+		}
+		lastBackupAge.WithLabelValues(namespace, name, service.ServiceID).Set(now.Sub(latest).Seconds())
+	}
+}
+
+// deleteLastBackupAge removes the backup age metric of the DB instances of a deleted Inventory
+func deleteLastBackupAge(namespace, name string) {
+	lastBackupAge.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "inventory": name})
+}
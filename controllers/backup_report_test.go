@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/utils/pointer"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+)
+
+var _ = Describe("BackupReport", func() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := func(instance, snapshotType, status string, created time.Time) rdstypesv2.DBSnapshot {
+		return rdstypesv2.DBSnapshot{
+			DBInstanceIdentifier: pointer.String(instance),
+			SnapshotType:         pointer.String(snapshotType),
+			Status:               pointer.String(status),
+			SnapshotCreateTime:   &created,
+		}
+	}
+
+	It("should report the latest available snapshots of the DB instances", func() {
+		times := map[string]*dbSnapshotTimes{}
+		collectLatestDBSnapshotTimes([]rdstypesv2.DBSnapshot{
+			snapshot("db-1", "automated", "available", now.Add(-26*time.Hour)),
+			snapshot("db-1", "automated", "available", now.Add(-2*time.Hour)),
+			snapshot("db-1", "automated", "creating", now.Add(-time.Hour)),
+			snapshot("db-1", "manual", "available", now.Add(-48*time.Hour)),
+		}, times)
+		Expect(times).Should(HaveKey("db-1"))
+
+		serviceInfo := map[string]string{}
+		latest := setBackupServiceInfo(serviceInfo, times["db-1"], now.Add(-5*time.Minute))
+		Expect(latest).Should(Equal(now.Add(-5 * time.Minute)))
+		Expect(serviceInfo).Should(Equal(map[string]string{
+			serviceInfoLatestAutomatedSnapshotTime: "2022-10-01T10:00:00Z",
+			serviceInfoLatestManualSnapshotTime:    "2022-09-29T12:00:00Z",
+			serviceInfoLatestBackupTime:            "2022-10-01T11:55:00Z",
+		}))
+	})
+
+	It("should record the backup age of the DB instances of the Inventory", func() {
+		serviceType := dbaasv1beta1.DatabaseServiceType(instanceType)
+		services := []dbaasv1beta1.DatabaseService{
+			{
+				ServiceID:   "db-1",
+				ServiceType: &serviceType,
+				ServiceInfo: map[string]string{serviceInfoLatestBackupTime: "2022-10-01T11:00:00Z"},
+			},
+			{
+				ServiceID:   "db-2",
+				ServiceType: &serviceType,
+				ServiceInfo: map[string]string{},
+			},
+		}
+		recordLastBackupAge("backup-report", "inventory", services, now)
+		Expect(testutil.ToFloat64(lastBackupAge.WithLabelValues("backup-report", "inventory", "db-1"))).Should(Equal(float64(3600)))
+		Expect(lastBackupAge.DeleteLabelValues("backup-report", "inventory", "db-2")).Should(BeFalse())
+
+		deleteLastBackupAge("backup-report", "inventory")
+		Expect(lastBackupAge.DeleteLabelValues("backup-report", "inventory", "db-1")).Should(BeFalse())
+	})
+})
@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	conditionDegraded = "Degraded"
+
+	degradedReasonCircuitOpen = "CircuitOpen"
+
+	degradedMessageCircuitOpen = "AWS calls of Inventory %s/%s suspended after %d consecutive failures until %s"
+)
+
+// CircuitBreaker suspends the AWS calls made for an Inventory after consecutive failures for a cool-down period,
+// the Inventory and its Connections are marked as Degraded while the circuit is open
+type CircuitBreaker struct {
+	failureThreshold int
+	coolDown         time.Duration
+
+	mutex    sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+}
+
+// NewCircuitBreaker returns nil if the failure threshold is not positive, which disables the circuit breaker
+func NewCircuitBreaker(failureThreshold int, coolDown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		coolDown:         coolDown,
+		circuits:         map[string]*circuit{},
+	}
+}
+
+// openUntil returns the end of the cool-down period if the circuit of the Inventory is open
+func (b *CircuitBreaker) openUntil(namespace, name string) (time.Time, bool) {
+	openUntil, _, open := b.state(namespace, name)
+	return openUntil, open
+}
+
+func (b *CircuitBreaker) state(namespace, name string) (time.Time, int, bool) {
+	if b == nil {
+		return time.Time{}, 0, false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	c, ok := b.circuits[namespace+"/"+name]
+	if !ok || c.openUntil.IsZero() {
+		return time.Time{}, 0, false
+	}
+	if time.Now().After(c.openUntil) {
+		// half open, the next call decides whether to close or to trip the circuit again
+		return time.Time{}, 0, false
+	}
+	return c.openUntil, c.failures, true
+}
+
+// recordFailure returns true if the failure trips the circuit of the Inventory
+func (b *CircuitBreaker) recordFailure(namespace, name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	key := namespace + "/" + name
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	c.failures++
+	if c.failures < b.failureThreshold {
+		return false
+	}
+	c.openUntil = time.Now().Add(b.coolDown)
+	return true
+}
+
+func (b *CircuitBreaker) recordSuccess(namespace, name string) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.circuits, namespace+"/"+name)
+}
+
+// setDegradedCondition sets the Degraded condition while the circuit of the Inventory is open and removes it once the
+// circuit closes
+func (b *CircuitBreaker) setDegradedCondition(conditions *[]metav1.Condition, namespace, name string) {
+	openUntil, failures, open := b.state(namespace, name)
+	if !open {
+		if c := apimeta.FindStatusCondition(*conditions, conditionDegraded); c != nil && c.Reason == degradedReasonCircuitOpen {
+			apimeta.RemoveStatusCondition(conditions, conditionDegraded)
+		}
+		return
+	}
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    conditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  degradedReasonCircuitOpen,
+		Message: fmt.Sprintf(degradedMessageCircuitOpen, namespace, name, failures, openUntil.Format(time.RFC3339)),
+	})
+}
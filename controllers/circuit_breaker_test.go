@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	Context("when the failure threshold is not positive", func() {
+		It("should never open the circuit", func() {
+			b := NewCircuitBreaker(0, time.Minute)
+			Expect(b).Should(BeNil())
+			Expect(b.recordFailure("ns", "inventory")).Should(BeFalse())
+			_, open := b.openUntil("ns", "inventory")
+			Expect(open).Should(BeFalse())
+		})
+	})
+
+	Context("when the failures reach the threshold", func() {
+		It("should open the circuit until the end of the cool-down period", func() {
+			b := NewCircuitBreaker(2, time.Minute)
+			Expect(b.recordFailure("ns", "inventory")).Should(BeFalse())
+			_, open := b.openUntil("ns", "inventory")
+			Expect(open).Should(BeFalse())
+
+			Expect(b.recordFailure("ns", "inventory")).Should(BeTrue())
+			openUntil, open := b.openUntil("ns", "inventory")
+			Expect(open).Should(BeTrue())
+			Expect(openUntil).Should(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+
+			_, open = b.openUntil("ns", "other-inventory")
+			Expect(open).Should(BeFalse())
+
+			var conditions []metav1.Condition
+			b.setDegradedCondition(&conditions, "ns", "inventory")
+			Expect(apimeta.IsStatusConditionTrue(conditions, conditionDegraded)).Should(BeTrue())
+
+			b.recordSuccess("ns", "inventory")
+			_, open = b.openUntil("ns", "inventory")
+			Expect(open).Should(BeFalse())
+			b.setDegradedCondition(&conditions, "ns", "inventory")
+			Expect(apimeta.FindStatusCondition(conditions, conditionDegraded)).Should(BeNil())
+		})
+
+		It("should half open the circuit after the cool-down period", func() {
+			b := NewCircuitBreaker(1, time.Millisecond)
+			Expect(b.recordFailure("ns", "inventory")).Should(BeTrue())
+			Eventually(func() bool {
+				_, open := b.openUntil("ns", "inventory")
+				return open
+			}).Should(BeFalse())
+			Expect(b.recordFailure("ns", "inventory")).Should(BeTrue())
+		})
+	})
+
+	Context("when the circuit of the Inventory of a Connection is open", func() {
+		reconcile := func(conditions []metav1.Condition) []metav1.Condition {
+			inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory"}}
+			connection := &rdsdbaasv1alpha1.RDSConnection{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "connection"},
+				Spec: dbaasv1beta1.DBaaSConnectionSpec{
+					InventoryRef:      dbaasv1beta1.NamespacedName{Namespace: "operator", Name: "inventory"},
+					DatabaseServiceID: "db",
+				},
+				Status: dbaasv1beta1.DBaaSConnectionStatus{Conditions: conditions},
+			}
+			b := NewCircuitBreaker(1, time.Minute)
+			b.recordFailure("operator", "inventory")
+			r := &RDSConnectionReconciler{
+				Client:         fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory, connection).Build(),
+				CircuitBreaker: b,
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(connection)})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).Should(BeNumerically(">", 0))
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(connection), connection)).Should(Succeed())
+			Expect(apimeta.IsStatusConditionTrue(connection.Status.Conditions, conditionDegraded)).Should(BeTrue())
+			return connection.Status.Conditions
+		}
+
+		It("should keep the binding condition of the Connection", func() {
+			ready := metav1.Condition{Type: connectionConditionReady, Status: metav1.ConditionTrue, Reason: connectionStatusReasonReady,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))}
+			condition := apimeta.FindStatusCondition(reconcile([]metav1.Condition{ready}), connectionConditionReady)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).Should(Equal(connectionStatusReasonReady))
+			Expect(condition.LastTransitionTime.Time).Should(BeTemporally("==", ready.LastTransitionTime.Time))
+		})
+
+		It("should not set the Connection without binding condition ready", func() {
+			condition := apimeta.FindStatusCondition(reconcile(nil), connectionConditionReady)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal(degradedReasonCircuitOpen))
+		})
+	})
+})
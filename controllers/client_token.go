@@ -0,0 +1,31 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getClientToken returns the idempotency token of the create operation of the AWS resource of an object, derived
+// from the UID of the object so that the retries of a create request that timed out do not create another resource
+func getClientToken(uid types.UID, operation string) string {
+	sum := sha256.Sum256([]byte(string(uid) + "/" + operation))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client token", func() {
+	It("should derive the same token for the retries of a create operation", func() {
+		token := getClientToken("6f1e4b9c-7c7e-4a55-a1a1-0a5d3b2f4f10", "CreateSecret")
+		Expect(token).Should(HaveLen(64))
+		Expect(getClientToken("6f1e4b9c-7c7e-4a55-a1a1-0a5d3b2f4f10", "CreateSecret")).Should(Equal(token))
+		Expect(getClientToken("6f1e4b9c-7c7e-4a55-a1a1-0a5d3b2f4f10", "RestoreDBInstanceFromS3")).ShouldNot(Equal(token))
+		Expect(getClientToken("0b6a2d1e-3c4f-4e5a-9b8c-7d6e5f4a3b2c", "CreateSecret")).ShouldNot(Equal(token))
+	})
+})
@@ -0,0 +1,47 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudwatch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type GetMetricDataAPI interface {
+	GetMetricData(context.Context, *cloudwatch.GetMetricDataInput, ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+type sdkV2GetMetricData struct {
+	client *cloudwatch.Client
+}
+
+func NewGetMetricData(accessKey, secretKey, region string) GetMetricDataAPI {
+	awsClient := cloudwatch.New(cloudwatch.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2GetMetricData{
+		client: awsClient,
+	}
+}
+
+func (g *sdkV2GetMetricData) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return g.client.GetMetricData(ctx, params, optFns...)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	controllerscloudwatch "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/cloudwatch"
+)
+
+// MetricValues are the latest values returned for the metrics of every DB instance and cluster
+var MetricValues = map[string]float64{
+	"CPUUtilization":      12.5,
+	"FreeStorageSpace":    10737418240,
+	"DatabaseConnections": 3,
+}
+
+type mockGetMetricData struct {
+	accessKey, secretKey, region string
+}
+
+func NewGetMetricData(accessKey, secretKey, region string) controllerscloudwatch.GetMetricDataAPI {
+	return &mockGetMetricData{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockGetMetricData) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		result := types.MetricDataResult{Id: query.Id, StatusCode: types.StatusCodeComplete}
+		if query.MetricStat != nil && query.MetricStat.Metric != nil && query.MetricStat.Metric.MetricName != nil {
+			if v, ok := MetricValues[*query.MetricStat.Metric.MetricName]; ok {
+				result.Timestamps = []time.Time{time.Now()}
+				result.Values = []float64{v}
+			}
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
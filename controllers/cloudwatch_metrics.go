@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllerscloudwatch "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/cloudwatch"
+)
+
+const (
+	// set to false to not relay the CloudWatch metrics of the DB service of the Connection
+	connectionCloudWatchMetricsAnnotation = "rds.dbaas.redhat.com/cloudwatch-metrics"
+
+	cloudWatchNamespaceRDS      = "AWS/RDS"
+	cloudWatchMetricPeriod      = 300
+	cloudWatchMetricLookback    = 15 * time.Minute
+	cloudWatchMetricStatAverage = "Average"
+
+	cloudWatchMetricCPUUtilization      = "CPUUtilization"
+	cloudWatchMetricFreeStorageSpace    = "FreeStorageSpace"
+	cloudWatchMetricDatabaseConnections = "DatabaseConnections"
+)
+
+var (
+	databaseCPUUtilization = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_database_cpu_utilization_percent",
+			Help: "The average CPU utilization of the DB service of a Connection over the last CloudWatch period.",
+		},
+		[]string{"namespace", "connection", "service_type", "service_id"},
+	)
+	databaseFreeStorageSpace = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_database_free_storage_space_bytes",
+			Help: "The average free storage space of the DB instance of a Connection over the last CloudWatch period.",
+		},
+		[]string{"namespace", "connection", "service_type", "service_id"},
+	)
+	databaseConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_database_connections",
+			Help: "The average number of client connections to the DB service of a Connection over the last CloudWatch period.",
+		},
+		[]string{"namespace", "connection", "service_type", "service_id"},
+	)
+
+	cloudWatchMetricGauges = map[string]*prometheus.GaugeVec{
+		cloudWatchMetricCPUUtilization:      databaseCPUUtilization,
+		cloudWatchMetricFreeStorageSpace:    databaseFreeStorageSpace,
+		cloudWatchMetricDatabaseConnections: databaseConnections,
+	}
+)
+
+func init() {
+	metrics.Registry.MustRegister(databaseCPUUtilization, databaseFreeStorageSpace, databaseConnections)
+}
+
+// syncCloudWatchMetrics relays the latest CloudWatch metrics of the DB service of a ready Connection, the metrics are
+// fetched at most once per interval and the time until the next fetch is returned, 0 if the metrics are not relayed
+func (r *RDSConnectionReconciler) syncCloudWatchMetrics(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	inventory *rdsdbaasv1alpha1.RDSInventory) (time.Duration, error) {
+	// the spokes do not call AWS, the metrics are not relayed in spoke mode
+	if r.CloudWatchMetricsInterval <= 0 || r.GetGetMetricDataAPI == nil || r.Hub != nil ||
+		connection.Annotations[connectionCloudWatchMetricsAnnotation] == "false" {
+		deleteCloudWatchMetrics(connection.Namespace, connection.Name)
+		return 0, nil
+	}
+
+	key := client.ObjectKeyFromObject(connection).String()
+	if v, ok := r.cloudWatchFetches.Load(key); ok {
+		if wait := time.Until(v.(time.Time).Add(r.CloudWatchMetricsInterval)); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	secret := &v1.Secret{}
+	if err := getInventoryCredentials(ctx, r.Client, inventory, secret); err != nil {
+		return 0, err
+	}
+	getMetricData := r.GetGetMetricDataAPI(string(secret.Data[awsAccessKeyID]), string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+
+	now := time.Now()
+	if err := relayCloudWatchMetrics(ctx, getMetricData, connection, now); err != nil {
+		return 0, err
+	}
+	r.cloudWatchFetches.Store(key, now)
+	return r.CloudWatchMetricsInterval, nil
+}
+
+// relayCloudWatchMetrics sets the metrics of the DB service of the Connection to their latest CloudWatch values
+func relayCloudWatchMetrics(ctx context.Context, getMetricData controllerscloudwatch.GetMetricDataAPI,
+	connection *rdsdbaasv1alpha1.RDSConnection, now time.Time) error {
+	serviceType := instanceType
+	dimension := "DBInstanceIdentifier"
+	if connection.Spec.DatabaseServiceType != nil && *connection.Spec.DatabaseServiceType == clusterType {
+		serviceType = clusterType
+		dimension = "DBClusterIdentifier"
+	}
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(now.Add(-cloudWatchMetricLookback)),
+		EndTime:   aws.Time(now),
+		ScanBy:    cloudwatchtypes.ScanByTimestampDescending,
+	}
+	ids := map[string]string{}
+	for _, name := range []string{cloudWatchMetricCPUUtilization, cloudWatchMetricFreeStorageSpace, cloudWatchMetricDatabaseConnections} {
+		// the query IDs must start with a lowercase letter
+		id := "m" + name
+		ids[id] = name
+		input.MetricDataQueries = append(input.MetricDataQueries, cloudwatchtypes.MetricDataQuery{
+			Id: pointer.String(id),
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  pointer.String(cloudWatchNamespaceRDS),
+					MetricName: pointer.String(name),
+					Dimensions: []cloudwatchtypes.Dimension{{Name: pointer.String(dimension), Value: pointer.String(connection.Spec.DatabaseServiceID)}},
+				},
+				Period: pointer.Int32(cloudWatchMetricPeriod),
+				Stat:   pointer.String(cloudWatchMetricStatAverage),
+			},
+		})
+	}
+	output, err := getMetricData.GetMetricData(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range output.MetricDataResults {
+		name, ok := ids[pointer.StringDeref(result.Id, "")]
+		if !ok {
+			continue
+		}
+		// the values are sorted by descending timestamp, a metric without datapoints is not reported by the DB service
+		if len(result.Values) > 0 {
+			cloudWatchMetricGauges[name].WithLabelValues(connection.Namespace, connection.Name, serviceType,
+				connection.Spec.DatabaseServiceID).Set(result.Values[0])
+		} else {
+			cloudWatchMetricGauges[name].DeletePartialMatch(prometheus.Labels{"namespace": connection.Namespace, "connection": connection.Name})
+		}
+	}
+	return nil
+}
+
+// deleteCloudWatchMetrics removes the relayed metrics of a Connection
+func deleteCloudWatchMetrics(namespace, name string) {
+	for _, gauge := range cloudWatchMetricGauges {
+		gauge.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "connection": name})
+	}
+}
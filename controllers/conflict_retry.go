@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	conflictResultRetried   = "retried"
+	conflictResultExhausted = "exhausted"
+	conflictResultRequeued  = "requeued"
+)
+
+// conflictRetry spreads the retries of the reconciles updating the same object, the jitter keeps them from conflicting
+// again at the same time
+var conflictRetry = wait.Backoff{
+	Steps:    5,
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   1.0,
+}
+
+var updateConflicts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rds_dbaas_update_conflicts_total",
+		Help: "The number of updates rejected for a conflict with a newer version of the object, retried, exhausting the retries or requeued.",
+	},
+	[]string{"kind", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(updateConflicts)
+}
+
+// retryOnConflict runs the update, and runs it again after refreshing the object while it conflicts with a newer
+// version of the object, the conflict is returned once the retries are exhausted
+func retryOnConflict(obj client.Object, update, refresh func() error) error {
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	attempts := 0
+	err := retry.OnError(conflictRetry, errors.IsConflict, func() error {
+		if attempts > 0 {
+			updateConflicts.WithLabelValues(kind, conflictResultRetried).Inc()
+			if err := refresh(); err != nil {
+				return err
+			}
+		}
+		attempts++
+		return update()
+	})
+	if errors.IsConflict(err) {
+		updateConflicts.WithLabelValues(kind, conflictResultExhausted).Inc()
+	}
+	return err
+}
+
+// updateStatus updates the status of the object computed by the reconcile, a conflict with a newer version of the object
+// is returned without retrying as the status is to be computed again from it, the callers requeue the reconcile
+func updateStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	err := c.Status().Update(ctx, obj)
+	if errors.IsConflict(err) {
+		updateConflicts.WithLabelValues(reflect.Indirect(reflect.ValueOf(obj)).Type().Name(), conflictResultRequeued).Inc()
+	}
+	return err
+}
+
+// updateObject applies the mutation to the object and updates it, the mutation is applied again on the latest version
+// of the object on a conflict. The status of the object is kept, as it is updated at the end of the reconcile.
+func updateObject(ctx context.Context, c client.Client, obj client.Object, mutate func() error) error {
+	if err := mutate(); err != nil {
+		return err
+	}
+	return retryOnConflict(obj, func() error {
+		return c.Update(ctx, obj)
+	}, func() error {
+		if err := refreshObject(ctx, c, obj); err != nil {
+			return err
+		}
+		return mutate()
+	})
+}
+
+// refreshObject reads the latest version of the object into it, except its status
+func refreshObject(ctx context.Context, c client.Client, obj client.Object) error {
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return err
+	}
+	status, ok := current["status"]
+	if !ok {
+		return nil
+	}
+	latest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	latest["status"] = status
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(latest, obj)
+}
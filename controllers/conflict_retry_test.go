@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// conflictingClient rejects the first updates of the objects, after another writer updated them
+type conflictingClient struct {
+	client.Client
+	conflicts  int
+	concurrent func(obj client.Object)
+}
+
+func (c *conflictingClient) conflict(obj client.Object) error {
+	if c.conflicts == 0 {
+		return nil
+	}
+	c.conflicts--
+	if c.concurrent != nil {
+		latest := obj.DeepCopyObject().(client.Object)
+		Expect(c.Client.Get(context.Background(), client.ObjectKeyFromObject(obj), latest)).Should(Succeed())
+		c.concurrent(latest)
+		Expect(c.Client.Update(context.Background(), latest)).Should(Succeed())
+	}
+	return errors.NewConflict(schema.GroupResource{Resource: "rdsinstances"}, obj.GetName(), nil)
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.conflict(obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return &conflictingStatusWriter{StatusWriter: c.Client.Status(), c: c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	c *conflictingClient
+}
+
+func (w *conflictingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := w.c.conflict(obj); err != nil {
+		return err
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+var _ = Describe("ConflictRetry", func() {
+	var inventory *rdsdbaasv1alpha1.RDSInventory
+	var c *conflictingClient
+
+	BeforeEach(func() {
+		inventory = &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory-conflict"},
+		}
+		c = &conflictingClient{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory.DeepCopy()).Build(),
+		}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(inventory), inventory)).Should(Succeed())
+	})
+
+	It("should apply the mutation again on the latest version of the object", func() {
+		c.conflicts = 1
+		c.concurrent = func(obj client.Object) {
+			obj.SetLabels(map[string]string{"team": "db"})
+		}
+		retried := testutil.ToFloat64(updateConflicts.WithLabelValues("RDSInventory", conflictResultRetried))
+		inventory.Status.Conditions = []metav1.Condition{{Type: "SpecSynced", Status: metav1.ConditionTrue}}
+
+		Expect(updateObject(context.Background(), c, inventory, func() error {
+			inventory.SetAnnotations(map[string]string{"region": "us-east-1"})
+			return nil
+		})).Should(Succeed())
+		Expect(inventory.Status.Conditions).Should(HaveLen(1))
+		Expect(testutil.ToFloat64(updateConflicts.WithLabelValues("RDSInventory", conflictResultRetried))).Should(Equal(retried + 1))
+
+		latest := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(inventory), latest)).Should(Succeed())
+		Expect(latest.Labels).Should(HaveKeyWithValue("team", "db"))
+		Expect(latest.Annotations).Should(HaveKeyWithValue("region", "us-east-1"))
+	})
+
+	It("should return the conflict of the status update to requeue the reconcile", func() {
+		c.conflicts = 1
+		c.concurrent = func(obj client.Object) {
+			obj.SetLabels(map[string]string{"team": "db"})
+		}
+		requeued := testutil.ToFloat64(updateConflicts.WithLabelValues("RDSInventory", conflictResultRequeued))
+		inventory.Status.Conditions = []metav1.Condition{{Type: "SpecSynced", Status: metav1.ConditionTrue}}
+
+		Expect(errors.IsConflict(updateStatus(context.Background(), c, inventory))).Should(BeTrue())
+		Expect(testutil.ToFloat64(updateConflicts.WithLabelValues("RDSInventory", conflictResultRequeued))).Should(Equal(requeued + 1))
+		latest := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(inventory), latest)).Should(Succeed())
+		Expect(latest.Status.Conditions).Should(BeEmpty())
+
+		// the status computed again from the latest version is updated
+		inventory = latest
+		inventory.Status.Conditions = []metav1.Condition{{Type: "SpecSynced", Status: metav1.ConditionTrue}}
+		Expect(updateStatus(context.Background(), c, inventory)).Should(Succeed())
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(inventory), latest)).Should(Succeed())
+		Expect(latest.Status.Conditions).Should(HaveLen(1))
+	})
+})
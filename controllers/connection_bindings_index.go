@@ -0,0 +1,130 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the checksum of the data of the binding Secret of the Connection, and the time it last changed
+	connectionCredentialsChecksumAnnotation    = "rds.dbaas.redhat.com/credentials-checksum"
+	connectionCredentialsLastRotatedAnnotation = "rds.dbaas.redhat.com/credentials-last-rotated"
+
+	// BindingsIndexConfigMapName is the ConfigMap listing the Connections of a namespace and their binding Secrets,
+	// keyed by the name of the Connection
+	BindingsIndexConfigMapName = "rds-dbaas-bindings"
+)
+
+// bindingsIndexEntry is the entry of a Connection in the bindings index of its namespace
+type bindingsIndexEntry struct {
+	DatabaseServiceID string `json:"databaseServiceID"`
+	Secret            string `json:"secret"`
+	ConfigMap         string `json:"configMap"`
+	Checksum          string `json:"checksum,omitempty"`
+	LastRotated       string `json:"lastRotated,omitempty"`
+}
+
+// getSecretChecksum returns the SHA-256 checksum of the data of the Secret, sorted by key
+func getSecretChecksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// syncCredentialsChecksum sets the checksum of the binding Secret on the Connection, and the time the credentials were
+// last rotated when the checksum changes
+func (r *RDSConnectionReconciler) syncCredentialsChecksum(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	secret *v1.Secret, now time.Time) error {
+	checksum := getSecretChecksum(secret.Data)
+	if connection.Annotations[connectionCredentialsChecksumAnnotation] == checksum {
+		return nil
+	}
+	patch := client.MergeFrom(connection.DeepCopy())
+	if connection.Annotations == nil {
+		connection.Annotations = map[string]string{}
+	}
+	connection.Annotations[connectionCredentialsChecksumAnnotation] = checksum
+	connection.Annotations[connectionCredentialsLastRotatedAnnotation] = now.UTC().Format(time.RFC3339)
+	return r.Patch(ctx, connection, patch)
+}
+
+// updateBindingsIndex sets the entry of the Connection in the bindings index ConfigMap of its namespace, or removes it
+// if the entry is nil. The entry is merged into the ConfigMap so that the concurrent reconciles of the Connections of
+// the namespace do not overwrite each other.
+func (r *RDSConnectionReconciler) updateBindingsIndex(ctx context.Context, namespace, name string, entry *bindingsIndexEntry) error {
+	var value *string
+	if entry != nil {
+		b, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		value = pointer.String(string(b))
+	}
+
+	cm := &v1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: BindingsIndexConfigMapName}, cm); err != nil {
+		if !errors.IsNotFound(err) || value == nil {
+			return client.IgnoreNotFound(err)
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      BindingsIndexConfigMapName,
+				Namespace: namespace,
+				// the ConfigMaps of the cache are selected by the label
+				Labels: map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue},
+			},
+			Data: map[string]string{name: *value},
+		}
+		return r.Create(ctx, cm)
+	}
+
+	current, ok := cm.Data[name]
+	if value == nil && !ok || value != nil && ok && current == *value {
+		return nil
+	}
+	b, err := json.Marshal(map[string]interface{}{"data": map[string]*string{name: value}})
+	if err != nil {
+		return err
+	}
+	return r.Patch(ctx, cm, client.RawPatch(types.MergePatchType, b))
+}
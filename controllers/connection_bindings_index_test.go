@@ -0,0 +1,33 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Connection bindings index", func() {
+	It("should compute the checksum of the binding Secret independently of the key order", func() {
+		checksum := getSecretChecksum(map[string][]byte{"username": []byte("admin"), "password": []byte("secret")})
+		Expect(checksum).Should(HaveLen(64))
+		Expect(getSecretChecksum(map[string][]byte{"password": []byte("secret"), "username": []byte("admin")})).Should(Equal(checksum))
+		Expect(getSecretChecksum(map[string][]byte{"username": []byte("admin"), "password": []byte("rotated")})).ShouldNot(Equal(checksum))
+		// the keys and values are delimited
+		Expect(getSecretChecksum(map[string][]byte{"ab": []byte("c")})).ShouldNot(Equal(getSecretChecksum(map[string][]byte{"a": []byte("bc")})))
+	})
+})
@@ -0,0 +1,243 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// bootstrap the change data capture of the database of the Connection with a Job, the replication slot and the
+	// publication of PostgreSQL or the binlog retention of MySQL, the CDC parameters are added to the binding Secret
+	connectionCDCAnnotation = "rds.dbaas.redhat.com/cdc"
+	// the binlog retention of MySQL in hours, 24 by default
+	connectionCDCBinlogRetentionHoursAnnotation = "rds.dbaas.redhat.com/cdc-binlog-retention-hours"
+
+	defaultCDCBinlogRetentionHours = 24
+	cdcPrefix                      = "cdc_"
+	cdcPlugin                      = "pgoutput"
+
+	cdcBootstrapActiveDeadlineSeconds = 300
+
+	// the keys of the CDC parameters in the binding Secret, for Debezium and Kafka Connect
+	cdcKeySlotName             = "slotName"
+	cdcKeyPublicationName      = "publicationName"
+	cdcKeyPluginName           = "pluginName"
+	cdcKeyServerID             = "serverId"
+	cdcKeyBinlogRetentionHours = "binlogRetentionHours"
+
+	connectionStatusMessageCDCBootstrapping = "Bootstrapping change data capture"
+	connectionStatusMessageCDCError         = "Failed to bootstrap change data capture"
+	connectionStatusMessageCDCUnsupported   = "Change data capture not supported"
+)
+
+// the statements of psql creating the publication of the tables of the database and the logical replication slot,
+// and granting the replication to the user of the Connection
+const postgresCDCBootstrapSQL = `SELECT format('CREATE PUBLICATION %I', :'publication') WHERE NOT EXISTS (SELECT FROM pg_publication WHERE pubname = :'publication')
+\gexec
+SELECT format('ALTER PUBLICATION %I ADD TABLE %I.%I', :'publication', t.schemaname, t.tablename) FROM pg_tables t WHERE t.schemaname NOT IN ('pg_catalog', 'information_schema') AND NOT EXISTS (SELECT FROM pg_publication_tables p WHERE p.pubname = :'publication' AND p.schemaname = t.schemaname AND p.tablename = t.tablename)
+\gexec
+SELECT pg_create_logical_replication_slot(:'slot', 'pgoutput') WHERE NOT EXISTS (SELECT FROM pg_replication_slots WHERE slot_name = :'slot');
+GRANT rds_replication TO :"user";
+`
+
+// isCDCConnection returns true if the Connection bootstraps the change data capture of its database
+func isCDCConnection(connection *rdsdbaasv1alpha1.RDSConnection) (bool, error) {
+	value, ok := connection.Annotations[connectionCDCAnnotation]
+	if !ok || len(value) == 0 {
+		return false, nil
+	}
+	cdc, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("value %s of annotation %s is invalid", value, connectionCDCAnnotation)
+	}
+	return cdc, nil
+}
+
+// validateCDCConnection returns an error if the change data capture cannot be bootstrapped for the engine
+func validateCDCConnection(connection *rdsdbaasv1alpha1.RDSConnection, engine string) error {
+	switch generateBindingType(engine) {
+	case "postgresql":
+		return nil
+	case "mysql":
+		_, err := getCDCBinlogRetentionHours(connection)
+		return err
+	default:
+		return fmt.Errorf("change data capture not supported by engine %s", engine)
+	}
+}
+
+func getCDCBinlogRetentionHours(connection *rdsdbaasv1alpha1.RDSConnection) (int, error) {
+	value, ok := connection.Annotations[connectionCDCBinlogRetentionHoursAnnotation]
+	if !ok || len(value) == 0 {
+		return defaultCDCBinlogRetentionHours, nil
+	}
+	// the binlog retention of RDS for MySQL is 168 hours at most
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 1 || hours > 168 {
+		return 0, fmt.Errorf("value %s of annotation %s is invalid", value, connectionCDCBinlogRetentionHoursAnnotation)
+	}
+	return hours, nil
+}
+
+// getCDCParameters returns the CDC parameters of the Connection for the engine, the slot and publication names of
+// PostgreSQL, or the server ID of the MySQL binlog client, which are unique for each Connection
+func getCDCParameters(connection *rdsdbaasv1alpha1.RDSConnection, engine string) map[string][]byte {
+	sum := sha256.Sum256([]byte(connection.Namespace + "/" + connection.Name))
+	switch generateBindingType(engine) {
+	case "postgresql":
+		name := cdcPrefix + hex.EncodeToString(sum[:])[:16]
+		return map[string][]byte{
+			cdcKeySlotName:        []byte(name),
+			cdcKeyPublicationName: []byte(name),
+			cdcKeyPluginName:      []byte(cdcPlugin),
+		}
+	case "mysql":
+		// the server IDs of the replicas are positive 32 bits integers
+		serverID := binary.BigEndian.Uint32(sum[:4])&0x7fffffff | 1
+		hours, _ := getCDCBinlogRetentionHours(connection)
+		return map[string][]byte{
+			cdcKeyServerID:             []byte(strconv.FormatUint(uint64(serverID), 10)),
+			cdcKeyBinlogRetentionHours: []byte(strconv.Itoa(hours)),
+		}
+	default:
+		return nil
+	}
+}
+
+func getCDCBootstrapObjectName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-cdc-bootstrap", connection.Name)
+}
+
+// syncCDCBootstrap bootstraps the change data capture of the database of the Connection with a Job run with the master
+// credentials, and returns the CDC parameters of the binding Secret once the Job completes. The engine must be
+// validated first.
+func (r *RDSConnectionReconciler) syncCDCBootstrap(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	engine string, masterUsername *string, masterPassword []byte, username *string, host *string, port *int64,
+	dbName *string) (map[string][]byte, bool, error) {
+	logger := log.FromContext(ctx)
+
+	parameters := getCDCParameters(connection, engine)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCDCBootstrapObjectName(connection),
+			Namespace: connection.Namespace,
+		},
+	}
+	if _, err := createOrApply(ctx, r.Client, secret, func(client.Object) error {
+		secret.Labels = buildConnectionLabels()
+		secret.Annotations = buildConnectionAnnotations(connection)
+		if err := ctrl.SetControllerReference(connection, secret, r.Scheme); err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			"username":                     []byte(*username),
+			connectionJobKeyMasterUsername: []byte(*masterUsername),
+			connectionJobKeyMasterPassword: masterPassword,
+		}
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: connection.Namespace, Name: getCDCBootstrapObjectName(connection)}, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, false, err
+		}
+		job = r.newCDCBootstrapJob(connection, generateBindingType(engine), parameters,
+			*username != *masterUsername, host, port, dbName)
+		if err := ctrl.SetControllerReference(connection, job, r.Scheme); err != nil {
+			return nil, false, err
+		}
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, false, err
+		}
+		logger.Info("CDC bootstrap Job created", "Job", job.Name)
+		return nil, false, nil
+	}
+
+	if done, err := getConnectionJobResult(job); !done || err != nil {
+		return nil, false, err
+	}
+	return parameters, true, nil
+}
+
+// newCDCBootstrapJob returns the Job bootstrapping the change data capture, the replication is granted to the user of
+// the Connection unless it is the master user already granted the replication
+func (r *RDSConnectionReconciler) newCDCBootstrapJob(connection *rdsdbaasv1alpha1.RDSConnection, bindingType string,
+	parameters map[string][]byte, grantUser bool, host *string, port *int64, dbName *string) *batchv1.Job {
+	secretName := getCDCBootstrapObjectName(connection)
+	container := r.newConnectionJobContainer("cdc-bootstrap", bindingType)
+	var dbPort string
+	if port != nil {
+		dbPort = strconv.FormatInt(*port, 10)
+	}
+	switch bindingType {
+	case "postgresql":
+		container.Command = []string{"/bin/sh", "-c",
+			`printf '%s\n' "$CDC_SQL" | psql --no-psqlrc -v ON_ERROR_STOP=1 -v user="$CDC_USER" -v slot="$CDC_SLOT" -v publication="$CDC_PUBLICATION" --file=-`}
+		database := pointer.StringDeref(dbName, "")
+		if len(database) == 0 {
+			database = *getDefaultDBName(postgres)
+		}
+		container.Env = []v1.EnvVar{
+			{Name: "PGHOST", Value: pointer.StringDeref(host, "")},
+			{Name: "PGPORT", Value: dbPort},
+			{Name: "PGDATABASE", Value: database},
+			secretEnvVar("PGUSER", secretName, connectionJobKeyMasterUsername),
+			secretEnvVar("PGPASSWORD", secretName, connectionJobKeyMasterPassword),
+			secretEnvVar("CDC_USER", secretName, "username"),
+			{Name: "CDC_SLOT", Value: string(parameters[cdcKeySlotName])},
+			{Name: "CDC_PUBLICATION", Value: string(parameters[cdcKeyPublicationName])},
+			{Name: "CDC_SQL", Value: postgresCDCBootstrapSQL},
+			{Name: "PGCONNECT_TIMEOUT", Value: "10"},
+		}
+	case "mysql":
+		sql := `CALL mysql.rds_set_configuration('binlog retention hours', $CDC_BINLOG_RETENTION_HOURS);`
+		if grantUser {
+			sql += ` GRANT REPLICATION SLAVE, REPLICATION CLIENT ON *.* TO '$CDC_USER'@'%';`
+		}
+		container.Command = []string{"/bin/sh", "-c",
+			`mysql --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" --connect-timeout=10 --execute="` + sql + `"`}
+		container.Env = []v1.EnvVar{
+			{Name: "DB_HOST", Value: pointer.StringDeref(host, "")},
+			{Name: "DB_PORT", Value: dbPort},
+			secretEnvVar("DB_USER", secretName, connectionJobKeyMasterUsername),
+			secretEnvVar("MYSQL_PWD", secretName, connectionJobKeyMasterPassword),
+			secretEnvVar("CDC_USER", secretName, "username"),
+			{Name: "CDC_BINLOG_RETENTION_HOURS", Value: string(parameters[cdcKeyBinlogRetentionHours])},
+		}
+	}
+	return newConnectionJob(connection, secretName, cdcBootstrapActiveDeadlineSeconds, container)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionCDC", func() {
+	connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Name: "connection", Namespace: "app"}}
+
+	It("should validate the change data capture of the Connection", func() {
+		c := connection.DeepCopy()
+		Expect(isCDCConnection(c)).Should(BeFalse())
+		c.Annotations = map[string]string{connectionCDCAnnotation: "true"}
+		Expect(isCDCConnection(c)).Should(BeTrue())
+
+		Expect(validateCDCConnection(c, "postgres")).Should(Succeed())
+		Expect(validateCDCConnection(c, "mysql")).Should(Succeed())
+		Expect(validateCDCConnection(c, "sqlserver-ex")).ShouldNot(Succeed())
+		c.Annotations[connectionCDCBinlogRetentionHoursAnnotation] = "200"
+		Expect(validateCDCConnection(c, "mysql")).ShouldNot(Succeed())
+	})
+
+	It("should return the CDC parameters of the engine", func() {
+		parameters := getCDCParameters(connection, "postgres")
+		Expect(parameters).Should(HaveKeyWithValue(cdcKeyPluginName, []byte("pgoutput")))
+		Expect(string(parameters[cdcKeySlotName])).Should(HavePrefix("cdc_"))
+		Expect(parameters[cdcKeyPublicationName]).Should(Equal(parameters[cdcKeySlotName]))
+
+		parameters = getCDCParameters(connection, "mysql")
+		Expect(parameters).Should(HaveKeyWithValue(cdcKeyBinlogRetentionHours, []byte("24")))
+		serverID, err := strconv.ParseUint(string(parameters[cdcKeyServerID]), 10, 32)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(serverID).Should(BeNumerically(">", 0))
+	})
+
+	It("should grant the replication to the user of the Connection", func() {
+		r := &RDSConnectionReconciler{}
+		job := r.newCDCBootstrapJob(connection, "mysql", getCDCParameters(connection, "mysql"), true,
+			pointer.String("host"), pointer.Int64(3306), pointer.String("app"))
+		Expect(job.Name).Should(Equal("connection-cdc-bootstrap"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).Should(ContainSubstring("GRANT REPLICATION SLAVE"))
+
+		job = r.newCDCBootstrapJob(connection, "mysql", getCDCParameters(connection, "mysql"), false,
+			pointer.String("host"), pointer.Int64(3306), pointer.String("app"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).ShouldNot(ContainSubstring("GRANT"))
+	})
+})
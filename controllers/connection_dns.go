@@ -0,0 +1,106 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// skip the check that the DB endpoint of the Connection resolves from the cluster (true), for the clusters whose
+	// operator namespace does not use the DNS of the application namespaces
+	connectionSkipDNSCheckAnnotation = "rds.dbaas.redhat.com/skip-dns-check"
+
+	dnsCheckTimeout = 5 * time.Second
+
+	connectionConditionEndpointResolved = "EndpointResolved"
+
+	connectionStatusReasonResolved    = "Resolved"
+	connectionStatusReasonDNSNotFound = "DNSNotFound"
+	connectionStatusReasonDNSTimeout  = "DNSTimeout"
+	connectionStatusReasonDNSError    = "DNSError"
+
+	connectionStatusMessageResolved    = "Endpoint %s resolves to %s"
+	connectionStatusMessageDNSNotFound = "Endpoint %s does not resolve from the cluster, associate the private hosted zone of " +
+		"the endpoint with the VPC of the cluster or forward its queries to the VPC resolver"
+	connectionStatusMessageDNSTimeout = "Resolution of endpoint %s timed out, check that the DNS servers of the cluster can " +
+		"reach a resolver of the private hosted zone of the endpoint"
+	connectionStatusMessageDNSError = "Resolution of endpoint %s failed: %v"
+)
+
+// checkEndpointResolution checks that the DB endpoint of the Connection resolves from the cluster and sets the result
+// in its EndpointResolved condition, the reason and the message of the failure are returned with its error so that
+// the Connection is not reported as failing to connect when DNS is the blocker
+func (r *RDSConnectionReconciler) checkEndpointResolution(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	host string) (string, string, error) {
+	if connection.Annotations[connectionSkipDNSCheckAnnotation] == "true" {
+		apimeta.RemoveStatusCondition(&connection.Status.Conditions, connectionConditionEndpointResolved)
+		return "", "", nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsCheckTimeout)
+	defer cancel()
+	addrs, err := r.lookupIPAddr(lookupCtx, host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+	}
+
+	condition := metav1.Condition{
+		Type:               connectionConditionEndpointResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             connectionStatusReasonResolved,
+		ObservedGeneration: connection.Generation,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason, condition.Message = getDNSErrorReason(host, err)
+		apimeta.SetStatusCondition(&connection.Status.Conditions, condition)
+		return condition.Reason, condition.Message, err
+	}
+	var ips []string
+	for _, a := range addrs {
+		ips = append(ips, a.IP.String())
+	}
+	sort.Strings(ips)
+	condition.Message = fmt.Sprintf(connectionStatusMessageResolved, host, strings.Join(ips, ","))
+	apimeta.SetStatusCondition(&connection.Status.Conditions, condition)
+	return "", "", nil
+}
+
+// getDNSErrorReason returns the reason and the message of the resolution failure of the endpoint
+func getDNSErrorReason(host string, err error) (string, string) {
+	var dnsErr *net.DNSError
+	switch {
+	case goerrors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return connectionStatusReasonDNSNotFound, fmt.Sprintf(connectionStatusMessageDNSNotFound, host)
+	case goerrors.As(err, &dnsErr) && dnsErr.IsTimeout, goerrors.Is(err, context.DeadlineExceeded):
+		return connectionStatusReasonDNSTimeout, fmt.Sprintf(connectionStatusMessageDNSTimeout, host)
+	default:
+		return connectionStatusReasonDNSError, fmt.Sprintf(connectionStatusMessageDNSError, host, err)
+	}
+}
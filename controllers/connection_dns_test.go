@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Connection endpoint resolution", func() {
+	connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "db", Generation: 2}}
+	host := "db.internal.example.com"
+
+	It("should report the addresses the DB endpoint resolves to", func() {
+		r := &RDSConnectionReconciler{LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return []net.IPAddr{{IP: net.ParseIP("10.0.2.15")}, {IP: net.ParseIP("10.0.1.20")}}, nil
+		}}
+		c := connection.DeepCopy()
+		_, _, err := r.checkEndpointResolution(context.TODO(), c, host)
+		Expect(err).ShouldNot(HaveOccurred())
+		condition := apimeta.FindStatusCondition(c.Status.Conditions, connectionConditionEndpointResolved)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).Should(ContainSubstring("10.0.1.20,10.0.2.15"))
+		Expect(condition.ObservedGeneration).Should(Equal(int64(2)))
+	})
+
+	It("should report the private hosted zone when the DB endpoint is not found", func() {
+		r := &RDSConnectionReconciler{LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}}
+		c := connection.DeepCopy()
+		reason, message, err := r.checkEndpointResolution(context.TODO(), c, host)
+		Expect(err).Should(HaveOccurred())
+		Expect(reason).Should(Equal(connectionStatusReasonDNSNotFound))
+		Expect(message).Should(ContainSubstring("private hosted zone"))
+		condition := apimeta.FindStatusCondition(c.Status.Conditions, connectionConditionEndpointResolved)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).Should(Equal(connectionStatusReasonDNSNotFound))
+	})
+
+	It("should report a timeout of the resolution", func() {
+		r := &RDSConnectionReconciler{LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return nil, &net.DNSError{Err: "i/o timeout", Name: host, IsTimeout: true}
+		}}
+		reason, _, err := r.checkEndpointResolution(context.TODO(), connection.DeepCopy(), host)
+		Expect(err).Should(HaveOccurred())
+		Expect(reason).Should(Equal(connectionStatusReasonDNSTimeout))
+	})
+
+	It("should skip the resolution when disabled", func() {
+		resolved := false
+		r := &RDSConnectionReconciler{LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			resolved = true
+			return nil, nil
+		}}
+		c := connection.DeepCopy()
+		c.Annotations = map[string]string{connectionSkipDNSCheckAnnotation: "true"}
+		_, _, err := r.checkEndpointResolution(context.TODO(), c, host)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).Should(BeFalse())
+		Expect(c.Status.Conditions).Should(BeEmpty())
+	})
+})
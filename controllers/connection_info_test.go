@@ -0,0 +1,67 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("ConnectionInfo", func() {
+	It("should set the defaults of the engine for the database name and the port", func() {
+		cm := &v1.ConfigMap{}
+		setConfigMap(cm, pointer.String("oracle-ee-cdb"), pointer.String(""), pointer.String("host"), pointer.Int64(0), nil, false)
+		Expect(cm.Data).Should(HaveKeyWithValue("database", "RDSCDB"))
+		Expect(cm.Data).Should(HaveKeyWithValue("port", "1521"))
+		Expect(cm.Data).Should(HaveKeyWithValue("type", "oracle"))
+
+		setConfigMap(cm, pointer.String("sqlserver-ex"), nil, pointer.String("host"), nil, nil, false)
+		Expect(cm.Data).Should(HaveKeyWithValue("database", "master"))
+		Expect(cm.Data).Should(HaveKeyWithValue("port", "1433"))
+
+		setConfigMap(cm, pointer.String("postgres"), pointer.String("app"), pointer.String("host"), pointer.Int64(5433), nil, false)
+		Expect(cm.Data).Should(HaveKeyWithValue("database", "app"))
+		Expect(cm.Data).Should(HaveKeyWithValue("port", "5433"))
+	})
+
+	It("should set the engine, the engine family and the engine version", func() {
+		data := map[string]string{}
+		setEngineConnectionInfo(data, &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			Engine:        pointer.String("mariadb"),
+			EngineVersion: pointer.String("10.6.10"),
+		}})
+		Expect(data).Should(Equal(map[string]string{
+			connectionInfoEngine:        "mariadb",
+			connectionInfoEngineFamily:  "mariadb",
+			connectionInfoEngineVersion: "10.6.10",
+		}))
+
+		data = map[string]string{}
+		setEngineConnectionInfo(data, &rdsv1alpha1.DBCluster{Spec: rdsv1alpha1.DBClusterSpec{
+			Engine: pointer.String("aurora-postgresql"),
+		}})
+		Expect(data).Should(Equal(map[string]string{
+			connectionInfoEngine:       "aurora-postgresql",
+			connectionInfoEngineFamily: "postgresql",
+		}))
+	})
+})
@@ -0,0 +1,282 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	label "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// create a NetworkPolicy allowing the egress of the pods of the namespace of the Connection to the DB endpoint
+	// (create), or only render it with an OpenShift EgressFirewall rule in a ConfigMap to be applied by the users
+	// (manifest), for the clusters denying the egress by default
+	connectionNetworkPolicyAnnotation = "rds.dbaas.redhat.com/network-policy"
+	connectionNetworkPolicyCreate     = "create"
+	connectionNetworkPolicyManifest   = "manifest"
+
+	// the comma-separated labels (key=value) of the pods the NetworkPolicy of the Connection applies to, all the pods
+	// of the namespace if not set
+	connectionNetworkPolicyPodSelectorAnnotation = "rds.dbaas.redhat.com/network-policy-pod-selector"
+
+	connectionNetworkPolicyManifestKey  = "networkpolicy.yaml"
+	connectionEgressFirewallManifestKey = "egressfirewall.yaml"
+
+	egressFirewallVersion = "k8s.ovn.org/v1"
+	egressFirewallKind    = "EgressFirewall"
+	// the EgressFirewall of a namespace must have this name
+	egressFirewallName = "default"
+
+	connectionConditionNetworkPolicy = "NetworkPolicy"
+
+	connectionNetworkPolicyReasonCreated    = "Created"
+	connectionNetworkPolicyReasonRendered   = "Rendered"
+	connectionNetworkPolicyReasonInputError = "InputError"
+	connectionNetworkPolicyReasonFailed     = "Failed"
+
+	connectionNetworkPolicyMessageCreated  = "NetworkPolicy %s allows the egress to %s port %d, its addresses are updated when the endpoint resolves to new ones"
+	connectionNetworkPolicyMessageRendered = "NetworkPolicy and EgressFirewall rule rendered in ConfigMap %s"
+	connectionNetworkPolicyMessageError    = "Failed to sync network policy"
+)
+
+// syncNetworkPolicy creates or renders the NetworkPolicy of the egress to the DB endpoint of the Connection if enabled,
+// and removes it once disabled. The endpoint is resolved at every reconcile as its addresses change on failover.
+func (r *RDSConnectionReconciler) syncNetworkPolicy(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	engine string, host *string, port *int64) error {
+	logger := log.FromContext(ctx)
+
+	setCondition := func(status metav1.ConditionStatus, reason, message string) {
+		apimeta.SetStatusCondition(&connection.Status.Conditions, metav1.Condition{
+			Type:               connectionConditionNetworkPolicy,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: connection.Generation,
+		})
+	}
+
+	mode := connection.Annotations[connectionNetworkPolicyAnnotation]
+	if mode != connectionNetworkPolicyCreate && mode != connectionNetworkPolicyManifest {
+		if apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionNetworkPolicy) == nil {
+			return nil
+		}
+		if e := r.deleteNetworkPolicy(ctx, connection); e != nil {
+			setCondition(metav1.ConditionFalse, connectionNetworkPolicyReasonFailed, connectionNetworkPolicyMessageError)
+			return e
+		}
+		logger.Info("Network policy removed")
+		apimeta.RemoveStatusCondition(&connection.Status.Conditions, connectionConditionNetworkPolicy)
+		return nil
+	}
+	if host == nil {
+		return nil
+	}
+	if port == nil {
+		if port = getDefaultDBPort(engine); port == nil {
+			return nil
+		}
+	}
+
+	podSelector, e := getNetworkPolicyPodSelector(connection)
+	if e != nil {
+		setCondition(metav1.ConditionFalse, connectionNetworkPolicyReasonInputError, e.Error())
+		return nil
+	}
+	addrs, e := r.lookupIPAddr(ctx, *host)
+	if e != nil {
+		setCondition(metav1.ConditionFalse, connectionNetworkPolicyReasonFailed, connectionNetworkPolicyMessageError)
+		return e
+	}
+	policy := newNetworkPolicy(connection, podSelector, addrs, int32(*port))
+
+	if mode == connectionNetworkPolicyManifest {
+		cm, e := r.createOrUpdateNetworkPolicyManifest(ctx, connection, policy, newEgressFirewall(connection, *host, int32(*port)))
+		if e != nil {
+			setCondition(metav1.ConditionFalse, connectionNetworkPolicyReasonFailed, connectionNetworkPolicyMessageError)
+			return e
+		}
+		setCondition(metav1.ConditionTrue, connectionNetworkPolicyReasonRendered, fmt.Sprintf(connectionNetworkPolicyMessageRendered, cm.Name))
+		return nil
+	}
+
+	if _, e := createOrApply(ctx, r.Client, policy, func(client.Object) error {
+		return ctrl.SetControllerReference(connection, policy, r.Scheme)
+	}); e != nil {
+		setCondition(metav1.ConditionFalse, connectionNetworkPolicyReasonFailed, connectionNetworkPolicyMessageError)
+		return e
+	}
+	setCondition(metav1.ConditionTrue, connectionNetworkPolicyReasonCreated,
+		fmt.Sprintf(connectionNetworkPolicyMessageCreated, policy.Name, *host, *port))
+	return nil
+}
+
+func getNetworkPolicyName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-db-egress", connection.Name)
+}
+
+// getNetworkPolicyPodSelector returns the labels of the pods the NetworkPolicy of the Connection applies to
+func getNetworkPolicyPodSelector(connection *rdsdbaasv1alpha1.RDSConnection) (map[string]string, error) {
+	value, ok := connection.Annotations[connectionNetworkPolicyPodSelectorAnnotation]
+	if !ok || len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	selector, err := label.ConvertSelectorToLabelsMap(value)
+	if err != nil {
+		return nil, fmt.Errorf("value %s of annotation %s is invalid", value, connectionNetworkPolicyPodSelectorAnnotation)
+	}
+	return selector, nil
+}
+
+// lookupIPAddr resolves the DB endpoint, with the resolver of the reconciler if set
+func (r *RDSConnectionReconciler) lookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if r.LookupIPAddr != nil {
+		return r.LookupIPAddr(ctx, host)
+	}
+	return net.DefaultResolver.LookupIPAddr(ctx, host)
+}
+
+// newNetworkPolicy returns the NetworkPolicy allowing the egress of the selected pods of the namespace of the
+// Connection to the addresses of the DB endpoint on its port
+func newNetworkPolicy(connection *rdsdbaasv1alpha1.RDSConnection, podSelector map[string]string, addrs []net.IPAddr,
+	port int32) *networkingv1.NetworkPolicy {
+	var cidrs []string
+	for _, a := range addrs {
+		if a.IP.To4() != nil {
+			cidrs = append(cidrs, a.IP.String()+"/32")
+		} else {
+			cidrs = append(cidrs, a.IP.String()+"/128")
+		}
+	}
+	// the order of the peers does not depend on the order of the DNS answers
+	sort.Strings(cidrs)
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, c := range cidrs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: c}})
+	}
+
+	protocol := v1.ProtocolTCP
+	dbPort := intstr.FromInt(int(port))
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getNetworkPolicyName(connection),
+			Namespace:   connection.Namespace,
+			Labels:      buildConnectionLabels(),
+			Annotations: buildConnectionAnnotations(connection),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &dbPort}},
+					To:    peers,
+				},
+			},
+		},
+	}
+}
+
+// newEgressFirewall returns the OpenShift EgressFirewall allowing the egress of the namespace of the Connection to the
+// DB endpoint by its DNS name, which is kept up to date by OVN-Kubernetes. The rule is to be merged into the
+// EgressFirewall of the namespace, as a namespace has only one.
+func newEgressFirewall(connection *rdsdbaasv1alpha1.RDSConnection, host string, port int32) *unstructured.Unstructured {
+	firewall := &unstructured.Unstructured{}
+	firewall.SetAPIVersion(egressFirewallVersion)
+	firewall.SetKind(egressFirewallKind)
+	firewall.SetName(egressFirewallName)
+	firewall.SetNamespace(connection.Namespace)
+	firewall.Object["spec"] = map[string]interface{}{
+		"egress": []interface{}{
+			map[string]interface{}{
+				"type": "Allow",
+				"to":   map[string]interface{}{"dnsName": host},
+				"ports": []interface{}{
+					map[string]interface{}{"protocol": "TCP", "port": int64(port)},
+				},
+			},
+		},
+	}
+	return firewall
+}
+
+// createOrUpdateNetworkPolicyManifest renders the NetworkPolicy and the EgressFirewall rule in a ConfigMap of the Connection
+func (r *RDSConnectionReconciler) createOrUpdateNetworkPolicyManifest(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	policy *networkingv1.NetworkPolicy, firewall *unstructured.Unstructured) (*v1.ConfigMap, error) {
+	p, e := yaml.Marshal(policy)
+	if e != nil {
+		return nil, e
+	}
+	f, e := yaml.Marshal(firewall.Object)
+	if e != nil {
+		return nil, e
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getNetworkPolicyName(connection),
+			Namespace: connection.Namespace,
+		},
+	}
+	if _, e := createOrApply(ctx, r.Client, cm, func(client.Object) error {
+		cm.ObjectMeta.Labels = buildConnectionLabels()
+		cm.ObjectMeta.Annotations = buildConnectionAnnotations(connection)
+		if err := ctrl.SetControllerReference(connection, cm, r.Scheme); err != nil {
+			return err
+		}
+		cm.Data = map[string]string{
+			connectionNetworkPolicyManifestKey:  string(p),
+			connectionEgressFirewallManifestKey: string(f),
+		}
+		return nil
+	}); e != nil {
+		return nil, e
+	}
+	return cm, nil
+}
+
+// deleteNetworkPolicy removes the NetworkPolicy and the manifest ConfigMap of the Connection
+func (r *RDSConnectionReconciler) deleteNetworkPolicy(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	meta := metav1.ObjectMeta{Name: getNetworkPolicyName(connection), Namespace: connection.Namespace}
+	for _, obj := range []client.Object{
+		&networkingv1.NetworkPolicy{ObjectMeta: meta},
+		&v1.ConfigMap{ObjectMeta: meta},
+	} {
+		if e := r.Delete(ctx, obj); e != nil && !errors.IsNotFound(e) {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Connection network policy", func() {
+	connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "db"}}
+
+	It("should allow the egress to the addresses of the DB endpoint", func() {
+		policy := newNetworkPolicy(connection, map[string]string{"app": "web"}, []net.IPAddr{
+			{IP: net.ParseIP("10.0.2.15")}, {IP: net.ParseIP("10.0.1.20")}, {IP: net.ParseIP("fd00::1")},
+		}, 5432)
+		Expect(policy.Name).Should(Equal("db-db-egress"))
+		Expect(policy.Namespace).Should(Equal("team-a"))
+		Expect(policy.Spec.PodSelector.MatchLabels).Should(Equal(map[string]string{"app": "web"}))
+		Expect(policy.Spec.Egress).Should(HaveLen(1))
+		var cidrs []string
+		for _, p := range policy.Spec.Egress[0].To {
+			cidrs = append(cidrs, p.IPBlock.CIDR)
+		}
+		Expect(cidrs).Should(Equal([]string{"10.0.1.20/32", "10.0.2.15/32", "fd00::1/128"}))
+		Expect(policy.Spec.Egress[0].Ports[0].Port.IntValue()).Should(Equal(5432))
+	})
+
+	It("should render the EgressFirewall rule of the DB endpoint", func() {
+		firewall := newEgressFirewall(connection, "db.abc.us-east-1.rds.amazonaws.com", 3306)
+		Expect(firewall.GetName()).Should(Equal("default"))
+		Expect(firewall.GetNamespace()).Should(Equal("team-a"))
+		rule := firewall.Object["spec"].(map[string]interface{})["egress"].([]interface{})[0].(map[string]interface{})
+		Expect(rule["to"]).Should(Equal(map[string]interface{}{"dnsName": "db.abc.us-east-1.rds.amazonaws.com"}))
+	})
+
+	It("should parse the pod selector of the network policy", func() {
+		c := connection.DeepCopy()
+		Expect(getNetworkPolicyPodSelector(c)).Should(BeNil())
+		c.Annotations = map[string]string{connectionNetworkPolicyPodSelectorAnnotation: "app=web,tier=backend"}
+		Expect(getNetworkPolicyPodSelector(c)).Should(Equal(map[string]string{"app": "web", "tier": "backend"}))
+		c.Annotations[connectionNetworkPolicyPodSelectorAnnotation] = "app"
+		_, err := getNetworkPolicyPodSelector(c)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should not resolve the DB endpoint unless the network policy is enabled", func() {
+		resolved := false
+		r := &RDSConnectionReconciler{LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			resolved = true
+			return nil, nil
+		}}
+		c := connection.DeepCopy()
+		Expect(r.syncNetworkPolicy(context.TODO(), c, "postgres", pointer.String("db.example.com"), nil)).Should(Succeed())
+		Expect(resolved).Should(BeFalse())
+		Expect(c.Status.Conditions).Should(BeEmpty())
+	})
+})
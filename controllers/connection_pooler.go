@@ -0,0 +1,314 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// deploy a pgbouncer Deployment and Service in front of the DB service of a PostgreSQL Connection (deploy), or only
+	// render their manifests in a ConfigMap to be applied by the users (manifest)
+	connectionPoolerAnnotation = "rds.dbaas.redhat.com/pgbouncer"
+	connectionPoolerDeploy     = "deploy"
+	connectionPoolerManifest   = "manifest"
+
+	// the pool mode (session, transaction or statement) and the pool size of the pgbouncer of the Connection
+	connectionPoolerPoolModeAnnotation = "rds.dbaas.redhat.com/pgbouncer-pool-mode"
+	connectionPoolerPoolSizeAnnotation = "rds.dbaas.redhat.com/pgbouncer-pool-size"
+
+	DefaultConnectionPoolerImage = "docker.io/edoburu/pgbouncer:1.18.0"
+
+	defaultConnectionPoolerPoolMode = "transaction"
+	defaultConnectionPoolerPoolSize = 20
+
+	connectionPoolerPort        = 5432
+	connectionPoolerManifestKey = "pgbouncer.yaml"
+
+	connectionConditionPooler = "ConnectionPooler"
+
+	connectionPoolerReasonDeployed    = "Deployed"
+	connectionPoolerReasonRendered    = "Rendered"
+	connectionPoolerReasonUnsupported = "Unsupported"
+	connectionPoolerReasonInputError  = "InputError"
+	connectionPoolerReasonFailed      = "Failed"
+
+	connectionPoolerMessageDeployed    = "pgbouncer deployed, connect to %s.%s.svc:%d"
+	connectionPoolerMessageRendered    = "pgbouncer manifests rendered in ConfigMap %s"
+	connectionPoolerMessageUnsupported = "Connection pooler only supported for PostgreSQL engines"
+	connectionPoolerMessageError       = "Failed to sync connection pooler"
+)
+
+// syncConnectionPooler deploys or renders the pgbouncer of the Connection if enabled, and removes it once disabled
+func (r *RDSConnectionReconciler) syncConnectionPooler(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection, engine string) error {
+	logger := log.FromContext(ctx)
+
+	setCondition := func(status metav1.ConditionStatus, reason, message string) {
+		apimeta.SetStatusCondition(&connection.Status.Conditions, metav1.Condition{
+			Type:               connectionConditionPooler,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: connection.Generation,
+		})
+	}
+
+	mode := connection.Annotations[connectionPoolerAnnotation]
+	if mode != connectionPoolerDeploy && mode != connectionPoolerManifest {
+		if apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionPooler) == nil {
+			return nil
+		}
+		if e := r.deleteConnectionPooler(ctx, connection); e != nil {
+			setCondition(metav1.ConditionFalse, connectionPoolerReasonFailed, connectionPoolerMessageError)
+			return e
+		}
+		logger.Info("Connection pooler removed")
+		apimeta.RemoveStatusCondition(&connection.Status.Conditions, connectionConditionPooler)
+		return nil
+	}
+	if generateBindingType(engine) != "postgresql" {
+		setCondition(metav1.ConditionFalse, connectionPoolerReasonUnsupported, connectionPoolerMessageUnsupported)
+		return nil
+	}
+	if connection.Status.CredentialsRef == nil || connection.Status.ConnectionInfoRef == nil {
+		return nil
+	}
+
+	deployment, service, e := r.newConnectionPooler(connection)
+	if e != nil {
+		setCondition(metav1.ConditionFalse, connectionPoolerReasonInputError, e.Error())
+		return nil
+	}
+
+	if mode == connectionPoolerManifest {
+		cm, e := r.createOrUpdateConnectionPoolerManifest(ctx, connection, deployment, service)
+		if e != nil {
+			setCondition(metav1.ConditionFalse, connectionPoolerReasonFailed, connectionPoolerMessageError)
+			return e
+		}
+		setCondition(metav1.ConditionTrue, connectionPoolerReasonRendered, fmt.Sprintf(connectionPoolerMessageRendered, cm.Name))
+		return nil
+	}
+
+	for _, obj := range []client.Object{deployment, service} {
+		obj := obj
+		if _, e := createOrApply(ctx, r.Client, obj, func(client.Object) error {
+			return ctrl.SetControllerReference(connection, obj, r.Scheme)
+		}); e != nil {
+			setCondition(metav1.ConditionFalse, connectionPoolerReasonFailed, connectionPoolerMessageError)
+			return e
+		}
+	}
+	setCondition(metav1.ConditionTrue, connectionPoolerReasonDeployed,
+		fmt.Sprintf(connectionPoolerMessageDeployed, service.Name, service.Namespace, connectionPoolerPort))
+	return nil
+}
+
+func getConnectionPoolerName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-pgbouncer", connection.Name)
+}
+
+// newConnectionPooler returns the pgbouncer Deployment and Service of the Connection, the pgbouncer reads the
+// credentials and the connection info from the Secret and the ConfigMap of the Connection
+func (r *RDSConnectionReconciler) newConnectionPooler(connection *rdsdbaasv1alpha1.RDSConnection) (*appsv1.Deployment, *v1.Service, error) {
+	poolMode := defaultConnectionPoolerPoolMode
+	if m, ok := connection.Annotations[connectionPoolerPoolModeAnnotation]; ok {
+		switch m {
+		case "session", "transaction", "statement":
+			poolMode = m
+		default:
+			return nil, nil, fmt.Errorf("value of annotation %s is invalid", connectionPoolerPoolModeAnnotation)
+		}
+	}
+	poolSize := defaultConnectionPoolerPoolSize
+	if s, ok := connection.Annotations[connectionPoolerPoolSizeAnnotation]; ok {
+		i, e := strconv.Atoi(s)
+		if e != nil || i <= 0 {
+			return nil, nil, fmt.Errorf("value of annotation %s is invalid", connectionPoolerPoolSizeAnnotation)
+		}
+		poolSize = i
+	}
+
+	image := r.ConnectionPoolerImage
+	if len(image) == 0 {
+		image = DefaultConnectionPoolerImage
+	}
+	name := getConnectionPoolerName(connection)
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "pgbouncer",
+		"app.kubernetes.io/instance":   name,
+		"app.kubernetes.io/managed-by": "rds-dbaas-operator",
+	}
+	fromConfigMap := func(name, key string, optional bool) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: connection.Status.ConnectionInfoRef.Name},
+					Key:                  key,
+					Optional:             pointer.Bool(optional),
+				},
+			},
+		}
+	}
+	fromSecret := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: connection.Status.CredentialsRef.Name},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: connection.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					AutomountServiceAccountToken: pointer.Bool(false),
+					SecurityContext: &v1.PodSecurityContext{
+						SeccompProfile: &v1.SeccompProfile{
+							Type: v1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []v1.Container{
+						{
+							Name:  "pgbouncer",
+							Image: image,
+							Ports: []v1.ContainerPort{{Name: "postgres", ContainerPort: connectionPoolerPort}},
+							Env: []v1.EnvVar{
+								fromConfigMap("DB_HOST", "host", false),
+								fromConfigMap("DB_PORT", "port", false),
+								fromConfigMap("DB_NAME", "database", true),
+								fromConfigMap("SERVER_TLS_SSLMODE", connectionInfoSSLMode, true),
+								fromSecret("DB_USER", "username"),
+								fromSecret("DB_PASSWORD", "password"),
+								{Name: "LISTEN_PORT", Value: strconv.Itoa(connectionPoolerPort)},
+								{Name: "AUTH_TYPE", Value: "scram-sha-256"},
+								{Name: "POOL_MODE", Value: poolMode},
+								{Name: "DEFAULT_POOL_SIZE", Value: strconv.Itoa(poolSize)},
+							},
+							ReadinessProbe: &v1.Probe{
+								ProbeHandler: v1.ProbeHandler{
+									TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(connectionPoolerPort)},
+								},
+							},
+							SecurityContext: &v1.SecurityContext{
+								AllowPrivilegeEscalation: pointer.Bool(false),
+								RunAsNonRoot:             pointer.Bool(true),
+								Capabilities: &v1.Capabilities{
+									Drop: []v1.Capability{"ALL"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	service := &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: connection.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: labels,
+			Ports: []v1.ServicePort{
+				{Name: "postgres", Port: connectionPoolerPort, TargetPort: intstr.FromString("postgres")},
+			},
+		},
+	}
+	return deployment, service, nil
+}
+
+// createOrUpdateConnectionPoolerManifest renders the manifests of the pgbouncer in a ConfigMap of the Connection
+func (r *RDSConnectionReconciler) createOrUpdateConnectionPoolerManifest(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	deployment *appsv1.Deployment, service *v1.Service) (*v1.ConfigMap, error) {
+	d, e := yaml.Marshal(deployment)
+	if e != nil {
+		return nil, e
+	}
+	s, e := yaml.Marshal(service)
+	if e != nil {
+		return nil, e
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getConnectionPoolerName(connection),
+			Namespace: connection.Namespace,
+		},
+	}
+	if _, e := createOrApply(ctx, r.Client, cm, func(client.Object) error {
+		cm.ObjectMeta.Labels = buildConnectionLabels()
+		cm.ObjectMeta.Annotations = buildConnectionAnnotations(connection)
+		if err := ctrl.SetControllerReference(connection, cm, r.Scheme); err != nil {
+			return err
+		}
+		cm.Data = map[string]string{
+			connectionPoolerManifestKey: fmt.Sprintf("%s---\n%s", d, s),
+		}
+		return nil
+	}); e != nil {
+		return nil, e
+	}
+	return cm, nil
+}
+
+// deleteConnectionPooler removes the pgbouncer Deployment, Service and manifest ConfigMap of the Connection
+func (r *RDSConnectionReconciler) deleteConnectionPooler(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	meta := metav1.ObjectMeta{Name: getConnectionPoolerName(connection), Namespace: connection.Namespace}
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: meta},
+		&v1.Service{ObjectMeta: meta},
+		&v1.ConfigMap{ObjectMeta: meta},
+	} {
+		if e := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); e != nil && !errors.IsNotFound(e) {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionPooler", func() {
+	newConnection := func(annotations map[string]string) *rdsdbaasv1alpha1.RDSConnection {
+		connection := &rdsdbaasv1alpha1.RDSConnection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "app-namespace",
+				Annotations: annotations,
+			},
+		}
+		connection.Status.CredentialsRef = &v1.LocalObjectReference{Name: "app-credentials"}
+		connection.Status.ConnectionInfoRef = &v1.LocalObjectReference{Name: "app-configs"}
+		return connection
+	}
+
+	It("should configure the pgbouncer from the Secret and the ConfigMap of the Connection", func() {
+		deployment, service, e := (&RDSConnectionReconciler{}).newConnectionPooler(newConnection(map[string]string{
+			connectionPoolerAnnotation:         connectionPoolerDeploy,
+			connectionPoolerPoolModeAnnotation: "session",
+		}))
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(deployment.Name).Should(Equal("app-pgbouncer"))
+		Expect(service.Name).Should(Equal("app-pgbouncer"))
+		Expect(service.Spec.Selector).Should(Equal(deployment.Spec.Selector.MatchLabels))
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).Should(Equal(DefaultConnectionPoolerImage))
+		env := map[string]v1.EnvVar{}
+		for _, e := range container.Env {
+			env[e.Name] = e
+		}
+		Expect(env["DB_HOST"].ValueFrom.ConfigMapKeyRef.Name).Should(Equal("app-configs"))
+		Expect(env["DB_PASSWORD"].ValueFrom.SecretKeyRef.Name).Should(Equal("app-credentials"))
+		Expect(env["POOL_MODE"].Value).Should(Equal("session"))
+		Expect(env["DEFAULT_POOL_SIZE"].Value).Should(Equal("20"))
+	})
+
+	It("should reject invalid pool settings", func() {
+		_, _, e := (&RDSConnectionReconciler{}).newConnectionPooler(newConnection(map[string]string{
+			connectionPoolerPoolModeAnnotation: "pooled",
+		}))
+		Expect(e).Should(HaveOccurred())
+		_, _, e = (&RDSConnectionReconciler{}).newConnectionPooler(newConnection(map[string]string{
+			connectionPoolerPoolSizeAnnotation: "0",
+		}))
+		Expect(e).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,447 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// bind the Connection with a user only granted SELECT on the database instead of the master user, the user is
+	// created by a Job with the master credentials
+	connectionReadOnlyAnnotation = "rds.dbaas.redhat.com/read-only"
+
+	readOnlyUsernamePrefix = "ro_"
+
+	readOnlyUserActiveDeadlineSeconds = 300
+
+	// the keys of the master credentials in the Secrets of the Jobs of the Connection
+	connectionJobKeyMasterUsername = "masterUsername"
+	connectionJobKeyMasterPassword = "masterPassword" //#nosec G101
+
+	// the label of the Jobs run with the master credentials and of their Secrets, which are kept out of the namespace of
+	// the Connection and owned by the Connection through its owner annotations as an owner reference cannot cross
+	// namespaces
+	connectionJobLabel = "rds.dbaas.redhat.com/connection-job"
+
+	// the finalizer of the Connections with Jobs run with the master credentials, which are deleted with the Connection
+	connectionJobsFinalizer = "rds.dbaas.redhat.com/connection-jobs"
+
+	connectionStatusMessageReadOnlyUserCreating = "Creating read-only user"
+	connectionStatusMessageReadOnlyUserError    = "Failed to create read-only user"
+	connectionStatusMessageReadOnlyUnsupported  = "Read-only Connection not supported"
+)
+
+// the statements of psql creating the read-only user, granted SELECT on the tables of all the schemas of the database
+const postgresReadOnlyUserSQL = `SELECT format('CREATE ROLE %I', :'user') WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = :'user')
+\gexec
+ALTER ROLE :"user" WITH LOGIN PASSWORD :'password';
+ALTER ROLE :"user" SET default_transaction_read_only = on;
+SELECT format('GRANT CONNECT ON DATABASE %I TO %I', current_database(), :'user')
+\gexec
+SELECT format('GRANT USAGE ON SCHEMA %I TO %I', nspname, :'user') FROM pg_namespace WHERE nspname NOT LIKE 'pg\_%' AND nspname <> 'information_schema'
+\gexec
+SELECT format('GRANT SELECT ON ALL TABLES IN SCHEMA %I TO %I', nspname, :'user') FROM pg_namespace WHERE nspname NOT LIKE 'pg\_%' AND nspname <> 'information_schema'
+\gexec
+SELECT format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I GRANT SELECT ON TABLES TO %I', nspname, :'user') FROM pg_namespace WHERE nspname NOT LIKE 'pg\_%' AND nspname <> 'information_schema'
+\gexec
+`
+
+// isReadOnlyConnection returns true if the Connection binds a read-only user
+func isReadOnlyConnection(connection *rdsdbaasv1alpha1.RDSConnection) (bool, error) {
+	value, ok := connection.Annotations[connectionReadOnlyAnnotation]
+	if !ok || len(value) == 0 {
+		return false, nil
+	}
+	readOnly, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("value %s of annotation %s is invalid", value, connectionReadOnlyAnnotation)
+	}
+	return readOnly, nil
+}
+
+// getReadOnlyUsername returns the name of the read-only user of the Connection, it fits the 16 characters of the
+// user names of MySQL 5.7
+func getReadOnlyUsername(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	sum := sha256.Sum256([]byte(connection.Namespace + "/" + connection.Name))
+	return readOnlyUsernamePrefix + hex.EncodeToString(sum[:])[:12]
+}
+
+// getReadOnlyUserObjectName returns the name of the Secret and the Job creating the read-only user of the Connection,
+// it is unique across the namespaces of the Connections as they run in the namespace of the Jobs
+func getReadOnlyUserObjectName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-read-only-user", strings.ReplaceAll(getReadOnlyUsername(connection), "_", "-"))
+}
+
+// getJobNamespace returns the namespace of the Jobs run with the master credentials of the DB services of the
+// Inventory, the users of the Connections cannot read their Secrets there
+func (r *RDSConnectionReconciler) getJobNamespace(inventory *rdsdbaasv1alpha1.RDSInventory) string {
+	if len(r.JobNamespace) > 0 {
+		return r.JobNamespace
+	}
+	return inventory.Namespace
+}
+
+// getMySQLGrantScope returns the scope of the grant of the read-only user in MySQL, the database of the DB service
+// is granted as granting all the databases exposes the system tables
+func getMySQLGrantScope(dbName string) string {
+	return fmt.Sprintf("`%s`.*", strings.ReplaceAll(dbName, "`", "``"))
+}
+
+// validateReadOnlyConnection returns an error if the read-only user cannot be created for the engine
+func validateReadOnlyConnection(engine string, dbName *string) error {
+	switch generateBindingType(engine) {
+	case "postgresql":
+		return nil
+	case "mysql":
+		if dbName == nil || len(*dbName) == 0 {
+			return fmt.Errorf("read-only Connection requires the database name of the DB service")
+		}
+		return nil
+	default:
+		return fmt.Errorf("read-only Connection not supported by engine %s", engine)
+	}
+}
+
+// syncReadOnlyUser creates the read-only user of the Connection with a Job and returns its credentials once the Job
+// completes, the password is generated once and kept in the Secret of the Job. The Job and its Secret run in the
+// namespace of the Jobs, and the master credentials are removed from the Secret once the Job completes, so they never
+// reach the namespace of the Connection. The engine must be validated first.
+func (r *RDSConnectionReconciler) syncReadOnlyUser(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	jobNamespace, engine string, masterUsername *string, masterPassword []byte, host *string, port *int64, dbName *string) (*string, []byte, bool, error) {
+	logger := log.FromContext(ctx)
+
+	if err := r.addConnectionJobsFinalizer(ctx, connection); err != nil {
+		return nil, nil, false, err
+	}
+
+	job := &batchv1.Job{}
+	jobFound := true
+	if err := r.Get(ctx, client.ObjectKey{Namespace: jobNamespace, Name: getReadOnlyUserObjectName(connection)}, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, nil, false, err
+		}
+		jobFound = false
+	}
+	created, jobErr := getConnectionJobResult(job)
+	if jobFound && !created && jobErr == nil {
+		// the Job is running
+		return nil, nil, false, nil
+	}
+
+	username := getReadOnlyUsername(connection)
+	secret, err := r.applyConnectionJobSecret(ctx, connection, jobNamespace, getReadOnlyUserObjectName(connection),
+		func(existing *v1.Secret) map[string][]byte {
+			password := []byte(generateAlphanumericPassword())
+			if existing != nil {
+				if p := existing.Data["password"]; len(p) > 0 {
+					password = p
+				}
+			}
+			data := map[string][]byte{
+				"username": []byte(username),
+				"password": password,
+			}
+			if !created {
+				data[connectionJobKeyMasterUsername] = []byte(*masterUsername)
+				data[connectionJobKeyMasterPassword] = masterPassword
+			}
+			return data
+		})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if jobErr != nil {
+		return nil, nil, false, jobErr
+	}
+	if created {
+		return &username, secret.Data["password"], true, nil
+	}
+
+	job = r.newReadOnlyUserJob(connection, jobNamespace, generateBindingType(engine), host, port, dbName)
+	if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, nil, false, err
+	}
+	logger.Info("Read-only user Job created", "Job", job.Name, "Namespace", jobNamespace)
+	return nil, nil, false, nil
+}
+
+// applyConnectionJobSecret applies the Secret of a Job of the Connection run in the namespace of the Jobs with the
+// data returned from the existing Secret, the keys no longer returned are removed from the Secret
+func (r *RDSConnectionReconciler) applyConnectionJobSecret(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	namespace, name string, getData func(existing *v1.Secret) map[string][]byte) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if _, err := createOrApply(ctx, r.Client, secret, func(existing client.Object) error {
+		var existingSecret *v1.Secret
+		if existing != nil {
+			existingSecret = existing.(*v1.Secret)
+		}
+		secret.Labels = buildConnectionLabels()
+		secret.Labels[connectionJobLabel] = "true"
+		secret.Annotations = buildConnectionAnnotations(connection)
+		secret.Data = getData(existingSecret)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// addConnectionJobsFinalizer adds the finalizer of the Jobs to the Connection before its first Job, so that no Job nor
+// Secret outlives the Connection
+func (r *RDSConnectionReconciler) addConnectionJobsFinalizer(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	if controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(connection.DeepCopy())
+	controllerutil.AddFinalizer(connection, connectionJobsFinalizer)
+	return r.Patch(ctx, connection, patch)
+}
+
+// isConnectionJobOf returns whether the Job or the Secret of a Job was run for the Connection
+func isConnectionJobOf(object client.Object, namespace, name string) bool {
+	return object.GetLabels()[connectionJobLabel] == "true" && object.GetAnnotations()["owner.kind"] == connectionKind &&
+		object.GetAnnotations()["owner.namespace"] == namespace && object.GetAnnotations()["owner"] == name
+}
+
+// deleteConnectionJobs deletes the Jobs run with the master credentials for the Connection and their Secrets
+func (r *RDSConnectionReconciler) deleteConnectionJobs(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.MatchingLabels{connectionJobLabel: "true"}); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !isConnectionJobOf(job, connection.Namespace, connection.Name) {
+			continue
+		}
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	secrets := &v1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingLabels{connectionJobLabel: "true"}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !isConnectionJobOf(secret, connection.Namespace, connection.Name) {
+			continue
+		}
+		if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalizeConnectionJobs deletes the Jobs of the deleted Connection and their Secrets, and removes its finalizer
+func (r *RDSConnectionReconciler) finalizeConnectionJobs(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	if !controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer) {
+		return nil
+	}
+	if err := r.deleteConnectionJobs(ctx, connection); err != nil {
+		return err
+	}
+	return updateObject(ctx, r.Client, connection, func() error {
+		controllerutil.RemoveFinalizer(connection, connectionJobsFinalizer)
+		return nil
+	})
+}
+
+// getJobConnectionRequests returns the Connection the Job was run for in the namespace of the Jobs
+func getJobConnectionRequests(object client.Object) []reconcile.Request {
+	if object.GetLabels()[connectionJobLabel] != "true" || object.GetAnnotations()["owner.kind"] != connectionKind {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: object.GetAnnotations()["owner.namespace"],
+				Name:      object.GetAnnotations()["owner"],
+			},
+		},
+	}
+}
+
+// generateAlphanumericPassword returns a password without special characters, as it is quoted in the SQL statements
+func generateAlphanumericPassword() string {
+	policy := defaultPasswordPolicy
+	policy.specials = ""
+	return generatePasswordWithPolicy(policy)
+}
+
+func (r *RDSConnectionReconciler) newReadOnlyUserJob(connection *rdsdbaasv1alpha1.RDSConnection, namespace, bindingType string,
+	host *string, port *int64, dbName *string) *batchv1.Job {
+	secretName := getReadOnlyUserObjectName(connection)
+	fromSecret := func(name, key string) v1.EnvVar {
+		return secretEnvVar(name, secretName, key)
+	}
+
+	container := r.newConnectionJobContainer("read-only-user", bindingType)
+	var dbPort string
+	if port != nil {
+		dbPort = strconv.FormatInt(*port, 10)
+	}
+	switch bindingType {
+	case "postgresql":
+		container.Command = []string{"/bin/sh", "-c",
+			`printf '%s\n' "$READ_ONLY_SQL" | psql --no-psqlrc -v ON_ERROR_STOP=1 -v user="$READ_ONLY_USER" -v password="$READ_ONLY_PASSWORD" --file=-`}
+		database := pointer.StringDeref(dbName, "")
+		if len(database) == 0 {
+			database = *getDefaultDBName(postgres)
+		}
+		container.Env = []v1.EnvVar{
+			{Name: "PGHOST", Value: pointer.StringDeref(host, "")},
+			{Name: "PGPORT", Value: dbPort},
+			{Name: "PGDATABASE", Value: database},
+			fromSecret("PGUSER", connectionJobKeyMasterUsername),
+			fromSecret("PGPASSWORD", connectionJobKeyMasterPassword),
+			fromSecret("READ_ONLY_USER", "username"),
+			fromSecret("READ_ONLY_PASSWORD", "password"),
+			{Name: "READ_ONLY_SQL", Value: postgresReadOnlyUserSQL},
+			{Name: "PGCONNECT_TIMEOUT", Value: "10"},
+		}
+	case "mysql":
+		container.Command = []string{"/bin/sh", "-c",
+			`mysql --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" --connect-timeout=10 --execute="` +
+				`CREATE USER IF NOT EXISTS '$READ_ONLY_USER'@'%' IDENTIFIED BY '$READ_ONLY_PASSWORD'; ` +
+				`ALTER USER '$READ_ONLY_USER'@'%' IDENTIFIED BY '$READ_ONLY_PASSWORD'; ` +
+				`GRANT SELECT, SHOW VIEW ON $READ_ONLY_SCOPE TO '$READ_ONLY_USER'@'%';"`}
+		container.Env = []v1.EnvVar{
+			{Name: "DB_HOST", Value: pointer.StringDeref(host, "")},
+			{Name: "DB_PORT", Value: dbPort},
+			fromSecret("DB_USER", connectionJobKeyMasterUsername),
+			fromSecret("MYSQL_PWD", connectionJobKeyMasterPassword),
+			fromSecret("READ_ONLY_USER", "username"),
+			fromSecret("READ_ONLY_PASSWORD", "password"),
+			{Name: "READ_ONLY_SCOPE", Value: getMySQLGrantScope(*dbName)},
+		}
+	}
+
+	job := newConnectionJob(connection, getReadOnlyUserObjectName(connection), readOnlyUserActiveDeadlineSeconds, container)
+	job.Namespace = namespace
+	job.Labels = buildConnectionLabels()
+	job.Labels[connectionJobLabel] = "true"
+	job.Annotations = buildConnectionAnnotations(connection)
+	return job
+}
+
+// newConnectionJob returns a Job of the Connection running the container against its database
+func newConnectionJob(connection *rdsdbaasv1alpha1.RDSConnection, name string, activeDeadlineSeconds int64, container v1.Container) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: connection.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          pointer.Int32(2),
+			ActiveDeadlineSeconds: pointer.Int64(activeDeadlineSeconds),
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy:                v1.RestartPolicyNever,
+					AutomountServiceAccountToken: pointer.Bool(false),
+					SecurityContext: &v1.PodSecurityContext{
+						SeccompProfile: &v1.SeccompProfile{
+							Type: v1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []v1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// getConnectionJobResult returns true once the Job completed, and an error if it failed
+func getConnectionJobResult(job *batchv1.Job) (bool, error) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("job %s failed: %s", job.Name, c.Message)
+		}
+	}
+	return false, nil
+}
+
+// newConnectionJobContainer returns the container of a Job of the Connection with the client of the engine
+func (r *RDSConnectionReconciler) newConnectionJobContainer(name, bindingType string) v1.Container {
+	container := v1.Container{
+		Name: name,
+		SecurityContext: &v1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsNonRoot:             pointer.Bool(true),
+			Capabilities: &v1.Capabilities{
+				Drop: []v1.Capability{"ALL"},
+			},
+		},
+	}
+	switch bindingType {
+	case "postgresql":
+		container.Image = r.ConnectionTestPostgreSQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestPostgreSQLImage
+		}
+	case "mysql":
+		container.Image = r.ConnectionTestMySQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestMySQLImage
+		}
+	}
+	return container
+}
+
+// secretEnvVar returns the environment variable of the container read from the key of the Secret
+func secretEnvVar(name, secretName, key string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
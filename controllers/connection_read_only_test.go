@@ -0,0 +1,144 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionReadOnly", func() {
+	connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Name: "connection", Namespace: "app"}}
+
+	It("should parse the read-only annotation of the Connection", func() {
+		c := connection.DeepCopy()
+		Expect(isReadOnlyConnection(c)).Should(BeFalse())
+		c.Annotations = map[string]string{connectionReadOnlyAnnotation: "true"}
+		Expect(isReadOnlyConnection(c)).Should(BeTrue())
+		c.Annotations[connectionReadOnlyAnnotation] = "yes"
+		_, err := isReadOnlyConnection(c)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should name the read-only user after the Connection", func() {
+		username := getReadOnlyUsername(connection)
+		Expect(username).Should(HavePrefix("ro_"))
+		Expect(len(username)).Should(BeNumerically("<=", 16))
+		Expect(getReadOnlyUsername(connection)).Should(Equal(username))
+		other := connection.DeepCopy()
+		other.Namespace = "other"
+		Expect(getReadOnlyUsername(other)).ShouldNot(Equal(username))
+	})
+
+	It("should validate the engine of the read-only Connection", func() {
+		Expect(validateReadOnlyConnection("postgres", nil)).Should(Succeed())
+		Expect(validateReadOnlyConnection("mysql", pointer.String("app"))).Should(Succeed())
+		Expect(validateReadOnlyConnection("mysql", nil)).ShouldNot(Succeed())
+		Expect(validateReadOnlyConnection("oracle-ee", nil)).ShouldNot(Succeed())
+	})
+
+	It("should quote the database of the MySQL grant", func() {
+		Expect(getMySQLGrantScope("app")).Should(Equal("`app`.*"))
+		Expect(getMySQLGrantScope("a`b")).Should(Equal("`a``b`.*"))
+	})
+
+	It("should read the credentials of the read-only user Job from its Secret", func() {
+		r := &RDSConnectionReconciler{}
+		job := r.newReadOnlyUserJob(connection, "operator", "postgresql", pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(job.Name).Should(Equal(getReadOnlyUserObjectName(connection)))
+		Expect(job.Namespace).Should(Equal("operator"))
+		Expect(job.OwnerReferences).Should(BeEmpty())
+		Expect(getJobConnectionRequests(job)).Should(HaveLen(1))
+		Expect(getJobConnectionRequests(job)[0].NamespacedName).Should(Equal(client.ObjectKeyFromObject(connection)))
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).Should(Equal(DefaultConnectionTestPostgreSQLImage))
+		for _, env := range container.Env {
+			switch env.Name {
+			case "PGPASSWORD", "READ_ONLY_PASSWORD", "PGUSER", "READ_ONLY_USER":
+				Expect(env.ValueFrom.SecretKeyRef.Name).Should(Equal(getReadOnlyUserObjectName(connection)))
+			case "PGDATABASE":
+				Expect(env.Value).Should(Equal("postgres"))
+			}
+		}
+	})
+
+	It("should never write the master credentials to the namespace of the Connection", func() {
+		ctx := context.Background()
+		c := connection.DeepCopy()
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(c).Build()}
+		r := &RDSConnectionReconciler{Client: cli, Scheme: scheme.Scheme, JobNamespace: "operator"}
+		expectNoMasterPassword := func() {
+			secrets := &v1.SecretList{}
+			Expect(r.List(ctx, secrets, client.InNamespace(c.Namespace))).Should(Succeed())
+			for _, secret := range secrets.Items {
+				for _, value := range secret.Data {
+					Expect(string(value)).ShouldNot(Equal("master-password"))
+				}
+			}
+		}
+
+		_, _, created, err := r.syncReadOnlyUser(ctx, c, "operator", "postgres", pointer.String("admin"),
+			[]byte("master-password"), pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(created).Should(BeFalse())
+		Expect(controllerutil.ContainsFinalizer(c, connectionJobsFinalizer)).Should(BeTrue())
+		expectNoMasterPassword()
+
+		job := &batchv1.Job{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "operator", Name: getReadOnlyUserObjectName(c)}, job)).Should(Succeed())
+		secret := &v1.Secret{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "operator", Name: getReadOnlyUserObjectName(c)}, secret)).Should(Succeed())
+		Expect(secret.Data).Should(HaveKeyWithValue(connectionJobKeyMasterPassword, []byte("master-password")))
+		password := secret.Data["password"]
+
+		// the master credentials are removed from the Secret of the Job once it completes
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+		Expect(r.Status().Update(ctx, job)).Should(Succeed())
+		username, p, created, err := r.syncReadOnlyUser(ctx, c, "operator", "postgres", pointer.String("admin"),
+			[]byte("master-password"), pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(created).Should(BeTrue())
+		Expect(*username).Should(Equal(getReadOnlyUsername(c)))
+		Expect(p).Should(Equal(password))
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(secret), secret)).Should(Succeed())
+		Expect(secret.Data).ShouldNot(HaveKey(connectionJobKeyMasterUsername))
+		Expect(secret.Data).ShouldNot(HaveKey(connectionJobKeyMasterPassword))
+		expectNoMasterPassword()
+
+		// the Job and its Secret do not outlive the Connection
+		Expect(r.deleteConnectionJobs(ctx, c)).Should(Succeed())
+		jobs := &batchv1.JobList{}
+		Expect(r.List(ctx, jobs, client.InNamespace("operator"))).Should(Succeed())
+		Expect(jobs.Items).Should(BeEmpty())
+		secrets := &v1.SecretList{}
+		Expect(r.List(ctx, secrets, client.InNamespace("operator"))).Should(Succeed())
+		Expect(secrets.Items).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,270 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the comma-separated namespaces where a copy of the credentials Secret of the Connection is maintained
+	connectionReplicateToAnnotation = "rds.dbaas.redhat.com/credentials-replicate-to"
+	// the consent of a target namespace to the copies of the credentials Secrets, the comma-separated namespaces of the
+	// Connections allowed to replicate their credentials or * for all, set on the Namespace so that only the users
+	// allowed by RBAC to update the Namespace can consent
+	namespaceAcceptCredentialsFromAnnotation = "rds.dbaas.redhat.com/accept-credentials-from"
+
+	// the label of the copies of the credentials Secrets, which are owned by the Connection through its owner annotations
+	// as an owner reference cannot cross namespaces
+	credentialsReplicaLabel = "rds.dbaas.redhat.com/credentials-replica"
+
+	// the finalizer of the Connections with copies of their credentials Secret, which are deleted with the Connection
+	connectionReplicasFinalizer = "rds.dbaas.redhat.com/credentials-replicas"
+
+	credentialsReplicaNamespacesKey = ".metadata.annotations.credentialsReplicateTo"
+
+	connectionConditionCredentialsReplicated = "CredentialsReplicated"
+
+	connectionStatusReasonReplicated      = "Replicated"
+	connectionStatusReasonConsentMissing  = "ConsentMissing"
+	connectionStatusReasonReplicaConflict = "Conflict"
+
+	connectionStatusMessageReplicated      = "Credentials replicated to namespaces %s"
+	connectionStatusMessageConsentMissing  = "Namespaces %s do not accept the credentials of namespace %s"
+	connectionStatusMessageReplicaConflict = "Secret %s exists in namespaces %s and is not a copy of the credentials"
+)
+
+// getCredentialsReplicaNamespaces returns the sorted namespaces where the credentials of the Connection are replicated
+func getCredentialsReplicaNamespaces(connection *rdsdbaasv1alpha1.RDSConnection) ([]string, error) {
+	value, ok := connection.Annotations[connectionReplicateToAnnotation]
+	if !ok || len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	set := map[string]struct{}{}
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if len(validation.IsDNS1123Label(ns)) > 0 || ns == connection.Namespace {
+			return nil, fmt.Errorf("value %s of annotation %s is invalid", value, connectionReplicateToAnnotation)
+		}
+		set[ns] = struct{}{}
+	}
+	namespaces := make([]string, 0, len(set))
+	for ns := range set {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// acceptsCredentialsFrom returns whether the consent annotation of the target namespace accepts the credentials of the
+// Connections of the namespace
+func acceptsCredentialsFrom(target *v1.Namespace, namespace string) bool {
+	for _, ns := range strings.Split(target.Annotations[namespaceAcceptCredentialsFromAnnotation], ",") {
+		if ns = strings.TrimSpace(ns); ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// isCredentialsReplicaOf returns whether the Secret is a copy of the credentials of the Connection
+func isCredentialsReplicaOf(secret *v1.Secret, namespace, name string) bool {
+	return secret.Labels[credentialsReplicaLabel] == "true" && secret.Annotations["owner.kind"] == connectionKind &&
+		secret.Annotations["owner.namespace"] == namespace && secret.Annotations["owner"] == name
+}
+
+// syncCredentialsReplicas maintains the copies of the credentials Secret of the Connection in the target namespaces
+// consenting to them, and deletes the copies in the namespaces no longer targeted or consenting. The result is set in
+// the CredentialsReplicated condition of the Connection, which is removed if the Connection has no target namespaces.
+func (r *RDSConnectionReconciler) syncCredentialsReplicas(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	secret *v1.Secret) error {
+	namespaces, err := getCredentialsReplicaNamespaces(connection)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		if err := r.deleteCredentialsReplicas(ctx, connection.Namespace, connection.Name, nil); err != nil {
+			return err
+		}
+		apimeta.RemoveStatusCondition(&connection.Status.Conditions, connectionConditionCredentialsReplicated)
+		if controllerutil.ContainsFinalizer(connection, connectionReplicasFinalizer) {
+			patch := client.MergeFrom(connection.DeepCopy())
+			controllerutil.RemoveFinalizer(connection, connectionReplicasFinalizer)
+			return r.Patch(ctx, connection, patch)
+		}
+		return nil
+	}
+
+	// the finalizer is added before the first copy so that no copy outlives the Connection
+	if !controllerutil.ContainsFinalizer(connection, connectionReplicasFinalizer) {
+		patch := client.MergeFrom(connection.DeepCopy())
+		controllerutil.AddFinalizer(connection, connectionReplicasFinalizer)
+		if err := r.Patch(ctx, connection, patch); err != nil {
+			return err
+		}
+	}
+
+	var replicated, notConsenting, conflicting []string
+	for _, ns := range namespaces {
+		target := &v1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: ns}, target); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			notConsenting = append(notConsenting, ns)
+			continue
+		}
+		if !target.DeletionTimestamp.IsZero() || !acceptsCredentialsFrom(target, connection.Namespace) ||
+			!r.NamespacePolicy.isAllowed(ns) {
+			notConsenting = append(notConsenting, ns)
+			continue
+		}
+
+		replica := &v1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ns, Name: secret.Name}, replica); err == nil {
+			if !isCredentialsReplicaOf(replica, connection.Namespace, connection.Name) {
+				conflicting = append(conflicting, ns)
+				continue
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+		replica = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: ns,
+			},
+		}
+		if _, err := createOrApply(ctx, r.Client, replica, func(client.Object) error {
+			replica.Labels = buildConnectionLabels()
+			replica.Labels[credentialsReplicaLabel] = "true"
+			replica.Annotations = buildConnectionAnnotations(connection)
+			if checksum, ok := connection.Annotations[connectionCredentialsChecksumAnnotation]; ok {
+				replica.Annotations[connectionCredentialsChecksumAnnotation] = checksum
+			}
+			replica.Type = secret.Type
+			replica.Data = secret.Data
+			return nil
+		}); err != nil {
+			return err
+		}
+		replicated = append(replicated, ns)
+	}
+
+	if err := r.deleteCredentialsReplicas(ctx, connection.Namespace, connection.Name, replicated); err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:    connectionConditionCredentialsReplicated,
+		Status:  metav1.ConditionTrue,
+		Reason:  connectionStatusReasonReplicated,
+		Message: fmt.Sprintf(connectionStatusMessageReplicated, strings.Join(replicated, ",")),
+	}
+	if len(notConsenting) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = connectionStatusReasonConsentMissing
+		condition.Message = fmt.Sprintf(connectionStatusMessageConsentMissing, strings.Join(notConsenting, ","), connection.Namespace)
+	} else if len(conflicting) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = connectionStatusReasonReplicaConflict
+		condition.Message = fmt.Sprintf(connectionStatusMessageReplicaConflict, secret.Name, strings.Join(conflicting, ","))
+	}
+	apimeta.SetStatusCondition(&connection.Status.Conditions, condition)
+	return nil
+}
+
+// deleteCredentialsReplicas deletes the copies of the credentials Secret of the Connection outside the kept namespaces
+func (r *RDSConnectionReconciler) deleteCredentialsReplicas(ctx context.Context, namespace, name string, keep []string) error {
+	secrets := &v1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingLabels{credentialsReplicaLabel: "true"}); err != nil {
+		return err
+	}
+	kept := map[string]struct{}{}
+	for _, ns := range keep {
+		kept[ns] = struct{}{}
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, ok := kept[secret.Namespace]; ok || !isCredentialsReplicaOf(secret, namespace, name) {
+			continue
+		}
+		if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalizeCredentialsReplicas deletes the copies of the credentials Secret of the deleted Connection and removes its
+// finalizer
+func (r *RDSConnectionReconciler) finalizeCredentialsReplicas(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	if !controllerutil.ContainsFinalizer(connection, connectionReplicasFinalizer) {
+		return nil
+	}
+	if err := r.deleteCredentialsReplicas(ctx, connection.Namespace, connection.Name, nil); err != nil {
+		return err
+	}
+	return updateObject(ctx, r.Client, connection, func() error {
+		controllerutil.RemoveFinalizer(connection, connectionReplicasFinalizer)
+		return nil
+	})
+}
+
+// getNamespaceConnectionRequests returns the Connections replicating their credentials to the namespace, which are
+// reconciled again when the consent of the namespace changes
+func getNamespaceConnectionRequests(object client.Object, mgr ctrl.Manager) []reconcile.Request {
+	ctx := context.Background()
+	connectionList := &rdsdbaasv1alpha1.RDSConnectionList{}
+	if e := mgr.GetClient().List(ctx, connectionList, client.MatchingFields{credentialsReplicaNamespacesKey: object.GetName()}); e != nil {
+		log.FromContext(ctx).Error(e, "Failed to get Connections for Namespace update", "Namespace", object.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, c := range connectionList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: c.Namespace,
+				Name:      c.Name,
+			},
+		})
+	}
+	return requests
+}
+
+// indexCredentialsReplicaNamespaces indexes the Connections by the namespaces where their credentials are replicated
+func indexCredentialsReplicaNamespaces(rawObj client.Object) []string {
+	namespaces, _ := getCredentialsReplicaNamespaces(rawObj.(*rdsdbaasv1alpha1.RDSConnection))
+	return namespaces
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Connection credentials replication", func() {
+	It("should parse the namespaces where the credentials are replicated", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}}
+		Expect(getCredentialsReplicaNamespaces(connection)).Should(BeEmpty())
+
+		connection.Annotations = map[string]string{connectionReplicateToAnnotation: "team-c, team-b,team-c"}
+		Expect(getCredentialsReplicaNamespaces(connection)).Should(Equal([]string{"team-b", "team-c"}))
+		Expect(indexCredentialsReplicaNamespaces(connection)).Should(Equal([]string{"team-b", "team-c"}))
+
+		connection.Annotations[connectionReplicateToAnnotation] = "team-b,team-a"
+		_, err := getCredentialsReplicaNamespaces(connection)
+		Expect(err).Should(MatchError("value team-b,team-a of annotation rds.dbaas.redhat.com/credentials-replicate-to is invalid"))
+		connection.Annotations[connectionReplicateToAnnotation] = "Team_B"
+		_, err = getCredentialsReplicaNamespaces(connection)
+		Expect(err).Should(HaveOccurred())
+		Expect(indexCredentialsReplicaNamespaces(connection)).Should(BeEmpty())
+	})
+
+	It("should only replicate the credentials to the consenting namespaces", func() {
+		target := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}
+		Expect(acceptsCredentialsFrom(target, "team-a")).Should(BeFalse())
+		target.Annotations = map[string]string{namespaceAcceptCredentialsFromAnnotation: "team-c, team-a"}
+		Expect(acceptsCredentialsFrom(target, "team-a")).Should(BeTrue())
+		Expect(acceptsCredentialsFrom(target, "team-d")).Should(BeFalse())
+		target.Annotations[namespaceAcceptCredentialsFromAnnotation] = "*"
+		Expect(acceptsCredentialsFrom(target, "team-d")).Should(BeTrue())
+	})
+
+	It("should recognize the copies of the credentials of the Connection", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "db"}}
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "team-b",
+			Name:        "db-credentials",
+			Labels:      map[string]string{credentialsReplicaLabel: "true"},
+			Annotations: buildConnectionAnnotations(connection),
+		}}
+		Expect(isCredentialsReplicaOf(secret, "team-a", "db")).Should(BeTrue())
+		Expect(isCredentialsReplicaOf(secret, "team-c", "db")).Should(BeFalse())
+		Expect(isCredentialsReplicaOf(secret, "team-a", "other")).Should(BeFalse())
+		secret.Labels = nil
+		Expect(isCredentialsReplicaOf(secret, "team-a", "db")).Should(BeFalse())
+	})
+	Context("when the Connection is deleted with its namespace", func() {
+		reconcile := func(policy string) client.Client {
+			now := metav1.Now()
+			namespace := &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", DeletionTimestamp: &now, Finalizers: []string{"kubernetes"}},
+				Status:     v1.NamespaceStatus{Phase: v1.NamespaceTerminating},
+			}
+			connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "team-a",
+				Name:              "db",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{connectionReplicasFinalizer, connectionJobsFinalizer},
+			}}
+			if policy != "" {
+				connection.Annotations = map[string]string{namespaceDeletionPolicyAnnotation: policy}
+			}
+			replica := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "team-b",
+				Name:        "db-credentials",
+				Labels:      map[string]string{credentialsReplicaLabel: "true"},
+				Annotations: buildConnectionAnnotations(connection),
+			}}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "team-a",
+				Name:        "db-read-only-user",
+				Labels:      map[string]string{connectionJobLabel: "true"},
+				Annotations: buildConnectionAnnotations(connection),
+			}}
+			r := &RDSConnectionReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(namespace, connection, replica, job).Build(),
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(connection)})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result).Should(Equal(ctrl.Result{}))
+			err = r.Get(context.Background(), client.ObjectKeyFromObject(connection), connection)
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+			err = r.Get(context.Background(), client.ObjectKeyFromObject(job), job)
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+			return r.Client
+		}
+
+		It("should delete the copies of the credentials by default", func() {
+			c := reconcile("")
+			err := c.Get(context.Background(), client.ObjectKey{Namespace: "team-b", Name: "db-credentials"}, &v1.Secret{})
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+		})
+
+		It("should delete the copies of the credentials with the delete policy", func() {
+			c := reconcile(namespaceDeletionPolicyDelete)
+			err := c.Get(context.Background(), client.ObjectKey{Namespace: "team-b", Name: "db-credentials"}, &v1.Secret{})
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+		})
+
+		It("should keep the copies of the credentials with the retain policy", func() {
+			c := reconcile(namespaceDeletionPolicyRetain)
+			Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "team-b", Name: "db-credentials"}, &v1.Secret{})).Should(Succeed())
+		})
+	})
+})
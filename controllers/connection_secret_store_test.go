@@ -0,0 +1,149 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionSecretStore", func() {
+	ctx := context.Background()
+
+	getExternalObject := func(r *RDSConnectionReconciler, version, kind, name string) (*unstructured.Unstructured, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(version)
+		obj.SetKind(kind)
+		return obj, r.Get(ctx, client.ObjectKey{Namespace: "app", Name: name}, obj)
+	}
+	nestedString := func(obj *unstructured.Unstructured, fields ...string) string {
+		value, _, _ := unstructured.NestedString(obj.Object, fields...)
+		return value
+	}
+
+	It("should push the credentials to the secret store before referencing them", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "connection",
+				Namespace: "app",
+				UID:       "uid",
+				Annotations: map[string]string{
+					connectionSecretStoreAnnotation:     "vault",
+					connectionSecretStoreKindAnnotation: "ClusterSecretStore",
+					connectionRemoteKeyAnnotation:       "app/db",
+				},
+			},
+			Spec: dbaasv1beta1.DBaaSConnectionSpec{DatabaseServiceID: "db"},
+		}
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(connection).Build()}
+		r := &RDSConnectionReconciler{Client: cli, Scheme: scheme.Scheme}
+
+		name, pushed, err := r.createOrUpdateExternalSecret(ctx, connection, "vault", pointer.String("admin"), []byte("password"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pushed).Should(BeFalse())
+		Expect(name).Should(BeEmpty())
+
+		source := &v1.Secret{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "app", Name: "connection-credentials-source"}, source)).Should(Succeed())
+		Expect(source.Data).Should(HaveKeyWithValue("username", []byte("admin")))
+		Expect(source.Data).Should(HaveKeyWithValue("password", []byte("password")))
+		Expect(metav1.IsControlledBy(source, connection)).Should(BeTrue())
+
+		pushSecret, err := getExternalObject(r, pushSecretVersion, pushSecretKind, "connection-credentials-source")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pushSecret.Object["spec"]).Should(HaveKeyWithValue("secretStoreRefs",
+			ConsistOf(HaveKeyWithValue("name", "vault"))))
+		Expect(nestedString(pushSecret, "spec", "selector", "secret", "name")).Should(Equal(source.Name))
+		Expect(nestedString(pushSecret, "spec", "deletionPolicy")).Should(Equal("Delete"))
+		data, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "data")
+		Expect(data).Should(HaveLen(2))
+		for _, d := range data {
+			match := d.(map[string]interface{})["match"].(map[string]interface{})
+			Expect(match["remoteRef"]).Should(HaveKeyWithValue("remoteKey", "app/db"))
+			Expect(match["remoteRef"]).Should(HaveKeyWithValue("property", match["secretKey"]))
+		}
+
+		// the ExternalSecret is not created before the credentials are in the store
+		_, err = getExternalObject(r, externalSecretVersion, externalSecretKind, "connection-credentials")
+		Expect(errors.IsNotFound(err)).Should(BeTrue())
+
+		Expect(unstructured.SetNestedSlice(pushSecret.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}, "status", "conditions")).Should(Succeed())
+		Expect(r.Update(ctx, pushSecret)).Should(Succeed())
+
+		name, pushed, err = r.createOrUpdateExternalSecret(ctx, connection, "vault", pointer.String("admin"), []byte("password"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pushed).Should(BeTrue())
+		Expect(name).Should(Equal("connection-credentials"))
+
+		externalSecret, err := getExternalObject(r, externalSecretVersion, externalSecretKind, name)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nestedString(externalSecret, "spec", "secretStoreRef", "kind")).Should(Equal("ClusterSecretStore"))
+		data, _, _ = unstructured.NestedSlice(externalSecret.Object, "spec", "data")
+		Expect(data).Should(HaveLen(2))
+		for _, d := range data {
+			ref := d.(map[string]interface{})
+			Expect(ref["remoteRef"]).Should(HaveKeyWithValue("key", "app/db"))
+			Expect(ref["remoteRef"]).Should(HaveKeyWithValue("property", ref["secretKey"]))
+		}
+
+		Expect(r.revokeConnection(ctx, connection)).Should(Succeed())
+		_, err = getExternalObject(r, pushSecretVersion, pushSecretKind, "connection-credentials-source")
+		Expect(errors.IsNotFound(err)).Should(BeTrue())
+		Expect(errors.IsNotFound(r.Get(ctx, client.ObjectKeyFromObject(source), &v1.Secret{}))).Should(BeTrue())
+	})
+
+	It("should not push a PushSecret without credentials", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Name: "connection", Namespace: "app"}}
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(connection).Build()}
+		r := &RDSConnectionReconciler{Client: cli, Scheme: scheme.Scheme}
+		_, pushed, err := r.createOrUpdateExternalSecret(ctx, connection, "vault", nil, nil)
+		Expect(err).Should(HaveOccurred())
+		Expect(pushed).Should(BeFalse())
+		_, err = getExternalObject(r, pushSecretVersion, pushSecretKind, "connection-credentials-source")
+		Expect(errors.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("should only consider a Ready PushSecret synced", func() {
+		pushSecret := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		Expect(isPushSecretSynced(pushSecret)).Should(BeFalse())
+		Expect(unstructured.SetNestedSlice(pushSecret.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "False"},
+		}, "status", "conditions")).Should(Succeed())
+		Expect(isPushSecretSynced(pushSecret)).Should(BeFalse())
+		Expect(unstructured.SetNestedSlice(pushSecret.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}, "status", "conditions")).Should(Succeed())
+		Expect(isPushSecretSynced(pushSecret)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,265 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the parameters of the parameter group that reject the connections without TLS
+	tlsParameterForceSSL               = "rds.force_ssl"
+	tlsParameterRequireSecureTransport = "require_secure_transport"
+
+	// the connection info keys of the TLS mode of the clients, by binding type
+	connectionInfoSSLMode      = "sslmode"
+	connectionInfoMySQLSSLMode = "ssl-mode"
+	connectionInfoEncrypt      = "encrypt"
+
+	connectionInfoTrustServerCertificate = "trustServerCertificate"
+
+	tlsRequirementCacheTTL = 10 * time.Minute
+
+	// requires the TLS connections to all the DB services of the Inventory, the Connections to the DB services that
+	// accept connections without TLS are rejected
+	inventoryRequireTLSAnnotation = "rds.dbaas.redhat.com/require-tls"
+
+	connectionStatusReasonTLSNotEnforced   = "TLSNotEnforced"
+	connectionStatusMessageTLSNotEnforced  = "Database service accepts connections without TLS, the Inventory requires TLS"
+	connectionStatusMessageTLSNotConfirmed = "TLS enforcement of Database service cannot be confirmed, the Inventory requires TLS"
+	connectionStatusMessageTLSUnsupported  = "Database engine has no TLS enforcement parameter, the Inventory requires TLS"
+	connectionStatusMessageTLSError        = "Failed to get TLS requirement of Database service"
+)
+
+type tlsRequirement struct {
+	required bool
+	expires  time.Time
+}
+
+// getTLSParameterName returns the parameter enforcing TLS connections to the engine, if the engine has one
+func getTLSParameterName(engine string) string {
+	switch generateBindingType(engine) {
+	case "postgresql", "sqlserver":
+		return tlsParameterForceSSL
+	case "mysql":
+		return tlsParameterRequireSecureTransport
+	default:
+		return ""
+	}
+}
+
+// isTLSRequired looks up the TLS parameter of the engine in the parameter group of the DB service, nil is returned if
+// the requirement is not known. In spoke mode the requirement is read from the service info published by the hub, as
+// the spoke does not call AWS.
+func (r *RDSConnectionReconciler) isTLSRequired(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	dbService client.Object, serviceInfo map[string]string, engine string) (*bool, error) {
+	if r.Hub != nil {
+		return getServiceInfoTLSRequired(serviceInfo), nil
+	}
+	parameterName := getTLSParameterName(engine)
+	groupName, cluster := getParameterGroupName(dbService)
+	if len(parameterName) == 0 || len(groupName) == 0 ||
+		(cluster && r.GetDescribeDBClusterParametersAPI == nil) || (!cluster && r.GetDescribeDBParametersAPI == nil) {
+		return nil, nil
+	}
+
+	secret := &v1.Secret{}
+	if err := getInventoryCredentials(ctx, r.Client, inventory, secret); err != nil {
+		return nil, err
+	}
+	required, err := describeTLSRequired(ctx, &r.tlsRequirements, r.GetDescribeDBParametersAPI, r.GetDescribeDBClusterParametersAPI,
+		r.CircuitBreaker, inventory, parameterName, groupName, cluster,
+		string(secret.Data[awsAccessKeyID]), string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	if err != nil {
+		return nil, err
+	}
+	return &required, nil
+}
+
+// getParameterGroupName returns the name of the parameter group of the DB service, and whether it is a DB cluster
+// parameter group
+func getParameterGroupName(dbService client.Object) (string, bool) {
+	switch s := dbService.(type) {
+	case *rdsv1alpha1.DBInstance:
+		if len(s.Status.DBParameterGroups) > 0 && s.Status.DBParameterGroups[0] != nil &&
+			s.Status.DBParameterGroups[0].DBParameterGroupName != nil {
+			return *s.Status.DBParameterGroups[0].DBParameterGroupName, false
+		}
+	case *rdsv1alpha1.DBCluster:
+		if s.Status.DBClusterParameterGroup != nil {
+			return *s.Status.DBClusterParameterGroup, true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// describeTLSRequired returns whether the TLS parameter is enabled in the parameter group. The parameter groups are
+// cached, as the DB services of an Inventory share the same groups.
+func describeTLSRequired(ctx context.Context, requirements *sync.Map,
+	getDescribeDBParameters func(accessKey, secretKey, region string) controllersrds.DescribeDBParametersAPI,
+	getDescribeDBClusterParameters func(accessKey, secretKey, region string) controllersrds.DescribeDBClusterParametersAPI,
+	circuitBreaker *CircuitBreaker, inventory *rdsdbaasv1alpha1.RDSInventory, parameterName, groupName string, cluster bool,
+	accessKey, secretKey, region string) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%t/%s", region, accessKey, cluster, groupName)
+	if v, ok := requirements.Load(key); ok {
+		if requirement := v.(tlsRequirement); time.Now().Before(requirement.expires) {
+			return requirement.required, nil
+		}
+	}
+
+	var value string
+	var found bool
+	if cluster {
+		describeDBClusterParameters := getDescribeDBClusterParameters(accessKey, secretKey, region)
+		input := &rds.DescribeDBClusterParametersInput{DBClusterParameterGroupName: &groupName}
+		for {
+			output, err := describeDBClusterParameters.DescribeDBClusterParameters(ctx, input)
+			if err != nil {
+				circuitBreaker.recordFailure(inventory.Namespace, inventory.Name)
+				return false, err
+			}
+			if value, found = findParameterValue(output.Parameters, parameterName); found ||
+				output.Marker == nil || len(*output.Marker) == 0 {
+				break
+			}
+			input.Marker = output.Marker
+		}
+	} else {
+		describeDBParameters := getDescribeDBParameters(accessKey, secretKey, region)
+		input := &rds.DescribeDBParametersInput{DBParameterGroupName: &groupName}
+		for {
+			output, err := describeDBParameters.DescribeDBParameters(ctx, input)
+			if err != nil {
+				circuitBreaker.recordFailure(inventory.Namespace, inventory.Name)
+				return false, err
+			}
+			if value, found = findParameterValue(output.Parameters, parameterName); found ||
+				output.Marker == nil || len(*output.Marker) == 0 {
+				break
+			}
+			input.Marker = output.Marker
+		}
+	}
+	circuitBreaker.recordSuccess(inventory.Namespace, inventory.Name)
+
+	required := isParameterEnabled(value)
+	requirements.Store(key, tlsRequirement{required: required, expires: time.Now().Add(tlsRequirementCacheTTL)})
+	return required, nil
+}
+
+func findParameterValue(parameters []rdstypesv2.Parameter, name string) (string, bool) {
+	for _, p := range parameters {
+		if p.ParameterName != nil && *p.ParameterName == name {
+			if p.ParameterValue == nil {
+				return "", true
+			}
+			return *p.ParameterValue, true
+		}
+	}
+	return "", false
+}
+
+func isParameterEnabled(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "on", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTLSRequiredByInventory returns true if the Inventory requires the TLS connections to its DB services
+func isTLSRequiredByInventory(inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error) {
+	value, ok := inventory.Annotations[inventoryRequireTLSAnnotation]
+	if !ok || len(value) == 0 {
+		return false, nil
+	}
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("value %s of annotation %s is invalid", value, inventoryRequireTLSAnnotation)
+	}
+	return required, nil
+}
+
+// getTLSNotEnforcedMessage returns why the DB service may accept connections without TLS, empty if the DB service
+// rejects them. The engines without a TLS parameter, such as Oracle whose TLS is set up with an option group, and the DB
+// services whose parameter group is not known cannot be confirmed to enforce TLS.
+func getTLSNotEnforcedMessage(engine string, tlsRequired *bool) string {
+	switch {
+	case len(getTLSParameterName(engine)) == 0:
+		return connectionStatusMessageTLSUnsupported
+	case tlsRequired == nil:
+		return connectionStatusMessageTLSNotConfirmed
+	case !*tlsRequired:
+		return connectionStatusMessageTLSNotEnforced
+	default:
+		return ""
+	}
+}
+
+// setTLSConnectionInfo sets the TLS mode of the clients of the engine in the connection info, the clients require TLS
+// if the DB service rejects the connections without it, and prefer TLS otherwise. The clients verify the certificate
+// and the host name of the DB service if TLS is strict, the Connections are only bound with strict TLS to the DB
+// services enforcing TLS.
+func setTLSConnectionInfo(data map[string]string, engine string, tlsRequired *bool, strict bool) {
+	if strict {
+		switch generateBindingType(engine) {
+		case "postgresql":
+			data[connectionInfoSSLMode] = "verify-full"
+		case "mysql":
+			data[connectionInfoMySQLSSLMode] = "VERIFY_IDENTITY"
+		case "sqlserver":
+			data[connectionInfoEncrypt] = "true"
+			data[connectionInfoTrustServerCertificate] = "false"
+		}
+		return
+	}
+	if tlsRequired == nil {
+		return
+	}
+	switch generateBindingType(engine) {
+	case "postgresql":
+		if *tlsRequired {
+			data[connectionInfoSSLMode] = "require"
+		} else {
+			data[connectionInfoSSLMode] = "prefer"
+		}
+	case "mysql":
+		if *tlsRequired {
+			data[connectionInfoMySQLSSLMode] = "REQUIRED"
+		} else {
+			data[connectionInfoMySQLSSLMode] = "PREFERRED"
+		}
+	case "sqlserver":
+		data[connectionInfoEncrypt] = fmt.Sprintf("%t", *tlsRequired)
+	}
+}
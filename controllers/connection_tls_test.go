@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionTLS", func() {
+	It("should get the TLS parameter of the engine", func() {
+		Expect(getTLSParameterName("postgres")).Should(Equal(tlsParameterForceSSL))
+		Expect(getTLSParameterName("aurora-postgresql")).Should(Equal(tlsParameterForceSSL))
+		Expect(getTLSParameterName("sqlserver-ex")).Should(Equal(tlsParameterForceSSL))
+		Expect(getTLSParameterName("mysql")).Should(Equal(tlsParameterRequireSecureTransport))
+		Expect(getTLSParameterName("aurora-mysql")).Should(Equal(tlsParameterRequireSecureTransport))
+		Expect(getTLSParameterName("mariadb")).Should(Equal(tlsParameterRequireSecureTransport))
+		Expect(getTLSParameterName("oracle-ee")).Should(BeEmpty())
+	})
+
+	It("should parse the value of the TLS parameter", func() {
+		Expect(isParameterEnabled("1")).Should(BeTrue())
+		Expect(isParameterEnabled("ON")).Should(BeTrue())
+		Expect(isParameterEnabled("0")).Should(BeFalse())
+		Expect(isParameterEnabled("")).Should(BeFalse())
+	})
+
+	It("should set the TLS mode of the clients in the connection info", func() {
+		cm := &v1.ConfigMap{}
+		setConfigMap(cm, pointer.String("postgres"), nil, pointer.String("host"), pointer.Int64(5432), pointer.Bool(true), false)
+		Expect(cm.Data).Should(HaveKeyWithValue("sslmode", "require"))
+
+		setConfigMap(cm, pointer.String("mysql"), nil, pointer.String("host"), pointer.Int64(3306), pointer.Bool(false), false)
+		Expect(cm.Data).Should(HaveKeyWithValue("ssl-mode", "PREFERRED"))
+
+		setConfigMap(cm, pointer.String("sqlserver-ex"), nil, pointer.String("host"), pointer.Int64(1433), pointer.Bool(true), false)
+		Expect(cm.Data).Should(HaveKeyWithValue("encrypt", "true"))
+
+		setConfigMap(cm, pointer.String("postgres"), nil, pointer.String("host"), pointer.Int64(5432), nil, false)
+		Expect(cm.Data).ShouldNot(HaveKey("sslmode"))
+	})
+
+	It("should set strict TLS in the connection info if the Inventory requires TLS", func() {
+		cm := &v1.ConfigMap{}
+		setConfigMap(cm, pointer.String("postgres"), nil, pointer.String("host"), pointer.Int64(5432), nil, true)
+		Expect(cm.Data).Should(HaveKeyWithValue("sslmode", "verify-full"))
+
+		setConfigMap(cm, pointer.String("mysql"), nil, pointer.String("host"), pointer.Int64(3306), pointer.Bool(true), true)
+		Expect(cm.Data).Should(HaveKeyWithValue("ssl-mode", "VERIFY_IDENTITY"))
+
+		setConfigMap(cm, pointer.String("sqlserver-ex"), nil, pointer.String("host"), pointer.Int64(1433), pointer.Bool(true), true)
+		Expect(cm.Data).Should(HaveKeyWithValue("encrypt", "true"))
+		Expect(cm.Data).Should(HaveKeyWithValue("trustServerCertificate", "false"))
+	})
+
+	It("should parse the TLS requirement of the Inventory", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(isTLSRequiredByInventory(inventory)).Should(BeFalse())
+
+		inventory.Annotations = map[string]string{inventoryRequireTLSAnnotation: "true"}
+		Expect(isTLSRequiredByInventory(inventory)).Should(BeTrue())
+
+		inventory.Annotations[inventoryRequireTLSAnnotation] = "yes"
+		_, err := isTLSRequiredByInventory(inventory)
+		Expect(err).Should(HaveOccurred())
+	})
+	It("should refuse the DB services that cannot be confirmed to enforce TLS", func() {
+		Expect(getTLSNotEnforcedMessage("postgres", pointer.Bool(true))).Should(BeEmpty())
+		Expect(getTLSNotEnforcedMessage("aurora-mysql", pointer.Bool(true))).Should(BeEmpty())
+		Expect(getTLSNotEnforcedMessage("postgres", pointer.Bool(false))).Should(Equal(connectionStatusMessageTLSNotEnforced))
+		Expect(getTLSNotEnforcedMessage("mysql", nil)).Should(Equal(connectionStatusMessageTLSNotConfirmed))
+		Expect(getTLSNotEnforcedMessage("oracle-ee", pointer.Bool(true))).Should(Equal(connectionStatusMessageTLSUnsupported))
+		Expect(getTLSNotEnforcedMessage("", nil)).Should(Equal(connectionStatusMessageTLSUnsupported))
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the time to live of the Connection from its creation, for example 72h, the credentials of the Connection are
+	// revoked once it expires. Only the read-only Connections may expire, as their user is dropped from the database,
+	// the master credentials cannot be revoked.
+	connectionTTLAnnotation = "rds.dbaas.redhat.com/ttl"
+
+	dropReadOnlyUserActiveDeadlineSeconds = 300
+
+	connectionStatusReasonExpired      = "Expired"
+	connectionStatusMessageExpired     = "Connection expired at %s, its credentials are revoked"
+	connectionStatusMessageRevoking    = "Dropping read-only user of expired Connection"
+	connectionStatusMessageRevokeError = "Failed to drop read-only user of expired Connection"
+)
+
+// the statements of psql dropping the read-only user, its sessions are terminated and its privileges revoked first
+const postgresDropReadOnlyUserSQL = `SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = :'user';
+SELECT format('DROP OWNED BY %I', :'user') WHERE EXISTS (SELECT FROM pg_roles WHERE rolname = :'user')
+\gexec
+SELECT format('DROP ROLE %I', :'user') WHERE EXISTS (SELECT FROM pg_roles WHERE rolname = :'user')
+\gexec
+`
+
+// getConnectionExpiry returns the time the Connection expires, zero is returned if the Connection does not expire
+func getConnectionExpiry(connection *rdsdbaasv1alpha1.RDSConnection) (time.Time, error) {
+	value, ok := connection.Annotations[connectionTTLAnnotation]
+	if !ok || len(value) == 0 {
+		return time.Time{}, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return time.Time{}, fmt.Errorf("value %s of annotation %s is invalid", value, connectionTTLAnnotation)
+	}
+	if readOnly, _ := isReadOnlyConnection(connection); !readOnly {
+		return time.Time{}, fmt.Errorf("annotation %s requires annotation %s, the master credentials cannot be revoked",
+			connectionTTLAnnotation, connectionReadOnlyAnnotation)
+	}
+	return connection.CreationTimestamp.Add(ttl), nil
+}
+
+// isConnectionRevoked returns true if the credentials of the expired Connection are already revoked
+func isConnectionRevoked(connection *rdsdbaasv1alpha1.RDSConnection) bool {
+	condition := apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionReady)
+	return condition != nil && condition.Reason == connectionStatusReasonExpired
+}
+
+// revokeConnection deletes the credentials Secret or the ExternalSecret and PushSecret of the expired Connection, the
+// copies of the Secret in other namespaces and the objects using the credentials, the ConfigMap of the Connection is
+// kept as it holds no credentials. The read-only user is dropped from the database next by dropReadOnlyUser.
+func (r *RDSConnectionReconciler) revokeConnection(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	secretName := fmt.Sprintf("%s-credentials", connection.Name)
+
+	externalSecret := &unstructured.Unstructured{}
+	externalSecret.SetAPIVersion(externalSecretVersion)
+	externalSecret.SetKind(externalSecretKind)
+	externalSecret.SetName(secretName)
+	externalSecret.SetNamespace(connection.Namespace)
+	if e := r.Delete(ctx, externalSecret); e != nil && !errors.IsNotFound(e) && !apimeta.IsNoMatchError(e) {
+		return e
+	}
+	// the PushSecret deletes the credentials from the secret store
+	sourceName := fmt.Sprintf("%s-credentials-source", connection.Name)
+	pushSecret := &unstructured.Unstructured{}
+	pushSecret.SetAPIVersion(pushSecretVersion)
+	pushSecret.SetKind(pushSecretKind)
+	pushSecret.SetName(sourceName)
+	pushSecret.SetNamespace(connection.Namespace)
+	if e := r.Delete(ctx, pushSecret); e != nil && !errors.IsNotFound(e) && !apimeta.IsNoMatchError(e) {
+		return e
+	}
+	source := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: sourceName, Namespace: connection.Namespace}}
+	if e := r.Delete(ctx, source); e != nil && !errors.IsNotFound(e) {
+		return e
+	}
+
+	secret := &v1.Secret{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: connection.Namespace, Name: secretName}, secret); e == nil {
+		if metav1.IsControlledBy(secret, connection) {
+			if e := r.Delete(ctx, secret); e != nil && !errors.IsNotFound(e) {
+				return e
+			}
+		}
+	} else if !errors.IsNotFound(e) {
+		return e
+	}
+
+	if e := r.deleteCredentialsReplicas(ctx, connection.Namespace, connection.Name, nil); e != nil {
+		return e
+	}
+
+	if e := r.deleteConnectionPooler(ctx, connection); e != nil {
+		return e
+	}
+	for _, obj := range []client.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: getConnectionTestJobName(connection), Namespace: connection.Namespace}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: getCDCBootstrapObjectName(connection), Namespace: connection.Namespace}},
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: getCDCBootstrapObjectName(connection), Namespace: connection.Namespace}},
+	} {
+		if e := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); e != nil && !errors.IsNotFound(e) {
+			return e
+		}
+	}
+
+	connection.Status.CredentialsRef = nil
+	return nil
+}
+
+// getDropReadOnlyUserObjectName returns the name of the Secret and the Job dropping the read-only user of the Connection
+func getDropReadOnlyUserObjectName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-drop-read-only-user", strings.ReplaceAll(getReadOnlyUsername(connection), "_", "-"))
+}
+
+// dropReadOnlyUser drops the read-only user of the expired Connection with a Job run with the master credentials in the
+// namespace of the Jobs, it returns true once the Job completes. The Jobs of the Connection and their Secrets are
+// deleted then, with the finalizer of the Jobs.
+func (r *RDSConnectionReconciler) dropReadOnlyUser(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	jobNamespace, engine string, masterUsername *string, masterPassword []byte, host *string, port *int64, dbName *string) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if err := r.addConnectionJobsFinalizer(ctx, connection); err != nil {
+		return false, err
+	}
+
+	name := getDropReadOnlyUserObjectName(connection)
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: jobNamespace, Name: name}, job); err == nil {
+		dropped, err := getConnectionJobResult(job)
+		if err != nil || !dropped {
+			return false, err
+		}
+		return true, r.finalizeConnectionJobs(ctx, connection)
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	if masterUsername == nil || len(masterPassword) == 0 {
+		return false, fmt.Errorf("master credentials of service %s not found", connection.Spec.DatabaseServiceID)
+	}
+	if _, err := r.applyConnectionJobSecret(ctx, connection, jobNamespace, name, func(*v1.Secret) map[string][]byte {
+		return map[string][]byte{
+			"username":                     []byte(getReadOnlyUsername(connection)),
+			connectionJobKeyMasterUsername: []byte(*masterUsername),
+			connectionJobKeyMasterPassword: masterPassword,
+		}
+	}); err != nil {
+		return false, err
+	}
+	job = r.newDropReadOnlyUserJob(connection, jobNamespace, generateBindingType(engine), host, port, dbName)
+	if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+	logger.Info("Drop read-only user Job created", "Job", job.Name, "Namespace", jobNamespace)
+	return false, nil
+}
+
+func (r *RDSConnectionReconciler) newDropReadOnlyUserJob(connection *rdsdbaasv1alpha1.RDSConnection, namespace, bindingType string,
+	host *string, port *int64, dbName *string) *batchv1.Job {
+	secretName := getDropReadOnlyUserObjectName(connection)
+	fromSecret := func(name, key string) v1.EnvVar {
+		return secretEnvVar(name, secretName, key)
+	}
+
+	container := r.newConnectionJobContainer("drop-read-only-user", bindingType)
+	var dbPort string
+	if port != nil {
+		dbPort = strconv.FormatInt(*port, 10)
+	}
+	switch bindingType {
+	case "postgresql":
+		container.Command = []string{"/bin/sh", "-c",
+			`printf '%s\n' "$DROP_SQL" | psql --no-psqlrc -v ON_ERROR_STOP=1 -v user="$READ_ONLY_USER" --file=-`}
+		database := pointer.StringDeref(dbName, "")
+		if len(database) == 0 {
+			database = *getDefaultDBName(postgres)
+		}
+		container.Env = []v1.EnvVar{
+			{Name: "PGHOST", Value: pointer.StringDeref(host, "")},
+			{Name: "PGPORT", Value: dbPort},
+			{Name: "PGDATABASE", Value: database},
+			fromSecret("PGUSER", connectionJobKeyMasterUsername),
+			fromSecret("PGPASSWORD", connectionJobKeyMasterPassword),
+			fromSecret("READ_ONLY_USER", "username"),
+			{Name: "DROP_SQL", Value: postgresDropReadOnlyUserSQL},
+			{Name: "PGCONNECT_TIMEOUT", Value: "10"},
+		}
+	case "mysql":
+		container.Command = []string{"/bin/sh", "-c",
+			`mysql --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" --connect-timeout=10 --execute="` +
+				`DROP USER IF EXISTS '$READ_ONLY_USER'@'%';"`}
+		container.Env = []v1.EnvVar{
+			{Name: "DB_HOST", Value: pointer.StringDeref(host, "")},
+			{Name: "DB_PORT", Value: dbPort},
+			fromSecret("DB_USER", connectionJobKeyMasterUsername),
+			fromSecret("MYSQL_PWD", connectionJobKeyMasterPassword),
+			fromSecret("READ_ONLY_USER", "username"),
+		}
+	}
+
+	job := newConnectionJob(connection, secretName, dropReadOnlyUserActiveDeadlineSeconds, container)
+	job.Namespace = namespace
+	job.Labels = buildConnectionLabels()
+	job.Labels[connectionJobLabel] = "true"
+	job.Annotations = buildConnectionAnnotations(connection)
+	return job
+}
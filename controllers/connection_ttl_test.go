@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ConnectionTTL", func() {
+	It("should get the expiry of the Connection from its TTL", func() {
+		created := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		Expect(getConnectionExpiry(connection)).Should(BeZero())
+
+		connection.Annotations = map[string]string{connectionTTLAnnotation: "72h", connectionReadOnlyAnnotation: "true"}
+		Expect(getConnectionExpiry(connection)).Should(Equal(created.Add(72 * time.Hour)))
+
+		for _, ttl := range []string{"3d", "-1h", "0s"} {
+			connection.Annotations[connectionTTLAnnotation] = ttl
+			_, err := getConnectionExpiry(connection)
+			Expect(err).Should(HaveOccurred())
+		}
+	})
+
+	It("should refuse the TTL of a Connection binding the master user", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{connectionTTLAnnotation: "72h"},
+		}}
+		_, err := getConnectionExpiry(connection)
+		Expect(err).Should(HaveOccurred())
+		connection.Annotations[connectionReadOnlyAnnotation] = "false"
+		_, err = getConnectionExpiry(connection)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should drop the read-only user of the expired Connection with a Job", func() {
+		ctx := context.Background()
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{
+			Name:        "connection",
+			Namespace:   "app",
+			Annotations: map[string]string{connectionTTLAnnotation: "1h", connectionReadOnlyAnnotation: "true"},
+		}}
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(connection).Build()}
+		r := &RDSConnectionReconciler{Client: cli, Scheme: scheme.Scheme, JobNamespace: "operator"}
+		name := getDropReadOnlyUserObjectName(connection)
+		Expect(name).ShouldNot(Equal(getReadOnlyUserObjectName(connection)))
+
+		// the Secret of the Job creating the read-only user is deleted once the user is dropped
+		_, err := r.applyConnectionJobSecret(ctx, connection, "operator", getReadOnlyUserObjectName(connection),
+			func(*v1.Secret) map[string][]byte {
+				return map[string][]byte{"username": []byte(getReadOnlyUsername(connection)), "password": []byte("password")}
+			})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		dropped, err := r.dropReadOnlyUser(ctx, connection, "operator", "postgres", pointer.String("admin"),
+			[]byte("master-password"), pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(dropped).Should(BeFalse())
+		Expect(controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer)).Should(BeTrue())
+
+		secret := &v1.Secret{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "operator", Name: name}, secret)).Should(Succeed())
+		Expect(secret.Data).Should(HaveKeyWithValue("username", []byte(getReadOnlyUsername(connection))))
+		Expect(secret.Data).Should(HaveKeyWithValue(connectionJobKeyMasterPassword, []byte("master-password")))
+		Expect(secret.Data).ShouldNot(HaveKey("password"))
+
+		job := &batchv1.Job{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "operator", Name: name}, job)).Should(Succeed())
+		Expect(getJobConnectionRequests(job)).Should(HaveLen(1))
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Env).Should(ContainElement(v1.EnvVar{Name: "DROP_SQL", Value: postgresDropReadOnlyUserSQL}))
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				Expect(env.ValueFrom.SecretKeyRef.Name).Should(Equal(name))
+			}
+		}
+
+		// the running Job is not created again
+		dropped, err = r.dropReadOnlyUser(ctx, connection, "operator", "postgres", pointer.String("admin"),
+			[]byte("master-password"), pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(dropped).Should(BeFalse())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+		Expect(r.Status().Update(ctx, job)).Should(Succeed())
+		dropped, err = r.dropReadOnlyUser(ctx, connection, "operator", "postgres", pointer.String("admin"),
+			[]byte("master-password"), pointer.String("host"), pointer.Int64(5432), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(dropped).Should(BeTrue())
+		Expect(controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer)).Should(BeFalse())
+		secrets := &v1.SecretList{}
+		Expect(r.List(ctx, secrets, client.InNamespace("operator"))).Should(Succeed())
+		Expect(secrets.Items).Should(BeEmpty())
+		jobs := &batchv1.JobList{}
+		Expect(r.List(ctx, jobs, client.InNamespace("operator"))).Should(Succeed())
+		Expect(jobs.Items).Should(BeEmpty())
+	})
+
+	It("should drop the MySQL read-only user", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Name: "connection", Namespace: "app"}}
+		r := &RDSConnectionReconciler{}
+		job := r.newDropReadOnlyUserJob(connection, "operator", "mysql", pointer.String("host"), pointer.Int64(3306), pointer.String("app"))
+		Expect(job.Namespace).Should(Equal("operator"))
+		Expect(job.Spec.Template.Spec.Containers[0].Image).Should(Equal(DefaultConnectionTestMySQLImage))
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).Should(ContainSubstring("DROP USER IF EXISTS '$READ_ONLY_USER'@'%'"))
+	})
+
+	It("should consider the Connection revoked once expired", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{}
+		Expect(isConnectionRevoked(connection)).Should(BeFalse())
+		connection.Status.Conditions = []metav1.Condition{{Type: connectionConditionReady, Status: metav1.ConditionFalse,
+			Reason: connectionStatusReasonUpdating}}
+		Expect(isConnectionRevoked(connection)).Should(BeFalse())
+		connection.Status.Conditions[0].Reason = connectionStatusReasonExpired
+		Expect(isConnectionRevoked(connection)).Should(BeTrue())
+	})
+})
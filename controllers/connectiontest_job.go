@@ -0,0 +1,257 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// run a Job pinging the database with the credentials of the Connection once it is ready for binding
+	connectionTestAnnotation = "rds.dbaas.redhat.com/connection-test"
+
+	connectionTestGenerationAnnotation = "rds.dbaas.redhat.com/connection-generation"
+
+	DefaultConnectionTestPostgreSQLImage = "quay.io/sclorg/postgresql-15-c9s:latest"
+	DefaultConnectionTestMySQLImage      = "quay.io/sclorg/mysql-80-c9s:latest"
+
+	connectionTestActiveDeadlineSeconds   = 120
+	connectionTestTTLSecondsAfterFinished = 3600
+
+	connectionConditionTested = "ConnectionTested"
+
+	connectionTestReasonRunning     = "Running"
+	connectionTestReasonPassed      = "Passed"
+	connectionTestReasonFailed      = "Failed"
+	connectionTestReasonUnsupported = "Unsupported"
+
+	connectionTestMessageRunning     = "Connection test Job running"
+	connectionTestMessagePassed      = "Connection test Job passed"
+	connectionTestMessageFailed      = "Connection test Job failed"
+	connectionTestMessageUnsupported = "Connection test not supported for engine"
+	connectionTestMessageError       = "Failed to run connection test Job"
+)
+
+// syncConnectionTest runs the connection test Job of the current generation of the Connection and reflects its
+// result in the ConnectionTested condition, the Job is run again once the Connection is changed
+func (r *RDSConnectionReconciler) syncConnectionTest(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection, engine string) error {
+	logger := log.FromContext(ctx)
+
+	if enabled, e := strconv.ParseBool(connection.Annotations[connectionTestAnnotation]); e != nil || !enabled {
+		apimeta.RemoveStatusCondition(&connection.Status.Conditions, connectionConditionTested)
+		return nil
+	}
+	if connection.Status.CredentialsRef == nil || connection.Status.ConnectionInfoRef == nil {
+		return nil
+	}
+
+	setCondition := func(status metav1.ConditionStatus, reason, message string) {
+		apimeta.SetStatusCondition(&connection.Status.Conditions, metav1.Condition{
+			Type:               connectionConditionTested,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: connection.Generation,
+		})
+	}
+
+	container, ok := r.getConnectionTestContainer(engine, connection.Status.CredentialsRef.Name, connection.Status.ConnectionInfoRef.Name)
+	if !ok {
+		setCondition(metav1.ConditionFalse, connectionTestReasonUnsupported, fmt.Sprintf("%s %s", connectionTestMessageUnsupported, engine))
+		return nil
+	}
+
+	generation := strconv.FormatInt(connection.Generation, 10)
+	job := &batchv1.Job{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: connection.Namespace, Name: getConnectionTestJobName(connection)}, job); e != nil {
+		if !errors.IsNotFound(e) {
+			setCondition(metav1.ConditionFalse, connectionTestReasonFailed, connectionTestMessageError)
+			return e
+		}
+		// the Job of the current generation is removed after it finishes
+		if condition := apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionTested); condition != nil &&
+			condition.ObservedGeneration == connection.Generation && condition.Reason != connectionTestReasonRunning {
+			return nil
+		}
+		job = newConnectionTestJob(connection, generation, container)
+		if e := ctrl.SetControllerReference(connection, job, r.Scheme); e != nil {
+			setCondition(metav1.ConditionFalse, connectionTestReasonFailed, connectionTestMessageError)
+			return e
+		}
+		if e := r.Create(ctx, job); e != nil && !errors.IsAlreadyExists(e) {
+			setCondition(metav1.ConditionFalse, connectionTestReasonFailed, connectionTestMessageError)
+			return e
+		}
+		logger.Info("Connection test Job created", "Job", job.Name)
+		setCondition(metav1.ConditionFalse, connectionTestReasonRunning, connectionTestMessageRunning)
+		return nil
+	}
+
+	if job.Annotations[connectionTestGenerationAnnotation] != generation {
+		// run the test again for the changed Connection
+		if e := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); e != nil && !errors.IsNotFound(e) {
+			setCondition(metav1.ConditionFalse, connectionTestReasonFailed, connectionTestMessageError)
+			return e
+		}
+		setCondition(metav1.ConditionFalse, connectionTestReasonRunning, connectionTestMessageRunning)
+		return nil
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			setCondition(metav1.ConditionTrue, connectionTestReasonPassed, connectionTestMessagePassed)
+			return nil
+		case batchv1.JobFailed:
+			message := connectionTestMessageFailed
+			if len(c.Message) > 0 {
+				message = fmt.Sprintf("%s: %s", message, c.Message)
+			}
+			setCondition(metav1.ConditionFalse, connectionTestReasonFailed, message)
+			return nil
+		}
+	}
+	setCondition(metav1.ConditionFalse, connectionTestReasonRunning, connectionTestMessageRunning)
+	return nil
+}
+
+func getConnectionTestJobName(connection *rdsdbaasv1alpha1.RDSConnection) string {
+	return fmt.Sprintf("%s-connection-test", connection.Name)
+}
+
+// getConnectionTestContainer returns the container running a query with the client of the engine,
+// the credentials and the connection info are read from the Secret and the ConfigMap of the Connection
+func (r *RDSConnectionReconciler) getConnectionTestContainer(engine, secretName, configMapName string) (v1.Container, bool) {
+	fromConfigMap := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: configMapName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	fromOptionalConfigMap := func(name, key string) v1.EnvVar {
+		env := fromConfigMap(name, key)
+		env.ValueFrom.ConfigMapKeyRef.Optional = pointer.Bool(true)
+		return env
+	}
+	fromSecret := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	container := v1.Container{
+		Name: "connection-test",
+		SecurityContext: &v1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsNonRoot:             pointer.Bool(true),
+			Capabilities: &v1.Capabilities{
+				Drop: []v1.Capability{"ALL"},
+			},
+		},
+	}
+	switch generateBindingType(engine) {
+	case "postgresql":
+		container.Image = r.ConnectionTestPostgreSQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestPostgreSQLImage
+		}
+		container.Command = []string{"psql", "--no-psqlrc", "--command", "SELECT 1"}
+		container.Env = []v1.EnvVar{
+			fromConfigMap("PGHOST", "host"),
+			fromConfigMap("PGPORT", "port"),
+			fromConfigMap("PGDATABASE", "database"),
+			fromSecret("PGUSER", "username"),
+			fromSecret("PGPASSWORD", "password"),
+			fromOptionalConfigMap("PGSSLMODE", connectionInfoSSLMode),
+			{Name: "PGCONNECT_TIMEOUT", Value: "10"},
+		}
+	case "mysql":
+		container.Image = r.ConnectionTestMySQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestMySQLImage
+		}
+		container.Command = []string{"/bin/sh", "-c",
+			`mysql --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" --ssl-mode="${DB_SSL_MODE:-PREFERRED}" --connect-timeout=10 --execute="SELECT 1"`}
+		container.Env = []v1.EnvVar{
+			fromConfigMap("DB_HOST", "host"),
+			fromConfigMap("DB_PORT", "port"),
+			fromSecret("DB_USER", "username"),
+			fromSecret("MYSQL_PWD", "password"),
+			fromOptionalConfigMap("DB_SSL_MODE", connectionInfoMySQLSSLMode),
+		}
+	default:
+		return container, false
+	}
+	return container, true
+}
+
+func newConnectionTestJob(connection *rdsdbaasv1alpha1.RDSConnection, generation string, container v1.Container) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getConnectionTestJobName(connection),
+			Namespace: connection.Namespace,
+			Annotations: map[string]string{
+				connectionTestGenerationAnnotation: generation,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            pointer.Int32(1),
+			ActiveDeadlineSeconds:   pointer.Int64(connectionTestActiveDeadlineSeconds),
+			TTLSecondsAfterFinished: pointer.Int32(connectionTestTTLSecondsAfterFinished),
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy:                v1.RestartPolicyNever,
+					AutomountServiceAccountToken: pointer.Bool(false),
+					SecurityContext: &v1.PodSecurityContext{
+						SeccompProfile: &v1.SeccompProfile{
+							Type: v1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []v1.Container{container},
+				},
+			},
+		},
+	}
+}
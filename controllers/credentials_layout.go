@@ -0,0 +1,281 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+const (
+	// the credentials Secret of an Inventory can hold an AWS shared credentials file instead of separate keys, the
+	// profile of the file is selected by the AWS_PROFILE key of the Secret
+	credentialsFileKey        = "credentials"
+	awsProfile                = "AWS_PROFILE"
+	defaultCredentialsProfile = "default"
+
+	awsDefaultRegion = "AWS_DEFAULT_REGION"
+
+	// the session token and the expiry time in RFC 3339 format of temporary credentials
+	awsSessionToken         = "AWS_SESSION_TOKEN" //#nosec G101
+	awsCredentialExpiration = "AWS_CREDENTIAL_EXPIRATION"
+
+	// the role of the Inventory assumed with the web identity of the operator if the Secret has no static credentials
+	awsRoleARN = "AWS_ROLE_ARN"
+
+	credentialsFileInvalidErrorTemplate = "line %d of the credentials file is invalid"
+	credentialsProfileErrorTemplate     = "profile %s is not in the credentials file"
+	credentialKeyNameErrorTemplate      = "required credential %s is missing, key %s of the credentials Secret is not supported"
+	credentialExpirationErrorTemplate   = "credential %s is not a time in RFC 3339 format"
+)
+
+// credentialsLayout reads the values of the credential keys from one layout of the credentials Secret
+type credentialsLayout struct {
+	name string
+	read func(data map[string][]byte) (map[string]string, error)
+}
+
+// credentialsLayouts are the layouts of the credentials Secret by precedence, the first layout setting a credential
+// gives its value
+var credentialsLayouts = []credentialsLayout{
+	{
+		name: "AWS environment variable keys",
+		read: readCredentialKeys(map[string][]string{
+			awsAccessKeyID:          {awsAccessKeyID},
+			awsSecretAccessKey:      {awsSecretAccessKey},
+			awsRegion:               {awsRegion, awsDefaultRegion},
+			awsSessionToken:         {awsSessionToken},
+			awsCredentialExpiration: {awsCredentialExpiration},
+			awsRoleARN:              {awsRoleARN},
+		}),
+	},
+	{
+		name: "lower-case keys",
+		read: readCredentialKeys(map[string][]string{
+			awsAccessKeyID:          {"aws_access_key_id"},
+			awsSecretAccessKey:      {"aws_secret_access_key"},
+			awsRegion:               {"aws_region", "aws_default_region", "region"},
+			awsSessionToken:         {"aws_session_token"},
+			awsCredentialExpiration: {"aws_credential_expiration"},
+			awsRoleARN:              {"aws_role_arn", "role_arn"},
+		}),
+	},
+	{
+		name: "credentials file",
+		read: readCredentialsFile,
+	},
+}
+
+// misnamedCredentialKeys are the key names commonly set instead of the supported ones, they are reported in the
+// validation errors of the missing credentials
+var misnamedCredentialKeys = map[string]string{
+	"AWS_ACCESS_KEY":           awsAccessKeyID,
+	"ACCESS_KEY_ID":            awsAccessKeyID,
+	"AWS_SECRET_KEY":           awsSecretAccessKey,
+	"SECRET_ACCESS_KEY":        awsSecretAccessKey,
+	"AWS_SECRET_ACCESS_KEY_ID": awsSecretAccessKey,
+	"REGION":                   awsRegion,
+}
+
+func readCredentialKeys(keys map[string][]string) func(data map[string][]byte) (map[string]string, error) {
+	return func(data map[string][]byte) (map[string]string, error) {
+		values := map[string]string{}
+		for credential, names := range keys {
+			for _, name := range names {
+				if v := strings.TrimSpace(string(data[name])); len(v) > 0 {
+					values[credential] = v
+					break
+				}
+			}
+		}
+		return values, nil
+	}
+}
+
+// readCredentialsFile reads the credentials of the selected profile of the AWS shared credentials file
+func readCredentialsFile(data map[string][]byte) (map[string]string, error) {
+	file, ok := data[credentialsFileKey]
+	if !ok {
+		return nil, nil
+	}
+	profile := strings.TrimSpace(string(data[awsProfile]))
+	if len(profile) == 0 {
+		profile = defaultCredentialsProfile
+	}
+
+	profiles := map[string]map[string]string{}
+	var section map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(file))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf(credentialsFileInvalidErrorTemplate, n)
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(strings.Trim(line, "[]"), "profile "))
+			section = map[string]string{}
+			profiles[name] = section
+			continue
+		}
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 || section == nil {
+			return nil, fmt.Errorf(credentialsFileInvalidErrorTemplate, n)
+		}
+		section[strings.ToLower(strings.TrimSpace(fields[0]))] = strings.TrimSpace(fields[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	section, ok = profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf(credentialsProfileErrorTemplate, profile)
+	}
+	return readCredentialKeys(map[string][]string{
+		awsAccessKeyID:     {"aws_access_key_id"},
+		awsSecretAccessKey: {"aws_secret_access_key"},
+		awsRegion:          {"region"},
+		awsSessionToken:    {"aws_session_token", "aws_security_token"},
+		// x_security_token_expires is written by the SSO tools
+		awsCredentialExpiration: {"aws_credential_expiration", "x_security_token_expires"},
+		awsRoleARN:              {"role_arn"},
+	})(toCredentialData(section))
+}
+
+func toCredentialData(section map[string]string) map[string][]byte {
+	data := make(map[string][]byte, len(section))
+	for k, v := range section {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// normalizeCredentials sets the credentials of the layouts of the credentials Secret of an Inventory on their
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_REGION, AWS_SESSION_TOKEN and AWS_ROLE_ARN keys, which are read by
+// the reconcilers and copied to the RDS controller, and sets the session token of temporary credentials for the AWS
+// clients. The Secret is only updated in memory.
+func normalizeCredentials(secret *v1.Secret) error {
+	values := map[string]string{}
+	for _, layout := range credentialsLayouts {
+		v, err := layout.read(secret.Data)
+		if err != nil {
+			return fmt.Errorf("failed to read the %s of the credentials Secret: %w", layout.name, err)
+		}
+		for credential, value := range v {
+			if _, ok := values[credential]; !ok {
+				values[credential] = value
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if v, ok := values[awsCredentialExpiration]; ok {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return fmt.Errorf(credentialExpirationErrorTemplate, awsCredentialExpiration)
+		}
+	}
+	controllerssts.SetSessionToken(values[awsAccessKeyID], values[awsSessionToken])
+
+	data := make(map[string][]byte, len(secret.Data)+len(values))
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	for credential, value := range values {
+		data[credential] = []byte(value)
+	}
+	secret.Data = data
+	return nil
+}
+
+// getCredentialsExpiry returns the expiry time of the temporary credentials of the normalized credentials Secret, or
+// zero if they do not expire
+func getCredentialsExpiry(secret *v1.Secret) time.Time {
+	expiry, err := time.Parse(time.RFC3339, string(secret.Data[awsCredentialExpiration]))
+	if err != nil {
+		return time.Time{}
+	}
+	return expiry
+}
+
+// getCredentialKeyNameError returns the validation error of a missing credential if the credentials Secret has a key
+// commonly set instead of the supported ones, or the key of the credential in another case or with dashes
+func getCredentialKeyNameError(data map[string][]byte, credential string) error {
+	for key := range data {
+		normalized := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if normalized == credential || misnamedCredentialKeys[normalized] == credential {
+			return fmt.Errorf(credentialKeyNameErrorTemplate, credential, key)
+		}
+	}
+	return nil
+}
+
+// getInventoryCredentials gets the credentials Secret of the Inventory with its credentials normalized
+func getInventoryCredentials(ctx context.Context, reader client.Reader, inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: inventory.Spec.CredentialsRef.Name}, secret); err != nil {
+		return err
+	}
+	if err := normalizeCredentials(secret); err != nil {
+		return err
+	}
+	_, err := assumeInventoryRole(ctx, secret)
+	return err
+}
+
+// assumeInventoryRole sets the temporary credentials of the AWS_ROLE_ARN role of the normalized credentials Secret of
+// an Inventory without static credentials, assumed with the web identity of the operator, on its AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN keys, and returns their expiry time. The role is not assumed if the
+// Secret has static credentials or no role, the Inventory then requires the static credentials. The Secret is only
+// updated in memory.
+func assumeInventoryRole(ctx context.Context, secret *v1.Secret) (time.Time, error) {
+	roleARN := string(secret.Data[awsRoleARN])
+	if len(roleARN) == 0 || len(secret.Data[awsAccessKeyID]) > 0 || len(secret.Data[awsSecretAccessKey]) > 0 {
+		return time.Time{}, nil
+	}
+	region := string(secret.Data[awsRegion])
+	if len(region) == 0 {
+		return time.Time{}, fmt.Errorf(requiredCredentialErrorTemplate, awsRegion)
+	}
+	credentials, err := controllerssts.AssumeRole(ctx, roleARN, region)
+	if err != nil {
+		return time.Time{}, err
+	}
+	controllerssts.SetSessionToken(credentials.AccessKeyID, credentials.SessionToken)
+
+	data := make(map[string][]byte, len(secret.Data)+3)
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	data[awsAccessKeyID] = []byte(credentials.AccessKeyID)
+	data[awsSecretAccessKey] = []byte(credentials.SecretAccessKey)
+	data[awsSessionToken] = []byte(credentials.SessionToken)
+	secret.Data = data
+	return credentials.Expires, nil
+}
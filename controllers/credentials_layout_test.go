@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredentialsLayout", func() {
+	It("should read the credentials of the lower-case keys", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			"aws_access_key_id":     []byte("AKIA"),
+			"aws_secret_access_key": []byte("secret"),
+			"region":                []byte("us-east-1"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("AKIA"))
+		Expect(string(secret.Data[awsSecretAccessKey])).Should(Equal("secret"))
+		Expect(string(secret.Data[awsRegion])).Should(Equal("us-east-1"))
+	})
+
+	It("should read the credentials of the selected profile of the credentials file", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte(`# AWS credentials
+[default]
+aws_access_key_id = AKIA-DEFAULT
+aws_secret_access_key = secret-default
+
+[profile dev]
+aws_access_key_id=AKIA-DEV
+aws_secret_access_key=secret-dev
+region=eu-west-1
+`),
+			awsProfile: []byte("dev"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("AKIA-DEV"))
+		Expect(string(secret.Data[awsSecretAccessKey])).Should(Equal("secret-dev"))
+		Expect(string(secret.Data[awsRegion])).Should(Equal("eu-west-1"))
+
+		delete(secret.Data, awsProfile)
+		delete(secret.Data, awsAccessKeyID)
+		delete(secret.Data, awsSecretAccessKey)
+		delete(secret.Data, awsRegion)
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("AKIA-DEFAULT"))
+		Expect(secret.Data).ShouldNot(HaveKey(awsRegion))
+	})
+
+	It("should take the credentials by the precedence of the layouts", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			awsAccessKeyID:          []byte("AKIA-ENV"),
+			"aws_access_key_id":     []byte("AKIA-LOWER"),
+			"aws_secret_access_key": []byte("secret-lower"),
+			credentialsFileKey:      []byte("[default]\naws_secret_access_key = secret-file\nregion = us-west-2\n"),
+			awsDefaultRegion:        []byte("us-east-2"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("AKIA-ENV"))
+		Expect(string(secret.Data[awsSecretAccessKey])).Should(Equal("secret-lower"))
+		Expect(string(secret.Data[awsRegion])).Should(Equal("us-east-2"))
+	})
+
+	It("should not update the data of the Secret in place", func() {
+		data := map[string][]byte{"aws_access_key_id": []byte("AKIA")}
+		secret := &v1.Secret{Data: data}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(data).ShouldNot(HaveKey(awsAccessKeyID))
+	})
+
+	It("should fail on invalid credentials files", func() {
+		Expect(normalizeCredentials(&v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte("aws_access_key_id = AKIA\n"),
+		}})).Should(MatchError(ContainSubstring("line 1 of the credentials file is invalid")))
+		Expect(normalizeCredentials(&v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte("[default]\naws_access_key_id\n"),
+		}})).Should(MatchError(ContainSubstring("line 2 of the credentials file is invalid")))
+		Expect(normalizeCredentials(&v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte("[default]\naws_access_key_id = AKIA\n"),
+			awsProfile:         []byte("prod"),
+		}})).Should(MatchError(ContainSubstring("profile prod is not in the credentials file")))
+	})
+
+	It("should report the misnamed credential keys", func() {
+		data := map[string][]byte{
+			"AWS_SECRET_KEY":    []byte("secret"),
+			"aws-access-key-id": []byte("AKIA"),
+		}
+		Expect(getCredentialKeyNameError(data, awsAccessKeyID)).Should(MatchError(
+			"required credential AWS_ACCESS_KEY_ID is missing, key aws-access-key-id of the credentials Secret is not supported"))
+		Expect(getCredentialKeyNameError(data, awsSecretAccessKey)).Should(MatchError(
+			"required credential AWS_SECRET_ACCESS_KEY is missing, key AWS_SECRET_KEY of the credentials Secret is not supported"))
+		Expect(getCredentialKeyNameError(data, awsRegion)).Should(BeNil())
+	})
+
+	It("should read the session token and the expiration of temporary credentials", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte("[default]\naws_access_key_id = ASIA-FILE\naws_secret_access_key = secret\n" +
+				"aws_session_token = token\nx_security_token_expires = 2022-11-01T10:00:00Z\n"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsSessionToken])).Should(Equal("token"))
+		Expect(getCredentialsExpiry(secret)).Should(Equal(time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)))
+		Expect(getCredentialsExpiry(&v1.Secret{})).Should(BeZero())
+	})
+
+	It("should fail on an invalid expiration of the credentials", func() {
+		Expect(normalizeCredentials(&v1.Secret{Data: map[string][]byte{
+			awsAccessKeyID:          []byte("ASIA"),
+			awsCredentialExpiration: []byte("tomorrow"),
+		}})).Should(MatchError(ContainSubstring(awsCredentialExpiration)))
+	})
+
+})
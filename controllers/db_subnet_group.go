@@ -0,0 +1,339 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const (
+	// create a DB subnet group from the worker subnets of the cluster for the instances of the Inventory
+	inventoryAutoDBSubnetGroupAnnotation = "rds.dbaas.redhat.com/auto-db-subnet-group"
+	// the name of the cluster in the kubernetes.io/cluster/<name> tags of its subnets, required if the cluster is not
+	// an OpenShift cluster with an infrastructure name
+	inventoryClusterNameAnnotation = "rds.dbaas.redhat.com/cluster-name"
+	clusterSubnetTagKeyPrefix      = "kubernetes.io/cluster/"
+	internalELBSubnetTagKey        = "kubernetes.io/role/internal-elb"
+
+	// the label of the DB subnet groups created by the operator, and the time they were last selected for a DB instance
+	autoDBSubnetGroupLabel              = "rds.dbaas.redhat.com/auto-db-subnet-group"
+	autoDBSubnetGroupLastUsedAnnotation = "rds.dbaas.redhat.com/last-used"
+
+	// the DB subnet groups created by the operator are deleted once no DB instance has used them for the grace period
+	autoDBSubnetGroupGracePeriod = time.Hour
+
+	// the ConfigMap of the DB subnet groups discovered by the sync of the Inventory, in the namespace of the Inventory
+	dbSubnetGroupsConfigMapSuffix = "-db-subnet-groups"
+	dbSubnetGroupsKey             = "dbSubnetGroups"
+)
+
+// discoveredDBSubnetGroup is a DB subnet group of the AWS account of an Inventory
+type discoveredDBSubnetGroup struct {
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// getClusterName returns the name of the cluster in the tags of its subnets, from the annotation of the Inventory or
+// the infrastructure name of the OpenShift cluster
+func getClusterName(ctx context.Context, reader client.Reader, inventory *rdsdbaasv1alpha1.RDSInventory) (string, error) {
+	if name, ok := inventory.Annotations[inventoryClusterNameAnnotation]; ok && len(name) > 0 {
+		return name, nil
+	}
+	infrastructure := &unstructured.Unstructured{}
+	infrastructure.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Infrastructure"})
+	if e := reader.Get(ctx, client.ObjectKey{Name: "cluster"}, infrastructure); e != nil {
+		if errors.IsNotFound(e) || apimeta.IsNoMatchError(e) {
+			return "", fmt.Errorf("cluster infrastructure not found for creating DB subnet group, set annotation %s of the Inventory",
+				inventoryClusterNameAnnotation)
+		}
+		return "", fmt.Errorf("failed to get the cluster infrastructure for creating DB subnet group: %v", e)
+	}
+	infraID, found, e := unstructured.NestedString(infrastructure.Object, "status", "infrastructureName")
+	if e != nil || !found || len(infraID) == 0 {
+		return "", fmt.Errorf("infrastructure name of the cluster not found for creating DB subnet group")
+	}
+	return infraID, nil
+}
+
+// createOrUpdateDBSubnetGroup creates the DB subnet group of the Inventory from the private worker subnets of the cluster,
+// the DB subnet group is deleted by the Inventory once no DB instance uses it
+func (r *RDSInstanceReconciler) createOrUpdateDBSubnetGroup(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	if r.GetDescribeSubnetsAPI == nil {
+		return nil
+	}
+
+	clusterName, e := getClusterName(ctx, r.Client, inventory)
+	if e != nil {
+		return e
+	}
+
+	describeSubnets := r.GetDescribeSubnetsAPI(string(secret.Data[awsAccessKeyID]), string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	input := &ec2.DescribeSubnetsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   pointer.String("tag-key"),
+				Values: []string{clusterSubnetTagKeyPrefix + clusterName},
+			},
+		},
+	}
+	var subnets, privateSubnets []ec2types.Subnet
+	for {
+		output, e := describeSubnets.DescribeSubnets(ctx, input)
+		if e != nil {
+			return e
+		}
+		if output == nil {
+			break
+		}
+		for _, subnet := range output.Subnets {
+			if subnet.SubnetId == nil {
+				continue
+			}
+			subnets = append(subnets, subnet)
+			for _, t := range subnet.Tags {
+				if t.Key != nil && *t.Key == internalELBSubnetTagKey {
+					privateSubnets = append(privateSubnets, subnet)
+					break
+				}
+			}
+		}
+		if output.NextToken == nil || len(*output.NextToken) == 0 {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	if len(privateSubnets) > 0 {
+		subnets = privateSubnets
+	}
+
+	var subnetIDs []string
+	zones := map[string]bool{}
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, *subnet.SubnetId)
+		if subnet.AvailabilityZone != nil {
+			zones[*subnet.AvailabilityZone] = true
+		}
+	}
+	// a DB subnet group must cover at least two availability zones
+	if len(zones) < 2 {
+		return fmt.Errorf("worker subnets of cluster %s are not in at least two availability zones for creating DB subnet group", clusterName)
+	}
+	sort.Strings(subnetIDs)
+
+	name := fmt.Sprintf("rhoda-%s", strings.ToLower(clusterName))
+	subnetGroup := &rdsv1alpha1.DBSubnetGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: inventory.Namespace,
+		},
+	}
+	if _, e := createOrApply(ctx, r.Client, subnetGroup, func(client.Object) error {
+		if e := ctrl.SetControllerReference(inventory, subnetGroup, r.Scheme); e != nil {
+			return e
+		}
+		subnetGroup.Labels = map[string]string{autoDBSubnetGroupLabel: "true"}
+		// the DB subnet group is not collected before the DB instance is created
+		subnetGroup.Annotations = map[string]string{autoDBSubnetGroupLastUsedAnnotation: time.Now().UTC().Format(time.RFC3339)}
+		subnetGroup.Spec.Name = pointer.String(name)
+		subnetGroup.Spec.Description = pointer.String(fmt.Sprintf("DB subnet group of the worker subnets of cluster %s", clusterName))
+		for i := range subnetIDs {
+			subnetGroup.Spec.SubnetIDs = append(subnetGroup.Spec.SubnetIDs, &subnetIDs[i])
+		}
+		return nil
+	}); e != nil {
+		return e
+	}
+
+	dbInstance.Spec.DBSubnetGroupName = pointer.String(name)
+	return nil
+}
+
+// syncDBSubnetGroups discovers the DB subnet groups of the Inventory with their tags, the DB subnet groups of the
+// Instances are selected among them by name prefix or tag selector
+func (r *RDSInventoryReconciler) syncDBSubnetGroups(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory, accessKey, secretKey, region string) error {
+	if r.GetDescribeDBSubnetGroupsAPI == nil {
+		return nil
+	}
+
+	describeDBSubnetGroups := r.GetDescribeDBSubnetGroupsAPI(accessKey, secretKey, region)
+	input := &rds.DescribeDBSubnetGroupsInput{
+		MaxRecords: pointer.Int32(100),
+	}
+	var subnetGroups []discoveredDBSubnetGroup
+	for {
+		output, e := describeDBSubnetGroups.DescribeDBSubnetGroups(ctx, input)
+		if e != nil {
+			return e
+		}
+		if output == nil {
+			break
+		}
+		for _, sg := range output.DBSubnetGroups {
+			if sg.DBSubnetGroupName == nil {
+				continue
+			}
+			subnetGroup := discoveredDBSubnetGroup{Name: *sg.DBSubnetGroupName}
+			if sg.DBSubnetGroupArn != nil && r.GetListTagsForResourceAPI != nil {
+				listTagsForResource := r.GetListTagsForResourceAPI(accessKey, secretKey, region)
+				tagsOutput, e := listTagsForResource.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{
+					ResourceName: sg.DBSubnetGroupArn,
+				})
+				if e != nil {
+					return e
+				}
+				for _, t := range tagsOutput.TagList {
+					if t.Key == nil {
+						continue
+					}
+					if subnetGroup.Tags == nil {
+						subnetGroup.Tags = map[string]string{}
+					}
+					subnetGroup.Tags[*t.Key] = pointer.StringDeref(t.Value, "")
+				}
+			}
+			subnetGroups = append(subnetGroups, subnetGroup)
+		}
+		if output.Marker == nil || len(*output.Marker) == 0 {
+			break
+		}
+		input.Marker = output.Marker
+	}
+	sort.Slice(subnetGroups, func(i, j int) bool {
+		return subnetGroups[i].Name < subnetGroups[j].Name
+	})
+	data, e := json.Marshal(subnetGroups)
+	if e != nil {
+		return e
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inventory.Name + dbSubnetGroupsConfigMapSuffix,
+			Namespace: inventory.Namespace,
+		},
+	}
+	_, e = createOrApply(ctx, r.Client, configMap, func(client.Object) error {
+		// the ConfigMaps of the cache are selected by the label
+		configMap.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+		configMap.Data = map[string]string{dbSubnetGroupsKey: string(data)}
+		return ctrl.SetControllerReference(inventory, configMap, r.Scheme)
+	})
+	return e
+}
+
+// getDiscoveredDBSubnetGroups returns the DB subnet groups discovered by the last sync of the Inventory, sorted by name
+func getDiscoveredDBSubnetGroups(ctx context.Context, reader client.Reader, inventory *rdsdbaasv1alpha1.RDSInventory) ([]discoveredDBSubnetGroup, error) {
+	configMap := &v1.ConfigMap{}
+	if e := reader.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: inventory.Name + dbSubnetGroupsConfigMapSuffix}, configMap); e != nil {
+		if errors.IsNotFound(e) {
+			return nil, fmt.Errorf("DB subnet groups of Inventory %s/%s not discovered yet", inventory.Namespace, inventory.Name)
+		}
+		return nil, e
+	}
+	var subnetGroups []discoveredDBSubnetGroup
+	if e := json.Unmarshal([]byte(configMap.Data[dbSubnetGroupsKey]), &subnetGroups); e != nil {
+		return nil, fmt.Errorf("DB subnet groups of Inventory %s/%s not valid: %v", inventory.Namespace, inventory.Name, e)
+	}
+	return subnetGroups, nil
+}
+
+// matchTags returns whether the tags hold all the tags of the selector
+func matchTags(tags, selector map[string]string) bool {
+	for k, v := range selector {
+		if tv, ok := tags[k]; !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteUnusedDBSubnetGroups deletes the DB subnet groups created by the operator for the DB instances of the Inventory
+// once no DB instance nor DB cluster of the Inventory uses them and they were last used before the grace period
+func (r *RDSInventoryReconciler) deleteUnusedDBSubnetGroups(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory, now time.Time) error {
+	logger := log.FromContext(ctx)
+
+	subnetGroupList := &rdsv1alpha1.DBSubnetGroupList{}
+	if e := r.List(ctx, subnetGroupList, client.InNamespace(inventory.Namespace), client.MatchingLabels{autoDBSubnetGroupLabel: "true"}); e != nil {
+		return e
+	}
+	if len(subnetGroupList.Items) == 0 {
+		return nil
+	}
+
+	used := map[string]bool{}
+	dbInstanceList := &rdsv1alpha1.DBInstanceList{}
+	if e := r.List(ctx, dbInstanceList, client.InNamespace(inventory.Namespace)); e != nil {
+		return e
+	}
+	for _, dbInstance := range dbInstanceList.Items {
+		if dbInstance.Spec.DBSubnetGroupName != nil {
+			used[*dbInstance.Spec.DBSubnetGroupName] = true
+		}
+	}
+	dbClusterList := &rdsv1alpha1.DBClusterList{}
+	if e := r.List(ctx, dbClusterList, client.InNamespace(inventory.Namespace)); e != nil {
+		return e
+	}
+	for _, dbCluster := range dbClusterList.Items {
+		if dbCluster.Spec.DBSubnetGroupName != nil {
+			used[*dbCluster.Spec.DBSubnetGroupName] = true
+		}
+	}
+
+	for i := range subnetGroupList.Items {
+		subnetGroup := &subnetGroupList.Items[i]
+		if !metav1.IsControlledBy(subnetGroup, inventory) || used[pointer.StringDeref(subnetGroup.Spec.Name, subnetGroup.Name)] {
+			continue
+		}
+		if lastUsed, e := time.Parse(time.RFC3339, subnetGroup.Annotations[autoDBSubnetGroupLastUsedAnnotation]); e == nil &&
+			now.Sub(lastUsed) < autoDBSubnetGroupGracePeriod {
+			continue
+		}
+		if e := r.Delete(ctx, subnetGroup, client.Preconditions{ResourceVersion: &subnetGroup.ResourceVersion}); e != nil {
+			if errors.IsNotFound(e) || errors.IsConflict(e) {
+				continue
+			}
+			return e
+		}
+		logger.Info("Unused DB subnet group deleted", "DBSubnetGroup", subnetGroup.Name)
+	}
+	return nil
+}
@@ -19,7 +19,6 @@ package controllers
 import (
 	cryptorand "crypto/rand"
 	"math/big"
-	"math/rand"
 
 	"k8s.io/utils/pointer"
 )
@@ -242,13 +241,27 @@ func generateBindingType(engine string) string {
 	}
 }
 
+// getEngineFamily returns the family of the engine, for the client libraries choosing their driver by the family, the
+// MariaDB engine is not in the MySQL family as it has its own drivers
+func getEngineFamily(engine string) string {
+	switch engine {
+	case mariadb:
+		return mariadb
+	default:
+		return generateBindingType(engine)
+	}
+}
+
 func getDefaultDBName(engine string) *string {
 	switch engine {
 	case postgres, auroraPostgresql:
 		return pointer.String("postgres")
 	case sqlserverEe, sqlserverSe, sqlserverEx, sqlserverWeb, customSqlserverEe, customSqlserverSe, customSqlserverWeb:
 		return pointer.String("master")
-	case oracleSe2, oracleSe2Cdb, oracleEe, oracleEeCdb, customOracleEe:
+	case oracleSe2Cdb, oracleEeCdb:
+		// the SID of the container database of the multitenant architecture
+		return pointer.String("RDSCDB")
+	case oracleSe2, oracleEe, customOracleEe:
 		return pointer.String("ORCL")
 	case mysql, mariadb, aurora, auroraMysql:
 		return pointer.String("mysql")
@@ -353,18 +366,7 @@ func getDBEngineAbbreviation(engine *string) string {
 }
 
 func generatePassword() string {
-	length := 12
-	buf := make([]byte, length)
-	buf[0] = digits[getRandInt(len(digits))]
-	buf[1] = specials[getRandInt(len(specials))]
-	buf[2] = letter[getRandInt(len(letter))]
-	for i := 3; i < length; i++ {
-		buf[i] = all[getRandInt(len(all))]
-	}
-	rand.Shuffle(len(buf), func(i, j int) {
-		buf[i], buf[j] = buf[j], buf[i]
-	})
-	return string(buf) // E.g. "3i[g0|)z"
+	return generatePasswordWithPolicy(defaultPasswordPolicy) // E.g. "3i[g0|)z"
 }
 
 func getRandInt(s int) int64 {
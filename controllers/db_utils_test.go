@@ -117,6 +117,22 @@ var _ = Describe("DBUtils", func() {
 		)
 	})
 
+	Context("Get Engine Family", func() {
+		DescribeTable("checking getEngineFamily",
+			func(engine string, family string) {
+				Expect(getEngineFamily(engine)).Should(Equal(family))
+			},
+
+			Entry("aurora", "aurora", "mysql"),
+			Entry("aurora-postgresql", "aurora-postgresql", "postgresql"),
+			Entry("mariadb", "mariadb", "mariadb"),
+			Entry("mysql", "mysql", "mysql"),
+			Entry("oracle-se2-cdb", "oracle-se2-cdb", "oracle"),
+			Entry("sqlserver-ex", "sqlserver-ex", "sqlserver"),
+			Entry("Invalid", "invalid", ""),
+		)
+	})
+
 	Context("Get Default DB Name", func() {
 		DescribeTable("checking getDefaultDBName",
 			func(engine string, dbName *string) {
@@ -135,9 +151,9 @@ var _ = Describe("DBUtils", func() {
 			Entry("mariadb", "mariadb", pointer.String("mysql")),
 			Entry("mysql", "mysql", pointer.String("mysql")),
 			Entry("oracle-ee", "oracle-ee", pointer.String("ORCL")),
-			Entry("oracle-ee-cdb", "oracle-ee-cdb", pointer.String("ORCL")),
+			Entry("oracle-ee-cdb", "oracle-ee-cdb", pointer.String("RDSCDB")),
 			Entry("oracle-se2", "oracle-se2", pointer.String("ORCL")),
-			Entry("oracle-se2-cdb", "oracle-se2-cdb", pointer.String("ORCL")),
+			Entry("oracle-se2-cdb", "oracle-se2-cdb", pointer.String("RDSCDB")),
 			Entry("postgres", "postgres", pointer.String("postgres")),
 			Entry("sqlserver-ee", "sqlserver-ee", pointer.String("master")),
 			Entry("sqlserver-se", "sqlserver-se", pointer.String("master")),
@@ -19,15 +19,21 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	label "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,14 +43,20 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	dbaasoperator "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
 )
 
 const (
@@ -57,6 +69,9 @@ const (
 	typeLabelValue      = "dbaas-provider-registration"
 
 	dbaasproviderCRFile = "rds_registration.yaml"
+
+	providerConditionOwnerResolved = "OwnerResolved"
+	providerReasonAmbiguousOwner   = "AmbiguousOwner"
 )
 
 var labels = map[string]string{relatedToLabelName: relatedToLabelValue, typeLabelName: typeLabelValue}
@@ -64,15 +79,31 @@ var labels = map[string]string{relatedToLabelName: relatedToLabelValue, typeLabe
 type DBaaSProviderReconciler struct {
 	client.Client
 	*runtime.Scheme
-	Clientset                *kubernetes.Clientset
-	DBaaSProviderCRFilePath  string
+	Clientset                                *kubernetes.Clientset
+	DBaaSProviderCRFilePath                  string
+	GetDescribeDBEngineVersionsAPI           func(accessKey, secretKey, region string) controllersrds.DescribeDBEngineVersionsAPI
+	GetDescribeOrderableDBInstanceOptionsAPI func(accessKey, secretKey, region string) controllersrds.DescribeOrderableDBInstanceOptionsAPI
+	ProvisioningOptionsRefreshInterval       time.Duration
+	// the Deployment and the ClusterRole owning the provider registration when the operator is not installed by OLM,
+	// the registration has no owner if the ClusterRole is not found
+	DeploymentName           string
+	OwnerClusterRoleName     string
 	operatorNameVersion      string
 	operatorInstallNamespace string
+
+	// the operator Deployment last reconciled, which is reconciled again when the registration file changes
+	deploymentMutex    sync.Mutex
+	deployment         *v1.Deployment
+	providerFileEvents chan event.GenericEvent
 }
 
+// provisioningOptions maps each database engine available to the Inventory accounts to its orderable instance classes
+type provisioningOptions map[string][]string
+
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;create;update;delete;watch
 // +kubebuilder:rbac:groups=dbaas.redhat.com,resources=dbaasproviders,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=dbaas.redhat.com,resources=dbaasproviders/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch
 
 func (r *DBaaSProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx, "DBaaSProvider", req.NamespacedName, "during", "DBaaSProvider Reconciler")
@@ -92,6 +123,7 @@ func (r *DBaaSProviderReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		logger.Error(err, "error fetching Deployment CR")
 		return ctrl.Result{}, err
 	}
+	r.setDeployment(dep)
 
 	isCrdInstalled, err := r.checkCrdInstalled(dbaasoperator.GroupVersion.String(), providerKind)
 	if err != nil {
@@ -105,23 +137,19 @@ func (r *DBaaSProviderReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// RDS controller registration custom resource isn't present,so create now with ClusterRole owner for GC
-	opts := &client.ListOptions{
-		LabelSelector: label.SelectorFromSet(map[string]string{
-			"olm.owner":      r.operatorNameVersion,
-			"olm.owner.kind": "ClusterServiceVersion",
-		}),
-	}
-	clusterRoleList := &rbac.ClusterRoleList{}
-	if err := r.List(context.Background(), clusterRoleList, opts); err != nil {
-		logger.Error(err, "unable to list ClusterRoles to seek potential operand owners")
+	owner, err := r.getOwnerClusterRole(ctx)
+	if err != nil {
+		var ambiguousErr *ambiguousOwnerError
+		if goerrors.As(err, &ambiguousErr) {
+			r.setOwnerResolvedCondition(ctx, err)
+		}
 		return ctrl.Result{}, err
 	}
 
-	if len(clusterRoleList.Items) < 1 {
-		err := errors.NewNotFound(
-			schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "ClusterRole"}, "potentialOwner")
-		logger.Error(err, "could not find ClusterRole owned by CSV to inherit operand")
-		return ctrl.Result{}, err
+	options, regions, err := r.discoverProvisioningOptions(ctx)
+	if err != nil {
+		// fall back to the static values of the registration file
+		logger.Error(err, "error discovering provisioning options from AWS")
 	}
 
 	instance := &dbaasoperator.DBaaSProvider{
@@ -129,12 +157,14 @@ func (r *DBaaSProviderReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			Name: providerCRName,
 		},
 	}
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, instance, func() error {
+	_, err = createOrApply(ctx, r.Client, instance, func(client.Object) error {
 		provider, err := readProviderCRFile(filepath.Join(r.DBaaSProviderCRFilePath, dbaasproviderCRFile))
 		if err != nil {
 			return err
 		}
-		bridgeProviderCR(instance, provider, clusterRoleList)
+		bridgeProviderCR(instance, provider, owner)
+		setProvisioningOptions(instance, options)
+		setProvisioningRegions(instance, regions)
 		return nil
 	})
 	if err != nil {
@@ -143,21 +173,378 @@ func (r *DBaaSProviderReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 	logger.Info("cluster-scoped resource created or updated")
 
+	if apimeta.FindStatusCondition(instance.Status.Conditions, providerConditionOwnerResolved) != nil {
+		apimeta.RemoveStatusCondition(&instance.Status.Conditions, providerConditionOwnerResolved)
+		if err := updateStatus(ctx, r.Client, instance); err != nil {
+			logger.Error(err, "error updating provider registration status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if r.ProvisioningOptionsRefreshInterval > 0 {
+		// refresh the provisioning options periodically, requeue bypasses the event filter
+		return ctrl.Result{RequeueAfter: r.ProvisioningOptionsRefreshInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// discoverProvisioningOptions queries the accounts of the ready Inventories allowing provisioning for the engines and instance classes that can be provisioned,
+// and returns the regions of the accounts. The registration is shared by the Inventories, so the options of their accounts are merged, and the Inventories failing
+// the discovery are skipped.
+func (r *DBaaSProviderReconciler) discoverProvisioningOptions(ctx context.Context) (provisioningOptions, []string, error) {
+	logger := log.FromContext(ctx)
+
+	if r.GetDescribeDBEngineVersionsAPI == nil || r.GetDescribeOrderableDBInstanceOptionsAPI == nil {
+		return nil, nil, nil
+	}
+
+	inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+	if err := r.List(ctx, inventoryList); err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(inventoryList.Items, func(i, j int) bool {
+		if inventoryList.Items[i].Namespace != inventoryList.Items[j].Namespace {
+			return inventoryList.Items[i].Namespace < inventoryList.Items[j].Namespace
+		}
+		return inventoryList.Items[i].Name < inventoryList.Items[j].Name
+	})
+
+	var options provisioningOptions
+	var regions []string
+	var discoveryErr error
+	for i := range inventoryList.Items {
+		inventory := &inventoryList.Items[i]
+		// the options of the read-only Inventories cannot be provisioned
+		if !apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionReady) ||
+			!isProvisioningInventory(inventory) || inventory.Spec.CredentialsRef == nil {
+			continue
+		}
+		inventoryOptions, region, err := r.discoverInventoryProvisioningOptions(ctx, inventory)
+		if err != nil {
+			logger.Error(err, "error discovering provisioning options of Inventory", logging.KeyInventory,
+				fmt.Sprintf("%s/%s", inventory.Namespace, inventory.Name))
+			discoveryErr = err
+			continue
+		}
+		// nothing can be provisioned in the region of an account without orderable engines
+		if len(inventoryOptions) == 0 {
+			continue
+		}
+		options = mergeProvisioningOptions(options, inventoryOptions)
+		if len(region) > 0 {
+			regions = append(regions, region)
+		}
+	}
+	if options == nil {
+		return nil, nil, discoveryErr
+	}
+	return options, uniqueSortedStrings(regions), nil
+}
+
+// discoverInventoryProvisioningOptions queries the account of the Inventory for the engines and instance classes that can be provisioned, and returns the region
+// of the account
+func (r *DBaaSProviderReconciler) discoverInventoryProvisioningOptions(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) (provisioningOptions, string, error) {
+	secret := &corev1.Secret{}
+	if err := getInventoryCredentials(ctx, r, inventory, secret); err != nil {
+		return nil, "", err
+	}
+	accessKey := string(secret.Data[awsAccessKeyID])
+	secretKey := string(secret.Data[awsSecretAccessKey])
+	region := string(secret.Data[awsRegion])
+
+	describeDBEngineVersions := r.GetDescribeDBEngineVersionsAPI(accessKey, secretKey, region)
+	describeOrderableDBInstanceOptions := r.GetDescribeOrderableDBInstanceOptionsAPI(accessKey, secretKey, region)
+
+	options := provisioningOptions{}
+	for _, engine := range []string{postgres, mysql, mariadb, oracleSe2, oracleSe2Cdb, sqlserverEx, sqlserverWeb, sqlserverSe, sqlserverEe} {
+		versionOutput, err := describeDBEngineVersions.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+			Engine:      pointer.String(engine),
+			DefaultOnly: true,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if versionOutput == nil || len(versionOutput.DBEngineVersions) == 0 {
+			continue
+		}
+		engineVersion := getDefaultEngineVersion(pointer.String(engine))
+		if engineVersion == nil {
+			engineVersion = versionOutput.DBEngineVersions[0].EngineVersion
+		}
+
+		var classes []string
+		found := map[string]bool{}
+		input := &rds.DescribeOrderableDBInstanceOptionsInput{
+			Engine:        pointer.String(engine),
+			EngineVersion: engineVersion,
+			MaxRecords:    pointer.Int32(100),
+		}
+		for {
+			output, err := describeOrderableDBInstanceOptions.DescribeOrderableDBInstanceOptions(ctx, input)
+			if err != nil {
+				return nil, "", err
+			}
+			if output == nil {
+				break
+			}
+			for _, o := range output.OrderableDBInstanceOptions {
+				if o.DBInstanceClass != nil && !found[*o.DBInstanceClass] {
+					found[*o.DBInstanceClass] = true
+					classes = append(classes, *o.DBInstanceClass)
+				}
+			}
+			if output.Marker == nil || len(*output.Marker) == 0 {
+				break
+			}
+			input.Marker = output.Marker
+		}
+		// the order of the options of the registration does not depend on the order of the AWS responses
+		sort.Strings(classes)
+		options[engine] = classes
+	}
+	return options, region, nil
+}
+
+// mergeProvisioningOptions adds the engines and instance classes of an Inventory account to the options of the other accounts
+func mergeProvisioningOptions(options, inventoryOptions provisioningOptions) provisioningOptions {
+	if options == nil {
+		options = provisioningOptions{}
+	}
+	for engine, classes := range inventoryOptions {
+		options[engine] = uniqueSortedStrings(append(options[engine], classes...))
+	}
+	return options
+}
+
+// uniqueSortedStrings returns the sorted values without duplicates
+func uniqueSortedStrings(values []string) []string {
+	result := []string{}
+	found := map[string]bool{}
+	for _, v := range values {
+		if !found[v] {
+			found[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// setProvisioningOptions restricts the engine and instance class options of the registration to the discovered values
+func setProvisioningOptions(instance *dbaasoperator.DBaaSProvider, options provisioningOptions) {
+	if len(options) == 0 {
+		return
+	}
+
+	if param, ok := instance.Spec.ProvisioningParameters[dbaasoperator.ProvisioningDatabaseType]; ok {
+		for i := range param.ConditionalData {
+			data := &param.ConditionalData[i]
+			var engines []dbaasoperator.Option
+			for _, o := range data.Options {
+				if _, ok := options[o.Value]; ok {
+					engines = append(engines, o)
+				}
+			}
+			data.Options = engines
+			data.DefaultValue = getDefaultOptionValue(data.DefaultValue, engines)
+		}
+		instance.Spec.ProvisioningParameters[dbaasoperator.ProvisioningDatabaseType] = param
+	}
+
+	if param, ok := instance.Spec.ProvisioningParameters[dbaasoperator.ProvisioningMachineType]; ok {
+		for i := range param.ConditionalData {
+			data := &param.ConditionalData[i]
+			for _, d := range data.Dependencies {
+				if d.Field != dbaasoperator.ProvisioningDatabaseType {
+					continue
+				}
+				if classes := options[d.Value]; len(classes) > 0 {
+					data.Options = make([]dbaasoperator.Option, 0, len(classes))
+					for _, c := range classes {
+						data.Options = append(data.Options, dbaasoperator.Option{Value: c})
+					}
+					data.DefaultValue = getDefaultOptionValue(data.DefaultValue, data.Options)
+				}
+			}
+		}
+		instance.Spec.ProvisioningParameters[dbaasoperator.ProvisioningMachineType] = param
+	}
+}
+
+// setProvisioningRegions sets the region options of the registration to the regions of the Inventory accounts, the Instances are provisioned in the region of
+// their Inventory
+func setProvisioningRegions(instance *dbaasoperator.DBaaSProvider, regions []string) {
+	if len(regions) == 0 {
+		return
+	}
+	if instance.Spec.ProvisioningParameters == nil {
+		instance.Spec.ProvisioningParameters = map[dbaasoperator.ProvisioningParameterType]dbaasoperator.ProvisioningParameter{}
+	}
+	data := dbaasoperator.ConditionalProvisioningParameterData{DefaultValue: regions[0]}
+	for _, region := range regions {
+		data.Options = append(data.Options, dbaasoperator.Option{Value: region})
+	}
+	instance.Spec.ProvisioningParameters[dbaasoperator.ProvisioningRegions] = dbaasoperator.ProvisioningParameter{
+		DisplayName:     "AWS Region",
+		HelpText:        "The geographical region of the provider account where the database instance is provisioned.",
+		ConditionalData: []dbaasoperator.ConditionalProvisioningParameterData{data},
+	}
+}
+
+func getDefaultOptionValue(defaultValue string, options []dbaasoperator.Option) string {
+	for _, o := range options {
+		if o.Value == defaultValue {
+			return defaultValue
+		}
+	}
+	if len(options) > 0 {
+		return options[0].Value
+	}
+	return ""
+}
+
+// getOwnerClusterRole returns the ClusterRole of the CSV when installed by OLM, otherwise the dedicated ClusterRole if found
+func (r *DBaaSProviderReconciler) getOwnerClusterRole(ctx context.Context) (*rbac.ClusterRole, error) {
+	logger := log.FromContext(ctx)
+
+	if len(r.operatorNameVersion) == 0 {
+		if len(r.OwnerClusterRoleName) == 0 {
+			return nil, nil
+		}
+		clusterRole := &rbac.ClusterRole{}
+		if err := r.Get(ctx, client.ObjectKey{Name: r.OwnerClusterRoleName}, clusterRole); err != nil {
+			if errors.IsNotFound(err) {
+				logger.Info("owner ClusterRole not found, the provider registration is created without owner", "ClusterRole", r.OwnerClusterRoleName)
+				return nil, nil
+			}
+			logger.Error(err, "unable to get owner ClusterRole")
+			return nil, err
+		}
+		return clusterRole, nil
+	}
+
+	opts := &client.ListOptions{
+		LabelSelector: label.SelectorFromSet(map[string]string{
+			"olm.owner":      r.operatorNameVersion,
+			"olm.owner.kind": "ClusterServiceVersion",
+		}),
+	}
+	clusterRoleList := &rbac.ClusterRoleList{}
+	if err := r.List(ctx, clusterRoleList, opts); err != nil {
+		logger.Error(err, "unable to list ClusterRoles to seek potential operand owners")
+		return nil, err
+	}
+
+	owner, err := selectOwnerClusterRole(clusterRoleList.Items, r.operatorNameVersion)
+	if err != nil {
+		logger.Error(err, "could not find ClusterRole owned by CSV to inherit operand")
+		return nil, err
+	}
+	return owner, nil
+}
+
+// ambiguousOwnerError is returned if several ClusterRoles of the CSV could own the provider registration
+type ambiguousOwnerError struct {
+	csvName    string
+	candidates []string
+}
+
+func (e *ambiguousOwnerError) Error() string {
+	return fmt.Sprintf("ClusterRoles %s of CSV %s are ambiguous owners of the provider registration",
+		strings.Join(e.candidates, ","), e.csvName)
+}
+
+// selectOwnerClusterRole picks the ClusterRole that OLM generated for the CSV, the name of which is prefixed by the CSV name,
+// the roles granting access to the provider registrations are preferred if there are several of them
+func selectOwnerClusterRole(clusterRoles []rbac.ClusterRole, csvName string) (*rbac.ClusterRole, error) {
+	var candidates []rbac.ClusterRole
+	for i := range clusterRoles {
+		if strings.HasPrefix(clusterRoles[i].Name, csvName+"-") {
+			candidates = append(candidates, clusterRoles[i])
+		}
+	}
+	if len(candidates) > 1 {
+		var providerRoles []rbac.ClusterRole
+		for i := range candidates {
+			if grantsProviderAccess(&candidates[i]) {
+				providerRoles = append(providerRoles, candidates[i])
+			}
+		}
+		if len(providerRoles) > 0 {
+			candidates = providerRoles
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, errors.NewNotFound(
+			schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "ClusterRole"}, "potentialOwner")
+	case 1:
+		return &candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i := range candidates {
+			names[i] = candidates[i].Name
+		}
+		sort.Strings(names)
+		return nil, &ambiguousOwnerError{csvName: csvName, candidates: names}
+	}
+}
+
+func grantsProviderAccess(clusterRole *rbac.ClusterRole) bool {
+	for _, rule := range clusterRole.Rules {
+		for _, group := range rule.APIGroups {
+			if group != dbaasoperator.GroupVersion.Group && group != rbac.APIGroupAll {
+				continue
+			}
+			for _, resource := range rule.Resources {
+				if resource == "dbaasproviders" || resource == rbac.ResourceAll {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// setOwnerResolvedCondition reports the owner selection failure on the provider registration, if it already exists
+func (r *DBaaSProviderReconciler) setOwnerResolvedCondition(ctx context.Context, err error) {
+	logger := log.FromContext(ctx)
+
+	provider := &dbaasoperator.DBaaSProvider{}
+	if e := r.Get(ctx, client.ObjectKey{Name: providerCRName}, provider); e != nil {
+		if !errors.IsNotFound(e) {
+			logger.Error(e, "error fetching provider registration")
+		}
+		return
+	}
+	apimeta.SetStatusCondition(&provider.Status.Conditions, metav1.Condition{
+		Type:    providerConditionOwnerResolved,
+		Status:  metav1.ConditionFalse,
+		Reason:  providerReasonAmbiguousOwner,
+		Message: err.Error(),
+	})
+	if e := updateStatus(ctx, r.Client, provider); e != nil {
+		logger.Error(e, "error updating provider registration status")
+	}
+}
+
 // bridgeProviderCR CR for RDS registration
-func bridgeProviderCR(instance *dbaasoperator.DBaaSProvider, provider *dbaasoperator.DBaaSProvider, clusterRoleList *rbac.ClusterRoleList) {
-	instance.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
-		{
-
-			APIVersion:         "rbac.authorization.k8s.io/v1",
-			Kind:               "ClusterRole",
-			UID:                clusterRoleList.Items[0].GetUID(),
-			Name:               clusterRoleList.Items[0].Name,
-			Controller:         pointer.BoolPtr(true),
-			BlockOwnerDeletion: pointer.BoolPtr(false),
-		},
+func bridgeProviderCR(instance *dbaasoperator.DBaaSProvider, provider *dbaasoperator.DBaaSProvider, owner *rbac.ClusterRole) {
+	if owner != nil {
+		instance.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
+			{
+
+				APIVersion:         "rbac.authorization.k8s.io/v1",
+				Kind:               "ClusterRole",
+				UID:                owner.GetUID(),
+				Name:               owner.Name,
+				Controller:         pointer.BoolPtr(true),
+				BlockOwnerDeletion: pointer.BoolPtr(false),
+			},
+		}
+	} else {
+		instance.ObjectMeta.OwnerReferences = nil
 	}
 	instance.ObjectMeta.Labels = labels
 	instance.Spec = provider.Spec
@@ -210,24 +597,55 @@ func (r *DBaaSProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.operatorInstallNamespace = operatorInstallNamespace
 	}
 
-	// envVar set for all operators
+	// envVar set for all operators installed by OLM
 	if operatorNameEnvVar, found := os.LookupEnv("OPERATOR_CONDITION_NAME"); !found {
-		err := fmt.Errorf("OPERATOR_CONDITION_NAME must be set")
-		logger.Error(err, "error fetching envVar")
-		return err
+		if len(r.DeploymentName) == 0 {
+			err := fmt.Errorf("OPERATOR_CONDITION_NAME must be set if the operator Deployment name is not set")
+			logger.Error(err, "error fetching envVar")
+			return err
+		}
+		logger.Info("OPERATOR_CONDITION_NAME not set, running without OLM", "Deployment", r.DeploymentName)
 	} else {
 		r.operatorNameVersion = operatorNameEnvVar
 	}
 
+	// reconcile the registration again when the file is updated, e.g. by a mounted ConfigMap
+	r.providerFileEvents = make(chan event.GenericEvent, 1)
+	if err := mgr.Add(&providerFileWatcher{
+		path:    filepath.Join(r.DBaaSProviderCRFilePath, dbaasproviderCRFile),
+		changed: r.providerFileChanged,
+	}); err != nil {
+		return err
+	}
+
 	customRateLimiter := workqueue.NewItemExponentialFailureRateLimiter(30*time.Second, 30*time.Minute)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{RateLimiter: customRateLimiter}).
-		For(&v1.Deployment{}).
-		WithEventFilter(r.ignoreOtherDeployments()).
+		For(&v1.Deployment{}, builder.WithPredicates(r.ignoreOtherDeployments())).
+		Watches(&source.Channel{Source: r.providerFileEvents}, &handler.EnqueueRequestForObject{}).
 		Complete(r)
 }
 
+func (r *DBaaSProviderReconciler) setDeployment(dep *v1.Deployment) {
+	r.deploymentMutex.Lock()
+	defer r.deploymentMutex.Unlock()
+	r.deployment = dep.DeepCopy()
+}
+
+// providerFileChanged queues the operator Deployment once it has been reconciled, a pending event already covers the change
+func (r *DBaaSProviderReconciler) providerFileChanged() {
+	r.deploymentMutex.Lock()
+	defer r.deploymentMutex.Unlock()
+	if r.deployment == nil {
+		return
+	}
+	select {
+	case r.providerFileEvents <- event.GenericEvent{Object: r.deployment.DeepCopy()}:
+	default:
+	}
+}
+
 //ignoreOtherDeployments  only on a 'create' event is issued for the deployment
 func (r *DBaaSProviderReconciler) ignoreOtherDeployments() predicate.Predicate {
 	return predicate.Funcs{
@@ -248,6 +666,9 @@ func (r *DBaaSProviderReconciler) ignoreOtherDeployments() predicate.Predicate {
 
 func (r *DBaaSProviderReconciler) evaluatePredicateObject(obj client.Object) bool {
 	lbls := obj.GetLabels()
+	if len(r.operatorNameVersion) == 0 {
+		return obj.GetNamespace() == r.operatorInstallNamespace && obj.GetName() == r.DeploymentName
+	}
 	if obj.GetNamespace() == r.operatorInstallNamespace {
 		if val, keyFound := lbls["olm.owner.kind"]; keyFound {
 			if val == "ClusterServiceVersion" {
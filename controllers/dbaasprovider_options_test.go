@@ -0,0 +1,171 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasoperator "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// regionDBInstanceClasses returns the orderable instance classes of each engine in the region of the client
+type regionDBInstanceClasses struct {
+	classes map[string]map[string][]string
+	region  string
+}
+
+func (r *regionDBInstanceClasses) DescribeDBEngineVersions(_ context.Context, params *rds.DescribeDBEngineVersionsInput,
+	_ ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+	output := &rds.DescribeDBEngineVersionsOutput{}
+	if _, ok := r.classes[r.region][*params.Engine]; ok {
+		output.DBEngineVersions = []types.DBEngineVersion{{Engine: params.Engine, EngineVersion: pointer.String("1.0")}}
+	}
+	return output, nil
+}
+
+func (r *regionDBInstanceClasses) DescribeOrderableDBInstanceOptions(_ context.Context, params *rds.DescribeOrderableDBInstanceOptionsInput,
+	_ ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error) {
+	output := &rds.DescribeOrderableDBInstanceOptionsOutput{}
+	for _, c := range r.classes[r.region][*params.Engine] {
+		output.OrderableDBInstanceOptions = append(output.OrderableDBInstanceOptions, types.OrderableDBInstanceOption{DBInstanceClass: pointer.String(c)})
+	}
+	return output, nil
+}
+
+var _ = Describe("ProvisioningOptions", func() {
+	It("should restrict the registration to the discovered engines and instance classes", func() {
+		provider, err := readProviderCRFile(filepath.Join("..", "rds", "dbaas", "dbaasprovider", dbaasproviderCRFile))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		setProvisioningOptions(provider, provisioningOptions{
+			postgres: {"db.t3.small", "db.m5.large"},
+			mysql:    {},
+		})
+
+		databaseType := provider.Spec.ProvisioningParameters[dbaasoperator.ProvisioningDatabaseType]
+		Expect(databaseType.ConditionalData).Should(HaveLen(1))
+		var engines []string
+		for _, o := range databaseType.ConditionalData[0].Options {
+			engines = append(engines, o.Value)
+		}
+		Expect(engines).Should(ConsistOf(postgres, mysql))
+
+		machineType := provider.Spec.ProvisioningParameters[dbaasoperator.ProvisioningMachineType]
+		for _, data := range machineType.ConditionalData {
+			Expect(data.Dependencies).Should(HaveLen(1))
+			switch data.Dependencies[0].Value {
+			case postgres:
+				Expect(data.Options).Should(Equal([]dbaasoperator.Option{{Value: "db.t3.small"}, {Value: "db.m5.large"}}))
+				Expect(data.DefaultValue).Should(Equal("db.t3.small"))
+			case mysql:
+				Expect(len(data.Options)).Should(BeNumerically(">", 0))
+				Expect(data.DefaultValue).Should(Equal("db.t3.micro"))
+			}
+		}
+	})
+
+	It("should keep the registration unchanged without discovered options", func() {
+		provider, err := readProviderCRFile(filepath.Join("..", "rds", "dbaas", "dbaasprovider", dbaasproviderCRFile))
+		Expect(err).ShouldNot(HaveOccurred())
+		expected := provider.DeepCopy()
+
+		setProvisioningOptions(provider, nil)
+		Expect(provider).Should(Equal(expected))
+	})
+	It("should set the regions of the Inventory accounts in the registration", func() {
+		provider, err := readProviderCRFile(filepath.Join("..", "rds", "dbaas", "dbaasprovider", dbaasproviderCRFile))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		setProvisioningRegions(provider, []string{"eu-west-1", "us-east-1"})
+
+		regions := provider.Spec.ProvisioningParameters[dbaasoperator.ProvisioningRegions]
+		Expect(regions.ConditionalData).Should(HaveLen(1))
+		Expect(regions.ConditionalData[0].Options).Should(Equal([]dbaasoperator.Option{{Value: "eu-west-1"}, {Value: "us-east-1"}}))
+		Expect(regions.ConditionalData[0].DefaultValue).Should(Equal("eu-west-1"))
+	})
+
+	It("should merge the options of the accounts of the ready Inventories allowing provisioning", func() {
+		newInventory := func(name, region string, ready bool, annotations map[string]string) []client.Object {
+			status := metav1.ConditionFalse
+			if ready {
+				status = metav1.ConditionTrue
+			}
+			inventory := &rdsdbaasv1alpha1.RDSInventory{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: name, Annotations: annotations},
+				Spec:       dbaasoperator.DBaaSInventorySpec{CredentialsRef: &dbaasoperator.LocalObjectReference{Name: name + "-credentials"}},
+				Status: dbaasoperator.DBaaSInventoryStatus{
+					Conditions: []metav1.Condition{{Type: inventoryConditionReady, Status: status}},
+				},
+			}
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: name + "-credentials"},
+				Data: map[string][]byte{
+					awsAccessKeyID:     []byte("access-key-" + name),
+					awsSecretAccessKey: []byte("secret-key-" + name),
+					awsRegion:          []byte(region),
+				},
+			}
+			return []client.Object{inventory, secret}
+		}
+		var objects []client.Object
+		objects = append(objects, newInventory("inventory-a", "us-east-1", true, nil)...)
+		objects = append(objects, newInventory("inventory-b", "eu-west-1", true, nil)...)
+		objects = append(objects, newInventory("inventory-c", "ap-south-1", false, nil)...)
+		objects = append(objects, newInventory("inventory-d", "ca-central-1", true,
+			map[string]string{rdsdbaasv1alpha1.AllowProvisioningAnnotation: "false"})...)
+		objects = append(objects, newInventory("inventory-e", "sa-east-1", true, nil)...)
+
+		classes := map[string]map[string][]string{
+			"us-east-1":    {postgres: {"db.t3.micro", "db.m5.large"}},
+			"eu-west-1":    {postgres: {"db.t3.micro", "db.r5.large"}, mysql: {"db.t3.small"}},
+			"ap-south-1":   {mariadb: {"db.t3.micro"}},
+			"ca-central-1": {mariadb: {"db.t3.micro"}},
+		}
+		r := &DBaaSProviderReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build(),
+			GetDescribeDBEngineVersionsAPI: func(_, _, region string) controllersrds.DescribeDBEngineVersionsAPI {
+				return &regionDBInstanceClasses{classes: classes, region: region}
+			},
+			GetDescribeOrderableDBInstanceOptionsAPI: func(_, _, region string) controllersrds.DescribeOrderableDBInstanceOptionsAPI {
+				return &regionDBInstanceClasses{classes: classes, region: region}
+			},
+		}
+
+		options, regions, err := r.discoverProvisioningOptions(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(options).Should(Equal(provisioningOptions{
+			postgres: {"db.m5.large", "db.r5.large", "db.t3.micro"},
+			mysql:    {"db.t3.small"},
+		}))
+		Expect(regions).Should(Equal([]string{"eu-west-1", "us-east-1"}))
+	})
+})
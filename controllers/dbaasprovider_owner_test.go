@@ -0,0 +1,76 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("OwnerClusterRole", func() {
+	csvName := "rds-dbaas-operator.v0.3.0"
+	clusterRole := func(name string, resources ...string) rbac.ClusterRole {
+		return rbac.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules: []rbac.PolicyRule{
+				{
+					APIGroups: []string{"dbaas.redhat.com"},
+					Resources: resources,
+					Verbs:     []string{"get"},
+				},
+			},
+		}
+	}
+
+	It("should select the ClusterRole generated for the CSV", func() {
+		owner, err := selectOwnerClusterRole([]rbac.ClusterRole{
+			clusterRole("other-operator.v0.1.0-abc", "dbaasproviders"),
+			clusterRole(csvName+"-def", "rdsinventories"),
+		}, csvName)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(owner.Name).Should(Equal(csvName + "-def"))
+	})
+
+	It("should prefer the ClusterRole granting access to the provider registrations", func() {
+		owner, err := selectOwnerClusterRole([]rbac.ClusterRole{
+			clusterRole(csvName+"-abc", "rdsinventories"),
+			clusterRole(csvName+"-def", "dbaasproviders"),
+		}, csvName)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(owner.Name).Should(Equal(csvName + "-def"))
+	})
+
+	It("should fail if the ClusterRoles are ambiguous", func() {
+		_, err := selectOwnerClusterRole([]rbac.ClusterRole{
+			clusterRole(csvName+"-def", "dbaasproviders"),
+			clusterRole(csvName+"-abc", "dbaasproviders"),
+		}, csvName)
+		Expect(err).Should(MatchError("ClusterRoles " + csvName + "-abc," + csvName + "-def of CSV " + csvName +
+			" are ambiguous owners of the provider registration"))
+	})
+
+	It("should fail if no ClusterRole is generated for the CSV", func() {
+		_, err := selectOwnerClusterRole([]rbac.ClusterRole{
+			clusterRole("other-operator.v0.1.0-abc", "dbaasproviders"),
+		}, csvName)
+		Expect(errors.IsNotFound(err)).Should(BeTrue())
+	})
+})
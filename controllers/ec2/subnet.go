@@ -0,0 +1,47 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type DescribeSubnetsAPI interface {
+	DescribeSubnets(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+}
+
+type sdkV2DescribeSubnets struct {
+	client *ec2.Client
+}
+
+func NewDescribeSubnets(accessKey, secretKey, region string) DescribeSubnetsAPI {
+	awsClient := ec2.New(ec2.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2DescribeSubnets{
+		client: awsClient,
+	}
+}
+
+func (d *sdkV2DescribeSubnets) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return d.client.DescribeSubnets(ctx, params, optFns...)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+
+	"k8s.io/utils/pointer"
+
+	controllersec2 "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+var securityGroupVpcs = map[string]string{
+	"sg-a": "vpc-a",
+	"sg-b": "vpc-b",
+}
+
+type mockDescribeSecurityGroups struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeSecurityGroups(accessKey, secretKey, region string) controllersec2.DescribeSecurityGroupsAPI {
+	return &mockDescribeSecurityGroups{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeSecurityGroups) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	var securityGroups []types.SecurityGroup
+	for _, id := range params.GroupIds {
+		if vpc, ok := securityGroupVpcs[id]; ok {
+			securityGroups = append(securityGroups, types.SecurityGroup{
+				GroupId: pointer.String(id),
+				VpcId:   pointer.String(vpc),
+			})
+		}
+	}
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: securityGroups}, nil
+}
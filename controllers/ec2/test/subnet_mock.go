@@ -0,0 +1,60 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+
+	"k8s.io/utils/pointer"
+
+	controllersec2 "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+type mockDescribeSubnets struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeSubnets(accessKey, secretKey, region string) controllersec2.DescribeSubnetsAPI {
+	return &mockDescribeSubnets{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeSubnets) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{
+		Subnets: []types.Subnet{
+			{
+				SubnetId:         pointer.String("subnet-private-a"),
+				AvailabilityZone: pointer.String("us-east-1a"),
+				VpcId:            pointer.String("vpc-a"),
+				Tags:             []types.Tag{{Key: pointer.String("kubernetes.io/role/internal-elb"), Value: pointer.String("")}},
+			},
+			{
+				SubnetId:         pointer.String("subnet-private-b"),
+				AvailabilityZone: pointer.String("us-east-1b"),
+				VpcId:            pointer.String("vpc-a"),
+				Tags:             []types.Tag{{Key: pointer.String("kubernetes.io/role/internal-elb"), Value: pointer.String("")}},
+			},
+			{
+				SubnetId:         pointer.String("subnet-public-a"),
+				AvailabilityZone: pointer.String("us-east-1a"),
+				VpcId:            pointer.String("vpc-a"),
+				Tags:             []types.Tag{{Key: pointer.String("kubernetes.io/role/elb"), Value: pointer.String("")}},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// applyClient is a fake client applying the server-side apply patches as creates or updates of the whole object, the
+// field managers are not tracked and the status of the existing object is kept as by the status subresource. The
+// applies not forcing the ownership fail with a conflict if they set one of the conflicts fields, in the format of
+// the managed fields paths.
+type applyClient struct {
+	client.Client
+	conflicts []string
+	// the number of applies and of forced applies
+	applies, forced int
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	options := &client.PatchOptions{}
+	options.ApplyOptions(opts)
+	force := options.Force != nil && *options.Force
+	c.applies++
+	if force {
+		c.forced++
+	}
+
+	u := obj.(*unstructured.Unstructured)
+	if !force {
+		var causes []metav1.StatusCause
+		for _, path := range getFieldPaths(u.Object, nil) {
+			for _, conflict := range c.conflicts {
+				if isConflictingField(formatFieldPath(path), []string{conflict}) {
+					causes = append(causes, metav1.StatusCause{Type: metav1.CauseTypeFieldManagerConflict,
+						Message: fmt.Sprintf("conflict with \"kubectl\": %s", conflict), Field: conflict})
+				}
+			}
+		}
+		if len(causes) > 0 {
+			return &errors.StatusError{ErrStatus: metav1.Status{Status: metav1.StatusFailure, Code: http.StatusConflict,
+				Reason: metav1.StatusReasonConflict, Details: &metav1.StatusDetails{Name: u.GetName(), Causes: causes}}}
+		}
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return c.Create(ctx, u)
+	}
+	u.SetResourceVersion(existing.GetResourceVersion())
+	if status, ok := existing.Object["status"]; ok {
+		u.Object["status"] = status
+	}
+	return c.Update(ctx, u)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the freeze windows of the Inventory, in addition to the freeze windows of the operator
+	inventoryFreezeWindowsAnnotation = "rds.dbaas.redhat.com/freeze-windows"
+
+	conditionFreezeWindow = "FreezeWindow"
+
+	freezeWindowReasonActive = "FreezeWindowActive"
+
+	freezeWindowMessageActive = "Modifications are deferred until %s by freeze window %q"
+)
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// FreezeWindow is a period starting at each time matching a cron expression, evaluated in UTC, during which the
+// operator does not modify the AWS resources.
+type FreezeWindow struct {
+	expression string
+	duration   time.Duration
+
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// the day of the month and the day of the week match any day if not restricted as in cron
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// ParseFreezeWindows parses the freeze windows separated by semicolons, each made of the five fields of a cron
+// expression followed by a duration, for example "0 8 * * mon-fri 10h;0 0 20 dec * 336h".
+func ParseFreezeWindows(value string) ([]FreezeWindow, error) {
+	var windows []FreezeWindow
+	for _, w := range strings.Split(value, ";") {
+		if len(strings.TrimSpace(w)) == 0 {
+			continue
+		}
+		window, err := parseFreezeWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseFreezeWindow(value string) (FreezeWindow, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 6 {
+		return FreezeWindow{}, fmt.Errorf("freeze window %q must be a cron expression of 5 fields followed by a duration", value)
+	}
+	window := FreezeWindow{expression: strings.Join(fields, " ")}
+	var err error
+	if window.duration, err = time.ParseDuration(fields[5]); err != nil || window.duration <= 0 {
+		return FreezeWindow{}, fmt.Errorf("duration of freeze window %q is invalid", value)
+	}
+	if window.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return FreezeWindow{}, fmt.Errorf("minute of freeze window %q is invalid: %w", value, err)
+	}
+	if window.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return FreezeWindow{}, fmt.Errorf("hour of freeze window %q is invalid: %w", value, err)
+	}
+	if window.daysOfMonth, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return FreezeWindow{}, fmt.Errorf("day of month of freeze window %q is invalid: %w", value, err)
+	}
+	if window.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return FreezeWindow{}, fmt.Errorf("month of freeze window %q is invalid: %w", value, err)
+	}
+	if window.daysOfWeek, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return FreezeWindow{}, fmt.Errorf("day of week of freeze window %q is invalid: %w", value, err)
+	}
+	// 7 is Sunday as 0
+	if window.daysOfWeek&(1<<7) != 0 {
+		window.daysOfWeek |= 1
+	}
+	window.anyDayOfMonth = strings.HasPrefix(fields[2], "*")
+	window.anyDayOfWeek = strings.HasPrefix(fields[4], "*")
+	return window, nil
+}
+
+// parseCronField returns the bits of the values of a comma-separated list of values, ranges and steps of a cron field
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	parseValue := func(s string) (int, error) {
+		if v, ok := names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("value %s is not between %d and %d", s, min, max)
+		}
+		return v, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("step %s is invalid", part[i+1:])
+			}
+			step = s
+			part = part[:i]
+		}
+		start, end := min, max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			if start, err = parseValue(bounds[0]); err != nil {
+				return 0, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseValue(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				end = max
+			}
+			if end < start {
+				return 0, fmt.Errorf("range %s is invalid", part)
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (w FreezeWindow) matchesDay(t time.Time) bool {
+	dom := w.daysOfMonth&(1<<uint(t.Day())) != 0
+	dow := w.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if w.anyDayOfMonth || w.anyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// lastStart returns the last start of the window that is not older than its duration
+func (w FreezeWindow) lastStart(now time.Time) (time.Time, bool) {
+	t := now.UTC().Truncate(time.Minute)
+	limit := now.UTC().Add(-w.duration)
+	for t.After(limit) {
+		if w.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+			continue
+		}
+		if !w.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+			continue
+		}
+		if w.hours&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(-time.Minute)
+			continue
+		}
+		if w.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// String returns the cron expression and the duration of the window
+func (w FreezeWindow) String() string {
+	return w.expression
+}
+
+// getActiveFreezeWindow returns the end and the expression of the active freeze window of the operator or of the
+// Inventory that ends last, or a zero time if none is active
+func getActiveFreezeWindow(windows []FreezeWindow, inventory *rdsdbaasv1alpha1.RDSInventory, now time.Time) (time.Time, string, error) {
+	all := windows
+	if value, ok := inventory.Annotations[inventoryFreezeWindowsAnnotation]; ok {
+		inventoryWindows, err := ParseFreezeWindows(value)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("value of annotation %s is invalid: %w", inventoryFreezeWindowsAnnotation, err)
+		}
+		all = append(append([]FreezeWindow{}, windows...), inventoryWindows...)
+	}
+
+	var until time.Time
+	var expression string
+	for _, w := range all {
+		if start, ok := w.lastStart(now); ok && start.Add(w.duration).After(until) {
+			until = start.Add(w.duration)
+			expression = w.String()
+		}
+	}
+	return until, expression, nil
+}
+
+// setFreezeWindowCondition sets the FreezeWindow condition of the Instance while a freeze window is active and
+// removes it otherwise
+func setFreezeWindowCondition(rdsInstance *rdsdbaasv1alpha1.RDSInstance, until time.Time, expression string) {
+	if until.IsZero() {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, conditionFreezeWindow)
+		return
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    conditionFreezeWindow,
+		Status:  metav1.ConditionTrue,
+		Reason:  freezeWindowReasonActive,
+		Message: fmt.Sprintf(freezeWindowMessageActive, until.Format(time.RFC3339), expression),
+	})
+}
+
+// requeueAtFreezeWindowEnd requeues the reconciliation at the end of the freeze window unless it is requeued earlier
+func requeueAtFreezeWindowEnd(result ctrl.Result, until time.Time, now time.Time) ctrl.Result {
+	if until.IsZero() || result.Requeue && result.RequeueAfter == 0 {
+		return result
+	}
+	if wait := until.Sub(now); result.RequeueAfter == 0 || wait < result.RequeueAfter {
+		result.RequeueAfter = wait
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Freeze windows", func() {
+	It("should parse the freeze windows", func() {
+		windows, err := ParseFreezeWindows("0 8 * * mon-fri 10h; 30 */6 1,15 jan-mar,dec 0,7 1h30m")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(windows).Should(HaveLen(2))
+		Expect(windows[0].String()).Should(Equal("0 8 * * mon-fri 10h"))
+
+		windows, err = ParseFreezeWindows("")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(windows).Should(BeEmpty())
+
+		for _, invalid := range []string{"0 8 * * 10h", "0 8 * * mon-fri", "0 8 * * mon-fri -1h", "60 8 * * * 1h",
+			"0 8 32 * * 1h", "0 8 * foo * 1h", "0 8 * * fri-mon 1h", "*/0 8 * * * 1h"} {
+			_, err = ParseFreezeWindows(invalid)
+			Expect(err).Should(HaveOccurred(), invalid)
+		}
+	})
+
+	It("should return the end of the active freeze window", func() {
+		windows, err := ParseFreezeWindows("0 8 * * mon-fri 10h")
+		Expect(err).ShouldNot(HaveOccurred())
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+
+		// Monday
+		monday := time.Date(2022, 10, 3, 0, 0, 0, 0, time.UTC)
+		until, window, err := getActiveFreezeWindow(windows, inventory, monday.Add(9*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(until).Should(Equal(monday.Add(18 * time.Hour)))
+		Expect(window).Should(Equal("0 8 * * mon-fri 10h"))
+
+		until, _, err = getActiveFreezeWindow(windows, inventory, monday.Add(18*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(until.IsZero()).Should(BeTrue())
+		until, _, err = getActiveFreezeWindow(windows, inventory, monday.Add(7*time.Hour+59*time.Minute))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(until.IsZero()).Should(BeTrue())
+
+		// Saturday
+		until, _, err = getActiveFreezeWindow(windows, inventory, monday.Add(5*24*time.Hour+9*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(until.IsZero()).Should(BeTrue())
+
+		// the freeze window of the Inventory across the end of the year ends last
+		inventory.Annotations = map[string]string{inventoryFreezeWindowsAnnotation: "0 0 20 dec * 336h"}
+		newYear := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+		until, window, err = getActiveFreezeWindow(windows, inventory, newYear)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(until).Should(Equal(time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)))
+		Expect(window).Should(Equal("0 0 20 dec * 336h"))
+
+		inventory.Annotations[inventoryFreezeWindowsAnnotation] = "invalid"
+		_, _, err = getActiveFreezeWindow(windows, inventory, newYear)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should set the freeze window condition and requeue at its end", func() {
+		now := time.Date(2022, 10, 3, 9, 0, 0, 0, time.UTC)
+		until := now.Add(time.Hour)
+		instance := &rdsdbaasv1alpha1.RDSInstance{}
+		setFreezeWindowCondition(instance, until, "0 8 * * mon-fri 10h")
+		condition := apimeta.FindStatusCondition(instance.Status.Conditions, conditionFreezeWindow)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(freezeWindowReasonActive))
+		setFreezeWindowCondition(instance, time.Time{}, "")
+		Expect(apimeta.FindStatusCondition(instance.Status.Conditions, conditionFreezeWindow)).Should(BeNil())
+
+		Expect(requeueAtFreezeWindowEnd(ctrl.Result{}, until, now)).Should(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+		Expect(requeueAtFreezeWindowEnd(ctrl.Result{RequeueAfter: time.Minute}, until, now)).Should(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		Expect(requeueAtFreezeWindowEnd(ctrl.Result{Requeue: true}, until, now)).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(requeueAtFreezeWindowEnd(ctrl.Result{}, time.Time{}, now)).Should(Equal(ctrl.Result{}))
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// the annotation of the Instance with the identifier generated for its DB instance, set before the DB instance is
+// created so that an interrupted creation is resumed with the same identifier
+const dbInstanceIdentifierAnnotation = "rds.dbaas.redhat.com/db-instance-identifier"
+
+// GracefulShutdown lets the reconciles in progress when the operator is stopped complete their AWS calls and record
+// them in the status, rather than being cancelled with the context of the manager. Once stopped, no reconcile is
+// started and the reconciles in progress are cancelled after the timeout.
+type GracefulShutdown struct {
+	stop    <-chan struct{}
+	timeout time.Duration
+
+	inFlight sync.WaitGroup
+}
+
+// NewGracefulShutdown returns nil if the timeout is not positive, which cancels the reconciles in progress as soon as
+// the operator is stopped
+func NewGracefulShutdown(ctx context.Context, timeout time.Duration) *GracefulShutdown {
+	if timeout <= 0 {
+		return nil
+	}
+	s := &GracefulShutdown{
+		stop:    ctx.Done(),
+		timeout: timeout,
+	}
+	go func() {
+		<-s.stop
+		logger := log.FromContext(ctx).WithName("graceful-shutdown")
+		logger.Info("Operator stopping, waiting for the reconciles in progress", "timeout", timeout)
+		done := make(chan struct{})
+		go func() {
+			s.inFlight.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			logger.Info("Reconciles in progress completed")
+		case <-time.After(timeout):
+			logger.Info("Reconciles in progress cancelled after the timeout")
+		}
+	}()
+	return s
+}
+
+// reconciler returns the reconciler running the reconciles with a context that is only cancelled once the timeout
+// expires after the operator is stopped
+func (s *GracefulShutdown) reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	if s == nil {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		select {
+		case <-s.stop:
+			// stopped, the request is reconciled by the next operator instance
+			return ctrl.Result{}, nil
+		default:
+		}
+
+		s.inFlight.Add(1)
+		defer s.inFlight.Done()
+
+		reconcileCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+		defer cancel()
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-done:
+				return
+			case <-s.stop:
+			}
+			timer := time.NewTimer(s.timeout)
+			defer timer.Stop()
+			select {
+			case <-done:
+			case <-timer.C:
+				cancel()
+			}
+		}()
+		return r.Reconcile(reconcileCtx, req)
+	})
+}
+
+// detachedContext keeps the values of its parent without its cancellation and deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
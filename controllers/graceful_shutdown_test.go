@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Graceful shutdown", func() {
+	It("should not wrap the reconciler if disabled", func() {
+		Expect(NewGracefulShutdown(context.Background(), 0)).Should(BeNil())
+		var s *GracefulShutdown
+		r := &RDSInstanceReconciler{}
+		Expect(s.reconciler(r)).Should(BeIdenticalTo(r))
+	})
+
+	It("should let the reconciles in progress complete once stopped", func() {
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		s := NewGracefulShutdown(ctx, time.Minute)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		reconciles := 0
+		r := s.reconciler(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			reconciles++
+			close(started)
+			<-release
+			return ctrl.Result{}, ctx.Err()
+		}))
+
+		result := make(chan error)
+		go func() {
+			_, err := r.Reconcile(ctx, ctrl.Request{})
+			result <- err
+		}()
+		<-started
+		stop()
+		close(release)
+		Expect(<-result).ShouldNot(HaveOccurred())
+
+		// no reconcile is started once stopped
+		_, err := r.Reconcile(ctx, ctrl.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(reconciles).Should(Equal(1))
+	})
+
+	It("should cancel the reconciles in progress after the timeout", func() {
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		s := NewGracefulShutdown(ctx, 10*time.Millisecond)
+
+		started := make(chan struct{})
+		r := s.reconciler(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			close(started)
+			<-ctx.Done()
+			return ctrl.Result{}, ctx.Err()
+		}))
+
+		result := make(chan error)
+		go func() {
+			_, err := r.Reconcile(ctx, ctrl.Request{})
+			result <- err
+		}()
+		<-started
+		stop()
+		Eventually(result).Should(Receive(MatchError(context.Canceled)))
+	})
+})
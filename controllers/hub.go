@@ -0,0 +1,214 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the service info key of the TLS requirement of a DB service published by the hub, the spokes read it instead
+	// of the parameter group of the DB service
+	serviceInfoTLSRequired = "tlsRequired"
+
+	hubReaderNameTemplate = "%s-hub-reader"
+)
+
+// NewHubCluster returns the hub cluster that runs the Inventories and the DB services of the Connections of a spoke cluster,
+// the spoke only runs the Connection controller, so the AWS credentials are only kept in the hub
+func NewHubCluster(kubeconfig string, scheme *runtime.Scheme, newCache cache.NewCacheFunc) (cluster.Cluster, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.New(config, func(o *cluster.Options) {
+		o.Scheme = scheme
+		o.NewCache = newCache
+	})
+}
+
+// getServiceInfoTLSRequired returns the TLS requirement of the DB service published by the hub, nil is returned if the
+// requirement is not known
+func getServiceInfoTLSRequired(serviceInfo map[string]string) *bool {
+	value, ok := serviceInfo[serviceInfoTLSRequired]
+	if !ok {
+		return nil
+	}
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &required
+}
+
+// serveSpokes publishes the TLS requirements of the DB services of the Inventory in their service info, and grants
+// the spoke user the read of the master password Secrets of the DB services only. The AWS credentials of the Inventory
+// stay in the hub, the spokes do not call AWS.
+func (r *RDSInventoryReconciler) serveSpokes(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	services []dbaasv1beta1.DatabaseService, accessKey, secretKey, region string) error {
+	if len(r.SpokeUser) == 0 {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	serviceInfos := map[string]map[string]string{}
+	for _, service := range services {
+		serviceInfos[fmt.Sprintf("%s/%s", getDatabaseServiceType(service), service.ServiceID)] = service.ServiceInfo
+	}
+
+	passwordSecrets := map[string]bool{}
+	addPasswordSecret := func(namespace, name string) {
+		// the AWS credentials of the Inventory are never granted
+		if (len(namespace) == 0 || namespace == inventory.Namespace) && len(name) > 0 &&
+			(inventory.Spec.CredentialsRef == nil || name != inventory.Spec.CredentialsRef.Name) {
+			passwordSecrets[name] = true
+		}
+	}
+	publishTLSRequired := func(serviceInfo map[string]string, dbService client.Object, engine *string) {
+		if serviceInfo == nil || engine == nil {
+			return
+		}
+		parameterName := getTLSParameterName(*engine)
+		groupName, cluster := getParameterGroupName(dbService)
+		if len(parameterName) == 0 || len(groupName) == 0 ||
+			(cluster && r.GetDescribeDBClusterParametersAPI == nil) || (!cluster && r.GetDescribeDBParametersAPI == nil) {
+			return
+		}
+		required, err := describeTLSRequired(ctx, &r.tlsRequirements, r.GetDescribeDBParametersAPI, r.GetDescribeDBClusterParametersAPI,
+			r.CircuitBreaker, inventory, parameterName, groupName, cluster, accessKey, secretKey, region)
+		if err != nil {
+			// the requirement is not known to the spokes until the next sync
+			logger.Error(err, "Failed to get TLS requirement of DB Service from its parameter group", "DB Service", dbService.GetName())
+			return
+		}
+		serviceInfo[serviceInfoTLSRequired] = strconv.FormatBool(required)
+	}
+
+	dbInstanceList := &rdsv1alpha1.DBInstanceList{}
+	if err := r.List(ctx, dbInstanceList, client.InNamespace(inventory.Namespace)); err != nil {
+		return err
+	}
+	for i := range dbInstanceList.Items {
+		dbInstance := &dbInstanceList.Items[i]
+		if dbInstance.Spec.DBInstanceIdentifier == nil {
+			continue
+		}
+		serviceInfo, ok := serviceInfos[fmt.Sprintf("%s/%s", instanceType, *dbInstance.Spec.DBInstanceIdentifier)]
+		if !ok {
+			continue
+		}
+		publishTLSRequired(serviceInfo, dbInstance, dbInstance.Spec.Engine)
+		if p := dbInstance.Spec.MasterUserPassword; p != nil {
+			addPasswordSecret(p.Namespace, p.Name)
+		}
+	}
+
+	dbClusterList := &rdsv1alpha1.DBClusterList{}
+	if err := r.List(ctx, dbClusterList, client.InNamespace(inventory.Namespace)); err != nil {
+		return err
+	}
+	for i := range dbClusterList.Items {
+		dbCluster := &dbClusterList.Items[i]
+		if dbCluster.Spec.DBClusterIdentifier == nil {
+			continue
+		}
+		serviceInfo, ok := serviceInfos[fmt.Sprintf("%s/%s", clusterType, *dbCluster.Spec.DBClusterIdentifier)]
+		if !ok {
+			continue
+		}
+		publishTLSRequired(serviceInfo, dbCluster, dbCluster.Spec.Engine)
+		if p := dbCluster.Spec.MasterUserPassword; p != nil {
+			addPasswordSecret(p.Namespace, p.Name)
+		}
+	}
+
+	var secretNames []string
+	for name := range passwordSecrets {
+		secretNames = append(secretNames, name)
+	}
+	sort.Strings(secretNames)
+	return r.syncHubReaderRole(ctx, inventory, secretNames)
+}
+
+// getHubReaderRules returns the rules of the Role of the spoke user in the namespace of an Inventory, the user can only
+// get the master password Secrets of the DB services. No rule is returned without Secrets, as a rule without resource
+// names would grant all the Secrets of the namespace.
+func getHubReaderRules(secretNames []string) []rbacv1.PolicyRule {
+	if len(secretNames) == 0 {
+		return nil
+	}
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			Verbs:         []string{"get"},
+			ResourceNames: secretNames,
+		},
+	}
+}
+
+// syncHubReaderRole creates or updates the Role and the RoleBinding of the spoke user in the namespace of the Inventory
+func (r *RDSInventoryReconciler) syncHubReaderRole(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	secretNames []string) error {
+	name := fmt.Sprintf(hubReaderNameTemplate, inventory.Name)
+
+	role := &rbacv1.Role{}
+	role.SetName(name)
+	role.SetNamespace(inventory.Namespace)
+	if _, err := createOrApply(ctx, r.Client, role, func(client.Object) error {
+		role.Rules = getHubReaderRules(secretNames)
+		return ctrl.SetControllerReference(inventory, role, r.Scheme)
+	}); err != nil {
+		return err
+	}
+
+	roleBinding := &rbacv1.RoleBinding{}
+	roleBinding.SetName(name)
+	roleBinding.SetNamespace(inventory.Namespace)
+	_, err := createOrApply(ctx, r.Client, roleBinding, func(client.Object) error {
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		}
+		roleBinding.Subjects = []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     r.SpokeUser,
+			},
+		}
+		return ctrl.SetControllerReference(inventory, roleBinding, r.Scheme)
+	})
+	return err
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// hubCluster is the hub of a spoke, the spoke does not call it to look up the TLS requirements
+type hubCluster struct {
+	cluster.Cluster
+}
+
+var _ = Describe("Hub", func() {
+	It("should parse the TLS requirement published by the hub", func() {
+		Expect(getServiceInfoTLSRequired(nil)).Should(BeNil())
+		Expect(getServiceInfoTLSRequired(map[string]string{serviceInfoTLSRequired: "invalid"})).Should(BeNil())
+		Expect(getServiceInfoTLSRequired(map[string]string{serviceInfoTLSRequired: "true"})).Should(Equal(pointer.Bool(true)))
+		Expect(getServiceInfoTLSRequired(map[string]string{serviceInfoTLSRequired: "false"})).Should(Equal(pointer.Bool(false)))
+	})
+
+	It("should only grant the master password Secrets to the spokes", func() {
+		Expect(getHubReaderRules(nil)).Should(BeEmpty())
+
+		rules := getHubReaderRules([]string{"instance-a-credentials", "cluster-a-credentials"})
+		Expect(rules).Should(HaveLen(1))
+		Expect(rules[0].Resources).Should(Equal([]string{"secrets"}))
+		Expect(rules[0].Verbs).Should(Equal([]string{"get"}))
+		Expect(rules[0].ResourceNames).Should(Equal([]string{"instance-a-credentials", "cluster-a-credentials"}))
+	})
+
+	It("should read the TLS requirement of the DB service from the hub in spoke mode", func() {
+		r := &RDSConnectionReconciler{Hub: &hubCluster{}}
+		inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory"}}
+		dbInstance := &rdsv1alpha1.DBInstance{
+			Status: rdsv1alpha1.DBInstanceStatus{
+				DBParameterGroups: []*rdsv1alpha1.DBParameterGroupStatus_SDK{{DBParameterGroupName: pointer.String("tls-required")}},
+			},
+		}
+
+		required, err := r.isTLSRequired(context.Background(), inventory, dbInstance, map[string]string{serviceInfoTLSRequired: "true"}, "postgres")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(required).Should(Equal(pointer.Bool(true)))
+
+		required, err = r.isTLSRequired(context.Background(), inventory, dbInstance, map[string]string{}, "postgres")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(required).Should(BeNil())
+	})
+
+	It("should not read the master credentials from AWS Secrets Manager in spoke mode", func() {
+		r := &RDSConnectionReconciler{Hub: &hubCluster{}}
+		inventory := &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "operator",
+				Name:        "inventory",
+				Annotations: map[string]string{masterCredentialsStoreAnnotation: masterCredentialsStoreSecretsManager},
+			},
+		}
+		_, err := r.getMasterCredentials(context.Background(), inventory, "instance-a")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	inventoryConditionCached = "Cached"
+
+	inventoryStatusReasonCached = "LastSync"
+
+	inventoryStatusMessageCached = "The DB services of the last sync are served while the Inventory syncs again after the operator restarted"
+)
+
+// isInventorySyncedSinceStart returns whether the Inventory has synced successfully since the operator started
+func isInventorySyncedSinceStart(namespace, name string) bool {
+	v, ok := inventorySyncRecords.Load(namespace + "/" + name)
+	return ok && !v.(inventorySyncRecord).lastSync.IsZero()
+}
+
+// servesCachedSync keeps the sync condition and the DB services of the last successful sync of the Inventory, which
+// are persisted in its status, while it syncs again after the operator restarted, for example while the RDS controller
+// restarts with the operator upgrade. Its Cached condition is set while they are served, the sync is reset as usual
+// once the Inventory has synced since the operator started.
+func servesCachedSync(inventory *rdsdbaasv1alpha1.RDSInventory) bool {
+	if isInventorySyncedSinceStart(inventory.Namespace, inventory.Name) ||
+		!apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionReady) {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionCached)
+		return false
+	}
+	apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+		Type:    inventoryConditionCached,
+		Status:  metav1.ConditionTrue,
+		Reason:  inventoryStatusReasonCached,
+		Message: inventoryStatusMessageCached,
+	})
+	return true
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InventoryCache", func() {
+	newInventory := func(name string, synced metav1.ConditionStatus) *rdsdbaasv1alpha1.RDSInventory {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cache", Name: name},
+		}
+		apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+			Type:   inventoryConditionReady,
+			Status: synced,
+			Reason: inventoryStatusReasonSyncOK,
+		})
+		return inventory
+	}
+
+	It("should serve the last sync until the Inventory syncs after the operator started", func() {
+		inventory := newInventory("restarted", metav1.ConditionTrue)
+		Expect(servesCachedSync(inventory)).Should(BeTrue())
+		cached := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionCached)
+		Expect(cached).ShouldNot(BeNil())
+		Expect(cached.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(cached.Reason).Should(Equal(inventoryStatusReasonCached))
+
+		recordInventorySync("cache", "restarted", true, time.Now())
+		defer deleteInventorySync("cache", "restarted")
+		Expect(servesCachedSync(inventory)).Should(BeFalse())
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionCached)).Should(BeNil())
+	})
+
+	It("should not serve the last sync of an Inventory that did not sync", func() {
+		inventory := newInventory("not-synced", metav1.ConditionFalse)
+		Expect(servesCachedSync(inventory)).Should(BeFalse())
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionCached)).Should(BeNil())
+	})
+
+	It("should serve the last sync of an Inventory that only failed to sync since the operator started", func() {
+		recordInventorySync("cache", "failed", false, time.Now())
+		defer deleteInventorySync("cache", "failed")
+		inventory := newInventory("failed", metav1.ConditionTrue)
+		Expect(servesCachedSync(inventory)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// the Inventory is Degraded from this time before its temporary credentials expire
+	credentialsExpiryWarning = 15 * time.Minute
+
+	degradedReasonCredentialsExpiring = "CredentialsExpiring"
+	degradedReasonCredentialsExpired  = "CredentialsExpired"
+
+	degradedMessageCredentialsExpiring = "The temporary AWS credentials of the Inventory expire at %s, update the credentials Secret with new credentials"
+	degradedMessageCredentialsExpired  = "The temporary AWS credentials of the Inventory expired at %s, update the credentials Secret with new credentials"
+)
+
+// setCredentialsExpiryCondition sets the Degraded condition of the Inventory while its temporary credentials are about
+// to expire or expired, and removes it otherwise unless the circuit of the Inventory is open. It returns the time
+// until the condition changes, or zero if it does not.
+func setCredentialsExpiryCondition(conditions *[]metav1.Condition, expiry, now time.Time) time.Duration {
+	if c := apimeta.FindStatusCondition(*conditions, conditionDegraded); c != nil && c.Reason == degradedReasonCircuitOpen {
+		return 0
+	}
+	removeCondition := func() {
+		if c := apimeta.FindStatusCondition(*conditions, conditionDegraded); c != nil &&
+			(c.Reason == degradedReasonCredentialsExpiring || c.Reason == degradedReasonCredentialsExpired) {
+			apimeta.RemoveStatusCondition(conditions, conditionDegraded)
+		}
+	}
+	if expiry.IsZero() {
+		removeCondition()
+		return 0
+	}
+
+	if !now.Before(expiry) {
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    conditionDegraded,
+			Status:  metav1.ConditionTrue,
+			Reason:  degradedReasonCredentialsExpired,
+			Message: fmt.Sprintf(degradedMessageCredentialsExpired, expiry.UTC().Format(time.RFC3339)),
+		})
+		return 0
+	}
+	if remaining := expiry.Sub(now); remaining <= credentialsExpiryWarning {
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    conditionDegraded,
+			Status:  metav1.ConditionTrue,
+			Reason:  degradedReasonCredentialsExpiring,
+			Message: fmt.Sprintf(degradedMessageCredentialsExpiring, expiry.UTC().Format(time.RFC3339)),
+		})
+		return remaining
+	}
+	removeCondition()
+	return expiry.Add(-credentialsExpiryWarning).Sub(now)
+}
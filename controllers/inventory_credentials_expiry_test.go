@@ -0,0 +1,72 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("InventoryCredentialsExpiry", func() {
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	It("should not set the Degraded condition for long-term credentials", func() {
+		var conditions []metav1.Condition
+		Expect(setCredentialsExpiryCondition(&conditions, time.Time{}, now)).Should(BeZero())
+		Expect(conditions).Should(BeEmpty())
+	})
+
+	It("should requeue before the credentials are about to expire", func() {
+		var conditions []metav1.Condition
+		Expect(setCredentialsExpiryCondition(&conditions, now.Add(time.Hour), now)).Should(Equal(45 * time.Minute))
+		Expect(conditions).Should(BeEmpty())
+	})
+
+	It("should set the Degraded condition with the expiry time", func() {
+		var conditions []metav1.Condition
+		Expect(setCredentialsExpiryCondition(&conditions, now.Add(10*time.Minute), now)).Should(Equal(10 * time.Minute))
+		condition := apimeta.FindStatusCondition(conditions, conditionDegraded)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Reason).Should(Equal(degradedReasonCredentialsExpiring))
+		Expect(condition.Message).Should(ContainSubstring("2022-11-01T10:10:00Z"))
+
+		Expect(setCredentialsExpiryCondition(&conditions, now.Add(-time.Minute), now)).Should(BeZero())
+		condition = apimeta.FindStatusCondition(conditions, conditionDegraded)
+		Expect(condition.Reason).Should(Equal(degradedReasonCredentialsExpired))
+		Expect(condition.Message).Should(ContainSubstring("2022-11-01T09:59:00Z"))
+
+		Expect(setCredentialsExpiryCondition(&conditions, now.Add(time.Hour), now)).Should(Equal(45 * time.Minute))
+		Expect(conditions).Should(BeEmpty())
+	})
+
+	It("should keep the Degraded condition of the open circuit", func() {
+		conditions := []metav1.Condition{{Type: conditionDegraded, Status: metav1.ConditionTrue, Reason: degradedReasonCircuitOpen}}
+		Expect(setCredentialsExpiryCondition(&conditions, now.Add(-time.Minute), now)).Should(BeZero())
+		Expect(apimeta.FindStatusCondition(conditions, conditionDegraded).Reason).Should(Equal(degradedReasonCircuitOpen))
+	})
+
+	It("should not remove the Degraded condition of the expired credentials when the circuit is closed", func() {
+		conditions := []metav1.Condition{{Type: conditionDegraded, Status: metav1.ConditionTrue, Reason: degradedReasonCredentialsExpired}}
+		NewCircuitBreaker(3, time.Minute).setDegradedCondition(&conditions, "ns", "inventory")
+		Expect(conditions).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const inventoryCredentialsRefKey = ".spec.credentialsRef.name"
+
+func indexInventoryCredentialsRef(rawObj client.Object) []string {
+	inventory := rawObj.(*rdsdbaasv1alpha1.RDSInventory)
+	if inventory.Spec.CredentialsRef == nil || len(inventory.Spec.CredentialsRef.Name) == 0 {
+		return nil
+	}
+	return []string{inventory.Spec.CredentialsRef.Name}
+}
+
+// credentialsSecretChanged filters the updates of the Secrets not changing their data, the Secrets are only cached
+// with the DBaaS type label like the credentials Secrets of the Inventories
+func credentialsSecretChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*v1.Secret)
+			if !ok {
+				return true
+			}
+			newSecret, ok := e.ObjectNew.(*v1.Secret)
+			if !ok {
+				return true
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// getCredentialsInventoryRequests returns the requests of the Inventories whose credentials Secret is the Secret, so
+// that the Inventories sync with the rotated credentials without waiting for their periodic sync
+func getCredentialsInventoryRequests(object client.Object, cli client.Reader) []reconcile.Request {
+	ctx := context.Background()
+	logger := log.FromContext(ctx)
+
+	inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+	if e := cli.List(ctx, inventoryList, client.InNamespace(object.GetNamespace()),
+		client.MatchingFields{inventoryCredentialsRefKey: object.GetName()}); e != nil {
+		logger.Error(e, "Failed to get Inventories for credentials Secret update", "Secret", object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, inventory := range inventoryList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: inventory.Namespace,
+				Name:      inventory.Name,
+			},
+		})
+	}
+	return requests
+}
+
+// getCredentialsFingerprint returns the hash of the normalized credentials of the Secret
+func getCredentialsFingerprint(secret *v1.Secret) string {
+	h := sha256.New()
+	for _, key := range []string{awsAccessKeyID, awsSecretAccessKey, awsSessionToken} {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(secret.Data[key])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// credentialsRotated records the credentials of the Inventory and returns whether they changed since the last
+// reconcile, the credentials of the first reconcile after the operator started are not rotated
+func (r *RDSInventoryReconciler) credentialsRotated(inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) bool {
+	fingerprint := getCredentialsFingerprint(secret)
+	key := client.ObjectKeyFromObject(inventory).String()
+	last, loaded := r.credentialsFingerprints.Load(key)
+	r.credentialsFingerprints.Store(key, fingerprint)
+	return loaded && last.(string) != fingerprint
+}
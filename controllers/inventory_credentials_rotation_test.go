@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InventoryCredentialsRotation", func() {
+	It("should index the Inventories by their credentials Secret", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory"},
+			Spec: dbaasv1beta1.DBaaSInventorySpec{
+				CredentialsRef: &dbaasv1beta1.LocalObjectReference{Name: "credentials"},
+			},
+		}
+		Expect(indexInventoryCredentialsRef(inventory)).Should(Equal([]string{"credentials"}))
+		Expect(indexInventoryCredentialsRef(&rdsdbaasv1alpha1.RDSInventory{})).Should(BeEmpty())
+	})
+
+	It("should only requeue the Inventories when the data of the Secret changes", func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "credentials", ResourceVersion: "1"},
+			Data:       map[string][]byte{awsAccessKeyID: []byte("AKIA-OLD")},
+		}
+		p := credentialsSecretChanged()
+
+		relabeled := secret.DeepCopy()
+		relabeled.ResourceVersion = "2"
+		relabeled.Labels = map[string]string{"team": "db"}
+		Expect(p.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: relabeled})).Should(BeFalse())
+
+		rotated := secret.DeepCopy()
+		rotated.Data[awsAccessKeyID] = []byte("AKIA-NEW")
+		Expect(p.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: rotated})).Should(BeTrue())
+		Expect(p.Create(event.CreateEvent{Object: secret})).Should(BeTrue())
+		Expect(p.Generic(event.GenericEvent{Object: secret})).Should(BeFalse())
+	})
+
+	It("should detect the rotation of the credentials of the Inventory", func() {
+		r := &RDSInventoryReconciler{}
+		inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory"}}
+		secret := &v1.Secret{Data: map[string][]byte{
+			awsAccessKeyID:     []byte("AKIA-OLD"),
+			awsSecretAccessKey: []byte("secret"),
+			awsRegion:          []byte("us-east-1"),
+		}}
+		Expect(r.credentialsRotated(inventory, secret)).Should(BeFalse())
+		Expect(r.credentialsRotated(inventory, secret)).Should(BeFalse())
+
+		secret.Data[awsRegion] = []byte("us-east-2")
+		Expect(r.credentialsRotated(inventory, secret)).Should(BeFalse())
+
+		secret.Data[awsSessionToken] = []byte("token")
+		Expect(r.credentialsRotated(inventory, secret)).Should(BeTrue())
+		Expect(r.credentialsRotated(inventory, secret)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,107 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const inventoryRefKey = ".spec.inventoryRef"
+
+// getInventoryRefKey returns the index key of the Inventory referenced by a Connection or an Instance, the Inventory
+// is in the namespace of the referencing resource unless the reference has a namespace
+func getInventoryRefKey(namespace string, ref dbaasv1beta1.NamespacedName) string {
+	if len(ref.Namespace) > 0 {
+		namespace = ref.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+func indexConnectionInventoryRef(rawObj client.Object) []string {
+	connection := rawObj.(*rdsdbaasv1alpha1.RDSConnection)
+	return []string{getInventoryRefKey(connection.Namespace, connection.Spec.InventoryRef)}
+}
+
+func indexInstanceInventoryRef(rawObj client.Object) []string {
+	instance := rawObj.(*rdsdbaasv1alpha1.RDSInstance)
+	return []string{getInventoryRefKey(instance.Namespace, instance.Spec.InventoryRef)}
+}
+
+// inventoryChanged filters the updates of the Inventories changing neither their spec nor their status, the periodic
+// syncs of an Inventory whose DB services do not change do not requeue its Connections and Instances
+func inventoryChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldInventory, ok := e.ObjectOld.(*rdsdbaasv1alpha1.RDSInventory)
+			if !ok {
+				return true
+			}
+			newInventory, ok := e.ObjectNew.(*rdsdbaasv1alpha1.RDSInventory)
+			if !ok {
+				return true
+			}
+			return oldInventory.Generation != newInventory.Generation ||
+				!equality.Semantic.DeepEqual(oldInventory.Status, newInventory.Status)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// getInventoryDependentRequests returns the requests of the resources of the list referencing the Inventory, so that
+// the changes of the Inventory propagate to its Connections and Instances without waiting for their periodic resync
+func getInventoryDependentRequests(object client.Object, cli client.Reader, list client.ObjectList) []reconcile.Request {
+	ctx := context.Background()
+	logger := log.FromContext(ctx)
+
+	key := object.GetNamespace() + "/" + object.GetName()
+	if e := cli.List(ctx, list, client.MatchingFields{inventoryRefKey: key}); e != nil {
+		logger.Error(e, "Failed to get the resources referencing the Inventory for Inventory update", "Inventory", key)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	if e := apimeta.EachListItem(list, func(o runtime.Object) error {
+		if obj, ok := o.(client.Object); ok {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+				},
+			})
+		}
+		return nil
+	}); e != nil {
+		logger.Error(e, "Failed to get the resources referencing the Inventory for Inventory update", "Inventory", key)
+		return nil
+	}
+	return requests
+}
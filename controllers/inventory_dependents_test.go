@@ -0,0 +1,72 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InventoryDependents", func() {
+	It("should index the Connections and Instances by the Inventory they reference", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "connection"},
+			Spec: dbaasv1beta1.DBaaSConnectionSpec{
+				InventoryRef: dbaasv1beta1.NamespacedName{Namespace: "operator", Name: "inventory"},
+			},
+		}
+		Expect(indexConnectionInventoryRef(connection)).Should(Equal([]string{"operator/inventory"}))
+
+		instance := &rdsdbaasv1alpha1.RDSInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "instance"},
+			Spec: dbaasv1beta1.DBaaSInstanceSpec{
+				InventoryRef: dbaasv1beta1.NamespacedName{Name: "inventory"},
+			},
+		}
+		Expect(indexInstanceInventoryRef(instance)).Should(Equal([]string{"dev/inventory"}))
+	})
+
+	It("should only requeue the dependents of the Inventories whose spec or status changed", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory", Generation: 1, ResourceVersion: "1"},
+			Status: dbaasv1beta1.DBaaSInventoryStatus{
+				DatabaseServices: []dbaasv1beta1.DatabaseService{{ServiceID: "db"}},
+			},
+		}
+		p := inventoryChanged()
+
+		synced := inventory.DeepCopy()
+		synced.ResourceVersion = "2"
+		Expect(p.Update(event.UpdateEvent{ObjectOld: inventory, ObjectNew: synced})).Should(BeFalse())
+
+		changed := synced.DeepCopy()
+		changed.Status.DatabaseServices = append(changed.Status.DatabaseServices, dbaasv1beta1.DatabaseService{ServiceID: "new-db"})
+		Expect(p.Update(event.UpdateEvent{ObjectOld: synced, ObjectNew: changed})).Should(BeTrue())
+
+		updated := synced.DeepCopy()
+		updated.Generation = 2
+		Expect(p.Update(event.UpdateEvent{ObjectOld: synced, ObjectNew: updated})).Should(BeTrue())
+
+		Expect(p.Create(event.CreateEvent{Object: inventory})).Should(BeTrue())
+		Expect(p.Delete(event.DeleteEvent{Object: inventory})).Should(BeTrue())
+	})
+})
@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	databaseServiceAppeared      = "appeared"
+	databaseServiceDisappeared   = "disappeared"
+	databaseServiceStatusChanged = "status_changed"
+
+	eventReasonDatabaseServiceAppeared      = "DatabaseServiceAppeared"
+	eventReasonDatabaseServiceDisappeared   = "DatabaseServiceDisappeared"
+	eventReasonDatabaseServiceStatusChanged = "DatabaseServiceStatusChanged"
+)
+
+var databaseServiceChanges = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rds_inventory_database_service_changes_total",
+		Help: "The number of DB instances and clusters that appeared in, disappeared from or changed status in an Inventory between two syncs.",
+	},
+	[]string{"namespace", "inventory", "service_type", "change"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(databaseServiceChanges)
+}
+
+// databaseServiceChange is a change of a database service of an Inventory between two syncs
+type databaseServiceChange struct {
+	change         string
+	serviceType    string
+	serviceID      string
+	previousStatus string
+	status         string
+}
+
+func getDatabaseServiceType(service dbaasv1beta1.DatabaseService) string {
+	if service.ServiceType == nil {
+		return instanceType
+	}
+	return string(*service.ServiceType)
+}
+
+func getDatabaseServiceStatus(service dbaasv1beta1.DatabaseService) string {
+	if getDatabaseServiceType(service) == clusterType {
+		return service.ServiceInfo["status"]
+	}
+	return service.ServiceInfo["dbInstanceStatus"]
+}
+
+// diffDatabaseServices returns the database services that appeared, disappeared or changed status between the
+// previous and the current database services of an Inventory, in the order of the services
+func diffDatabaseServices(previous, current []dbaasv1beta1.DatabaseService) []databaseServiceChange {
+	key := func(service dbaasv1beta1.DatabaseService) string {
+		return fmt.Sprintf("%s/%s", getDatabaseServiceType(service), service.ServiceID)
+	}
+	previousServices := map[string]dbaasv1beta1.DatabaseService{}
+	for _, service := range previous {
+		previousServices[key(service)] = service
+	}
+	currentServices := map[string]bool{}
+
+	var changes []databaseServiceChange
+	for _, service := range current {
+		currentServices[key(service)] = true
+		change := databaseServiceChange{
+			serviceType: getDatabaseServiceType(service),
+			serviceID:   service.ServiceID,
+			status:      getDatabaseServiceStatus(service),
+		}
+		if p, ok := previousServices[key(service)]; !ok {
+			change.change = databaseServiceAppeared
+		} else if previousStatus := getDatabaseServiceStatus(p); previousStatus != change.status {
+			change.change = databaseServiceStatusChanged
+			change.previousStatus = previousStatus
+		} else {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for _, service := range previous {
+		if !currentServices[key(service)] {
+			changes = append(changes, databaseServiceChange{
+				change:         databaseServiceDisappeared,
+				serviceType:    getDatabaseServiceType(service),
+				serviceID:      service.ServiceID,
+				previousStatus: getDatabaseServiceStatus(service),
+			})
+		}
+	}
+	return changes
+}
+
+// recordDatabaseServiceChanges emits an Event and counts each change of the database services of the Inventory
+func (r *RDSInventoryReconciler) recordDatabaseServiceChanges(inventory *rdsdbaasv1alpha1.RDSInventory, changes []databaseServiceChange) {
+	for _, change := range changes {
+		databaseServiceChanges.WithLabelValues(inventory.Namespace, inventory.Name, change.serviceType, change.change).Inc()
+		if r.Recorder == nil {
+			continue
+		}
+		switch change.change {
+		case databaseServiceAppeared:
+			r.Recorder.Eventf(inventory, v1.EventTypeNormal, eventReasonDatabaseServiceAppeared,
+				"DB %s %s appeared with status %s", change.serviceType, change.serviceID, change.status)
+		case databaseServiceDisappeared:
+			r.Recorder.Eventf(inventory, v1.EventTypeWarning, eventReasonDatabaseServiceDisappeared,
+				"DB %s %s disappeared", change.serviceType, change.serviceID)
+		case databaseServiceStatusChanged:
+			r.Recorder.Eventf(inventory, v1.EventTypeNormal, eventReasonDatabaseServiceStatusChanged,
+				"DB %s %s status changed from %s to %s", change.serviceType, change.serviceID, change.previousStatus, change.status)
+		}
+	}
+}
+
+// deleteDatabaseServiceChanges removes the change counters of a deleted Inventory
+func deleteDatabaseServiceChanges(namespace, name string) {
+	databaseServiceChanges.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "inventory": name})
+}
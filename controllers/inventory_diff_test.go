@@ -0,0 +1,54 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+)
+
+var _ = Describe("InventoryDiff", func() {
+	instance := dbaasv1beta1.DatabaseServiceType(instanceType)
+	cluster := dbaasv1beta1.DatabaseServiceType(clusterType)
+
+	It("should diff the database services of two syncs", func() {
+		previous := []dbaasv1beta1.DatabaseService{
+			{ServiceID: "db-1", ServiceType: &instance, ServiceInfo: map[string]string{"dbInstanceStatus": "available"}},
+			{ServiceID: "db-2", ServiceType: &instance, ServiceInfo: map[string]string{"dbInstanceStatus": "available"}},
+			{ServiceID: "cluster-1", ServiceType: &cluster, ServiceInfo: map[string]string{"status": "available"}},
+		}
+		current := []dbaasv1beta1.DatabaseService{
+			{ServiceID: "db-1", ServiceType: &instance, ServiceInfo: map[string]string{"dbInstanceStatus": "modifying"}},
+			{ServiceID: "cluster-1", ServiceType: &cluster, ServiceInfo: map[string]string{"status": "available"}},
+			{ServiceID: "db-3", ServiceType: &instance, ServiceInfo: map[string]string{"dbInstanceStatus": "creating"}},
+		}
+		Expect(diffDatabaseServices(previous, current)).Should(Equal([]databaseServiceChange{
+			{change: databaseServiceStatusChanged, serviceType: instanceType, serviceID: "db-1", previousStatus: "available", status: "modifying"},
+			{change: databaseServiceAppeared, serviceType: instanceType, serviceID: "db-3", status: "creating"},
+			{change: databaseServiceDisappeared, serviceType: instanceType, serviceID: "db-2", previousStatus: "available"},
+		}))
+	})
+
+	It("should not report unchanged database services", func() {
+		services := []dbaasv1beta1.DatabaseService{
+			{ServiceID: "db-1", ServiceType: &instance, ServiceInfo: map[string]string{"dbInstanceStatus": "available"}},
+		}
+		Expect(diffDatabaseServices(services, services)).Should(BeEmpty())
+	})
+})
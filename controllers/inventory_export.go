@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultInventoryExportHistory is the default number of exports of the DB services of an Inventory kept
+	DefaultInventoryExportHistory = 48
+
+	inventoryExportConfigMapSuffix = "-export"
+	inventoryExportKeyFormat       = "20060102T150405Z"
+	inventoryExportKeySuffix       = ".json"
+
+	// the exports are pruned once their total size reaches the limit, below the maximum size of a ConfigMap
+	inventoryExportMaxSize = 768 * 1024
+)
+
+// InventoryExport is the record of the DB services exposed by an Inventory at a time
+type InventoryExport struct {
+	Inventory        string                         `json:"inventory"`
+	ExportedAt       time.Time                      `json:"exportedAt"`
+	DatabaseServices []dbaasv1beta1.DatabaseService `json:"databaseServices"`
+}
+
+// getInventoryExportConfigMapName returns the ConfigMap of the exports of the Inventory
+func getInventoryExportConfigMapName(inventory *rdsdbaasv1alpha1.RDSInventory) string {
+	return inventory.Name + inventoryExportConfigMapSuffix
+}
+
+// getLastInventoryExportTime returns the time of the latest export of the ConfigMap data, zero if there is none
+func getLastInventoryExportTime(data map[string]string) time.Time {
+	var last time.Time
+	for key := range data {
+		if t, e := time.Parse(inventoryExportKeyFormat, strings.TrimSuffix(key, inventoryExportKeySuffix)); e == nil && t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// pruneInventoryExports removes the oldest exports beyond the history or the maximum size of the ConfigMap data
+func pruneInventoryExports(data map[string]string, history int) {
+	var keys []string
+	size := 0
+	for key, value := range data {
+		keys = append(keys, key)
+		size += len(key) + len(value)
+	}
+	// the keys are sorted by time
+	sort.Strings(keys)
+	for _, key := range keys {
+		if len(data) <= 1 || len(data) <= history && size <= inventoryExportMaxSize {
+			return
+		}
+		size -= len(key) + len(data[key])
+		delete(data, key)
+	}
+}
+
+// exportDatabaseServices records the DB services of the Inventory in its export ConfigMap once the export interval
+// has elapsed since the latest export, giving a timestamped history of the DB services exposed to the cluster
+func (r *RDSInventoryReconciler) exportDatabaseServices(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory, now time.Time) error {
+	if r.ExportInterval <= 0 {
+		return nil
+	}
+	history := r.ExportHistory
+	if history <= 0 {
+		history = DefaultInventoryExportHistory
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getInventoryExportConfigMapName(inventory),
+			Namespace: inventory.Namespace,
+		},
+	}
+	exported := true
+	_, err := createOrApply(ctx, r.Client, configMap, func(existing client.Object) error {
+		data := map[string]string{}
+		if existing != nil {
+			for k, v := range existing.(*v1.ConfigMap).Data {
+				data[k] = v
+			}
+		}
+		if last := getLastInventoryExportTime(data); !last.IsZero() && now.Sub(last) < r.ExportInterval {
+			exported = false
+		} else {
+			services := inventory.Status.DatabaseServices
+			if services == nil {
+				services = []dbaasv1beta1.DatabaseService{}
+			}
+			b, e := json.Marshal(InventoryExport{
+				Inventory:        client.ObjectKeyFromObject(inventory).String(),
+				ExportedAt:       now.UTC().Truncate(time.Second),
+				DatabaseServices: services,
+			})
+			if e != nil {
+				return e
+			}
+			data[now.UTC().Format(inventoryExportKeyFormat)+inventoryExportKeySuffix] = string(b)
+			pruneInventoryExports(data, history)
+		}
+		// the ConfigMaps of the cache are selected by the label
+		configMap.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+		configMap.Data = data
+		return ctrl.SetControllerReference(inventory, configMap, r.Scheme)
+	})
+	if err == nil && exported {
+		log.FromContext(ctx).Info("DB services of Inventory exported", "ConfigMap", configMap.Name)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inventory export", func() {
+	It("should find the time of the latest export", func() {
+		Expect(getLastInventoryExportTime(map[string]string{})).Should(BeZero())
+		Expect(getLastInventoryExportTime(map[string]string{
+			"20221001T120000Z.json": "{}",
+			"20221001T130000Z.json": "{}",
+			"README":                "",
+		})).Should(Equal(time.Date(2022, 10, 1, 13, 0, 0, 0, time.UTC)))
+	})
+
+	It("should prune the oldest exports beyond the history and the maximum size", func() {
+		data := map[string]string{
+			"20221001T120000Z.json": "{}",
+			"20221001T130000Z.json": "{}",
+			"20221001T140000Z.json": "{}",
+		}
+		pruneInventoryExports(data, 2)
+		Expect(data).Should(HaveLen(2))
+		Expect(data).ShouldNot(HaveKey("20221001T120000Z.json"))
+
+		large := strings.Repeat("x", inventoryExportMaxSize/2)
+		data = map[string]string{
+			"20221001T120000Z.json": large,
+			"20221001T130000Z.json": large,
+			"20221001T140000Z.json": large,
+		}
+		pruneInventoryExports(data, 10)
+		Expect(data).Should(HaveLen(1))
+		Expect(data).Should(HaveKey("20221001T140000Z.json"))
+	})
+})
@@ -0,0 +1,147 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// failoverEvents returns the events to the sync, and records the start time of the sync
+type failoverEvents struct {
+	events    []rdstypesv2.Event
+	startTime *time.Time
+}
+
+func (f *failoverEvents) DescribeEvents(_ context.Context, params *rds.DescribeEventsInput, _ ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+	f.startTime = params.StartTime
+	return &rds.DescribeEventsOutput{Events: f.events}, nil
+}
+
+var _ = Describe("InventoryFailoverEvents", func() {
+	ctx := context.Background()
+	inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory", UID: "uid"}}
+	newCursor := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "inventory" + failoverEventsConfigMapSuffix},
+			Data:       data,
+		}
+	}
+	getCursor := func(r *RDSInventoryReconciler) *v1.ConfigMap {
+		configMap := &v1.ConfigMap{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "operator", Name: "inventory" + failoverEventsConfigMapSuffix}, configMap)).Should(Succeed())
+		return configMap
+	}
+
+	It("should resume the sync of the failover events from the last event notified", func() {
+		lastEventTime := time.Now().Add(-10 * time.Minute).UTC()
+		eventTime := lastEventTime.Add(5 * time.Minute)
+		// the events of other sources are not notified to the Connections
+		events := &failoverEvents{
+			events: []rdstypesv2.Event{
+				{SourceType: rdstypesv2.SourceTypeDbSnapshot, SourceIdentifier: pointer.String("snapshot"), Date: &lastEventTime},
+				{SourceType: rdstypesv2.SourceTypeDbSnapshot, SourceIdentifier: pointer.String("snapshot"), Date: &eventTime},
+			},
+		}
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory.DeepCopy(),
+			newCursor(map[string]string{failoverEventsKeyLastTime: lastEventTime.Format(time.RFC3339Nano)})).Build()}
+		r := &RDSInventoryReconciler{
+			Client: cli,
+			Scheme: scheme.Scheme,
+			GetDescribeEventsAPI: func(accessKey, secretKey, region string) controllersrds.DescribeEventsAPI {
+				return events
+			},
+		}
+
+		// the sync after a restart starts from the last event notified
+		Expect(r.syncFailoverEvents(ctx, inventory.DeepCopy(), "key", "secret", "us-east-1")).Should(Succeed())
+		Expect(events.startTime).ShouldNot(BeNil())
+		Expect(events.startTime.Equal(lastEventTime)).Should(BeTrue())
+
+		cursor := getCursor(r)
+		Expect(cursor.Data).Should(HaveKeyWithValue(failoverEventsKeyLastTime, eventTime.Format(time.RFC3339Nano)))
+		Expect(cursor.Data).Should(HaveKeyWithValue(failoverEventsKeyLastEvents, getFailoverEventKey(events.events[1])))
+		Expect(metav1.IsControlledBy(cursor, inventory)).Should(BeTrue())
+
+		// the Inventory is not modified
+		updated := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(inventory), updated)).Should(Succeed())
+		Expect(updated.Annotations).Should(BeEmpty())
+		Expect(updated.ResourceVersion).Should(Equal("999"))
+	})
+
+	It("should notify the events of the same time as the last event notified once", func() {
+		eventTime := time.Now().Add(-10 * time.Minute).UTC()
+		notified := rdstypesv2.Event{SourceType: rdstypesv2.SourceTypeDbInstance, SourceIdentifier: pointer.String("db-a"), Date: &eventTime}
+		missed := rdstypesv2.Event{SourceType: rdstypesv2.SourceTypeDbInstance, SourceIdentifier: pointer.String("db-b"), Date: &eventTime}
+		events := &failoverEvents{events: []rdstypesv2.Event{notified, missed}}
+		cli := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(inventory.DeepCopy(), newCursor(map[string]string{
+				failoverEventsKeyLastTime:   eventTime.Format(time.RFC3339Nano),
+				failoverEventsKeyLastEvents: getFailoverEventKey(notified),
+			})).Build()}
+		recorder := record.NewFakeRecorder(10)
+		r := &RDSInventoryReconciler{
+			Client:   cli,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+			GetDescribeEventsAPI: func(accessKey, secretKey, region string) controllersrds.DescribeEventsAPI {
+				return events
+			},
+		}
+
+		Expect(r.syncFailoverEvents(ctx, inventory.DeepCopy(), "key", "secret", "us-east-1")).Should(Succeed())
+		Expect(recorder.Events).Should(HaveLen(1))
+		Expect(<-recorder.Events).Should(ContainSubstring("db-b"))
+		Expect(strings.Fields(getCursor(r).Data[failoverEventsKeyLastEvents])).Should(ConsistOf(
+			getFailoverEventKey(notified), getFailoverEventKey(missed)))
+
+		// the events are not notified again
+		Expect(r.syncFailoverEvents(ctx, inventory.DeepCopy(), "key", "secret", "us-east-1")).Should(Succeed())
+		Expect(recorder.Events).Should(BeEmpty())
+	})
+
+	It("should sync from the lookback if the time of the last event notified is invalid", func() {
+		events := &failoverEvents{}
+		r := &RDSInventoryReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory.DeepCopy(),
+				newCursor(map[string]string{failoverEventsKeyLastTime: "yesterday"})).Build(),
+			GetDescribeEventsAPI: func(accessKey, secretKey, region string) controllersrds.DescribeEventsAPI {
+				return events
+			},
+		}
+		Expect(r.syncFailoverEvents(ctx, inventory.DeepCopy(), "key", "secret", "us-east-1")).Should(Succeed())
+		Expect(events.startTime).ShouldNot(BeNil())
+		Expect(time.Since(*events.startTime)).Should(BeNumerically("~", failoverEventLookback, time.Minute))
+	})
+})
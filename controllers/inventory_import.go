@@ -0,0 +1,131 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	label "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// create the Connections to the DB services of the Inventory matching the selector in the namespace, the selector
+	// is a label selector on the service info of the DB services, for example engine=postgres,tag.team=payments
+	inventoryImportNamespaceAnnotation = "rds.dbaas.redhat.com/import-connections-namespace"
+	inventoryImportSelectorAnnotation  = "rds.dbaas.redhat.com/import-connections-selector"
+
+	// the Inventory the Connection is imported from
+	importedFromInventoryLabelKey = "rds.dbaas.redhat.com/imported-from"
+
+	eventReasonConnectionsImported = "ConnectionsImported"
+	eventReasonImportFailed        = "ConnectionsImportFailed"
+)
+
+// getImportSelector returns the namespace and the selector of the Connections imported for the Inventory, nil is
+// returned if the Inventory does not import Connections
+func getImportSelector(inventory *rdsdbaasv1alpha1.RDSInventory) (string, label.Selector, error) {
+	namespace, ok := inventory.Annotations[inventoryImportNamespaceAnnotation]
+	if !ok || len(namespace) == 0 {
+		return "", nil, nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", nil, fmt.Errorf("namespace %s of annotation %s is invalid: %s", namespace, inventoryImportNamespaceAnnotation,
+			strings.Join(errs, ", "))
+	}
+	selector, err := label.Parse(inventory.Annotations[inventoryImportSelectorAnnotation])
+	if err != nil {
+		return "", nil, fmt.Errorf("value of annotation %s is invalid: %v", inventoryImportSelectorAnnotation, err)
+	}
+	return namespace, selector, nil
+}
+
+// getImportedConnectionName returns the name of the Connection imported for the DB service of the Inventory
+func getImportedConnectionName(inventory *rdsdbaasv1alpha1.RDSInventory, service dbaasv1beta1.DatabaseService) string {
+	name := fmt.Sprintf("%s-%s", inventory.Name, strings.ToLower(service.ServiceID))
+	if service.ServiceType != nil && *service.ServiceType == clusterType {
+		name += "-" + clusterType
+	}
+	return name
+}
+
+// importConnections creates the missing Connections to the DB services of the Inventory matching its import selector,
+// the Connection controller then creates their Secrets and ConfigMaps
+func (r *RDSInventoryReconciler) importConnections(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	logger := log.FromContext(ctx)
+
+	namespace, selector, err := getImportSelector(inventory)
+	if err != nil || selector == nil {
+		return err
+	}
+
+	var imported []string
+	for _, service := range inventory.Status.DatabaseServices {
+		if !selector.Matches(label.Set(service.ServiceInfo)) {
+			continue
+		}
+		connection := &rdsdbaasv1alpha1.RDSConnection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      getImportedConnectionName(inventory, service),
+				Namespace: namespace,
+				Labels:    map[string]string{importedFromInventoryLabelKey: inventory.Name},
+			},
+			Spec: dbaasv1beta1.DBaaSConnectionSpec{
+				InventoryRef: dbaasv1beta1.NamespacedName{
+					Name:      inventory.Name,
+					Namespace: inventory.Namespace,
+				},
+				DatabaseServiceID:   service.ServiceID,
+				DatabaseServiceType: service.ServiceType,
+			},
+		}
+		if errs := validation.IsDNS1123Subdomain(connection.Name); len(errs) > 0 {
+			logger.Info("DB service not imported, the Connection name is invalid", "serviceID", service.ServiceID,
+				"name", connection.Name)
+			continue
+		}
+		if err := r.Create(ctx, connection); err != nil {
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+			if r.Recorder != nil {
+				r.Recorder.Eventf(inventory, v1.EventTypeWarning, eventReasonImportFailed,
+					"Failed to import Connection to DB service %s in namespace %s: %v", service.ServiceID, namespace, err)
+			}
+			return err
+		}
+		imported = append(imported, service.ServiceID)
+	}
+
+	if len(imported) > 0 {
+		logger.Info("Connections imported for DB services of the Inventory", "namespace", namespace, "services", imported)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(inventory, v1.EventTypeNormal, eventReasonConnectionsImported,
+				"Connections to DB services %s imported in namespace %s", strings.Join(imported, ","), namespace)
+		}
+	}
+	return nil
+}
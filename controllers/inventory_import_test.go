@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	label "k8s.io/apimachinery/pkg/labels"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("InventoryImport", func() {
+	It("should parse the import selector of the Inventory", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Name: "inventory"}}
+		_, selector, err := getImportSelector(inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(selector).Should(BeNil())
+
+		inventory.Annotations = map[string]string{
+			inventoryImportNamespaceAnnotation: "app",
+			inventoryImportSelectorAnnotation:  "engine=postgres,tag.team in (payments)",
+		}
+		namespace, selector, err := getImportSelector(inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(namespace).Should(Equal("app"))
+		Expect(selector.Matches(label.Set{"engine": "postgres", "tag.team": "payments"})).Should(BeTrue())
+		Expect(selector.Matches(label.Set{"engine": "mysql", "tag.team": "payments"})).Should(BeFalse())
+
+		inventory.Annotations[inventoryImportSelectorAnnotation] = "engine in (postgres"
+		_, _, err = getImportSelector(inventory)
+		Expect(err).Should(HaveOccurred())
+
+		inventory.Annotations[inventoryImportNamespaceAnnotation] = "App"
+		_, _, err = getImportSelector(inventory)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should name the imported Connections after the Inventory and the DB services", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Name: "inventory"}}
+		Expect(getImportedConnectionName(inventory, dbaasv1beta1.DatabaseService{ServiceID: "db-1"})).Should(Equal("inventory-db-1"))
+		serviceType := dbaasv1beta1.DatabaseServiceType(clusterType)
+		Expect(getImportedConnectionName(inventory, dbaasv1beta1.DatabaseService{ServiceID: "DB-1", ServiceType: &serviceType})).
+			Should(Equal("inventory-db-1-cluster"))
+	})
+})
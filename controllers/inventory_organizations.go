@@ -0,0 +1,252 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the role of the management account of the organization assumed with the credentials of the Inventory to list the
+	// member accounts of the organizational unit
+	organizationsRoleARNAnnotation = "rds.dbaas.redhat.com/organizations-role-arn"
+	// the organizational unit, or the root, whose member accounts are discovered
+	organizationsUnitIDAnnotation = "rds.dbaas.redhat.com/organizations-unit-id"
+	// the role assumed in each member account from the management account role
+	organizationsMemberRoleNameAnnotation = "rds.dbaas.redhat.com/organizations-member-role-name"
+
+	// the role created by AWS Organizations in the accounts created in the organization
+	defaultOrganizationsMemberRoleName = "OrganizationAccountAccessRole"
+
+	inventoryConditionOrganizationSynced  = "OrganizationSynced"
+	inventoryConditionAccountSyncedPrefix = "AccountSynced-"
+
+	inventoryStatusMessageOrganizationSynced = "%d member accounts of the organizational unit %s discovered"
+	inventoryStatusMessageOrganizationError  = "Failed to list the member accounts of the organizational unit %s: %v"
+	inventoryStatusMessageAccountSynced      = "%d DB services of the member account %s synced"
+	inventoryStatusMessageAccountError       = "Failed to read the DB services of the member account %s: %v"
+
+	serviceInfoOwnerAccountID = "ackResourceMetadata.ownerAccountID"
+)
+
+// getMemberRoleARN returns the ARN of the role assumed in the member account, in the partition of the management
+// account role
+func getMemberRoleARN(managementRoleARN, accountID, roleName string) (string, error) {
+	a, e := arn.Parse(managementRoleARN)
+	if e != nil {
+		return "", fmt.Errorf("organizations role ARN %s is invalid: %w", managementRoleARN, e)
+	}
+	return arn.ARN{
+		Partition: a.Partition,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  "role/" + roleName,
+	}.String(), nil
+}
+
+// newMemberDBInstanceService returns the database service of a DB instance of a member account, the DB instances of
+// the member accounts are not adopted so the service is built from the AWS instance and identified by the account
+func newMemberDBInstanceService(accountID string, dbInstance rdstypesv2.DBInstance) dbaasv1beta1.DatabaseService {
+	service := newCustomDBInstanceService(dbInstance, getDeploymentServiceInfo(dbInstance))
+	service.ServiceID = accountID + "/" + service.ServiceID
+	service.ServiceInfo[serviceInfoOwnerAccountID] = accountID
+	return service
+}
+
+// newMemberDBClusterService returns the database service of a DB cluster of a member account
+func newMemberDBClusterService(accountID string, dbCluster rdstypesv2.DBCluster) dbaasv1beta1.DatabaseService {
+	serviceType := dbaasv1beta1.DatabaseServiceType(clusterType)
+	serviceInfo := map[string]string{
+		serviceInfoOwnerAccountID: accountID,
+	}
+	if dbCluster.Status != nil {
+		serviceInfo["status"] = *dbCluster.Status
+	}
+	if dbCluster.Engine != nil {
+		serviceInfo["engine"] = *dbCluster.Engine
+	}
+	if dbCluster.EngineVersion != nil {
+		serviceInfo["engineVersion"] = *dbCluster.EngineVersion
+	}
+	if dbCluster.DBClusterArn != nil {
+		serviceInfo["ackResourceMetadata.arn"] = *dbCluster.DBClusterArn
+	}
+	return dbaasv1beta1.DatabaseService{
+		ServiceID:   accountID + "/" + *dbCluster.DBClusterIdentifier,
+		ServiceName: *dbCluster.DBClusterIdentifier,
+		ServiceType: &serviceType,
+		ServiceInfo: serviceInfo,
+	}
+}
+
+// removeAccountSyncedConditions removes the conditions of the member accounts not discovered anymore
+func removeAccountSyncedConditions(inventory *rdsdbaasv1alpha1.RDSInventory, accountIDs map[string]bool) {
+	var conditions []metav1.Condition
+	for _, c := range inventory.Status.Conditions {
+		if strings.HasPrefix(c.Type, inventoryConditionAccountSyncedPrefix) &&
+			!accountIDs[strings.TrimPrefix(c.Type, inventoryConditionAccountSyncedPrefix)] {
+			continue
+		}
+		conditions = append(conditions, c)
+	}
+	inventory.Status.Conditions = conditions
+}
+
+// syncOrganizationAccounts returns the DB services of the active member accounts of the organizational unit of the
+// Inventory, read with the role assumed in each account from the management account role. The accounts of the DB
+// services of the Inventory credentials are skipped. The member accounts failing are reported in their condition and
+// do not fail the sync of the Inventory.
+func (r *RDSInventoryReconciler) syncOrganizationAccounts(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	accessKey, secretKey, region string, services []dbaasv1beta1.DatabaseService) []dbaasv1beta1.DatabaseService {
+	logger := log.FromContext(ctx)
+
+	roleARN := inventory.Annotations[organizationsRoleARNAnnotation]
+	unitID := inventory.Annotations[organizationsUnitIDAnnotation]
+	if len(roleARN) == 0 || len(unitID) == 0 || r.GetListAccountsForParentPaginatorAPI == nil {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionOrganizationSynced)
+		removeAccountSyncedConditions(inventory, nil)
+		return nil
+	}
+	memberRoleName := defaultOrganizationsMemberRoleName
+	if n, ok := inventory.Annotations[organizationsMemberRoleNameAnnotation]; ok && len(n) > 0 {
+		memberRoleName = n
+	}
+
+	var accounts []organizationstypes.Account
+	listAccountsPaginator := r.GetListAccountsForParentPaginatorAPI(accessKey, secretKey, region, roleARN, unitID)
+	for listAccountsPaginator.HasMorePages() {
+		output, e := listAccountsPaginator.NextPage(ctx)
+		if e != nil {
+			// the DB services of the member accounts are discovered again in the next sync
+			logger.Error(e, "Failed to list the member accounts of the organizational unit of the Inventory")
+			apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+				Type:    inventoryConditionOrganizationSynced,
+				Status:  metav1.ConditionFalse,
+				Reason:  inventoryStatusReasonBackendError,
+				Message: fmt.Sprintf(inventoryStatusMessageOrganizationError, unitID, e),
+			})
+			return nil
+		}
+		if output != nil {
+			accounts = append(accounts, output.Accounts...)
+		}
+	}
+
+	ownAccountIDs := map[string]bool{}
+	for _, service := range services {
+		if id, ok := service.ServiceInfo[serviceInfoOwnerAccountID]; ok {
+			ownAccountIDs[id] = true
+		}
+	}
+
+	var memberServices []dbaasv1beta1.DatabaseService
+	accountIDs := map[string]bool{}
+	for _, account := range accounts {
+		if account.Id == nil || account.Status != organizationstypes.AccountStatusActive || ownAccountIDs[*account.Id] {
+			continue
+		}
+		accountID := *account.Id
+		accountIDs[accountID] = true
+		sv, e := r.getMemberAccountServices(ctx, accessKey, secretKey, region, roleARN, accountID, memberRoleName)
+		if e != nil {
+			logger.Error(e, "Failed to read the DB services of the member account of the Inventory", "Account", accountID)
+			apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+				Type:    inventoryConditionAccountSyncedPrefix + accountID,
+				Status:  metav1.ConditionFalse,
+				Reason:  inventoryStatusReasonBackendError,
+				Message: fmt.Sprintf(inventoryStatusMessageAccountError, accountID, e),
+			})
+			continue
+		}
+		apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+			Type:    inventoryConditionAccountSyncedPrefix + accountID,
+			Status:  metav1.ConditionTrue,
+			Reason:  inventoryStatusReasonSyncOK,
+			Message: fmt.Sprintf(inventoryStatusMessageAccountSynced, len(sv), accountID),
+		})
+		memberServices = append(memberServices, sv...)
+	}
+	removeAccountSyncedConditions(inventory, accountIDs)
+	apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+		Type:    inventoryConditionOrganizationSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  inventoryStatusReasonSyncOK,
+		Message: fmt.Sprintf(inventoryStatusMessageOrganizationSynced, len(accountIDs), unitID),
+	})
+	return memberServices
+}
+
+// getMemberAccountServices returns the DB services of the DB clusters and of the DB instances not in a DB cluster of
+// the member account
+func (r *RDSInventoryReconciler) getMemberAccountServices(ctx context.Context, accessKey, secretKey, region,
+	managementRoleARN, accountID, memberRoleName string) ([]dbaasv1beta1.DatabaseService, error) {
+	memberRoleARN, e := getMemberRoleARN(managementRoleARN, accountID, memberRoleName)
+	if e != nil {
+		return nil, e
+	}
+
+	var services []dbaasv1beta1.DatabaseService
+	if r.GetAssumeRoleDescribeDBClustersPaginatorAPI != nil {
+		describeDBClustersPaginator := r.GetAssumeRoleDescribeDBClustersPaginatorAPI(accessKey, secretKey, region,
+			managementRoleARN, memberRoleARN)
+		for describeDBClustersPaginator.HasMorePages() {
+			output, e := describeDBClustersPaginator.NextPage(ctx)
+			if e != nil {
+				return nil, e
+			}
+			if output == nil {
+				continue
+			}
+			for _, cluster := range output.DBClusters {
+				if cluster.DBClusterIdentifier != nil {
+					services = append(services, newMemberDBClusterService(accountID, cluster))
+				}
+			}
+		}
+	}
+	if r.GetAssumeRoleDescribeDBInstancesPaginatorAPI != nil {
+		describeDBInstancesPaginator := r.GetAssumeRoleDescribeDBInstancesPaginatorAPI(accessKey, secretKey, region,
+			managementRoleARN, memberRoleARN)
+		for describeDBInstancesPaginator.HasMorePages() {
+			output, e := describeDBInstancesPaginator.NextPage(ctx)
+			if e != nil {
+				return nil, e
+			}
+			if output == nil {
+				continue
+			}
+			for _, instance := range output.DBInstances {
+				if instance.DBInstanceIdentifier != nil && instance.DBClusterIdentifier == nil {
+					services = append(services, newMemberDBInstanceService(accountID, instance))
+				}
+			}
+		}
+	}
+	return services, nil
+}
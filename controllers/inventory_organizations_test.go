@@ -0,0 +1,149 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersorganizations "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/organizations"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+)
+
+type accountsPaginator struct {
+	accounts []organizationstypes.Account
+	done     bool
+}
+
+func (p *accountsPaginator) HasMorePages() bool {
+	return !p.done
+}
+
+func (p *accountsPaginator) NextPage(context.Context, ...func(option *organizations.Options)) (*organizations.ListAccountsForParentOutput, error) {
+	p.done = true
+	return &organizations.ListAccountsForParentOutput{Accounts: p.accounts}, nil
+}
+
+type memberDBInstancesPaginator struct {
+	instances []rdstypesv2.DBInstance
+	err       error
+	done      bool
+}
+
+func (p *memberDBInstancesPaginator) HasMorePages() bool {
+	return !p.done
+}
+
+func (p *memberDBInstancesPaginator) NextPage(context.Context, ...func(option *rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	p.done = true
+	return &rds.DescribeDBInstancesOutput{DBInstances: p.instances}, p.err
+}
+
+var _ = Describe("Inventory organization accounts", func() {
+	It("should build the member account role ARN in the partition of the management role", func() {
+		roleARN, err := getMemberRoleARN("arn:aws-us-gov:iam::111111111111:role/management", "222222222222",
+			defaultOrganizationsMemberRoleName)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(roleARN).Should(Equal("arn:aws-us-gov:iam::222222222222:role/OrganizationAccountAccessRole"))
+		_, err = getMemberRoleARN("management", "222222222222", defaultOrganizationsMemberRoleName)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should report the DB services of the member accounts with the status of each account", func() {
+		var assumedRoles [][]string
+		r := &RDSInventoryReconciler{
+			GetListAccountsForParentPaginatorAPI: func(accessKey, secretKey, region, roleARN, parentID string) controllersorganizations.ListAccountsForParentPaginatorAPI {
+				return &accountsPaginator{accounts: []organizationstypes.Account{
+					{Id: pointer.String("111111111111"), Status: organizationstypes.AccountStatusActive},
+					{Id: pointer.String("222222222222"), Status: organizationstypes.AccountStatusActive},
+					{Id: pointer.String("333333333333"), Status: organizationstypes.AccountStatusActive},
+					{Id: pointer.String("444444444444"), Status: organizationstypes.AccountStatusSuspended},
+				}}
+			},
+			GetAssumeRoleDescribeDBInstancesPaginatorAPI: func(accessKey, secretKey, region string, roleARNs ...string) controllersrds.DescribeDBInstancesPaginatorAPI {
+				assumedRoles = append(assumedRoles, roleARNs)
+				if roleARNs[1] == "arn:aws:iam::333333333333:role/reader" {
+					return &memberDBInstancesPaginator{err: fmt.Errorf("access denied")}
+				}
+				return &memberDBInstancesPaginator{instances: []rdstypesv2.DBInstance{
+					{DBInstanceIdentifier: pointer.String("db-1"), Engine: pointer.String("postgres")},
+					{DBInstanceIdentifier: pointer.String("db-2"), DBClusterIdentifier: pointer.String("cluster-1")},
+				}}
+			},
+		}
+		inventory := &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					organizationsRoleARNAnnotation:        "arn:aws:iam::111111111111:role/management",
+					organizationsUnitIDAnnotation:         "ou-abcd-12345678",
+					organizationsMemberRoleNameAnnotation: "reader",
+				},
+			},
+		}
+		inventory.Status.Conditions = []metav1.Condition{
+			{Type: inventoryConditionAccountSyncedPrefix + "555555555555", Status: metav1.ConditionTrue, Reason: inventoryStatusReasonSyncOK},
+		}
+		local := []dbaasv1beta1.DatabaseService{
+			{ServiceID: "local", ServiceInfo: map[string]string{serviceInfoOwnerAccountID: "111111111111"}},
+		}
+
+		services := r.syncOrganizationAccounts(context.TODO(), inventory, "key", "secret", "us-east-1", local)
+		Expect(services).Should(HaveLen(1))
+		Expect(services[0].ServiceID).Should(Equal("222222222222/db-1"))
+		Expect(services[0].ServiceName).Should(Equal("db-1"))
+		Expect(services[0].ServiceInfo[serviceInfoOwnerAccountID]).Should(Equal("222222222222"))
+		Expect(assumedRoles).Should(ConsistOf(
+			[]string{"arn:aws:iam::111111111111:role/management", "arn:aws:iam::222222222222:role/reader"},
+			[]string{"arn:aws:iam::111111111111:role/management", "arn:aws:iam::333333333333:role/reader"},
+		))
+
+		Expect(apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionOrganizationSynced)).Should(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionAccountSyncedPrefix+"222222222222")).Should(BeTrue())
+		failed := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionAccountSyncedPrefix+"333333333333")
+		Expect(failed).ShouldNot(BeNil())
+		Expect(failed.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(failed.Message).Should(ContainSubstring("access denied"))
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionAccountSyncedPrefix+"444444444444")).Should(BeNil())
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionAccountSyncedPrefix+"555555555555")).Should(BeNil())
+	})
+
+	It("should remove the organization conditions once the discovery is not configured", func() {
+		r := &RDSInventoryReconciler{}
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+		inventory.Status.Conditions = []metav1.Condition{
+			{Type: inventoryConditionOrganizationSynced, Status: metav1.ConditionTrue, Reason: inventoryStatusReasonSyncOK},
+			{Type: inventoryConditionAccountSyncedPrefix + "222222222222", Status: metav1.ConditionTrue, Reason: inventoryStatusReasonSyncOK},
+			{Type: inventoryConditionReady, Status: metav1.ConditionTrue, Reason: inventoryStatusReasonSyncOK},
+		}
+		Expect(r.syncOrganizationAccounts(context.TODO(), inventory, "key", "secret", "us-east-1", nil)).Should(BeEmpty())
+		Expect(inventory.Status.Conditions).Should(HaveLen(1))
+		Expect(inventory.Status.Conditions[0].Type).Should(Equal(inventoryConditionReady))
+	})
+})
@@ -0,0 +1,106 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	inventoryConditionProvisioningAllowed = "ProvisioningAllowed"
+
+	inventoryStatusReasonProvisioningAllowed = "Allowed"
+	inventoryStatusReasonReadOnly            = "ReadOnly"
+
+	inventoryStatusMessageProvisioningAllowed = "Instances can be provisioned against the Inventory"
+	inventoryStatusMessageReadOnly            = "The DB services of the Inventory are only discovered and bound, Instances cannot be provisioned against it"
+)
+
+// setProvisioningAllowedCondition sets the ProvisioningAllowed condition of the Inventory from its annotation, the
+// condition is removed if the annotation is not set
+func setProvisioningAllowedCondition(inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	if _, ok := inventory.Annotations[rdsdbaasv1alpha1.AllowProvisioningAnnotation]; !ok {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionProvisioningAllowed)
+		return nil
+	}
+	allowed, err := inventory.IsProvisioningAllowed()
+	if err != nil {
+		return err
+	}
+	condition := metav1.Condition{
+		Type:    inventoryConditionProvisioningAllowed,
+		Status:  metav1.ConditionTrue,
+		Reason:  inventoryStatusReasonProvisioningAllowed,
+		Message: inventoryStatusMessageProvisioningAllowed,
+	}
+	if !allowed {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = inventoryStatusReasonReadOnly
+		condition.Message = inventoryStatusMessageReadOnly
+	}
+	apimeta.SetStatusCondition(&inventory.Status.Conditions, condition)
+	return nil
+}
+
+// isProvisioningInventory returns whether Instances can be provisioned against the Inventory, the Inventories with an
+// invalid annotation are not
+func isProvisioningInventory(inventory *rdsdbaasv1alpha1.RDSInventory) bool {
+	allowed, err := inventory.IsProvisioningAllowed()
+	return err == nil && allowed
+}
+
+// validateInventoryProvisioning rejects the provisioning of the Instance if the Inventory does not allow it, the
+// Instances provisioned before the Inventory is made read-only are still reconciled
+func (r *RDSInstanceReconciler) validateInventoryProvisioning(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	err := inventory.ValidateProvisioning()
+	if err == nil {
+		return nil
+	}
+	if provisioned, e := r.isInstanceProvisioned(ctx, rdsInstance, inventory); e != nil {
+		return e
+	} else if provisioned {
+		return nil
+	}
+	return err
+}
+
+// isInstanceProvisioned returns whether the DB Instance or the DB Cluster of the Instance is created
+func (r *RDSInstanceReconciler) isInstanceProvisioned(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error) {
+	if len(rdsInstance.Status.InstanceID) > 0 {
+		return true, nil
+	}
+	var dbService client.Object = &rdsv1alpha1.DBInstance{}
+	if isMultiAZClusterDeployment(rdsInstance) {
+		dbService = &rdsv1alpha1.DBCluster{}
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: rdsInstance.Name}, dbService); err == nil {
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+	return false, nil
+}
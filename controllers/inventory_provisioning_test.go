@@ -0,0 +1,56 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Inventory provisioning", func() {
+	It("should reflect the annotation of the Inventory in its condition", func() {
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+		Expect(setProvisioningAllowedCondition(inventory)).Should(Succeed())
+		Expect(inventory.Status.Conditions).Should(BeEmpty())
+		Expect(isProvisioningInventory(inventory)).Should(BeTrue())
+
+		inventory.Annotations = map[string]string{rdsdbaasv1alpha1.AllowProvisioningAnnotation: "false"}
+		Expect(setProvisioningAllowedCondition(inventory)).Should(Succeed())
+		condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionProvisioningAllowed)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).Should(Equal(inventoryStatusReasonReadOnly))
+		Expect(isProvisioningInventory(inventory)).Should(BeFalse())
+
+		inventory.Annotations[rdsdbaasv1alpha1.AllowProvisioningAnnotation] = "true"
+		Expect(setProvisioningAllowedCondition(inventory)).Should(Succeed())
+		Expect(apimeta.IsStatusConditionTrue(inventory.Status.Conditions, inventoryConditionProvisioningAllowed)).Should(BeTrue())
+
+		inventory.Annotations[rdsdbaasv1alpha1.AllowProvisioningAnnotation] = "maybe"
+		Expect(setProvisioningAllowedCondition(inventory)).ShouldNot(Succeed())
+		Expect(isProvisioningInventory(inventory)).Should(BeFalse())
+
+		delete(inventory.Annotations, rdsdbaasv1alpha1.AllowProvisioningAnnotation)
+		Expect(setProvisioningAllowedCondition(inventory)).Should(Succeed())
+		Expect(inventory.Status.Conditions).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the comma-separated namespaces, names or glob patterns such as team-*, whose Snapshots, Parameter Groups, Option
+	// Groups and Event Subscriptions may reference the Inventory in addition to the namespace of the Inventory
+	inventoryAllowedNamespacesAnnotation = "rds.dbaas.redhat.com/allowed-namespaces"
+	// the comma-separated IDs of the AWS accounts the Snapshots of the Inventory may share their DB snapshot with
+	inventorySnapshotShareAccountsAnnotation = "rds.dbaas.redhat.com/snapshot-share-accounts"
+	// the comma-separated Amazon S3 buckets, names or glob patterns, the Snapshots of the Inventory may export their DB
+	// snapshot to
+	inventorySnapshotExportBucketsAnnotation = "rds.dbaas.redhat.com/snapshot-export-buckets"
+
+	inventoryReferenceNamespaceErrorTemplate = "namespace %s is not allowed by the namespace policy of the operator"
+	inventoryReferenceErrorTemplate          = "Inventory %s/%s does not allow namespace %s in its %s annotation"
+	inventoryDBInstanceErrorTemplate         = "DB instance %s is not the DB instance of an Instance or a Connection of Inventory %s/%s in namespace %s"
+	inventoryShareAccountErrorTemplate       = "Inventory %s/%s does not allow sharing its DB snapshots with AWS account %s in its %s annotation"
+	inventoryExportBucketErrorTemplate       = "Inventory %s/%s does not allow exporting its DB snapshots to Amazon S3 bucket %s in its %s annotation"
+)
+
+// checkInventoryReference returns an error if the object of the namespace may not use the Inventory. The namespace must
+// be allowed by the namespace policy, and be the namespace of the Inventory or one of the namespaces allowed by the
+// annotation of the Inventory, which is set by the owner of the Inventory.
+func checkInventoryReference(policy *NamespacePolicy, namespace string, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	if !policy.isAllowed(namespace) {
+		return fmt.Errorf(inventoryReferenceNamespaceErrorTemplate, namespace)
+	}
+	if namespace == inventory.Namespace ||
+		matchNamespace(splitAnnotationList(inventory.Annotations[inventoryAllowedNamespacesAnnotation]), namespace) {
+		return nil
+	}
+	return fmt.Errorf(inventoryReferenceErrorTemplate, inventory.Namespace, inventory.Name, namespace,
+		inventoryAllowedNamespacesAnnotation)
+}
+
+// checkDBInstanceReference returns an error if the object of another namespace than the Inventory references a DB
+// instance that is not the DB instance of an Instance of its namespace, or bound by a Connection of its namespace. The
+// objects of the namespace of the Inventory may reference all its DB instances.
+func checkDBInstanceReference(ctx context.Context, reader client.Reader, namespace string, inventory *rdsdbaasv1alpha1.RDSInventory,
+	dbInstanceID string) error {
+	if namespace == inventory.Namespace {
+		return nil
+	}
+	inventoryRef := dbaasv1beta1.NamespacedName{Namespace: inventory.Namespace, Name: inventory.Name}
+
+	instances := &rdsdbaasv1alpha1.RDSInstanceList{}
+	if err := reader.List(ctx, instances, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for i := range instances.Items {
+		if instances.Items[i].Spec.InventoryRef == inventoryRef && instances.Items[i].Status.InstanceID == dbInstanceID {
+			return nil
+		}
+	}
+	connections := &rdsdbaasv1alpha1.RDSConnectionList{}
+	if err := reader.List(ctx, connections, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for i := range connections.Items {
+		c := &connections.Items[i]
+		if c.Spec.InventoryRef == inventoryRef && c.Spec.DatabaseServiceID == dbInstanceID &&
+			(c.Spec.DatabaseServiceType == nil || *c.Spec.DatabaseServiceType != clusterType) {
+			return nil
+		}
+	}
+	return fmt.Errorf(inventoryDBInstanceErrorTemplate, dbInstanceID, inventory.Namespace, inventory.Name, namespace)
+}
+
+// checkSnapshotDestinations returns an error if the Snapshot shares its DB snapshot with an AWS account or exports it to
+// an Amazon S3 bucket that the annotations of its Inventory do not allow
+func checkSnapshotDestinations(snapshot *rdsdbaasv1alpha1.RDSSnapshot, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	accounts := map[string]bool{}
+	for _, account := range splitAnnotationList(inventory.Annotations[inventorySnapshotShareAccountsAnnotation]) {
+		accounts[account] = true
+	}
+	for _, account := range snapshot.Spec.ShareWithAccounts {
+		if !accounts[account] {
+			return fmt.Errorf(inventoryShareAccountErrorTemplate, inventory.Namespace, inventory.Name, account,
+				inventorySnapshotShareAccountsAnnotation)
+		}
+	}
+	if snapshot.Spec.Export != nil {
+		allowed := false
+		for _, pattern := range splitAnnotationList(inventory.Annotations[inventorySnapshotExportBucketsAnnotation]) {
+			if ok, _ := path.Match(pattern, snapshot.Spec.Export.S3BucketName); ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf(inventoryExportBucketErrorTemplate, inventory.Namespace, inventory.Name,
+				snapshot.Spec.Export.S3BucketName, inventorySnapshotExportBucketsAnnotation)
+		}
+	}
+	return nil
+}
+
+func splitAnnotationList(value string) []string {
+	var values []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	controllersrdstest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds/test"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// recordingModifyDBSnapshotAttribute records the AWS accounts the DB snapshots are shared with
+type recordingModifyDBSnapshotAttribute struct {
+	shared map[string][]string
+}
+
+func (m *recordingModifyDBSnapshotAttribute) ModifyDBSnapshotAttribute(ctx context.Context, params *rds.ModifyDBSnapshotAttributeInput,
+	optFns ...func(*rds.Options)) (*rds.ModifyDBSnapshotAttributeOutput, error) {
+	m.shared[*params.DBSnapshotIdentifier] = append(m.shared[*params.DBSnapshotIdentifier], params.ValuesToAdd...)
+	return &rds.ModifyDBSnapshotAttributeOutput{}, nil
+}
+
+var _ = Describe("InventoryReference", func() {
+	ctx := context.Background()
+
+	newInventory := func(annotations map[string]string) *rdsdbaasv1alpha1.RDSInventory {
+		return &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "admin", Name: "inventory", Annotations: annotations},
+			Spec: dbaasv1beta1.DBaaSInventorySpec{
+				CredentialsRef: &dbaasv1beta1.LocalObjectReference{Name: "credentials"},
+			},
+			Status: dbaasv1beta1.DBaaSInventoryStatus{
+				Conditions: []metav1.Condition{{Type: inventoryConditionReady, Status: metav1.ConditionTrue,
+					Reason: inventoryStatusReasonSyncOK, LastTransitionTime: metav1.Now()}},
+			},
+		}
+	}
+
+	It("should only allow the namespace of the Inventory and the namespaces of its annotation", func() {
+		inventory := newInventory(nil)
+		Expect(checkInventoryReference(nil, "admin", inventory)).Should(Succeed())
+		Expect(checkInventoryReference(nil, "team-a", inventory)).Should(MatchError(ContainSubstring(inventoryAllowedNamespacesAnnotation)))
+
+		inventory = newInventory(map[string]string{inventoryAllowedNamespacesAnnotation: "team-*, staging"})
+		Expect(checkInventoryReference(nil, "team-a", inventory)).Should(Succeed())
+		Expect(checkInventoryReference(nil, "staging", inventory)).Should(Succeed())
+		Expect(checkInventoryReference(nil, "prod", inventory)).Should(HaveOccurred())
+	})
+
+	It("should apply the namespace policy", func() {
+		p, e := NewNamespacePolicy("admin", "", NamespacePolicyDeny)
+		Expect(e).ShouldNot(HaveOccurred())
+		inventory := newInventory(map[string]string{inventoryAllowedNamespacesAnnotation: "*"})
+		Expect(checkInventoryReference(p, "admin", inventory)).Should(Succeed())
+		Expect(checkInventoryReference(p, "team-a", inventory)).Should(MatchError(ContainSubstring("namespace policy")))
+	})
+
+	Context("when the Snapshot is reconciled", func() {
+		var modify *recordingModifyDBSnapshotAttribute
+
+		newReconciler := func(objects ...client.Object) *RDSSnapshotReconciler {
+			credentials := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "admin", Name: "credentials"},
+				Data: map[string][]byte{
+					awsAccessKeyID:     []byte("AKIA"),
+					awsSecretAccessKey: []byte("secret"),
+					awsRegion:          []byte("us-east-1"),
+				},
+			}
+			modify = &recordingModifyDBSnapshotAttribute{shared: map[string][]string{}}
+			return &RDSSnapshotReconciler{
+				Client:                    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, credentials)...).Build(),
+				GetCreateDBSnapshotAPI:    controllersrdstest.NewCreateDBSnapshot,
+				GetDescribeDBSnapshotsAPI: controllersrdstest.NewDescribeDBSnapshots,
+				GetModifyDBSnapshotAttributeAPI: func(accessKey, secretKey, region string) controllersrds.ModifyDBSnapshotAttributeAPI {
+					return modify
+				},
+				GetStartExportTaskAPI:     controllersrdstest.NewStartExportTask,
+				GetDescribeExportTasksAPI: controllersrdstest.NewDescribeExportTasks,
+			}
+		}
+
+		newSnapshot := func(namespace, name, dbInstanceID string) *rdsdbaasv1alpha1.RDSSnapshot {
+			return &rdsdbaasv1alpha1.RDSSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Spec: rdsdbaasv1alpha1.RDSSnapshotSpec{
+					InventoryRef: dbaasv1beta1.NamespacedName{Namespace: "admin", Name: "inventory"},
+					DBInstanceID: dbInstanceID,
+				},
+			}
+		}
+
+		reconcile := func(r *RDSSnapshotReconciler, snapshot *rdsdbaasv1alpha1.RDSSnapshot) *metav1.Condition {
+			_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(snapshot)})
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot)).Should(Succeed())
+			return apimeta.FindStatusCondition(snapshot.Status.Conditions, snapshotConditionReady)
+		}
+
+		It("should create, share and export the DB snapshot to the destinations allowed by the Inventory", func() {
+			snapshot := newSnapshot("admin", "snapshot-reference-allowed", "db-admin")
+			snapshot.Spec.ShareWithAccounts = []string{"111111111111"}
+			snapshot.Spec.Export = &rdsdbaasv1alpha1.RDSSnapshotExport{
+				S3BucketName: "exports-admin",
+				IAMRoleARN:   "arn:aws:iam::123456789012:role/export",
+				KMSKeyID:     "key",
+			}
+			inventory := newInventory(map[string]string{
+				inventorySnapshotShareAccountsAnnotation: "111111111111, 222222222222",
+				inventorySnapshotExportBucketsAnnotation: "exports-*",
+			})
+			r := newReconciler(inventory, snapshot)
+
+			condition := reconcile(r, snapshot)
+			Expect(condition).ShouldNot(BeNil())
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(snapshot.Status.SnapshotARN).Should(ContainSubstring("snapshot-reference-allowed"))
+			Expect(modify.shared).Should(Equal(map[string][]string{"snapshot-reference-allowed": {"111111111111"}}))
+			Expect(snapshot.Status.ExportS3Location).Should(HavePrefix("s3://exports-admin/"))
+		})
+
+		It("should not share the DB snapshot with the AWS accounts not allowed by the Inventory", func() {
+			snapshot := newSnapshot("admin", "snapshot-reference-share", "db-admin")
+			snapshot.Spec.ShareWithAccounts = []string{"333333333333"}
+			r := newReconciler(newInventory(map[string]string{inventorySnapshotShareAccountsAnnotation: "111111111111"}), snapshot)
+
+			condition := reconcile(r, snapshot)
+			Expect(condition.Reason).Should(Equal(snapshotStatusReasonNotAllowed))
+			Expect(snapshot.Status.SnapshotARN).Should(BeEmpty())
+			Expect(modify.shared).Should(BeEmpty())
+		})
+
+		It("should not export the DB snapshot to the Amazon S3 buckets not allowed by the Inventory", func() {
+			snapshot := newSnapshot("admin", "snapshot-reference-export", "db-admin")
+			snapshot.Spec.Export = &rdsdbaasv1alpha1.RDSSnapshotExport{
+				S3BucketName: "attacker-bucket",
+				IAMRoleARN:   "arn:aws:iam::123456789012:role/export",
+				KMSKeyID:     "key",
+			}
+			r := newReconciler(newInventory(nil), snapshot)
+
+			condition := reconcile(r, snapshot)
+			Expect(condition.Reason).Should(Equal(snapshotStatusReasonNotAllowed))
+			Expect(snapshot.Status.SnapshotARN).Should(BeEmpty())
+			Expect(snapshot.Status.ExportTaskID).Should(BeEmpty())
+		})
+
+		It("should not use the Inventory of another namespace not allowing the namespace of the Snapshot", func() {
+			snapshot := newSnapshot("team-a", "snapshot-reference-namespace", "db-admin")
+			r := newReconciler(newInventory(nil), snapshot)
+
+			condition := reconcile(r, snapshot)
+			Expect(condition.Reason).Should(Equal(snapshotStatusReasonNotAllowed))
+			Expect(snapshot.Status.SnapshotARN).Should(BeEmpty())
+		})
+
+		It("should only snapshot the DB instances of the Instances and Connections of the namespace of the Snapshot", func() {
+			inventory := newInventory(map[string]string{inventoryAllowedNamespacesAnnotation: "team-a"})
+			instance := &rdsdbaasv1alpha1.RDSInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "instance"},
+				Spec: dbaasv1beta1.DBaaSInstanceSpec{
+					InventoryRef: dbaasv1beta1.NamespacedName{Namespace: "admin", Name: "inventory"},
+				},
+				Status: dbaasv1beta1.DBaaSInstanceStatus{InstanceID: "db-team-a"},
+			}
+			connection := &rdsdbaasv1alpha1.RDSConnection{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "connection"},
+				Spec: dbaasv1beta1.DBaaSConnectionSpec{
+					InventoryRef:      dbaasv1beta1.NamespacedName{Namespace: "admin", Name: "inventory"},
+					DatabaseServiceID: "db-team-a-bound",
+				},
+			}
+			other := newSnapshot("team-a", "snapshot-reference-other", "db-admin")
+			owned := newSnapshot("team-a", "snapshot-reference-owned", "db-team-a")
+			bound := newSnapshot("team-a", "snapshot-reference-bound", "db-team-a-bound")
+			r := newReconciler(inventory, instance, connection, other, owned, bound)
+
+			condition := reconcile(r, other)
+			Expect(condition.Reason).Should(Equal(snapshotStatusReasonNotAllowed))
+			Expect(other.Status.SnapshotARN).Should(BeEmpty())
+
+			condition = reconcile(r, owned)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(owned.Status.SnapshotARN).ShouldNot(BeEmpty())
+
+			condition = reconcile(r, bound)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(bound.Status.SnapshotARN).ShouldNot(BeEmpty())
+		})
+	})
+
+	It("should not let another namespace adopt or delete the DB parameter groups of the Inventory", func() {
+		credentials := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "admin", Name: "credentials"},
+			Data: map[string][]byte{
+				awsAccessKeyID:     []byte("AKIA"),
+				awsSecretAccessKey: []byte("secret"),
+				awsRegion:          []byte("us-east-1"),
+			},
+		}
+		_, e := controllersrdstest.NewCreateDBParameterGroup("AKIA", "secret", "us-east-1").CreateDBParameterGroup(ctx,
+			&rds.CreateDBParameterGroupInput{
+				DBParameterGroupName:   pointer.String("admin-reference-group"),
+				DBParameterGroupFamily: pointer.String("postgres14"),
+				Description:            pointer.String("admin"),
+			})
+		Expect(e).ShouldNot(HaveOccurred())
+		parameterGroup := &rdsdbaasv1alpha1.RDSParameterGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "parameter-group"},
+			Spec: rdsdbaasv1alpha1.RDSParameterGroupSpec{
+				InventoryRef:       dbaasv1beta1.NamespacedName{Namespace: "admin", Name: "inventory"},
+				ParameterGroupName: "admin-reference-group",
+				Family:             "postgres14",
+			},
+		}
+		inventory := newInventory(map[string]string{inventoryAllowedNamespacesAnnotation: "team-a"})
+		r := &RDSParameterGroupReconciler{
+			Client:                          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory, credentials, parameterGroup).Build(),
+			GetCreateDBParameterGroupAPI:    controllersrdstest.NewCreateDBParameterGroup,
+			GetDescribeDBParameterGroupsAPI: controllersrdstest.NewDescribeDBParameterGroups,
+			GetModifyDBParameterGroupAPI:    controllersrdstest.NewModifyDBParameterGroup,
+			GetDeleteDBParameterGroupAPI:    controllersrdstest.NewDeleteDBParameterGroup,
+			GetDescribeDBParametersAPI:      controllersrdstest.NewDescribeDBParameters,
+		}
+
+		_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(parameterGroup)})
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(parameterGroup), parameterGroup)).Should(Succeed())
+		condition := apimeta.FindStatusCondition(parameterGroup.Status.Conditions, parameterGroupConditionReady)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Reason).Should(Equal(parameterGroupStatusReasonNotAllowed))
+		Expect(parameterGroup.Status.ParameterGroupARN).Should(BeEmpty())
+
+		Expect(r.Delete(ctx, parameterGroup)).Should(Succeed())
+		_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(parameterGroup)})
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(parameterGroup), parameterGroup)).ShouldNot(Succeed())
+		output, e := controllersrdstest.NewDescribeDBParameterGroups("AKIA", "secret", "us-east-1").DescribeDBParameterGroups(ctx,
+			&rds.DescribeDBParameterGroupsInput{DBParameterGroupName: pointer.String("admin-reference-group")})
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(output.DBParameterGroups).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InventoryRole", func() {
+	ctx := context.Background()
+
+	It("should not assume a role with the static credentials", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			awsAccessKeyID:     []byte("AKIA"),
+			awsSecretAccessKey: []byte("secret"),
+			awsRegion:          []byte("us-east-1"),
+			awsRoleARN:         []byte("arn:aws:iam::222222222222:role/rds-inventory"),
+		}}
+		expiry, err := assumeInventoryRole(ctx, secret)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(expiry).Should(BeZero())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("AKIA"))
+	})
+
+	It("should not set credentials without static credentials and role", func() {
+		secret := &v1.Secret{Data: map[string][]byte{awsRegion: []byte("us-east-1")}}
+		_, err := assumeInventoryRole(ctx, secret)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(secret.Data).ShouldNot(HaveKey(awsAccessKeyID))
+	})
+
+	It("should read the role of the lower-case keys and of the credentials file", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			"role_arn": []byte("arn:aws:iam::222222222222:role/rds-inventory"),
+			"region":   []byte("us-east-1"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsRoleARN])).Should(Equal("arn:aws:iam::222222222222:role/rds-inventory"))
+
+		secret = &v1.Secret{Data: map[string][]byte{
+			credentialsFileKey: []byte("[default]\nrole_arn = arn:aws:iam::222222222222:role/rds-file\nregion = us-east-1\n"),
+		}}
+		Expect(normalizeCredentials(secret)).Should(Succeed())
+		Expect(string(secret.Data[awsRoleARN])).Should(Equal("arn:aws:iam::222222222222:role/rds-file"))
+	})
+
+	It("should fail to assume the role if the web identity is not enabled", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			awsRegion:  []byte("us-east-1"),
+			awsRoleARN: []byte("arn:aws:iam::222222222222:role/rds-inventory"),
+		}}
+		_, err := assumeInventoryRole(ctx, secret)
+		Expect(err).Should(MatchError(ContainSubstring("web identity of the operator is not enabled")))
+		Expect(secret.Data).ShouldNot(HaveKey(awsAccessKeyID))
+	})
+
+	It("should require the region to assume the role", func() {
+		secret := &v1.Secret{Data: map[string][]byte{
+			awsRoleARN: []byte("arn:aws:iam::222222222222:role/rds-inventory"),
+		}}
+		_, err := assumeInventoryRole(ctx, secret)
+		Expect(err).Should(MatchError(ContainSubstring(awsRegion)))
+	})
+
+	Context("when the web identity is enabled", func() {
+		BeforeEach(func() {
+			w, err := controllerssts.NewWebIdentity("arn:aws:iam::111111111111:role/operator", "/var/run/token", "",
+				[]string{"arn:aws:iam::222222222222:role/rds-*"})
+			Expect(err).ShouldNot(HaveOccurred())
+			controllerssts.EnableWebIdentity(w)
+		})
+
+		AfterEach(func() {
+			controllerssts.EnableWebIdentity(nil)
+		})
+
+		It("should not assume the roles that are not allowed for the Inventory", func() {
+			inventory := &rdsdbaasv1alpha1.RDSInventory{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "inventory"},
+				Spec: dbaasv1beta1.DBaaSInventorySpec{
+					CredentialsRef: &dbaasv1beta1.LocalObjectReference{Name: "credentials"},
+				},
+			}
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "credentials"},
+				Data: map[string][]byte{
+					awsRegion:  []byte("us-east-1"),
+					awsRoleARN: []byte("arn:aws:iam::111111111111:role/operator"),
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+			err := getInventoryCredentials(ctx, c, inventory, &v1.Secret{})
+			Expect(err).Should(MatchError(ContainSubstring("is not allowed")))
+		})
+	})
+
+	It("should pass the temporary credentials to the RDS controller and restart it when they change", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ack-system", Name: ackDeploymentName},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(0)},
+		}
+		c := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment).Build()}
+		r := &RDSInventoryReconciler{Client: c, ACKInstallNamespace: "ack-system"}
+		credentials := &v1.Secret{Data: map[string][]byte{
+			awsAccessKeyID:     []byte("ASIA1"),
+			awsSecretAccessKey: []byte("secret1"),
+			awsSessionToken:    []byte("token1"),
+		}}
+
+		Expect(r.createOrUpdateSecret(ctx, c, credentials)).Should(Succeed())
+		secret := &v1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "ack-system", Name: secretName}, secret)).Should(Succeed())
+		Expect(string(secret.Data[awsAccessKeyID])).Should(Equal("ASIA1"))
+		Expect(string(secret.Data[awsSessionToken])).Should(Equal("token1"))
+
+		Expect(r.startRDSController(ctx, getCredentialsFingerprint(credentials))).Should(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).Should(Succeed())
+		Expect(*deployment.Spec.Replicas).Should(BeEquivalentTo(1))
+		fingerprint := deployment.Spec.Template.Annotations[ackCredentialsFingerprintAnnotation]
+		Expect(fingerprint).ShouldNot(BeEmpty())
+
+		credentials.Data[awsAccessKeyID] = []byte("ASIA2")
+		credentials.Data[awsSessionToken] = []byte("token2")
+		Expect(r.startRDSController(ctx, getCredentialsFingerprint(credentials))).Should(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).Should(Succeed())
+		Expect(deployment.Spec.Template.Annotations[ackCredentialsFingerprintAnnotation]).ShouldNot(Equal(fingerprint))
+	})
+})
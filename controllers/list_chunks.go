@@ -0,0 +1,53 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listChunkSize is the number of objects listed per request by listInChunks, as the default page size of client-go
+const listChunkSize = 500
+
+// listInChunks lists the objects from the API server in chunks of listChunkSize objects with continue tokens, so that
+// listing very large numbers of objects does not time out, and sets all the items on the list. It is used with the
+// API readers not backed by the cache: the cache lists the objects from memory and does not support continue tokens,
+// its lists would be truncated.
+func listInChunks(ctx context.Context, reader client.Reader, list client.ObjectList, opts ...client.ListOption) error {
+	var items []runtime.Object
+	var continueToken string
+	for {
+		page := list.DeepCopyObject().(client.ObjectList)
+		pageOpts := append([]client.ListOption{client.Limit(listChunkSize), client.Continue(continueToken)}, opts...)
+		if err := reader.List(ctx, page, pageOpts...); err != nil {
+			return err
+		}
+		pageItems, err := apimeta.ExtractList(page)
+		if err != nil {
+			return err
+		}
+		items = append(items, pageItems...)
+		if continueToken = page.GetContinue(); len(continueToken) == 0 {
+			list.SetResourceVersion(page.GetResourceVersion())
+			return apimeta.SetList(list, items)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+// pagingReader serves the Inventories in pages of the requested limit, the continue token is the offset
+type pagingReader struct {
+	inventories []rdsdbaasv1alpha1.RDSInventory
+	requests    int
+}
+
+func (p *pagingReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (p *pagingReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	p.requests++
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	offset := 0
+	if len(listOpts.Continue) > 0 {
+		offset, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := offset + int(listOpts.Limit)
+	inventoryList := list.(*rdsdbaasv1alpha1.RDSInventoryList)
+	inventoryList.ResourceVersion = "1"
+	if end < len(p.inventories) {
+		inventoryList.Continue = strconv.Itoa(end)
+	} else {
+		end = len(p.inventories)
+	}
+	inventoryList.Items = p.inventories[offset:end]
+	return nil
+}
+
+var _ = Describe("List chunks", func() {
+	It("should list all the objects in chunks", func() {
+		reader := &pagingReader{}
+		for i := 0; i < listChunkSize*2+1; i++ {
+			reader.inventories = append(reader.inventories, rdsdbaasv1alpha1.RDSInventory{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("inventory-%d", i)},
+			})
+		}
+		inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+		Expect(listInChunks(context.TODO(), reader, inventoryList, client.InNamespace("default"))).Should(Succeed())
+		Expect(reader.requests).Should(Equal(3))
+		Expect(inventoryList.Items).Should(HaveLen(listChunkSize*2 + 1))
+		Expect(inventoryList.Items[listChunkSize*2].Name).Should(Equal(fmt.Sprintf("inventory-%d", listChunkSize*2)))
+		Expect(inventoryList.Continue).Should(BeEmpty())
+	})
+})
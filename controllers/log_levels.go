@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+)
+
+const (
+	// LogLevelsConfigMapName is the ConfigMap in the install namespace of the operator holding the log levels,
+	// the key default sets the level of all the controllers and the controller names (e.g. rdsinventory) set their own levels
+	LogLevelsConfigMapName = "rds-dbaas-operator-log-levels"
+
+	logLevelsDefaultKey = "default"
+
+	defaultLogLevelsRefreshInterval = 30 * time.Second
+	// the time before watching the ConfigMap again once its watch ended
+	logLevelsRewatchDelay = time.Second
+)
+
+// LogLevelsWatcher applies the log levels of the ConfigMap, the runtime configuration of the logging of the operator,
+// as soon as it changes, the levels of the command line are restored once the ConfigMap is deleted
+type LogLevelsWatcher struct {
+	// APIReader reads the ConfigMap that is not labeled for the cache
+	APIReader client.Reader
+	// Watcher watches the changes of the ConfigMap, the ConfigMap is only read at the refresh interval if not set
+	Watcher      client.WithWatch
+	Namespace    string
+	Levels       *logging.Levels
+	DefaultLevel zapcore.Level
+	// the levels are also refreshed at the interval, in case the watch missed a change
+	RefreshInterval time.Duration
+}
+
+// NeedLeaderElection makes all the replicas apply the log levels
+func (w *LogLevelsWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start applies the log levels on the changes of the ConfigMap until the manager stops
+func (w *LogLevelsWatcher) Start(ctx context.Context) error {
+	interval := w.RefreshInterval
+	if interval <= 0 {
+		interval = defaultLogLevelsRefreshInterval
+	}
+	var current map[string]string
+	for {
+		current = w.refresh(ctx, current)
+		events, stop := w.watch(ctx)
+		timer := time.NewTimer(interval)
+		watched, closed := true, false
+		for watched {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				stop()
+				return nil
+			case _, ok := <-events:
+				if !ok {
+					watched, closed = false, true
+					break
+				}
+				current = w.refresh(ctx, current)
+			case <-timer.C:
+				watched = false
+			}
+		}
+		timer.Stop()
+		stop()
+		if closed {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(logLevelsRewatchDelay):
+			}
+		}
+	}
+}
+
+// watch returns the events of the ConfigMap and the function stopping its watch, no event is returned if the
+// ConfigMap is not watched
+func (w *LogLevelsWatcher) watch(ctx context.Context) (<-chan watch.Event, func()) {
+	if w.Watcher == nil {
+		return nil, func() {}
+	}
+	watcher, err := w.Watcher.Watch(ctx, &v1.ConfigMapList{}, client.InNamespace(w.Namespace),
+		client.MatchingFields{"metadata.name": LogLevelsConfigMapName})
+	if err != nil {
+		log.FromContext(ctx).WithName("log-levels").Error(err, "Failed to watch log levels ConfigMap")
+		return nil, func() {}
+	}
+	return watcher.ResultChan(), watcher.Stop
+}
+
+func (w *LogLevelsWatcher) refresh(ctx context.Context, current map[string]string) map[string]string {
+	logger := log.FromContext(ctx).WithName("log-levels")
+
+	cm := &v1.ConfigMap{}
+	data := map[string]string{}
+	if err := w.APIReader.Get(ctx, client.ObjectKey{Namespace: w.Namespace, Name: LogLevelsConfigMapName}, cm); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get log levels ConfigMap")
+			return current
+		}
+	} else if cm.Data != nil {
+		data = cm.Data
+	}
+	if current != nil && reflect.DeepEqual(current, data) {
+		return current
+	}
+
+	defaultLevel := w.DefaultLevel
+	levels := map[string]zapcore.Level{}
+	for key, value := range data {
+		level, err := logging.ParseLevel(value)
+		if err != nil {
+			logger.Error(err, "Invalid log level, ignored", "Key", key, "Level", value)
+			continue
+		}
+		if key == logLevelsDefaultKey {
+			defaultLevel = level
+		} else {
+			levels[key] = level
+		}
+	}
+	w.Levels.Set(defaultLevel, levels)
+	logger.Info("Log levels applied", "Default", defaultLevel, "Controllers", data)
+	return data
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+)
+
+var _ = Describe("LogLevelsWatcher", func() {
+	It("should apply the log levels as soon as the ConfigMap changes", func() {
+		levels := logging.NewLevels(zapcore.InfoLevel)
+		core, logs := observer.New(zapcore.Level(-127))
+		logger := zap.New(core, logging.WrapCore(levels)).With(zap.String("controller", "rdsinventory"))
+		cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		w := &LogLevelsWatcher{
+			APIReader:       cli,
+			Watcher:         cli,
+			Namespace:       "operator",
+			Levels:          levels,
+			DefaultLevel:    zapcore.InfoLevel,
+			RefreshInterval: time.Hour,
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(w.Start(ctx)).Should(Succeed())
+		}()
+
+		logger.Debug("debug")
+		Expect(logs.Len()).Should(BeZero())
+
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: LogLevelsConfigMapName},
+			Data:       map[string]string{"rdsinventory": "debug"},
+		}
+		Expect(cli.Create(ctx, cm)).Should(Succeed())
+		Eventually(func() int {
+			logger.Debug("debug")
+			return logs.FilterMessage("debug").Len()
+		}, 5*time.Second).ShouldNot(BeZero())
+
+		// the levels of the command line are restored once the ConfigMap is deleted
+		Expect(cli.Delete(ctx, cm)).Should(Succeed())
+		Eventually(func() bool {
+			return logger.Core().Enabled(zapcore.DebugLevel)
+		}, 5*time.Second).Should(BeFalse())
+	})
+})
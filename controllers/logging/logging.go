@@ -0,0 +1,208 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// the structured keys logged consistently across the controllers
+	KeyInventory    = "inventory"
+	KeyRegion       = "region"
+	KeyDBInstanceID = "dbInstanceID"
+	KeyAWSRequestID = "awsRequestID"
+
+	// the key of the controller name that controller-runtime adds to the loggers of the reconcilers
+	keyController = "controller"
+
+	redacted = "[REDACTED]"
+)
+
+// sensitiveKeys are the lower case key fragments of which the values are never logged, the keys of the fields and of
+// the maps logged as field values are matched, the fields of the logged structs are not inspected
+var sensitiveKeys = []string{"password", "secretkey", "secretaccesskey", "accesskey", "token", "privatekey", "credentials"}
+
+// Levels holds the default log level and the log levels by controller name, it can be changed at runtime
+type Levels struct {
+	mutex        sync.RWMutex
+	defaultLevel zapcore.Level
+	levels       map[string]zapcore.Level
+}
+
+// NewLevels returns the log levels with the default level for all the controllers
+func NewLevels(defaultLevel zapcore.Level) *Levels {
+	return &Levels{
+		defaultLevel: defaultLevel,
+		levels:       map[string]zapcore.Level{},
+	}
+}
+
+// Set replaces the default log level and the log levels by controller name
+func (l *Levels) Set(defaultLevel zapcore.Level, levels map[string]zapcore.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.defaultLevel = defaultLevel
+	l.levels = levels
+}
+
+func (l *Levels) enabled(controller string, level zapcore.Level) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if lvl, ok := l.levels[controller]; ok {
+		return lvl.Enabled(level)
+	}
+	return l.defaultLevel.Enabled(level)
+}
+
+// ParseLevel parses a zap level name (e.g. debug, info, error) or a logr verbosity (e.g. 2)
+func ParseLevel(text string) (zapcore.Level, error) {
+	text = strings.TrimSpace(text)
+	if v, err := strconv.Atoi(text); err == nil {
+		if v < 0 || v > 127 {
+			return zapcore.InfoLevel, fmt.Errorf("log verbosity %d is out of range", v)
+		}
+		return zapcore.Level(-v), nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(text))); err != nil {
+		return zapcore.InfoLevel, err
+	}
+	return level, nil
+}
+
+// WrapCore returns the zap option that filters the log entries by the level of their controller,
+// redacts the values of the sensitive keys of the fields and of the logged maps and adds the AWS request IDs
+// of the logged errors.
+// The level of the wrapped core needs to be low enough to not filter out the entries itself.
+func WrapCore(levels *Levels) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, levels: levels}
+	})
+}
+
+type levelCore struct {
+	zapcore.Core
+	levels     *Levels
+	controller string
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.enabled(c.controller, level) && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	controller := c.controller
+	for _, f := range fields {
+		if f.Key == keyController && f.Type == zapcore.StringType {
+			controller = f.String
+		}
+	}
+	return &levelCore{
+		Core:       c.Core.With(redactFields(fields)),
+		levels:     c.levels,
+		controller: controller,
+	}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *levelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	result := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if isSensitiveKey(f.Key) {
+			result = append(result, zap.String(f.Key, redacted))
+			continue
+		}
+		if f.Type == zapcore.ReflectType && f.Interface != nil {
+			if m, ok := redactMap(reflect.ValueOf(f.Interface)); ok {
+				result = append(result, zap.Any(f.Key, m))
+				continue
+			}
+		}
+		result = append(result, f)
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok {
+				var responseError *awshttp.ResponseError
+				if errors.As(err, &responseError) && len(responseError.ServiceRequestID()) > 0 {
+					result = append(result, zap.String(KeyAWSRequestID, responseError.ServiceRequestID()))
+				}
+			}
+		}
+	}
+	return result
+}
+
+// redactMap returns a copy of the map with string keys with the values of its sensitive keys redacted, including
+// in the nested maps, if it has sensitive keys
+func redactMap(v reflect.Value) (map[string]interface{}, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	m := make(map[string]interface{}, v.Len())
+	found := false
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if isSensitiveKey(key) {
+			m[key] = redacted
+			found = true
+			continue
+		}
+		if nested, ok := redactMap(iter.Value()); ok {
+			m[key] = nested
+			found = true
+			continue
+		}
+		m[key] = iter.Value().Interface()
+	}
+	return m, found
+}
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
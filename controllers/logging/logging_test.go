@@ -0,0 +1,115 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var _ = Describe("Logging", func() {
+	var levels *Levels
+	var logs *observer.ObservedLogs
+	var logger *zap.Logger
+
+	BeforeEach(func() {
+		levels = NewLevels(zapcore.InfoLevel)
+		var core zapcore.Core
+		core, logs = observer.New(zapcore.Level(-127))
+		logger = zap.New(core, WrapCore(levels))
+	})
+
+	It("should filter the log entries by the level of their controller", func() {
+		inventory := logger.With(zap.String(keyController, "rdsinventory"))
+		instance := logger.With(zap.String(keyController, "rdsinstance"))
+
+		inventory.Debug("inventory debug")
+		instance.Info("instance info")
+		Expect(logs.TakeAll()).Should(HaveLen(1))
+
+		levels.Set(zapcore.ErrorLevel, map[string]zapcore.Level{"rdsinventory": zapcore.DebugLevel})
+		inventory.Debug("inventory debug")
+		instance.Info("instance info")
+		instance.Error("instance error")
+		entries := logs.TakeAll()
+		Expect(entries).Should(HaveLen(2))
+		Expect(entries[0].Message).Should(Equal("inventory debug"))
+		Expect(entries[1].Message).Should(Equal("instance error"))
+
+		// the logr verbosities are negative zap levels
+		level, err := ParseLevel("2")
+		Expect(err).ShouldNot(HaveOccurred())
+		levels.Set(zapcore.InfoLevel, map[string]zapcore.Level{"rdsinstance": level})
+		instance.Check(zapcore.Level(-2), "instance verbose").Write()
+		instance.Check(zapcore.Level(-3), "instance more verbose").Write()
+		Expect(logs.FilterMessage("instance verbose").Len()).Should(Equal(1))
+		Expect(logs.FilterMessage("instance more verbose").Len()).Should(BeZero())
+	})
+
+	It("should parse the log levels", func() {
+		Expect(ParseLevel("debug")).Should(Equal(zapcore.DebugLevel))
+		Expect(ParseLevel(" ERROR ")).Should(Equal(zapcore.ErrorLevel))
+		Expect(ParseLevel("5")).Should(Equal(zapcore.Level(-5)))
+		_, err := ParseLevel("128")
+		Expect(err).Should(HaveOccurred())
+		_, err = ParseLevel("verbose")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should redact the values of the sensitive keys", func() {
+		logger.With(zap.String("AWS_SECRET_ACCESS_KEY", "secret")).Info("credentials",
+			zap.String("masterPassword", "password"), zap.String("user-token", "token"), zap.String(KeyInventory, "inventory"))
+		entry := logs.TakeAll()[0]
+		Expect(entry.ContextMap()).Should(Equal(map[string]interface{}{
+			"AWS_SECRET_ACCESS_KEY": redacted,
+			"masterPassword":        redacted,
+			"user-token":            redacted,
+			KeyInventory:            "inventory",
+		}))
+	})
+
+	It("should redact the values of the sensitive keys of the logged maps", func() {
+		logger.Info("secret", zap.Any("data", map[string][]byte{"username": []byte("user"), "password": []byte("password")}),
+			zap.Any("config", map[string]interface{}{"vault": map[string]string{"token": "token", "address": "vault"}}),
+			zap.Any("options", map[string]string{"region": "us-east-1"}))
+		fields := logs.TakeAll()[0].ContextMap()
+		Expect(fields["data"]).Should(Equal(map[string]interface{}{"username": []byte("user"), "password": redacted}))
+		Expect(fields["config"]).Should(Equal(map[string]interface{}{
+			"vault": map[string]interface{}{"token": redacted, "address": "vault"},
+		}))
+		Expect(fields["options"]).Should(Equal(map[string]string{"region": "us-east-1"}))
+	})
+
+	It("should add the AWS request IDs of the logged errors", func() {
+		err := &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 500}},
+				Err: errors.New("internal error")},
+			RequestID: "request-id",
+		}
+		logger.Error("AWS call failed", zap.Error(err))
+		Expect(logs.TakeAll()[0].ContextMap()).Should(HaveKeyWithValue(KeyAWSRequestID, "request-id"))
+	})
+})
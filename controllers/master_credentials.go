@@ -0,0 +1,250 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretsmanagertypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// store the master credentials of the provisioned DB instances in AWS Secrets Manager
+	masterCredentialsStoreAnnotation     = "rds.dbaas.redhat.com/master-credentials-store"
+	masterCredentialsStoreSecretsManager = "secretsmanager"
+	masterCredentialsStoredAnnotation    = "rds.dbaas.redhat.com/master-credentials-stored"
+	masterCredentialsSecretNameTemplate  = "rds-dbaas/%s"
+)
+
+// masterCredentials is the Secrets Manager secret value for the master credentials of a DB instance
+type masterCredentials struct {
+	Username             string `json:"username"`
+	Password             string `json:"password"`
+	Engine               string `json:"engine,omitempty"`
+	DBInstanceIdentifier string `json:"dbInstanceIdentifier,omitempty"`
+}
+
+func useSecretsManagerForMasterCredentials(inventory *rdsdbaasv1alpha1.RDSInventory) bool {
+	return inventory.Annotations[masterCredentialsStoreAnnotation] == masterCredentialsStoreSecretsManager
+}
+
+// isMasterPasswordReleased returns true if the master password of the DB instance is only kept in AWS Secrets Manager,
+// the Secret of the master password is kept until the credentials are stored and the DB instance is available in
+// AWS with them, including the reset of the master password of a clone
+func (r *RDSInstanceReconciler) isMasterPasswordReleased(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error) {
+	if !useSecretsManagerForMasterCredentials(inventory) {
+		return false, nil
+	}
+	existing := &rdsv1alpha1.DBInstance{}
+	if e := r.Get(ctx, client.ObjectKeyFromObject(dbInstance), existing); e != nil {
+		if errors.IsNotFound(e) {
+			return false, nil
+		}
+		return false, e
+	}
+	if existing.Annotations[masterCredentialsStoredAnnotation] != "true" {
+		return false, nil
+	}
+	// the Secret is no longer referenced once released
+	if existing.Spec.MasterUserPassword == nil {
+		return true, nil
+	}
+	if pointer.StringDeref(existing.Status.DBInstanceStatus, "") != "available" {
+		return false, nil
+	}
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[cloneFrom]; ok {
+		if condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionCloned); condition == nil ||
+			condition.Status != metav1.ConditionTrue {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// storeMasterCredentials writes the master credentials of the DB instance to AWS Secrets Manager once,
+// the connections of the DB instance read the credentials from AWS Secrets Manager. The Secret of the master password
+// is deleted once the DB instance no longer references it.
+func (r *RDSInstanceReconciler) storeMasterCredentials(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	if !useSecretsManagerForMasterCredentials(inventory) || r.GetCreateSecretAPI == nil || r.GetPutSecretValueAPI == nil {
+		return nil
+	}
+	if dbInstance.Annotations[masterCredentialsStoredAnnotation] == "true" {
+		if dbInstance.Spec.MasterUserPassword != nil {
+			return nil
+		}
+		passwordSecret := &v1.Secret{}
+		passwordSecret.Name = getCredentialsSecretName(dbInstance.Name)
+		passwordSecret.Namespace = rdsInstance.Namespace
+		if e := r.Delete(ctx, passwordSecret); e != nil && !errors.IsNotFound(e) {
+			return e
+		}
+		return nil
+	}
+	if dbInstance.Spec.DBInstanceIdentifier == nil || dbInstance.Spec.MasterUsername == nil || dbInstance.Spec.MasterUserPassword == nil {
+		return nil
+	}
+
+	passwordSecret := &v1.Secret{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: dbInstance.Spec.MasterUserPassword.Namespace,
+		Name: dbInstance.Spec.MasterUserPassword.Name}, passwordSecret); e != nil {
+		return e
+	}
+	credentials := masterCredentials{
+		Username:             *dbInstance.Spec.MasterUsername,
+		Password:             string(passwordSecret.Data[dbInstance.Spec.MasterUserPassword.Key]),
+		Engine:               pointer.StringDeref(dbInstance.Spec.Engine, ""),
+		DBInstanceIdentifier: *dbInstance.Spec.DBInstanceIdentifier,
+	}
+	value, e := json.Marshal(credentials)
+	if e != nil {
+		return e
+	}
+
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		return e
+	}
+	accessKey := string(secret.Data[awsAccessKeyID])
+	secretKey := string(secret.Data[awsSecretAccessKey])
+	region := string(secret.Data[awsRegion])
+
+	name := fmt.Sprintf(masterCredentialsSecretNameTemplate, *dbInstance.Spec.DBInstanceIdentifier)
+	createSecret := r.GetCreateSecretAPI(accessKey, secretKey, region)
+	if _, e := createSecret.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:               pointer.String(name),
+		Description:        pointer.String(fmt.Sprintf("Master credentials of DB instance %s", *dbInstance.Spec.DBInstanceIdentifier)),
+		SecretString:       pointer.String(string(value)),
+		ClientRequestToken: pointer.String(getClientToken(dbInstance.UID, "CreateSecret")),
+	}); e != nil {
+		var existsErr *secretsmanagertypes.ResourceExistsException
+		if !goerrors.As(e, &existsErr) {
+			return e
+		}
+		putSecretValue := r.GetPutSecretValueAPI(accessKey, secretKey, region)
+		if _, e := putSecretValue.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:     pointer.String(name),
+			SecretString: pointer.String(string(value)),
+		}); e != nil {
+			return e
+		}
+	}
+
+	patch := client.MergeFrom(dbInstance.DeepCopy())
+	if dbInstance.Annotations == nil {
+		dbInstance.Annotations = map[string]string{}
+	}
+	dbInstance.Annotations[masterCredentialsStoredAnnotation] = "true"
+	return r.Patch(ctx, dbInstance, patch)
+}
+
+// deleteMasterCredentials schedules the deletion of the master credentials of the deleted DB instance from AWS Secrets Manager
+func (r *RDSInstanceReconciler) deleteMasterCredentials(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	if r.GetDeleteSecretAPI == nil || len(rdsInstance.Status.InstanceID) == 0 {
+		return nil
+	}
+	inventory := &rdsdbaasv1alpha1.RDSInventory{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Spec.InventoryRef.Namespace, Name: rdsInstance.Spec.InventoryRef.Name}, inventory); e != nil {
+		if errors.IsNotFound(e) {
+			return nil
+		}
+		return e
+	}
+	if !useSecretsManagerForMasterCredentials(inventory) || inventory.Spec.CredentialsRef == nil {
+		return nil
+	}
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		if errors.IsNotFound(e) {
+			return nil
+		}
+		return e
+	}
+
+	deleteSecret := r.GetDeleteSecretAPI(string(secret.Data[awsAccessKeyID]), string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	if _, e := deleteSecret.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:             pointer.String(fmt.Sprintf(masterCredentialsSecretNameTemplate, rdsInstance.Status.InstanceID)),
+		RecoveryWindowInDays: pointer.Int64(7),
+	}); e != nil {
+		var notFoundErr *secretsmanagertypes.ResourceNotFoundException
+		if !goerrors.As(e, &notFoundErr) {
+			return e
+		}
+	}
+	return nil
+}
+
+// getMasterCredentials reads the master credentials of the DB service from AWS Secrets Manager,
+// nil is returned if the credentials are not stored in AWS Secrets Manager
+func (r *RDSConnectionReconciler) getMasterCredentials(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	serviceID string) (*masterCredentials, error) {
+	if !useSecretsManagerForMasterCredentials(inventory) {
+		return nil, nil
+	}
+	if r.Hub != nil {
+		// the spokes do not read the AWS credentials of the Inventories of the hub
+		return nil, fmt.Errorf("master credentials of service %s are stored in AWS Secrets Manager, which is not read in spoke mode", serviceID)
+	}
+	if r.GetGetSecretValueAPI == nil {
+		return nil, nil
+	}
+	secret := &v1.Secret{}
+	if err := getInventoryCredentials(ctx, r.Client, inventory, secret); err != nil {
+		return nil, err
+	}
+
+	getSecretValue := r.GetGetSecretValueAPI(string(secret.Data[awsAccessKeyID]), string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	output, err := getSecretValue.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: pointer.String(fmt.Sprintf(masterCredentialsSecretNameTemplate, serviceID)),
+	})
+	if err != nil {
+		var notFoundErr *secretsmanagertypes.ResourceNotFoundException
+		if goerrors.As(err, &notFoundErr) {
+			// the credentials of adopted DB services are not stored in AWS Secrets Manager
+			r.CircuitBreaker.recordSuccess(inventory.Namespace, inventory.Name)
+			return nil, nil
+		}
+		r.CircuitBreaker.recordFailure(inventory.Namespace, inventory.Name)
+		return nil, err
+	}
+	r.CircuitBreaker.recordSuccess(inventory.Namespace, inventory.Name)
+	if output.SecretString == nil {
+		return nil, fmt.Errorf("master credentials of service %s not valid", serviceID)
+	}
+	credentials := &masterCredentials{}
+	if err := json.Unmarshal([]byte(*output.SecretString), credentials); err != nil {
+		return nil, err
+	}
+	if len(credentials.Username) == 0 || len(credentials.Password) == 0 {
+		return nil, fmt.Errorf("master credentials of service %s not valid", serviceID)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllerssecretsmanagertest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager/test"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MasterCredentials", func() {
+	var inventory *rdsdbaasv1alpha1.RDSInventory
+	var rdsInstance *rdsdbaasv1alpha1.RDSInstance
+	var dbInstance *rdsv1alpha1.DBInstance
+
+	BeforeEach(func() {
+		inventory = &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "operator",
+				Name:        "inventory",
+				Annotations: map[string]string{masterCredentialsStoreAnnotation: masterCredentialsStoreSecretsManager},
+			},
+		}
+		rdsInstance = &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "instance"}}
+		dbInstance = &rdsv1alpha1.DBInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "operator",
+				Name:        "rhoda-postgres-instance",
+				Annotations: map[string]string{masterCredentialsStoredAnnotation: "true"},
+			},
+			Spec: rdsv1alpha1.DBInstanceSpec{
+				DBInstanceIdentifier: pointer.String("instance-a"),
+				MasterUsername:       pointer.String("postgres"),
+				MasterUserPassword: &ackv1alpha1.SecretKeyReference{
+					SecretReference: v1.SecretReference{Namespace: "operator", Name: getCredentialsSecretName("rhoda-postgres-instance")},
+					Key:             "password",
+				},
+			},
+			Status: rdsv1alpha1.DBInstanceStatus{DBInstanceStatus: pointer.String("available")},
+		}
+	})
+
+	newReconciler := func(objects ...client.Object) *RDSInstanceReconciler {
+		return &RDSInstanceReconciler{
+			Client:               fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build(),
+			GetCreateSecretAPI:   controllerssecretsmanagertest.NewCreateSecret,
+			GetPutSecretValueAPI: controllerssecretsmanagertest.NewPutSecretValue,
+		}
+	}
+
+	It("should release the master password once the stored DB instance is available", func() {
+		r := newReconciler(dbInstance.DeepCopy())
+		Expect(r.isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeTrue())
+
+		inventory.Annotations = nil
+		Expect(r.isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeFalse())
+	})
+
+	It("should keep the master password until the DB instance is available with the stored credentials", func() {
+		creating := dbInstance.DeepCopy()
+		creating.Status.DBInstanceStatus = pointer.String("creating")
+		Expect(newReconciler(creating).isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeFalse())
+
+		notStored := dbInstance.DeepCopy()
+		notStored.Annotations = nil
+		Expect(newReconciler(notStored).isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeFalse())
+
+		Expect(newReconciler().isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeFalse())
+	})
+
+	It("should keep the master password of a clone until its master password is reset", func() {
+		r := newReconciler(dbInstance.DeepCopy())
+		rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{cloneFrom: "source"}
+		Expect(r.isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeFalse())
+
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type: instanceConditionCloned, Status: metav1.ConditionTrue, Reason: instanceStatusReasonRestored,
+		})
+		Expect(r.isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeTrue())
+	})
+
+	It("should stay released once the DB instance no longer references the master password", func() {
+		released := dbInstance.DeepCopy()
+		released.Spec.MasterUserPassword = nil
+		released.Status.DBInstanceStatus = pointer.String("modifying")
+		Expect(newReconciler(released).isMasterPasswordReleased(context.Background(), dbInstance, rdsInstance, inventory)).Should(BeTrue())
+	})
+
+	It("should delete the Secret of the released master password", func() {
+		passwordSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: getCredentialsSecretName(dbInstance.Name)},
+			Data:       map[string][]byte{"password": []byte("password")},
+		}
+		r := newReconciler(passwordSecret)
+
+		Expect(r.storeMasterCredentials(context.Background(), dbInstance, rdsInstance, inventory)).Should(Succeed())
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(passwordSecret), &v1.Secret{})).Should(Succeed())
+
+		dbInstance.Spec.MasterUserPassword = nil
+		Expect(r.storeMasterCredentials(context.Background(), dbInstance, rdsInstance, inventory)).Should(Succeed())
+		Expect(errors.IsNotFound(r.Get(context.Background(), client.ObjectKeyFromObject(passwordSecret), &v1.Secret{}))).Should(BeTrue())
+		Expect(r.storeMasterCredentials(context.Background(), dbInstance, rdsInstance, inventory)).Should(Succeed())
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+)
+
+const (
+	// the master username of the DB instance, the default username of the engine is used if not set
+	masterUsername = "MasterUsername"
+
+	// the policy of the generated master password: its length, its charset (all or alphanumeric) and the characters
+	// excluded from the charset, e.g. the characters to escape in the connection strings of the applications
+	masterPasswordLength            = "MasterPasswordLength"
+	masterPasswordCharset           = "MasterPasswordCharset"
+	masterPasswordExcludeCharacters = "MasterPasswordExcludeCharacters"
+
+	passwordCharsetAll          = "all"
+	passwordCharsetAlphanumeric = "alphanumeric"
+
+	minPasswordLength     = 8
+	defaultPasswordLength = 12
+)
+
+var masterUsernameRegex = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_]*$")
+
+// reservedMasterUsernames are the usernames of the engines that RDS rejects as master username, by binding type
+var reservedMasterUsernames = map[string][]string{
+	"postgresql": {"rdsadmin", "rdsrepladmin", "rds_superuser", "rds_replication", "rds_password", "public", "user",
+		"current_user", "session_user", "all", "select"},
+	"mysql":     {"rdsadmin", "rdsrepladmin", "root", "mysql", "user", "select"},
+	"oracle":    {"rdsadmin", "rdsrepladmin", "sys", "system", "sysdba", "sysbackup", "dba", "public"},
+	"sqlserver": {"rdsa", "rdsadmin", "sa", "sysadmin", "guest", "dbo", "public"},
+}
+
+// passwordPolicy is the policy of a generated password, which has at least one letter, one digit and one special
+// character if the policy has any
+type passwordPolicy struct {
+	length   int
+	letters  string
+	digits   string
+	specials string
+}
+
+var defaultPasswordPolicy = passwordPolicy{
+	length:   defaultPasswordLength,
+	letters:  letter,
+	digits:   digits,
+	specials: specials,
+}
+
+// getMaxMasterUsernameLength returns the maximum length of the master username of the engine
+func getMaxMasterUsernameLength(engine string) int {
+	switch generateBindingType(engine) {
+	case "mysql":
+		return 16
+	case "oracle":
+		return 30
+	case "sqlserver":
+		return 128
+	default:
+		return 63
+	}
+}
+
+// getMaxMasterPasswordLength returns the maximum length of the master password of the engine
+func getMaxMasterPasswordLength(engine string) int {
+	switch generateBindingType(engine) {
+	case "mysql":
+		return 41
+	case "oracle":
+		return 30
+	default:
+		return 128
+	}
+}
+
+// validateMasterUsername checks the master username against the naming constraints and the reserved words of the engine
+func validateMasterUsername(engine, username string) error {
+	if len(username) > getMaxMasterUsernameLength(engine) || !masterUsernameRegex.MatchString(username) {
+		return fmt.Errorf(invalidParameterErrorTemplate, masterUsername)
+	}
+	for _, reserved := range reservedMasterUsernames[generateBindingType(engine)] {
+		if strings.EqualFold(username, reserved) {
+			return fmt.Errorf("value of parameter %s is reserved by engine %s", masterUsername, engine)
+		}
+	}
+	return nil
+}
+
+// getPasswordPolicy returns the password policy set by the provisioning parameters of the Instance
+func getPasswordPolicy(engine string, parameters map[dbaasv1beta1.ProvisioningParameterType]string) (passwordPolicy, error) {
+	policy := defaultPasswordPolicy
+	if l, ok := parameters[masterPasswordLength]; ok {
+		i, e := strconv.Atoi(l)
+		if e != nil || i < minPasswordLength || i > getMaxMasterPasswordLength(engine) {
+			return policy, fmt.Errorf(invalidParameterErrorTemplate, masterPasswordLength)
+		}
+		policy.length = i
+	}
+	if c, ok := parameters[masterPasswordCharset]; ok {
+		switch c {
+		case passwordCharsetAll:
+		case passwordCharsetAlphanumeric:
+			policy.specials = ""
+		default:
+			return policy, fmt.Errorf(invalidParameterErrorTemplate, masterPasswordCharset)
+		}
+	}
+	if exclude, ok := parameters[masterPasswordExcludeCharacters]; ok {
+		remove := func(r rune) rune {
+			if strings.ContainsRune(exclude, r) {
+				return -1
+			}
+			return r
+		}
+		policy.letters = strings.Map(remove, policy.letters)
+		policy.digits = strings.Map(remove, policy.digits)
+		policy.specials = strings.Map(remove, policy.specials)
+		if len(policy.letters) == 0 || len(policy.digits) == 0 {
+			return policy, fmt.Errorf(invalidParameterErrorTemplate, masterPasswordExcludeCharacters)
+		}
+	}
+	return policy, nil
+}
+
+// generatePasswordWithPolicy returns a random password following the policy
+func generatePasswordWithPolicy(policy passwordPolicy) string {
+	all := policy.letters + policy.digits + policy.specials
+	buf := make([]byte, policy.length)
+	buf[0] = policy.digits[getRandInt(len(policy.digits))]
+	buf[1] = policy.letters[getRandInt(len(policy.letters))]
+	i := 2
+	if len(policy.specials) > 0 {
+		buf[2] = policy.specials[getRandInt(len(policy.specials))]
+		i = 3
+	}
+	for ; i < policy.length; i++ {
+		buf[i] = all[getRandInt(len(all))]
+	}
+	rand.Shuffle(len(buf), func(i, j int) {
+		buf[i], buf[j] = buf[j], buf[i]
+	})
+	return string(buf)
+}
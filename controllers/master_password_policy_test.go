@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"unicode"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+)
+
+var _ = Describe("MasterPasswordPolicy", func() {
+	It("should validate the master username against the engine", func() {
+		Expect(validateMasterUsername("postgres", "appadmin")).Should(Succeed())
+		Expect(validateMasterUsername("postgres", "rdsadmin")).ShouldNot(Succeed())
+		Expect(validateMasterUsername("sqlserver-ex", "SA")).ShouldNot(Succeed())
+		Expect(validateMasterUsername("mysql", "root")).ShouldNot(Succeed())
+		Expect(validateMasterUsername("mysql", "abcdefghijklmnopq")).ShouldNot(Succeed())
+		Expect(validateMasterUsername("postgres", "abcdefghijklmnopq")).Should(Succeed())
+		Expect(validateMasterUsername("postgres", "0admin")).ShouldNot(Succeed())
+	})
+
+	It("should generate the master password with the policy", func() {
+		policy, e := getPasswordPolicy("postgres", map[dbaasv1beta1.ProvisioningParameterType]string{
+			masterPasswordLength:            "20",
+			masterPasswordExcludeCharacters: "%#?$",
+		})
+		Expect(e).ShouldNot(HaveOccurred())
+		s := generatePasswordWithPolicy(policy)
+		Expect(len(s)).Should(Equal(20))
+		Expect(strings.ContainsAny(s, "%#?$")).Should(BeFalse())
+
+		policy, e = getPasswordPolicy("postgres", map[dbaasv1beta1.ProvisioningParameterType]string{
+			masterPasswordCharset: "alphanumeric",
+		})
+		Expect(e).ShouldNot(HaveOccurred())
+		for _, c := range generatePasswordWithPolicy(policy) {
+			Expect(unicode.IsLetter(c) || unicode.IsDigit(c)).Should(BeTrue())
+		}
+	})
+
+	It("should reject an invalid password policy", func() {
+		_, e := getPasswordPolicy("mysql", map[dbaasv1beta1.ProvisioningParameterType]string{masterPasswordLength: "42"})
+		Expect(e).Should(HaveOccurred())
+		_, e = getPasswordPolicy("postgres", map[dbaasv1beta1.ProvisioningParameterType]string{masterPasswordLength: "7"})
+		Expect(e).Should(HaveOccurred())
+		_, e = getPasswordPolicy("postgres", map[dbaasv1beta1.ProvisioningParameterType]string{masterPasswordCharset: "digits"})
+		Expect(e).Should(HaveOccurred())
+		_, e = getPasswordPolicy("postgres", map[dbaasv1beta1.ProvisioningParameterType]string{masterPasswordExcludeCharacters: "0123456789"})
+		Expect(e).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	migrationVersionAnnotation = "rds.dbaas.redhat.com/migration-version"
+
+	// bump when a migration is appended to inventoryMigrations
+	currentMigrationVersion = 1
+
+	inventoryConditionMigrationComplete = "MigrationComplete"
+
+	migrationStatusReasonMigrated = "Migrated"
+	migrationStatusReasonFailed   = "MigrationFailed"
+
+	eventReasonMigrated        = "Migrated"
+	eventReasonMigrationFailed = "MigrationFailed"
+)
+
+type inventoryMigration struct {
+	version int
+	name    string
+	migrate func(ctx context.Context, m *Migrator, inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error)
+}
+
+// inventoryMigrations are applied in order to the Inventories with a lower migration version. The credentials Secrets
+// belong to the users and are never modified, the key layouts they may use are read as is by normalizeCredentials.
+var inventoryMigrations = []inventoryMigration{
+	{
+		version: 1,
+		name:    "check credentials secret label",
+		migrate: checkCredentialsLabel,
+	},
+}
+
+// Migrator migrates the resources created by earlier operator versions in place when the operator starts
+type Migrator struct {
+	client.Client
+	// APIReader reads the Secrets that are not labeled for the cache yet
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+}
+
+// NeedLeaderElection makes only the leader run the migrations
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the migrations once, the Inventories failing to migrate are retried on the next start
+func (m *Migrator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("migration")
+
+	inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+	if err := listInChunks(ctx, m.APIReader, inventoryList); err != nil {
+		logger.Error(err, "Failed to list Inventories for migration")
+		return nil
+	}
+	for i := range inventoryList.Items {
+		inventory := &inventoryList.Items[i]
+		if err := m.migrateInventory(ctx, inventory); err != nil {
+			logger.Error(err, "Failed to migrate Inventory", "Inventory", client.ObjectKeyFromObject(inventory))
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) migrateInventory(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	logger := log.FromContext(ctx).WithName("migration")
+
+	version := getMigrationVersion(inventory)
+	if version >= currentMigrationVersion {
+		return nil
+	}
+
+	var applied []string
+	for _, migration := range inventoryMigrations {
+		if migration.version <= version {
+			continue
+		}
+		changed, err := migration.migrate(ctx, m, inventory)
+		if err != nil {
+			message := fmt.Sprintf("Failed to %s: %v", migration.name, err)
+			m.Recorder.Event(inventory, v1.EventTypeWarning, eventReasonMigrationFailed, message)
+			if e := m.setMigrationCondition(ctx, inventory, metav1.ConditionFalse, migrationStatusReasonFailed, message); e != nil {
+				logger.Error(e, "Failed to update migration condition of Inventory")
+			}
+			return err
+		}
+		if changed {
+			applied = append(applied, migration.name)
+			m.Recorder.Event(inventory, v1.EventTypeNormal, eventReasonMigrated, fmt.Sprintf("Migration applied: %s", migration.name))
+		}
+		version = migration.version
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Get(ctx, client.ObjectKeyFromObject(inventory), inventory); err != nil {
+			return err
+		}
+		if inventory.Annotations == nil {
+			inventory.Annotations = map[string]string{}
+		}
+		inventory.Annotations[migrationVersionAnnotation] = strconv.Itoa(version)
+		return m.Update(ctx, inventory)
+	}); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Migrated to version %d", version)
+	if len(applied) > 0 {
+		message = fmt.Sprintf("%s: %s", message, strings.Join(applied, ", "))
+	}
+	logger.Info("Inventory migrated", "Inventory", client.ObjectKeyFromObject(inventory), "version", version)
+	return m.setMigrationCondition(ctx, inventory, metav1.ConditionTrue, migrationStatusReasonMigrated, message)
+}
+
+func (m *Migrator) setMigrationCondition(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	status metav1.ConditionStatus, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Get(ctx, client.ObjectKeyFromObject(inventory), inventory); err != nil {
+			return err
+		}
+		apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+			Type:    inventoryConditionMigrationComplete,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+		return m.Status().Update(ctx, inventory)
+	})
+}
+
+func getMigrationVersion(inventory *rdsdbaasv1alpha1.RDSInventory) int {
+	v, ok := inventory.Annotations[migrationVersionAnnotation]
+	if !ok {
+		return 0
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func (m *Migrator) getCredentialsSecret(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) (*v1.Secret, error) {
+	if inventory.Spec.CredentialsRef == nil {
+		return nil, nil
+	}
+	secret := &v1.Secret{}
+	if err := m.APIReader.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: inventory.Spec.CredentialsRef.Name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// checkCredentialsLabel fails if the credentials Secret is not labeled for the cache of the operator, the label is to
+// be set by the user
+func checkCredentialsLabel(ctx context.Context, m *Migrator, inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error) {
+	secret, err := m.getCredentialsSecret(ctx, inventory)
+	if err != nil || secret == nil {
+		return false, err
+	}
+	if secret.Labels[dbaasv1beta1.TypeLabelKey] != dbaasv1beta1.TypeLabelValue {
+		return false, fmt.Errorf("credentials secret %s is not labeled %s=%s", secret.Name, dbaasv1beta1.TypeLabelKey,
+			dbaasv1beta1.TypeLabelValue)
+	}
+	return false, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Migration", func() {
+	Context("Check Credentials Label", func() {
+		It("should report the unlabeled credentials Secret without modifying it", func() {
+			ctx := context.Background()
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "inventory", ResourceVersion: "1"},
+				Data:       map[string][]byte{"aws_access_key_id": []byte("key")},
+			}
+			inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Name: "inventory", Namespace: "inventory"}}
+			inventory.Spec.CredentialsRef = &dbaasv1beta1.LocalObjectReference{Name: secret.Name}
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret.DeepCopy()).Build()
+			m := &Migrator{Client: cli, APIReader: cli}
+
+			changed, err := checkCredentialsLabel(ctx, m, inventory)
+			Expect(err).Should(HaveOccurred())
+			Expect(changed).Should(BeFalse())
+			s := &v1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(secret), s)).Should(Succeed())
+			Expect(s.ResourceVersion).Should(Equal(secret.ResourceVersion))
+			Expect(s.Labels).Should(BeEmpty())
+			Expect(s.Data).Should(Equal(secret.Data))
+
+			s.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+			Expect(cli.Update(ctx, s)).Should(Succeed())
+			Expect(checkCredentialsLabel(ctx, m, inventory)).Should(BeFalse())
+
+			inventory.Spec.CredentialsRef.Name = "missing"
+			Expect(checkCredentialsLabel(ctx, m, inventory)).Should(BeFalse())
+		})
+	})
+
+	Context("Get Migration Version", func() {
+		DescribeTable("checking getMigrationVersion",
+			func(annotations map[string]string, version int) {
+				inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+				Expect(getMigrationVersion(inventory)).Should(Equal(version))
+			},
+
+			Entry("not migrated", nil, 0),
+			Entry("migrated", map[string]string{"rds.dbaas.redhat.com/migration-version": "1"}, 1),
+			Entry("invalid", map[string]string{"rds.dbaas.redhat.com/migration-version": "v1"}, 0),
+		)
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	goerrors "errors"
+	"text/template"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	monitoringResourceName = "rds-dbaas-operator"
+
+	// DefaultMonitoringSyncFailureFor is the default time an Inventory fails to sync before it is alerted on
+	DefaultMonitoringSyncFailureFor = 15 * time.Minute
+	// DefaultMonitoringThrottlingRate is the default rate of throttled AWS calls per second of an Inventory alerted on
+	DefaultMonitoringThrottlingRate = 0.1
+)
+
+//go:embed monitoring/*.yaml.tmpl
+var monitoringTemplates embed.FS
+
+var (
+	inventorySynced = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_inventory_synced",
+			Help: "Whether the last sync of an Inventory with AWS succeeded (1) or failed (0).",
+		},
+		[]string{"namespace", "inventory"},
+	)
+	inventorySyncFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_inventory_sync_failures_total",
+			Help: "The number of failed syncs of an Inventory, by the reason of its SpecSynced condition.",
+		},
+		[]string{"namespace", "inventory", "reason"},
+	)
+	inventoryAWSThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_inventory_aws_throttled_total",
+			Help: "The number of syncs of an Inventory failed by the throttling of its AWS calls.",
+		},
+		[]string{"namespace", "inventory"},
+	)
+	inventoryCredentialsExpired = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_inventory_credentials_expired",
+			Help: "Whether AWS rejected the security token of the credentials of an Inventory as expired (1) or not (0).",
+		},
+		[]string{"namespace", "inventory"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(inventorySynced, inventorySyncFailures, inventoryAWSThrottled, inventoryCredentialsExpired)
+}
+
+// recordInventorySyncResult sets the sync metrics of an Inventory from the result of its sync, the credentials are
+// only known to be expired or not once an AWS call is made
+func recordInventorySyncResult(namespace, name string, synced bool, reason string, err error) {
+	recordInventorySync(namespace, name, synced, time.Now())
+	if synced {
+		inventorySynced.WithLabelValues(namespace, name).Set(1)
+		inventoryCredentialsExpired.WithLabelValues(namespace, name).Set(0)
+		return
+	}
+	inventorySynced.WithLabelValues(namespace, name).Set(0)
+	inventorySyncFailures.WithLabelValues(namespace, name, reason).Inc()
+
+	var apiErr smithy.APIError
+	if err == nil || !goerrors.As(err, &apiErr) {
+		return
+	}
+	switch code := apiErr.ErrorCode(); {
+	case code == "ExpiredToken" || code == "ExpiredTokenException":
+		inventoryCredentialsExpired.WithLabelValues(namespace, name).Set(1)
+	case getAWSErrorCodeReason(code, "") == connectionStatusReasonThrottled:
+		inventoryAWSThrottled.WithLabelValues(namespace, name).Inc()
+	default:
+		inventoryCredentialsExpired.WithLabelValues(namespace, name).Set(0)
+	}
+}
+
+// deleteInventorySyncResult removes the sync metrics of a deleted Inventory
+func deleteInventorySyncResult(namespace, name string) {
+	deleteInventorySync(namespace, name)
+	labels := prometheus.Labels{"namespace": namespace, "inventory": name}
+	inventorySynced.DeletePartialMatch(labels)
+	inventorySyncFailures.DeletePartialMatch(labels)
+	inventoryAWSThrottled.DeletePartialMatch(labels)
+	inventoryCredentialsExpired.DeletePartialMatch(labels)
+}
+
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;update;patch
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards,verbs=get;create;update;patch
+
+// MonitoringResources creates the Grafana dashboard and the Prometheus alerts of the operator metrics in the install
+// namespace, the resources of a monitoring stack that is not installed are skipped
+type MonitoringResources struct {
+	client.Client
+	Namespace string
+	// GrafanaInstanceSelector selects the Grafana instances importing the dashboard
+	GrafanaInstanceSelector map[string]string
+	SyncFailureFor          time.Duration
+	ThrottlingRate          float64
+}
+
+type monitoringTemplateData struct {
+	Name             string
+	Namespace        string
+	InstanceSelector map[string]string
+	SyncFailureFor   string
+	ThrottlingRate   float64
+}
+
+// NeedLeaderElection makes only the leader apply the resources
+func (m *MonitoringResources) NeedLeaderElection() bool {
+	return true
+}
+
+// Start applies the resources once, they are applied again on the next start
+func (m *MonitoringResources) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("monitoring")
+
+	objs, err := m.render()
+	if err != nil {
+		logger.Error(err, "Failed to render monitoring resources")
+		return nil
+	}
+	for _, obj := range objs {
+		if err := applyObject(ctx, m.Client, obj); err != nil {
+			if apimeta.IsNoMatchError(err) {
+				logger.Info("Monitoring resource kind not installed, skipped", "kind", obj.GetKind())
+				continue
+			}
+			logger.Error(err, "Failed to apply monitoring resource", "kind", obj.GetKind(), "name", obj.GetName())
+			continue
+		}
+		logger.Info("Monitoring resource applied", "kind", obj.GetKind(), "name", obj.GetName())
+	}
+	return nil
+}
+
+func (m *MonitoringResources) render() ([]*unstructured.Unstructured, error) {
+	data := monitoringTemplateData{
+		Name:             monitoringResourceName,
+		Namespace:        m.Namespace,
+		InstanceSelector: m.GrafanaInstanceSelector,
+		SyncFailureFor:   model.Duration(m.SyncFailureFor).String(),
+		ThrottlingRate:   m.ThrottlingRate,
+	}
+	if m.SyncFailureFor <= 0 {
+		data.SyncFailureFor = model.Duration(DefaultMonitoringSyncFailureFor).String()
+	}
+	if m.ThrottlingRate <= 0 {
+		data.ThrottlingRate = DefaultMonitoringThrottlingRate
+	}
+
+	tmpl, err := template.ParseFS(monitoringTemplates, "monitoring/*.yaml.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	var objs []*unstructured.Unstructured
+	for _, t := range tmpl.Templates() {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(buf.Bytes(), &obj.Object); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
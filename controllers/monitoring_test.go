@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Monitoring", func() {
+	It("should render the dashboard and the alerts", func() {
+		m := &MonitoringResources{
+			Namespace:               "operator-ns",
+			GrafanaInstanceSelector: map[string]string{"dashboards": "grafana"},
+			SyncFailureFor:          30 * time.Minute,
+		}
+		objs, err := m.render()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(objs).Should(HaveLen(2))
+
+		kinds := map[string]*unstructured.Unstructured{}
+		for _, obj := range objs {
+			Expect(obj.GetNamespace()).Should(Equal("operator-ns"))
+			Expect(obj.GetName()).Should(Equal(monitoringResourceName))
+			kinds[obj.GetKind()] = obj
+		}
+
+		dashboard, ok := kinds["GrafanaDashboard"]
+		Expect(ok).Should(BeTrue())
+		selector, _, _ := unstructured.NestedStringMap(dashboard.Object, "spec", "instanceSelector", "matchLabels")
+		Expect(selector).Should(Equal(map[string]string{"dashboards": "grafana"}))
+		content, _, _ := unstructured.NestedString(dashboard.Object, "spec", "json")
+		var panels struct {
+			Panels []struct {
+				Title string `json:"title"`
+			} `json:"panels"`
+		}
+		Expect(json.Unmarshal([]byte(content), &panels)).Should(Succeed())
+		Expect(panels.Panels).ShouldNot(BeEmpty())
+
+		rule, ok := kinds["PrometheusRule"]
+		Expect(ok).Should(BeTrue())
+		groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+		Expect(groups).Should(HaveLen(1))
+		rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+		alerts := map[string]map[string]interface{}{}
+		for _, r := range rules {
+			alerts[r.(map[string]interface{})["alert"].(string)] = r.(map[string]interface{})
+		}
+		Expect(alerts).Should(HaveKey("RDSInventorySyncFailing"))
+		Expect(alerts).Should(HaveKey("RDSInventoryAWSThrottling"))
+		Expect(alerts).Should(HaveKey("RDSInventoryCredentialsExpired"))
+		Expect(alerts).Should(HaveKey("RDSInstanceEngineVersionDeprecated"))
+		Expect(alerts).Should(HaveKey("RDSWebIdentityRefreshFailing"))
+		Expect(alerts["RDSInventorySyncFailing"]["for"]).Should(Equal("30m"))
+		Expect(alerts["RDSInventoryAWSThrottling"]["expr"]).Should(ContainSubstring(fmt.Sprintf("> %v", DefaultMonitoringThrottlingRate)))
+		Expect(alerts["RDSInventoryCredentialsExpired"]["annotations"].(map[string]interface{})["summary"]).
+			Should(ContainSubstring("{{ $labels.namespace }}"))
+	})
+
+	It("should record the sync results of an Inventory", func() {
+		defer deleteInventorySyncResult("monitoring", "inventory")
+
+		recordInventorySyncResult("monitoring", "inventory", false, inventoryStatusReasonInputError,
+			&smithy.GenericAPIError{Code: "ExpiredToken"})
+		Expect(testutil.ToFloat64(inventorySynced.WithLabelValues("monitoring", "inventory"))).Should(BeNumerically("==", 0))
+		Expect(testutil.ToFloat64(inventoryCredentialsExpired.WithLabelValues("monitoring", "inventory"))).Should(BeNumerically("==", 1))
+
+		recordInventorySyncResult("monitoring", "inventory", false, inventoryStatusReasonBackendError,
+			&smithy.GenericAPIError{Code: "Throttling"})
+		Expect(testutil.ToFloat64(inventoryAWSThrottled.WithLabelValues("monitoring", "inventory"))).Should(BeNumerically("==", 1))
+		Expect(testutil.ToFloat64(inventorySyncFailures.WithLabelValues("monitoring", "inventory", inventoryStatusReasonBackendError))).
+			Should(BeNumerically("==", 1))
+
+		recordInventorySyncResult("monitoring", "inventory", true, inventoryStatusReasonSyncOK, nil)
+		Expect(testutil.ToFloat64(inventorySynced.WithLabelValues("monitoring", "inventory"))).Should(BeNumerically("==", 1))
+		Expect(testutil.ToFloat64(inventoryCredentialsExpired.WithLabelValues("monitoring", "inventory"))).Should(BeNumerically("==", 0))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// NamespacePolicyAllow reconciles the Inventories and Connections of the namespaces not in the allow or deny list
+	NamespacePolicyAllow = "allow"
+	// NamespacePolicyDeny only reconciles the Inventories and Connections of the namespaces in the allow list
+	NamespacePolicyDeny = "deny"
+
+	inventoryStatusReasonNamespaceNotAllowed  = "NamespaceNotAllowed"
+	connectionStatusReasonNamespaceNotAllowed = "NamespaceNotAllowed"
+
+	inventoryStatusMessageNamespaceNotAllowed  = "Inventories are not reconciled in namespace %s"
+	connectionStatusMessageNamespaceNotAllowed = "Connections are not reconciled in namespace %s"
+)
+
+// NamespacePolicy restricts the namespaces in which the Inventories and Connections are reconciled, to roll out the
+// provider to selected namespaces. The lists hold namespace names or glob patterns such as team-*, the deny list takes
+// precedence over the allow list.
+type NamespacePolicy struct {
+	allow       []string
+	deny        []string
+	defaultDeny bool
+}
+
+// NewNamespacePolicy parses the comma-separated allow and deny lists, it returns nil if all the namespaces are allowed
+func NewNamespacePolicy(allow, deny, defaultPolicy string) (*NamespacePolicy, error) {
+	p := &NamespacePolicy{}
+	switch defaultPolicy {
+	case NamespacePolicyAllow, "":
+	case NamespacePolicyDeny:
+		p.defaultDeny = true
+	default:
+		return nil, fmt.Errorf("namespace policy %s is invalid", defaultPolicy)
+	}
+	var err error
+	if p.allow, err = parseNamespacePatterns(allow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = parseNamespacePatterns(deny); err != nil {
+		return nil, err
+	}
+	if !p.defaultDeny && len(p.deny) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+func parseNamespacePatterns(value string) ([]string, error) {
+	var patterns []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); len(s) == 0 {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("namespace pattern %s is invalid", s)
+		}
+		patterns = append(patterns, s)
+	}
+	return patterns, nil
+}
+
+func matchNamespace(patterns []string, namespace string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowed returns whether the Inventories and Connections of the namespace are reconciled
+func (p *NamespacePolicy) isAllowed(namespace string) bool {
+	if p == nil {
+		return true
+	}
+	if matchNamespace(p.deny, namespace) {
+		return false
+	}
+	return !p.defaultDeny || matchNamespace(p.allow, namespace)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NamespacePolicy", func() {
+	It("should allow all the namespaces by default", func() {
+		p, e := NewNamespacePolicy("", "", "")
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(p).Should(BeNil())
+		Expect(p.isAllowed("team-a")).Should(BeTrue())
+	})
+
+	It("should only allow the namespaces of the allow list if denied by default", func() {
+		p, e := NewNamespacePolicy("team-a, staging-*", "", NamespacePolicyDeny)
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(p.isAllowed("team-a")).Should(BeTrue())
+		Expect(p.isAllowed("staging-1")).Should(BeTrue())
+		Expect(p.isAllowed("team-b")).Should(BeFalse())
+	})
+
+	It("should not allow the namespaces of the deny list", func() {
+		p, e := NewNamespacePolicy("team-*", "team-legacy", NamespacePolicyDeny)
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(p.isAllowed("team-a")).Should(BeTrue())
+		Expect(p.isAllowed("team-legacy")).Should(BeFalse())
+
+		p, e = NewNamespacePolicy("", "kube-*,openshift-*", NamespacePolicyAllow)
+		Expect(e).ShouldNot(HaveOccurred())
+		Expect(p.isAllowed("openshift-config")).Should(BeFalse())
+		Expect(p.isAllowed("team-b")).Should(BeTrue())
+	})
+
+	It("should reject the invalid policies", func() {
+		_, e := NewNamespacePolicy("", "", "block")
+		Expect(e).Should(HaveOccurred())
+		_, e = NewNamespacePolicy("team-[", "", NamespacePolicyDeny)
+		Expect(e).Should(HaveOccurred())
+	})
+	It("should not add the finalizer to the Inventories in the namespaces not allowed", func() {
+		p, e := NewNamespacePolicy("team-a", "", NamespacePolicyDeny)
+		Expect(e).ShouldNot(HaveOccurred())
+		inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "inventory"}}
+		r := &RDSInventoryReconciler{
+			Client:          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(inventory).Build(),
+			NamespacePolicy: p,
+		}
+
+		// the status is applied with server-side apply, not supported by the fake client
+		_, _ = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(inventory)})
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(inventory), inventory)).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(inventory, inventoryFinalizer)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultOperatorHealthInterval is the default interval at which the health of the operator is set in its
+	// OperatorCondition
+	DefaultOperatorHealthInterval = time.Minute
+
+	operatorConditionDegraded = "Degraded"
+
+	operatorStatusReasonHealthy              = "Healthy"
+	operatorStatusReasonInventoriesNotSynced = "InventoriesNotSynced"
+	operatorStatusReasonWebhookFailures      = "WebhookFailures"
+
+	operatorStatusMessageHealthy              = "All the Inventories are synced and the webhooks do not fail"
+	operatorStatusMessageInventoriesNotSynced = "Inventories not synced: %s"
+	operatorStatusMessageWebhookFailures      = "%d webhook requests failed since the last check: %s"
+
+	webhookRequestsMetric = "controller_runtime_webhook_requests_total"
+)
+
+//+kubebuilder:rbac:groups=operators.coreos.com,resources=operatorconditions,verbs=get;update
+
+// OperatorHealth sets the aggregate health of the controllers, the Inventories failing to sync and the failed
+// webhook requests, in the Degraded condition of the OperatorCondition of the operator installed by OLM, so that
+// OLM does not report the operator as healthy when it fails. Only the webhook requests of the leader are counted.
+type OperatorHealth struct {
+	client.Client
+	// APIReader reads the OperatorCondition that is not cached
+	APIReader client.Reader
+	Namespace string
+	// OperatorConditionName is the OperatorCondition created by OLM for the operator, from OPERATOR_CONDITION_NAME
+	OperatorConditionName string
+	Interval              time.Duration
+	// Gatherer reads the webhook metrics, the controller-runtime registry if not set
+	Gatherer prometheus.Gatherer
+
+	// the failed webhook requests by webhook path at the last check
+	webhookFailures map[string]float64
+}
+
+// NeedLeaderElection makes only the leader set the condition
+func (h *OperatorHealth) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sets the health of the operator until the manager stops
+func (h *OperatorHealth) Start(ctx context.Context) error {
+	interval := h.Interval
+	if interval <= 0 {
+		interval = DefaultOperatorHealthInterval
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := h.sync(ctx); err != nil {
+			log.FromContext(ctx).WithName("operator-health").Error(err, "Failed to set operator health",
+				"OperatorCondition", h.OperatorConditionName)
+		}
+	}, interval)
+	return nil
+}
+
+func (h *OperatorHealth) sync(ctx context.Context) error {
+	condition, err := h.getDegradedCondition(ctx)
+	if err != nil {
+		return err
+	}
+
+	operatorCondition := &operatorsv2.OperatorCondition{}
+	if err := h.APIReader.Get(ctx, client.ObjectKey{Namespace: h.Namespace, Name: h.OperatorConditionName}, operatorCondition); err != nil {
+		return err
+	}
+	if c := apimeta.FindStatusCondition(operatorCondition.Spec.Conditions, operatorConditionDegraded); c != nil &&
+		c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+		return nil
+	}
+	apimeta.SetStatusCondition(&operatorCondition.Spec.Conditions, condition)
+	return h.Update(ctx, operatorCondition)
+}
+
+// getDegradedCondition returns the Degraded condition from the SpecSynced condition of the Inventories and the
+// webhook requests failed with a server error since the last check
+func (h *OperatorHealth) getDegradedCondition(ctx context.Context) (metav1.Condition, error) {
+	inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+	if err := h.List(ctx, inventoryList); err != nil {
+		return metav1.Condition{}, err
+	}
+	var notSynced []string
+	for i := range inventoryList.Items {
+		inventory := &inventoryList.Items[i]
+		if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); c != nil &&
+			c.Status == metav1.ConditionFalse {
+			notSynced = append(notSynced, fmt.Sprintf("%s/%s (%s)", inventory.Namespace, inventory.Name, c.Reason))
+		}
+	}
+	sort.Strings(notSynced)
+
+	failed, webhooks, err := h.getWebhookFailures()
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+
+	var reasons, messages []string
+	if len(notSynced) > 0 {
+		reasons = append(reasons, operatorStatusReasonInventoriesNotSynced)
+		messages = append(messages, fmt.Sprintf(operatorStatusMessageInventoriesNotSynced, strings.Join(notSynced, ", ")))
+	}
+	if failed > 0 {
+		reasons = append(reasons, operatorStatusReasonWebhookFailures)
+		messages = append(messages, fmt.Sprintf(operatorStatusMessageWebhookFailures, failed, strings.Join(webhooks, ", ")))
+	}
+	if len(reasons) == 0 {
+		return metav1.Condition{
+			Type:    operatorConditionDegraded,
+			Status:  metav1.ConditionFalse,
+			Reason:  operatorStatusReasonHealthy,
+			Message: operatorStatusMessageHealthy,
+		}, nil
+	}
+	// the condition reason is a single CamelCase word, the first cause is reported and all of them in the message
+	return metav1.Condition{
+		Type:    operatorConditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasons[0],
+		Message: strings.Join(messages, "; "),
+	}, nil
+}
+
+// getWebhookFailures returns the number of webhook requests answered with a server error since the last check and
+// the webhooks that failed them, the first check only records the current counts
+func (h *OperatorHealth) getWebhookFailures() (int, []string, error) {
+	gatherer := h.Gatherer
+	if gatherer == nil {
+		gatherer = metrics.Registry
+	}
+	families, err := gatherer.Gather()
+	if err != nil {
+		return 0, nil, err
+	}
+	current := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != webhookRequestsMetric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			var code, webhook string
+			for _, l := range m.GetLabel() {
+				switch l.GetName() {
+				case "code":
+					code = l.GetValue()
+				case "webhook":
+					webhook = l.GetValue()
+				}
+			}
+			if c, e := strconv.Atoi(code); e == nil && c >= 500 {
+				current[webhook] += m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	previous := h.webhookFailures
+	h.webhookFailures = current
+	if previous == nil {
+		return 0, nil, nil
+	}
+	failed := 0
+	var webhooks []string
+	for webhook, count := range current {
+		if d := int(count - previous[webhook]); d > 0 {
+			failed += d
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Strings(webhooks)
+	return failed, webhooks, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("Operator health", func() {
+	It("should count the webhook requests failed since the last check", func() {
+		requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: webhookRequestsMetric},
+			[]string{"code", "webhook"})
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(requests)
+		h := &OperatorHealth{Gatherer: registry}
+
+		requests.WithLabelValues("500", "/validate-instance").Add(3)
+		requests.WithLabelValues("200", "/validate-instance").Add(10)
+		failed, _, err := h.getWebhookFailures()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failed).Should(BeZero())
+
+		requests.WithLabelValues("500", "/validate-instance").Add(2)
+		requests.WithLabelValues("503", "/validate-inventory").Inc()
+		requests.WithLabelValues("200", "/validate-connection").Add(5)
+		failed, webhooks, err := h.getWebhookFailures()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failed).Should(Equal(3))
+		Expect(webhooks).Should(Equal([]string{"/validate-instance", "/validate-inventory"}))
+
+		failed, webhooks, err = h.getWebhookFailures()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failed).Should(BeZero())
+		Expect(webhooks).Should(BeEmpty())
+	})
+})
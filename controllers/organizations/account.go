@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organizations
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type ListAccountsForParentPaginatorAPI interface {
+	HasMorePages() bool
+	NextPage(context.Context, ...func(option *organizations.Options)) (*organizations.ListAccountsForParentOutput, error)
+}
+
+type sdkV2ListAccountsForParentPaginator struct {
+	paginator *organizations.ListAccountsForParentPaginator
+}
+
+// NewListAccountsForParentPaginator lists the accounts of the organizational unit with the role of the management
+// account of the organization assumed from the static credentials
+func NewListAccountsForParentPaginator(accessKey, secretKey, region, roleARN, parentID string) ListAccountsForParentPaginatorAPI {
+	awsClient := organizations.New(organizations.Options{
+		Region:      region,
+		Credentials: controllerssts.NewAssumeRoleCredentials(accessKey, secretKey, region, roleARN),
+	})
+	paginator := organizations.NewListAccountsForParentPaginator(awsClient, &organizations.ListAccountsForParentInput{
+		ParentId: &parentID,
+	})
+	return &sdkV2ListAccountsForParentPaginator{
+		paginator: paginator,
+	}
+}
+
+func (p *sdkV2ListAccountsForParentPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *sdkV2ListAccountsForParentPaginator) NextPage(ctx context.Context, f ...func(option *organizations.Options)) (*organizations.ListAccountsForParentOutput, error) {
+	return p.paginator.NextPage(ctx, f...)
+}
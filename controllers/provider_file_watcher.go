@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// providerFileWatcher calls changed when the content of the provider registration file changes, the directory is
+// watched rather than the file as the updates of a mounted ConfigMap replace the symbolic links of the directory
+type providerFileWatcher struct {
+	path    string
+	changed func()
+}
+
+// NeedLeaderElection makes the watcher run with the provider controller on the leader
+func (w *providerFileWatcher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start watches the file until the manager stops
+func (w *providerFileWatcher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("provider-file-watcher")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+
+	last, _ := fileChecksum(w.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			sum, err := fileChecksum(w.path)
+			if err != nil || sum == last {
+				continue
+			}
+			last = sum
+			logger.Info("provider registration file changed", "file", w.path)
+			w.changed()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Error(err, "error watching provider registration file", "file", w.path)
+		}
+	}
+}
+
+func fileChecksum(path string) ([sha256.Size]byte, error) {
+	d, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(d), nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// CallRecorder is called for each attempt of an AWS API call made with a context carrying it
+type CallRecorder func(service, operation string)
+
+type callRecorderKey struct{}
+
+// WithCallRecorder returns a context recording the AWS API calls made with it
+func WithCallRecorder(ctx context.Context, recorder CallRecorder) context.Context {
+	return context.WithValue(ctx, callRecorderKey{}, recorder)
+}
+
+// recordCalls adds the middleware calling the recorder of the context, after the retry middleware so the retried
+// attempts are recorded as they count against the API limits of the account
+func recordCalls(o *rds.Options) {
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("RecordCall",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if recorder, ok := ctx.Value(callRecorderKey{}).(CallRecorder); ok && recorder != nil {
+					recorder(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx))
+				}
+				return next.HandleFinalize(ctx, in)
+			}), middleware.After)
+	})
+}
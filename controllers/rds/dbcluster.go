@@ -19,9 +19,9 @@ package rds
 import (
 	"context"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
 )
 
 type DescribeDBClustersPaginatorAPI interface {
@@ -36,8 +36,21 @@ type sdkV2DescribeDBClustersPaginator struct {
 func NewDescribeDBClustersPaginator(accessKey, secretKey, region string) DescribeDBClustersPaginatorAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	paginator := rds.NewDescribeDBClustersPaginator(awsClient, nil)
+	return &sdkV2DescribeDBClustersPaginator{
+		paginator: paginator,
+	}
+}
+
+// NewAssumeRoleDescribeDBClustersPaginator describes the DB clusters of the account of the last role of the
+// chain of roles assumed from the static credentials
+func NewAssumeRoleDescribeDBClustersPaginator(accessKey, secretKey, region string, roleARNs ...string) DescribeDBClustersPaginatorAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewAssumeRoleCredentials(accessKey, secretKey, region, roleARNs...),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	paginator := rds.NewDescribeDBClustersPaginator(awsClient, nil)
 	return &sdkV2DescribeDBClustersPaginator{
 		paginator: paginator,
@@ -63,8 +76,8 @@ type sdkV2ModifyDBCluster struct {
 func NewModifyDBCluster(accessKey, secretKey, region string) ModifyDBClusterAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	return &sdkV2ModifyDBCluster{
 		client: awsClient,
 	}
@@ -85,8 +98,8 @@ type sdkV2DescribeDBClusters struct {
 func NewDescribeDBClusters(accessKey, secretKey, region string) DescribeDBClustersAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	return &sdkV2DescribeDBClusters{
 		client: awsClient,
 	}
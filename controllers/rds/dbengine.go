@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type DescribeDBEngineVersionsAPI interface {
+	DescribeDBEngineVersions(context.Context, *rds.DescribeDBEngineVersionsInput, ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error)
+}
+
+type sdkV2DescribeDBEngineVersions struct {
+	client *rds.Client
+}
+
+func NewDescribeDBEngineVersions(accessKey, secretKey, region string) DescribeDBEngineVersionsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeDBEngineVersions{
+		client: awsClient,
+	}
+}
+
+func (d *sdkV2DescribeDBEngineVersions) DescribeDBEngineVersions(ctx context.Context, params *rds.DescribeDBEngineVersionsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+	return d.client.DescribeDBEngineVersions(ctx, params, optFns...)
+}
+
+type DescribeOrderableDBInstanceOptionsAPI interface {
+	DescribeOrderableDBInstanceOptions(context.Context, *rds.DescribeOrderableDBInstanceOptionsInput, ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error)
+}
+
+type sdkV2DescribeOrderableDBInstanceOptions struct {
+	client *rds.Client
+}
+
+func NewDescribeOrderableDBInstanceOptions(accessKey, secretKey, region string) DescribeOrderableDBInstanceOptionsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeOrderableDBInstanceOptions{
+		client: awsClient,
+	}
+}
+
+func (d *sdkV2DescribeOrderableDBInstanceOptions) DescribeOrderableDBInstanceOptions(ctx context.Context, params *rds.DescribeOrderableDBInstanceOptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error) {
+	return d.client.DescribeOrderableDBInstanceOptions(ctx, params, optFns...)
+}
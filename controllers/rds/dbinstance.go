@@ -19,9 +19,9 @@ package rds
 import (
 	"context"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
 )
 
 type DescribeDBInstancesPaginatorAPI interface {
@@ -36,8 +36,21 @@ type sdkV2DescribeDBInstancesPaginator struct {
 func NewDescribeDBInstancesPaginator(accessKey, secretKey, region string) DescribeDBInstancesPaginatorAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	paginator := rds.NewDescribeDBInstancesPaginator(awsClient, nil)
+	return &sdkV2DescribeDBInstancesPaginator{
+		paginator: paginator,
+	}
+}
+
+// NewAssumeRoleDescribeDBInstancesPaginator describes the DB instances of the account of the last role of the
+// chain of roles assumed from the static credentials
+func NewAssumeRoleDescribeDBInstancesPaginator(accessKey, secretKey, region string, roleARNs ...string) DescribeDBInstancesPaginatorAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewAssumeRoleCredentials(accessKey, secretKey, region, roleARNs...),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	paginator := rds.NewDescribeDBInstancesPaginator(awsClient, nil)
 	return &sdkV2DescribeDBInstancesPaginator{
 		paginator: paginator,
@@ -63,8 +76,8 @@ type sdkV2ModifyDBInstance struct {
 func NewModifyDBInstance(accessKey, secretKey, region string) ModifyDBInstanceAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	return &sdkV2ModifyDBInstance{
 		client: awsClient,
 	}
@@ -85,8 +98,8 @@ type sdkV2DescribeDBInstances struct {
 func NewDescribeDBInstances(accessKey, secretKey, region string) DescribeDBInstancesAPI {
 	awsClient := rds.New(rds.Options{
 		Region:      region,
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-	})
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
 	return &sdkV2DescribeDBInstances{
 		client: awsClient,
 	}
@@ -95,3 +108,47 @@ func NewDescribeDBInstances(accessKey, secretKey, region string) DescribeDBInsta
 func (d *sdkV2DescribeDBInstances) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
 	return d.client.DescribeDBInstances(ctx, params, optFns...)
 }
+
+type RestoreDBInstanceFromS3API interface {
+	RestoreDBInstanceFromS3(context.Context, *rds.RestoreDBInstanceFromS3Input, ...func(*rds.Options)) (*rds.RestoreDBInstanceFromS3Output, error)
+}
+
+type sdkV2RestoreDBInstanceFromS3 struct {
+	client *rds.Client
+}
+
+func NewRestoreDBInstanceFromS3(accessKey, secretKey, region string) RestoreDBInstanceFromS3API {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2RestoreDBInstanceFromS3{
+		client: awsClient,
+	}
+}
+
+func (r *sdkV2RestoreDBInstanceFromS3) RestoreDBInstanceFromS3(ctx context.Context, params *rds.RestoreDBInstanceFromS3Input, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceFromS3Output, error) {
+	return r.client.RestoreDBInstanceFromS3(ctx, params, optFns...)
+}
+
+type RestoreDBInstanceToPointInTimeAPI interface {
+	RestoreDBInstanceToPointInTime(context.Context, *rds.RestoreDBInstanceToPointInTimeInput, ...func(*rds.Options)) (*rds.RestoreDBInstanceToPointInTimeOutput, error)
+}
+
+type sdkV2RestoreDBInstanceToPointInTime struct {
+	client *rds.Client
+}
+
+func NewRestoreDBInstanceToPointInTime(accessKey, secretKey, region string) RestoreDBInstanceToPointInTimeAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2RestoreDBInstanceToPointInTime{
+		client: awsClient,
+	}
+}
+
+func (r *sdkV2RestoreDBInstanceToPointInTime) RestoreDBInstanceToPointInTime(ctx context.Context, params *rds.RestoreDBInstanceToPointInTimeInput, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceToPointInTimeOutput, error) {
+	return r.client.RestoreDBInstanceToPointInTime(ctx, params, optFns...)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type DescribeDBParametersAPI interface {
+	DescribeDBParameters(context.Context, *rds.DescribeDBParametersInput, ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error)
+}
+
+type sdkV2DescribeDBParameters struct {
+	client *rds.Client
+}
+
+func NewDescribeDBParameters(accessKey, secretKey, region string) DescribeDBParametersAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeDBParameters{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeDBParameters) DescribeDBParameters(ctx context.Context, params *rds.DescribeDBParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error) {
+	return a.client.DescribeDBParameters(ctx, params, optFns...)
+}
+
+type DescribeDBClusterParametersAPI interface {
+	DescribeDBClusterParameters(context.Context, *rds.DescribeDBClusterParametersInput, ...func(*rds.Options)) (*rds.DescribeDBClusterParametersOutput, error)
+}
+
+type sdkV2DescribeDBClusterParameters struct {
+	client *rds.Client
+}
+
+func NewDescribeDBClusterParameters(accessKey, secretKey, region string) DescribeDBClusterParametersAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeDBClusterParameters{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeDBClusterParameters) DescribeDBClusterParameters(ctx context.Context, params *rds.DescribeDBClusterParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParametersOutput, error) {
+	return a.client.DescribeDBClusterParameters(ctx, params, optFns...)
+}
+
+type CreateDBParameterGroupAPI interface {
+	CreateDBParameterGroup(context.Context, *rds.CreateDBParameterGroupInput, ...func(*rds.Options)) (*rds.CreateDBParameterGroupOutput, error)
+}
+
+type sdkV2CreateDBParameterGroup struct {
+	client *rds.Client
+}
+
+func NewCreateDBParameterGroup(accessKey, secretKey, region string) CreateDBParameterGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2CreateDBParameterGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2CreateDBParameterGroup) CreateDBParameterGroup(ctx context.Context, params *rds.CreateDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.CreateDBParameterGroupOutput, error) {
+	return a.client.CreateDBParameterGroup(ctx, params, optFns...)
+}
+
+type DescribeDBParameterGroupsAPI interface {
+	DescribeDBParameterGroups(context.Context, *rds.DescribeDBParameterGroupsInput, ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error)
+}
+
+type sdkV2DescribeDBParameterGroups struct {
+	client *rds.Client
+}
+
+func NewDescribeDBParameterGroups(accessKey, secretKey, region string) DescribeDBParameterGroupsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeDBParameterGroups{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeDBParameterGroups) DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error) {
+	return a.client.DescribeDBParameterGroups(ctx, params, optFns...)
+}
+
+type ModifyDBParameterGroupAPI interface {
+	ModifyDBParameterGroup(context.Context, *rds.ModifyDBParameterGroupInput, ...func(*rds.Options)) (*rds.ModifyDBParameterGroupOutput, error)
+}
+
+type sdkV2ModifyDBParameterGroup struct {
+	client *rds.Client
+}
+
+func NewModifyDBParameterGroup(accessKey, secretKey, region string) ModifyDBParameterGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2ModifyDBParameterGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2ModifyDBParameterGroup) ModifyDBParameterGroup(ctx context.Context, params *rds.ModifyDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.ModifyDBParameterGroupOutput, error) {
+	return a.client.ModifyDBParameterGroup(ctx, params, optFns...)
+}
+
+type DeleteDBParameterGroupAPI interface {
+	DeleteDBParameterGroup(context.Context, *rds.DeleteDBParameterGroupInput, ...func(*rds.Options)) (*rds.DeleteDBParameterGroupOutput, error)
+}
+
+type sdkV2DeleteDBParameterGroup struct {
+	client *rds.Client
+}
+
+func NewDeleteDBParameterGroup(accessKey, secretKey, region string) DeleteDBParameterGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DeleteDBParameterGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DeleteDBParameterGroup) DeleteDBParameterGroup(ctx context.Context, params *rds.DeleteDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.DeleteDBParameterGroupOutput, error) {
+	return a.client.DeleteDBParameterGroup(ctx, params, optFns...)
+}
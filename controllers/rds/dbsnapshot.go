@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type CreateDBSnapshotAPI interface {
+	CreateDBSnapshot(context.Context, *rds.CreateDBSnapshotInput, ...func(*rds.Options)) (*rds.CreateDBSnapshotOutput, error)
+}
+
+type sdkV2CreateDBSnapshot struct {
+	client *rds.Client
+}
+
+func NewCreateDBSnapshot(accessKey, secretKey, region string) CreateDBSnapshotAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2CreateDBSnapshot{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2CreateDBSnapshot) CreateDBSnapshot(ctx context.Context, params *rds.CreateDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CreateDBSnapshotOutput, error) {
+	return a.client.CreateDBSnapshot(ctx, params, optFns...)
+}
+
+type DescribeDBSnapshotsAPI interface {
+	DescribeDBSnapshots(context.Context, *rds.DescribeDBSnapshotsInput, ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error)
+}
+
+type sdkV2DescribeDBSnapshots struct {
+	client *rds.Client
+}
+
+func NewDescribeDBSnapshots(accessKey, secretKey, region string) DescribeDBSnapshotsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeDBSnapshots{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeDBSnapshots) DescribeDBSnapshots(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+	return a.client.DescribeDBSnapshots(ctx, params, optFns...)
+}
+
+type ModifyDBSnapshotAttributeAPI interface {
+	ModifyDBSnapshotAttribute(context.Context, *rds.ModifyDBSnapshotAttributeInput, ...func(*rds.Options)) (*rds.ModifyDBSnapshotAttributeOutput, error)
+}
+
+type sdkV2ModifyDBSnapshotAttribute struct {
+	client *rds.Client
+}
+
+func NewModifyDBSnapshotAttribute(accessKey, secretKey, region string) ModifyDBSnapshotAttributeAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2ModifyDBSnapshotAttribute{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2ModifyDBSnapshotAttribute) ModifyDBSnapshotAttribute(ctx context.Context, params *rds.ModifyDBSnapshotAttributeInput, optFns ...func(*rds.Options)) (*rds.ModifyDBSnapshotAttributeOutput, error) {
+	return a.client.ModifyDBSnapshotAttribute(ctx, params, optFns...)
+}
+
+type StartExportTaskAPI interface {
+	StartExportTask(context.Context, *rds.StartExportTaskInput, ...func(*rds.Options)) (*rds.StartExportTaskOutput, error)
+}
+
+type sdkV2StartExportTask struct {
+	client *rds.Client
+}
+
+func NewStartExportTask(accessKey, secretKey, region string) StartExportTaskAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2StartExportTask{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2StartExportTask) StartExportTask(ctx context.Context, params *rds.StartExportTaskInput, optFns ...func(*rds.Options)) (*rds.StartExportTaskOutput, error) {
+	return a.client.StartExportTask(ctx, params, optFns...)
+}
+
+type DescribeExportTasksAPI interface {
+	DescribeExportTasks(context.Context, *rds.DescribeExportTasksInput, ...func(*rds.Options)) (*rds.DescribeExportTasksOutput, error)
+}
+
+type sdkV2DescribeExportTasks struct {
+	client *rds.Client
+}
+
+func NewDescribeExportTasks(accessKey, secretKey, region string) DescribeExportTasksAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeExportTasks{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeExportTasks) DescribeExportTasks(ctx context.Context, params *rds.DescribeExportTasksInput, optFns ...func(*rds.Options)) (*rds.DescribeExportTasksOutput, error) {
+	return a.client.DescribeExportTasks(ctx, params, optFns...)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+type endpointsKey struct{}
+
+// WithEndpoints returns a context making the AWS API calls to the endpoints in order, the next endpoint is called
+// when the retries of an endpoint fail to reach it
+func WithEndpoints(ctx context.Context, endpoints []string) context.Context {
+	return context.WithValue(ctx, endpointsKey{}, endpoints)
+}
+
+// failoverEndpoints adds the middleware calling the endpoints of the context, before the retry middleware so each
+// endpoint is retried before the next one is called
+func failoverEndpoints(o *rds.Options) {
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("FailoverEndpoints",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				endpoints, _ := ctx.Value(endpointsKey{}).([]string)
+				req, ok := in.Request.(*smithyhttp.Request)
+				if len(endpoints) == 0 || !ok {
+					return next.HandleFinalize(ctx, in)
+				}
+
+				var out middleware.FinalizeOutput
+				var metadata middleware.Metadata
+				var err error
+				for _, endpoint := range endpoints {
+					if err = req.RewindStream(); err != nil {
+						return out, metadata, err
+					}
+					r := req.Clone()
+					r.URL.Host = endpoint
+					r.Host = endpoint
+					in.Request = r
+					out, metadata, err = next.HandleFinalize(ctx, in)
+					if err == nil || ctx.Err() != nil || !IsEndpointError(err) {
+						return out, metadata, err
+					}
+				}
+				return out, metadata, err
+			}), middleware.Before)
+	})
+}
+
+// IsEndpointError returns true if the error is caused by an endpoint that cannot be reached, as opposed to an error
+// returned by the endpoint
+func IsEndpointError(err error) bool {
+	var sendErr *smithyhttp.RequestSendError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &sendErr) || errors.As(err, &dnsErr) || errors.As(err, &opErr)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type CreateEventSubscriptionAPI interface {
+	CreateEventSubscription(context.Context, *rds.CreateEventSubscriptionInput, ...func(*rds.Options)) (*rds.CreateEventSubscriptionOutput, error)
+}
+
+type sdkV2CreateEventSubscription struct {
+	client *rds.Client
+}
+
+func NewCreateEventSubscription(accessKey, secretKey, region string) CreateEventSubscriptionAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2CreateEventSubscription{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2CreateEventSubscription) CreateEventSubscription(ctx context.Context, params *rds.CreateEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.CreateEventSubscriptionOutput, error) {
+	return a.client.CreateEventSubscription(ctx, params, optFns...)
+}
+
+type DescribeEventSubscriptionsAPI interface {
+	DescribeEventSubscriptions(context.Context, *rds.DescribeEventSubscriptionsInput, ...func(*rds.Options)) (*rds.DescribeEventSubscriptionsOutput, error)
+}
+
+type sdkV2DescribeEventSubscriptions struct {
+	client *rds.Client
+}
+
+func NewDescribeEventSubscriptions(accessKey, secretKey, region string) DescribeEventSubscriptionsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeEventSubscriptions{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeEventSubscriptions) DescribeEventSubscriptions(ctx context.Context, params *rds.DescribeEventSubscriptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventSubscriptionsOutput, error) {
+	return a.client.DescribeEventSubscriptions(ctx, params, optFns...)
+}
+
+type ModifyEventSubscriptionAPI interface {
+	ModifyEventSubscription(context.Context, *rds.ModifyEventSubscriptionInput, ...func(*rds.Options)) (*rds.ModifyEventSubscriptionOutput, error)
+}
+
+type sdkV2ModifyEventSubscription struct {
+	client *rds.Client
+}
+
+func NewModifyEventSubscription(accessKey, secretKey, region string) ModifyEventSubscriptionAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2ModifyEventSubscription{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2ModifyEventSubscription) ModifyEventSubscription(ctx context.Context, params *rds.ModifyEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.ModifyEventSubscriptionOutput, error) {
+	return a.client.ModifyEventSubscription(ctx, params, optFns...)
+}
+
+type DeleteEventSubscriptionAPI interface {
+	DeleteEventSubscription(context.Context, *rds.DeleteEventSubscriptionInput, ...func(*rds.Options)) (*rds.DeleteEventSubscriptionOutput, error)
+}
+
+type sdkV2DeleteEventSubscription struct {
+	client *rds.Client
+}
+
+func NewDeleteEventSubscription(accessKey, secretKey, region string) DeleteEventSubscriptionAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DeleteEventSubscription{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DeleteEventSubscription) DeleteEventSubscription(ctx context.Context, params *rds.DeleteEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.DeleteEventSubscriptionOutput, error) {
+	return a.client.DeleteEventSubscription(ctx, params, optFns...)
+}
+
+type AddSourceIdentifierToSubscriptionAPI interface {
+	AddSourceIdentifierToSubscription(context.Context, *rds.AddSourceIdentifierToSubscriptionInput, ...func(*rds.Options)) (*rds.AddSourceIdentifierToSubscriptionOutput, error)
+}
+
+type sdkV2AddSourceIdentifierToSubscription struct {
+	client *rds.Client
+}
+
+func NewAddSourceIdentifierToSubscription(accessKey, secretKey, region string) AddSourceIdentifierToSubscriptionAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2AddSourceIdentifierToSubscription{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2AddSourceIdentifierToSubscription) AddSourceIdentifierToSubscription(ctx context.Context, params *rds.AddSourceIdentifierToSubscriptionInput, optFns ...func(*rds.Options)) (*rds.AddSourceIdentifierToSubscriptionOutput, error) {
+	return a.client.AddSourceIdentifierToSubscription(ctx, params, optFns...)
+}
+
+type RemoveSourceIdentifierFromSubscriptionAPI interface {
+	RemoveSourceIdentifierFromSubscription(context.Context, *rds.RemoveSourceIdentifierFromSubscriptionInput, ...func(*rds.Options)) (*rds.RemoveSourceIdentifierFromSubscriptionOutput, error)
+}
+
+type sdkV2RemoveSourceIdentifierFromSubscription struct {
+	client *rds.Client
+}
+
+func NewRemoveSourceIdentifierFromSubscription(accessKey, secretKey, region string) RemoveSourceIdentifierFromSubscriptionAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2RemoveSourceIdentifierFromSubscription{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2RemoveSourceIdentifierFromSubscription) RemoveSourceIdentifierFromSubscription(ctx context.Context, params *rds.RemoveSourceIdentifierFromSubscriptionInput, optFns ...func(*rds.Options)) (*rds.RemoveSourceIdentifierFromSubscriptionOutput, error) {
+	return a.client.RemoveSourceIdentifierFromSubscription(ctx, params, optFns...)
+}
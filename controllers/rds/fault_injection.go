@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	// the codes of the injected errors, the throttling errors are retried with backoff by the AWS SDK
+	injectedErrorCode      = "InternalFailure"
+	injectedThrottlingCode = "Throttling"
+)
+
+// FaultInjection injects errors, throttling and latency in the AWS API calls, to validate the alerting and the
+// backoff of the operator in non-production clusters
+type FaultInjection struct {
+	errorRate      float64
+	throttlingRate float64
+	latency        time.Duration
+	// the operations the faults are injected in, all the operations if empty
+	operations map[string]bool
+}
+
+// the fault injection of the AWS API calls, it is set once when the operator starts, before any AWS client is created
+var faultInjection *FaultInjection
+
+// NewFaultInjection returns the fault injection with the rates (0 to 1) of the AWS API calls failing with an error and
+// with a throttling error, and the latency added to the calls of the comma-separated operations
+func NewFaultInjection(errorRate, throttlingRate float64, latency time.Duration, operations string) (*FaultInjection, error) {
+	if errorRate < 0 || errorRate > 1 {
+		return nil, fmt.Errorf("fault injection error rate %v is invalid", errorRate)
+	}
+	if throttlingRate < 0 || throttlingRate > 1 || errorRate+throttlingRate > 1 {
+		return nil, fmt.Errorf("fault injection throttling rate %v is invalid", throttlingRate)
+	}
+	if latency < 0 {
+		return nil, fmt.Errorf("fault injection latency %v is invalid", latency)
+	}
+	f := &FaultInjection{
+		errorRate:      errorRate,
+		throttlingRate: throttlingRate,
+		latency:        latency,
+		operations:     map[string]bool{},
+	}
+	for _, o := range strings.Split(operations, ",") {
+		if o = strings.TrimSpace(o); len(o) > 0 {
+			f.operations[o] = true
+		}
+	}
+	return f, nil
+}
+
+// EnableFaultInjection injects the faults in the AWS API calls of the clients created afterwards
+func EnableFaultInjection(f *FaultInjection) {
+	faultInjection = f
+}
+
+// fault returns the error injected in an attempt of the operation, or nil
+func (f *FaultInjection) fault(operation string) error {
+	if len(f.operations) > 0 && !f.operations[operation] {
+		return nil
+	}
+	switch r := rand.Float64(); {
+	case r < f.errorRate:
+		return &smithy.GenericAPIError{Code: injectedErrorCode, Message: "Fault injected in " + operation, Fault: smithy.FaultServer}
+	case r < f.errorRate+f.throttlingRate:
+		return &smithy.GenericAPIError{Code: injectedThrottlingCode, Message: "Throttling injected in " + operation, Fault: smithy.FaultServer}
+	default:
+		return nil
+	}
+}
+
+// injectFaults adds the middleware injecting the faults, after the retry middleware so each attempt can fail and the
+// retries of the throttling errors are backed off as for the errors returned by AWS
+func injectFaults(o *rds.Options) {
+	f := faultInjection
+	if f == nil {
+		return
+	}
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("InjectFaults",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				operation := awsmiddleware.GetOperationName(ctx)
+				if f.latency > 0 && (len(f.operations) == 0 || f.operations[operation]) {
+					select {
+					case <-time.After(f.latency):
+					case <-ctx.Done():
+						return middleware.FinalizeOutput{}, middleware.Metadata{}, ctx.Err()
+					}
+				}
+				if err := f.fault(operation); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+				return next.HandleFinalize(ctx, in)
+			}), middleware.After)
+	})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type CreateOptionGroupAPI interface {
+	CreateOptionGroup(context.Context, *rds.CreateOptionGroupInput, ...func(*rds.Options)) (*rds.CreateOptionGroupOutput, error)
+}
+
+type sdkV2CreateOptionGroup struct {
+	client *rds.Client
+}
+
+func NewCreateOptionGroup(accessKey, secretKey, region string) CreateOptionGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2CreateOptionGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2CreateOptionGroup) CreateOptionGroup(ctx context.Context, params *rds.CreateOptionGroupInput, optFns ...func(*rds.Options)) (*rds.CreateOptionGroupOutput, error) {
+	return a.client.CreateOptionGroup(ctx, params, optFns...)
+}
+
+type DescribeOptionGroupsAPI interface {
+	DescribeOptionGroups(context.Context, *rds.DescribeOptionGroupsInput, ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error)
+}
+
+type sdkV2DescribeOptionGroups struct {
+	client *rds.Client
+}
+
+func NewDescribeOptionGroups(accessKey, secretKey, region string) DescribeOptionGroupsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeOptionGroups{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeOptionGroups) DescribeOptionGroups(ctx context.Context, params *rds.DescribeOptionGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error) {
+	return a.client.DescribeOptionGroups(ctx, params, optFns...)
+}
+
+type ModifyOptionGroupAPI interface {
+	ModifyOptionGroup(context.Context, *rds.ModifyOptionGroupInput, ...func(*rds.Options)) (*rds.ModifyOptionGroupOutput, error)
+}
+
+type sdkV2ModifyOptionGroup struct {
+	client *rds.Client
+}
+
+func NewModifyOptionGroup(accessKey, secretKey, region string) ModifyOptionGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2ModifyOptionGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2ModifyOptionGroup) ModifyOptionGroup(ctx context.Context, params *rds.ModifyOptionGroupInput, optFns ...func(*rds.Options)) (*rds.ModifyOptionGroupOutput, error) {
+	return a.client.ModifyOptionGroup(ctx, params, optFns...)
+}
+
+type DeleteOptionGroupAPI interface {
+	DeleteOptionGroup(context.Context, *rds.DeleteOptionGroupInput, ...func(*rds.Options)) (*rds.DeleteOptionGroupOutput, error)
+}
+
+type sdkV2DeleteOptionGroup struct {
+	client *rds.Client
+}
+
+func NewDeleteOptionGroup(accessKey, secretKey, region string) DeleteOptionGroupAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DeleteOptionGroup{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DeleteOptionGroup) DeleteOptionGroup(ctx context.Context, params *rds.DeleteOptionGroupInput, optFns ...func(*rds.Options)) (*rds.DeleteOptionGroupOutput, error) {
+	return a.client.DeleteOptionGroup(ctx, params, optFns...)
+}
+
+type DescribeOptionGroupOptionsAPI interface {
+	DescribeOptionGroupOptions(context.Context, *rds.DescribeOptionGroupOptionsInput, ...func(*rds.Options)) (*rds.DescribeOptionGroupOptionsOutput, error)
+}
+
+type sdkV2DescribeOptionGroupOptions struct {
+	client *rds.Client
+}
+
+func NewDescribeOptionGroupOptions(accessKey, secretKey, region string) DescribeOptionGroupOptionsAPI {
+	awsClient := rds.New(rds.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	}, recordCalls, failoverEndpoints, injectFaults)
+	return &sdkV2DescribeOptionGroupOptions{
+		client: awsClient,
+	}
+}
+
+func (a *sdkV2DescribeOptionGroupOptions) DescribeOptionGroupOptions(ctx context.Context, params *rds.DescribeOptionGroupOptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupOptionsOutput, error) {
+	return a.client.DescribeOptionGroupOptions(ctx, params, optFns...)
+}
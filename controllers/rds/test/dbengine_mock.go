@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var (
+	ProviderControllerTestAccessKeySuffix = "PROVIDERCONTROLLER"
+)
+
+var providerTestDBEngineVersions = map[string][]types.DBEngineVersion{
+	"postgres": {
+		{Engine: pointer.String("postgres"), EngineVersion: pointer.String("13.7"), DBEngineVersionDescription: pointer.String("PostgreSQL 13.7-R1")},
+		{Engine: pointer.String("postgres"), EngineVersion: pointer.String("14.5"), DBEngineVersionDescription: pointer.String("PostgreSQL 14.5-R1")},
+	},
+	"mysql": {
+		{Engine: pointer.String("mysql"), EngineVersion: pointer.String("8.0.28"), DBEngineVersionDescription: pointer.String("MySQL Community Edition")},
+	},
+}
+
+var providerTestOrderableDBInstanceOptions = map[string][]types.OrderableDBInstanceOption{
+	"postgres": {
+		{Engine: pointer.String("postgres"), DBInstanceClass: pointer.String("db.t3.micro")},
+		{Engine: pointer.String("postgres"), DBInstanceClass: pointer.String("db.t3.small")},
+		{Engine: pointer.String("postgres"), DBInstanceClass: pointer.String("db.t3.micro")},
+	},
+	"mysql": {
+		{Engine: pointer.String("mysql"), DBInstanceClass: pointer.String("db.t3.micro")},
+		{Engine: pointer.String("mysql"), DBInstanceClass: pointer.String("db.m5.large")},
+	},
+}
+
+type mockDescribeDBEngineVersions struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBEngineVersions(accessKey, secretKey, region string) controllersrds.DescribeDBEngineVersionsAPI {
+	return &mockDescribeDBEngineVersions{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeDBEngineVersions) DescribeDBEngineVersions(ctx context.Context, params *rds.DescribeDBEngineVersionsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+	output := &rds.DescribeDBEngineVersionsOutput{}
+	if strings.HasSuffix(d.accessKey, ProviderControllerTestAccessKeySuffix) && params.Engine != nil {
+		output.DBEngineVersions = providerTestDBEngineVersions[*params.Engine]
+	}
+	return output, nil
+}
+
+type mockDescribeOrderableDBInstanceOptions struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeOrderableDBInstanceOptions(accessKey, secretKey, region string) controllersrds.DescribeOrderableDBInstanceOptionsAPI {
+	return &mockDescribeOrderableDBInstanceOptions{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeOrderableDBInstanceOptions) DescribeOrderableDBInstanceOptions(ctx context.Context, params *rds.DescribeOrderableDBInstanceOptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error) {
+	output := &rds.DescribeOrderableDBInstanceOptionsOutput{}
+	if strings.HasSuffix(d.accessKey, ProviderControllerTestAccessKeySuffix) && params.Engine != nil {
+		output.OrderableDBInstanceOptions = providerTestOrderableDBInstanceOptions[*params.Engine]
+	}
+	return output, nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/utils/pointer"
 
@@ -287,5 +288,63 @@ func (d *mockDescribeDBInstances) DescribeDBInstances(ctx context.Context, param
 	if strings.HasSuffix(d.accessKey, "INVALID") {
 		return nil, fmt.Errorf("invalid accesskey")
 	}
+	if pointer.StringDeref(params.DBInstanceIdentifier, "") == "mock-clone-source-db-instance" {
+		latestRestorableTime := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		return &rds.DescribeDBInstancesOutput{
+			DBInstances: []types.DBInstance{
+				{
+					DBInstanceIdentifier: params.DBInstanceIdentifier,
+					DBInstanceStatus:     pointer.String("available"),
+					Engine:               pointer.String("postgres"),
+					EngineVersion:        pointer.String("14.5"),
+					MasterUsername:       pointer.String("test-username"),
+					DBName:               pointer.String("test-dbname"),
+					AllocatedStorage:     50,
+					LatestRestorableTime: &latestRestorableTime,
+				},
+			},
+		}, nil
+	}
 	return nil, nil
 }
+
+type mockRestoreDBInstanceFromS3 struct {
+	accessKey, secretKey, region string
+}
+
+func NewRestoreDBInstanceFromS3(accessKey, secretKey, region string) controllersrds.RestoreDBInstanceFromS3API {
+	return &mockRestoreDBInstanceFromS3{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (r *mockRestoreDBInstanceFromS3) RestoreDBInstanceFromS3(ctx context.Context, params *rds.RestoreDBInstanceFromS3Input, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceFromS3Output, error) {
+	if strings.HasSuffix(r.accessKey, "INVALID") {
+		return nil, fmt.Errorf("invalid accesskey")
+	}
+	return &rds.RestoreDBInstanceFromS3Output{
+		DBInstance: &types.DBInstance{
+			DBInstanceIdentifier: params.DBInstanceIdentifier,
+			DBInstanceStatus:     pointer.String("creating"),
+			Engine:               params.Engine,
+		},
+	}, nil
+}
+
+type mockRestoreDBInstanceToPointInTime struct {
+	accessKey, secretKey, region string
+}
+
+func NewRestoreDBInstanceToPointInTime(accessKey, secretKey, region string) controllersrds.RestoreDBInstanceToPointInTimeAPI {
+	return &mockRestoreDBInstanceToPointInTime{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (r *mockRestoreDBInstanceToPointInTime) RestoreDBInstanceToPointInTime(ctx context.Context, params *rds.RestoreDBInstanceToPointInTimeInput, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceToPointInTimeOutput, error) {
+	if strings.HasSuffix(r.accessKey, "INVALID") {
+		return nil, fmt.Errorf("invalid accesskey")
+	}
+	return &rds.RestoreDBInstanceToPointInTimeOutput{
+		DBInstance: &types.DBInstance{
+			DBInstanceIdentifier: params.TargetDBInstanceIdentifier,
+			DBInstanceStatus:     pointer.String("creating"),
+		},
+	}, nil
+}
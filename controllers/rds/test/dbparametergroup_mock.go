@@ -0,0 +1,173 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// TLSParameterGroupName is the parameter group of the mock DB services that enforces TLS connections
+const TLSParameterGroupName = "tls-required"
+
+// the parameters of the DB parameter groups created with the mock, by name of the parameter and by apply type
+var mockParameterApplyTypes = map[string]string{
+	"max_connections":            "static",
+	"log_min_duration_statement": "dynamic",
+	"shared_buffers":             "static",
+}
+
+var dbParameterGroups sync.Map
+
+var dbParameterGroupValues sync.Map
+
+type mockDescribeDBParameters struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBParameters(accessKey, secretKey, region string) controllersrds.DescribeDBParametersAPI {
+	return &mockDescribeDBParameters{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockDescribeDBParameters) DescribeDBParameters(ctx context.Context, params *rds.DescribeDBParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error) {
+	if params.DBParameterGroupName != nil {
+		if values, ok := dbParameterGroupValues.Load(*params.DBParameterGroupName); ok {
+			var parameters []types.Parameter
+			values.(*sync.Map).Range(func(name, value interface{}) bool {
+				parameters = append(parameters, types.Parameter{
+					ParameterName:  pointer.String(name.(string)),
+					ParameterValue: pointer.String(value.(string)),
+					ApplyType:      pointer.String(mockParameterApplyTypes[name.(string)]),
+					IsModifiable:   true,
+				})
+				return true
+			})
+			return &rds.DescribeDBParametersOutput{Parameters: parameters}, nil
+		}
+	}
+	return &rds.DescribeDBParametersOutput{Parameters: mockTLSParameters(params.DBParameterGroupName)}, nil
+}
+
+type mockDescribeDBClusterParameters struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBClusterParameters(accessKey, secretKey, region string) controllersrds.DescribeDBClusterParametersAPI {
+	return &mockDescribeDBClusterParameters{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockDescribeDBClusterParameters) DescribeDBClusterParameters(ctx context.Context, params *rds.DescribeDBClusterParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParametersOutput, error) {
+	return &rds.DescribeDBClusterParametersOutput{Parameters: mockTLSParameters(params.DBClusterParameterGroupName)}, nil
+}
+
+func mockTLSParameters(group *string) []types.Parameter {
+	value := "0"
+	if group != nil && *group == TLSParameterGroupName {
+		value = "1"
+	}
+	return []types.Parameter{
+		{ParameterName: pointer.String("rds.force_ssl"), ParameterValue: pointer.String(value)},
+		{ParameterName: pointer.String("require_secure_transport"), ParameterValue: pointer.String(value)},
+	}
+}
+
+type mockCreateDBParameterGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewCreateDBParameterGroup(accessKey, secretKey, region string) controllersrds.CreateDBParameterGroupAPI {
+	return &mockCreateDBParameterGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (c *mockCreateDBParameterGroup) CreateDBParameterGroup(ctx context.Context, params *rds.CreateDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.CreateDBParameterGroupOutput, error) {
+	group := types.DBParameterGroup{
+		DBParameterGroupName:   params.DBParameterGroupName,
+		DBParameterGroupFamily: params.DBParameterGroupFamily,
+		Description:            params.Description,
+		DBParameterGroupArn:    pointer.String(fmt.Sprintf("arn:aws:rds:%s:123456789012:pg:%s", c.region, *params.DBParameterGroupName)),
+	}
+	if _, loaded := dbParameterGroups.LoadOrStore(*params.DBParameterGroupName, group); loaded {
+		return nil, &types.DBParameterGroupAlreadyExistsFault{Message: pointer.String("parameter group already exists")}
+	}
+	values := &sync.Map{}
+	for name := range mockParameterApplyTypes {
+		values.Store(name, "")
+	}
+	dbParameterGroupValues.Store(*params.DBParameterGroupName, values)
+	return &rds.CreateDBParameterGroupOutput{DBParameterGroup: &group}, nil
+}
+
+type mockDescribeDBParameterGroups struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBParameterGroups(accessKey, secretKey, region string) controllersrds.DescribeDBParameterGroupsAPI {
+	return &mockDescribeDBParameterGroups{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeDBParameterGroups) DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error) {
+	if params.DBParameterGroupName == nil {
+		return &rds.DescribeDBParameterGroupsOutput{}, nil
+	}
+	group, ok := dbParameterGroups.Load(*params.DBParameterGroupName)
+	if !ok {
+		return nil, &types.DBParameterGroupNotFoundFault{Message: pointer.String("parameter group not found")}
+	}
+	return &rds.DescribeDBParameterGroupsOutput{DBParameterGroups: []types.DBParameterGroup{group.(types.DBParameterGroup)}}, nil
+}
+
+type mockModifyDBParameterGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewModifyDBParameterGroup(accessKey, secretKey, region string) controllersrds.ModifyDBParameterGroupAPI {
+	return &mockModifyDBParameterGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockModifyDBParameterGroup) ModifyDBParameterGroup(ctx context.Context, params *rds.ModifyDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.ModifyDBParameterGroupOutput, error) {
+	values, ok := dbParameterGroupValues.Load(*params.DBParameterGroupName)
+	if !ok {
+		return nil, &types.DBParameterGroupNotFoundFault{Message: pointer.String("parameter group not found")}
+	}
+	for _, parameter := range params.Parameters {
+		values.(*sync.Map).Store(*parameter.ParameterName, pointer.StringDeref(parameter.ParameterValue, ""))
+	}
+	return &rds.ModifyDBParameterGroupOutput{DBParameterGroupName: params.DBParameterGroupName}, nil
+}
+
+type mockDeleteDBParameterGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewDeleteDBParameterGroup(accessKey, secretKey, region string) controllersrds.DeleteDBParameterGroupAPI {
+	return &mockDeleteDBParameterGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDeleteDBParameterGroup) DeleteDBParameterGroup(ctx context.Context, params *rds.DeleteDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.DeleteDBParameterGroupOutput, error) {
+	if _, loaded := dbParameterGroups.LoadAndDelete(*params.DBParameterGroupName); !loaded {
+		return nil, &types.DBParameterGroupNotFoundFault{Message: pointer.String("parameter group not found")}
+	}
+	dbParameterGroupValues.Delete(*params.DBParameterGroupName)
+	return &rds.DeleteDBParameterGroupOutput{}, nil
+}
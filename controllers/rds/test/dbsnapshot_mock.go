@@ -0,0 +1,138 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var dbSnapshots sync.Map
+
+var exportTasks sync.Map
+
+type mockCreateDBSnapshot struct {
+	accessKey, secretKey, region string
+}
+
+func NewCreateDBSnapshot(accessKey, secretKey, region string) controllersrds.CreateDBSnapshotAPI {
+	return &mockCreateDBSnapshot{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (c *mockCreateDBSnapshot) CreateDBSnapshot(ctx context.Context, params *rds.CreateDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CreateDBSnapshotOutput, error) {
+	snapshot := types.DBSnapshot{
+		DBInstanceIdentifier: params.DBInstanceIdentifier,
+		DBSnapshotIdentifier: params.DBSnapshotIdentifier,
+		DBSnapshotArn:        pointer.String(fmt.Sprintf("arn:aws:rds:%s:123456789012:snapshot:%s", c.region, *params.DBSnapshotIdentifier)),
+		Status:               pointer.String("available"),
+		SnapshotType:         pointer.String("manual"),
+		PercentProgress:      100,
+	}
+	if _, loaded := dbSnapshots.LoadOrStore(*params.DBSnapshotIdentifier, snapshot); loaded {
+		return nil, &types.DBSnapshotAlreadyExistsFault{Message: pointer.String("snapshot already exists")}
+	}
+	return &rds.CreateDBSnapshotOutput{DBSnapshot: &snapshot}, nil
+}
+
+type mockDescribeDBSnapshots struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBSnapshots(accessKey, secretKey, region string) controllersrds.DescribeDBSnapshotsAPI {
+	return &mockDescribeDBSnapshots{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeDBSnapshots) DescribeDBSnapshots(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+	if params.DBSnapshotIdentifier == nil {
+		return &rds.DescribeDBSnapshotsOutput{}, nil
+	}
+	snapshot, ok := dbSnapshots.Load(*params.DBSnapshotIdentifier)
+	if !ok {
+		return nil, &types.DBSnapshotNotFoundFault{Message: pointer.String("snapshot not found")}
+	}
+	return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []types.DBSnapshot{snapshot.(types.DBSnapshot)}}, nil
+}
+
+type mockModifyDBSnapshotAttribute struct {
+	accessKey, secretKey, region string
+}
+
+func NewModifyDBSnapshotAttribute(accessKey, secretKey, region string) controllersrds.ModifyDBSnapshotAttributeAPI {
+	return &mockModifyDBSnapshotAttribute{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockModifyDBSnapshotAttribute) ModifyDBSnapshotAttribute(ctx context.Context, params *rds.ModifyDBSnapshotAttributeInput, optFns ...func(*rds.Options)) (*rds.ModifyDBSnapshotAttributeOutput, error) {
+	return &rds.ModifyDBSnapshotAttributeOutput{}, nil
+}
+
+type mockStartExportTask struct {
+	accessKey, secretKey, region string
+}
+
+func NewStartExportTask(accessKey, secretKey, region string) controllersrds.StartExportTaskAPI {
+	return &mockStartExportTask{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (s *mockStartExportTask) StartExportTask(ctx context.Context, params *rds.StartExportTaskInput, optFns ...func(*rds.Options)) (*rds.StartExportTaskOutput, error) {
+	task := types.ExportTask{
+		ExportTaskIdentifier: params.ExportTaskIdentifier,
+		SourceArn:            params.SourceArn,
+		S3Bucket:             params.S3BucketName,
+		S3Prefix:             params.S3Prefix,
+		IamRoleArn:           params.IamRoleArn,
+		KmsKeyId:             params.KmsKeyId,
+		Status:               pointer.String("COMPLETE"),
+		PercentProgress:      100,
+	}
+	if _, loaded := exportTasks.LoadOrStore(*params.ExportTaskIdentifier, task); loaded {
+		return nil, &types.ExportTaskAlreadyExistsFault{Message: pointer.String("export task already exists")}
+	}
+	return &rds.StartExportTaskOutput{
+		ExportTaskIdentifier: task.ExportTaskIdentifier,
+		SourceArn:            task.SourceArn,
+		S3Bucket:             task.S3Bucket,
+		S3Prefix:             task.S3Prefix,
+		Status:               task.Status,
+		PercentProgress:      task.PercentProgress,
+	}, nil
+}
+
+type mockDescribeExportTasks struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeExportTasks(accessKey, secretKey, region string) controllersrds.DescribeExportTasksAPI {
+	return &mockDescribeExportTasks{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeExportTasks) DescribeExportTasks(ctx context.Context, params *rds.DescribeExportTasksInput, optFns ...func(*rds.Options)) (*rds.DescribeExportTasksOutput, error) {
+	if params.ExportTaskIdentifier == nil {
+		return &rds.DescribeExportTasksOutput{}, nil
+	}
+	task, ok := exportTasks.Load(*params.ExportTaskIdentifier)
+	if !ok {
+		return nil, &types.ExportTaskNotFoundFault{Message: pointer.String("export task not found")}
+	}
+	return &rds.DescribeExportTasksOutput{ExportTasks: []types.ExportTask{task.(types.ExportTask)}}, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var instanceTestDBSubnetGroups = []types.DBSubnetGroup{
+	{
+		DBSubnetGroupName: pointer.String("rhoda-subnet-group-a"),
+		DBSubnetGroupArn:  pointer.String("arn:aws:rds:us-east-1:123456789012:subgrp:rhoda-subnet-group-a"),
+		VpcId:             pointer.String("vpc-a"),
+		Subnets: []types.Subnet{
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1a")}},
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1b")}},
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1c")}},
+		},
+	},
+	{
+		DBSubnetGroupName: pointer.String("rhoda-subnet-group-b"),
+		DBSubnetGroupArn:  pointer.String("arn:aws:rds:us-east-1:123456789012:subgrp:rhoda-subnet-group-b"),
+		VpcId:             pointer.String("vpc-b"),
+		Subnets: []types.Subnet{
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1a")}},
+		},
+	},
+	{
+		DBSubnetGroupName: pointer.String("default"),
+		DBSubnetGroupArn:  pointer.String("arn:aws:rds:us-east-1:123456789012:subgrp:default"),
+		VpcId:             pointer.String("vpc-a"),
+		Subnets: []types.Subnet{
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1a")}},
+			{SubnetAvailabilityZone: &types.AvailabilityZone{Name: pointer.String("us-east-1b")}},
+		},
+	},
+}
+
+var instanceTestResourceTags = map[string][]types.Tag{
+	"arn:aws:rds:us-east-1:123456789012:subgrp:rhoda-subnet-group-b": {
+		{Key: pointer.String("environment"), Value: pointer.String("test")},
+	},
+}
+
+type mockDescribeDBSubnetGroups struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeDBSubnetGroups(accessKey, secretKey, region string) controllersrds.DescribeDBSubnetGroupsAPI {
+	return &mockDescribeDBSubnetGroups{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeDBSubnetGroups) DescribeDBSubnetGroups(ctx context.Context, params *rds.DescribeDBSubnetGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	if !strings.HasSuffix(d.accessKey, InstanceControllerTestAccessKeySuffix) {
+		return &rds.DescribeDBSubnetGroupsOutput{}, nil
+	}
+	var subnetGroups []types.DBSubnetGroup
+	for _, sg := range instanceTestDBSubnetGroups {
+		if params.DBSubnetGroupName == nil || *params.DBSubnetGroupName == *sg.DBSubnetGroupName {
+			subnetGroups = append(subnetGroups, sg)
+		}
+	}
+	return &rds.DescribeDBSubnetGroupsOutput{DBSubnetGroups: subnetGroups}, nil
+}
+
+type mockListTagsForResource struct {
+	accessKey, secretKey, region string
+}
+
+func NewListTagsForResource(accessKey, secretKey, region string) controllersrds.ListTagsForResourceAPI {
+	return &mockListTagsForResource{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (l *mockListTagsForResource) ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error) {
+	if !strings.HasSuffix(l.accessKey, InstanceControllerTestAccessKeySuffix) || params.ResourceName == nil {
+		return &rds.ListTagsForResourceOutput{}, nil
+	}
+	return &rds.ListTagsForResourceOutput{TagList: instanceTestResourceTags[*params.ResourceName]}, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var eventSubscriptions sync.Map
+
+type mockCreateEventSubscription struct {
+	accessKey, secretKey, region string
+}
+
+func NewCreateEventSubscription(accessKey, secretKey, region string) controllersrds.CreateEventSubscriptionAPI {
+	return &mockCreateEventSubscription{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (c *mockCreateEventSubscription) CreateEventSubscription(ctx context.Context, params *rds.CreateEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.CreateEventSubscriptionOutput, error) {
+	subscription := types.EventSubscription{
+		CustSubscriptionId:   params.SubscriptionName,
+		Enabled:              pointer.BoolDeref(params.Enabled, true),
+		EventCategoriesList:  params.EventCategories,
+		EventSubscriptionArn: pointer.String(fmt.Sprintf("arn:aws:rds:%s:123456789012:es:%s", c.region, *params.SubscriptionName)),
+		SnsTopicArn:          params.SnsTopicArn,
+		SourceIdsList:        params.SourceIds,
+		SourceType:           params.SourceType,
+		Status:               pointer.String("active"),
+	}
+	if _, loaded := eventSubscriptions.LoadOrStore(*params.SubscriptionName, subscription); loaded {
+		return nil, &types.SubscriptionAlreadyExistFault{Message: pointer.String("subscription already exists")}
+	}
+	return &rds.CreateEventSubscriptionOutput{EventSubscription: &subscription}, nil
+}
+
+type mockDescribeEventSubscriptions struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeEventSubscriptions(accessKey, secretKey, region string) controllersrds.DescribeEventSubscriptionsAPI {
+	return &mockDescribeEventSubscriptions{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeEventSubscriptions) DescribeEventSubscriptions(ctx context.Context, params *rds.DescribeEventSubscriptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventSubscriptionsOutput, error) {
+	if params.SubscriptionName == nil {
+		return &rds.DescribeEventSubscriptionsOutput{}, nil
+	}
+	subscription, ok := eventSubscriptions.Load(*params.SubscriptionName)
+	if !ok {
+		return nil, &types.SubscriptionNotFoundFault{Message: pointer.String("subscription not found")}
+	}
+	return &rds.DescribeEventSubscriptionsOutput{EventSubscriptionsList: []types.EventSubscription{subscription.(types.EventSubscription)}}, nil
+}
+
+type mockModifyEventSubscription struct {
+	accessKey, secretKey, region string
+}
+
+func NewModifyEventSubscription(accessKey, secretKey, region string) controllersrds.ModifyEventSubscriptionAPI {
+	return &mockModifyEventSubscription{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockModifyEventSubscription) ModifyEventSubscription(ctx context.Context, params *rds.ModifyEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.ModifyEventSubscriptionOutput, error) {
+	s, ok := eventSubscriptions.Load(*params.SubscriptionName)
+	if !ok {
+		return nil, &types.SubscriptionNotFoundFault{Message: pointer.String("subscription not found")}
+	}
+	subscription := s.(types.EventSubscription)
+	if params.SnsTopicArn != nil {
+		subscription.SnsTopicArn = params.SnsTopicArn
+	}
+	if params.SourceType != nil {
+		subscription.SourceType = params.SourceType
+	}
+	if len(params.EventCategories) > 0 {
+		subscription.EventCategoriesList = params.EventCategories
+	}
+	if params.Enabled != nil {
+		subscription.Enabled = *params.Enabled
+	}
+	eventSubscriptions.Store(*params.SubscriptionName, subscription)
+	return &rds.ModifyEventSubscriptionOutput{EventSubscription: &subscription}, nil
+}
+
+type mockDeleteEventSubscription struct {
+	accessKey, secretKey, region string
+}
+
+func NewDeleteEventSubscription(accessKey, secretKey, region string) controllersrds.DeleteEventSubscriptionAPI {
+	return &mockDeleteEventSubscription{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDeleteEventSubscription) DeleteEventSubscription(ctx context.Context, params *rds.DeleteEventSubscriptionInput, optFns ...func(*rds.Options)) (*rds.DeleteEventSubscriptionOutput, error) {
+	if _, loaded := eventSubscriptions.LoadAndDelete(*params.SubscriptionName); !loaded {
+		return nil, &types.SubscriptionNotFoundFault{Message: pointer.String("subscription not found")}
+	}
+	return &rds.DeleteEventSubscriptionOutput{}, nil
+}
+
+type mockAddSourceIdentifierToSubscription struct {
+	accessKey, secretKey, region string
+}
+
+func NewAddSourceIdentifierToSubscription(accessKey, secretKey, region string) controllersrds.AddSourceIdentifierToSubscriptionAPI {
+	return &mockAddSourceIdentifierToSubscription{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (a *mockAddSourceIdentifierToSubscription) AddSourceIdentifierToSubscription(ctx context.Context, params *rds.AddSourceIdentifierToSubscriptionInput, optFns ...func(*rds.Options)) (*rds.AddSourceIdentifierToSubscriptionOutput, error) {
+	s, ok := eventSubscriptions.Load(*params.SubscriptionName)
+	if !ok {
+		return nil, &types.SubscriptionNotFoundFault{Message: pointer.String("subscription not found")}
+	}
+	subscription := s.(types.EventSubscription)
+	subscription.SourceIdsList = append(subscription.SourceIdsList, *params.SourceIdentifier)
+	eventSubscriptions.Store(*params.SubscriptionName, subscription)
+	return &rds.AddSourceIdentifierToSubscriptionOutput{EventSubscription: &subscription}, nil
+}
+
+type mockRemoveSourceIdentifierFromSubscription struct {
+	accessKey, secretKey, region string
+}
+
+func NewRemoveSourceIdentifierFromSubscription(accessKey, secretKey, region string) controllersrds.RemoveSourceIdentifierFromSubscriptionAPI {
+	return &mockRemoveSourceIdentifierFromSubscription{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (r *mockRemoveSourceIdentifierFromSubscription) RemoveSourceIdentifierFromSubscription(ctx context.Context, params *rds.RemoveSourceIdentifierFromSubscriptionInput, optFns ...func(*rds.Options)) (*rds.RemoveSourceIdentifierFromSubscriptionOutput, error) {
+	s, ok := eventSubscriptions.Load(*params.SubscriptionName)
+	if !ok {
+		return nil, &types.SubscriptionNotFoundFault{Message: pointer.String("subscription not found")}
+	}
+	subscription := s.(types.EventSubscription)
+	var sourceIDs []string
+	for _, id := range subscription.SourceIdsList {
+		if id != *params.SourceIdentifier {
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	subscription.SourceIdsList = sourceIDs
+	eventSubscriptions.Store(*params.SubscriptionName, subscription)
+	return &rds.RemoveSourceIdentifierFromSubscriptionOutput{EventSubscription: &subscription}, nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/utils/pointer"
+
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// the options available for the DB option groups created with the mock, by engine
+var mockOptionGroupOptions = map[string][]types.OptionGroupOption{
+	"oracle-ee": {
+		{Name: pointer.String("TDE"), Persistent: true, Permanent: true},
+		{Name: pointer.String("APEX"), OptionGroupOptionVersions: []types.OptionVersion{
+			{Version: pointer.String("21.1.v1"), IsDefault: true},
+		}},
+		{Name: pointer.String("APEX-DEV"), OptionsDependedOn: []string{"APEX"}},
+		{Name: pointer.String("OEM"), PortRequired: true, DefaultPort: pointer.Int32(5500)},
+		{Name: pointer.String("NATIVE_NETWORK_ENCRYPTION"), OptionsConflictsWith: []string{"SSL"}},
+		{Name: pointer.String("SSL"), PortRequired: true, OptionsConflictsWith: []string{"NATIVE_NETWORK_ENCRYPTION"}},
+	},
+	"sqlserver-se": {
+		{Name: pointer.String("TDE"), Persistent: true},
+		{Name: pointer.String("SQLSERVER_BACKUP_RESTORE"), OptionGroupOptionSettings: []types.OptionGroupOptionSetting{
+			{SettingName: pointer.String("IAM_ROLE_ARN"), IsModifiable: true, IsRequired: true},
+		}},
+	},
+}
+
+var optionGroups sync.Map
+
+type mockCreateOptionGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewCreateOptionGroup(accessKey, secretKey, region string) controllersrds.CreateOptionGroupAPI {
+	return &mockCreateOptionGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (c *mockCreateOptionGroup) CreateOptionGroup(ctx context.Context, params *rds.CreateOptionGroupInput, optFns ...func(*rds.Options)) (*rds.CreateOptionGroupOutput, error) {
+	group := types.OptionGroup{
+		OptionGroupName:        params.OptionGroupName,
+		EngineName:             params.EngineName,
+		MajorEngineVersion:     params.MajorEngineVersion,
+		OptionGroupDescription: params.OptionGroupDescription,
+		OptionGroupArn:         pointer.String(fmt.Sprintf("arn:aws:rds:%s:123456789012:og:%s", c.region, *params.OptionGroupName)),
+	}
+	if _, loaded := optionGroups.LoadOrStore(*params.OptionGroupName, group); loaded {
+		return nil, &types.OptionGroupAlreadyExistsFault{Message: pointer.String("option group already exists")}
+	}
+	return &rds.CreateOptionGroupOutput{OptionGroup: &group}, nil
+}
+
+type mockDescribeOptionGroups struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeOptionGroups(accessKey, secretKey, region string) controllersrds.DescribeOptionGroupsAPI {
+	return &mockDescribeOptionGroups{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeOptionGroups) DescribeOptionGroups(ctx context.Context, params *rds.DescribeOptionGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error) {
+	if params.OptionGroupName == nil {
+		return &rds.DescribeOptionGroupsOutput{}, nil
+	}
+	group, ok := optionGroups.Load(*params.OptionGroupName)
+	if !ok {
+		return nil, &types.OptionGroupNotFoundFault{Message: pointer.String("option group not found")}
+	}
+	return &rds.DescribeOptionGroupsOutput{OptionGroupsList: []types.OptionGroup{group.(types.OptionGroup)}}, nil
+}
+
+type mockModifyOptionGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewModifyOptionGroup(accessKey, secretKey, region string) controllersrds.ModifyOptionGroupAPI {
+	return &mockModifyOptionGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (m *mockModifyOptionGroup) ModifyOptionGroup(ctx context.Context, params *rds.ModifyOptionGroupInput, optFns ...func(*rds.Options)) (*rds.ModifyOptionGroupOutput, error) {
+	g, ok := optionGroups.Load(*params.OptionGroupName)
+	if !ok {
+		return nil, &types.OptionGroupNotFoundFault{Message: pointer.String("option group not found")}
+	}
+	group := g.(types.OptionGroup)
+
+	options := map[string]types.Option{}
+	for _, o := range group.Options {
+		options[*o.OptionName] = o
+	}
+	for _, name := range params.OptionsToRemove {
+		delete(options, name)
+	}
+	for _, c := range params.OptionsToInclude {
+		option := types.Option{
+			OptionName:     c.OptionName,
+			OptionVersion:  c.OptionVersion,
+			Port:           c.Port,
+			OptionSettings: c.OptionSettings,
+		}
+		for _, sg := range c.VpcSecurityGroupMemberships {
+			option.VpcSecurityGroupMemberships = append(option.VpcSecurityGroupMemberships, types.VpcSecurityGroupMembership{
+				VpcSecurityGroupId: pointer.String(sg),
+				Status:             pointer.String("active"),
+			})
+		}
+		for _, a := range mockOptionGroupOptions[pointer.StringDeref(group.EngineName, "")] {
+			if *a.Name == *c.OptionName {
+				option.Persistent = a.Persistent
+				option.Permanent = a.Permanent
+			}
+		}
+		options[*c.OptionName] = option
+	}
+	group.Options = nil
+	for _, o := range options {
+		group.Options = append(group.Options, o)
+	}
+	optionGroups.Store(*params.OptionGroupName, group)
+	return &rds.ModifyOptionGroupOutput{OptionGroup: &group}, nil
+}
+
+type mockDeleteOptionGroup struct {
+	accessKey, secretKey, region string
+}
+
+func NewDeleteOptionGroup(accessKey, secretKey, region string) controllersrds.DeleteOptionGroupAPI {
+	return &mockDeleteOptionGroup{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDeleteOptionGroup) DeleteOptionGroup(ctx context.Context, params *rds.DeleteOptionGroupInput, optFns ...func(*rds.Options)) (*rds.DeleteOptionGroupOutput, error) {
+	if _, loaded := optionGroups.LoadAndDelete(*params.OptionGroupName); !loaded {
+		return nil, &types.OptionGroupNotFoundFault{Message: pointer.String("option group not found")}
+	}
+	return &rds.DeleteOptionGroupOutput{}, nil
+}
+
+type mockDescribeOptionGroupOptions struct {
+	accessKey, secretKey, region string
+}
+
+func NewDescribeOptionGroupOptions(accessKey, secretKey, region string) controllersrds.DescribeOptionGroupOptionsAPI {
+	return &mockDescribeOptionGroupOptions{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDescribeOptionGroupOptions) DescribeOptionGroupOptions(ctx context.Context, params *rds.DescribeOptionGroupOptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupOptionsOutput, error) {
+	var options []types.OptionGroupOption
+	for _, o := range mockOptionGroupOptions[pointer.StringDeref(params.EngineName, "")] {
+		o.EngineName = params.EngineName
+		o.MajorEngineVersion = params.MajorEngineVersion
+		options = append(options, o)
+	}
+	return &rds.DescribeOptionGroupOptionsOutput{OptionGroupOptions: options}, nil
+}
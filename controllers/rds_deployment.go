@@ -0,0 +1,180 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the deployment of a DB instance reported in its service info, RDS Custom and RDS on Outposts DB instances do
+	// not support all the operations of the standard DB instances
+	serviceInfoDeploymentType = "deploymentType"
+
+	deploymentTypeStandard = "standard"
+	deploymentTypeCustom   = "custom"
+	deploymentTypeOutposts = "outposts"
+
+	// the deployment of a DB cluster reported in its service info, the Multi-AZ DB clusters run the postgres and mysql
+	// engines on a writer and two readable standby DB instances of a DB cluster instance class
+	deploymentTypeAurora         = "aurora"
+	deploymentTypeMultiAZCluster = "multi-az-cluster"
+
+	serviceInfoAutomationMode               = "automationMode"
+	serviceInfoResumeFullAutomationModeTime = "resumeFullAutomationModeTime"
+	serviceInfoCustomIAMInstanceProfile     = "customIAMInstanceProfile"
+	serviceInfoOutpostARN                   = "outpostARN"
+
+	customEnginePrefix = "custom-"
+
+	connectionStatusReasonUnsupportedDeployment  = "UnsupportedDeployment"
+	connectionStatusMessageUnsupportedDeployment = "Deployment of database service not supported"
+
+	instanceStatusReasonUnsupportedDeployment = "UnsupportedDeployment"
+
+	degradedMessageStorageFullOutposts = "The DB instance has reached its storage capacity allocation of %d GiB, " +
+		"the allocated storage of DB instances on Outposts is not increased automatically as it is limited by the capacity of the Outpost"
+)
+
+// unsupportedDeploymentError is returned for the operations not supported by the deployment of a DB instance
+type unsupportedDeploymentError struct {
+	deploymentType string
+	operation      string
+}
+
+func (e *unsupportedDeploymentError) Error() string {
+	return fmt.Sprintf("%s is not supported for %s DB instances", e.operation, e.deploymentType)
+}
+
+func isCustomEngine(engine string) bool {
+	return strings.HasPrefix(engine, customEnginePrefix)
+}
+
+// getAWSDBInstanceDeploymentType classifies a DB instance returned by DescribeDBInstances
+func getAWSDBInstanceDeploymentType(dbInstance rdstypesv2.DBInstance) string {
+	if isCustomEngine(pointer.StringDeref(dbInstance.Engine, "")) || dbInstance.CustomIamInstanceProfile != nil {
+		return deploymentTypeCustom
+	}
+	if len(getAWSDBInstanceOutpostARN(dbInstance)) > 0 {
+		return deploymentTypeOutposts
+	}
+	return deploymentTypeStandard
+}
+
+func getAWSDBInstanceOutpostARN(dbInstance rdstypesv2.DBInstance) string {
+	if dbInstance.DBSubnetGroup == nil {
+		return ""
+	}
+	for _, subnet := range dbInstance.DBSubnetGroup.Subnets {
+		if subnet.SubnetOutpost != nil && subnet.SubnetOutpost.Arn != nil {
+			return *subnet.SubnetOutpost.Arn
+		}
+	}
+	return ""
+}
+
+// getDeploymentServiceInfo returns the deployment type and the attributes specific to the deployment of a DB instance
+// returned by DescribeDBInstances
+func getDeploymentServiceInfo(dbInstance rdstypesv2.DBInstance) map[string]string {
+	serviceInfo := map[string]string{
+		serviceInfoDeploymentType: getAWSDBInstanceDeploymentType(dbInstance),
+	}
+	switch serviceInfo[serviceInfoDeploymentType] {
+	case deploymentTypeCustom:
+		if len(dbInstance.AutomationMode) > 0 {
+			serviceInfo[serviceInfoAutomationMode] = string(dbInstance.AutomationMode)
+		}
+		if dbInstance.ResumeFullAutomationModeTime != nil {
+			serviceInfo[serviceInfoResumeFullAutomationModeTime] = dbInstance.ResumeFullAutomationModeTime.UTC().Format(time.RFC3339)
+		}
+		if dbInstance.CustomIamInstanceProfile != nil {
+			serviceInfo[serviceInfoCustomIAMInstanceProfile] = *dbInstance.CustomIamInstanceProfile
+		}
+	case deploymentTypeOutposts:
+		serviceInfo[serviceInfoOutpostARN] = getAWSDBInstanceOutpostARN(dbInstance)
+	}
+	return serviceInfo
+}
+
+// newCustomDBInstanceService returns the database service of an RDS Custom DB instance, the RDS Custom DB instances
+// are not adopted as the DB instance controller does not support them so the service is built from the AWS instance
+func newCustomDBInstanceService(dbInstance rdstypesv2.DBInstance, deploymentServiceInfo map[string]string) dbaasv1beta1.DatabaseService {
+	serviceType := dbaasv1beta1.DatabaseServiceType(instanceType)
+	serviceInfo := map[string]string{}
+	if dbInstance.DBInstanceStatus != nil {
+		serviceInfo["dbInstanceStatus"] = *dbInstance.DBInstanceStatus
+	}
+	if dbInstance.Engine != nil {
+		serviceInfo["engine"] = *dbInstance.Engine
+	}
+	if dbInstance.EngineVersion != nil {
+		serviceInfo["engineVersion"] = *dbInstance.EngineVersion
+	}
+	if dbInstance.DBInstanceClass != nil {
+		serviceInfo["dbInstanceClass"] = *dbInstance.DBInstanceClass
+	}
+	if dbInstance.DBInstanceArn != nil {
+		serviceInfo["ackResourceMetadata.arn"] = *dbInstance.DBInstanceArn
+	}
+	for k, v := range deploymentServiceInfo {
+		serviceInfo[k] = v
+	}
+	return dbaasv1beta1.DatabaseService{
+		ServiceID:   *dbInstance.DBInstanceIdentifier,
+		ServiceName: *dbInstance.DBInstanceIdentifier,
+		ServiceType: &serviceType,
+		ServiceInfo: serviceInfo,
+	}
+}
+
+// isOutpostsDBInstance returns whether the DB instance is hosted on an Outpost
+func isOutpostsDBInstance(dbInstance *rdsv1alpha1.DBInstance) bool {
+	if dbInstance.Status.DBSubnetGroup == nil {
+		return false
+	}
+	for _, subnet := range dbInstance.Status.DBSubnetGroup.Subnets {
+		if subnet != nil && subnet.SubnetOutpost != nil && subnet.SubnetOutpost.ARN != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getDBClusterDeploymentType classifies a DB cluster, only the Multi-AZ DB clusters have a DB cluster instance class
+func getDBClusterDeploymentType(dbCluster *rdsv1alpha1.DBCluster) string {
+	if dbCluster.Spec.DBClusterInstanceClass != nil && !strings.HasPrefix(pointer.StringDeref(dbCluster.Spec.Engine, ""), aurora) {
+		return deploymentTypeMultiAZCluster
+	}
+	return deploymentTypeAurora
+}
+
+// checkDeploymentSupported returns an unsupportedDeploymentError if the operation is not supported by the deployment of
+// the database service with the service info
+func checkDeploymentSupported(serviceInfo map[string]string, operation string) error {
+	if t := serviceInfo[serviceInfoDeploymentType]; t == deploymentTypeCustom {
+		return &unsupportedDeploymentError{deploymentType: "RDS Custom", operation: operation}
+	}
+	return nil
+}
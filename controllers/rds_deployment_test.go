@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("RDSDeployment", func() {
+	It("should classify the standard DB instances", func() {
+		serviceInfo := getDeploymentServiceInfo(rdstypesv2.DBInstance{Engine: pointer.String(postgres)})
+		Expect(serviceInfo).Should(Equal(map[string]string{serviceInfoDeploymentType: deploymentTypeStandard}))
+		Expect(checkDeploymentSupported(serviceInfo, "Connection")).Should(Succeed())
+	})
+
+	It("should classify the RDS Custom DB instances", func() {
+		dbInstance := rdstypesv2.DBInstance{
+			DBInstanceIdentifier:     pointer.String("custom-1"),
+			DBInstanceArn:            pointer.String("arn:aws:rds:us-east-1:123456789012:db:custom-1"),
+			DBInstanceStatus:         pointer.String("available"),
+			Engine:                   pointer.String(customSqlserverEe),
+			AutomationMode:           rdstypesv2.AutomationModeAllPaused,
+			CustomIamInstanceProfile: pointer.String("AWSRDSCustomInstanceProfile"),
+		}
+		serviceInfo := getDeploymentServiceInfo(dbInstance)
+		Expect(serviceInfo).Should(Equal(map[string]string{
+			serviceInfoDeploymentType:           deploymentTypeCustom,
+			serviceInfoAutomationMode:           "all-paused",
+			serviceInfoCustomIAMInstanceProfile: "AWSRDSCustomInstanceProfile",
+		}))
+
+		service := newCustomDBInstanceService(dbInstance, serviceInfo)
+		Expect(service.ServiceID).Should(Equal("custom-1"))
+		Expect(service.ServiceInfo).Should(HaveKeyWithValue("dbInstanceStatus", "available"))
+		Expect(service.ServiceInfo).Should(HaveKeyWithValue(serviceInfoDeploymentType, deploymentTypeCustom))
+
+		e := checkDeploymentSupported(service.ServiceInfo, "Connection")
+		Expect(e).Should(HaveOccurred())
+		Expect(e.Error()).Should(Equal("Connection is not supported for RDS Custom DB instances"))
+	})
+
+	It("should classify the DB instances on Outposts", func() {
+		outpostARN := "arn:aws:outposts:us-east-1:123456789012:outpost/op-0123456789abcdef0"
+		serviceInfo := getDeploymentServiceInfo(rdstypesv2.DBInstance{
+			Engine: pointer.String(mysql),
+			DBSubnetGroup: &rdstypesv2.DBSubnetGroup{
+				Subnets: []rdstypesv2.Subnet{{SubnetOutpost: &rdstypesv2.Outpost{Arn: pointer.String(outpostARN)}}},
+			},
+		})
+		Expect(serviceInfo).Should(Equal(map[string]string{
+			serviceInfoDeploymentType: deploymentTypeOutposts,
+			serviceInfoOutpostARN:     outpostARN,
+		}))
+		Expect(checkDeploymentSupported(serviceInfo, "Connection")).Should(Succeed())
+
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		Expect(isOutpostsDBInstance(dbInstance)).Should(BeFalse())
+		dbInstance.Status.DBSubnetGroup = &rdsv1alpha1.DBSubnetGroup_SDK{
+			Subnets: []*rdsv1alpha1.Subnet{{SubnetOutpost: &rdsv1alpha1.Outpost{ARN: pointer.String(outpostARN)}}},
+		}
+		Expect(isOutpostsDBInstance(dbInstance)).Should(BeTrue())
+	})
+
+	It("should classify the Multi-AZ DB clusters", func() {
+		dbCluster := &rdsv1alpha1.DBCluster{Spec: rdsv1alpha1.DBClusterSpec{Engine: pointer.String(auroraPostgresql)}}
+		Expect(getDBClusterDeploymentType(dbCluster)).Should(Equal(deploymentTypeAurora))
+
+		dbCluster.Spec.Engine = pointer.String(postgres)
+		dbCluster.Spec.DBClusterInstanceClass = pointer.String("db.m6gd.large")
+		dbCluster.Spec.StorageType = pointer.String("io1")
+		dbCluster.Status.Endpoint = pointer.String("multi-az.cluster-abc.us-east-1.rds.amazonaws.com")
+		dbCluster.Status.ReaderEndpoint = pointer.String("multi-az.cluster-ro-abc.us-east-1.rds.amazonaws.com")
+		serviceInfo := parseDBClusterStatus(dbCluster)
+		Expect(serviceInfo).Should(HaveKeyWithValue(serviceInfoDeploymentType, deploymentTypeMultiAZCluster))
+		Expect(serviceInfo).Should(HaveKeyWithValue("dbClusterInstanceClass", "db.m6gd.large"))
+		Expect(serviceInfo).Should(HaveKeyWithValue("storageType", "io1"))
+		Expect(serviceInfo).Should(HaveKeyWithValue("endpoint", "multi-az.cluster-abc.us-east-1.rds.amazonaws.com"))
+		Expect(serviceInfo).Should(HaveKeyWithValue("readerEndpoint", "multi-az.cluster-ro-abc.us-east-1.rds.amazonaws.com"))
+	})
+
+	It("should validate the deployment option of the Instances", func() {
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{
+			dbaasv1beta1.ProvisioningDatabaseType: postgres,
+		}
+		Expect(isMultiAZClusterDeployment(rdsInstance)).Should(BeFalse())
+		Expect(validateDeploymentOption(rdsInstance)).Should(Succeed())
+
+		rdsInstance.Spec.ProvisioningParameters[deploymentOption] = deploymentTypeMultiAZCluster
+		Expect(isMultiAZClusterDeployment(rdsInstance)).Should(BeTrue())
+		Expect(validateDeploymentOption(rdsInstance)).Should(Succeed())
+
+		rdsInstance.Spec.ProvisioningParameters[cloneFrom] = "source"
+		e := validateDeploymentOption(rdsInstance)
+		Expect(e).Should(HaveOccurred())
+		Expect(e.Error()).Should(Equal("parameter CloneFrom is not supported for Multi-AZ DB clusters"))
+
+		delete(rdsInstance.Spec.ProvisioningParameters, cloneFrom)
+		rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningDatabaseType] = sqlserverEe
+		Expect(validateDeploymentOption(rdsInstance)).Should(HaveOccurred())
+
+		rdsInstance.Spec.ProvisioningParameters[deploymentOption] = "multi-az"
+		Expect(validateDeploymentOption(rdsInstance)).Should(HaveOccurred())
+	})
+})
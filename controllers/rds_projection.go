@@ -0,0 +1,78 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// awsDBInstance is the projection of an AWS DB instance on the fields used by the Inventory sync, the pages of
+// DescribeDBInstances are projected as they are read so the option groups, parameter groups, endpoints and other
+// nested lists of the response are not kept in memory for the whole sync of a large Inventory
+type awsDBInstance struct {
+	DBInstanceArn        *string
+	DBInstanceIdentifier *string
+	DBClusterIdentifier  *string
+	DBInstanceStatus     *string
+	Engine               *string
+	MasterUsername       *string
+	DBName               *string
+	// the instance is tagged as externally managed, it is not adopted
+	ExternallyManaged bool
+}
+
+func projectDBInstances(dbInstances []rdstypesv2.DBInstance) []awsDBInstance {
+	projected := make([]awsDBInstance, len(dbInstances))
+	for i := range dbInstances {
+		projected[i] = awsDBInstance{
+			DBInstanceArn:        dbInstances[i].DBInstanceArn,
+			DBInstanceIdentifier: dbInstances[i].DBInstanceIdentifier,
+			DBClusterIdentifier:  dbInstances[i].DBClusterIdentifier,
+			DBInstanceStatus:     dbInstances[i].DBInstanceStatus,
+			Engine:               dbInstances[i].Engine,
+			MasterUsername:       dbInstances[i].MasterUsername,
+			DBName:               dbInstances[i].DBName,
+			ExternallyManaged:    isExternallyManagedTagged(dbInstances[i].TagList),
+		}
+	}
+	return projected
+}
+
+// awsDBCluster is the projection of an AWS DB cluster on the fields used by the Inventory sync
+type awsDBCluster struct {
+	DBClusterArn        *string
+	DBClusterIdentifier *string
+	Status              *string
+	Engine              *string
+	MasterUsername      *string
+	DatabaseName        *string
+}
+
+func projectDBClusters(dbClusters []rdstypesv2.DBCluster) []awsDBCluster {
+	projected := make([]awsDBCluster, len(dbClusters))
+	for i := range dbClusters {
+		projected[i] = awsDBCluster{
+			DBClusterArn:        dbClusters[i].DBClusterArn,
+			DBClusterIdentifier: dbClusters[i].DBClusterIdentifier,
+			Status:              dbClusters[i].Status,
+			Engine:              dbClusters[i].Engine,
+			MasterUsername:      dbClusters[i].MasterUsername,
+			DatabaseName:        dbClusters[i].DatabaseName,
+		}
+	}
+	return projected
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("DBInstanceProjection", func() {
+	It("should keep the fields used by the Inventory sync", func() {
+		projected := projectDBInstances([]rdstypesv2.DBInstance{
+			{
+				DBInstanceArn:        pointer.String("arn:aws:rds:us-east-1:123456789012:db:db-1"),
+				DBInstanceIdentifier: pointer.String("db-1"),
+				DBInstanceStatus:     pointer.String("available"),
+				Engine:               pointer.String("postgres"),
+				MasterUsername:       pointer.String("postgres"),
+				DBName:               pointer.String("app"),
+				OptionGroupMemberships: []rdstypesv2.OptionGroupMembership{
+					{OptionGroupName: pointer.String("default:postgres-13")},
+				},
+				TagList: []rdstypesv2.Tag{
+					{Key: pointer.String(managedByTagKey), Value: pointer.String(managedByTagValueExternal)},
+				},
+			},
+		})
+		Expect(projected).Should(Equal([]awsDBInstance{
+			{
+				DBInstanceArn:        pointer.String("arn:aws:rds:us-east-1:123456789012:db:db-1"),
+				DBInstanceIdentifier: pointer.String("db-1"),
+				DBInstanceStatus:     pointer.String("available"),
+				Engine:               pointer.String("postgres"),
+				MasterUsername:       pointer.String("postgres"),
+				DBName:               pointer.String("app"),
+				ExternallyManaged:    true,
+			},
+		}))
+	})
+})
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -31,14 +32,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 )
 
 const (
 	rdsClusterKind = "DBCluster"
 
 	clusterType = "cluster"
+
+	endpointTypeWriter       = "writer"
+	endpointTypeReader       = "reader"
+	endpointTypeCustomPrefix = "custom:"
 )
 
 func parseNamespacedName(namespacedNameString string) types.NamespacedName {
@@ -52,11 +61,16 @@ func parseNamespacedName(namespacedNameString string) types.NamespacedName {
 	}
 }
 
+// getCredentialsSecretName returns the name of the Secret of the master password of a DB service
+func getCredentialsSecretName(name string) string {
+	return fmt.Sprintf("%s-credentials", name)
+}
+
 func setCredentials(ctx context.Context, cli client.Client, scheme *runtime.Scheme, name string,
-	namespace string, owner metav1.Object, kind string, setSpec func(string)) (*v1.Secret, error) {
+	namespace string, owner metav1.Object, kind string, policy passwordPolicy, setSpec func(string)) (*v1.Secret, error) {
 	logger := log.FromContext(ctx)
 
-	secretName := fmt.Sprintf("%s-credentials", name)
+	secretName := getCredentialsSecretName(name)
 	secret := &v1.Secret{}
 	if e := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); e != nil {
 		if errors.IsNotFound(e) {
@@ -73,7 +87,7 @@ func setCredentials(ctx context.Context, cli client.Client, scheme *runtime.Sche
 				}
 			}
 			secret.Data = map[string][]byte{
-				"password": []byte(generatePassword()),
+				"password": []byte(generatePasswordWithPolicy(policy)),
 			}
 			if e := cli.Create(ctx, secret); e != nil {
 				logger.Error(e, "Failed to create credential secret")
@@ -89,7 +103,7 @@ func setCredentials(ctx context.Context, cli client.Client, scheme *runtime.Sche
 		secret.Data = map[string][]byte{}
 	}
 	if _, ok := secret.Data["password"]; !ok {
-		secret.Data["password"] = []byte(generatePassword())
+		secret.Data["password"] = []byte(generatePasswordWithPolicy(policy))
 		if e := cli.Update(ctx, secret); e != nil {
 			logger.Error(e, "Failed to update credential secret")
 			return nil, e
@@ -119,6 +133,43 @@ func createSecretAnnotations(owner metav1.Object, kind string) map[string]string
 	return nil
 }
 
+// parseTagSelector parses the tag selector in the format of key1=value1,key2=value2
+func parseTagSelector(selector string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, t := range strings.Split(selector, ",") {
+		kv := strings.SplitN(strings.TrimSpace(t), "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("tag selector %s not valid", selector)
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
+
+// getDBClusterEndpoint returns the address of the writer, reader or named custom endpoint of the DB cluster
+func getDBClusterEndpoint(dbCluster *rdsv1alpha1.DBCluster, endpointType string) (*string, error) {
+	switch {
+	case len(endpointType) == 0 || endpointType == endpointTypeWriter:
+		return dbCluster.Status.Endpoint, nil
+	case endpointType == endpointTypeReader:
+		return dbCluster.Status.ReaderEndpoint, nil
+	case strings.HasPrefix(endpointType, endpointTypeCustomPrefix):
+		name := strings.TrimPrefix(endpointType, endpointTypeCustomPrefix)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("custom endpoint name not set")
+		}
+		// custom endpoint addresses are in the format of <name>.cluster-custom-<id>.<region>.rds.amazonaws.com
+		for _, ep := range dbCluster.Status.CustomEndpoints {
+			if ep != nil && strings.SplitN(*ep, ".", 2)[0] == name {
+				return ep, nil
+			}
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("endpoint type %s not valid", endpointType)
+	}
+}
+
 func parseDBInstanceStatus(dbInstance *rdsv1alpha1.DBInstance) map[string]string {
 	instanceStatus := map[string]string{}
 	if dbInstance.Spec.Engine != nil {
@@ -466,6 +517,16 @@ func parseDBClusterStatus(dbCluster *rdsv1alpha1.DBCluster) map[string]string {
 	if dbCluster.Spec.EngineVersion != nil {
 		clusterStatus["engineVersion"] = *dbCluster.Spec.EngineVersion
 	}
+	clusterStatus[serviceInfoDeploymentType] = getDBClusterDeploymentType(dbCluster)
+	if dbCluster.Spec.DBClusterInstanceClass != nil {
+		clusterStatus["dbClusterInstanceClass"] = *dbCluster.Spec.DBClusterInstanceClass
+	}
+	if dbCluster.Spec.AllocatedStorage != nil {
+		clusterStatus["allocatedStorage"] = strconv.FormatInt(*dbCluster.Spec.AllocatedStorage, 10)
+	}
+	if dbCluster.Spec.StorageType != nil {
+		clusterStatus["storageType"] = *dbCluster.Spec.StorageType
+	}
 	if dbCluster.Status.ACKResourceMetadata != nil {
 		if dbCluster.Status.ACKResourceMetadata.ARN != nil {
 			clusterStatus["ackResourceMetadata.arn"] = string(*dbCluster.Status.ACKResourceMetadata.ARN)
@@ -698,3 +759,73 @@ func parseDBClusterStatus(dbCluster *rdsv1alpha1.DBCluster) map[string]string {
 	}
 	return clusterStatus
 }
+
+var (
+	awsAccessDeniedErrorCodes = map[string]struct{}{
+		"AccessDenied":                {},
+		"AccessDeniedException":       {},
+		"UnauthorizedOperation":       {},
+		"UnrecognizedClientException": {},
+		"InvalidClientTokenId":        {},
+		"SignatureDoesNotMatch":       {},
+		"ExpiredToken":                {},
+		"ExpiredTokenException":       {},
+	}
+	awsServiceNotFoundErrorCodes = map[string]struct{}{
+		"DBInstanceNotFound":      {},
+		"DBInstanceNotFoundFault": {},
+		"DBClusterNotFoundFault":  {},
+	}
+)
+
+// getAWSErrorReason maps the error code of an AWS API error to the condition reason of the connection
+func getAWSErrorReason(err error, defaultReason string) string {
+	var apiErr smithy.APIError
+	if !goerrors.As(err, &apiErr) {
+		return defaultReason
+	}
+	return getAWSErrorCodeReason(apiErr.ErrorCode(), defaultReason)
+}
+
+func getAWSErrorCodeReason(code, defaultReason string) string {
+	if _, ok := retry.DefaultThrottleErrorCodes[code]; ok {
+		return connectionStatusReasonThrottled
+	}
+	if _, ok := awsAccessDeniedErrorCodes[code]; ok {
+		return connectionStatusReasonAccessDenied
+	}
+	if _, ok := awsServiceNotFoundErrorCodes[code]; ok {
+		return connectionStatusReasonServiceNotFound
+	}
+	return defaultReason
+}
+
+// getACKConditionReason maps the AWS error reported by the terminal or recoverable condition of an ACK resource
+// to the condition reason of the connection, the condition message starts with the AWS error code
+func getACKConditionReason(conditions []*ackv1alpha1.Condition, defaultReason string) string {
+	for _, c := range conditions {
+		if c == nil || c.Status != v1.ConditionTrue || c.Message == nil {
+			continue
+		}
+		if c.Type != ackv1alpha1.ConditionTypeTerminal && c.Type != ackv1alpha1.ConditionTypeRecoverable {
+			continue
+		}
+		code := strings.TrimSpace(strings.SplitN(*c.Message, ":", 2)[0])
+		if reason := getAWSErrorCodeReason(code, ""); len(reason) > 0 {
+			return reason
+		}
+	}
+	return defaultReason
+}
+
+// isNamespaceTerminating returns true if the namespace is being deleted
+func isNamespaceTerminating(ctx context.Context, cli client.Client, name string) (bool, error) {
+	namespace := &v1.Namespace{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !namespace.DeletionTimestamp.IsZero() || namespace.Status.Phase == v1.NamespaceTerminating, nil
+}
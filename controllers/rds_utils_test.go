@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/aws/smithy-go"
+
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+var _ = Describe("RDSUtils", func() {
+	Context("Parse Tag Selector", func() {
+		DescribeTable("checking parseTagSelector",
+			func(selector string, tags map[string]string, valid bool) {
+				t, err := parseTagSelector(selector)
+				if valid {
+					Expect(err).ShouldNot(HaveOccurred())
+				} else {
+					Expect(err).Should(HaveOccurred())
+				}
+				Expect(t).Should(Equal(tags))
+			},
+
+			Entry("single tag", "environment=test", map[string]string{"environment": "test"}, true),
+			Entry("multiple tags", "environment=test, team=db", map[string]string{"environment": "test", "team": "db"}, true),
+			Entry("empty value", "environment=", map[string]string{"environment": ""}, true),
+			Entry("missing value", "environment", nil, false),
+			Entry("missing key", "=test", nil, false),
+			Entry("empty", "", nil, false),
+		)
+	})
+
+	Context("Get DB Cluster Endpoint", func() {
+		dbCluster := &rdsv1alpha1.DBCluster{
+			Status: rdsv1alpha1.DBClusterStatus{
+				Endpoint:       pointer.String("cluster-a.cluster-123456789012.us-east-1.rds.amazonaws.com"),
+				ReaderEndpoint: pointer.String("cluster-a.cluster-ro-123456789012.us-east-1.rds.amazonaws.com"),
+				CustomEndpoints: []*string{
+					pointer.String("analytics.cluster-custom-123456789012.us-east-1.rds.amazonaws.com"),
+					pointer.String("reporting.cluster-custom-123456789012.us-east-1.rds.amazonaws.com"),
+				},
+			},
+		}
+
+		DescribeTable("checking getDBClusterEndpoint",
+			func(endpointType string, endpoint *string, valid bool) {
+				ep, err := getDBClusterEndpoint(dbCluster, endpointType)
+				if valid {
+					Expect(err).ShouldNot(HaveOccurred())
+				} else {
+					Expect(err).Should(HaveOccurred())
+				}
+				Expect(ep).Should(Equal(endpoint))
+			},
+
+			Entry("default", "", pointer.String("cluster-a.cluster-123456789012.us-east-1.rds.amazonaws.com"), true),
+			Entry("writer", "writer", pointer.String("cluster-a.cluster-123456789012.us-east-1.rds.amazonaws.com"), true),
+			Entry("reader", "reader", pointer.String("cluster-a.cluster-ro-123456789012.us-east-1.rds.amazonaws.com"), true),
+			Entry("custom", "custom:reporting", pointer.String("reporting.cluster-custom-123456789012.us-east-1.rds.amazonaws.com"), true),
+			Entry("custom not found", "custom:report", nil, true),
+			Entry("custom without name", "custom:", nil, false),
+			Entry("invalid", "replica", nil, false),
+		)
+	})
+	Context("Get AWS Error Reason", func() {
+		DescribeTable("checking getAWSErrorReason",
+			func(err error, reason string) {
+				Expect(getAWSErrorReason(err, connectionStatusReasonBackendError)).Should(Equal(reason))
+			},
+
+			Entry("throttling", &smithy.GenericAPIError{Code: "ThrottlingException"}, "Throttled"),
+			Entry("access denied", &smithy.GenericAPIError{Code: "AccessDeniedException"}, "AccessDenied"),
+			Entry("instance not found", &smithy.GenericAPIError{Code: "DBInstanceNotFound"}, "DBServiceNotFound"),
+			Entry("wrapped", fmt.Errorf("failed: %w", &smithy.GenericAPIError{Code: "Throttling"}), "Throttled"),
+			Entry("other API error", &smithy.GenericAPIError{Code: "InvalidParameterValue"}, "BackendError"),
+			Entry("not an API error", fmt.Errorf("failed"), "BackendError"),
+		)
+	})
+
+	Context("Get ACK Condition Reason", func() {
+		DescribeTable("checking getACKConditionReason",
+			func(conditions []*ackv1alpha1.Condition, reason string) {
+				Expect(getACKConditionReason(conditions, connectionStatusReasonServiceNotAvailable)).Should(Equal(reason))
+			},
+
+			Entry("no condition", nil, "DBServiceNotAvailable"),
+			Entry("terminal access denied", []*ackv1alpha1.Condition{
+				{
+					Type:    ackv1alpha1.ConditionTypeTerminal,
+					Status:  v1.ConditionTrue,
+					Message: pointer.String("AccessDenied: User is not authorized to perform: rds:DescribeDBInstances"),
+				},
+			}, "AccessDenied"),
+			Entry("recoverable not found", []*ackv1alpha1.Condition{
+				{
+					Type:    ackv1alpha1.ConditionTypeRecoverable,
+					Status:  v1.ConditionTrue,
+					Message: pointer.String("DBInstanceNotFound: DBInstance instance-a not found."),
+				},
+			}, "DBServiceNotFound"),
+			Entry("condition not true", []*ackv1alpha1.Condition{
+				{
+					Type:    ackv1alpha1.ConditionTypeTerminal,
+					Status:  v1.ConditionFalse,
+					Message: pointer.String("AccessDenied: User is not authorized"),
+				},
+			}, "DBServiceNotAvailable"),
+			Entry("synced condition", []*ackv1alpha1.Condition{
+				{
+					Type:    ackv1alpha1.ConditionTypeResourceSynced,
+					Status:  v1.ConditionTrue,
+					Message: pointer.String("Throttling: Rate exceeded"),
+				},
+			}, "DBServiceNotAvailable"),
+		)
+	})
+
+})
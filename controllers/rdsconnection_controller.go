@@ -19,16 +19,26 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -37,6 +47,11 @@ import (
 
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllerscloudwatch "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/cloudwatch"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	controllerssecretsmanager "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager"
+	controllersvault "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 )
@@ -44,16 +59,48 @@ import (
 const (
 	databaseServiceIDKey = ".spec.databaseServiceID"
 
+	connectionEndpointTypeAnnotation = "rds.dbaas.redhat.com/endpoint-type"
+
+	// write the credentials through an ExternalSecret of the secret store instead of a Secret
+	connectionSecretStoreAnnotation     = "rds.dbaas.redhat.com/credentials-secret-store"
+	connectionSecretStoreKindAnnotation = "rds.dbaas.redhat.com/credentials-secret-store-kind"
+	connectionRemoteKeyAnnotation       = "rds.dbaas.redhat.com/credentials-remote-key"
+
+	secretStoreKind       = "SecretStore"
+	externalSecretVersion = "external-secrets.io/v1beta1"
+	externalSecretKind    = "ExternalSecret"
+	pushSecretVersion     = "external-secrets.io/v1alpha1"
+	pushSecretKind        = "PushSecret"
+
 	databaseProvider = "Red Hat DBaaS / Amazon Relational Database Service (RDS)"
 
+	// the connection info keys of the AWS identifiers of the DB service
+	connectionInfoARN        = "arn"
+	connectionInfoResourceID = "resourceId"
+	connectionInfoRegion     = "region"
+
+	// the connection info keys of the engine of the DB service, for the client libraries choosing their driver
+	connectionInfoEngine        = "engine"
+	connectionInfoEngineFamily  = "engineFamily"
+	connectionInfoEngineVersion = "engineVersion"
+
+	// the kind of the owner annotations of the objects of a Connection, which is not set on every Connection read
+	connectionKind = "RDSConnection"
+
 	connectionConditionReady = "ReadyForBinding"
 
-	connectionStatusReasonReady        = "Ready"
-	connectionStatusReasonUpdating     = "Updating"
-	connectionStatusReasonBackendError = "BackendError"
-	connectionStatusReasonInputError   = "InputError"
-	connectionStatusReasonNotFound     = "NotFound"
-	connectionStatusReasonUnreachable  = "Unreachable"
+	connectionStatusReasonReady               = "Ready"
+	connectionStatusReasonUpdating            = "Updating"
+	connectionStatusReasonBackendError        = "BackendError"
+	connectionStatusReasonInputError          = "InputError"
+	connectionStatusReasonUnreachable         = "Unreachable"
+	connectionStatusReasonInventoryNotFound   = "InventoryNotFound"
+	connectionStatusReasonInventoryNotReady   = "InventoryNotReady"
+	connectionStatusReasonServiceNotFound     = "DBServiceNotFound"
+	connectionStatusReasonServiceNotAvailable = "DBServiceNotAvailable"
+	connectionStatusReasonSecretNotFound      = "SecretNotFound"
+	connectionStatusReasonAccessDenied        = "AccessDenied"
+	connectionStatusReasonThrottled           = "Throttled"
 
 	connectionStatusMessageUpdateError       = "Failed to update Connection"
 	connectionStatusMessageUpdating          = "Updating Connection"
@@ -67,16 +114,57 @@ const (
 	connectionStatusMessagePasswordInvalid   = "Password invalid"
 	connectionStatusMessageUsernameNotFound  = "Username not found"
 	connectionStatusMessageEndpointNotFound  = "Endpoint not found"
+	connectionStatusMessageEndpointInvalid   = "Endpoint type invalid"
 	connectionStatusMessageGetPasswordError  = "Failed to get secret for password" //#nosec G101
 	connectionStatusMessageInventoryNotFound = "Inventory not found"
 	connectionStatusMessageInventoryNotReady = "Inventory not ready"
 	connectionStatusMessageGetInventoryError = "Failed to get Inventory"
+	connectionStatusMessageVaultError        = "Failed to publish credentials to Vault"
+	connectionStatusMessageCircuitOpen       = "AWS calls of Inventory suspended after consecutive failures"
+	connectionStatusMessageReplicationError  = "Failed to replicate credentials"
+	connectionStatusMessageCredentialsPush   = "Waiting for the credentials to be pushed to the secret store"
 )
 
 // RDSConnectionReconciler reconciles a RDSConnection object
 type RDSConnectionReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme               *runtime.Scheme
+	Recorder             record.EventRecorder
+	GetGetSecretValueAPI func(accessKey, secretKey, region string) controllerssecretsmanager.GetSecretValueAPI
+	// the connection credentials are published to Vault if set
+	VaultClient    controllersvault.Client
+	CircuitBreaker *CircuitBreaker
+	APIBudget      *APIBudget
+	// the lookups of the TLS requirement of the DB services in their parameter groups, the TLS mode of the clients
+	// is not set in the connection info if not set
+	GetDescribeDBParametersAPI        func(accessKey, secretKey, region string) controllersrds.DescribeDBParametersAPI
+	GetDescribeDBClusterParametersAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBClusterParametersAPI
+	// the hub cluster of the Inventories and the DB services in spoke mode, the Connections are reconciled
+	// against the local cluster if not set
+	Hub cluster.Cluster
+	// the images of the connection test Jobs, the defaults are used if not set
+	ConnectionTestPostgreSQLImage string
+	ConnectionTestMySQLImage      string
+	// the image of the pgbouncer connection poolers, the default is used if not set
+	ConnectionPoolerImage string
+	// the relay of the CloudWatch metrics of the DB services of ready Connections, the metrics are not relayed
+	// if the interval is 0
+	GetGetMetricDataAPI       func(accessKey, secretKey, region string) controllerscloudwatch.GetMetricDataAPI
+	CloudWatchMetricsInterval time.Duration
+	// the reconciles of the Connections in their first bind attempt go ahead of the background work if set
+	Priority *ReconcilePriority
+	// the Connections are only reconciled in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+	// the namespace of the Jobs run with the master credentials of the DB services, such as the Jobs creating the
+	// read-only users, the namespace of the Inventory is used if not set
+	JobNamespace string
+	// the lookups of the addresses of the DB endpoints of the network policies, the default resolver is used if not set
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	// the TLS requirements of the parameter groups by region and name
+	tlsRequirements sync.Map
+	// the last fetch times of the CloudWatch metrics by Connection
+	cloudWatchFetches sync.Map
 }
 
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsconnections,verbs=get;list;watch;create;update;patch;delete
@@ -84,7 +172,14 @@ type RDSConnectionReconciler struct {
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsconnections/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbinstances,verbs=get;list;watch
 //+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbclusters,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;delete;update
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;delete;update;patch
+//+kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=external-secrets.io,resources=pushsecrets,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -93,12 +188,15 @@ type RDSConnectionReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
+	ctx = withApplyRecorder(ctx, r.Recorder)
 
 	var bindingStatus, bindingStatusReason, bindingStatusMessage string
 
 	var connection rdsdbaasv1alpha1.RDSConnection
 	var inventory rdsdbaasv1alpha1.RDSInventory
 	var dbService client.Object
+	// the service info of the DB service in the Inventory status
+	var serviceInfo map[string]string
 
 	var passwordSecret *ackv1alpha1.SecretKeyReference
 	var username *string
@@ -108,6 +206,7 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	var dbName *string
 
 	var masterUserSecret v1.Secret
+	var password []byte
 
 	returnError := func(e error, reason, message string) {
 		result = ctrl.Result{}
@@ -140,7 +239,8 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			Message: bindingStatusMessage,
 		}
 		apimeta.SetStatusCondition(&connection.Status.Conditions, condition)
-		if e := r.Status().Update(ctx, &connection); e != nil {
+		r.CircuitBreaker.setDegradedCondition(&connection.Status.Conditions, connection.Spec.InventoryRef.Namespace, connection.Spec.InventoryRef.Name)
+		if e := updateStatus(ctx, r.Client, &connection); e != nil {
 			if errors.IsConflict(e) {
 				logger.Info("Connection modified, retry reconciling")
 				result = ctrl.Result{Requeue: true}
@@ -171,6 +271,7 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				}
 				if cType == sType {
 					serviceName = &ds.ServiceName
+					serviceInfo = ds.ServiceInfo
 					break
 				}
 			}
@@ -183,7 +284,12 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				e = fmt.Errorf("database service %s not found", connection.Spec.DatabaseServiceID)
 			}
 			logger.Error(e, "DB Service not found from Inventory")
-			returnError(e, connectionStatusReasonNotFound, connectionStatusMessageServiceNotFound)
+			returnError(e, connectionStatusReasonServiceNotFound, connectionStatusMessageServiceNotFound)
+			return true
+		}
+		if e := checkDeploymentSupported(serviceInfo, "Connection"); e != nil {
+			logger.Error(e, "DB Service deployment not supported")
+			returnError(e, connectionStatusReasonUnsupportedDeployment, connectionStatusMessageUnsupportedDeployment)
 			return true
 		}
 
@@ -193,10 +299,14 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			dbService = &rdsv1alpha1.DBInstance{}
 		}
 
-		if e := r.Get(ctx, client.ObjectKey{Namespace: connection.Spec.InventoryRef.Namespace,
+		if e := r.hubReader().Get(ctx, client.ObjectKey{Namespace: connection.Spec.InventoryRef.Namespace,
 			Name: *serviceName}, dbService); e != nil {
 			logger.Error(e, "Failed to get DB Service")
-			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageGetServiceError)
+			if errors.IsNotFound(e) {
+				returnError(e, connectionStatusReasonServiceNotFound, connectionStatusMessageServiceNotFound)
+			} else {
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageGetServiceError)
+			}
 			return true
 		}
 
@@ -205,14 +315,14 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			if s.Status.Status == nil || *s.Status.Status != "available" {
 				e := fmt.Errorf("cluster %s not ready", connection.Spec.DatabaseServiceID)
 				logger.Error(e, "DB Cluster not ready")
-				returnError(e, connectionStatusReasonUnreachable, connectionStatusMessageServiceNotReady)
+				returnError(e, getACKConditionReason(s.Status.Conditions, connectionStatusReasonServiceNotAvailable), connectionStatusMessageServiceNotReady)
 				return true
 			}
 		case *rdsv1alpha1.DBInstance:
 			if s.Status.DBInstanceStatus == nil || *s.Status.DBInstanceStatus != "available" {
 				e := fmt.Errorf("instance %s not ready", connection.Spec.DatabaseServiceID)
 				logger.Error(e, "DB Instance not ready")
-				returnError(e, connectionStatusReasonUnreachable, connectionStatusMessageServiceNotReady)
+				returnError(e, getACKConditionReason(s.Status.Conditions, connectionStatusReasonServiceNotAvailable), connectionStatusMessageServiceNotReady)
 				return true
 			}
 		default:
@@ -226,21 +336,38 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	checkDBConnectionStatus := func() bool {
+		endpointType := connection.Annotations[connectionEndpointTypeAnnotation]
 		switch s := dbService.(type) {
 		case *rdsv1alpha1.DBCluster:
 			engine = s.Spec.Engine
 			passwordSecret = s.Spec.MasterUserPassword
 			username = s.Spec.MasterUsername
-			host = s.Status.Endpoint
+			h, e := getDBClusterEndpoint(s, endpointType)
+			if e != nil {
+				logger.Error(e, "DB Cluster endpoint type not valid")
+				returnError(e, connectionStatusReasonInputError, connectionStatusMessageEndpointInvalid)
+				return true
+			}
+			host = h
 			port = s.Spec.Port
 			dbName = s.Spec.DatabaseName
 		case *rdsv1alpha1.DBInstance:
+			if len(endpointType) > 0 && endpointType != endpointTypeWriter {
+				e := fmt.Errorf("endpoint type %s not supported by instance %s", endpointType, connection.Spec.DatabaseServiceID)
+				logger.Error(e, "DB Instance endpoint type not valid")
+				returnError(e, connectionStatusReasonInputError, connectionStatusMessageEndpointInvalid)
+				return true
+			}
 			engine = s.Spec.Engine
 			passwordSecret = s.Spec.MasterUserPassword
 			username = s.Spec.MasterUsername
 			if s.Status.Endpoint != nil {
 				host = s.Status.Endpoint.Address
 				port = s.Status.Endpoint.Port
+				if s.Spec.Port != nil && port != nil && *s.Spec.Port != *port {
+					// the port of the endpoint is the one the instance accepts connections on
+					logger.Info("DB Instance port does not match the port of its endpoint", "port", *s.Spec.Port, "endpointPort", *port)
+				}
 			} else {
 				host = nil
 				port = nil
@@ -248,6 +375,26 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			dbName = s.Spec.DBName
 		}
 
+		if credentials, e := r.getMasterCredentials(ctx, &inventory, connection.Spec.DatabaseServiceID); e != nil {
+			logger.Error(e, "Failed to get master credentials of DB Service from AWS Secrets Manager")
+			if errors.IsNotFound(e) {
+				returnError(e, connectionStatusReasonSecretNotFound, connectionStatusMessageGetPasswordError)
+				return true
+			}
+			returnError(e, getAWSErrorReason(e, connectionStatusReasonBackendError), connectionStatusMessageGetPasswordError)
+			return true
+		} else if credentials != nil {
+			username = pointer.String(credentials.Username)
+			password = []byte(credentials.Password)
+			if host == nil || port == nil {
+				e := fmt.Errorf("service %s endpoint not found", connection.Spec.DatabaseServiceID)
+				logger.Error(e, "DB Service endpoint not found")
+				returnError(e, connectionStatusReasonUnreachable, connectionStatusMessageEndpointNotFound)
+				return true
+			}
+			return false
+		}
+
 		if passwordSecret == nil {
 			e := fmt.Errorf("service %s master password not set", connection.Spec.DatabaseServiceID)
 			logger.Error(e, "DB Service master password not set")
@@ -255,10 +402,10 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			return true
 		}
 
-		if e := r.Get(ctx, client.ObjectKey{Namespace: passwordSecret.Namespace, Name: passwordSecret.Name}, &masterUserSecret); e != nil {
+		if e := r.hubSecretReader().Get(ctx, client.ObjectKey{Namespace: passwordSecret.Namespace, Name: passwordSecret.Name}, &masterUserSecret); e != nil {
 			logger.Error(e, "Failed to get secret for DB Service master password")
 			if errors.IsNotFound(e) {
-				returnError(e, connectionStatusReasonNotFound, connectionStatusMessageGetPasswordError)
+				returnError(e, connectionStatusReasonSecretNotFound, connectionStatusMessageGetPasswordError)
 			} else {
 				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageGetPasswordError)
 			}
@@ -269,6 +416,8 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			logger.Error(e, "DB Service master password key not set")
 			returnError(e, connectionStatusReasonInputError, connectionStatusMessagePasswordInvalid)
 			return true
+		} else {
+			password = v
 		}
 		if username == nil {
 			e := fmt.Errorf("service %s master username not set", connection.Spec.DatabaseServiceID)
@@ -287,23 +436,186 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	syncConnectionStatus := func() bool {
-		userSecret, e := r.createOrUpdateSecret(ctx, &connection, username, masterUserSecret.Data[passwordSecret.Key])
+		readOnly, e := isReadOnlyConnection(&connection)
+		if e == nil && readOnly {
+			if _, ok := connection.Annotations[connectionSecretStoreAnnotation]; ok {
+				e = fmt.Errorf("read-only Connection not supported with annotation %s", connectionSecretStoreAnnotation)
+			} else if engine != nil {
+				e = validateReadOnlyConnection(*engine, dbName)
+			}
+		}
 		if e != nil {
-			logger.Error(e, "Failed to create or update secret for Connection")
-			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageSecretError)
+			logger.Error(e, "Read-only Connection not valid")
+			returnError(e, connectionStatusReasonInputError, connectionStatusMessageReadOnlyUnsupported)
+			return true
+		}
+		cdc, e := isCDCConnection(&connection)
+		if e == nil && cdc {
+			if _, ok := connection.Annotations[connectionSecretStoreAnnotation]; ok {
+				e = fmt.Errorf("change data capture not supported with annotation %s", connectionSecretStoreAnnotation)
+			} else if engine != nil {
+				e = validateCDCConnection(&connection, *engine)
+			}
+		}
+		if e != nil {
+			logger.Error(e, "Change data capture of Connection not valid")
+			returnError(e, connectionStatusReasonInputError, connectionStatusMessageCDCUnsupported)
 			return true
 		}
+		replicaNamespaces, e := getCredentialsReplicaNamespaces(&connection)
+		if e == nil && len(replicaNamespaces) > 0 {
+			if _, ok := connection.Annotations[connectionSecretStoreAnnotation]; ok {
+				e = fmt.Errorf("credentials replication not supported with annotation %s", connectionSecretStoreAnnotation)
+			}
+		}
+		if e != nil {
+			logger.Error(e, "Credentials replication of Connection not valid")
+			returnError(e, connectionStatusReasonInputError, e.Error())
+			return true
+		}
+
+		if host != nil {
+			if reason, message, e := r.checkEndpointResolution(ctx, &connection, *host); e != nil {
+				logger.Error(e, "DB Service endpoint does not resolve from the cluster", "host", *host)
+				returnError(e, reason, message)
+				return true
+			}
+		}
+
+		masterUsername, masterPassword := username, password
+		if readOnly && engine != nil {
+			u, p, created, e := r.syncReadOnlyUser(ctx, &connection, r.getJobNamespace(&inventory), *engine, username, password, host, port, dbName)
+			if e != nil {
+				logger.Error(e, "Failed to create read-only user for Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageReadOnlyUserError)
+				return true
+			}
+			if !created {
+				returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageReadOnlyUserCreating)
+				return true
+			}
+			username, password = u, p
+		}
+
+		var cdcParameters map[string][]byte
+		if cdc && engine != nil {
+			parameters, done, e := r.syncCDCBootstrap(ctx, &connection, *engine, masterUsername, masterPassword, username, host, port, dbName)
+			if e != nil {
+				logger.Error(e, "Failed to bootstrap change data capture for Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageCDCError)
+				return true
+			}
+			if !done {
+				returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageCDCBootstrapping)
+				return true
+			}
+			cdcParameters = parameters
+		}
+
+		strictTLS, e := isTLSRequiredByInventory(&inventory)
+		if e != nil {
+			logger.Error(e, "Failed to parse TLS requirement of the Inventory")
+			returnError(e, connectionStatusReasonInputError, e.Error())
+			return true
+		}
+		var tlsRequired *bool
+		if engine != nil {
+			t, e := r.isTLSRequired(ctx, &inventory, dbService, serviceInfo, *engine)
+			if e != nil {
+				logger.Error(e, "Failed to get TLS requirement of DB Service from its parameter group")
+				if strictTLS {
+					returnError(e, getAWSErrorReason(e, connectionStatusReasonBackendError), connectionStatusMessageTLSError)
+					return true
+				}
+			}
+			tlsRequired = t
+		}
+		if strictTLS {
+			if message := getTLSNotEnforcedMessage(pointer.StringDeref(engine, ""), tlsRequired); len(message) > 0 {
+				e := fmt.Errorf("service %s may accept connections without TLS", connection.Spec.DatabaseServiceID)
+				logger.Error(e, "DB Service does not enforce TLS as required by the Inventory")
+				returnError(e, connectionStatusReasonTLSNotEnforced, message)
+				return true
+			}
+		}
+
+		var userSecretName string
+		if store, ok := connection.Annotations[connectionSecretStoreAnnotation]; ok && len(store) > 0 {
+			name, pushed, e := r.createOrUpdateExternalSecret(ctx, &connection, store, username, password)
+			if e != nil {
+				logger.Error(e, "Failed to create or update external secret for Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageSecretError)
+				return true
+			}
+			if !pushed {
+				logger.Info("Credentials of Connection not pushed to the secret store yet")
+				returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageCredentialsPush)
+				return true
+			}
+			userSecretName = name
+		} else {
+			var tagLabels map[string]string
+			if mapping, e := getTagLabelMapping(&inventory); e != nil {
+				logger.Error(e, "Failed to parse AWS tag to label mapping of the Inventory")
+			} else {
+				tagLabels = buildTagLabels(serviceInfo, mapping)
+			}
+			userSecret, e := r.createOrUpdateSecret(ctx, &connection, username, password, cdcParameters, tagLabels)
+			if e != nil {
+				logger.Error(e, "Failed to create or update secret for Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageSecretError)
+				return true
+			}
+			userSecretName = userSecret.Name
+			if e := r.syncCredentialsChecksum(ctx, &connection, userSecret, time.Now()); e != nil {
+				if errors.IsConflict(e) {
+					logger.Info("Connection modified, retry reconciling")
+					returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageUpdating)
+					return true
+				}
+				logger.Error(e, "Failed to set credentials checksum of Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageUpdateError)
+				return true
+			}
+			if e := r.syncCredentialsReplicas(ctx, &connection, userSecret); e != nil {
+				if errors.IsConflict(e) {
+					logger.Info("Connection modified, retry reconciling")
+					returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageUpdating)
+					return true
+				}
+				logger.Error(e, "Failed to replicate credentials of Connection")
+				returnError(e, connectionStatusReasonBackendError, connectionStatusMessageReplicationError)
+				return true
+			}
+		}
 
-		dbConfigMap, e := r.createOrUpdateConfigMap(ctx, &connection, engine, dbName, host, port)
+		dbConfigMap, e := r.createOrUpdateConfigMap(ctx, &connection, dbService, engine, dbName, host, port, tlsRequired, strictTLS)
 		if e != nil {
 			logger.Error(e, "Failed to create or update configmap for Connection")
 			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageConfigMapError)
 			return true
 		}
 
-		connection.Status.CredentialsRef = &v1.LocalObjectReference{Name: userSecret.Name}
+		if e := r.publishVaultCredentials(ctx, &connection, &inventory, username, password, dbConfigMap); e != nil {
+			logger.Error(e, "Failed to publish credentials of Connection to Vault")
+			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageVaultError)
+			return true
+		}
+
+		if e := r.updateBindingsIndex(ctx, connection.Namespace, connection.Name, &bindingsIndexEntry{
+			DatabaseServiceID: connection.Spec.DatabaseServiceID,
+			Secret:            userSecretName,
+			ConfigMap:         dbConfigMap.Name,
+			Checksum:          connection.Annotations[connectionCredentialsChecksumAnnotation],
+			LastRotated:       connection.Annotations[connectionCredentialsLastRotatedAnnotation],
+		}); e != nil {
+			// the bindings index is updated again in the next reconcile
+			logger.Error(e, "Failed to update bindings index of Connection namespace")
+		}
+
+		connection.Status.CredentialsRef = &v1.LocalObjectReference{Name: userSecretName}
 		connection.Status.ConnectionInfoRef = &v1.LocalObjectReference{Name: dbConfigMap.Name}
-		if e := r.Status().Update(ctx, &connection); e != nil {
+		if e := updateStatus(ctx, r.Client, &connection); e != nil {
 			if errors.IsConflict(e) {
 				logger.Info("Connection modified, retry reconciling")
 				returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageUpdating)
@@ -319,19 +631,107 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err = r.Get(ctx, req.NamespacedName, &connection); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("RDS Connection resource not found, has been deleted")
+			r.cloudWatchFetches.Delete(req.NamespacedName.String())
+			deleteCloudWatchMetrics(req.Namespace, req.Name)
+			if e := r.updateBindingsIndex(ctx, req.Namespace, req.Name, nil); e != nil {
+				logger.Error(e, "Failed to remove deleted Connection from bindings index")
+				return ctrl.Result{}, e
+			}
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Error fetching RDS Connection for reconcile")
 		return ctrl.Result{}, err
 	}
 
+	if !connection.DeletionTimestamp.IsZero() {
+		namespaceTerminating, e := isNamespaceTerminating(ctx, r.Client, connection.Namespace)
+		if e != nil {
+			logger.Error(e, "Failed to get namespace of deleted Connection")
+			return ctrl.Result{}, e
+		}
+		if namespaceTerminating {
+			if e := r.releaseConnectionFinalizers(ctx, &connection); e != nil {
+				if errors.IsConflict(e) {
+					logger.Info("Connection modified, retry reconciling")
+					return ctrl.Result{Requeue: true}, nil
+				}
+				logger.Error(e, "Failed to remove finalizers of Connection in terminating namespace")
+				return ctrl.Result{}, e
+			}
+			return ctrl.Result{}, nil
+		}
+		if e := r.finalizeCredentialsReplicas(ctx, &connection); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Connection modified, retry reconciling")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			logger.Error(e, "Failed to delete credentials replicas of deleted Connection")
+			return ctrl.Result{}, e
+		}
+		if e := r.finalizeConnectionJobs(ctx, &connection); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Connection modified, retry reconciling")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			logger.Error(e, "Failed to delete Jobs of deleted Connection")
+			return ctrl.Result{}, e
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if isFirstBindAttempt(&connection) {
+		defer r.Priority.beginHigh()()
+	}
+
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", connection.Spec.InventoryRef.Namespace, connection.Spec.InventoryRef.Name))
+	if connection.Spec.DatabaseServiceType == nil || *connection.Spec.DatabaseServiceType != clusterType {
+		logger = logger.WithValues(logging.KeyDBInstanceID, connection.Spec.DatabaseServiceID)
+	}
+	ctx = log.IntoContext(ctx, logger)
+
 	defer updateConnectionReadyCondition()
 
-	if e := r.Get(ctx, client.ObjectKey{Namespace: connection.Spec.InventoryRef.Namespace,
+	if !r.NamespacePolicy.isAllowed(connection.Namespace) {
+		logger.Info("RDS Connection not reconciled in namespace not allowed by the namespace policy")
+		returnError(nil, connectionStatusReasonNamespaceNotAllowed, fmt.Sprintf(connectionStatusMessageNamespaceNotAllowed, connection.Namespace))
+		return
+	}
+
+	expiry, e := getConnectionExpiry(&connection)
+	if e != nil {
+		logger.Error(e, "Failed to parse TTL of Connection")
+		returnError(e, connectionStatusReasonInputError, e.Error())
+		return
+	}
+	expired := !expiry.IsZero() && !time.Now().Before(expiry)
+	if expired {
+		if isConnectionRevoked(&connection) {
+			returnError(nil, connectionStatusReasonExpired, fmt.Sprintf(connectionStatusMessageExpired, expiry.UTC().Format(time.RFC3339)))
+			return
+		}
+		// the credentials are removed from the cluster before the read-only user is dropped from the database
+		if e := r.revokeConnection(ctx, &connection); e != nil {
+			logger.Error(e, "Failed to revoke credentials of expired Connection")
+			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageSecretError)
+			return
+		}
+		if e := r.updateBindingsIndex(ctx, connection.Namespace, connection.Name, nil); e != nil {
+			logger.Error(e, "Failed to remove expired Connection from bindings index")
+		}
+	} else if !expiry.IsZero() {
+		// the Connection is reconciled again when it expires
+		defer func() {
+			if until := time.Until(expiry); err == nil && (result.RequeueAfter == 0 || until < result.RequeueAfter) {
+				result.RequeueAfter = until
+			}
+		}()
+	}
+
+	if e := r.hubReader().Get(ctx, client.ObjectKey{Namespace: connection.Spec.InventoryRef.Namespace,
 		Name: connection.Spec.InventoryRef.Name}, &inventory); e != nil {
 		if errors.IsNotFound(e) {
 			logger.Info("RDS Inventory resource not found, may have been deleted")
-			returnError(e, connectionStatusReasonNotFound, connectionStatusMessageInventoryNotFound)
+			returnError(e, connectionStatusReasonInventoryNotFound, connectionStatusMessageInventoryNotFound)
 			return
 		}
 		logger.Error(e, "Failed to get RDS Inventory")
@@ -339,9 +739,35 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return
 	}
 
+	// the AWS calls of the Connection count against the budget of its Inventory
+	ctx = r.APIBudget.withCallRecorder(ctx, inventory.Namespace, inventory.Name)
+	endpointsCtx, e := withAWSEndpoints(ctx, &inventory)
+	if e != nil {
+		logger.Error(e, "Failed to parse AWS endpoints of the RDS Inventory")
+		returnError(e, connectionStatusReasonInputError, e.Error())
+		return
+	}
+	ctx = endpointsCtx
+
+	if openUntil, open := r.CircuitBreaker.openUntil(inventory.Namespace, inventory.Name); open {
+		logger.Info("AWS calls of the RDS Inventory suspended", "until", openUntil)
+		result = ctrl.Result{RequeueAfter: time.Until(openUntil)}
+		// the Connection is only Degraded, it keeps its binding condition as its DB service is not checked
+		if c := apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionReady); c != nil {
+			bindingStatus = string(c.Status)
+			bindingStatusReason = c.Reason
+			bindingStatusMessage = c.Message
+			return
+		}
+		bindingStatus = string(metav1.ConditionFalse)
+		bindingStatusReason = degradedReasonCircuitOpen
+		bindingStatusMessage = connectionStatusMessageCircuitOpen
+		return
+	}
+
 	if condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); condition == nil || condition.Status != metav1.ConditionTrue {
 		logger.Info("RDS Inventory not ready")
-		returnRequeue(connectionStatusReasonUnreachable, connectionStatusMessageInventoryNotReady)
+		returnRequeue(connectionStatusReasonInventoryNotReady, connectionStatusMessageInventoryNotReady)
 		return
 	}
 
@@ -353,16 +779,52 @@ func (r *RDSConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return
 	}
 
+	if expired {
+		dropped, e := r.dropReadOnlyUser(ctx, &connection, r.getJobNamespace(&inventory), pointer.StringDeref(engine, ""),
+			username, password, host, port, dbName)
+		if e != nil {
+			logger.Error(e, "Failed to drop read-only user of expired Connection")
+			returnError(e, connectionStatusReasonBackendError, connectionStatusMessageRevokeError)
+			return
+		}
+		if !dropped {
+			returnRequeue(connectionStatusReasonUpdating, connectionStatusMessageRevoking)
+			return
+		}
+		logger.Info("Connection expired, credentials revoked", "expiry", expiry)
+		returnError(nil, connectionStatusReasonExpired, fmt.Sprintf(connectionStatusMessageExpired, expiry.UTC().Format(time.RFC3339)))
+		return
+	}
+
 	if syncConnectionStatus() {
 		return
 	}
 
+	if engine != nil {
+		if e := r.syncConnectionTest(ctx, &connection, *engine); e != nil {
+			logger.Error(e, "Failed to run connection test Job for Connection")
+		}
+		if e := r.syncConnectionPooler(ctx, &connection, *engine); e != nil {
+			logger.Error(e, "Failed to sync connection pooler for Connection")
+		}
+		if e := r.syncNetworkPolicy(ctx, &connection, *engine, host, port); e != nil {
+			logger.Error(e, "Failed to sync network policy for Connection")
+		}
+	}
+
+	metricsWait, e := r.syncCloudWatchMetrics(ctx, &connection, &inventory)
+	if e != nil {
+		logger.Error(e, "Failed to relay CloudWatch metrics for Connection")
+		metricsWait = r.CloudWatchMetricsInterval
+	}
+
 	returnReady()
+	result.RequeueAfter = metricsWait
 	return
 }
 
 func (r *RDSConnectionReconciler) createOrUpdateSecret(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
-	username *string, password []byte) (*v1.Secret, error) {
+	username *string, password []byte, extraData map[string][]byte, tagLabels map[string]string) (*v1.Secret, error) {
 	secretName := fmt.Sprintf("%s-credentials", connection.Name)
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -370,13 +832,21 @@ func (r *RDSConnectionReconciler) createOrUpdateSecret(ctx context.Context, conn
 			Namespace: connection.Namespace,
 		},
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+	_, err := createOrApply(ctx, r.Client, secret, func(client.Object) error {
 		secret.ObjectMeta.Labels = buildConnectionLabels()
-		secret.ObjectMeta.Annotations = buildConnectionAnnotations(connection, &secret.ObjectMeta)
+		for k, v := range tagLabels {
+			if _, ok := secret.ObjectMeta.Labels[k]; !ok {
+				secret.ObjectMeta.Labels[k] = v
+			}
+		}
+		secret.ObjectMeta.Annotations = buildConnectionAnnotations(connection)
 		if err := ctrl.SetControllerReference(connection, secret, r.Scheme); err != nil {
 			return err
 		}
 		setSecret(secret, username, password)
+		for k, v := range extraData {
+			secret.Data[k] = v
+		}
 		return nil
 	})
 	if err != nil {
@@ -385,6 +855,204 @@ func (r *RDSConnectionReconciler) createOrUpdateSecret(ctx context.Context, conn
 	return secret, nil
 }
 
+// publishVaultCredentials writes the connection credentials to <vault connections path>/<namespace>/<name> of the Inventory
+func (r *RDSConnectionReconciler) publishVaultCredentials(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	inventory *rdsdbaasv1alpha1.RDSInventory, username *string, password []byte, dbConfigMap *v1.ConfigMap) error {
+	path, ok := inventory.Annotations[vaultConnectionsPathAnnotation]
+	if !ok || len(path) == 0 {
+		return nil
+	}
+	if r.VaultClient == nil {
+		return fmt.Errorf("annotation %s is set, but no Vault server is configured for the operator", vaultConnectionsPathAnnotation)
+	}
+	// the external secret mode does not expose the credentials to the operator
+	if username == nil || len(password) == 0 {
+		return nil
+	}
+	data := map[string]string{
+		"username": *username,
+		"password": string(password),
+	}
+	for k, v := range dbConfigMap.Data {
+		data[k] = v
+	}
+	return r.VaultClient.Write(ctx, fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(path, "/"), connection.Namespace, connection.Name), data)
+}
+
+// createOrUpdateExternalSecret pushes the credentials to the secret store through a PushSecret and creates the
+// ExternalSecret that populates the credentials Secret from the store once they are pushed. The remote key holds the
+// username and password properties and defaults to the database service ID. It returns false while the PushSecret has
+// not written the credentials to the store yet.
+func (r *RDSConnectionReconciler) createOrUpdateExternalSecret(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	store string, username *string, password []byte) (string, bool, error) {
+	secretName := fmt.Sprintf("%s-credentials", connection.Name)
+
+	// the Secret created by the operator before is replaced by the one of the ExternalSecret
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: connection.Namespace, Name: secretName}, secret); err == nil {
+		if metav1.IsControlledBy(secret, connection) {
+			if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+				return "", false, err
+			}
+		}
+	} else if !errors.IsNotFound(err) {
+		return "", false, err
+	}
+
+	if username == nil || len(password) == 0 {
+		return "", false, fmt.Errorf("credentials of service %s not found", connection.Spec.DatabaseServiceID)
+	}
+
+	storeKind := secretStoreKind
+	if kind, ok := connection.Annotations[connectionSecretStoreKindAnnotation]; ok && len(kind) > 0 {
+		storeKind = kind
+	}
+	remoteKey := connection.Spec.DatabaseServiceID
+	if key, ok := connection.Annotations[connectionRemoteKeyAnnotation]; ok && len(key) > 0 {
+		remoteKey = key
+	}
+
+	pushed, err := r.pushCredentials(ctx, connection, store, storeKind, remoteKey, username, password)
+	if err != nil || !pushed {
+		return "", false, err
+	}
+
+	externalSecret := &unstructured.Unstructured{}
+	externalSecret.SetAPIVersion(externalSecretVersion)
+	externalSecret.SetKind(externalSecretKind)
+	externalSecret.SetName(secretName)
+	externalSecret.SetNamespace(connection.Namespace)
+	_, err = createOrApply(ctx, r.Client, externalSecret, func(client.Object) error {
+		externalSecret.SetLabels(buildConnectionLabels())
+		externalSecret.SetAnnotations(buildConnectionAnnotations(connection))
+		if err := ctrl.SetControllerReference(connection, externalSecret, r.Scheme); err != nil {
+			return err
+		}
+		templateLabels := map[string]interface{}{}
+		for k, v := range buildConnectionLabels() {
+			templateLabels[k] = v
+		}
+		externalSecret.Object["spec"] = map[string]interface{}{
+			"refreshInterval": "1h",
+			"secretStoreRef": map[string]interface{}{
+				"name": store,
+				"kind": storeKind,
+			},
+			"target": map[string]interface{}{
+				"name":           secretName,
+				"creationPolicy": "Owner",
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": templateLabels,
+					},
+				},
+			},
+			"data": []interface{}{
+				map[string]interface{}{
+					"secretKey": "username",
+					"remoteRef": map[string]interface{}{
+						"key":      remoteKey,
+						"property": "username",
+					},
+				},
+				map[string]interface{}{
+					"secretKey": "password",
+					"remoteRef": map[string]interface{}{
+						"key":      remoteKey,
+						"property": "password",
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return secretName, true, nil
+}
+
+// pushCredentials writes the credentials to the username and password properties of the remote key in the secret
+// store, through a PushSecret reading them from the <name>-credentials-source Secret of the Connection. The pushed
+// credentials are deleted from the store with the PushSecret.
+func (r *RDSConnectionReconciler) pushCredentials(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
+	store, storeKind, remoteKey string, username *string, password []byte) (bool, error) {
+	sourceName := fmt.Sprintf("%s-credentials-source", connection.Name)
+	source := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sourceName,
+			Namespace: connection.Namespace,
+		},
+	}
+	if _, err := createOrApply(ctx, r.Client, source, func(client.Object) error {
+		source.ObjectMeta.Labels = buildConnectionLabels()
+		source.ObjectMeta.Annotations = buildConnectionAnnotations(connection)
+		if err := ctrl.SetControllerReference(connection, source, r.Scheme); err != nil {
+			return err
+		}
+		setSecret(source, username, password)
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	pushSecret := &unstructured.Unstructured{}
+	pushSecret.SetAPIVersion(pushSecretVersion)
+	pushSecret.SetKind(pushSecretKind)
+	pushSecret.SetName(sourceName)
+	pushSecret.SetNamespace(connection.Namespace)
+	if _, err := createOrApply(ctx, r.Client, pushSecret, func(client.Object) error {
+		pushSecret.SetLabels(buildConnectionLabels())
+		pushSecret.SetAnnotations(buildConnectionAnnotations(connection))
+		if err := ctrl.SetControllerReference(connection, pushSecret, r.Scheme); err != nil {
+			return err
+		}
+		var data []interface{}
+		for _, key := range []string{"username", "password"} {
+			data = append(data, map[string]interface{}{
+				"match": map[string]interface{}{
+					"secretKey": key,
+					"remoteRef": map[string]interface{}{
+						"remoteKey": remoteKey,
+						"property":  key,
+					},
+				},
+			})
+		}
+		pushSecret.Object["spec"] = map[string]interface{}{
+			"refreshInterval": "1h",
+			"deletionPolicy":  "Delete",
+			"secretStoreRefs": []interface{}{
+				map[string]interface{}{
+					"name": store,
+					"kind": storeKind,
+				},
+			},
+			"selector": map[string]interface{}{
+				"secret": map[string]interface{}{
+					"name": sourceName,
+				},
+			},
+			"data": data,
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	return isPushSecretSynced(pushSecret), nil
+}
+
+// isPushSecretSynced returns true if the PushSecret reports the credentials as written to the secret store
+func isPushSecretSynced(pushSecret *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(pushSecret.Object, "status", "conditions")
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok && condition["type"] == "Ready" {
+			return condition["status"] == string(metav1.ConditionTrue)
+		}
+	}
+	return false
+}
+
 func setSecret(secret *v1.Secret, username *string, password []byte) {
 	data := map[string][]byte{
 		"username": []byte(*username),
@@ -394,7 +1062,7 @@ func setSecret(secret *v1.Secret, username *string, password []byte) {
 }
 
 func (r *RDSConnectionReconciler) createOrUpdateConfigMap(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection,
-	engine *string, dbName *string, host *string, port *int64) (*v1.ConfigMap, error) {
+	dbService client.Object, engine *string, dbName *string, host *string, port *int64, tlsRequired *bool, strictTLS bool) (*v1.ConfigMap, error) {
 	cmName := fmt.Sprintf("%s-configs", connection.Name)
 	cm := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -402,13 +1070,15 @@ func (r *RDSConnectionReconciler) createOrUpdateConfigMap(ctx context.Context, c
 			Namespace: connection.Namespace,
 		},
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+	_, err := createOrApply(ctx, r.Client, cm, func(client.Object) error {
 		cm.ObjectMeta.Labels = buildConnectionLabels()
-		cm.ObjectMeta.Annotations = buildConnectionAnnotations(connection, &cm.ObjectMeta)
+		cm.ObjectMeta.Annotations = buildConnectionAnnotations(connection)
 		if err := ctrl.SetControllerReference(connection, cm, r.Scheme); err != nil {
 			return err
 		}
-		setConfigMap(cm, engine, dbName, host, port)
+		setConfigMap(cm, engine, dbName, host, port, tlsRequired, strictTLS)
+		setAWSResourceConnectionInfo(cm.Data, dbService)
+		setEngineConnectionInfo(cm.Data, dbService)
 		return nil
 	})
 	if err != nil {
@@ -417,14 +1087,15 @@ func (r *RDSConnectionReconciler) createOrUpdateConfigMap(ctx context.Context, c
 	return cm, nil
 }
 
-func setConfigMap(cm *v1.ConfigMap, engine *string, dbName *string, host *string, port *int64) {
+func setConfigMap(cm *v1.ConfigMap, engine *string, dbName *string, host *string, port *int64, tlsRequired *bool, strictTLS bool) {
 	dataMap := map[string]string{
-		"type":     generateBindingType(*engine),
+		"type":     generateBindingType(pointer.StringDeref(engine, "")),
 		"provider": databaseProvider,
 		"host":     *host,
 	}
 
-	if dbName != nil {
+	// the DB services created without a database name or port have the defaults of their engine
+	if dbName != nil && len(*dbName) > 0 {
 		dataMap["database"] = *dbName
 	} else if engine != nil {
 		if dbn := getDefaultDBName(*engine); dbn != nil {
@@ -432,7 +1103,7 @@ func setConfigMap(cm *v1.ConfigMap, engine *string, dbName *string, host *string
 		}
 	}
 
-	if port != nil {
+	if port != nil && *port > 0 {
 		dataMap["port"] = strconv.FormatInt(*port, 10)
 	} else if engine != nil {
 		if p := getDefaultDBPort(*engine); p != nil {
@@ -440,44 +1111,139 @@ func setConfigMap(cm *v1.ConfigMap, engine *string, dbName *string, host *string
 		}
 	}
 
+	if engine != nil {
+		setTLSConnectionInfo(dataMap, *engine, tlsRequired, strictTLS)
+	}
+
 	cm.Data = dataMap
 }
 
+// setAWSResourceConnectionInfo sets the ARN, the resource ID and the region of the DB service in the connection info,
+// for the applications calling the AWS APIs on the DB service, for example to generate IAM authentication tokens
+func setAWSResourceConnectionInfo(data map[string]string, dbService client.Object) {
+	var metadata *ackv1alpha1.ResourceMetadata
+	var resourceID *string
+	switch s := dbService.(type) {
+	case *rdsv1alpha1.DBCluster:
+		metadata = s.Status.ACKResourceMetadata
+		resourceID = s.Status.DBClusterResourceID
+	case *rdsv1alpha1.DBInstance:
+		metadata = s.Status.ACKResourceMetadata
+		resourceID = s.Status.DBIResourceID
+	}
+	if metadata != nil {
+		if metadata.ARN != nil {
+			data[connectionInfoARN] = string(*metadata.ARN)
+		}
+		if metadata.Region != nil {
+			data[connectionInfoRegion] = string(*metadata.Region)
+		}
+	}
+	if resourceID != nil {
+		data[connectionInfoResourceID] = *resourceID
+	}
+}
+
+// setEngineConnectionInfo sets the engine, the engine family and the engine version of the DB service in the
+// connection info
+func setEngineConnectionInfo(data map[string]string, dbService client.Object) {
+	var engine, engineVersion *string
+	switch s := dbService.(type) {
+	case *rdsv1alpha1.DBCluster:
+		engine = s.Spec.Engine
+		engineVersion = s.Spec.EngineVersion
+	case *rdsv1alpha1.DBInstance:
+		engine = s.Spec.Engine
+		engineVersion = s.Spec.EngineVersion
+	}
+	if engine != nil && len(*engine) > 0 {
+		data[connectionInfoEngine] = *engine
+		if family := getEngineFamily(*engine); len(family) > 0 {
+			data[connectionInfoEngineFamily] = family
+		}
+	}
+	if engineVersion != nil && len(*engineVersion) > 0 {
+		data[connectionInfoEngineVersion] = *engineVersion
+	}
+}
+
 func buildConnectionLabels() map[string]string {
 	return map[string]string{
 		dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue,
 	}
 }
 
-func buildConnectionAnnotations(connection *rdsdbaasv1alpha1.RDSConnection, obj *metav1.ObjectMeta) map[string]string {
-	annotations := map[string]string{}
-	if obj.Annotations != nil {
-		for key, value := range obj.Annotations {
-			annotations[key] = value
-		}
+// buildConnectionAnnotations returns the annotations applied by the operator, the other annotations are kept by the apply
+func buildConnectionAnnotations(connection *rdsdbaasv1alpha1.RDSConnection) map[string]string {
+	return map[string]string{
+		"managed-by":      "rds-dbaas-operator",
+		"owner":           connection.Name,
+		"owner.kind":      connectionKind,
+		"owner.namespace": connection.Namespace,
 	}
-	annotations["managed-by"] = "rds-dbaas-operator"
-	annotations["owner"] = connection.Name
-	annotations["owner.kind"] = connection.Kind
-	annotations["owner.namespace"] = connection.Namespace
-	return annotations
+}
+
+// hubReader returns the reader of the Inventories and the DB services, which are only in the hub cluster in spoke mode
+func (r *RDSConnectionReconciler) hubReader() client.Reader {
+	if r.Hub != nil {
+		return r.Hub.GetClient()
+	}
+	return r.Client
+}
+
+// hubSecretReader returns the reader of the master password Secrets of the DB services, the Secrets of the hub are not
+// cached in spoke mode, as the spoke user can only get the master password Secrets granted by the hub
+func (r *RDSConnectionReconciler) hubSecretReader() client.Reader {
+	if r.Hub != nil {
+		return r.Hub.GetAPIReader()
+	}
+	return r.Client
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RDSConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var dbInstanceSource, dbClusterSource, inventorySource source.Source
+	if r.Hub != nil {
+		dbInstanceSource = source.NewKindWithCache(&rdsv1alpha1.DBInstance{}, r.Hub.GetCache())
+		dbClusterSource = source.NewKindWithCache(&rdsv1alpha1.DBCluster{}, r.Hub.GetCache())
+		inventorySource = source.NewKindWithCache(&rdsdbaasv1alpha1.RDSInventory{}, r.Hub.GetCache())
+	} else {
+		dbInstanceSource = &source.Kind{Type: &rdsv1alpha1.DBInstance{}}
+		dbClusterSource = &source.Kind{Type: &rdsv1alpha1.DBCluster{}}
+		inventorySource = &source.Kind{Type: &rdsdbaasv1alpha1.RDSInventory{}}
+	}
+
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&rdsdbaasv1alpha1.RDSConnection{}).
+		Owns(&batchv1.Job{}).
 		Watches(
-			&source.Kind{Type: &rdsv1alpha1.DBInstance{}},
-			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+			&source.Kind{Type: &batchv1.Job{}},
+			handler.EnqueueRequestsFromMapFunc(getJobConnectionRequests),
+		).
+		Watches(
+			dbInstanceSource,
+			r.Priority.lowPriority(handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
 				return getInstanceConnectionRequests(o, mgr)
-			}),
+			})),
 		).
 		Watches(
-			&source.Kind{Type: &rdsv1alpha1.DBCluster{}},
-			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+			dbClusterSource,
+			r.Priority.lowPriority(handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
 				return getInstanceConnectionRequests(o, mgr)
-			}),
+			})),
+		).
+		Watches(
+			&source.Kind{Type: &v1.Namespace{}},
+			r.Priority.lowPriority(handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+				return getNamespaceConnectionRequests(o, mgr)
+			})),
+		).
+		Watches(
+			inventorySource,
+			r.Priority.lowPriority(handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+				return getInventoryDependentRequests(o, mgr.GetClient(), &rdsdbaasv1alpha1.RDSConnectionList{})
+			})),
+			builder.WithPredicates(inventoryChanged()),
 		).
 		Complete(r); err != nil {
 		return err
@@ -491,6 +1257,16 @@ func (r *RDSConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rdsdbaasv1alpha1.RDSConnection{}, credentialsReplicaNamespacesKey,
+		indexCredentialsReplicaNamespaces); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rdsdbaasv1alpha1.RDSConnection{}, inventoryRefKey,
+		indexConnectionInventoryRef); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -539,3 +1315,33 @@ func getInstanceConnectionRequests(object client.Object, mgr ctrl.Manager) []rec
 	}
 	return requests
 }
+
+// releaseConnectionFinalizers removes the finalizers of the Connection deleted with its namespace so that they do not
+// block the termination of the namespace, the copies of the credentials Secret in the other namespaces are kept if the
+// namespace deletion policy of the Connection retains them. The Jobs run with the master credentials are always
+// deleted, the failures of the deletions are logged and do not keep the finalizers.
+func (r *RDSConnectionReconciler) releaseConnectionFinalizers(ctx context.Context, connection *rdsdbaasv1alpha1.RDSConnection) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(connection, connectionReplicasFinalizer) &&
+		!controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer) {
+		return nil
+	}
+	if controllerutil.ContainsFinalizer(connection, connectionReplicasFinalizer) {
+		if connection.Annotations[namespaceDeletionPolicyAnnotation] == namespaceDeletionPolicyRetain {
+			logger.Info("Credentials replicas of Connection retained as its namespace is terminating")
+		} else if e := r.deleteCredentialsReplicas(ctx, connection.Namespace, connection.Name, nil); e != nil {
+			logger.Error(e, "Failed to delete credentials replicas of Connection in terminating namespace")
+		}
+	}
+	if controllerutil.ContainsFinalizer(connection, connectionJobsFinalizer) {
+		if e := r.deleteConnectionJobs(ctx, connection); e != nil {
+			logger.Error(e, "Failed to delete Jobs of Connection in terminating namespace")
+		}
+	}
+	return updateObject(ctx, r.Client, connection, func() error {
+		controllerutil.RemoveFinalizer(connection, connectionReplicasFinalizer)
+		controllerutil.RemoveFinalizer(connection, connectionJobsFinalizer)
+		return nil
+	})
+}
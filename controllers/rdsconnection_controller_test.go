@@ -22,7 +22,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -183,7 +185,7 @@ var _ = Describe("RDSConnectionController", func() {
 							return false
 						}
 						condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-						if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+						if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InventoryNotFound" {
 							return false
 						}
 						return true
@@ -221,7 +223,7 @@ var _ = Describe("RDSConnectionController", func() {
 								return false
 							}
 							condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Unreachable" {
+							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InventoryNotReady" {
 								return false
 							}
 							return true
@@ -278,7 +280,7 @@ var _ = Describe("RDSConnectionController", func() {
 									return false
 								}
 								condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-								if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+								if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "DBServiceNotFound" {
 									return false
 								}
 								return true
@@ -300,7 +302,7 @@ var _ = Describe("RDSConnectionController", func() {
 										return false
 									}
 									condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-									if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Unreachable" {
+									if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "DBServiceNotAvailable" {
 										return false
 									}
 									return true
@@ -372,7 +374,7 @@ var _ = Describe("RDSConnectionController", func() {
 												return false
 											}
 											condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-											if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+											if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "SecretNotFound" {
 												return false
 											}
 											return true
@@ -575,6 +577,56 @@ var _ = Describe("RDSConnectionController", func() {
 														Expect(dbOk).Should(BeTrue())
 														Expect(db).Should(Equal("postgres"))
 													})
+
+													It("should run the connection test Job if enabled", func() {
+														By("enabling the connection test of the Connection")
+														conn := &rdsdbaasv1alpha1.RDSConnection{
+															ObjectMeta: metav1.ObjectMeta{
+																Name:      connectionName,
+																Namespace: testNamespace,
+															},
+														}
+														Eventually(func() bool {
+															if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(conn), conn); err != nil {
+																return false
+															}
+															if conn.Annotations == nil {
+																conn.Annotations = map[string]string{}
+															}
+															conn.Annotations["rds.dbaas.redhat.com/connection-test"] = "true"
+															err := k8sClient.Update(ctx, conn)
+															return err == nil
+														}, timeout).Should(BeTrue())
+
+														By("checking the connection test Job of the Connection")
+														job := &batchv1.Job{
+															ObjectMeta: metav1.ObjectMeta{
+																Name:      fmt.Sprintf("%s-connection-test", connectionName),
+																Namespace: testNamespace,
+															},
+														}
+														Eventually(func() bool {
+															err := k8sClient.Get(ctx, client.ObjectKeyFromObject(job), job)
+															return err == nil
+														}, timeout).Should(BeTrue())
+														jobOwner := metav1.GetControllerOf(job)
+														Expect(jobOwner).ShouldNot(BeNil())
+														Expect(jobOwner.Name).Should(Equal(connectionName))
+														Expect(job.Spec.Template.Spec.Containers).Should(HaveLen(1))
+														Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("psql"))
+
+														By("checking the connection test condition of the Connection")
+														Eventually(func() bool {
+															if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(conn), conn); err != nil {
+																return false
+															}
+															condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ConnectionTested")
+															if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Running" {
+																return false
+															}
+															return true
+														}, timeout).Should(BeTrue())
+													})
 												})
 											})
 										})
@@ -875,7 +927,7 @@ var _ = Describe("RDSConnectionController", func() {
 							return false
 						}
 						condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-						if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+						if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InventoryNotFound" {
 							return false
 						}
 						return true
@@ -913,7 +965,7 @@ var _ = Describe("RDSConnectionController", func() {
 								return false
 							}
 							condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Unreachable" {
+							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InventoryNotReady" {
 								return false
 							}
 							return true
@@ -970,7 +1022,7 @@ var _ = Describe("RDSConnectionController", func() {
 									return false
 								}
 								condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-								if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+								if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "DBServiceNotFound" {
 									return false
 								}
 								return true
@@ -992,7 +1044,7 @@ var _ = Describe("RDSConnectionController", func() {
 										return false
 									}
 									condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-									if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Unreachable" {
+									if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "DBServiceNotAvailable" {
 										return false
 									}
 									return true
@@ -1064,7 +1116,7 @@ var _ = Describe("RDSConnectionController", func() {
 												return false
 											}
 											condition := apimeta.FindStatusCondition(conn.Status.Conditions, "ReadyForBinding")
-											if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+											if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "SecretNotFound" {
 												return false
 											}
 											return true
@@ -1281,4 +1333,100 @@ var _ = Describe("RDSConnectionController", func() {
 			})
 		})
 	})
+	Context("when Connection is deleted with its namespace", func() {
+		assertNamespaceDeletion := func(policy string, replicaRetained bool) func() {
+			return func() {
+				namespace := &v1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "connection-namespace-deletion-" + policy,
+					},
+				}
+				assertResourceCreation(namespace)()
+
+				connection := &rdsdbaasv1alpha1.RDSConnection{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "rds-connection-namespace-deletion",
+						Namespace: namespace.Name,
+						Annotations: map[string]string{
+							"rds.dbaas.redhat.com/namespace-deletion-policy": policy,
+						},
+						Finalizers: []string{
+							"rds.dbaas.redhat.com/credentials-replicas",
+							"rds.dbaas.redhat.com/connection-jobs",
+						},
+					},
+					Spec: dbaasv1beta1.DBaaSConnectionSpec{
+						InventoryRef: dbaasv1beta1.NamespacedName{
+							Name:      "inventory-connection-namespace-deletion",
+							Namespace: testNamespace,
+						},
+						DatabaseServiceID: "dbInstance",
+					},
+				}
+				assertResourceCreation(connection)()
+
+				ownerAnnotations := map[string]string{
+					"managed-by":      "rds-dbaas-operator",
+					"owner":           connection.Name,
+					"owner.kind":      "RDSConnection",
+					"owner.namespace": connection.Namespace,
+				}
+				replica := &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "rds-connection-namespace-deletion-credentials-" + policy,
+						Namespace: testNamespace,
+						Labels: map[string]string{
+							dbaasv1beta1.TypeLabelKey:                  dbaasv1beta1.TypeLabelValue,
+							"rds.dbaas.redhat.com/credentials-replica": "true",
+						},
+						Annotations: ownerAnnotations,
+					},
+				}
+				assertResourceCreation(replica)()
+				defer assertResourceDeletionIfExist(replica)()
+				jobSecret := &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "rds-connection-namespace-deletion-job",
+						Namespace: namespace.Name,
+						Labels: map[string]string{
+							dbaasv1beta1.TypeLabelKey:             dbaasv1beta1.TypeLabelValue,
+							"rds.dbaas.redhat.com/connection-job": "true",
+						},
+						Annotations: ownerAnnotations,
+					},
+				}
+				assertResourceCreation(jobSecret)()
+
+				By("deleting the namespace of the Connection")
+				Expect(k8sClient.Delete(ctx, namespace)).Should(Succeed())
+				Eventually(func() bool {
+					if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
+						return false
+					}
+					return namespace.Status.Phase == v1.NamespaceTerminating
+				}, timeout).Should(BeTrue())
+
+				By("checking the Connection deleted without blocking the namespace termination")
+				assertResourceDeletion(connection)()
+
+				By("checking the Secret of the connection Jobs deleted")
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, client.ObjectKeyFromObject(jobSecret), jobSecret)
+					return errors.IsNotFound(err)
+				}, timeout).Should(BeTrue())
+
+				By("checking the copy of the credentials")
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(replica), replica)
+				if replicaRetained {
+					Expect(err).ShouldNot(HaveOccurred())
+				} else {
+					Expect(errors.IsNotFound(err)).Should(BeTrue())
+				}
+			}
+		}
+
+		It("should delete the copies of the credentials with the delete policy", assertNamespaceDeletion("delete", false))
+
+		It("should keep the copies of the credentials with the retain policy", assertNamespaceDeletion("retain", true))
+	})
 })
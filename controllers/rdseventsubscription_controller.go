@@ -0,0 +1,558 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	eventSubscriptionFinalizer = "rds.dbaas.redhat.com/eventsubscription"
+
+	eventSubscriptionRequeueInterval = 1 * time.Minute
+	eventSubscriptionSyncInterval    = 10 * time.Minute
+
+	eventSubscriptionSourceTypeDBInstance = "db-instance"
+
+	eventSubscriptionConditionReady = "EventSubscriptionReady"
+
+	eventSubscriptionStatusReasonReady            = "Ready"
+	eventSubscriptionStatusReasonCreating         = "Creating"
+	eventSubscriptionStatusReasonInstanceNotReady = "InstanceNotReady"
+	eventSubscriptionStatusReasonBackendError     = "BackendError"
+	eventSubscriptionStatusReasonInputError       = "InputError"
+	eventSubscriptionStatusReasonNotFound         = "NotFound"
+	eventSubscriptionStatusReasonUnreachable      = "Unreachable"
+	eventSubscriptionStatusReasonNotAllowed       = "NotAllowed"
+
+	eventSubscriptionStatusMessageCreateError        = "Failed to create event subscription"
+	eventSubscriptionStatusMessageCreating           = "Creating event subscription"
+	eventSubscriptionStatusMessageGetError           = "Failed to get event subscription"
+	eventSubscriptionStatusMessageModifyError        = "Failed to modify event subscription"
+	eventSubscriptionStatusMessageSourcesError       = "Failed to modify sources of event subscription"
+	eventSubscriptionStatusMessageDeleteError        = "Failed to delete event subscription"
+	eventSubscriptionStatusMessageInstanceSourceType = "The source type must be %s for the Instances to be sources of the events"
+	eventSubscriptionStatusMessageInstanceNotFound   = "Instance %s not found"
+	eventSubscriptionStatusMessageInstanceInventory  = "Instance %s is not of Inventory %s/%s"
+	eventSubscriptionStatusMessageInstanceNotReady   = "DB instance of Instance %s not created yet"
+	eventSubscriptionStatusMessageSourcesNotAllowed  = "The Event Subscriptions of namespace %s only have the Instances of their namespace as sources"
+	eventSubscriptionStatusMessageNotCreated         = "Event subscription %s already exists, the Event Subscriptions of namespace %s only manage the event subscriptions they create"
+	eventSubscriptionStatusMessageGetInstanceError   = "Failed to get Instance %s"
+	eventSubscriptionStatusMessageUpdateError        = "Failed to update Event Subscription"
+	eventSubscriptionStatusMessageInventoryNotFound  = "Inventory not found"
+	eventSubscriptionStatusMessageInventoryNotReady  = "Inventory not ready"
+	eventSubscriptionStatusMessageGetInventoryError  = "Failed to get Inventory"
+	eventSubscriptionStatusMessageCredentialsError   = "Failed to get credentials of Inventory"
+)
+
+// RDSEventSubscriptionReconciler reconciles a RDSEventSubscription object
+type RDSEventSubscriptionReconciler struct {
+	client.Client
+	Scheme                                       *runtime.Scheme
+	GetCreateEventSubscriptionAPI                func(accessKey, secretKey, region string) controllersrds.CreateEventSubscriptionAPI
+	GetDescribeEventSubscriptionsAPI             func(accessKey, secretKey, region string) controllersrds.DescribeEventSubscriptionsAPI
+	GetModifyEventSubscriptionAPI                func(accessKey, secretKey, region string) controllersrds.ModifyEventSubscriptionAPI
+	GetDeleteEventSubscriptionAPI                func(accessKey, secretKey, region string) controllersrds.DeleteEventSubscriptionAPI
+	GetAddSourceIdentifierToSubscriptionAPI      func(accessKey, secretKey, region string) controllersrds.AddSourceIdentifierToSubscriptionAPI
+	GetRemoveSourceIdentifierFromSubscriptionAPI func(accessKey, secretKey, region string) controllersrds.RemoveSourceIdentifierFromSubscriptionAPI
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the Event Subscriptions may only use the Inventories in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+}
+
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdseventsubscriptions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdseventsubscriptions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdseventsubscriptions/finalizers,verbs=update
+
+// Reconcile creates the event subscription and sets its SNS topic, source type, event categories and sources to the
+// ones of the spec, the sources include the DB instances of the Instances referenced by the spec once they are
+// created. The event subscription is deleted from AWS when the RDSEventSubscription is deleted.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
+func (r *RDSEventSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	var subscriptionStatus, subscriptionStatusReason, subscriptionStatusMessage string
+
+	var eventSubscription rdsdbaasv1alpha1.RDSEventSubscription
+	var inventory rdsdbaasv1alpha1.RDSInventory
+	var accessKey, secretKey, region string
+	var subscriptionName string
+	var sourceIDs []string
+
+	returnError := func(e error, reason, message string) {
+		result = ctrl.Result{}
+		err = e
+		subscriptionStatus = string(metav1.ConditionFalse)
+		subscriptionStatusReason = reason
+		subscriptionStatusMessage = message
+	}
+
+	returnRequeue := func(reason, message string) {
+		result = ctrl.Result{RequeueAfter: eventSubscriptionRequeueInterval}
+		err = nil
+		subscriptionStatus = string(metav1.ConditionFalse)
+		subscriptionStatusReason = reason
+		subscriptionStatusMessage = message
+	}
+
+	returnReady := func() {
+		result = ctrl.Result{RequeueAfter: eventSubscriptionSyncInterval}
+		err = nil
+		subscriptionStatus = string(metav1.ConditionTrue)
+		subscriptionStatusReason = eventSubscriptionStatusReasonReady
+		subscriptionStatusMessage = ""
+	}
+
+	updateEventSubscriptionReadyCondition := func() {
+		// the status is not updated once the finalizer is removed or when the resource is requeued
+		if len(subscriptionStatusReason) == 0 {
+			return
+		}
+		condition := metav1.Condition{
+			Type:    eventSubscriptionConditionReady,
+			Status:  metav1.ConditionStatus(subscriptionStatus),
+			Reason:  subscriptionStatusReason,
+			Message: subscriptionStatusMessage,
+		}
+		apimeta.SetStatusCondition(&eventSubscription.Status.Conditions, condition)
+		if e := updateStatus(ctx, r.Client, &eventSubscription); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Event Subscription modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+			} else {
+				logger.Error(e, "Failed to update Event Subscription status")
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+
+	checkInventory := func() bool {
+		if e := r.Get(ctx, client.ObjectKey{Namespace: eventSubscription.Spec.InventoryRef.Namespace,
+			Name: eventSubscription.Spec.InventoryRef.Name}, &inventory); e != nil {
+			if errors.IsNotFound(e) {
+				logger.Info("RDS Inventory resource not found, may have been deleted")
+				returnError(e, eventSubscriptionStatusReasonNotFound, eventSubscriptionStatusMessageInventoryNotFound)
+				return true
+			}
+			logger.Error(e, "Failed to get RDS Inventory")
+			returnError(e, eventSubscriptionStatusReasonBackendError, eventSubscriptionStatusMessageGetInventoryError)
+			return true
+		}
+		if e := checkInventoryReference(r.NamespacePolicy, eventSubscription.Namespace, &inventory); e != nil {
+			logger.Info("RDS Inventory not allowed for the Event Subscription", "reason", e.Error())
+			returnError(e, eventSubscriptionStatusReasonNotAllowed, e.Error())
+			return true
+		}
+
+		if condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); condition == nil || condition.Status != metav1.ConditionTrue {
+			logger.Info("RDS Inventory not ready")
+			returnRequeue(eventSubscriptionStatusReasonUnreachable, eventSubscriptionStatusMessageInventoryNotReady)
+			return true
+		}
+
+		secret := &v1.Secret{}
+		if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
+			logger.Error(e, "Failed to get credentials of RDS Inventory")
+			returnError(e, eventSubscriptionStatusReasonBackendError, eventSubscriptionStatusMessageCredentialsError)
+			return true
+		}
+		accessKey = string(secret.Data[awsAccessKeyID])
+		secretKey = string(secret.Data[awsSecretAccessKey])
+		region = string(secret.Data[awsRegion])
+		logger = logger.WithValues(logging.KeyRegion, region)
+		ctx = log.IntoContext(ctx, logger)
+		return false
+	}
+
+	checkFinalizer := func() bool {
+		if eventSubscription.DeletionTimestamp.IsZero() {
+			if !controllerutil.ContainsFinalizer(&eventSubscription, eventSubscriptionFinalizer) {
+				if e := updateObject(ctx, r.Client, &eventSubscription, func() error {
+					controllerutil.AddFinalizer(&eventSubscription, eventSubscriptionFinalizer)
+					return nil
+				}); e != nil {
+					if errors.IsConflict(e) {
+						logger.Info("Event Subscription modified, retry reconciling")
+						result = ctrl.Result{Requeue: true}
+						return true
+					}
+					logger.Error(e, "Failed to add finalizer to Event Subscription")
+					returnError(e, eventSubscriptionStatusReasonBackendError, eventSubscriptionStatusMessageUpdateError)
+					return true
+				}
+				logger.Info("Finalizer added to Event Subscription")
+			}
+			return false
+		}
+
+		if !controllerutil.ContainsFinalizer(&eventSubscription, eventSubscriptionFinalizer) {
+			return true
+		}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: eventSubscription.Spec.InventoryRef.Namespace,
+			Name: eventSubscription.Spec.InventoryRef.Name}, &inventory); e != nil && errors.IsNotFound(e) {
+			// the event subscription cannot be deleted without the credentials of the Inventory
+			logger.Info("RDS Inventory resource not found, event subscription kept in AWS")
+		} else if e == nil && (checkInventoryReference(r.NamespacePolicy, eventSubscription.Namespace, &inventory) != nil ||
+			(eventSubscription.Namespace != inventory.Namespace && len(eventSubscription.Status.SubscriptionARN) == 0)) {
+			// the event subscription is only deleted by the namespaces allowed to use the Inventory, and by the other
+			// namespaces than the one of the Inventory only if they created it
+			logger.Info("RDS Inventory not allowed for the Event Subscription, event subscription kept in AWS")
+		} else {
+			if checkInventory() {
+				return true
+			}
+			deleteEventSubscription := r.GetDeleteEventSubscriptionAPI(accessKey, secretKey, region)
+			if _, e := deleteEventSubscription.DeleteEventSubscription(ctx, &rds.DeleteEventSubscriptionInput{
+				SubscriptionName: pointer.String(subscriptionName),
+			}); e != nil {
+				var notFoundErr *rdstypesv2.SubscriptionNotFoundFault
+				if !goerrors.As(e, &notFoundErr) {
+					logger.Error(e, "Failed to delete event subscription")
+					returnError(e, getAWSErrorReason(e, eventSubscriptionStatusReasonBackendError), eventSubscriptionStatusMessageDeleteError)
+					return true
+				}
+			}
+			logger.Info("Event subscription deleted")
+		}
+
+		if e := updateObject(ctx, r.Client, &eventSubscription, func() error {
+			controllerutil.RemoveFinalizer(&eventSubscription, eventSubscriptionFinalizer)
+			return nil
+		}); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Event Subscription modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+				return true
+			}
+			logger.Error(e, "Failed to remove finalizer from Event Subscription")
+			returnError(e, eventSubscriptionStatusReasonBackendError, eventSubscriptionStatusMessageUpdateError)
+			return true
+		}
+		logger.Info("Finalizer removed from Event Subscription")
+		return true
+	}
+
+	resolveSourceIDs := func() bool {
+		// the event subscriptions of the other namespaces than the one of the Inventory only send the events of their
+		// DB instances
+		if eventSubscription.Namespace != inventory.Namespace &&
+			(len(eventSubscription.Spec.SourceIDs) > 0 || len(eventSubscription.Spec.InstanceRefs) == 0) {
+			e := fmt.Errorf(eventSubscriptionStatusMessageSourcesNotAllowed, eventSubscription.Namespace)
+			returnError(e, eventSubscriptionStatusReasonNotAllowed, e.Error())
+			return true
+		}
+		sourceIDs = append(sourceIDs, eventSubscription.Spec.SourceIDs...)
+		if len(eventSubscription.Spec.InstanceRefs) > 0 && eventSubscription.Spec.SourceType != eventSubscriptionSourceTypeDBInstance {
+			e := fmt.Errorf(eventSubscriptionStatusMessageInstanceSourceType, eventSubscriptionSourceTypeDBInstance)
+			returnError(e, eventSubscriptionStatusReasonInputError, e.Error())
+			return true
+		}
+		for _, name := range eventSubscription.Spec.InstanceRefs {
+			instance := &rdsdbaasv1alpha1.RDSInstance{}
+			if e := r.Get(ctx, client.ObjectKey{Namespace: eventSubscription.Namespace, Name: name}, instance); e != nil {
+				if errors.IsNotFound(e) {
+					logger.Info("RDS Instance of Event Subscription not found", "Instance", name)
+					returnRequeue(eventSubscriptionStatusReasonNotFound, fmt.Sprintf(eventSubscriptionStatusMessageInstanceNotFound, name))
+					return true
+				}
+				logger.Error(e, "Failed to get RDS Instance of Event Subscription", "Instance", name)
+				returnError(e, eventSubscriptionStatusReasonBackendError, fmt.Sprintf(eventSubscriptionStatusMessageGetInstanceError, name))
+				return true
+			}
+			if instance.Spec.InventoryRef != eventSubscription.Spec.InventoryRef {
+				e := fmt.Errorf(eventSubscriptionStatusMessageInstanceInventory, name, eventSubscription.Spec.InventoryRef.Namespace,
+					eventSubscription.Spec.InventoryRef.Name)
+				returnError(e, eventSubscriptionStatusReasonInputError, e.Error())
+				return true
+			}
+			if len(instance.Status.InstanceID) == 0 {
+				logger.Info("DB instance of RDS Instance of Event Subscription not created yet", "Instance", name)
+				returnRequeue(eventSubscriptionStatusReasonInstanceNotReady, fmt.Sprintf(eventSubscriptionStatusMessageInstanceNotReady, name))
+				return true
+			}
+			sourceIDs = append(sourceIDs, instance.Status.InstanceID)
+		}
+		return false
+	}
+
+	syncEventSubscription := func() bool {
+		describeEventSubscriptions := r.GetDescribeEventSubscriptionsAPI(accessKey, secretKey, region)
+		output, e := describeEventSubscriptions.DescribeEventSubscriptions(ctx, &rds.DescribeEventSubscriptionsInput{
+			SubscriptionName: pointer.String(subscriptionName),
+		})
+		var notFoundErr *rdstypesv2.SubscriptionNotFoundFault
+		if e != nil && !goerrors.As(e, &notFoundErr) {
+			logger.Error(e, "Failed to get event subscription")
+			returnError(e, getAWSErrorReason(e, eventSubscriptionStatusReasonBackendError), eventSubscriptionStatusMessageGetError)
+			return true
+		}
+
+		var subscription *rdstypesv2.EventSubscription
+		if e == nil && len(output.EventSubscriptionsList) > 0 {
+			if eventSubscription.Namespace != inventory.Namespace && len(eventSubscription.Status.SubscriptionARN) == 0 {
+				// the event subscriptions of the Inventory are not adopted by the other namespaces
+				e := fmt.Errorf(eventSubscriptionStatusMessageNotCreated, subscriptionName, eventSubscription.Namespace)
+				returnError(e, eventSubscriptionStatusReasonNotAllowed, e.Error())
+				return true
+			}
+			subscription = &output.EventSubscriptionsList[0]
+		} else {
+			input := &rds.CreateEventSubscriptionInput{
+				SubscriptionName: pointer.String(subscriptionName),
+				SnsTopicArn:      pointer.String(eventSubscription.Spec.SnsTopicARN),
+				Enabled:          pointer.Bool(pointer.BoolDeref(eventSubscription.Spec.Enabled, true)),
+				EventCategories:  eventSubscription.Spec.EventCategories,
+				SourceIds:        sourceIDs,
+			}
+			if len(eventSubscription.Spec.SourceType) > 0 {
+				input.SourceType = pointer.String(eventSubscription.Spec.SourceType)
+			}
+			createEventSubscription := r.GetCreateEventSubscriptionAPI(accessKey, secretKey, region)
+			created, e := createEventSubscription.CreateEventSubscription(ctx, input)
+			var existsErr *rdstypesv2.SubscriptionAlreadyExistFault
+			if goerrors.As(e, &existsErr) {
+				// the name is the idempotency key of the creation, this is a retry of a request that timed out
+				logger.Info("Event subscription already created, retry syncing")
+				returnRequeue(eventSubscriptionStatusReasonCreating, eventSubscriptionStatusMessageCreating)
+				return true
+			}
+			if e != nil {
+				logger.Error(e, "Failed to create event subscription")
+				returnError(e, getEventSubscriptionErrorReason(e), eventSubscriptionStatusMessageCreateError)
+				return true
+			}
+			logger.Info("Event subscription created")
+			subscription = created.EventSubscription
+			if subscription != nil {
+				// the event subscription is managed by the Event Subscription once created
+				eventSubscription.Status.SubscriptionARN = pointer.StringDeref(subscription.EventSubscriptionArn, "")
+			}
+		}
+		if subscription == nil {
+			return false
+		}
+
+		if input := getEventSubscriptionModification(&eventSubscription, subscription); input != nil {
+			input.SubscriptionName = pointer.String(subscriptionName)
+			modifyEventSubscription := r.GetModifyEventSubscriptionAPI(accessKey, secretKey, region)
+			modified, e := modifyEventSubscription.ModifyEventSubscription(ctx, input)
+			if e != nil {
+				logger.Error(e, "Failed to modify event subscription")
+				returnError(e, getEventSubscriptionErrorReason(e), eventSubscriptionStatusMessageModifyError)
+				return true
+			}
+			logger.Info("Event subscription modified")
+			if modified.EventSubscription != nil {
+				subscription = modified.EventSubscription
+			}
+		}
+
+		add, remove := getEventSubscriptionSourceChanges(sourceIDs, subscription.SourceIdsList)
+		for _, id := range add {
+			addSourceIdentifier := r.GetAddSourceIdentifierToSubscriptionAPI(accessKey, secretKey, region)
+			if _, e := addSourceIdentifier.AddSourceIdentifierToSubscription(ctx, &rds.AddSourceIdentifierToSubscriptionInput{
+				SubscriptionName: pointer.String(subscriptionName),
+				SourceIdentifier: pointer.String(id),
+			}); e != nil {
+				logger.Error(e, "Failed to add source to event subscription", "Source", id)
+				returnError(e, getEventSubscriptionErrorReason(e), eventSubscriptionStatusMessageSourcesError)
+				return true
+			}
+		}
+		for _, id := range remove {
+			removeSourceIdentifier := r.GetRemoveSourceIdentifierFromSubscriptionAPI(accessKey, secretKey, region)
+			if _, e := removeSourceIdentifier.RemoveSourceIdentifierFromSubscription(ctx, &rds.RemoveSourceIdentifierFromSubscriptionInput{
+				SubscriptionName: pointer.String(subscriptionName),
+				SourceIdentifier: pointer.String(id),
+			}); e != nil {
+				var sourceNotFoundErr *rdstypesv2.SourceNotFoundFault
+				if !goerrors.As(e, &sourceNotFoundErr) {
+					logger.Error(e, "Failed to remove source from event subscription", "Source", id)
+					returnError(e, getEventSubscriptionErrorReason(e), eventSubscriptionStatusMessageSourcesError)
+					return true
+				}
+			}
+		}
+		if len(add) > 0 || len(remove) > 0 {
+			logger.Info("Event subscription sources modified", "Added", len(add), "Removed", len(remove))
+		}
+
+		eventSubscription.Status.SubscriptionARN = pointer.StringDeref(subscription.EventSubscriptionArn, "")
+		eventSubscription.Status.SubscriptionStatus = pointer.StringDeref(subscription.Status, "")
+		eventSubscription.Status.SourceIDs = getSortedSet(sourceIDs)
+		return false
+	}
+
+	if err = r.Get(ctx, req.NamespacedName, &eventSubscription); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("RDS Event Subscription resource not found, has been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error fetching RDS Event Subscription for reconcile")
+		return ctrl.Result{}, err
+	}
+
+	subscriptionName = getEventSubscriptionName(&eventSubscription)
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", eventSubscription.Spec.InventoryRef.Namespace, eventSubscription.Spec.InventoryRef.Name),
+		"Event Subscription", subscriptionName)
+	ctx = log.IntoContext(ctx, logger)
+
+	defer updateEventSubscriptionReadyCondition()
+
+	if checkFinalizer() {
+		return
+	}
+
+	if checkInventory() {
+		return
+	}
+
+	if resolveSourceIDs() {
+		return
+	}
+
+	if syncEventSubscription() {
+		return
+	}
+
+	returnReady()
+	return
+}
+
+// getEventSubscriptionName returns the name of the event subscription, defaults to the name of the RDSEventSubscription
+func getEventSubscriptionName(eventSubscription *rdsdbaasv1alpha1.RDSEventSubscription) string {
+	if len(eventSubscription.Spec.SubscriptionName) > 0 {
+		return eventSubscription.Spec.SubscriptionName
+	}
+	return eventSubscription.Name
+}
+
+// getEventSubscriptionErrorReason returns the reason of the errors of the SNS topic or of the event categories of the
+// spec as input errors
+func getEventSubscriptionErrorReason(err error) string {
+	var invalidTopicErr *rdstypesv2.SNSInvalidTopicFault
+	var topicNotFoundErr *rdstypesv2.SNSTopicArnNotFoundFault
+	var noAuthorizationErr *rdstypesv2.SNSNoAuthorizationFault
+	var categoryNotFoundErr *rdstypesv2.SubscriptionCategoryNotFoundFault
+	var sourceNotFoundErr *rdstypesv2.SourceNotFoundFault
+	if goerrors.As(err, &invalidTopicErr) || goerrors.As(err, &topicNotFoundErr) || goerrors.As(err, &noAuthorizationErr) ||
+		goerrors.As(err, &categoryNotFoundErr) || goerrors.As(err, &sourceNotFoundErr) {
+		return eventSubscriptionStatusReasonInputError
+	}
+	return getAWSErrorReason(err, eventSubscriptionStatusReasonBackendError)
+}
+
+// getSortedSet returns the distinct values sorted
+func getSortedSet(values []string) []string {
+	set := map[string]bool{}
+	var sorted []string
+	for _, v := range values {
+		if !set[v] {
+			set[v] = true
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// getEventSubscriptionModification returns the modification of the SNS topic, the source type, the event categories
+// and the state of the event subscription to the ones of the spec, or nil if the event subscription is in sync. The
+// event categories are only modified if the spec sets them, as the categories of an event subscription can be
+// replaced but not cleared.
+func getEventSubscriptionModification(eventSubscription *rdsdbaasv1alpha1.RDSEventSubscription,
+	subscription *rdstypesv2.EventSubscription) *rds.ModifyEventSubscriptionInput {
+	input := &rds.ModifyEventSubscriptionInput{}
+	modified := false
+	if eventSubscription.Spec.SnsTopicARN != pointer.StringDeref(subscription.SnsTopicArn, "") {
+		input.SnsTopicArn = pointer.String(eventSubscription.Spec.SnsTopicARN)
+		modified = true
+	}
+	if len(eventSubscription.Spec.SourceType) > 0 && eventSubscription.Spec.SourceType != pointer.StringDeref(subscription.SourceType, "") {
+		input.SourceType = pointer.String(eventSubscription.Spec.SourceType)
+		modified = true
+	}
+	if categories := getSortedSet(eventSubscription.Spec.EventCategories); len(categories) > 0 &&
+		!reflect.DeepEqual(categories, getSortedSet(subscription.EventCategoriesList)) {
+		input.EventCategories = categories
+		modified = true
+	}
+	if enabled := pointer.BoolDeref(eventSubscription.Spec.Enabled, true); enabled != subscription.Enabled {
+		input.Enabled = pointer.Bool(enabled)
+		modified = true
+	}
+	if !modified {
+		return nil
+	}
+	return input
+}
+
+// getEventSubscriptionSourceChanges returns the sources to add to and to remove from the event subscription
+func getEventSubscriptionSourceChanges(sourceIDs, current []string) (add, remove []string) {
+	desired := map[string]bool{}
+	for _, id := range sourceIDs {
+		desired[id] = true
+	}
+	existing := map[string]bool{}
+	for _, id := range current {
+		existing[id] = true
+		if !desired[id] {
+			remove = append(remove, id)
+		}
+	}
+	for _, id := range getSortedSet(sourceIDs) {
+		if !existing[id] {
+			add = append(add, id)
+		}
+	}
+	sort.Strings(remove)
+	return add, remove
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RDSEventSubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rdsdbaasv1alpha1.RDSEventSubscription{}).
+		Complete(r.GracefulShutdown.reconciler(r))
+}
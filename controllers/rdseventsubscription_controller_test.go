@@ -0,0 +1,110 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("RDSEventSubscriptionController", func() {
+	Context("when Event Subscription is created", func() {
+		subscriptionName := "rds-event-subscription-controller"
+		inventoryName := "rds-inventory-event-subscription-controller"
+
+		eventSubscription := &rdsdbaasv1alpha1.RDSEventSubscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      subscriptionName,
+				Namespace: testNamespace,
+			},
+			Spec: rdsdbaasv1alpha1.RDSEventSubscriptionSpec{
+				InventoryRef: dbaasv1beta1.NamespacedName{
+					Name:      inventoryName,
+					Namespace: testNamespace,
+				},
+				SnsTopicARN:     "arn:aws:sns:us-east-1:123456789012:rds-alerts",
+				SourceType:      "db-instance",
+				EventCategories: []string{"availability", "failover"},
+			},
+		}
+		BeforeEach(assertResourceCreation(eventSubscription))
+		AfterEach(assertResourceDeletion(eventSubscription))
+
+		Context("when Inventory is not created", func() {
+			It("should make Event Subscription in error status", func() {
+				es := &rdsdbaasv1alpha1.RDSEventSubscription{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      subscriptionName,
+						Namespace: testNamespace,
+					},
+				}
+				Eventually(func() bool {
+					if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(es), es); err != nil {
+						return false
+					}
+					condition := apimeta.FindStatusCondition(es.Status.Conditions, "EventSubscriptionReady")
+					if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "NotFound" {
+						return false
+					}
+					return true
+				}, timeout).Should(BeTrue())
+			})
+		})
+
+		Context("when Inventory is not ready", func() {
+			inventory := &rdsdbaasv1alpha1.RDSInventory{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      inventoryName,
+					Namespace: testNamespace,
+				},
+				Spec: dbaasv1beta1.DBaaSInventorySpec{
+					CredentialsRef: &dbaasv1beta1.LocalObjectReference{
+						Name: "credentials-ref-event-subscription-controller",
+					},
+				},
+			}
+			BeforeEach(assertResourceCreation(inventory))
+			AfterEach(assertResourceDeletion(inventory))
+
+			It("should make Event Subscription in error status", func() {
+				es := &rdsdbaasv1alpha1.RDSEventSubscription{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      subscriptionName,
+						Namespace: testNamespace,
+					},
+				}
+				Eventually(func() bool {
+					if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(es), es); err != nil {
+						return false
+					}
+					condition := apimeta.FindStatusCondition(es.Status.Conditions, "EventSubscriptionReady")
+					if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Unreachable" {
+						return false
+					}
+					return true
+				}, timeout).Should(BeTrue())
+			})
+		})
+	})
+})
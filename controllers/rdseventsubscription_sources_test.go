@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Event Subscription changes", func() {
+	It("should add the missing sources and remove the sources not in the spec", func() {
+		add, remove := getEventSubscriptionSourceChanges([]string{"db-2", "db-1", "db-2"}, []string{"db-3", "db-1"})
+		Expect(add).Should(Equal([]string{"db-2"}))
+		Expect(remove).Should(Equal([]string{"db-3"}))
+		add, remove = getEventSubscriptionSourceChanges(nil, nil)
+		Expect(add).Should(BeEmpty())
+		Expect(remove).Should(BeEmpty())
+	})
+
+	It("should only modify the event subscription out of sync with the spec", func() {
+		eventSubscription := &rdsdbaasv1alpha1.RDSEventSubscription{
+			Spec: rdsdbaasv1alpha1.RDSEventSubscriptionSpec{
+				SnsTopicARN:     "arn:aws:sns:us-east-1:123456789012:rds-alerts",
+				SourceType:      "db-instance",
+				EventCategories: []string{"failover", "availability"},
+			},
+		}
+		subscription := &rdstypesv2.EventSubscription{
+			SnsTopicArn:         pointer.String("arn:aws:sns:us-east-1:123456789012:rds-alerts"),
+			SourceType:          pointer.String("db-instance"),
+			EventCategoriesList: []string{"availability", "failover"},
+			Enabled:             true,
+		}
+		Expect(getEventSubscriptionModification(eventSubscription, subscription)).Should(BeNil())
+
+		eventSubscription.Spec.EventCategories = []string{"maintenance"}
+		eventSubscription.Spec.Enabled = pointer.Bool(false)
+		input := getEventSubscriptionModification(eventSubscription, subscription)
+		Expect(input).ShouldNot(BeNil())
+		Expect(input.SnsTopicArn).Should(BeNil())
+		Expect(input.SourceType).Should(BeNil())
+		Expect(input.EventCategories).Should(Equal([]string{"maintenance"}))
+		Expect(input.Enabled).Should(Equal(pointer.Bool(false)))
+	})
+})
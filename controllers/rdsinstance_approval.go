@@ -0,0 +1,158 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	label "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the provisioning of the Instances of the namespaces matching the label selector, e.g. env=prod, against the
+	// Inventory requires an approval
+	approvalNamespaceSelectorAnnotation = "rds.dbaas.redhat.com/approval-namespace-selector"
+	// the provisioning of the Instances of an instance class larger than the size, e.g. xlarge, against the Inventory
+	// requires an approval
+	approvalInstanceClassAboveAnnotation = "rds.dbaas.redhat.com/approval-instance-class-above"
+
+	instanceConditionPendingApproval = "PendingApproval"
+
+	instanceStatusReasonApprovalRequired = "ApprovalRequired"
+	instanceStatusReasonApproved         = "Approved"
+
+	instanceStatusMessageApprovalRequired = "Provisioning of the Instance requires an approval as %s, an approver sets its annotation %s to true"
+	instanceStatusMessageApproved         = "Provisioning of the Instance approved"
+
+	eventReasonApprovalRequired = "ApprovalRequired"
+)
+
+// the sizes of the instance classes below xlarge, the sizes Nxlarge are ranked after xlarge by N
+var instanceClassSizes = map[string]int{
+	"nano":   0,
+	"micro":  1,
+	"small":  2,
+	"medium": 3,
+	"large":  4,
+	"xlarge": 5,
+	"metal":  1000,
+}
+
+// getInstanceSizeRank returns the rank of the size of an instance class, e.g. large, 2xlarge or metal
+func getInstanceSizeRank(size string) (int, error) {
+	size = strings.ToLower(size)
+	if rank, ok := instanceClassSizes[size]; ok {
+		return rank, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge")); strings.HasSuffix(size, "xlarge") && err == nil && n > 0 {
+		return instanceClassSizes["large"] + n, nil
+	}
+	return 0, fmt.Errorf("instance size %s is invalid", size)
+}
+
+// getApprovalPolicyMatches returns the rules of the approval policy of the Inventory matched by the Instance
+func (r *RDSInstanceReconciler) getApprovalPolicyMatches(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) ([]string, error) {
+	var matches []string
+	if value, ok := inventory.Annotations[approvalNamespaceSelectorAnnotation]; ok && len(value) > 0 {
+		selector, err := label.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("value %s of annotation %s is invalid: %v", value, approvalNamespaceSelectorAnnotation, err)
+		}
+		namespace := &v1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: rdsInstance.Namespace}, namespace); err != nil {
+			return nil, err
+		}
+		if selector.Matches(label.Set(namespace.Labels)) {
+			matches = append(matches, fmt.Sprintf("namespace %s matches %s", rdsInstance.Namespace, value))
+		}
+	}
+	if value, ok := inventory.Annotations[approvalInstanceClassAboveAnnotation]; ok && len(value) > 0 {
+		threshold, err := getInstanceSizeRank(value)
+		if err != nil {
+			return nil, fmt.Errorf("value %s of annotation %s is invalid", value, approvalInstanceClassAboveAnnotation)
+		}
+		class := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningMachineType]
+		if len(class) == 0 {
+			class = defaultDBInstanceClass
+		}
+		rank, err := getInstanceSizeRank(class[strings.LastIndex(class, ".")+1:])
+		if err != nil {
+			return nil, fmt.Errorf(invalidParameterErrorTemplate, dbaasv1beta1.ProvisioningMachineType)
+		}
+		if rank > threshold {
+			matches = append(matches, fmt.Sprintf("instance class %s is larger than %s", class, value))
+		}
+	}
+	return matches, nil
+}
+
+// checkProvisioningApproval sets the PendingApproval condition of the Instance matching the approval policy of its
+// Inventory and returns whether its provisioning waits for the approval, the Instances already provisioned are not
+// gated. The approval annotation is only accepted by the webhook from the approvers.
+func (r *RDSInstanceReconciler) checkProvisioningApproval(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) (bool, string, error) {
+	if len(inventory.Annotations[approvalNamespaceSelectorAnnotation]) == 0 &&
+		len(inventory.Annotations[approvalInstanceClassAboveAnnotation]) == 0 {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionPendingApproval)
+		return false, "", nil
+	}
+	if provisioned, err := r.isInstanceProvisioned(ctx, rdsInstance, inventory); err != nil || provisioned {
+		return false, "", err
+	}
+
+	matches, err := r.getApprovalPolicyMatches(ctx, rdsInstance, inventory)
+	if err != nil {
+		return false, "", err
+	}
+	if len(matches) == 0 {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionPendingApproval)
+		return false, "", nil
+	}
+	if rdsInstance.Annotations[rdsdbaasv1alpha1.ProvisioningApprovedAnnotation] == "true" {
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionPendingApproval,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonApproved,
+			Message: instanceStatusMessageApproved,
+		})
+		return false, "", nil
+	}
+
+	message := fmt.Sprintf(instanceStatusMessageApprovalRequired, strings.Join(matches, " and "),
+		rdsdbaasv1alpha1.ProvisioningApprovedAnnotation)
+	if !apimeta.IsStatusConditionTrue(rdsInstance.Status.Conditions, instanceConditionPendingApproval) && r.Recorder != nil {
+		r.Recorder.Event(rdsInstance, v1.EventTypeNormal, eventReasonApprovalRequired, message)
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    instanceConditionPendingApproval,
+		Status:  metav1.ConditionTrue,
+		Reason:  instanceStatusReasonApprovalRequired,
+		Message: message,
+	})
+	return true, message, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance provisioning approval", func() {
+	inventory := &rdsdbaasv1alpha1.RDSInventory{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "openshift-dbaas-operator",
+		Name:        "aws",
+		Annotations: map[string]string{approvalInstanceClassAboveAnnotation: "xlarge"},
+	}}
+
+	newInstance := func(class string) *rdsdbaasv1alpha1.RDSInstance {
+		instance := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "db"}}
+		instance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{
+			dbaasv1beta1.ProvisioningMachineType: class,
+		}
+		return instance
+	}
+
+	It("should rank the instance sizes", func() {
+		for _, sizes := range [][2]string{{"micro", "large"}, {"large", "xlarge"}, {"xlarge", "2xlarge"}, {"4xlarge", "16xlarge"},
+			{"24xlarge", "metal"}} {
+			smaller, err := getInstanceSizeRank(sizes[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			larger, err := getInstanceSizeRank(sizes[1])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(smaller).Should(BeNumerically("<", larger), sizes[0])
+		}
+		_, err := getInstanceSizeRank("huge")
+		Expect(err).Should(HaveOccurred())
+		_, err = getInstanceSizeRank("0xlarge")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should match the instance classes above the threshold", func() {
+		r := &RDSInstanceReconciler{}
+		matches, err := r.getApprovalPolicyMatches(context.TODO(), newInstance("db.r6g.2xlarge"), inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(matches).Should(HaveLen(1))
+		matches, err = r.getApprovalPolicyMatches(context.TODO(), newInstance("db.r6g.xlarge"), inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(matches).Should(BeEmpty())
+		matches, err = r.getApprovalPolicyMatches(context.TODO(), newInstance(""), inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(matches).Should(BeEmpty())
+		_, err = r.getApprovalPolicyMatches(context.TODO(), newInstance("db.r6g.huge"), inventory)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should not gate the Instances without policy or already provisioned", func() {
+		r := &RDSInstanceReconciler{}
+		instance := newInstance("db.r6g.2xlarge")
+		instance.Status.Conditions = []metav1.Condition{{Type: instanceConditionPendingApproval, Status: metav1.ConditionTrue}}
+		pending, _, err := r.checkProvisioningApproval(context.TODO(), instance, &rdsdbaasv1alpha1.RDSInventory{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pending).Should(BeFalse())
+		Expect(apimeta.FindStatusCondition(instance.Status.Conditions, instanceConditionPendingApproval)).Should(BeNil())
+
+		instance.Status.InstanceID = "rhoda-postgres-1"
+		pending, _, err = r.checkProvisioningApproval(context.TODO(), instance, inventory)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pending).Should(BeFalse())
+	})
+})
@@ -0,0 +1,304 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the comma-separated keys of the branches of the Instance, e.g. the numbers of the pull requests, each branch is an
+	// Instance restored from a snapshot of the DB instance taken when the key is added, and deleted with its snapshot
+	// once the key is removed
+	instanceBranchesAnnotation = "rds.dbaas.redhat.com/branches"
+	// the template of the names of the branches of the Instance, with the name of the source Instance and the key of the
+	// branch, {{.Source}}-{{.Key}} if not set
+	instanceBranchNameTemplateAnnotation = "rds.dbaas.redhat.com/branch-name-template"
+
+	defaultBranchNameTemplate = "{{.Source}}-{{.Key}}"
+
+	// the source Instance and the key of the branches and of their snapshots
+	branchOfLabel  = "rds.dbaas.redhat.com/branch-of"
+	branchKeyLabel = "rds.dbaas.redhat.com/branch-key"
+
+	instanceConditionBranches = "Branches"
+
+	instanceStatusReasonBranchesSynced  = "Synced"
+	instanceStatusReasonBranchesPending = "Pending"
+
+	instanceStatusMessageBranchesSynced         = "Branches %s are created"
+	instanceStatusMessageBranchesSnapshotting   = "Branches %s wait for the snapshot of the DB instance"
+	instanceStatusMessageBranchesSourceNotReady = "Branches wait for the DB instance to be available"
+)
+
+// the provisioning parameters of the source Instance that are not inherited by its branches, which are restored from a
+// snapshot of the source DB instance under their own identifier
+var branchExcludedParameters = []dbaasv1beta1.ProvisioningParameterType{
+	dbaasv1beta1.ProvisioningName, cloneFrom, dbSnapshotIdentifier, s3BucketName, s3Prefix, s3IngestionRoleARN,
+	sourceEngineVersion, masterUsername, provisioningPreset, instanceTTL,
+}
+
+type branchNameData struct {
+	Source string
+	Key    string
+}
+
+// getBranchKeys returns the keys of the branches of the Instance, each key must be a DNS label
+func getBranchKeys(rdsInstance *rdsdbaasv1alpha1.RDSInstance) ([]string, error) {
+	var keys []string
+	for _, key := range strings.Split(rdsInstance.Annotations[instanceBranchesAnnotation], ",") {
+		key = strings.TrimSpace(key)
+		if len(key) == 0 {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(key); len(errs) > 0 {
+			return nil, fmt.Errorf("branch %s of annotation %s is invalid: %s", key, instanceBranchesAnnotation,
+				strings.Join(errs, ", "))
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// getBranchName returns the name of the branch of the Instance from the template of its annotation
+func getBranchName(rdsInstance *rdsdbaasv1alpha1.RDSInstance, key string) (string, error) {
+	value := rdsInstance.Annotations[instanceBranchNameTemplateAnnotation]
+	if len(value) == 0 {
+		value = defaultBranchNameTemplate
+	}
+	tmpl, err := template.New("branch").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("value %s of annotation %s is invalid: %v", value, instanceBranchNameTemplateAnnotation, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, branchNameData{Source: rdsInstance.Name, Key: key}); err != nil {
+		return "", fmt.Errorf("value %s of annotation %s is invalid: %v", value, instanceBranchNameTemplateAnnotation, err)
+	}
+	name := buf.String()
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("name %s of branch %s is invalid: %s", name, key, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// getBranchSnapshotID returns the identifier of the DB snapshot of the branch, unique in the region of the source DB
+// instance
+func getBranchSnapshotID(dbInstanceID, key string) string {
+	return fmt.Sprintf("%s-branch-%s", dbInstanceID, key)
+}
+
+// validateBranches checks the annotations of the branches of the Instance
+func validateBranches(rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	keys, err := getBranchKeys(rdsInstance)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	if isMultiAZClusterDeployment(rdsInstance) {
+		return fmt.Errorf("annotation %s is not supported for Multi-AZ DB clusters", instanceBranchesAnnotation)
+	}
+	names := map[string]bool{}
+	for _, key := range keys {
+		name, err := getBranchName(rdsInstance, key)
+		if err != nil {
+			return err
+		}
+		if names[name] || name == rdsInstance.Name {
+			return fmt.Errorf("name %s of branch %s is not unique", name, key)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// newBranchInstance returns the Instance of the branch, restored from the DB snapshot with the provisioning parameters
+// of the source Instance
+func newBranchInstance(source *rdsdbaasv1alpha1.RDSInstance, key, name, snapshotID string) *rdsdbaasv1alpha1.RDSInstance {
+	parameters := map[dbaasv1beta1.ProvisioningParameterType]string{}
+	for k, v := range source.Spec.ProvisioningParameters {
+		parameters[k] = v
+	}
+	for _, p := range branchExcludedParameters {
+		delete(parameters, p)
+	}
+	parameters[dbSnapshotIdentifier] = snapshotID
+	return &rdsdbaasv1alpha1.RDSInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      name,
+			Labels:    map[string]string{branchOfLabel: source.Name, branchKeyLabel: key},
+		},
+		Spec: dbaasv1beta1.DBaaSInstanceSpec{
+			InventoryRef:           source.Spec.InventoryRef,
+			ProvisioningParameters: parameters,
+		},
+	}
+}
+
+// syncBranches creates a snapshot of the DB instance of the Instance for each new branch, creates the Instance of the
+// branch restored from the snapshot once it is available, and deletes the branches and the snapshots whose key is
+// removed. The branches are owned by the source Instance and deleted with it.
+func (r *RDSInstanceReconciler) syncBranches(ctx context.Context, source *rdsdbaasv1alpha1.RDSInstance) error {
+	logger := log.FromContext(ctx)
+
+	keys, err := getBranchKeys(source)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 && apimeta.FindStatusCondition(source.Status.Conditions, instanceConditionBranches) == nil {
+		return nil
+	}
+
+	selector := client.MatchingLabels{branchOfLabel: source.Name}
+	keep := map[string]bool{}
+	for _, key := range keys {
+		keep[key] = true
+	}
+	branchList := &rdsdbaasv1alpha1.RDSInstanceList{}
+	if err := r.List(ctx, branchList, client.InNamespace(source.Namespace), selector); err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for i := range branchList.Items {
+		branch := &branchList.Items[i]
+		if !metav1.IsControlledBy(branch, source) {
+			continue
+		}
+		if key := branch.Labels[branchKeyLabel]; !keep[key] {
+			if err := r.Delete(ctx, branch); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			logger.Info("Branch of Instance deleted", "branch", branch.Name, "key", key)
+			continue
+		}
+		existing[branch.Labels[branchKeyLabel]] = true
+	}
+	snapshotList := &rdsdbaasv1alpha1.RDSSnapshotList{}
+	if err := r.List(ctx, snapshotList, client.InNamespace(source.Namespace), selector); err != nil {
+		return err
+	}
+	for i := range snapshotList.Items {
+		snapshot := &snapshotList.Items[i]
+		if metav1.IsControlledBy(snapshot, source) && !keep[snapshot.Labels[branchKeyLabel]] {
+			if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		apimeta.RemoveStatusCondition(&source.Status.Conditions, instanceConditionBranches)
+		return nil
+	}
+	if len(source.Status.InstanceID) == 0 || source.Status.Phase != dbaasv1beta1.InstancePhaseReady {
+		apimeta.SetStatusCondition(&source.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionBranches,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonBranchesPending,
+			Message: instanceStatusMessageBranchesSourceNotReady,
+		})
+		return nil
+	}
+
+	var created, snapshotting []string
+	for _, key := range keys {
+		if existing[key] {
+			created = append(created, key)
+			continue
+		}
+		name, err := getBranchName(source, key)
+		if err != nil {
+			return err
+		}
+		snapshotID := getBranchSnapshotID(source.Status.InstanceID, key)
+		snapshot := &rdsdbaasv1alpha1.RDSSnapshot{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: source.Namespace, Name: name}, snapshot); errors.IsNotFound(err) {
+			snapshot = &rdsdbaasv1alpha1.RDSSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: source.Namespace,
+					Name:      name,
+					Labels:    map[string]string{branchOfLabel: source.Name, branchKeyLabel: key},
+				},
+				Spec: rdsdbaasv1alpha1.RDSSnapshotSpec{
+					InventoryRef: source.Spec.InventoryRef,
+					DBInstanceID: source.Status.InstanceID,
+					SnapshotID:   snapshotID,
+				},
+			}
+			if err := ctrl.SetControllerReference(source, snapshot, r.Scheme); err != nil {
+				return err
+			}
+			if err := r.Create(ctx, snapshot); err != nil {
+				return err
+			}
+			logger.Info("Snapshot of branch of Instance created", "branch", name, "key", key)
+			snapshotting = append(snapshotting, key)
+			continue
+		} else if err != nil {
+			return err
+		} else if !metav1.IsControlledBy(snapshot, source) {
+			return fmt.Errorf("snapshot %s of branch %s is not owned by the Instance", name, key)
+		}
+		if !apimeta.IsStatusConditionTrue(snapshot.Status.Conditions, snapshotConditionReady) {
+			snapshotting = append(snapshotting, key)
+			continue
+		}
+
+		branch := newBranchInstance(source, key, name, snapshot.Spec.SnapshotID)
+		if err := ctrl.SetControllerReference(source, branch, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, branch); err != nil {
+			if errors.IsAlreadyExists(err) {
+				return fmt.Errorf("branch %s of Instance can not be created, Instance %s already exists", key, name)
+			}
+			return err
+		}
+		logger.Info("Branch of Instance created", "branch", name, "key", key)
+		created = append(created, key)
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionBranches,
+		Status:  metav1.ConditionTrue,
+		Reason:  instanceStatusReasonBranchesSynced,
+		Message: fmt.Sprintf(instanceStatusMessageBranchesSynced, strings.Join(created, ",")),
+	}
+	if len(snapshotting) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = instanceStatusReasonBranchesPending
+		condition.Message = fmt.Sprintf(instanceStatusMessageBranchesSnapshotting, strings.Join(snapshotting, ","))
+	}
+	apimeta.SetStatusCondition(&source.Status.Conditions, condition)
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance branches", func() {
+	newSource := func(annotations map[string]string) *rdsdbaasv1alpha1.RDSInstance {
+		source := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "previews", Name: "app-db", Annotations: annotations,
+		}}
+		source.Spec.InventoryRef = dbaasv1beta1.NamespacedName{Namespace: "openshift-dbaas-operator", Name: "aws"}
+		source.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{
+			dbaasv1beta1.ProvisioningName:         "app-db",
+			dbaasv1beta1.ProvisioningDatabaseType: "postgres",
+			dbaasv1beta1.ProvisioningMachineType:  "db.t3.medium",
+			instanceTTL:                           "720h",
+		}
+		return source
+	}
+
+	It("should parse the keys of the branches", func() {
+		keys, err := getBranchKeys(newSource(map[string]string{instanceBranchesAnnotation: "pr-15, pr-12,,"}))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).Should(Equal([]string{"pr-12", "pr-15"}))
+		_, err = getBranchKeys(newSource(map[string]string{instanceBranchesAnnotation: "PR_12"}))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should name the branches from the template", func() {
+		name, err := getBranchName(newSource(nil), "pr-12")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(name).Should(Equal("app-db-pr-12"))
+		name, err = getBranchName(newSource(map[string]string{instanceBranchNameTemplateAnnotation: "preview-{{.Key}}"}), "pr-12")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(name).Should(Equal("preview-pr-12"))
+		_, err = getBranchName(newSource(map[string]string{instanceBranchNameTemplateAnnotation: "{{.Number}}"}), "pr-12")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should reject the branches with the same name", func() {
+		Expect(validateBranches(newSource(map[string]string{
+			instanceBranchesAnnotation:           "pr-12,pr-15",
+			instanceBranchNameTemplateAnnotation: "{{.Source}}-preview",
+		}))).ShouldNot(Succeed())
+		Expect(validateBranches(newSource(map[string]string{instanceBranchesAnnotation: "pr-12,pr-15"}))).Should(Succeed())
+	})
+
+	It("should restore the branch from its snapshot with the parameters of the source", func() {
+		source := newSource(nil)
+		branch := newBranchInstance(source, "pr-12", "app-db-pr-12", getBranchSnapshotID("app-db", "pr-12"))
+		Expect(branch.Namespace).Should(Equal("previews"))
+		Expect(branch.Labels).Should(Equal(map[string]string{branchOfLabel: "app-db", branchKeyLabel: "pr-12"}))
+		Expect(branch.Spec.InventoryRef).Should(Equal(source.Spec.InventoryRef))
+		Expect(branch.Spec.ProvisioningParameters).Should(Equal(map[dbaasv1beta1.ProvisioningParameterType]string{
+			dbaasv1beta1.ProvisioningDatabaseType: "postgres",
+			dbaasv1beta1.ProvisioningMachineType:  "db.t3.medium",
+			dbSnapshotIdentifier:                  "app-db-branch-pr-12",
+		}))
+		Expect(source.Spec.ProvisioningParameters).Should(HaveKey(dbaasv1beta1.ProvisioningName))
+	})
+})
@@ -0,0 +1,258 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// clone the DB instance of the same Inventory with the identifier at its latest restorable time
+	cloneFrom = "CloneFrom"
+
+	instanceConditionCloned = "Cloned"
+
+	instanceStatusMessageCloning    = "Cloning DB Instance from DB instance %s"
+	instanceStatusMessageCloned     = "DB Instance cloned from DB instance %s"
+	instanceStatusMessageCloneError = "Failed to clone DB Instance from DB instance %s"
+)
+
+// validateCloneFromParameters checks that the provisioning parameters of a clone do not set what is inherited from the
+// source DB instance or another source of the data
+func validateCloneFromParameters(rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	for _, p := range []dbaasv1beta1.ProvisioningParameterType{dbSnapshotIdentifier, s3BucketName, engineVersion, masterUsername} {
+		if _, ok := rdsInstance.Spec.ProvisioningParameters[p]; ok {
+			return fmt.Errorf("parameters %s and %s can not be set together", cloneFrom, p)
+		}
+	}
+	return nil
+}
+
+// isInventoriedDBInstance returns true if the DB instance is a DB service of type instance of the Inventory
+func isInventoriedDBInstance(inventory *rdsdbaasv1alpha1.RDSInventory, dbInstanceID string) bool {
+	for _, service := range inventory.Status.DatabaseServices {
+		if service.ServiceID == dbInstanceID && service.ServiceType != nil &&
+			*service.ServiceType == dbaasv1beta1.DatabaseServiceType(instanceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCloneSourceSpec sets the engine, the engine version, the master username, the database name and the minimum
+// allocated storage of a clone from its source DB instance, which are inherited when it is restored to a point in time
+func (r *RDSInstanceReconciler) setCloneSourceSpec(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	if e := validateCloneFromParameters(rdsInstance); e != nil {
+		return e
+	}
+	if !dbInstance.CreationTimestamp.IsZero() && dbInstance.Spec.Engine != nil {
+		// kept from the created DB Instance, the source is no longer needed once cloned
+		return nil
+	}
+
+	sourceID := rdsInstance.Spec.ProvisioningParameters[cloneFrom]
+	if !isInventoriedDBInstance(inventory, sourceID) {
+		return fmt.Errorf("DB instance %s to clone is not found in the Inventory", sourceID)
+	}
+
+	describeDBInstances := r.GetDescribeDBInstancesAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	output, e := describeDBInstances.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: pointer.String(sourceID),
+	})
+	if e != nil {
+		return fmt.Errorf("failed to describe DB instance %s to clone: %w", sourceID, e)
+	}
+	if output == nil || len(output.DBInstances) == 0 {
+		return fmt.Errorf("DB instance %s to clone is not found", sourceID)
+	}
+	source := output.DBInstances[0]
+	if source.LatestRestorableTime == nil {
+		return fmt.Errorf("DB instance %s to clone has no restorable time, its automated backups may be disabled", sourceID)
+	}
+	if engine, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningDatabaseType]; ok &&
+		engine != pointer.StringDeref(source.Engine, "") {
+		return fmt.Errorf("engine %s is not the engine of DB instance %s to clone", engine, sourceID)
+	}
+
+	dbInstance.Spec.Engine = source.Engine
+	dbInstance.Spec.EngineVersion = source.EngineVersion
+	dbInstance.Spec.MasterUsername = source.MasterUsername
+	dbInstance.Spec.DBName = source.DBName
+	if storage, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningStorageGib]; !ok {
+		dbInstance.Spec.AllocatedStorage = pointer.Int64(int64(source.AllocatedStorage))
+	} else if i, e := strconv.ParseInt(storage, 10, 64); e == nil && i < int64(source.AllocatedStorage) {
+		return fmt.Errorf("allocated storage %d is less than the allocated storage %d of DB instance %s to clone",
+			i, source.AllocatedStorage, sourceID)
+	}
+	return nil
+}
+
+// restoreDBInstanceToPointInTime creates the AWS instance from the latest restorable time of the source DB instance
+// of the provisioning parameters before the DB Instance is created
+func (r *RDSInstanceReconciler) restoreDBInstanceToPointInTime(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, secret *v1.Secret) error {
+	logger := log.FromContext(ctx)
+
+	sourceID, ok := rdsInstance.Spec.ProvisioningParameters[cloneFrom]
+	if !ok {
+		return nil
+	}
+
+	input := &rds.RestoreDBInstanceToPointInTimeInput{
+		SourceDBInstanceIdentifier: pointer.String(sourceID),
+		TargetDBInstanceIdentifier: dbInstance.Spec.DBInstanceIdentifier,
+		UseLatestRestorableTime:    true,
+		DBInstanceClass:            dbInstance.Spec.DBInstanceClass,
+		AvailabilityZone:           dbInstance.Spec.AvailabilityZone,
+		DBSubnetGroupName:          dbInstance.Spec.DBSubnetGroupName,
+		PubliclyAccessible:         dbInstance.Spec.PubliclyAccessible,
+		StorageType:                dbInstance.Spec.StorageType,
+		LicenseModel:               dbInstance.Spec.LicenseModel,
+		DBParameterGroupName:       dbInstance.Spec.DBParameterGroupName,
+		OptionGroupName:            dbInstance.Spec.OptionGroupName,
+		DeletionProtection:         dbInstance.Spec.DeletionProtection,
+		CopyTagsToSnapshot:         dbInstance.Spec.CopyTagsToSnapshot,
+	}
+	if dbInstance.Spec.IOPS != nil {
+		input.Iops = pointer.Int32(int32(*dbInstance.Spec.IOPS))
+	}
+	if dbInstance.Spec.MaxAllocatedStorage != nil {
+		input.MaxAllocatedStorage = pointer.Int32(int32(*dbInstance.Spec.MaxAllocatedStorage))
+	}
+	for _, sg := range dbInstance.Spec.VPCSecurityGroupIDs {
+		input.VpcSecurityGroupIds = append(input.VpcSecurityGroupIds, *sg)
+	}
+	for _, t := range dbInstance.Spec.Tags {
+		input.Tags = append(input.Tags, rdstypesv2.Tag{Key: t.Key, Value: t.Value})
+	}
+
+	restoreDBInstanceToPointInTime := r.GetRestoreDBInstanceToPointInTimeAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	if _, e := restoreDBInstanceToPointInTime.RestoreDBInstanceToPointInTime(ctx, input); e != nil {
+		var existsErr *rdstypesv2.DBInstanceAlreadyExistsFault
+		if goerrors.As(e, &existsErr) {
+			// cloned by a previous reconciliation before the DB Instance was created
+			return nil
+		}
+		message := fmt.Sprintf(instanceStatusMessageCloneError, sourceID)
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionCloned,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonRestoreFailed,
+			Message: fmt.Sprintf("%s: %s", message, e.Error()),
+		})
+		return fmt.Errorf("%s: %w", message, e)
+	}
+	logger.Info("DB Instance clone started", "DB Instance Identifier", *dbInstance.Spec.DBInstanceIdentifier,
+		"Source DB Instance Identifier", sourceID)
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    instanceConditionCloned,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonRestoring,
+		Message: fmt.Sprintf(instanceStatusMessageCloning, sourceID),
+	})
+	return nil
+}
+
+// syncClonedCondition reflects the progress of the clone by the status of the AWS instance, the master password of
+// the source DB instance is replaced by the one of the Instance once the clone is available
+func (r *RDSInstanceReconciler) syncClonedCondition(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	logger := log.FromContext(ctx)
+
+	sourceID, ok := rdsInstance.Spec.ProvisioningParameters[cloneFrom]
+	if !ok {
+		return nil
+	}
+	if condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionCloned); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionCloned,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonRestoring,
+		Message: fmt.Sprintf(instanceStatusMessageCloning, sourceID),
+	}
+	status := pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "")
+	statusMessage := getDBInstanceStatusMessage(status)
+	if len(statusMessage) == 0 {
+		statusMessage = status
+	}
+	switch status {
+	case "":
+	case "available":
+		if e := r.resetClonedMasterPassword(ctx, dbInstance, inventory); e != nil {
+			return e
+		}
+		logger.Info("Master password of cloned DB Instance reset")
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonRestored
+		condition.Message = fmt.Sprintf(instanceStatusMessageCloned, sourceID)
+	case "failed", "incompatible-restore", "incompatible-parameters", "incompatible-network", "inaccessible-encryption-credentials":
+		condition.Reason = instanceStatusReasonRestoreFailed
+		condition.Message = fmt.Sprintf("%s: %s", fmt.Sprintf(instanceStatusMessageCloneError, sourceID), statusMessage)
+	default:
+		condition.Message = fmt.Sprintf("%s: %s", fmt.Sprintf(instanceStatusMessageCloning, sourceID), statusMessage)
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+	return nil
+}
+
+// resetClonedMasterPassword sets the master password of the credentials of the DB Instance to the cloned AWS instance
+func (r *RDSInstanceReconciler) resetClonedMasterPassword(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	if dbInstance.Spec.DBInstanceIdentifier == nil || dbInstance.Spec.MasterUserPassword == nil {
+		return fmt.Errorf("master credentials of cloned DB Instance are not set")
+	}
+	passwordSecret := &v1.Secret{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: dbInstance.Spec.MasterUserPassword.Namespace,
+		Name: dbInstance.Spec.MasterUserPassword.Name}, passwordSecret); e != nil {
+		return e
+	}
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		return e
+	}
+	modifyDBInstance := r.GetModifyDBInstanceAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	_, e := modifyDBInstance.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: dbInstance.Spec.DBInstanceIdentifier,
+		MasterUserPassword:   pointer.String(string(passwordSecret.Data[dbInstance.Spec.MasterUserPassword.Key])),
+		ApplyImmediately:     true,
+	})
+	return e
+}
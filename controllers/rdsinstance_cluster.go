@@ -0,0 +1,166 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the deployment of the provisioned AWS instance, a Multi-AZ DB cluster (multi-az-cluster) is provisioned instead
+	// of a DB instance with a writer and two readable standby DB instances of the DB cluster instance class
+	deploymentOption = "DeploymentOption"
+
+	ackDBClusterFinalizer = "finalizers.rds.services.k8s.aws/DBCluster"
+
+	// the Multi-AZ DB clusters require provisioned IOPS storage
+	defaultMultiAZClusterStorageType = "io1"
+	defaultMultiAZClusterIOPS        = 1000
+)
+
+// the provisioning parameters of DB instances that do not apply to Multi-AZ DB clusters
+var multiAZClusterUnsupportedParameters = []dbaasv1beta1.ProvisioningParameterType{
+	cloneFrom, s3BucketName, dbSnapshotIdentifier, maxAllocatedStorage, licenseModel, rdsParameterGroup, rdsOptionGroup,
+	domain, domainIAMRoleName, logRetentionDays, rdsdbaasv1alpha1.ExtraParameters,
+}
+
+// isMultiAZClusterDeployment returns whether a Multi-AZ DB cluster is provisioned for the Instance
+func isMultiAZClusterDeployment(rdsInstance *rdsdbaasv1alpha1.RDSInstance) bool {
+	return rdsInstance.Spec.ProvisioningParameters[deploymentOption] == deploymentTypeMultiAZCluster
+}
+
+// validateDeploymentOption returns an error if the deployment option of the Instance is unknown, or if the
+// provisioning parameters are not supported by the deployment
+func validateDeploymentOption(rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	option, ok := rdsInstance.Spec.ProvisioningParameters[deploymentOption]
+	if !ok || option == deploymentTypeStandard {
+		return nil
+	}
+	if option != deploymentTypeMultiAZCluster {
+		return fmt.Errorf(invalidParameterErrorTemplate, deploymentOption)
+	}
+	switch engine := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningDatabaseType]; engine {
+	case postgres, mysql, "":
+	default:
+		return fmt.Errorf("engine %s does not support Multi-AZ DB clusters", engine)
+	}
+	for _, p := range multiAZClusterUnsupportedParameters {
+		if _, ok := rdsInstance.Spec.ProvisioningParameters[p]; ok {
+			return fmt.Errorf("parameter %s is not supported for Multi-AZ DB clusters", p)
+		}
+	}
+	return nil
+}
+
+// setMultiAZDBClusterSpec sets the spec of the DB Cluster of a Multi-AZ DB cluster from the provisioning parameters,
+// the parameters shared with the DB instances are resolved as for a DB Instance of the same name
+func (r *RDSInstanceReconciler) setMultiAZDBClusterSpec(ctx context.Context, dbCluster *rdsv1alpha1.DBCluster,
+	existingDBCluster *rdsv1alpha1.DBCluster, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	if e := validateDeploymentOption(rdsInstance); e != nil {
+		return e
+	}
+
+	dbInstance := &rdsv1alpha1.DBInstance{}
+	dbInstance.Name = dbCluster.Name
+	dbInstance.Namespace = dbCluster.Namespace
+	if existingDBCluster != nil {
+		// the fields that are only set when the DB Cluster is created are kept
+		dbInstance.CreationTimestamp = existingDBCluster.CreationTimestamp
+		dbInstance.Spec.DBInstanceIdentifier = existingDBCluster.Spec.DBClusterIdentifier
+		dbInstance.Spec.MasterUsername = existingDBCluster.Spec.MasterUsername
+		dbInstance.Spec.DBSubnetGroupName = existingDBCluster.Spec.DBSubnetGroupName
+	}
+	if e := r.setDBInstanceSpec(ctx, dbInstance, rdsInstance, inventory, secret); e != nil {
+		return e
+	}
+
+	dbCluster.Spec.DBClusterIdentifier = dbInstance.Spec.DBInstanceIdentifier
+	dbCluster.Spec.Engine = dbInstance.Spec.Engine
+	dbCluster.Spec.EngineVersion = dbInstance.Spec.EngineVersion
+	dbCluster.Spec.DBClusterInstanceClass = dbInstance.Spec.DBInstanceClass
+	dbCluster.Spec.AllocatedStorage = dbInstance.Spec.AllocatedStorage
+	dbCluster.Spec.StorageType = dbInstance.Spec.StorageType
+	dbCluster.Spec.IOPS = dbInstance.Spec.IOPS
+	if dbCluster.Spec.StorageType == nil {
+		dbCluster.Spec.StorageType = pointer.String(defaultMultiAZClusterStorageType)
+	}
+	if *dbCluster.Spec.StorageType == defaultMultiAZClusterStorageType && dbCluster.Spec.IOPS == nil {
+		dbCluster.Spec.IOPS = pointer.Int64(defaultMultiAZClusterIOPS)
+	}
+	dbCluster.Spec.DBSubnetGroupName = dbInstance.Spec.DBSubnetGroupName
+	dbCluster.Spec.VPCSecurityGroupIDs = dbInstance.Spec.VPCSecurityGroupIDs
+	dbCluster.Spec.PubliclyAccessible = dbInstance.Spec.PubliclyAccessible
+	dbCluster.Spec.DeletionProtection = dbInstance.Spec.DeletionProtection
+	dbCluster.Spec.CopyTagsToSnapshot = dbInstance.Spec.CopyTagsToSnapshot
+	dbCluster.Spec.MasterUsername = dbInstance.Spec.MasterUsername
+	dbCluster.Spec.MasterUserPassword = dbInstance.Spec.MasterUserPassword
+	dbCluster.Spec.DatabaseName = dbInstance.Spec.DBName
+	dbCluster.Spec.Tags = dbInstance.Spec.Tags
+	return nil
+}
+
+// deleteMultiAZDBCluster deletes the DB Cluster of the Instance, the AWS cluster is kept if it is retained. It returns
+// true while the DB Cluster still exists.
+func (r *RDSInstanceReconciler) deleteMultiAZDBCluster(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	retain bool) (bool, error) {
+	dbCluster := &rdsv1alpha1.DBCluster{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Spec.InventoryRef.Namespace, Name: rdsInstance.Name}, dbCluster); e != nil {
+		if errors.IsNotFound(e) {
+			return false, nil
+		}
+		return false, e
+	}
+	if retain && controllerutil.ContainsFinalizer(dbCluster, ackDBClusterFinalizer) {
+		// the RDS controller does not delete the AWS cluster without its finalizer
+		if e := updateObject(ctx, r.Client, dbCluster, func() error {
+			controllerutil.RemoveFinalizer(dbCluster, ackDBClusterFinalizer)
+			return nil
+		}); e != nil {
+			return true, e
+		}
+	} else if !retain && pointer.BoolDeref(dbCluster.Spec.DeletionProtection, false) {
+		if e := updateObject(ctx, r.Client, dbCluster, func() error {
+			dbCluster.Spec.DeletionProtection = pointer.Bool(false)
+			return nil
+		}); e != nil {
+			return true, e
+		}
+		if r.Recorder != nil {
+			r.Recorder.Event(rdsInstance, v1.EventTypeNormal, eventReasonDeletionProtectionLifted,
+				"Deletion protection of the DB cluster lifted to delete it")
+		}
+		return true, nil
+	}
+	if dbCluster.DeletionTimestamp.IsZero() {
+		if e := r.Delete(ctx, dbCluster); e != nil && !errors.IsNotFound(e) {
+			return true, e
+		}
+	}
+	return true, nil
+}
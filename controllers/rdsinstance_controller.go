@@ -18,19 +18,24 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -40,8 +45,14 @@ import (
 
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersec2 "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/ec2"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	controllerssecretsmanager "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	ophandler "github.com/operator-framework/operator-lib/handler"
 )
 
@@ -61,6 +72,37 @@ const (
 	publiclyAccessible  = "PubliclyAccessible"
 	vpcSecurityGroupIDs = "VPCSecurityGroupIDs"
 	licenseModel        = "LicenseModel"
+	copyTagsToSnapshot  = "CopyTagsToSnapshot"
+
+	// the deletion protected AWS instance of a deleted Instance is only deleted if permitted by the deletion protection policy
+	deletionProtection = "DeletionProtection"
+
+	// restore from the identifier of a DB snapshot or the ARN of a DB snapshot shared by another AWS account
+	dbSnapshotIdentifier = "DBSnapshotIdentifier"
+
+	// use the DB parameter group of an RDSParameterGroup of the same Inventory in the namespace of the Instance
+	rdsParameterGroup = "RDSParameterGroup"
+
+	// use the DB option group of an RDSOptionGroup of the same Inventory and engine version in the namespace of the Instance
+	rdsOptionGroup = "RDSOptionGroup"
+
+	// restore a MySQL instance from the Percona XtraBackup files in an Amazon S3 bucket
+	s3BucketName        = "S3BucketName"
+	s3Prefix            = "S3Prefix"
+	s3IngestionRoleARN  = "S3IngestionRoleARN"
+	sourceEngineVersion = "SourceEngineVersion"
+	s3SourceEngine      = "mysql"
+
+	// select the DB subnet group by name prefix or by tags in the format of key1=value1,key2=value2
+	dbSubnetGroupNamePrefix = "DBSubnetGroupNamePrefix"
+	dbSubnetGroupSelector   = "DBSubnetGroupSelector"
+
+	// the AWS instance is deleted (delete) or kept (retain) if the Instance is deleted with its namespace, the copies
+	// of the credentials Secret in the other namespaces are deleted or kept if the Connection is deleted with its namespace
+	namespaceDeletionPolicyAnnotation = "rds.dbaas.redhat.com/namespace-deletion-policy"
+	namespaceDeletionPolicyDelete     = "delete"
+	namespaceDeletionPolicyRetain     = "retain"
+	ackDBInstanceFinalizer            = "finalizers.rds.services.k8s.aws/DBInstance"
 
 	defaultDBInstanceClass    = "db.t3.micro"
 	defaultAllocatedStorage   = 20
@@ -68,7 +110,8 @@ const (
 	defaultAvailabilityZone   = "us-east-1a"
 	defaultLicenseModel       = "license-included"
 
-	instanceConditionReady = "ProvisionReady"
+	instanceConditionReady          = "ProvisionReady"
+	instanceConditionRestoredFromS3 = "RestoredFromS3"
 
 	instanceStatusReasonReady        = "Ready"
 	instanceStatusReasonCreating     = "Creating"
@@ -79,20 +122,31 @@ const (
 	instanceStatusReasonBackendError = "BackendError"
 	instanceStatusReasonNotFound     = "NotFound"
 	instanceStatusReasonUnreachable  = "Unreachable"
+	instanceStatusReasonStopped      = "Stopped"
 
 	instanceStatusReasonDBInstance = "DBInstance"
 
-	instanceStatusMessageUpdateError         = "Failed to update Instance"
-	instanceStatusMessageCreating            = "Creating Instance"
-	instanceStatusMessageUpdating            = "Updating Instance"
-	instanceStatusMessageDeleting            = "Deleting Instance"
-	instanceStatusMessageError               = "Instance with error"
-	instanceStatusMessageCreateOrUpdateError = "Failed to create or update DB Instance"
-	instanceStatusMessageGetError            = "Failed to get DB Instance"
-	instanceStatusMessageDeleteError         = "Failed to delete DB Instance"
-	instanceStatusMessageInventoryNotFound   = "Inventory not found"
-	instanceStatusMessageInventoryNotReady   = "Inventory not ready"
-	instanceStatusMessageGetInventoryError   = "Failed to get Inventory"
+	instanceStatusReasonRestoring     = "Restoring"
+	instanceStatusReasonRestored      = "Restored"
+	instanceStatusReasonRestoreFailed = "RestoreFailed"
+
+	instanceStatusMessageUpdateError           = "Failed to update Instance"
+	instanceStatusMessageCreating              = "Creating Instance"
+	instanceStatusMessageUpdating              = "Updating Instance"
+	instanceStatusMessageDeleting              = "Deleting Instance"
+	instanceStatusMessageError                 = "Instance with error"
+	instanceStatusMessageStopped               = "Instance stopped"
+	instanceStatusMessageCreateOrUpdateError   = "Failed to create or update DB Instance"
+	instanceStatusMessageGetError              = "Failed to get DB Instance"
+	instanceStatusMessageDeleteError           = "Failed to delete DB Instance"
+	instanceStatusMessageStoreCredentialsError = "Failed to store master credentials of DB Instance"
+	instanceStatusMessageInventoryNotFound     = "Inventory not found"
+	instanceStatusMessageInventoryNotReady     = "Inventory not ready"
+	instanceStatusMessageBranchesError         = "Failed to sync branches of Instance"
+	instanceStatusMessageGetInventoryError     = "Failed to get Inventory"
+	instanceStatusMessageRestoring             = "Restoring DB Instance from Amazon S3"
+	instanceStatusMessageRestored              = "DB Instance restored from Amazon S3"
+	instanceStatusMessageRestoreError          = "Failed to restore DB Instance from Amazon S3"
 
 	requiredParameterErrorTemplate = "required parameter %s is missing"
 	invalidParameterErrorTemplate  = "value of parameter %s is invalid"
@@ -101,13 +155,64 @@ const (
 // RDSInstanceReconciler reconciles a RDSInstance object
 type RDSInstanceReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme                        *runtime.Scheme
+	GetDescribeDBSubnetGroupsAPI  func(accessKey, secretKey, region string) controllersrds.DescribeDBSubnetGroupsAPI
+	GetDescribeSecurityGroupsAPI  func(accessKey, secretKey, region string) controllersec2.DescribeSecurityGroupsAPI
+	GetDescribeSubnetsAPI         func(accessKey, secretKey, region string) controllersec2.DescribeSubnetsAPI
+	GetCreateSecretAPI            func(accessKey, secretKey, region string) controllerssecretsmanager.CreateSecretAPI
+	GetPutSecretValueAPI          func(accessKey, secretKey, region string) controllerssecretsmanager.PutSecretValueAPI
+	GetDeleteSecretAPI            func(accessKey, secretKey, region string) controllerssecretsmanager.DeleteSecretAPI
+	GetRestoreDBInstanceFromS3API func(accessKey, secretKey, region string) controllersrds.RestoreDBInstanceFromS3API
+	// the clones are restored to the latest restorable time of their source, and their master password is reset
+	GetRestoreDBInstanceToPointInTimeAPI func(accessKey, secretKey, region string) controllersrds.RestoreDBInstanceToPointInTimeAPI
+	GetModifyDBInstanceAPI               func(accessKey, secretKey, region string) controllersrds.ModifyDBInstanceAPI
+	// the identifiers generated for the DB instances are checked against the AWS DB instances of the region if set
+	GetDescribeDBInstancesAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBInstancesAPI
+	// the pending maintenance actions recommended for the DB instances are reported in the conditions of the Instances if set
+	GetDescribePendingMaintenanceActionsAPI func(accessKey, secretKey, region string) controllersrds.DescribePendingMaintenanceActionsAPI
+	EnableExtraParameters                   bool
+	ExtraParametersAllowList                []string
+	Recorder                                record.EventRecorder
+	// StorageFullRemediationPercent is the default percentage by which the allocated storage of a storage-full DB instance
+	// is increased, 0 disables the remediation unless it is enabled by the annotation of the Instance
+	StorageFullRemediationPercent int64
+	// the strategy and the prefix of the identifiers generated for the Instances without the Name provisioning
+	// parameter, the uuid strategy and the rhoda prefix are used if not set
+	DBInstanceIdentifierStrategy string
+	DBInstanceIdentifierPrefix   string
+	// the DB instances are not modified during the freeze windows of the operator and of their Inventory
+	FreezeWindows []FreezeWindow
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the deletion protected AWS instances of the deleted Instances are retained unless the policy is delete,
+	// it is overridden by the annotation of the Instance
+	DeletionProtectionPolicy string
+	// the engine versions of the DB instances are checked against their status in AWS and the end of life of their
+	// major version by engine:major version if set, the upgrades of the deprecated versions are proposed if enabled
+	GetDescribeDBEngineVersionsAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBEngineVersionsAPI
+	EngineVersionEndOfLife         map[string]time.Time
+	EngineVersionEndOfLifeWarning  time.Duration
+	ProposeEngineUpgrades          bool
+	// the monthly prices per GiB of the storage types, the saving of the conversion of the gp2 storage of the DB
+	// instances to gp3 is estimated if set
+	StoragePrices map[string]float64
+	// APIReader reads the provisioning presets ConfigMaps that are not cached, the client is used if not set
+	APIReader client.Reader
+
+	recommendationsSyncTimes sync.Map
+	engineVersions           sync.Map
 }
 
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsinstances,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsinstances/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsinstances/finalizers,verbs=update
-//+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbinstances,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbinstances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbclusters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbsubnetgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -116,12 +221,18 @@ type RDSInstanceReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
+	ctx = withApplyRecorder(ctx, r.Recorder)
 
 	var inventory rdsdbaasv1alpha1.RDSInventory
 	var instance rdsdbaasv1alpha1.RDSInstance
 
 	var provisionStatus, provisionStatusReason, provisionStatusMessage string
 	var phase dbaasv1beta1.DBaasInstancePhase
+	var frozenUntil time.Time
+	var freezeWindow string
+	var deferredModifications []string
+	var maintenanceWindowStart time.Time
+	var recommendationsSyncAt time.Time
 
 	returnUpdating := func() {
 		result = ctrl.Result{Requeue: true}
@@ -181,7 +292,7 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		} else if len(instance.Status.Phase) == 0 {
 			instance.Status.Phase = dbaasv1beta1.InstancePhaseUnknown
 		}
-		if e := r.Status().Update(ctx, &instance); e != nil {
+		if e := updateStatus(ctx, r.Client, &instance); e != nil {
 			if errors.IsConflict(e) {
 				logger.Info("Instance modified, retry reconciling")
 				result = ctrl.Result{Requeue: true}
@@ -198,8 +309,10 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if instance.ObjectMeta.DeletionTimestamp.IsZero() {
 			if !controllerutil.ContainsFinalizer(&instance, instanceFinalizer) {
 				phase = dbaasv1beta1.InstancePhasePending
-				controllerutil.AddFinalizer(&instance, instanceFinalizer)
-				if e := r.Update(ctx, &instance); e != nil {
+				if e := updateObject(ctx, r.Client, &instance, func() error {
+					controllerutil.AddFinalizer(&instance, instanceFinalizer)
+					return nil
+				}); e != nil {
 					if errors.IsConflict(e) {
 						logger.Info("Instance modified, retry reconciling")
 						returnUpdating()
@@ -216,25 +329,122 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		} else {
 			if controllerutil.ContainsFinalizer(&instance, instanceFinalizer) {
 				phase = dbaasv1beta1.InstancePhaseDeleting
+				namespaceTerminating, e := isNamespaceTerminating(ctx, r.Client, instance.Namespace)
+				if e != nil {
+					logger.Error(e, "Failed to get namespace of Instance")
+					returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
+					return true
+				}
+				retain := namespaceTerminating && instance.Annotations[namespaceDeletionPolicyAnnotation] == namespaceDeletionPolicyRetain
+				// the AWS instance handed off to IaC tooling is kept
+				retain = retain || isExternallyManaged(&instance)
 				dbInstance := &rdsv1alpha1.DBInstance{}
-				if e := r.Get(ctx, client.ObjectKey{Namespace: instance.Spec.InventoryRef.Namespace, Name: instance.Name}, dbInstance); e != nil {
-					if !errors.IsNotFound(e) {
-						logger.Error(e, "Failed to get DB Instance status")
-						returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
+				e = r.Get(ctx, client.ObjectKey{Namespace: instance.Spec.InventoryRef.Namespace, Name: instance.Name}, dbInstance)
+				if e != nil && !errors.IsNotFound(e) {
+					logger.Error(e, "Failed to get DB Instance status")
+					returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
+					return true
+				}
+				found := e == nil
+				if !retain && isDeletionProtected(&instance, dbInstance) {
+					policy, e := r.getDeletionProtectionPolicy(&instance)
+					if e != nil {
+						logger.Error(e, "Failed to get deletion protection policy of Instance")
+						returnError(e, instanceStatusReasonInputError, e.Error())
 						return true
 					}
-				} else {
-					if e := r.Delete(ctx, dbInstance); e != nil {
-						logger.Error(e, "Failed to delete DB Instance")
+					if policy == DeletionProtectionPolicyRetain {
+						retain = true
+					} else if found && dbInstance.DeletionTimestamp.IsZero() {
+						if lifted, e := r.liftDeletionProtection(ctx, &instance, dbInstance); e != nil {
+							if errors.IsConflict(e) {
+								logger.Info("DB Instance modified, retry reconciling")
+								returnUpdating()
+								return true
+							}
+							logger.Error(e, "Failed to lift deletion protection of DB Instance")
+							returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
+							return true
+						} else if !lifted {
+							returnUpdating()
+							return true
+						}
+					}
+				}
+				if found {
+					if retain && controllerutil.ContainsFinalizer(dbInstance, ackDBInstanceFinalizer) {
+						// the RDS controller does not delete the AWS instance without its finalizer
+						if e := updateObject(ctx, r.Client, dbInstance, func() error {
+							controllerutil.RemoveFinalizer(dbInstance, ackDBInstanceFinalizer)
+							return nil
+						}); e != nil {
+							if errors.IsConflict(e) {
+								logger.Info("DB Instance modified, retry reconciling")
+								returnUpdating()
+								return true
+							}
+							logger.Error(e, "Failed to remove finalizer from DB Instance")
+							returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
+							return true
+						}
+						if isExternallyManaged(&instance) {
+							logger.Info("Externally managed DB Instance retained in AWS")
+						} else if namespaceTerminating {
+							logger.Info("DB Instance retained in AWS as the namespace of Instance is terminating")
+						} else {
+							logger.Info("Deletion protected DB Instance retained in AWS")
+							if r.Recorder != nil {
+								r.Recorder.Event(&instance, v1.EventTypeNormal, eventReasonDeletionProtected,
+									"The deletion protected DB instance is retained in AWS as its deletion is not permitted by the deletion protection policy")
+							}
+						}
+					}
+					if dbInstance.DeletionTimestamp.IsZero() {
+						if e := r.Delete(ctx, dbInstance); e != nil && !errors.IsNotFound(e) {
+							logger.Error(e, "Failed to delete DB Instance")
+							returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
+							return true
+						}
+					}
+					// the RDS controller keeps deleting the AWS instance, do not block the namespace termination on it
+					if !namespaceTerminating && !retain {
+						returnUpdating()
+						return true
+					}
+				}
+
+				if isMultiAZClusterDeployment(&instance) {
+					if exists, e := r.deleteMultiAZDBCluster(ctx, &instance, retain); e != nil {
+						if errors.IsConflict(e) {
+							logger.Info("DB Cluster modified, retry reconciling")
+							returnUpdating()
+							return true
+						}
+						logger.Error(e, "Failed to delete DB Cluster")
 						returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
 						return true
+					} else if exists && !namespaceTerminating && !retain {
+						returnUpdating()
+						return true
+					}
+				}
+
+				if !retain {
+					if e := r.deleteMasterCredentials(ctx, &instance); e != nil {
+						logger.Error(e, "Failed to delete master credentials of DB Instance from AWS Secrets Manager")
+						if !namespaceTerminating {
+							returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
+							return true
+						}
 					}
-					returnUpdating()
-					return true
 				}
 
-				controllerutil.RemoveFinalizer(&instance, instanceFinalizer)
-				if e := r.Update(ctx, &instance); e != nil {
+				r.deleteRecommendationsSyncTime(&instance)
+				deleteEngineVersionDeprecatedMetric(&instance)
+				if e := updateObject(ctx, r.Client, &instance, func() error {
+					controllerutil.RemoveFinalizer(&instance, instanceFinalizer)
+					return nil
+				}); e != nil {
 					if errors.IsConflict(e) {
 						logger.Info("Instance modified, retry reconciling")
 						returnUpdating()
@@ -266,7 +476,39 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			},
 		}
 
-		if r, e := controllerutil.CreateOrUpdate(ctx, r.Client, dbInstance, func() error {
+		if !frozenUntil.IsZero() {
+			// the DB Instance is created but not modified during a freeze window
+			if e := r.Get(ctx, client.ObjectKeyFromObject(dbInstance), dbInstance); e == nil {
+				logger.Info("Modifications of DB Instance deferred by freeze window", "until", frozenUntil)
+				return false
+			} else if !errors.IsNotFound(e) {
+				logger.Error(e, "Failed to get DB Instance")
+				returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
+				return true
+			}
+		}
+
+		if r, e := createOrApply(ctx, r.Client, dbInstance, func(existing client.Object) error {
+			if existing != nil {
+				// the fields that are only set when the DB Instance is created are kept
+				existingDBInstance := existing.(*rdsv1alpha1.DBInstance)
+				dbInstance.CreationTimestamp = existingDBInstance.CreationTimestamp
+				dbInstance.Spec.DBInstanceIdentifier = existingDBInstance.Spec.DBInstanceIdentifier
+				dbInstance.Spec.MasterUsername = existingDBInstance.Spec.MasterUsername
+				dbInstance.Spec.DBSubnetGroupName = existingDBInstance.Spec.DBSubnetGroupName
+				if _, ok := instance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningAvailabilityZones]; !ok {
+					dbInstance.Spec.AvailabilityZone = existingDBInstance.Spec.AvailabilityZone
+				}
+				if _, ok := instance.Spec.ProvisioningParameters[cloneFrom]; ok {
+					// the engine and the database of a clone are the ones of its source DB instance
+					dbInstance.Spec.Engine = existingDBInstance.Spec.Engine
+					dbInstance.Spec.EngineVersion = existingDBInstance.Spec.EngineVersion
+					dbInstance.Spec.DBName = existingDBInstance.Spec.DBName
+					if _, ok := instance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningStorageGib]; !ok {
+						dbInstance.Spec.AllocatedStorage = existingDBInstance.Spec.AllocatedStorage
+					}
+				}
+			}
 			if e := ophandler.SetOwnerAnnotations(&instance, dbInstance); e != nil {
 				logger.Error(e, "Failed to set owner for DB Instance")
 				returnError(e, instanceStatusReasonBackendError, e.Error())
@@ -274,30 +516,54 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			}
 
 			secret := &v1.Secret{}
-			if e := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace,
-				Name: inventory.Spec.CredentialsRef.Name}, secret); e != nil {
+			if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
 				logger.Error(e, "Failed to get Inventory credentials for setting spec of DB Instance")
 				returnError(e, instanceStatusReasonInputError, e.Error())
 				return e
 			}
 
-			if e := r.setDBInstanceSpec(ctx, dbInstance, &instance, secret); e != nil {
+			if e := r.setDBInstanceSpec(ctx, dbInstance, &instance, &inventory, secret); e != nil {
 				logger.Error(e, "Failed to set spec for DB Instance")
-				returnError(e, instanceStatusReasonInputError, e.Error())
+				reason := instanceStatusReasonInputError
+				var unsupported *unsupportedDeploymentError
+				var invalidNetwork *invalidNetworkError
+				if goerrors.As(e, &unsupported) {
+					reason = instanceStatusReasonUnsupportedDeployment
+				} else if goerrors.As(e, &invalidNetwork) {
+					reason = instanceStatusReasonInvalidNetwork
+				}
+				returnError(e, reason, e.Error())
 				return e
 			}
+			if existing != nil {
+				var e error
+				if deferredModifications, maintenanceWindowStart, e = r.deferDisruptiveModifications(dbInstance,
+					existing.(*rdsv1alpha1.DBInstance), &instance, time.Now()); e != nil {
+					logger.Error(e, "Failed to defer modifications of DB Instance")
+					returnError(e, instanceStatusReasonInputError, e.Error())
+					return e
+				}
+			}
 			return nil
 		}); e != nil {
 			logger.Error(e, "Failed to create or update DB Instance")
 			returnError(e, "", instanceStatusMessageCreateOrUpdateError)
 			return true
 		} else if r == controllerutil.OperationResultCreated {
+			// the identifier is reported as soon as the DB Instance is created, before its status is synced
+			instance.Status.InstanceID = *dbInstance.Spec.DBInstanceIdentifier
 			phase = dbaasv1beta1.InstancePhaseCreating
 			returnRequeue(instanceStatusReasonCreating, instanceStatusMessageCreating)
 			return true
 		} else if r == controllerutil.OperationResultUpdated {
 			phase = dbaasv1beta1.InstancePhaseUpdating
 		}
+
+		if e := r.storeMasterCredentials(ctx, dbInstance, &instance, &inventory); e != nil {
+			logger.Error(e, "Failed to store master credentials of DB Instance in AWS Secrets Manager")
+			returnError(e, instanceStatusReasonBackendError, instanceStatusMessageStoreCredentialsError)
+			return true
+		}
 		return false
 	}
 
@@ -313,42 +579,181 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return true
 		}
 
+		// the Instance is updated before its status is set, as the update returns the status stored in the cluster
+		var remediatedAllocatedStorage int64
+		var e error
+		if frozenUntil.IsZero() {
+			remediatedAllocatedStorage, e = r.remediateStorageFull(ctx, &instance, dbInstance)
+		}
+		if e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Instance modified, retry reconciling")
+				returnUpdating()
+				return true
+			}
+			logger.Error(e, "Failed to increase allocated storage of storage-full DB Instance")
+			returnError(e, instanceStatusReasonBackendError, instanceStatusMessageUpdateError)
+			return true
+		}
+		if e := r.setIaCImportAnnotations(ctx, &instance, dbInstance); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Instance modified, retry reconciling")
+				returnUpdating()
+				return true
+			}
+			logger.Error(e, "Failed to set import annotations of Instance")
+			returnError(e, instanceStatusReasonBackendError, instanceStatusMessageUpdateError)
+			return true
+		}
+		engineVersionCheck, e := r.checkEngineVersion(ctx, &instance, &inventory, dbInstance, time.Now())
+		if e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Instance modified, retry reconciling")
+				returnUpdating()
+				return true
+			}
+			// the engine version is checked again in the next reconcile
+			logger.Error(e, "Failed to check engine version of DB Instance")
+		}
+
 		instance.Status.InstanceID = *dbInstance.Spec.DBInstanceIdentifier
 		setDBInstancePhase(dbInstance, &instance)
 		setDBInstanceStatus(dbInstance, &instance)
-		regex := regexp.MustCompile("^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$")
-		for _, condition := range dbInstance.Status.Conditions {
-			c := metav1.Condition{
-				Type:   string(condition.Type),
-				Status: metav1.ConditionStatus(condition.Status),
-			}
-			if condition.LastTransitionTime != nil {
-				c.LastTransitionTime = metav1.Time{Time: condition.LastTransitionTime.Time}
-			}
-			if condition.Reason != nil && len(*condition.Reason) > 0 {
-				if match := regex.MatchString(*condition.Reason); match {
-					c.Reason = *condition.Reason
-					if condition.Message != nil {
-						c.Message = *condition.Message
-					}
-				} else {
-					c.Reason = instanceStatusReasonDBInstance
-					if condition.Message != nil {
-						c.Message = fmt.Sprintf("Reason: %s, Message: %s", *condition.Reason, *condition.Message)
-					} else {
-						c.Message = fmt.Sprintf("Reason: %s", *condition.Reason)
-					}
+		r.setStorageFullCondition(&instance, dbInstance, remediatedAllocatedStorage)
+		r.setStorageTypeConversionCondition(&instance, dbInstance)
+		setDomainJoinedCondition(dbInstance, &instance)
+		setPendingModificationsCondition(dbInstance, &instance)
+		setEngineVersionDeprecatedCondition(&instance, engineVersionCheck)
+		setFreezeWindowCondition(&instance, frozenUntil, freezeWindow)
+		if frozenUntil.IsZero() {
+			setModificationsDeferredCondition(&instance, deferredModifications, maintenanceWindowStart)
+		}
+		if _, ok := instance.Spec.ProvisioningParameters[s3BucketName]; ok {
+			setRestoredFromS3Condition(dbInstance, &instance)
+		}
+		if e := r.syncClonedCondition(ctx, dbInstance, &instance, &inventory); e != nil {
+			logger.Error(e, "Failed to reset master password of cloned DB Instance")
+			returnError(e, instanceStatusReasonBackendError, fmt.Sprintf(instanceStatusMessageCloneError,
+				instance.Spec.ProvisioningParameters[cloneFrom]))
+			return true
+		}
+		setACKResourceConditions(dbInstance.Status.Conditions, &instance)
+		if dbInstance.Status.ACKResourceMetadata != nil && dbInstance.Status.ACKResourceMetadata.ARN != nil {
+			if recommendationsSyncAt, e = r.syncRecommendationsCondition(ctx, &instance, &inventory,
+				string(*dbInstance.Status.ACKResourceMetadata.ARN), time.Now()); e != nil {
+				// the recommended actions are synced again in the next reconcile
+				logger.Error(e, "Failed to sync recommended actions of DB Instance")
+			}
+		}
+
+		if e := updateStatus(ctx, r.Client, &instance); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Instance modified, retry reconciling")
+				returnUpdating()
+				return true
+			}
+			logger.Error(e, "Failed to sync Instance status")
+			returnError(e, instanceStatusReasonBackendError, instanceStatusMessageUpdateError)
+			return true
+		}
+		return false
+	}
+
+	createOrUpdateMultiAZDBCluster := func() bool {
+		dbCluster := &rdsv1alpha1.DBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instance.Name,
+				Namespace: inventory.Namespace,
+			},
+		}
+
+		if !frozenUntil.IsZero() {
+			// the DB Cluster is created but not modified during a freeze window
+			if e := r.Get(ctx, client.ObjectKeyFromObject(dbCluster), dbCluster); e == nil {
+				logger.Info("Modifications of DB Cluster deferred by freeze window", "until", frozenUntil)
+				return false
+			} else if !errors.IsNotFound(e) {
+				logger.Error(e, "Failed to get DB Cluster")
+				returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
+				return true
+			}
+		}
+
+		if r, e := createOrApply(ctx, r.Client, dbCluster, func(existing client.Object) error {
+			var existingDBCluster *rdsv1alpha1.DBCluster
+			if existing != nil {
+				existingDBCluster = existing.(*rdsv1alpha1.DBCluster)
+				dbCluster.CreationTimestamp = existingDBCluster.CreationTimestamp
+			}
+			if e := ophandler.SetOwnerAnnotations(&instance, dbCluster); e != nil {
+				logger.Error(e, "Failed to set owner for DB Cluster")
+				returnError(e, instanceStatusReasonBackendError, e.Error())
+				return e
+			}
+
+			secret := &v1.Secret{}
+			if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
+				logger.Error(e, "Failed to get Inventory credentials for setting spec of DB Cluster")
+				returnError(e, instanceStatusReasonInputError, e.Error())
+				return e
+			}
+
+			if e := r.setMultiAZDBClusterSpec(ctx, dbCluster, existingDBCluster, &instance, &inventory, secret); e != nil {
+				logger.Error(e, "Failed to set spec for DB Cluster")
+				reason := instanceStatusReasonInputError
+				var unsupported *unsupportedDeploymentError
+				var invalidNetwork *invalidNetworkError
+				if goerrors.As(e, &unsupported) {
+					reason = instanceStatusReasonUnsupportedDeployment
+				} else if goerrors.As(e, &invalidNetwork) {
+					reason = instanceStatusReasonInvalidNetwork
 				}
+				returnError(e, reason, e.Error())
+				return e
+			}
+			return nil
+		}); e != nil {
+			logger.Error(e, "Failed to create or update DB Cluster")
+			returnError(e, "", instanceStatusMessageCreateOrUpdateError)
+			return true
+		} else if r == controllerutil.OperationResultCreated {
+			instance.Status.InstanceID = *dbCluster.Spec.DBClusterIdentifier
+			phase = dbaasv1beta1.InstancePhaseCreating
+			returnRequeue(instanceStatusReasonCreating, instanceStatusMessageCreating)
+			return true
+		} else if r == controllerutil.OperationResultUpdated {
+			phase = dbaasv1beta1.InstancePhaseUpdating
+		}
+		return false
+	}
+
+	syncMultiAZDBClusterStatus := func() bool {
+		dbCluster := &rdsv1alpha1.DBCluster{}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: instance.Name}, dbCluster); e != nil {
+			logger.Error(e, "Failed to get DB Cluster status")
+			if errors.IsNotFound(e) {
+				returnError(e, instanceStatusReasonNotFound, instanceStatusMessageGetError)
 			} else {
-				c.Reason = instanceStatusReasonDBInstance
-				if condition.Message != nil {
-					c.Message = *condition.Message
-				}
+				returnError(e, instanceStatusReasonBackendError, instanceStatusMessageGetError)
 			}
-			apimeta.SetStatusCondition(&instance.Status.Conditions, c)
+			return true
 		}
 
-		if e := r.Status().Update(ctx, &instance); e != nil {
+		instance.Status.InstanceID = *dbCluster.Spec.DBClusterIdentifier
+		instance.Status.Phase = getDBInstanceStatusPhase(pointer.StringDeref(dbCluster.Status.Status, ""))
+		instance.Status.InstanceInfo = parseDBClusterStatus(dbCluster)
+		setFreezeWindowCondition(&instance, frozenUntil, freezeWindow)
+		setACKResourceConditions(dbCluster.Status.Conditions, &instance)
+		if dbCluster.Status.ACKResourceMetadata != nil && dbCluster.Status.ACKResourceMetadata.ARN != nil {
+			var e error
+			if recommendationsSyncAt, e = r.syncRecommendationsCondition(ctx, &instance, &inventory,
+				string(*dbCluster.Status.ACKResourceMetadata.ARN), time.Now()); e != nil {
+				// the recommended actions are synced again in the next reconcile
+				logger.Error(e, "Failed to sync recommended actions of DB Cluster")
+			}
+		}
+
+		if e := updateStatus(ctx, r.Client, &instance); e != nil {
 			if errors.IsConflict(e) {
 				logger.Info("Instance modified, retry reconciling")
 				returnUpdating()
@@ -370,12 +775,40 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", instance.Spec.InventoryRef.Namespace, instance.Spec.InventoryRef.Name))
+	if len(instance.Status.InstanceID) > 0 {
+		logger = logger.WithValues(logging.KeyDBInstanceID, instance.Status.InstanceID)
+	}
+	ctx = log.IntoContext(ctx, logger)
+
 	defer updateInstanceReadyCondition()
 
 	if checkFinalizer() {
 		return
 	}
 
+	if expired, expiryRequeueAt, e := r.checkInstanceExpiry(&instance, time.Now()); e != nil {
+		logger.Error(e, "Failed to check expiry of Instance")
+		returnError(e, instanceStatusReasonInputError, e.Error())
+		return
+	} else if expired {
+		if e := r.deleteExpiredInstance(ctx, &instance); e != nil {
+			logger.Error(e, "Failed to delete expired Instance")
+			returnError(e, instanceStatusReasonBackendError, instanceStatusMessageDeleteError)
+			return
+		}
+		phase = dbaasv1beta1.InstancePhaseDeleting
+		returnNotReady(instanceStatusReasonDeleting, instanceStatusMessageDeleting)
+		return
+	} else {
+		// the expiry is warned about and the Instance deleted on time
+		defer func() {
+			if err == nil {
+				result = requeueAtFreezeWindowEnd(result, expiryRequeueAt, time.Now())
+			}
+		}()
+	}
+
 	if e := r.Get(ctx, client.ObjectKey{Namespace: instance.Spec.InventoryRef.Namespace,
 		Name: instance.Spec.InventoryRef.Name}, &inventory); e != nil {
 		if errors.IsNotFound(e) {
@@ -395,24 +828,110 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return
 	}
 
-	if createOrUpdateDBInstance() {
+	if e := r.validateInventoryProvisioning(ctx, &instance, &inventory); e != nil {
+		logger.Error(e, "Instance not allowed by the RDS Inventory")
+		returnError(e, instanceStatusReasonInputError, e.Error())
+		return
+	}
+
+	if updated, e := r.applyProvisioningPreset(ctx, &instance, &inventory); e != nil {
+		if errors.IsConflict(e) {
+			logger.Info("Instance modified, retry reconciling")
+			returnUpdating()
+			return
+		}
+		logger.Error(e, "Failed to apply provisioning preset of Instance")
+		returnError(e, instanceStatusReasonInputError, e.Error())
+		return
+	} else if updated {
+		returnUpdating()
+		return
+	}
+
+	if pending, message, e := r.checkProvisioningApproval(ctx, &instance, &inventory); e != nil {
+		logger.Error(e, "Failed to check provisioning approval of Instance")
+		returnError(e, instanceStatusReasonInputError, e.Error())
 		return
+	} else if pending {
+		logger.Info("Provisioning of Instance pending approval")
+		phase = dbaasv1beta1.InstancePhasePending
+		returnNotReady(instanceStatusReasonApprovalRequired, message)
+		return
+	}
+
+	now := time.Now()
+	if frozenUntil, freezeWindow, err = getActiveFreezeWindow(r.FreezeWindows, &inventory, now); err != nil {
+		logger.Error(err, "Failed to get freeze windows of Inventory")
+		returnError(err, instanceStatusReasonInputError, err.Error())
+		return
+	}
+	defer func() {
+		if err == nil {
+			result = requeueAtFreezeWindowEnd(result, frozenUntil, now)
+			// the deferred modifications are applied once the maintenance window starts
+			result = requeueAtFreezeWindowEnd(result, maintenanceWindowStart, now)
+			result = requeueAtFreezeWindowEnd(result, recommendationsSyncAt, now)
+		}
+	}()
+
+	// the status of a DB cluster is reported in the status key of its instance info
+	statusKey := "dbInstanceStatus"
+	if isMultiAZClusterDeployment(&instance) {
+		if createOrUpdateMultiAZDBCluster() {
+			return
+		}
+		if syncMultiAZDBClusterStatus() {
+			return
+		}
+		statusKey = "status"
+	} else {
+		if createOrUpdateDBInstance() {
+			return
+		}
+		if syncDBInstanceStatus() {
+			return
+		}
 	}
 
-	if syncDBInstanceStatus() {
+	if e := validateBranches(&instance); e != nil {
+		logger.Error(e, "Branches of Instance not valid")
+		returnError(e, instanceStatusReasonInputError, e.Error())
+		return
+	}
+	if e := r.syncBranches(ctx, &instance); e != nil {
+		logger.Error(e, "Failed to sync branches of Instance")
+		returnError(e, instanceStatusReasonBackendError, fmt.Sprintf("%s: %v", instanceStatusMessageBranchesError, e))
 		return
 	}
 
+	statusMessage := getDBInstanceStatusMessage(instance.Status.InstanceInfo[statusKey])
 	switch instance.Status.Phase {
 	case dbaasv1beta1.InstancePhaseReady:
 		returnReady()
 	case dbaasv1beta1.InstancePhaseFailed, dbaasv1beta1.InstancePhaseDeleted:
-		returnNotReady(instanceStatusReasonTerminated, string(instance.Status.Phase))
+		if len(statusMessage) > 0 {
+			returnNotReady(instanceStatusReasonTerminated, fmt.Sprintf("%s: %s", instance.Status.Phase, statusMessage))
+		} else {
+			returnNotReady(instanceStatusReasonTerminated, string(instance.Status.Phase))
+		}
 	case dbaasv1beta1.InstancePhasePending, dbaasv1beta1.InstancePhaseCreating,
 		dbaasv1beta1.InstancePhaseUpdating, dbaasv1beta1.InstancePhaseDeleting:
 		returnUpdating()
+		if len(statusMessage) > 0 {
+			provisionStatusMessage = fmt.Sprintf("%s: %s", instanceStatusMessageUpdating, statusMessage)
+		}
+		if interval := getDBInstanceStatusRequeueInterval(instance.Status.InstanceInfo[statusKey]); interval > 0 {
+			result = ctrl.Result{RequeueAfter: interval}
+		}
 	case dbaasv1beta1.InstancePhaseError, dbaasv1beta1.InstancePhaseUnknown:
-		returnRequeue(instanceStatusReasonBackendError, instanceStatusMessageError)
+		if instance.Status.InstanceInfo[statusKey] == "stopped" {
+			// a stopped DB instance is not an error, the Instance is ready again once the DB instance is started
+			returnNotReady(instanceStatusReasonStopped, fmt.Sprintf("%s: %s", instanceStatusMessageStopped, statusMessage))
+		} else if len(statusMessage) > 0 {
+			returnRequeue(instanceStatusReasonBackendError, fmt.Sprintf("%s: %s", instanceStatusMessageError, statusMessage))
+		} else {
+			returnRequeue(instanceStatusReasonBackendError, instanceStatusMessageError)
+		}
 	default:
 	}
 
@@ -420,30 +939,53 @@ func (r *RDSInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 }
 
 func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
-	rdsInstance *rdsdbaasv1alpha1.RDSInstance, secret *v1.Secret) error {
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	// the DB Instance is provisioned in the region of the Inventory account, which is the only region it can select
+	if region, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningRegions]; ok && len(region) > 0 {
+		if inventoryRegion, ok := secret.Data[awsRegion]; ok && region != string(inventoryRegion) {
+			return fmt.Errorf(invalidParameterErrorTemplate, dbaasv1beta1.ProvisioningRegions)
+		}
+	}
+
 	if az, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningAvailabilityZones]; ok {
 		dbInstance.Spec.AvailabilityZone = pointer.String(az)
-	} else if region, ok := secret.Data[awsRegion]; ok {
-		az := getDefaultAvailabilityZone(string(region))
-		if az != nil {
-			dbInstance.Spec.AvailabilityZone = az
+	} else if dbInstance.Spec.AvailabilityZone == nil {
+		// the availability zone selected when the DB Instance was created is kept
+		if region, ok := secret.Data[awsRegion]; ok {
+			az, e := r.getPreferredAvailabilityZone(ctx, rdsInstance, string(region))
+			if e != nil {
+				return e
+			}
+			if az != nil {
+				dbInstance.Spec.AvailabilityZone = az
+			} else {
+				return fmt.Errorf(requiredParameterErrorTemplate, "AvailabilityZone")
+			}
 		} else {
-			return fmt.Errorf(requiredParameterErrorTemplate, "AvailabilityZone")
+			dbInstance.Spec.AvailabilityZone = pointer.String(defaultAvailabilityZone)
 		}
-	} else {
-		dbInstance.Spec.AvailabilityZone = pointer.String(defaultAvailabilityZone)
 	}
 
-	if engine, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningDatabaseType]; ok {
-		dbInstance.Spec.Engine = pointer.String(engine)
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[cloneFrom]; ok {
+		if e := r.setCloneSourceSpec(ctx, dbInstance, rdsInstance, inventory, secret); e != nil {
+			return e
+		}
 	} else {
-		return fmt.Errorf(requiredParameterErrorTemplate, "Engine")
-	}
+		if engine, ok := rdsInstance.Spec.ProvisioningParameters[dbaasv1beta1.ProvisioningDatabaseType]; ok {
+			if isCustomEngine(engine) {
+				// RDS Custom DB instances require a custom engine version and an instance profile set up outside of the operator
+				return &unsupportedDeploymentError{deploymentType: "RDS Custom", operation: "Provisioning"}
+			}
+			dbInstance.Spec.Engine = pointer.String(engine)
+		} else {
+			return fmt.Errorf(requiredParameterErrorTemplate, "Engine")
+		}
 
-	if engineVersion, ok := rdsInstance.Spec.ProvisioningParameters[engineVersion]; ok {
-		dbInstance.Spec.EngineVersion = pointer.String(engineVersion)
-	} else {
-		dbInstance.Spec.EngineVersion = getDefaultEngineVersion(dbInstance.Spec.Engine)
+		if engineVersion, ok := rdsInstance.Spec.ProvisioningParameters[engineVersion]; ok {
+			dbInstance.Spec.EngineVersion = pointer.String(engineVersion)
+		} else {
+			dbInstance.Spec.EngineVersion = getDefaultEngineVersion(dbInstance.Spec.Engine)
+		}
 	}
 
 	if dbInstance.Spec.DBInstanceIdentifier == nil {
@@ -454,8 +996,25 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 			} else {
 				return fmt.Errorf(invalidParameterErrorTemplate, "DBInstanceIdentifier")
 			}
+		} else if id, ok := rdsInstance.Annotations[dbInstanceIdentifierAnnotation]; ok && len(id) > 0 {
+			// the creation of the DB instance was interrupted, it is resumed with the same identifier
+			dbInstance.Spec.DBInstanceIdentifier = pointer.String(id)
 		} else {
-			dbInstance.Spec.DBInstanceIdentifier = pointer.String(fmt.Sprintf("rhoda-%s%s", getDBEngineAbbreviation(dbInstance.Spec.Engine), string(uuid.NewUUID())))
+			id, e := r.newDBInstanceIdentifier(ctx, dbInstance, rdsInstance, secret)
+			if e != nil {
+				return e
+			}
+			// the identifier is recorded before the DB instance is created to not create another one if interrupted
+			if e := updateObject(ctx, r.Client, rdsInstance, func() error {
+				if rdsInstance.Annotations == nil {
+					rdsInstance.Annotations = map[string]string{}
+				}
+				rdsInstance.Annotations[dbInstanceIdentifierAnnotation] = id
+				return nil
+			}); e != nil {
+				return e
+			}
+			dbInstance.Spec.DBInstanceIdentifier = pointer.String(id)
 		}
 	}
 
@@ -475,9 +1034,12 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		} else {
 			dbInstance.Spec.AllocatedStorage = pointer.Int64(i)
 		}
-	} else {
+	} else if dbInstance.Spec.AllocatedStorage == nil {
 		dbInstance.Spec.AllocatedStorage = pointer.Int64(defaultAllocatedStorage)
 	}
+	if remediated := getRemediatedAllocatedStorage(rdsInstance); remediated > *dbInstance.Spec.AllocatedStorage {
+		dbInstance.Spec.AllocatedStorage = pointer.Int64(remediated)
+	}
 
 	if iops, ok := rdsInstance.Spec.ProvisioningParameters[iops]; ok {
 		if i, e := strconv.ParseInt(iops, 10, 64); e != nil {
@@ -487,6 +1049,10 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		}
 	}
 
+	if e := validateGP3IOPS(dbInstance); e != nil {
+		return e
+	}
+
 	if maxAllocatedStorage, ok := rdsInstance.Spec.ProvisioningParameters[maxAllocatedStorage]; ok {
 		if i, e := strconv.ParseInt(maxAllocatedStorage, 10, 64); e != nil {
 			return fmt.Errorf(invalidParameterErrorTemplate, "MaxAllocatedStorage")
@@ -499,6 +1065,37 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		dbInstance.Spec.DBSubnetGroupName = pointer.String(dbSubnetGroupName)
 	}
 
+	if snapshotID, ok := rdsInstance.Spec.ProvisioningParameters[dbSnapshotIdentifier]; ok {
+		dbInstance.Spec.DBSnapshotIdentifier = pointer.String(snapshotID)
+	}
+
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[logRetentionDays]; ok {
+		if e := r.setLogRetentionParameterGroup(ctx, dbInstance, rdsInstance); e != nil {
+			return e
+		}
+	} else if name, ok := rdsInstance.Spec.ProvisioningParameters[rdsParameterGroup]; ok {
+		groupName, e := r.getReferencedDBParameterGroupName(ctx, rdsInstance, name)
+		if e != nil {
+			return e
+		}
+		dbInstance.Spec.DBParameterGroupName = pointer.String(groupName)
+	}
+
+	if name, ok := rdsInstance.Spec.ProvisioningParameters[rdsOptionGroup]; ok {
+		groupName, e := r.getReferencedOptionGroupName(ctx, rdsInstance, pointer.StringDeref(dbInstance.Spec.Engine, ""),
+			pointer.StringDeref(dbInstance.Spec.EngineVersion, ""), name)
+		if e != nil {
+			return e
+		}
+		dbInstance.Spec.OptionGroupName = pointer.String(groupName)
+	}
+
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[s3BucketName]; ok {
+		if e := validateRestoreFromS3Parameters(rdsInstance, dbInstance); e != nil {
+			return e
+		}
+	}
+
 	if publiclyAccessible, ok := rdsInstance.Spec.ProvisioningParameters[publiclyAccessible]; ok {
 		if b, e := strconv.ParseBool(publiclyAccessible); e != nil {
 			return fmt.Errorf(invalidParameterErrorTemplate, "PubliclyAccessible")
@@ -509,6 +1106,24 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		dbInstance.Spec.PubliclyAccessible = pointer.Bool(defaultPubliclyAccessible)
 	}
 
+	if deletionProtection, ok := rdsInstance.Spec.ProvisioningParameters[deletionProtection]; ok {
+		if b, e := strconv.ParseBool(deletionProtection); e != nil {
+			return fmt.Errorf(invalidParameterErrorTemplate, "DeletionProtection")
+		} else {
+			dbInstance.Spec.DeletionProtection = pointer.Bool(b)
+		}
+	}
+
+	if copyTagsToSnapshot, ok := rdsInstance.Spec.ProvisioningParameters[copyTagsToSnapshot]; ok {
+		if b, e := strconv.ParseBool(copyTagsToSnapshot); e != nil {
+			return fmt.Errorf(invalidParameterErrorTemplate, "CopyTagsToSnapshot")
+		} else {
+			dbInstance.Spec.CopyTagsToSnapshot = pointer.Bool(b)
+		}
+	}
+
+	setExternallyManagedTag(dbInstance, rdsInstance)
+
 	if vpcSecurityGroupIDs, ok := rdsInstance.Spec.ProvisioningParameters[vpcSecurityGroupIDs]; ok {
 		sl := strings.Split(vpcSecurityGroupIDs, ",")
 		var sgs []*string
@@ -519,6 +1134,12 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		dbInstance.Spec.VPCSecurityGroupIDs = sgs
 	}
 
+	if dbInstance.CreationTimestamp.IsZero() {
+		if e := r.setDBSubnetGroup(ctx, dbInstance, rdsInstance, inventory, secret); e != nil {
+			return e
+		}
+	}
+
 	if licenseModel, ok := rdsInstance.Spec.ProvisioningParameters[licenseModel]; ok {
 		dbInstance.Spec.LicenseModel = pointer.String(licenseModel)
 	} else if dbInstance.Spec.Engine != nil {
@@ -528,75 +1149,471 @@ func (r *RDSInstanceReconciler) setDBInstanceSpec(ctx context.Context, dbInstanc
 		}
 	}
 
-	if _, e := setCredentials(ctx, r.Client, r.Scheme, dbInstance.GetName(), rdsInstance.Namespace, rdsInstance, rdsInstance.Kind,
-		func(secretName string) {
-			if dbInstance.Spec.MasterUsername == nil {
-				dbInstance.Spec.MasterUsername = pointer.String(generateUsername(*dbInstance.Spec.Engine))
-			}
+	if e := setDomainParameters(dbInstance, rdsInstance); e != nil {
+		return e
+	}
 
-			dbInstance.Spec.MasterUserPassword = &ackv1alpha1.SecretKeyReference{
-				SecretReference: v1.SecretReference{
-					Name:      secretName,
-					Namespace: rdsInstance.Namespace,
-				},
-				Key: "password",
-			}
-		}); e != nil {
-		return fmt.Errorf("failed to set credentials for DB instance")
+	if value, ok := rdsInstance.Spec.ProvisioningParameters[rdsdbaasv1alpha1.ExtraParameters]; ok {
+		if e := r.setExtraParameters(dbInstance, value); e != nil {
+			return e
+		}
 	}
 
-	dbName := generateDBName(*dbInstance.Spec.Engine)
-	dbInstance.Spec.DBName = dbName
+	if dbInstance.CreationTimestamp.IsZero() {
+		if e := r.validateNetwork(ctx, dbInstance, rdsInstance, secret); e != nil {
+			return e
+		}
+	}
+
+	if username, ok := rdsInstance.Spec.ProvisioningParameters[masterUsername]; ok && dbInstance.Spec.MasterUsername == nil {
+		if e := validateMasterUsername(*dbInstance.Spec.Engine, username); e != nil {
+			return e
+		}
+		dbInstance.Spec.MasterUsername = pointer.String(username)
+	}
+
+	policy, e := getPasswordPolicy(*dbInstance.Spec.Engine, rdsInstance.Spec.ProvisioningParameters)
+	if e != nil {
+		return e
+	}
+
+	// the master password is not kept in the cluster once it is stored in AWS Secrets Manager
+	released, e := r.isMasterPasswordReleased(ctx, dbInstance, rdsInstance, inventory)
+	if e != nil {
+		return e
+	}
+	var credentials *v1.Secret
+	if !released {
+		credentials, e = setCredentials(ctx, r.Client, r.Scheme, dbInstance.GetName(), rdsInstance.Namespace, rdsInstance, rdsInstance.Kind, policy,
+			func(secretName string) {
+				if dbInstance.Spec.MasterUsername == nil {
+					dbInstance.Spec.MasterUsername = pointer.String(generateUsername(*dbInstance.Spec.Engine))
+				}
+
+				dbInstance.Spec.MasterUserPassword = &ackv1alpha1.SecretKeyReference{
+					SecretReference: v1.SecretReference{
+						Name:      secretName,
+						Namespace: rdsInstance.Namespace,
+					},
+					Key: "password",
+				}
+			})
+		if e != nil {
+			return fmt.Errorf("failed to set credentials for DB instance")
+		}
+	}
+
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[cloneFrom]; !ok {
+		dbName := generateDBName(*dbInstance.Spec.Engine)
+		dbInstance.Spec.DBName = dbName
+	}
+
+	if dbInstance.CreationTimestamp.IsZero() {
+		// the RDS controller takes over the restored AWS instance with the same identifier
+		if e := r.restoreDBInstanceFromS3(ctx, dbInstance, rdsInstance, secret, credentials); e != nil {
+			return e
+		}
+		if e := r.restoreDBInstanceToPointInTime(ctx, dbInstance, rdsInstance, secret); e != nil {
+			return e
+		}
+	}
 
 	return nil
 }
 
-func setDBInstancePhase(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
-	var status string
-	if dbInstance.Status.DBInstanceStatus != nil {
-		status = *dbInstance.Status.DBInstanceStatus
-	} else {
-		status = ""
+// setExtraParameters passes the allowed fields of the extra parameters through to the spec of the DB Instance
+func (r *RDSInstanceReconciler) setExtraParameters(dbInstance *rdsv1alpha1.DBInstance, value string) error {
+	if !r.EnableExtraParameters {
+		return fmt.Errorf("parameter %s is not enabled", rdsdbaasv1alpha1.ExtraParameters)
+	}
+	parameters, e := rdsdbaasv1alpha1.ParseExtraParameters(value, r.ExtraParametersAllowList)
+	if e != nil {
+		return e
+	}
+
+	b, e := json.Marshal(dbInstance.Spec)
+	if e != nil {
+		return e
+	}
+	spec := map[string]json.RawMessage{}
+	if e := json.Unmarshal(b, &spec); e != nil {
+		return e
+	}
+	for k, v := range parameters {
+		spec[k] = v
+	}
+	if b, e = json.Marshal(spec); e != nil {
+		return e
+	}
+	dbInstanceSpec := rdsv1alpha1.DBInstanceSpec{}
+	if e := json.Unmarshal(b, &dbInstanceSpec); e != nil {
+		return fmt.Errorf(invalidParameterErrorTemplate, rdsdbaasv1alpha1.ExtraParameters)
+	}
+	dbInstance.Spec = dbInstanceSpec
+	return nil
+}
+
+func validateRestoreFromS3Parameters(rdsInstance *rdsdbaasv1alpha1.RDSInstance, dbInstance *rdsv1alpha1.DBInstance) error {
+	if *dbInstance.Spec.Engine != s3SourceEngine {
+		return fmt.Errorf("restore from Amazon S3 is only supported for engine %s", s3SourceEngine)
+	}
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[dbSnapshotIdentifier]; ok {
+		return fmt.Errorf("parameters %s and %s can not be set together", dbSnapshotIdentifier, s3BucketName)
+	}
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[s3IngestionRoleARN]; !ok {
+		return fmt.Errorf(requiredParameterErrorTemplate, s3IngestionRoleARN)
+	}
+	if _, ok := rdsInstance.Spec.ProvisioningParameters[sourceEngineVersion]; !ok {
+		return fmt.Errorf(requiredParameterErrorTemplate, sourceEngineVersion)
+	}
+	return nil
+}
+
+// restoreDBInstanceFromS3 creates the AWS instance from the Percona XtraBackup files in the Amazon S3 bucket
+// of the provisioning parameters before the DB Instance is created
+func (r *RDSInstanceReconciler) restoreDBInstanceFromS3(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, secret *v1.Secret, credentials *v1.Secret) error {
+	logger := log.FromContext(ctx)
+
+	bucket, ok := rdsInstance.Spec.ProvisioningParameters[s3BucketName]
+	if !ok {
+		return nil
+	}
+
+	input := &rds.RestoreDBInstanceFromS3Input{
+		DBInstanceIdentifier: dbInstance.Spec.DBInstanceIdentifier,
+		DBInstanceClass:      dbInstance.Spec.DBInstanceClass,
+		Engine:               dbInstance.Spec.Engine,
+		EngineVersion:        dbInstance.Spec.EngineVersion,
+		SourceEngine:         pointer.String(s3SourceEngine),
+		SourceEngineVersion:  pointer.String(rdsInstance.Spec.ProvisioningParameters[sourceEngineVersion]),
+		S3BucketName:         pointer.String(bucket),
+		S3IngestionRoleArn:   pointer.String(rdsInstance.Spec.ProvisioningParameters[s3IngestionRoleARN]),
+		MasterUsername:       dbInstance.Spec.MasterUsername,
+		MasterUserPassword:   pointer.String(string(credentials.Data["password"])),
+		DBName:               dbInstance.Spec.DBName,
+		AvailabilityZone:     dbInstance.Spec.AvailabilityZone,
+		DBSubnetGroupName:    dbInstance.Spec.DBSubnetGroupName,
+		PubliclyAccessible:   dbInstance.Spec.PubliclyAccessible,
+		StorageType:          dbInstance.Spec.StorageType,
+		LicenseModel:         dbInstance.Spec.LicenseModel,
+	}
+	if prefix, ok := rdsInstance.Spec.ProvisioningParameters[s3Prefix]; ok {
+		input.S3Prefix = pointer.String(prefix)
+	}
+	if dbInstance.Spec.AllocatedStorage != nil {
+		input.AllocatedStorage = pointer.Int32(int32(*dbInstance.Spec.AllocatedStorage))
+	}
+	if dbInstance.Spec.IOPS != nil {
+		input.Iops = pointer.Int32(int32(*dbInstance.Spec.IOPS))
+	}
+	if dbInstance.Spec.MaxAllocatedStorage != nil {
+		input.MaxAllocatedStorage = pointer.Int32(int32(*dbInstance.Spec.MaxAllocatedStorage))
+	}
+	for _, sg := range dbInstance.Spec.VPCSecurityGroupIDs {
+		input.VpcSecurityGroupIds = append(input.VpcSecurityGroupIds, *sg)
+	}
+
+	restoreDBInstanceFromS3 := r.GetRestoreDBInstanceFromS3API(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	if _, e := restoreDBInstanceFromS3.RestoreDBInstanceFromS3(ctx, input); e != nil {
+		var existsErr *rdstypesv2.DBInstanceAlreadyExistsFault
+		if goerrors.As(e, &existsErr) {
+			// restored by a previous reconciliation before the DB Instance was created
+			return nil
+		}
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionRestoredFromS3,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonRestoreFailed,
+			Message: fmt.Sprintf("%s: %s", instanceStatusMessageRestoreError, e.Error()),
+		})
+		return fmt.Errorf("%s: %w", instanceStatusMessageRestoreError, e)
+	}
+	logger.Info("DB Instance restore from Amazon S3 started", "DB Instance Identifier", *dbInstance.Spec.DBInstanceIdentifier)
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    instanceConditionRestoredFromS3,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonRestoring,
+		Message: instanceStatusMessageRestoring,
+	})
+	return nil
+}
+
+// setRestoredFromS3Condition reflects the progress of the restore from Amazon S3 by the status of the AWS instance
+func setRestoredFromS3Condition(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	if condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionRestoredFromS3); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionRestoredFromS3,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonRestoring,
+		Message: instanceStatusMessageRestoring,
+	}
+	status := pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "")
+	statusMessage := getDBInstanceStatusMessage(status)
+	if len(statusMessage) == 0 {
+		statusMessage = status
 	}
 	switch status {
+	case "":
 	case "available":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseReady
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonRestored
+		condition.Message = instanceStatusMessageRestored
+	case "failed", "incompatible-restore", "incompatible-parameters", "incompatible-network", "inaccessible-encryption-credentials":
+		condition.Reason = instanceStatusReasonRestoreFailed
+		condition.Message = fmt.Sprintf("%s: %s", instanceStatusMessageRestoreError, statusMessage)
+	default:
+		condition.Message = fmt.Sprintf("%s: %s", instanceStatusMessageRestoring, statusMessage)
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+}
+
+// setDBSubnetGroup selects the DB subnet group by the name prefix or tag selector of the provisioning parameters among
+// the DB subnet groups discovered by the sync of the Inventory
+func (r *RDSInstanceReconciler) setDBSubnetGroup(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, inventory *rdsdbaasv1alpha1.RDSInventory, secret *v1.Secret) error {
+	prefix, hasPrefix := rdsInstance.Spec.ProvisioningParameters[dbSubnetGroupNamePrefix]
+	selector, hasSelector := rdsInstance.Spec.ProvisioningParameters[dbSubnetGroupSelector]
+	if !hasPrefix && !hasSelector {
+		if dbInstance.Spec.DBSubnetGroupName == nil && inventory.Annotations[inventoryAutoDBSubnetGroupAnnotation] == "true" {
+			return r.createOrUpdateDBSubnetGroup(ctx, dbInstance, inventory, secret)
+		}
+		return nil
+	}
+	var tags map[string]string
+	if hasSelector {
+		t, e := parseTagSelector(selector)
+		if e != nil {
+			return fmt.Errorf(invalidParameterErrorTemplate, dbSubnetGroupSelector)
+		}
+		tags = t
+	}
+
+	subnetGroups, e := getDiscoveredDBSubnetGroups(ctx, r.Client, inventory)
+	if e != nil {
+		return e
+	}
+	for _, sg := range subnetGroups {
+		if dbInstance.Spec.DBSubnetGroupName != nil && *dbInstance.Spec.DBSubnetGroupName != sg.Name {
+			continue
+		}
+		if hasPrefix && !strings.HasPrefix(sg.Name, prefix) {
+			continue
+		}
+		if hasSelector && !matchTags(sg.Tags, tags) {
+			continue
+		}
+		// the DB subnet groups are sorted by name
+		dbInstance.Spec.DBSubnetGroupName = pointer.String(sg.Name)
+		return nil
+	}
+	return fmt.Errorf("no DB subnet group matches the provisioning parameters")
+}
+
+func setDBInstancePhase(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	rdsInstance.Status.Phase = getDBInstanceStatusPhase(pointer.StringDeref(dbInstance.Status.DBInstanceStatus, ""))
+}
+
+// getDBInstanceStatusPhase returns the phase of an Instance from the status of its DB instance, the DB clusters share
+// the statuses of the DB instances
+func getDBInstanceStatusPhase(status string) dbaasv1beta1.DBaasInstancePhase {
+	switch status {
+	case "available":
+		return dbaasv1beta1.InstancePhaseReady
 	case "creating":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseCreating
+		return dbaasv1beta1.InstancePhaseCreating
+	case "delete-precheck", "deleting":
+		return dbaasv1beta1.InstancePhaseDeleting
+	case "failed", "inaccessible-encryption-credentials":
+		return dbaasv1beta1.InstancePhaseFailed
+	case "inaccessible-encryption-credentials-recoverable", "incompatible-network", "incompatible-option-group",
+		"incompatible-parameters", "incompatible-restore", "insufficient-capacity", "restore-error", "storage-full":
+		return dbaasv1beta1.InstancePhaseError
+	case "backing-up", "configuring-activity-stream", "configuring-enhanced-monitoring", "configuring-iam-database-auth",
+		"configuring-log-exports", "converting-to-vpc", "maintenance", "modifying", "moving-to-vpc", "rebooting",
+		"resetting-master-credentials", "renaming", "starting", "stopping", "storage-config-upgrade", "storage-optimization",
+		"upgrading":
+		return dbaasv1beta1.InstancePhaseUpdating
+	default:
+		return dbaasv1beta1.InstancePhaseUnknown
+	}
+}
+
+// getDBInstanceStatusMessage returns the human-readable description of the RDS DB instance status
+func getDBInstanceStatusMessage(status string) string {
+	switch status {
+	case "backing-up":
+		return "The DB instance is being backed up"
+	case "configuring-activity-stream":
+		return "The database activity stream of the DB instance is being configured"
+	case "configuring-enhanced-monitoring":
+		return "Enhanced Monitoring is being enabled or disabled for the DB instance"
+	case "configuring-iam-database-auth":
+		return "AWS IAM database authentication is being enabled or disabled for the DB instance"
+	case "configuring-log-exports":
+		return "Publishing log files to Amazon CloudWatch Logs is being enabled or disabled for the DB instance"
+	case "converting-to-vpc", "moving-to-vpc":
+		return "The DB instance is being moved to an Amazon VPC"
+	case "creating":
+		return "The DB instance is being created, the DB instance is inaccessible while it is being created"
+	case "delete-precheck":
+		return "Amazon RDS is validating that read replicas are healthy and are safe to delete"
 	case "deleting":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseDeleting
+		return "The DB instance is being deleted"
 	case "failed":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseFailed
-	case "inaccessible-encryption-credentials-recoverable", "incompatible-parameters", "restore-error":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseError
-	case "backing-up", "configuring-enhanced-monitoring", "configuring-iam-database-auth", "configuring-log-exports",
-		"converting-to-vpc", "maintenance", "modifying", "moving-to-vpc", "rebooting", "resetting-master-credentials",
-		"renaming", "starting", "stopping", "storage-optimization", "upgrading":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseUpdating
-	case "inaccessible-encryption-credentials", "incompatible-network", "incompatible-option-group", "incompatible-restore",
-		"insufficient-capacity", "stopped", "storage-full":
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseUnknown
+		return "The DB instance has failed and Amazon RDS can't recover it"
+	case "inaccessible-encryption-credentials":
+		return "The AWS KMS key used to encrypt the DB instance can't be accessed or recovered"
+	case "inaccessible-encryption-credentials-recoverable":
+		return "The AWS KMS key used to encrypt the DB instance can't be accessed, the DB instance can be recovered if the key is reactivated"
+	case "incompatible-network":
+		return "Amazon RDS is attempting to perform a recovery action on the DB instance but can't do so because the VPC is in a state that prevents the action from being completed"
+	case "incompatible-option-group":
+		return "Amazon RDS attempted to apply an option group change but can't do so, and Amazon RDS can't roll back to the previous option group state"
+	case "incompatible-parameters":
+		return "Amazon RDS can't start the DB instance because the parameters in the DB parameter group aren't compatible with the DB instance"
+	case "incompatible-restore":
+		return "Amazon RDS can't do a point-in-time restore of the DB instance"
+	case "insufficient-capacity":
+		return "Amazon RDS can't create the DB instance because sufficient capacity isn't currently available"
+	case "maintenance":
+		return "Amazon RDS is applying a maintenance update to the DB instance"
+	case "modifying":
+		return "The DB instance is being modified because of a request to modify the DB instance"
+	case "rebooting":
+		return "The DB instance is being rebooted"
+	case "renaming":
+		return "The DB instance is being renamed"
+	case "resetting-master-credentials":
+		return "The master credentials for the DB instance are being reset"
+	case "restore-error":
+		return "The DB instance encountered an error attempting to restore to a point-in-time or from a snapshot"
+	case "starting":
+		return "The DB instance is starting"
+	case "stopped":
+		return "The DB instance is stopped"
+	case "stopping":
+		return "The DB instance is being stopped"
+	case "storage-config-upgrade":
+		return "The storage file system configuration of the DB instance is being upgraded"
+	case "storage-full":
+		return "The DB instance has reached its storage capacity allocation"
+	case "storage-optimization":
+		return "Amazon RDS is optimizing the storage of the DB instance, the DB instance is fully operational"
+	case "upgrading":
+		return "The database engine version of the DB instance is being upgraded"
+	default:
+		return ""
+	}
+}
+
+// getDBInstanceStatusRequeueInterval returns the interval to check again the DB instance in a transitional status,
+// zero means the requeue is left to the rate limiter of the controller
+func getDBInstanceStatusRequeueInterval(status string) time.Duration {
+	switch status {
+	case "rebooting", "renaming", "resetting-master-credentials", "starting", "stopping":
+		return 15 * time.Second
+	case "configuring-activity-stream", "configuring-enhanced-monitoring", "configuring-iam-database-auth",
+		"configuring-log-exports", "delete-precheck", "deleting", "modifying":
+		return 30 * time.Second
+	case "creating":
+		return 1 * time.Minute
+	case "backing-up", "converting-to-vpc", "maintenance", "moving-to-vpc", "upgrading":
+		return 2 * time.Minute
+	case "storage-config-upgrade", "storage-optimization":
+		// the storage optimization can take several hours and the DB instance is operational meanwhile
+		return 10 * time.Minute
 	default:
-		rdsInstance.Status.Phase = dbaasv1beta1.InstancePhaseUnknown
+		return 0
 	}
 }
 
 func setDBInstanceStatus(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
 	instanceStatus := parseDBInstanceStatus(dbInstance)
 	rdsInstance.Status.InstanceInfo = instanceStatus
+	setIaCImportInstanceInfo(rdsInstance, dbInstance)
+}
+
+// setACKResourceConditions copies the conditions of the RDS controller resource of the Instance to the Instance, the
+// reasons that are not valid condition reasons are moved to the message
+func setACKResourceConditions(conditions []*ackv1alpha1.Condition, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	regex := regexp.MustCompile("^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$")
+	for _, condition := range conditions {
+		c := metav1.Condition{
+			Type:   string(condition.Type),
+			Status: metav1.ConditionStatus(condition.Status),
+		}
+		if condition.LastTransitionTime != nil {
+			c.LastTransitionTime = metav1.Time{Time: condition.LastTransitionTime.Time}
+		}
+		if condition.Reason != nil && len(*condition.Reason) > 0 {
+			if match := regex.MatchString(*condition.Reason); match {
+				c.Reason = *condition.Reason
+				if condition.Message != nil {
+					c.Message = *condition.Message
+				}
+			} else {
+				c.Reason = instanceStatusReasonDBInstance
+				if condition.Message != nil {
+					c.Message = fmt.Sprintf("Reason: %s, Message: %s", *condition.Reason, *condition.Message)
+				} else {
+					c.Message = fmt.Sprintf("Reason: %s", *condition.Reason)
+				}
+			}
+		} else {
+			c.Reason = instanceStatusReasonDBInstance
+			if condition.Message != nil {
+				c.Message = *condition.Message
+			}
+		}
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, c)
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RDSInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.validateDBInstanceIdentifierOptions(); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rdsdbaasv1alpha1.RDSInstance{}, inventoryRefKey,
+		indexInstanceInventoryRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rdsv1alpha1.DBInstance{}, dbInstanceIdentifierKey,
+		indexDBInstanceIdentifier); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&rdsdbaasv1alpha1.RDSInstance{}).
+		Owns(&rdsdbaasv1alpha1.RDSParameterGroup{}).
+		// the branches of the Instances and their snapshots
+		Owns(&rdsdbaasv1alpha1.RDSInstance{}).
+		Owns(&rdsdbaasv1alpha1.RDSSnapshot{}).
 		Watches(
 			&source.Kind{Type: &rdsv1alpha1.DBInstance{}},
 			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
 				return getOwnerInstanceRequests(o)
 			}),
 		).
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &rdsv1alpha1.DBCluster{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+				return getOwnerInstanceRequests(o)
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &rdsdbaasv1alpha1.RDSInventory{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+				return getInventoryDependentRequests(o, mgr.GetClient(), &rdsdbaasv1alpha1.RDSInstanceList{})
+			}),
+			builder.WithPredicates(inventoryChanged()),
+		).
+		Complete(r.GracefulShutdown.reconciler(r))
 }
 
 // Code from operator-lib: https://github.com/operator-framework/operator-lib/blob/d389ad4d93a46dba047b11161b755141fc853098/handler/enqueue_annotation.go#L121
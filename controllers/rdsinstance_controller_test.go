@@ -71,6 +71,7 @@ var _ = Describe("RDSInstanceController", func() {
 					"PubliclyAccessible":                       "false",
 					"VPCSecurityGroupIDs":                      "default",
 					"LicenseModel":                             "license-included",
+					"ExtraParameters":                          `{"multiAZ":true}`,
 				},
 			},
 		}
@@ -198,6 +199,53 @@ var _ = Describe("RDSInstanceController", func() {
 					})
 				})
 
+				Context("when restore from S3 is set for engine other than MySQL", func() {
+					instanceS3 := &rdsdbaasv1alpha1.RDSInstance{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      instanceName + "-s3",
+							Namespace: testNamespace,
+						},
+						Spec: dbaasv1beta1.DBaaSInstanceSpec{
+							InventoryRef: dbaasv1beta1.NamespacedName{
+								Name:      inventoryName,
+								Namespace: testNamespace,
+							},
+							ProvisioningParameters: map[dbaasv1beta1.ProvisioningParameterType]string{
+								dbaasv1beta1.ProvisioningName:              instanceName + "-s3",
+								dbaasv1beta1.ProvisioningAvailabilityZones: "us-east-1a",
+								dbaasv1beta1.ProvisioningDatabaseType:      "postgres",
+								dbaasv1beta1.ProvisioningMachineType:       "db.t3.micro",
+								dbaasv1beta1.ProvisioningStorageGib:        "20",
+								"S3BucketName":                             "xtrabackup-bucket",
+								"S3IngestionRoleARN":                       "arn:aws:iam::123456789012:role/rds-s3-ingestion",
+								"SourceEngineVersion":                      "8.0.28",
+							},
+						},
+					}
+					BeforeEach(assertResourceCreation(instanceS3))
+					AfterEach(assertResourceDeletion(instanceS3))
+
+					It("should make Instance in error status", func() {
+						ins := &rdsdbaasv1alpha1.RDSInstance{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      instanceName + "-s3",
+								Namespace: testNamespace,
+							},
+						}
+						Eventually(func() bool {
+							if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ins), ins); err != nil {
+								return false
+							}
+							condition := apimeta.FindStatusCondition(ins.Status.Conditions, "ProvisionReady")
+							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InputError" ||
+								condition.Message != "Failed to create or update DB Instance: restore from Amazon S3 is only supported for engine mysql" {
+								return false
+							}
+							return true
+						}, timeout).Should(BeTrue())
+					})
+				})
+
 				Context("when identifier is not set for the instance", func() {
 					instanceIdentifier := &rdsdbaasv1alpha1.RDSInstance{
 						ObjectMeta: metav1.ObjectMeta{
@@ -246,6 +294,62 @@ var _ = Describe("RDSInstanceController", func() {
 					})
 				})
 
+				Context("when the DB subnet group is selected by the provisioning parameters", func() {
+					newSubnetGroupInstance := func(suffix string, parameters map[dbaasv1beta1.ProvisioningParameterType]string) *rdsdbaasv1alpha1.RDSInstance {
+						parameters[dbaasv1beta1.ProvisioningAvailabilityZones] = "us-east-1a"
+						parameters[dbaasv1beta1.ProvisioningDatabaseType] = "postgres"
+						parameters[dbaasv1beta1.ProvisioningMachineType] = "db.t3.micro"
+						parameters[dbaasv1beta1.ProvisioningStorageGib] = "20"
+						return &rdsdbaasv1alpha1.RDSInstance{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      instanceName + suffix,
+								Namespace: testNamespace,
+							},
+							Spec: dbaasv1beta1.DBaaSInstanceSpec{
+								InventoryRef: dbaasv1beta1.NamespacedName{
+									Name:      inventoryName,
+									Namespace: testNamespace,
+								},
+								ProvisioningParameters: parameters,
+							},
+						}
+					}
+					instancePrefix := newSubnetGroupInstance("-subnet-group-prefix", map[dbaasv1beta1.ProvisioningParameterType]string{
+						"DBSubnetGroupNamePrefix": "rhoda-",
+					})
+					instanceSelector := newSubnetGroupInstance("-subnet-group-selector", map[dbaasv1beta1.ProvisioningParameterType]string{
+						"DBSubnetGroupNamePrefix": "rhoda-",
+						"DBSubnetGroupSelector":   "environment=test",
+					})
+					BeforeEach(assertResourceCreation(instancePrefix))
+					AfterEach(assertResourceDeletion(instancePrefix))
+					BeforeEach(assertResourceCreation(instanceSelector))
+					AfterEach(assertResourceDeletion(instanceSelector))
+
+					assertDBSubnetGroup := func(ins *rdsdbaasv1alpha1.RDSInstance, name string) {
+						dbInstance := &rdsv1alpha1.DBInstance{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      ins.Name,
+								Namespace: testNamespace,
+							},
+						}
+						Eventually(func() *string {
+							if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dbInstance), dbInstance); err != nil {
+								return nil
+							}
+							return dbInstance.Spec.DBSubnetGroupName
+						}, timeout).Should(Equal(pointer.String(name)))
+					}
+
+					It("should select the first DB subnet group discovered by the Inventory matching the name prefix", func() {
+						assertDBSubnetGroup(instancePrefix, "rhoda-subnet-group-a")
+					})
+
+					It("should select the DB subnet group discovered by the Inventory matching the tag selector", func() {
+						assertDBSubnetGroup(instanceSelector, "rhoda-subnet-group-b")
+					})
+				})
+
 				Context("when instance identifier starts not with letter", func() {
 					instanceIdentifier := &rdsdbaasv1alpha1.RDSInstance{
 						ObjectMeta: metav1.ObjectMeta{
@@ -554,6 +658,50 @@ var _ = Describe("RDSInstanceController", func() {
 					})
 				})
 
+				Context("when region is not the region of the Inventory", func() {
+					instanceOtherRegion := &rdsdbaasv1alpha1.RDSInstance{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      instanceName + "-other-region",
+							Namespace: testNamespace,
+						},
+						Spec: dbaasv1beta1.DBaaSInstanceSpec{
+							InventoryRef: dbaasv1beta1.NamespacedName{
+								Name:      inventoryName,
+								Namespace: testNamespace,
+							},
+							ProvisioningParameters: map[dbaasv1beta1.ProvisioningParameterType]string{
+								dbaasv1beta1.ProvisioningName:         instanceName,
+								dbaasv1beta1.ProvisioningRegions:      "eu-west-1",
+								dbaasv1beta1.ProvisioningDatabaseType: "postgres",
+								dbaasv1beta1.ProvisioningMachineType:  "db.t3.micro",
+								dbaasv1beta1.ProvisioningStorageGib:   "20",
+							},
+						},
+					}
+					BeforeEach(assertResourceCreation(instanceOtherRegion))
+					AfterEach(assertResourceDeletion(instanceOtherRegion))
+
+					It("should make Instance in error status", func() {
+						ins := &rdsdbaasv1alpha1.RDSInstance{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      instanceName + "-other-region",
+								Namespace: testNamespace,
+							},
+						}
+						Eventually(func() bool {
+							if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ins), ins); err != nil {
+								return false
+							}
+							condition := apimeta.FindStatusCondition(ins.Status.Conditions, "ProvisionReady")
+							if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InputError" ||
+								condition.Message != "Failed to create or update DB Instance: value of parameter regions is invalid" {
+								return false
+							}
+							return true
+						}, timeout).Should(BeTrue())
+					})
+				})
+
 				Context("when Instance creation info is complete", func() {
 					Context("when checking the creation of the RDS DB Instance", func() {
 						It("should create RDS DB instance and Secret for user password", func() {
@@ -593,6 +741,8 @@ var _ = Describe("RDSInstanceController", func() {
 								Expect(*dbInstance.Spec.VPCSecurityGroupIDs[0]).Should(Equal("default"))
 								Expect(dbInstance.Spec.LicenseModel).ShouldNot(BeNil())
 								Expect(*dbInstance.Spec.LicenseModel).Should(Equal("license-included"))
+								Expect(dbInstance.Spec.MultiAZ).ShouldNot(BeNil())
+								Expect(*dbInstance.Spec.MultiAZ).Should(BeTrue())
 								Expect(dbInstance.Spec.AvailabilityZone).ShouldNot(BeNil())
 								Expect(*dbInstance.Spec.AvailabilityZone).Should(Equal("us-east-1a"))
 								Expect(dbInstance.Spec.DBName).ShouldNot(BeNil())
@@ -1037,32 +1187,37 @@ var _ = Describe("RDSInstanceController", func() {
 								}, timeout).Should(BeTrue())
 							},
 							Entry("available", pointer.String("available"), dbaasv1beta1.InstancePhaseReady, "True", "Ready", ""),
-							Entry("creating", pointer.String("creating"), dbaasv1beta1.InstancePhaseCreating, "Unknown", "Updating", "Updating Instance"),
-							Entry("deleting", pointer.String("deleting"), dbaasv1beta1.InstancePhaseDeleting, "Unknown", "Updating", "Updating Instance"),
-							Entry("failed", pointer.String("failed"), dbaasv1beta1.InstancePhaseFailed, "False", "Terminated", "Failed"),
-							Entry("inaccessible-encryption-credentials-recoverable", pointer.String("inaccessible-encryption-credentials-recoverable"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error"),
-							Entry("incompatible-parameters", pointer.String("incompatible-parameters"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error"),
-							Entry("restore-error", pointer.String("restore-error"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error"),
-							Entry("backing-up", pointer.String("backing-up"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("configuring-enhanced-monitoring", pointer.String("configuring-enhanced-monitoring"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("configuring-iam-database-auth", pointer.String("configuring-iam-database-auth"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("configuring-log-exports", pointer.String("configuring-log-exports"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("converting-to-vpc", pointer.String("converting-to-vpc"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("moving-to-vpc", pointer.String("moving-to-vpc"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("rebooting", pointer.String("rebooting"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("resetting-master-credentials", pointer.String("resetting-master-credentials"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("renaming", pointer.String("renaming"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("starting", pointer.String("starting"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("stopping", pointer.String("stopping"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("storage-optimization", pointer.String("storage-optimization"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("upgrading", pointer.String("upgrading"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance"),
-							Entry("inaccessible-encryption-credentials", pointer.String("inaccessible-encryption-credentials"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("incompatible-network", pointer.String("incompatible-network"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("incompatible-option-group", pointer.String("incompatible-option-group"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("incompatible-restore", pointer.String("incompatible-restore"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("insufficient-capacity", pointer.String("insufficient-capacity"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("stopped", pointer.String("stopped"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
-							Entry("storage-full", pointer.String("storage-full"), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
+							Entry("creating", pointer.String("creating"), dbaasv1beta1.InstancePhaseCreating, "Unknown", "Updating", "Updating Instance: The DB instance is being created, the DB instance is inaccessible while it is being created"),
+							Entry("delete-precheck", pointer.String("delete-precheck"), dbaasv1beta1.InstancePhaseDeleting, "Unknown", "Updating", "Updating Instance: Amazon RDS is validating that read replicas are healthy and are safe to delete"),
+							Entry("deleting", pointer.String("deleting"), dbaasv1beta1.InstancePhaseDeleting, "Unknown", "Updating", "Updating Instance: The DB instance is being deleted"),
+							Entry("failed", pointer.String("failed"), dbaasv1beta1.InstancePhaseFailed, "False", "Terminated", "Failed: The DB instance has failed and Amazon RDS can't recover it"),
+							Entry("inaccessible-encryption-credentials", pointer.String("inaccessible-encryption-credentials"), dbaasv1beta1.InstancePhaseFailed, "False", "Terminated", "Failed: The AWS KMS key used to encrypt the DB instance can't be accessed or recovered"),
+							Entry("inaccessible-encryption-credentials-recoverable", pointer.String("inaccessible-encryption-credentials-recoverable"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: The AWS KMS key used to encrypt the DB instance can't be accessed, the DB instance can be recovered if the key is reactivated"),
+							Entry("incompatible-network", pointer.String("incompatible-network"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: Amazon RDS is attempting to perform a recovery action on the DB instance but can't do so because the VPC is in a state that prevents the action from being completed"),
+							Entry("incompatible-option-group", pointer.String("incompatible-option-group"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: Amazon RDS attempted to apply an option group change but can't do so, and Amazon RDS can't roll back to the previous option group state"),
+							Entry("incompatible-parameters", pointer.String("incompatible-parameters"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: Amazon RDS can't start the DB instance because the parameters in the DB parameter group aren't compatible with the DB instance"),
+							Entry("incompatible-restore", pointer.String("incompatible-restore"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: Amazon RDS can't do a point-in-time restore of the DB instance"),
+							Entry("insufficient-capacity", pointer.String("insufficient-capacity"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: Amazon RDS can't create the DB instance because sufficient capacity isn't currently available"),
+							Entry("restore-error", pointer.String("restore-error"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: The DB instance encountered an error attempting to restore to a point-in-time or from a snapshot"),
+							Entry("storage-full", pointer.String("storage-full"), dbaasv1beta1.InstancePhaseError, "False", "BackendError", "Instance with error: The DB instance has reached its storage capacity allocation"),
+							Entry("backing-up", pointer.String("backing-up"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being backed up"),
+							Entry("configuring-activity-stream", pointer.String("configuring-activity-stream"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The database activity stream of the DB instance is being configured"),
+							Entry("configuring-enhanced-monitoring", pointer.String("configuring-enhanced-monitoring"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: Enhanced Monitoring is being enabled or disabled for the DB instance"),
+							Entry("configuring-iam-database-auth", pointer.String("configuring-iam-database-auth"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: AWS IAM database authentication is being enabled or disabled for the DB instance"),
+							Entry("configuring-log-exports", pointer.String("configuring-log-exports"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: Publishing log files to Amazon CloudWatch Logs is being enabled or disabled for the DB instance"),
+							Entry("converting-to-vpc", pointer.String("converting-to-vpc"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being moved to an Amazon VPC"),
+							Entry("maintenance", pointer.String("maintenance"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: Amazon RDS is applying a maintenance update to the DB instance"),
+							Entry("modifying", pointer.String("modifying"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being modified because of a request to modify the DB instance"),
+							Entry("moving-to-vpc", pointer.String("moving-to-vpc"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being moved to an Amazon VPC"),
+							Entry("rebooting", pointer.String("rebooting"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being rebooted"),
+							Entry("resetting-master-credentials", pointer.String("resetting-master-credentials"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The master credentials for the DB instance are being reset"),
+							Entry("renaming", pointer.String("renaming"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being renamed"),
+							Entry("starting", pointer.String("starting"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is starting"),
+							Entry("stopping", pointer.String("stopping"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The DB instance is being stopped"),
+							Entry("storage-config-upgrade", pointer.String("storage-config-upgrade"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The storage file system configuration of the DB instance is being upgraded"),
+							Entry("storage-optimization", pointer.String("storage-optimization"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: Amazon RDS is optimizing the storage of the DB instance, the DB instance is fully operational"),
+							Entry("upgrading", pointer.String("upgrading"), dbaasv1beta1.InstancePhaseUpdating, "Unknown", "Updating", "Updating Instance: The database engine version of the DB instance is being upgraded"),
+							Entry("stopped", pointer.String("stopped"), dbaasv1beta1.InstancePhaseUnknown, "False", "Stopped", "Instance stopped: The DB instance is stopped"),
 							Entry("blank", pointer.String(""), dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
 							Entry("nil", nil, dbaasv1beta1.InstancePhaseUnknown, "False", "BackendError", "Instance with error"),
 						)
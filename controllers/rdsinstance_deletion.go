@@ -0,0 +1,134 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// DeletionProtectionPolicyRetain keeps the deletion protected AWS instance of a deleted Instance
+	DeletionProtectionPolicyRetain = "retain"
+	// DeletionProtectionPolicyDelete lifts the deletion protection of the AWS instance of a deleted Instance to delete it
+	DeletionProtectionPolicyDelete = "delete"
+
+	// the deletion protection policy of the Instance, it can only retain the AWS instance deleted by the policy of the
+	// operator, not delete the AWS instance retained by it
+	deletionProtectionPolicyAnnotation = "rds.dbaas.redhat.com/deletion-protection-policy"
+
+	eventReasonDeletionProtected        = "DeletionProtected"
+	eventReasonDeletionProtectionLifted = "DeletionProtectionLifted"
+)
+
+// isDeletionProtected returns true if the DB instance of the Instance is deletion protected, the provisioning parameter
+// is checked too as the protection of the DB instance is lifted before it is deleted
+func isDeletionProtected(rdsInstance *rdsdbaasv1alpha1.RDSInstance, dbInstance *rdsv1alpha1.DBInstance) bool {
+	if dbInstance != nil && pointer.BoolDeref(dbInstance.Spec.DeletionProtection, false) {
+		return true
+	}
+	b, e := strconv.ParseBool(rdsInstance.Spec.ProvisioningParameters[deletionProtection])
+	return e == nil && b
+}
+
+// getDeletionProtectionPolicy returns the deletion protection policy of the Instance, the protected AWS instances are
+// retained unless the deletion is explicitly permitted by the operator and not narrowed by the Instance
+func (r *RDSInstanceReconciler) getDeletionProtectionPolicy(rdsInstance *rdsdbaasv1alpha1.RDSInstance) (string, error) {
+	policy := r.DeletionProtectionPolicy
+	switch policy {
+	case "":
+		policy = DeletionProtectionPolicyRetain
+	case DeletionProtectionPolicyRetain, DeletionProtectionPolicyDelete:
+	default:
+		return "", fmt.Errorf("deletion protection policy %s is invalid", policy)
+	}
+	if p, ok := rdsInstance.Annotations[deletionProtectionPolicyAnnotation]; ok {
+		switch p {
+		case DeletionProtectionPolicyRetain:
+			return DeletionProtectionPolicyRetain, nil
+		case DeletionProtectionPolicyDelete:
+		default:
+			return "", fmt.Errorf("deletion protection policy %s is invalid", p)
+		}
+	}
+	return policy, nil
+}
+
+// liftDeletionProtection disables the deletion protection of the DB instance, and returns true once it is disabled
+// in AWS so that the RDS controller can delete the AWS instance
+func (r *RDSInstanceReconciler) liftDeletionProtection(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	dbInstance *rdsv1alpha1.DBInstance) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if pointer.BoolDeref(dbInstance.Spec.DeletionProtection, false) {
+		if e := updateObject(ctx, r.Client, dbInstance, func() error {
+			dbInstance.Spec.DeletionProtection = pointer.Bool(false)
+			return nil
+		}); e != nil {
+			return false, e
+		}
+		logger.Info("Deletion protection of DB Instance lifted as permitted by the deletion protection policy")
+		if r.Recorder != nil {
+			r.Recorder.Event(rdsInstance, v1.EventTypeNormal, eventReasonDeletionProtectionLifted,
+				"Deletion protection of the DB instance lifted to delete it")
+		}
+		return false, nil
+	}
+
+	if r.GetDescribeDBInstancesAPI == nil || dbInstance.Spec.DBInstanceIdentifier == nil {
+		return true, nil
+	}
+	inventory := &rdsdbaasv1alpha1.RDSInventory{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Spec.InventoryRef.Namespace,
+		Name: rdsInstance.Spec.InventoryRef.Name}, inventory); e != nil {
+		return false, e
+	}
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		return false, e
+	}
+	describeDBInstances := r.GetDescribeDBInstancesAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	output, e := describeDBInstances.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: dbInstance.Spec.DBInstanceIdentifier,
+	})
+	if e != nil {
+		var notFound *rdstypesv2.DBInstanceNotFoundFault
+		if goerrors.As(e, &notFound) {
+			return true, nil
+		}
+		return false, e
+	}
+	for _, i := range output.DBInstances {
+		if i.DeletionProtection {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("Instance deletion protection", func() {
+	It("should check the deletion protection of the DB instance and of the provisioning parameter", func() {
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		Expect(isDeletionProtected(rdsInstance, &rdsv1alpha1.DBInstance{})).Should(BeFalse())
+
+		dbInstance := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{DeletionProtection: pointer.Bool(true)}}
+		Expect(isDeletionProtected(rdsInstance, dbInstance)).Should(BeTrue())
+
+		// the protection lifted from the DB instance is still checked from the provisioning parameter
+		rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{deletionProtection: "true"}
+		Expect(isDeletionProtected(rdsInstance, &rdsv1alpha1.DBInstance{})).Should(BeTrue())
+		Expect(isDeletionProtected(rdsInstance, nil)).Should(BeTrue())
+	})
+
+	It("should retain the protected DB instances unless the deletion is permitted", func() {
+		r := &RDSInstanceReconciler{}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		policy, err := r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyRetain))
+
+		r.DeletionProtectionPolicy = DeletionProtectionPolicyDelete
+		policy, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyDelete))
+
+		rdsInstance.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{deletionProtectionPolicyAnnotation: DeletionProtectionPolicyRetain}}
+		policy, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyRetain))
+
+		// the Instance cannot permit the deletion retained by the policy of the operator
+		r.DeletionProtectionPolicy = DeletionProtectionPolicyRetain
+		rdsInstance.Annotations[deletionProtectionPolicyAnnotation] = DeletionProtectionPolicyDelete
+		policy, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyRetain))
+		r.DeletionProtectionPolicy = ""
+		policy, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyRetain))
+		r.DeletionProtectionPolicy = DeletionProtectionPolicyDelete
+		policy, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy).Should(Equal(DeletionProtectionPolicyDelete))
+
+		rdsInstance.Annotations[deletionProtectionPolicyAnnotation] = "force"
+		_, err = r.getDeletionProtectionPolicy(rdsInstance)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// join the DB instance to the AWS Managed Microsoft AD directory with the ID, through the IAM role allowing RDS
+	// to call the AWS Directory Service
+	domain            = "Domain"
+	domainIAMRoleName = "DomainIAMRoleName"
+
+	instanceConditionDomainJoined = "DomainJoined"
+
+	instanceStatusReasonDomainJoined     = "Joined"
+	instanceStatusReasonDomainJoining    = "Joining"
+	instanceStatusReasonDomainJoinFailed = "JoinFailed"
+
+	instanceStatusMessageDomainJoined     = "DB Instance joined to domain %s"
+	instanceStatusMessageDomainJoining    = "Joining DB Instance to domain %s"
+	instanceStatusMessageDomainJoinFailed = "Failed to join DB Instance to domain %s"
+
+	domainMembershipStatusJoined = "joined"
+	domainMembershipStatusFailed = "failed"
+)
+
+var directoryIDRegex = regexp.MustCompile("^d-[0-9a-f]{10}$")
+
+// setDomainParameters sets the domain of the SQL Server and Oracle DB instances from the provisioning parameters
+func setDomainParameters(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	directoryID, hasDomain := rdsInstance.Spec.ProvisioningParameters[domain]
+	roleName, hasRoleName := rdsInstance.Spec.ProvisioningParameters[domainIAMRoleName]
+	if !hasDomain {
+		if hasRoleName {
+			return fmt.Errorf(requiredParameterErrorTemplate, domain)
+		}
+		return nil
+	}
+
+	switch generateBindingType(pointer.StringDeref(dbInstance.Spec.Engine, "")) {
+	case "sqlserver", "oracle":
+	default:
+		return fmt.Errorf("parameter %s is not supported by engine %s", domain, pointer.StringDeref(dbInstance.Spec.Engine, ""))
+	}
+	if !directoryIDRegex.MatchString(directoryID) {
+		return fmt.Errorf(invalidParameterErrorTemplate, domain)
+	}
+	if !hasRoleName || len(roleName) == 0 {
+		return fmt.Errorf(requiredParameterErrorTemplate, domainIAMRoleName)
+	}
+	dbInstance.Spec.Domain = pointer.String(directoryID)
+	dbInstance.Spec.DomainIAMRoleName = pointer.String(roleName)
+	return nil
+}
+
+// setDomainJoinedCondition reflects the domain membership of the AWS instance, the condition is removed if the
+// DB instance is not joined to a domain
+func setDomainJoinedCondition(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	if dbInstance.Spec.Domain == nil {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionDomainJoined)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionDomainJoined,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonDomainJoining,
+		Message: fmt.Sprintf(instanceStatusMessageDomainJoining, *dbInstance.Spec.Domain),
+	}
+	for _, membership := range dbInstance.Status.DomainMemberships {
+		if membership == nil || pointer.StringDeref(membership.Domain, "") != *dbInstance.Spec.Domain {
+			continue
+		}
+		name := *dbInstance.Spec.Domain
+		if membership.FQDN != nil {
+			name = *membership.FQDN
+		}
+		switch status := pointer.StringDeref(membership.Status, ""); status {
+		case domainMembershipStatusJoined:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = instanceStatusReasonDomainJoined
+			condition.Message = fmt.Sprintf(instanceStatusMessageDomainJoined, name)
+		case domainMembershipStatusFailed:
+			condition.Reason = instanceStatusReasonDomainJoinFailed
+			condition.Message = fmt.Sprintf(instanceStatusMessageDomainJoinFailed, name)
+		default:
+			condition.Message = fmt.Sprintf(instanceStatusMessageDomainJoining, name)
+			if len(status) > 0 {
+				condition.Message = fmt.Sprintf("%s: %s", condition.Message, status)
+			}
+		}
+		break
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("DBInstanceDomain", func() {
+	newInstance := func(parameters map[dbaasv1beta1.ProvisioningParameterType]string) *rdsdbaasv1alpha1.RDSInstance {
+		return &rdsdbaasv1alpha1.RDSInstance{
+			Spec: dbaasv1beta1.DBaaSInstanceSpec{ProvisioningParameters: parameters},
+		}
+	}
+
+	It("should set the domain of SQL Server instances", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Spec.Engine = pointer.String("sqlserver-ex")
+		Expect(setDomainParameters(dbInstance, newInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+			domain:            "d-1234567890",
+			domainIAMRoleName: "rds-directoryservice-access-role",
+		}))).Should(Succeed())
+		Expect(dbInstance.Spec.Domain).Should(Equal(pointer.String("d-1234567890")))
+		Expect(dbInstance.Spec.DomainIAMRoleName).Should(Equal(pointer.String("rds-directoryservice-access-role")))
+	})
+
+	It("should reject the domain of other engines and incomplete parameters", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Spec.Engine = pointer.String("postgres")
+		Expect(setDomainParameters(dbInstance, newInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+			domain:            "d-1234567890",
+			domainIAMRoleName: "role",
+		}))).ShouldNot(Succeed())
+
+		dbInstance.Spec.Engine = pointer.String("oracle-ee")
+		Expect(setDomainParameters(dbInstance, newInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+			domain: "d-1234567890",
+		}))).ShouldNot(Succeed())
+		Expect(setDomainParameters(dbInstance, newInstance(map[dbaasv1beta1.ProvisioningParameterType]string{
+			domain:            "corp.example.com",
+			domainIAMRoleName: "role",
+		}))).ShouldNot(Succeed())
+	})
+
+	It("should reflect the domain membership in the conditions", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Spec.Domain = pointer.String("d-1234567890")
+		rdsInstance := newInstance(nil)
+
+		setDomainJoinedCondition(dbInstance, rdsInstance)
+		condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionDomainJoined)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonDomainJoining))
+
+		dbInstance.Status.DomainMemberships = []*rdsv1alpha1.DomainMembership{
+			{Domain: pointer.String("d-1234567890"), FQDN: pointer.String("corp.example.com"), Status: pointer.String("joined")},
+		}
+		setDomainJoinedCondition(dbInstance, rdsInstance)
+		condition = apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionDomainJoined)
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).Should(ContainSubstring("corp.example.com"))
+
+		dbInstance.Spec.Domain = nil
+		setDomainJoinedCondition(dbInstance, rdsInstance)
+		Expect(apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionDomainJoined)).Should(BeNil())
+	})
+})
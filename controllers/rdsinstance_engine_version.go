@@ -0,0 +1,270 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// DefaultEngineVersionEndOfLifeWarning is the default time before the end of life of an engine major version its
+	// DB instances are flagged
+	DefaultEngineVersionEndOfLifeWarning = 90 * 24 * time.Hour
+
+	// the minor version upgrade proposed by the operator for the DB instance on a deprecated engine version
+	engineUpgradeProposalAnnotation = "rds.dbaas.redhat.com/engine-upgrade-proposal"
+	// the proposed engine version approved by the user, the operator then sets the EngineVersion provisioning parameter
+	// of the Instance to it, which is applied in the maintenance window unless the Instance applies it immediately
+	engineUpgradeApprovedAnnotation = "rds.dbaas.redhat.com/engine-upgrade-approved"
+
+	instanceConditionEngineVersionDeprecated = "EngineVersionDeprecated"
+
+	instanceStatusReasonEngineVersionDeprecated    = "Deprecated"
+	instanceStatusReasonEngineVersionEndOfLifeSoon = "EndOfLifeSoon"
+	instanceStatusReasonEngineVersionSupported     = "Supported"
+
+	eventReasonEngineUpgradeProposed = "EngineUpgradeProposed"
+	eventReasonEngineUpgradeApproved = "EngineUpgradeApproved"
+
+	engineVersionStatusDeprecated = "deprecated"
+
+	// the engine versions described by AWS are cached for the interval
+	engineVersionCacheInterval = 24 * time.Hour
+)
+
+var instanceEngineVersionDeprecated = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "rds_dbaas_instance_engine_version_deprecated",
+		Help: "Whether the engine version of the DB instance of an Instance is deprecated or its major version reaches its end of life soon (1) or not (0).",
+	},
+	[]string{"namespace", "instance", "engine", "engine_version"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(instanceEngineVersionDeprecated)
+}
+
+// ParseEngineVersionEndOfLife parses the end of life dates of engine major versions separated by commas, each an engine
+// and a major version followed by a date, for example "postgres:10=2023-04-17,mysql:5.7=2024-02-29"
+func ParseEngineVersionEndOfLife(value string) (map[string]time.Time, error) {
+	endOfLife := map[string]time.Time{}
+	for _, e := range strings.Split(value, ",") {
+		if e = strings.TrimSpace(e); len(e) == 0 {
+			continue
+		}
+		fields := strings.SplitN(e, "=", 2)
+		if len(fields) != 2 || !strings.Contains(fields[0], ":") {
+			return nil, fmt.Errorf("engine version end of life %q must be an engine and a major version followed by a date", e)
+		}
+		date, err := time.Parse("2006-01-02", fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("date of engine version end of life %q is invalid", e)
+		}
+		endOfLife[fields[0]] = date
+	}
+	return endOfLife, nil
+}
+
+type cachedEngineVersion struct {
+	version  *rdstypesv2.DBEngineVersion
+	cachedAt time.Time
+}
+
+// engineVersionCheck is the result of the check of the engine version of a DB instance
+type engineVersionCheck struct {
+	engine, engineVersion string
+	deprecated            bool
+	endOfLife             time.Time
+	proposal              string
+}
+
+// getEngineVersion returns the engine version described by AWS for the region of the Inventory, deprecated versions
+// included, or nil if it is not found
+func (r *RDSInstanceReconciler) getEngineVersion(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	engine, engineVersion string, now time.Time) (*rdstypesv2.DBEngineVersion, error) {
+	key := fmt.Sprintf("%s/%s/%s/%s", inventory.Namespace, inventory.Name, engine, engineVersion)
+	if v, ok := r.engineVersions.Load(key); ok && now.Before(v.(cachedEngineVersion).cachedAt.Add(engineVersionCacheInterval)) {
+		return v.(cachedEngineVersion).version, nil
+	}
+
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		return nil, e
+	}
+	describeDBEngineVersions := r.GetDescribeDBEngineVersionsAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	output, e := describeDBEngineVersions.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:        pointer.String(engine),
+		EngineVersion: pointer.String(engineVersion),
+		IncludeAll:    pointer.Bool(true),
+	})
+	if e != nil {
+		return nil, e
+	}
+	var version *rdstypesv2.DBEngineVersion
+	if output != nil && len(output.DBEngineVersions) > 0 {
+		version = &output.DBEngineVersions[0]
+	}
+	r.engineVersions.Store(key, cachedEngineVersion{version: version, cachedAt: now})
+	return version, nil
+}
+
+// getEngineUpgradeProposal returns the latest minor version the deprecated engine version can be upgraded to, the
+// major version upgrades are not proposed as they are not supported by the DB Instances
+func getEngineUpgradeProposal(version *rdstypesv2.DBEngineVersion) string {
+	var proposal string
+	for _, target := range version.ValidUpgradeTarget {
+		if !target.IsMajorVersionUpgrade && target.EngineVersion != nil {
+			// the targets are sorted by version
+			proposal = *target.EngineVersion
+		}
+	}
+	return proposal
+}
+
+// checkEngineVersion compares the engine version of the DB Instance against the status of the version in AWS and the
+// end of life of its major version, and proposes an upgrade of the deprecated versions if enabled. The approved
+// proposal is set as the EngineVersion provisioning parameter of the Instance. It returns nil if the engine version is
+// not checked.
+func (r *RDSInstanceReconciler) checkEngineVersion(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory, dbInstance *rdsv1alpha1.DBInstance, now time.Time) (*engineVersionCheck, error) {
+	logger := log.FromContext(ctx)
+
+	if r.GetDescribeDBEngineVersionsAPI == nil || dbInstance.Spec.Engine == nil || dbInstance.Spec.EngineVersion == nil {
+		return nil, nil
+	}
+	check := &engineVersionCheck{engine: *dbInstance.Spec.Engine, engineVersion: *dbInstance.Spec.EngineVersion}
+	version, e := r.getEngineVersion(ctx, inventory, check.engine, check.engineVersion, now)
+	if e != nil || version == nil {
+		return nil, e
+	}
+	check.deprecated = pointer.StringDeref(version.Status, "") == engineVersionStatusDeprecated
+	if t, ok := r.EngineVersionEndOfLife[check.engine+":"+pointer.StringDeref(version.MajorEngineVersion, "")]; ok &&
+		now.Add(r.EngineVersionEndOfLifeWarning).After(t) {
+		check.endOfLife = t
+	}
+	if check.deprecated {
+		check.proposal = getEngineUpgradeProposal(version)
+	}
+
+	proposal := rdsInstance.Annotations[engineUpgradeProposalAnnotation]
+	if approved, ok := rdsInstance.Annotations[engineUpgradeApprovedAnnotation]; ok && len(proposal) > 0 && approved == proposal {
+		if e := updateObject(ctx, r.Client, rdsInstance, func() error {
+			if rdsInstance.Spec.ProvisioningParameters == nil {
+				rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{}
+			}
+			rdsInstance.Spec.ProvisioningParameters[engineVersion] = approved
+			delete(rdsInstance.Annotations, engineUpgradeProposalAnnotation)
+			delete(rdsInstance.Annotations, engineUpgradeApprovedAnnotation)
+			return nil
+		}); e != nil {
+			return nil, e
+		}
+		logger.Info("Approved engine version upgrade of DB Instance set", "engineVersion", approved)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(rdsInstance, v1.EventTypeNormal, eventReasonEngineUpgradeApproved,
+				"Upgrade of the DB instance from engine version %s to %s approved", check.engineVersion, approved)
+		}
+		return check, nil
+	}
+
+	var setProposal func() error
+	switch {
+	case r.ProposeEngineUpgrades && len(check.proposal) > 0 && proposal != check.proposal:
+		setProposal = func() error {
+			if rdsInstance.Annotations == nil {
+				rdsInstance.Annotations = map[string]string{}
+			}
+			rdsInstance.Annotations[engineUpgradeProposalAnnotation] = check.proposal
+			return nil
+		}
+	case len(check.proposal) == 0 && len(proposal) > 0:
+		setProposal = func() error {
+			delete(rdsInstance.Annotations, engineUpgradeProposalAnnotation)
+			return nil
+		}
+	default:
+		return check, nil
+	}
+	if e := updateObject(ctx, r.Client, rdsInstance, setProposal); e != nil {
+		return nil, e
+	}
+	if len(check.proposal) > 0 && r.Recorder != nil {
+		r.Recorder.Eventf(rdsInstance, v1.EventTypeWarning, eventReasonEngineUpgradeProposed,
+			"Upgrade of the DB instance from deprecated engine version %s to %s proposed, set annotation %s to %s to approve it",
+			check.engineVersion, check.proposal, engineUpgradeApprovedAnnotation, check.proposal)
+	}
+	return check, nil
+}
+
+// setEngineVersionDeprecatedCondition sets the EngineVersionDeprecated condition and metric of the Instance from the
+// check of its engine version, they are kept if the engine version is not checked
+func setEngineVersionDeprecatedCondition(rdsInstance *rdsdbaasv1alpha1.RDSInstance, check *engineVersionCheck) {
+	if check == nil {
+		return
+	}
+	condition := metav1.Condition{
+		Type:    instanceConditionEngineVersionDeprecated,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonEngineVersionSupported,
+		Message: fmt.Sprintf("Engine version %s %s is supported", check.engine, check.engineVersion),
+	}
+	switch {
+	case check.deprecated:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonEngineVersionDeprecated
+		condition.Message = fmt.Sprintf("Engine version %s %s is deprecated", check.engine, check.engineVersion)
+		if len(check.proposal) > 0 {
+			condition.Message += fmt.Sprintf(", upgrade to %s", check.proposal)
+		}
+	case !check.endOfLife.IsZero():
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonEngineVersionEndOfLifeSoon
+		condition.Message = fmt.Sprintf("Major version of engine version %s %s reaches its end of life on %s, upgrade to a later major version",
+			check.engine, check.engineVersion, check.endOfLife.Format("2006-01-02"))
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+
+	instanceEngineVersionDeprecated.DeletePartialMatch(prometheus.Labels{"namespace": rdsInstance.Namespace, "instance": rdsInstance.Name})
+	value := 0.0
+	if condition.Status == metav1.ConditionTrue {
+		value = 1
+	}
+	instanceEngineVersionDeprecated.WithLabelValues(rdsInstance.Namespace, rdsInstance.Name, check.engine, check.engineVersion).Set(value)
+}
+
+// deleteEngineVersionDeprecatedMetric removes the engine version metric of a deleted Instance
+func deleteEngineVersionDeprecatedMetric(rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	instanceEngineVersionDeprecated.DeletePartialMatch(prometheus.Labels{"namespace": rdsInstance.Namespace, "instance": rdsInstance.Name})
+}
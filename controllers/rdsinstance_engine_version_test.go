@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance engine version", func() {
+	It("should parse the end of life of the engine major versions", func() {
+		endOfLife, err := ParseEngineVersionEndOfLife("postgres:10=2023-04-17, mysql:5.7=2024-02-29")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(endOfLife).Should(Equal(map[string]time.Time{
+			"postgres:10": time.Date(2023, 4, 17, 0, 0, 0, 0, time.UTC),
+			"mysql:5.7":   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		}))
+
+		_, err = ParseEngineVersionEndOfLife("postgres=2023-04-17")
+		Expect(err).Should(HaveOccurred())
+		_, err = ParseEngineVersionEndOfLife("postgres:10=April")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should propose the latest minor version upgrade", func() {
+		Expect(getEngineUpgradeProposal(&rdstypesv2.DBEngineVersion{
+			ValidUpgradeTarget: []rdstypesv2.UpgradeTarget{
+				{EngineVersion: pointer.String("13.7")},
+				{EngineVersion: pointer.String("13.8")},
+				{EngineVersion: pointer.String("14.5"), IsMajorVersionUpgrade: true},
+			},
+		})).Should(Equal("13.8"))
+		Expect(getEngineUpgradeProposal(&rdstypesv2.DBEngineVersion{})).Should(BeEmpty())
+	})
+
+	It("should flag the deprecated and end of life engine versions", func() {
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+		setEngineVersionDeprecatedCondition(rdsInstance, nil)
+		Expect(rdsInstance.Status.Conditions).Should(BeEmpty())
+
+		setEngineVersionDeprecatedCondition(rdsInstance, &engineVersionCheck{engine: "postgres", engineVersion: "13.3",
+			deprecated: true, proposal: "13.8"})
+		condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionEngineVersionDeprecated)
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonEngineVersionDeprecated))
+		Expect(condition.Message).Should(Equal("Engine version postgres 13.3 is deprecated, upgrade to 13.8"))
+
+		setEngineVersionDeprecatedCondition(rdsInstance, &engineVersionCheck{engine: "postgres", engineVersion: "10.21",
+			endOfLife: time.Date(2023, 4, 17, 0, 0, 0, 0, time.UTC)})
+		condition = apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionEngineVersionDeprecated)
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonEngineVersionEndOfLifeSoon))
+
+		setEngineVersionDeprecatedCondition(rdsInstance, &engineVersionCheck{engine: "postgres", engineVersion: "14.6"})
+		condition = apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionEngineVersionDeprecated)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		deleteEngineVersionDeprecatedMetric(rdsInstance)
+	})
+})
@@ -0,0 +1,136 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the annotations of the Instance with the ARN of its AWS instance and the command importing the AWS instance in
+	// Terraform, to hand off the AWS instance to IaC tooling without recreating it
+	awsResourceARNAnnotation  = "rds.dbaas.redhat.com/aws-resource-arn"
+	terraformImportAnnotation = "rds.dbaas.redhat.com/terraform-import"
+
+	// the AWS instance of the Instance is tagged as externally managed if true, it is retained in AWS once the
+	// Instance is deleted and it is not adopted by the Inventory
+	externallyManagedAnnotation = "rds.dbaas.redhat.com/externally-managed"
+
+	managedByTagKey           = "rds.dbaas.redhat.com/managed-by"
+	managedByTagValueExternal = "external"
+
+	instanceInfoTerraformImport      = "iac.terraformImport"
+	instanceInfoCloudFormationImport = "iac.cloudFormationImport"
+)
+
+var (
+	terraformNameInvalidChars      = regexp.MustCompile("[^a-zA-Z0-9_-]")
+	cloudFormationLogicalIDInvalid = regexp.MustCompile("[^a-zA-Z0-9]")
+)
+
+// isExternallyManaged returns true if the AWS instance of the Instance is handed off to IaC tooling
+func isExternallyManaged(rdsInstance *rdsdbaasv1alpha1.RDSInstance) bool {
+	b, e := strconv.ParseBool(rdsInstance.Annotations[externallyManagedAnnotation])
+	return e == nil && b
+}
+
+// isExternallyManagedTagged returns true if the AWS tags have the tag of the externally managed instances
+func isExternallyManagedTagged(tags []rdstypesv2.Tag) bool {
+	for _, tag := range tags {
+		if pointer.StringDeref(tag.Key, "") == managedByTagKey && pointer.StringDeref(tag.Value, "") == managedByTagValueExternal {
+			return true
+		}
+	}
+	return false
+}
+
+// setExternallyManagedTag tags the DB instance as externally managed if the Instance is handed off to IaC tooling
+func setExternallyManagedTag(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	if !isExternallyManaged(rdsInstance) {
+		return
+	}
+	dbInstance.Spec.Tags = append(dbInstance.Spec.Tags, &rdsv1alpha1.Tag{
+		Key:   pointer.String(managedByTagKey),
+		Value: pointer.String(managedByTagValueExternal),
+	})
+}
+
+// getTerraformImportCommand returns the command importing the DB instance in the Terraform resource named after the Instance
+func getTerraformImportCommand(name, dbInstanceIdentifier string) string {
+	return fmt.Sprintf("terraform import aws_db_instance.%s %s", terraformNameInvalidChars.ReplaceAllString(name, "_"),
+		dbInstanceIdentifier)
+}
+
+// getCloudFormationImportResources returns the resources to import of the CloudFormation change set importing the
+// DB instance in the resource named after the Instance
+func getCloudFormationImportResources(name, dbInstanceIdentifier string) string {
+	var logicalID strings.Builder
+	for _, part := range cloudFormationLogicalIDInvalid.Split(name, -1) {
+		if len(part) > 0 {
+			logicalID.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	resources, _ := json.Marshal([]map[string]interface{}{{
+		"ResourceType":       "AWS::RDS::DBInstance",
+		"LogicalResourceId":  logicalID.String(),
+		"ResourceIdentifier": map[string]string{"DBInstanceIdentifier": dbInstanceIdentifier},
+	}})
+	return string(resources)
+}
+
+// setIaCImportInstanceInfo adds the import snippets of the DB instance of the Instance to its status
+func setIaCImportInstanceInfo(rdsInstance *rdsdbaasv1alpha1.RDSInstance, dbInstance *rdsv1alpha1.DBInstance) {
+	if dbInstance.Spec.DBInstanceIdentifier == nil || rdsInstance.Status.InstanceInfo == nil {
+		return
+	}
+	rdsInstance.Status.InstanceInfo[instanceInfoTerraformImport] = getTerraformImportCommand(rdsInstance.Name, *dbInstance.Spec.DBInstanceIdentifier)
+	rdsInstance.Status.InstanceInfo[instanceInfoCloudFormationImport] = getCloudFormationImportResources(rdsInstance.Name, *dbInstance.Spec.DBInstanceIdentifier)
+}
+
+// setIaCImportAnnotations sets the annotations of the Instance with the ARN and the Terraform import command of its
+// DB instance once the DB instance is created in AWS
+func (r *RDSInstanceReconciler) setIaCImportAnnotations(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	dbInstance *rdsv1alpha1.DBInstance) error {
+	if dbInstance.Spec.DBInstanceIdentifier == nil ||
+		dbInstance.Status.ACKResourceMetadata == nil || dbInstance.Status.ACKResourceMetadata.ARN == nil {
+		return nil
+	}
+	arn := string(*dbInstance.Status.ACKResourceMetadata.ARN)
+	command := getTerraformImportCommand(rdsInstance.Name, *dbInstance.Spec.DBInstanceIdentifier)
+	if rdsInstance.Annotations[awsResourceARNAnnotation] == arn && rdsInstance.Annotations[terraformImportAnnotation] == command {
+		return nil
+	}
+	return updateObject(ctx, r.Client, rdsInstance, func() error {
+		if rdsInstance.Annotations == nil {
+			rdsInstance.Annotations = map[string]string{}
+		}
+		rdsInstance.Annotations[awsResourceARNAnnotation] = arn
+		rdsInstance.Annotations[terraformImportAnnotation] = command
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("Instance IaC import hints", func() {
+	It("should report the import snippets of the DB instance", func() {
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Name: "orders-db.v2"}}
+		rdsInstance.Status.InstanceInfo = map[string]string{}
+		dbInstance := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{DBInstanceIdentifier: pointer.String("rhoda-postgres-1234")}}
+		setIaCImportInstanceInfo(rdsInstance, dbInstance)
+
+		Expect(rdsInstance.Status.InstanceInfo[instanceInfoTerraformImport]).
+			Should(Equal("terraform import aws_db_instance.orders-db_v2 rhoda-postgres-1234"))
+		Expect(rdsInstance.Status.InstanceInfo[instanceInfoCloudFormationImport]).
+			Should(MatchJSON(`[{"ResourceType":"AWS::RDS::DBInstance","LogicalResourceId":"OrdersDbV2","ResourceIdentifier":{"DBInstanceIdentifier":"rhoda-postgres-1234"}}]`))
+	})
+
+	It("should tag the DB instance of an externally managed Instance", func() {
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		setExternallyManagedTag(dbInstance, rdsInstance)
+		Expect(dbInstance.Spec.Tags).Should(BeEmpty())
+
+		rdsInstance.Annotations = map[string]string{externallyManagedAnnotation: "true"}
+		Expect(isExternallyManaged(rdsInstance)).Should(BeTrue())
+		setExternallyManagedTag(dbInstance, rdsInstance)
+		Expect(dbInstance.Spec.Tags).Should(HaveLen(1))
+
+		var tags []rdstypesv2.Tag
+		for _, t := range dbInstance.Spec.Tags {
+			tags = append(tags, rdstypesv2.Tag{Key: t.Key, Value: t.Value})
+		}
+		Expect(isExternallyManagedTagged(tags)).Should(BeTrue())
+		Expect(isExternallyManagedTagged([]rdstypesv2.Tag{{Key: pointer.String(managedByTagKey), Value: pointer.String("operator")}})).Should(BeFalse())
+	})
+})
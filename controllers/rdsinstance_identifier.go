@@ -0,0 +1,160 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	goerrors "errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// DBInstanceIdentifierStrategyUUID generates the identifiers of the DB instances from the prefix, the engine and a UUID
+	DBInstanceIdentifierStrategyUUID = "uuid"
+	// DBInstanceIdentifierStrategyName generates the identifiers of the DB instances from the prefix, the namespace and
+	// the name of the Instance, and a hash of the namespace and the name keeping them unique once truncated
+	DBInstanceIdentifierStrategyName = "name"
+
+	DefaultDBInstanceIdentifierPrefix = "rhoda"
+
+	maxDBInstanceIdentifierLength       = 63
+	maxDBInstanceIdentifierPrefixLength = 20
+	dbInstanceIdentifierHashLength      = 8
+	// the number of identifiers generated for an Instance before giving up on finding one not in use
+	maxDBInstanceIdentifierAttempts = 5
+
+	dbInstanceIdentifierKey = ".spec.dbInstanceIdentifier"
+)
+
+var (
+	dbInstanceIdentifierInvalidChars = regexp.MustCompile("[^a-z0-9]+")
+	dbInstanceIdentifierPrefixRegex  = regexp.MustCompile("^[a-z](-?[a-z0-9]+)*$")
+)
+
+// validateDBInstanceIdentifierOptions checks the identifier strategy and the prefix of the reconciler
+func (r *RDSInstanceReconciler) validateDBInstanceIdentifierOptions() error {
+	switch r.DBInstanceIdentifierStrategy {
+	case "", DBInstanceIdentifierStrategyUUID, DBInstanceIdentifierStrategyName:
+	default:
+		return fmt.Errorf("DB instance identifier strategy %s not supported", r.DBInstanceIdentifierStrategy)
+	}
+	if len(r.DBInstanceIdentifierPrefix) > 0 && (len(r.DBInstanceIdentifierPrefix) > maxDBInstanceIdentifierPrefixLength ||
+		!dbInstanceIdentifierPrefixRegex.MatchString(r.DBInstanceIdentifierPrefix)) {
+		return fmt.Errorf("DB instance identifier prefix %s not valid", r.DBInstanceIdentifierPrefix)
+	}
+	return nil
+}
+
+// newDBInstanceIdentifier generates the identifier of a new DB instance with the identifier strategy of the reconciler.
+// The generated identifiers already used by a DB Instance of the Inventory namespace or by an AWS DB instance of the
+// region are skipped.
+func (r *RDSInstanceReconciler) newDBInstanceIdentifier(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, secret *v1.Secret) (string, error) {
+	logger := log.FromContext(ctx)
+
+	prefix := r.DBInstanceIdentifierPrefix
+	if len(prefix) == 0 {
+		prefix = DefaultDBInstanceIdentifierPrefix
+	}
+	for attempt := 0; attempt < maxDBInstanceIdentifierAttempts; attempt++ {
+		var id string
+		if r.DBInstanceIdentifierStrategy == DBInstanceIdentifierStrategyName {
+			id = generateDBInstanceIdentifier(prefix, rdsInstance.Namespace, rdsInstance.Name, attempt)
+		} else {
+			id = fmt.Sprintf("%s-%s%s", prefix, getDBEngineAbbreviation(dbInstance.Spec.Engine), string(uuid.NewUUID()))
+		}
+		inUse, e := r.isDBInstanceIdentifierInUse(ctx, dbInstance.Namespace, id, secret)
+		if e != nil {
+			return "", e
+		}
+		if !inUse {
+			return id, nil
+		}
+		logger.Info("Generated DB Instance identifier already in use", "identifier", id)
+	}
+	return "", fmt.Errorf("no DB instance identifier not in use found after %d attempts", maxDBInstanceIdentifierAttempts)
+}
+
+// generateDBInstanceIdentifier returns the identifier of the name strategy, the prefix, the namespace and the name are
+// truncated so that the identifier with the hash is at most 63 characters long
+func generateDBInstanceIdentifier(prefix, namespace, name string, attempt int) string {
+	hashInput := fmt.Sprintf("%s/%s", namespace, name)
+	if attempt > 0 {
+		hashInput = fmt.Sprintf("%s/%d", hashInput, attempt)
+	}
+	sum := sha256.Sum256([]byte(hashInput))
+	hash := hex.EncodeToString(sum[:])[:dbInstanceIdentifierHashLength]
+
+	base := dbInstanceIdentifierInvalidChars.ReplaceAllString(strings.ToLower(fmt.Sprintf("%s-%s-%s", prefix, namespace, name)), "-")
+	base = strings.Trim(base, "-")
+	if len(base) == 0 || base[0] < 'a' || base[0] > 'z' {
+		base = "db-" + base
+	}
+	if maxLength := maxDBInstanceIdentifierLength - dbInstanceIdentifierHashLength - 1; len(base) > maxLength {
+		base = strings.TrimRight(base[:maxLength], "-")
+	}
+	return fmt.Sprintf("%s-%s", base, hash)
+}
+
+// isDBInstanceIdentifierInUse checks whether a DB Instance of the namespace or an AWS DB instance of the region already
+// has the identifier
+func (r *RDSInstanceReconciler) isDBInstanceIdentifierInUse(ctx context.Context, namespace, id string, secret *v1.Secret) (bool, error) {
+	dbInstanceList := &rdsv1alpha1.DBInstanceList{}
+	if e := r.List(ctx, dbInstanceList, client.InNamespace(namespace), client.MatchingFields{dbInstanceIdentifierKey: id}); e != nil {
+		return false, e
+	}
+	if len(dbInstanceList.Items) > 0 {
+		return true, nil
+	}
+
+	if r.GetDescribeDBInstancesAPI == nil {
+		return false, nil
+	}
+	describeDBInstances := r.GetDescribeDBInstancesAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	output, e := describeDBInstances.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: &id})
+	if e != nil {
+		var notFound *rdstypesv2.DBInstanceNotFoundFault
+		if goerrors.As(e, &notFound) {
+			return false, nil
+		}
+		return false, e
+	}
+	return output != nil && len(output.DBInstances) > 0, nil
+}
+
+func indexDBInstanceIdentifier(rawObj client.Object) []string {
+	dbInstance := rawObj.(*rdsv1alpha1.DBInstance)
+	if dbInstance.Spec.DBInstanceIdentifier == nil {
+		return nil
+	}
+	return []string{*dbInstance.Spec.DBInstanceIdentifier}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/utils/pointer"
+
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DBInstanceIdentifier", func() {
+	validIdentifier := regexp.MustCompile("^[a-z](-?[a-z0-9]+)*$")
+
+	It("should generate the identifier from the prefix, the namespace and the name", func() {
+		id := generateDBInstanceIdentifier("rhoda", "my-namespace", "my_instance", 0)
+		Expect(strings.HasPrefix(id, "rhoda-my-namespace-my-instance-")).Should(BeTrue())
+		Expect(validIdentifier.MatchString(id)).Should(BeTrue())
+		Expect(generateDBInstanceIdentifier("rhoda", "my-namespace", "my_instance", 0)).Should(Equal(id))
+		Expect(generateDBInstanceIdentifier("rhoda", "my-namespace", "my_instance", 1)).ShouldNot(Equal(id))
+	})
+
+	It("should truncate the identifier of long names", func() {
+		name := strings.Repeat("instance-", 10)
+		id := generateDBInstanceIdentifier("rhoda", "my-namespace", name, 0)
+		Expect(len(id)).Should(BeNumerically("<=", 63))
+		Expect(validIdentifier.MatchString(id)).Should(BeTrue())
+		Expect(generateDBInstanceIdentifier("rhoda", "my-namespace", name+"2", 0)).ShouldNot(Equal(id))
+	})
+
+	It("should validate the identifier options", func() {
+		Expect((&RDSInstanceReconciler{}).validateDBInstanceIdentifierOptions()).Should(Succeed())
+		Expect((&RDSInstanceReconciler{DBInstanceIdentifierStrategy: "name", DBInstanceIdentifierPrefix: "db"}).validateDBInstanceIdentifierOptions()).Should(Succeed())
+		Expect((&RDSInstanceReconciler{DBInstanceIdentifierStrategy: "random"}).validateDBInstanceIdentifierOptions()).ShouldNot(Succeed())
+		Expect((&RDSInstanceReconciler{DBInstanceIdentifierPrefix: "0db"}).validateDBInstanceIdentifierOptions()).ShouldNot(Succeed())
+	})
+
+	It("should index the DB Instances by identifier", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		Expect(indexDBInstanceIdentifier(dbInstance)).Should(BeEmpty())
+		dbInstance.Spec.DBInstanceIdentifier = pointer.String("rhoda-postgres-1")
+		Expect(indexDBInstanceIdentifier(dbInstance)).Should(Equal([]string{"rhoda-postgres-1"}))
+	})
+})
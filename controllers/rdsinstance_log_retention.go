@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the days the engine logs of the DB instance are retained, set with the parameter of the engine in a DB parameter
+	// group of the Instance, the parameters of the RDSParameterGroup of the Instance are kept
+	logRetentionDays = "LogRetentionDays"
+
+	// the retention of the PostgreSQL logs in minutes
+	postgresLogRetentionPeriod = "rds.log_retention_period"
+
+	minLogRetentionDays = 1
+	maxLogRetentionDays = 7
+
+	logRetentionParameterGroupSuffix = "-log-retention"
+)
+
+// getLogRetentionParameters returns the engine parameters retaining the logs for the days of the Instance
+func getLogRetentionParameters(engine string, value string) (map[string]string, error) {
+	days, e := strconv.Atoi(value)
+	if e != nil || days < minLogRetentionDays || days > maxLogRetentionDays {
+		return nil, fmt.Errorf(invalidParameterErrorTemplate, logRetentionDays)
+	}
+	switch engine {
+	case postgres:
+		return map[string]string{postgresLogRetentionPeriod: strconv.Itoa(days * 24 * 60)}, nil
+	default:
+		return nil, fmt.Errorf("parameter %s is not supported for engine %s", logRetentionDays, engine)
+	}
+}
+
+// getDefaultParameterGroupFamily returns the DB parameter group family of the engine version, for example postgres14
+// for 14.6 and postgres9.6 for 9.6.24
+func getDefaultParameterGroupFamily(engine, engineVersion string) (string, error) {
+	fields := strings.Split(engineVersion, ".")
+	major, e := strconv.Atoi(fields[0])
+	if e != nil || len(fields) < 2 {
+		return "", fmt.Errorf("DB parameter group family of %s %s is unknown", engine, engineVersion)
+	}
+	if engine == postgres && major >= 10 {
+		return fmt.Sprintf("%s%d", engine, major), nil
+	}
+	return fmt.Sprintf("%s%d.%s", engine, major, fields[1]), nil
+}
+
+// setLogRetentionParameterGroup creates or updates the RDSParameterGroup of the Instance with the log retention
+// parameters, and sets it on the DB Instance once it is ready
+func (r *RDSInstanceReconciler) setLogRetentionParameterGroup(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	engine := pointer.StringDeref(dbInstance.Spec.Engine, "")
+	retention, e := getLogRetentionParameters(engine, rdsInstance.Spec.ProvisioningParameters[logRetentionDays])
+	if e != nil {
+		return e
+	}
+
+	var family string
+	parameters := map[string]string{}
+	if name, ok := rdsInstance.Spec.ProvisioningParameters[rdsParameterGroup]; ok {
+		referenced := &rdsdbaasv1alpha1.RDSParameterGroup{}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Namespace, Name: name}, referenced); e != nil {
+			if errors.IsNotFound(e) {
+				return fmt.Errorf("RDSParameterGroup %s not found", name)
+			}
+			return e
+		}
+		if referenced.Spec.InventoryRef != rdsInstance.Spec.InventoryRef {
+			return fmt.Errorf("RDSParameterGroup %s is not of Inventory %s/%s", name, rdsInstance.Spec.InventoryRef.Namespace,
+				rdsInstance.Spec.InventoryRef.Name)
+		}
+		family = referenced.Spec.Family
+		for k, v := range referenced.Spec.Parameters {
+			parameters[k] = v
+		}
+	} else if family, e = getDefaultParameterGroupFamily(engine, pointer.StringDeref(dbInstance.Spec.EngineVersion, "")); e != nil {
+		return e
+	}
+	for k, v := range retention {
+		parameters[k] = v
+	}
+
+	parameterGroup := &rdsdbaasv1alpha1.RDSParameterGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rdsInstance.Name + logRetentionParameterGroupSuffix,
+			Namespace: rdsInstance.Namespace,
+		},
+	}
+	if _, e := createOrApply(ctx, r.Client, parameterGroup, func(existing client.Object) error {
+		parameterGroup.Spec.InventoryRef = rdsInstance.Spec.InventoryRef
+		parameterGroup.Spec.ParameterGroupName = *dbInstance.Spec.DBInstanceIdentifier + logRetentionParameterGroupSuffix
+		parameterGroup.Spec.Family = family
+		if existing != nil {
+			// the family of the DB parameter group cannot be changed once it is created
+			parameterGroup.Spec.Family = existing.(*rdsdbaasv1alpha1.RDSParameterGroup).Spec.Family
+		}
+		parameterGroup.Spec.Description = fmt.Sprintf("Log retention of RDSInstance %s/%s", rdsInstance.Namespace, rdsInstance.Name)
+		parameterGroup.Spec.Parameters = parameters
+		return ctrl.SetControllerReference(rdsInstance, parameterGroup, r.Scheme)
+	}); e != nil {
+		return e
+	}
+
+	if !apimeta.IsStatusConditionTrue(parameterGroup.Status.Conditions, parameterGroupConditionReady) {
+		return fmt.Errorf("RDSParameterGroup %s is not ready", parameterGroup.Name)
+	}
+	dbInstance.Spec.DBParameterGroupName = pointer.String(getDBParameterGroupName(parameterGroup))
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instance log retention", func() {
+	It("should translate the log retention days into engine parameters", func() {
+		parameters, err := getLogRetentionParameters(postgres, "3")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(parameters).Should(Equal(map[string]string{postgresLogRetentionPeriod: "4320"}))
+
+		_, err = getLogRetentionParameters(postgres, "8")
+		Expect(err).Should(MatchError("value of parameter LogRetentionDays is invalid"))
+		_, err = getLogRetentionParameters(postgres, "one")
+		Expect(err).Should(HaveOccurred())
+		_, err = getLogRetentionParameters("mysql", "3")
+		Expect(err).Should(MatchError("parameter LogRetentionDays is not supported for engine mysql"))
+	})
+
+	It("should derive the DB parameter group family from the engine version", func() {
+		family, err := getDefaultParameterGroupFamily(postgres, "14.6")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(family).Should(Equal("postgres14"))
+
+		family, err = getDefaultParameterGroupFamily(postgres, "9.6.24")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(family).Should(Equal("postgres9.6"))
+
+		_, err = getDefaultParameterGroupFamily(postgres, "")
+		Expect(err).Should(HaveOccurred())
+	})
+})
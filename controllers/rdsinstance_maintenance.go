@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// apply the modifications causing a downtime of the DB instance immediately (true), or in its next maintenance
+	// window (false, the default)
+	applyImmediately = "ApplyImmediately"
+
+	instanceConditionModificationsDeferred = "ModificationsDeferred"
+
+	instanceStatusReasonModificationsDeferred = "MaintenanceWindow"
+
+	instanceStatusMessageModificationsDeferred = "Modifications of %s cause a downtime and are deferred until the maintenance window starts at %s"
+
+	eventReasonDowntimeModification = "DowntimeModification"
+)
+
+// the fields of the spec of the DB Instance whose modification restarts the DB instance
+var disruptiveModifications = []struct {
+	name  string
+	field func(spec *rdsv1alpha1.DBInstanceSpec) **string
+}{
+	{"dbInstanceClass", func(spec *rdsv1alpha1.DBInstanceSpec) **string { return &spec.DBInstanceClass }},
+	{"engineVersion", func(spec *rdsv1alpha1.DBInstanceSpec) **string { return &spec.EngineVersion }},
+	{"storageType", func(spec *rdsv1alpha1.DBInstanceSpec) **string { return &spec.StorageType }},
+}
+
+// maintenanceWindow is the weekly maintenance window of a DB instance, in minutes from the start of the week in UTC
+type maintenanceWindow struct {
+	start, end int
+}
+
+const minutesPerWeek = 7 * 24 * 60
+
+// parseMaintenanceWindow parses the maintenance window of a DB instance in the format ddd:hh24:mi-ddd:hh24:mi,
+// for example sun:05:00-sun:06:00
+func parseMaintenanceWindow(value string) (maintenanceWindow, error) {
+	parse := func(s string) (int, error) {
+		fields := strings.Split(strings.ToLower(s), ":")
+		if len(fields) != 3 {
+			return 0, fmt.Errorf("maintenance window %s is invalid", value)
+		}
+		day, ok := cronDayNames[fields[0]]
+		hour, hourErr := strconv.Atoi(fields[1])
+		minute, minuteErr := strconv.Atoi(fields[2])
+		if !ok || hourErr != nil || minuteErr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+			return 0, fmt.Errorf("maintenance window %s is invalid", value)
+		}
+		return day*24*60 + hour*60 + minute, nil
+	}
+	bounds := strings.Split(value, "-")
+	if len(bounds) != 2 {
+		return maintenanceWindow{}, fmt.Errorf("maintenance window %s is invalid", value)
+	}
+	start, err := parse(bounds[0])
+	if err != nil {
+		return maintenanceWindow{}, err
+	}
+	end, err := parse(bounds[1])
+	if err != nil {
+		return maintenanceWindow{}, err
+	}
+	return maintenanceWindow{start: start, end: end}, nil
+}
+
+// nextStart returns zero if the maintenance window is in progress, or the time it starts next otherwise
+func (w maintenanceWindow) nextStart(now time.Time) time.Time {
+	now = now.UTC()
+	minute := int(now.Weekday())*24*60 + now.Hour()*60 + now.Minute()
+	elapsed := (minute - w.start + minutesPerWeek) % minutesPerWeek
+	if elapsed < (w.end-w.start+minutesPerWeek)%minutesPerWeek {
+		return time.Time{}
+	}
+	return now.Truncate(time.Minute).Add(time.Duration(minutesPerWeek-elapsed) * time.Minute)
+}
+
+// deferDisruptiveModifications keeps the values of the created DB Instance for the modifications causing a downtime
+// until its maintenance window unless the Instance applies them immediately, as the RDS controller applies all the
+// modifications immediately. It returns the deferred fields and the start of the maintenance window.
+func (r *RDSInstanceReconciler) deferDisruptiveModifications(dbInstance, existingDBInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, now time.Time) ([]string, time.Time, error) {
+	if existingDBInstance == nil {
+		return nil, time.Time{}, nil
+	}
+
+	var modified []string
+	for _, m := range disruptiveModifications {
+		if current := *m.field(&existingDBInstance.Spec); current != nil && pointer.StringDeref(*m.field(&dbInstance.Spec), *current) != *current &&
+			!isOnlineModification(m.name, *current, pointer.StringDeref(*m.field(&dbInstance.Spec), *current)) {
+			modified = append(modified, m.name)
+		}
+	}
+	if len(modified) == 0 {
+		return nil, time.Time{}, nil
+	}
+
+	immediately := false
+	if value, ok := rdsInstance.Spec.ProvisioningParameters[applyImmediately]; ok {
+		b, e := strconv.ParseBool(value)
+		if e != nil {
+			return nil, time.Time{}, fmt.Errorf(invalidParameterErrorTemplate, applyImmediately)
+		}
+		immediately = b
+	}
+
+	var start time.Time
+	if value := pointer.StringDeref(existingDBInstance.Spec.PreferredMaintenanceWindow, ""); !immediately && len(value) > 0 {
+		window, e := parseMaintenanceWindow(value)
+		if e != nil {
+			return nil, time.Time{}, e
+		}
+		start = window.nextStart(now)
+	}
+	if start.IsZero() {
+		if immediately && r.Recorder != nil {
+			r.Recorder.Eventf(rdsInstance, v1.EventTypeWarning, eventReasonDowntimeModification,
+				"Modifications of %s are applied immediately and cause a downtime of the DB instance", strings.Join(modified, ","))
+		}
+		return nil, time.Time{}, nil
+	}
+
+	for _, m := range disruptiveModifications {
+		if current := *m.field(&existingDBInstance.Spec); current != nil &&
+			!isOnlineModification(m.name, *current, pointer.StringDeref(*m.field(&dbInstance.Spec), *current)) {
+			*m.field(&dbInstance.Spec) = current
+		}
+	}
+	return modified, start, nil
+}
+
+// setModificationsDeferredCondition sets the ModificationsDeferred condition of the Instance while modifications wait
+// for the maintenance window and removes it otherwise
+func setModificationsDeferredCondition(rdsInstance *rdsdbaasv1alpha1.RDSInstance, fields []string, start time.Time) {
+	if len(fields) == 0 {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionModificationsDeferred)
+		return
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:   instanceConditionModificationsDeferred,
+		Status: metav1.ConditionTrue,
+		Reason: instanceStatusReasonModificationsDeferred,
+		Message: fmt.Sprintf(instanceStatusMessageModificationsDeferred, strings.Join(fields, ","),
+			start.Format(time.RFC3339)),
+	})
+}
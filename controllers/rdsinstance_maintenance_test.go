@@ -0,0 +1,78 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("Instance maintenance window", func() {
+	// a Saturday
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	It("should find the next start of the maintenance window", func() {
+		w, err := parseMaintenanceWindow("sun:05:00-sun:06:00")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(w.nextStart(now)).Should(Equal(time.Date(2022, 10, 2, 5, 0, 0, 0, time.UTC)))
+		Expect(w.nextStart(time.Date(2022, 10, 2, 5, 30, 0, 0, time.UTC))).Should(BeZero())
+
+		// the window wraps around the end of the week
+		w, err = parseMaintenanceWindow("sat:23:30-sun:00:30")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(w.nextStart(time.Date(2022, 10, 2, 0, 10, 0, 0, time.UTC))).Should(BeZero())
+		Expect(w.nextStart(now)).Should(Equal(time.Date(2022, 10, 1, 23, 30, 0, 0, time.UTC)))
+
+		_, err = parseMaintenanceWindow("sun:25:00-sun:26:00")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should defer the modifications causing a downtime until the maintenance window", func() {
+		r := &RDSInstanceReconciler{}
+		existing := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			DBInstanceClass:            pointer.String("db.t3.micro"),
+			AllocatedStorage:           pointer.Int64(20),
+			PreferredMaintenanceWindow: pointer.String("sun:05:00-sun:06:00"),
+		}}
+		dbInstance := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			DBInstanceClass:  pointer.String("db.t3.small"),
+			AllocatedStorage: pointer.Int64(50),
+		}}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		fields, start, err := r.deferDisruptiveModifications(dbInstance, existing, rdsInstance, now)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fields).Should(Equal([]string{"dbInstanceClass"}))
+		Expect(start).Should(Equal(time.Date(2022, 10, 2, 5, 0, 0, 0, time.UTC)))
+		Expect(dbInstance.Spec.DBInstanceClass).Should(Equal(pointer.String("db.t3.micro")))
+		Expect(dbInstance.Spec.AllocatedStorage).Should(Equal(pointer.Int64(50)))
+
+		dbInstance.Spec.DBInstanceClass = pointer.String("db.t3.small")
+		rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{applyImmediately: "true"}
+		fields, _, err = r.deferDisruptiveModifications(dbInstance, existing, rdsInstance, now)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fields).Should(BeEmpty())
+		Expect(dbInstance.Spec.DBInstanceClass).Should(Equal(pointer.String("db.t3.small")))
+	})
+})
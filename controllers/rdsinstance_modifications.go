@@ -0,0 +1,113 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	instanceConditionPendingModifications = "PendingModifications"
+
+	instanceStatusReasonApplyingModifications = "Applying"
+	instanceStatusReasonMaintenanceWindow     = "MaintenanceWindow"
+
+	instanceStatusMessageApplyingModifications = "Modifications of %s are being applied to DB Instance"
+	instanceStatusMessageMaintenanceWindow     = "Modifications of %s are applied to DB Instance in the next maintenance window"
+
+	pendingModifiedValuesApplyTiming = "pendingModifiedValues.applyTiming"
+
+	applyTimingImmediate             = "immediate"
+	applyTimingNextMaintenanceWindow = "next-maintenance-window"
+)
+
+// the statuses of the DB instances applying modifications
+var dbInstanceModifyingStatuses = map[string]struct{}{
+	"modifying":                       {},
+	"upgrading":                       {},
+	"rebooting":                       {},
+	"renaming":                        {},
+	"resetting-master-credentials":    {},
+	"moving-to-vpc":                   {},
+	"converting-to-vpc":               {},
+	"configuring-enhanced-monitoring": {},
+	"configuring-iam-database-auth":   {},
+	"configuring-log-exports":         {},
+}
+
+// getPendingModifiedFields returns the sorted names of the fields of the pending modified values of the DB Instance
+func getPendingModifiedFields(dbInstance *rdsv1alpha1.DBInstance) []string {
+	if dbInstance.Status.PendingModifiedValues == nil {
+		return nil
+	}
+	b, e := json.Marshal(dbInstance.Status.PendingModifiedValues)
+	if e != nil {
+		return nil
+	}
+	values := map[string]json.RawMessage{}
+	if e := json.Unmarshal(b, &values); e != nil {
+		return nil
+	}
+	var fields []string
+	for k := range values {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// setPendingModificationsCondition reflects the pending modified values of the DB Instance and whether they are being
+// applied or wait for the maintenance window, the apply timing is reported in the Instance info too
+func setPendingModificationsCondition(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	fields := getPendingModifiedFields(dbInstance)
+	if len(fields) == 0 {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionPendingModifications)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionPendingModifications,
+		Status:  metav1.ConditionTrue,
+		Reason:  instanceStatusReasonApplyingModifications,
+		Message: fmt.Sprintf(instanceStatusMessageApplyingModifications, strings.Join(fields, ",")),
+	}
+	timing := applyTimingImmediate
+	if _, ok := dbInstanceModifyingStatuses[pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "")]; !ok {
+		timing = applyTimingNextMaintenanceWindow
+		condition.Reason = instanceStatusReasonMaintenanceWindow
+		condition.Message = fmt.Sprintf(instanceStatusMessageMaintenanceWindow, strings.Join(fields, ","))
+		if window := pointer.StringDeref(dbInstance.Spec.PreferredMaintenanceWindow, ""); len(window) > 0 {
+			condition.Message = fmt.Sprintf("%s %s", condition.Message, window)
+		}
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+
+	if rdsInstance.Status.InstanceInfo == nil {
+		rdsInstance.Status.InstanceInfo = map[string]string{}
+	}
+	rdsInstance.Status.InstanceInfo[pendingModifiedValuesApplyTiming] = timing
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("Instance pending modifications", func() {
+	It("should report the pending modified values being applied", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Status.DBInstanceStatus = pointer.String("modifying")
+		dbInstance.Status.PendingModifiedValues = &rdsv1alpha1.PendingModifiedValues{
+			EngineVersion:   pointer.String("14.6"),
+			DBInstanceClass: pointer.String("db.t3.small"),
+		}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		setPendingModificationsCondition(dbInstance, rdsInstance)
+		c := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionPendingModifications)
+		Expect(c).ShouldNot(BeNil())
+		Expect(c.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(c.Reason).Should(Equal(instanceStatusReasonApplyingModifications))
+		Expect(c.Message).Should(ContainSubstring("dbInstanceClass,engineVersion"))
+		Expect(rdsInstance.Status.InstanceInfo[pendingModifiedValuesApplyTiming]).Should(Equal(applyTimingImmediate))
+	})
+
+	It("should report the pending modified values waiting for the maintenance window", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Spec.PreferredMaintenanceWindow = pointer.String("sun:05:00-sun:06:00")
+		dbInstance.Status.DBInstanceStatus = pointer.String("available")
+		dbInstance.Status.PendingModifiedValues = &rdsv1alpha1.PendingModifiedValues{AllocatedStorage: pointer.Int64(100)}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		setPendingModificationsCondition(dbInstance, rdsInstance)
+		c := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionPendingModifications)
+		Expect(c).ShouldNot(BeNil())
+		Expect(c.Reason).Should(Equal(instanceStatusReasonMaintenanceWindow))
+		Expect(c.Message).Should(ContainSubstring("sun:05:00-sun:06:00"))
+		Expect(rdsInstance.Status.InstanceInfo[pendingModifiedValuesApplyTiming]).Should(Equal(applyTimingNextMaintenanceWindow))
+
+		dbInstance.Status.PendingModifiedValues = &rdsv1alpha1.PendingModifiedValues{}
+		setPendingModificationsCondition(dbInstance, rdsInstance)
+		Expect(apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionPendingModifications)).Should(BeNil())
+	})
+})
@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	// the DB subnet group of the default VPC, used by the DB instances created without a DB subnet group
+	defaultDBSubnetGroupName = "default"
+
+	// the availability zones the DB subnet group spans at least for the standby DB instances of Multi-AZ deployments
+	multiAZInstanceMinAvailabilityZones = 2
+	multiAZClusterMinAvailabilityZones  = 3
+
+	instanceStatusReasonInvalidNetwork = "InvalidNetwork"
+)
+
+// invalidNetworkError is returned for the DB subnet groups and VPC security groups the DB instance can not be created in
+type invalidNetworkError struct {
+	message string
+}
+
+func (e *invalidNetworkError) Error() string {
+	return e.message
+}
+
+// getMinAvailabilityZones returns the number of availability zones the DB subnet group of the DB instance spans at
+// least, zero for the Single-AZ deployments
+func getMinAvailabilityZones(dbInstance *rdsv1alpha1.DBInstance, rdsInstance *rdsdbaasv1alpha1.RDSInstance) int {
+	if isMultiAZClusterDeployment(rdsInstance) {
+		return multiAZClusterMinAvailabilityZones
+	}
+	if pointer.BoolDeref(dbInstance.Spec.MultiAZ, false) {
+		return multiAZInstanceMinAvailabilityZones
+	}
+	return 0
+}
+
+// getSubnetGroupAvailabilityZones returns the sorted availability zones of the subnets of the DB subnet group
+func getSubnetGroupAvailabilityZones(subnetGroup rdstypesv2.DBSubnetGroup) []string {
+	zones := map[string]struct{}{}
+	for _, subnet := range subnetGroup.Subnets {
+		if subnet.SubnetAvailabilityZone != nil && subnet.SubnetAvailabilityZone.Name != nil {
+			zones[*subnet.SubnetAvailabilityZone.Name] = struct{}{}
+		}
+	}
+	var names []string
+	for zone := range zones {
+		names = append(names, zone)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateNetwork validates that the DB subnet group of a DB instance to create spans the availability zones of its
+// deployment and that its VPC security groups are in the VPC of the DB subnet group, as AWS rejects the creation with
+// an InvalidParameterCombination error otherwise
+func (r *RDSInstanceReconciler) validateNetwork(ctx context.Context, dbInstance *rdsv1alpha1.DBInstance,
+	rdsInstance *rdsdbaasv1alpha1.RDSInstance, secret *v1.Secret) error {
+	minZones := getMinAvailabilityZones(dbInstance, rdsInstance)
+	if r.GetDescribeDBSubnetGroupsAPI == nil || (dbInstance.Spec.DBSubnetGroupName == nil && minZones == 0) {
+		return nil
+	}
+	name := pointer.StringDeref(dbInstance.Spec.DBSubnetGroupName, defaultDBSubnetGroupName)
+
+	accessKey := string(secret.Data[awsAccessKeyID])
+	secretKey := string(secret.Data[awsSecretAccessKey])
+	region := string(secret.Data[awsRegion])
+
+	describeDBSubnetGroups := r.GetDescribeDBSubnetGroupsAPI(accessKey, secretKey, region)
+	output, e := describeDBSubnetGroups.DescribeDBSubnetGroups(ctx, &rds.DescribeDBSubnetGroupsInput{
+		DBSubnetGroupName: pointer.String(name),
+	})
+	if e != nil {
+		var notFoundErr *rdstypesv2.DBSubnetGroupNotFoundFault
+		if goerrors.As(e, &notFoundErr) {
+			if dbInstance.Spec.DBSubnetGroupName == nil {
+				// the account has no default VPC, AWS reports the missing DB subnet group
+				return nil
+			}
+			return &invalidNetworkError{message: fmt.Sprintf("DB subnet group %s is not found", name)}
+		}
+		return e
+	}
+	if output == nil || len(output.DBSubnetGroups) == 0 {
+		return nil
+	}
+	subnetGroup := output.DBSubnetGroups[0]
+
+	if zones := getSubnetGroupAvailabilityZones(subnetGroup); len(zones) < minZones {
+		return &invalidNetworkError{message: fmt.Sprintf("DB subnet group %s spans %d availability zones (%s), "+
+			"Multi-AZ deployments require subnets in at least %d availability zones", name, len(zones),
+			strings.Join(zones, ","), minZones)}
+	}
+
+	if len(dbInstance.Spec.VPCSecurityGroupIDs) == 0 || subnetGroup.VpcId == nil || r.GetDescribeSecurityGroupsAPI == nil {
+		return nil
+	}
+	var groupIDs []string
+	for _, id := range dbInstance.Spec.VPCSecurityGroupIDs {
+		if id != nil {
+			groupIDs = append(groupIDs, *id)
+		}
+	}
+	describeSecurityGroups := r.GetDescribeSecurityGroupsAPI(accessKey, secretKey, region)
+	sgOutput, e := describeSecurityGroups.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: groupIDs,
+	})
+	if e != nil {
+		return e
+	}
+	found := map[string]bool{}
+	for _, sg := range sgOutput.SecurityGroups {
+		if sg.VpcId == nil || *sg.VpcId != *subnetGroup.VpcId {
+			return &invalidNetworkError{message: fmt.Sprintf("VPC security group %s is not in the VPC %s of DB subnet group %s",
+				pointer.StringDeref(sg.GroupId, ""), *subnetGroup.VpcId, name)}
+		}
+		found[pointer.StringDeref(sg.GroupId, "")] = true
+	}
+	// the VPC security groups not resolved can not be checked against the VPC of the DB subnet group
+	for _, id := range groupIDs {
+		if !found[id] {
+			return &invalidNetworkError{message: fmt.Sprintf("VPC security group %s is not found", id)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	nodeRoleWorkerLabel       = "node-role.kubernetes.io/worker"
+	nodeRoleMasterLabel       = "node-role.kubernetes.io/master"
+	nodeRoleControlPlaneLabel = "node-role.kubernetes.io/control-plane"
+
+	instanceConditionZonalPlacement = "ZonalPlacement"
+
+	instanceStatusReasonClusterZone = "ClusterZone"
+	instanceStatusReasonDefaultZone = "DefaultZone"
+
+	instanceStatusMessageClusterZone = "Availability zone %s selected as %d worker nodes of the cluster are in it"
+	instanceStatusMessageDefaultZone = "Availability zone %s selected as no worker node of the cluster is in an availability zone of region %s"
+)
+
+// getNodeZone returns the availability zone of the node from its topology labels
+func getNodeZone(node metav1.PartialObjectMetadata) string {
+	if zone, ok := node.Labels[v1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[v1.LabelFailureDomainBetaZone]
+}
+
+// getWorkerZones returns the number of worker nodes in each availability zone of the region, the nodes that are not
+// control plane nodes are the workers if no node has the worker role
+func getWorkerZones(nodes []metav1.PartialObjectMetadata, region string) map[string]int {
+	isZoneOfRegion := func(zone string) bool {
+		if azs, ok := availabilityZones[region]; ok {
+			for _, az := range azs {
+				if az == zone {
+					return true
+				}
+			}
+			return false
+		}
+		return len(zone) > len(region) && strings.HasPrefix(zone, region)
+	}
+
+	hasWorkerRole := false
+	for _, node := range nodes {
+		if _, ok := node.Labels[nodeRoleWorkerLabel]; ok {
+			hasWorkerRole = true
+			break
+		}
+	}
+	zones := map[string]int{}
+	for _, node := range nodes {
+		if hasWorkerRole {
+			if _, ok := node.Labels[nodeRoleWorkerLabel]; !ok {
+				continue
+			}
+		} else {
+			_, master := node.Labels[nodeRoleMasterLabel]
+			_, controlPlane := node.Labels[nodeRoleControlPlaneLabel]
+			if master || controlPlane {
+				continue
+			}
+		}
+		if zone := getNodeZone(node); isZoneOfRegion(zone) {
+			zones[zone]++
+		}
+	}
+	return zones
+}
+
+// selectWorkerZone returns the availability zone with the most worker nodes, the first one in alphabetical order if
+// several zones have as many workers, or an empty string if no worker node is in the region
+func selectWorkerZone(zones map[string]int) (string, int) {
+	var names []string
+	for zone := range zones {
+		names = append(names, zone)
+	}
+	sort.Strings(names)
+	selected, workers := "", 0
+	for _, zone := range names {
+		if zones[zone] > workers {
+			selected, workers = zone, zones[zone]
+		}
+	}
+	return selected, workers
+}
+
+// getPreferredAvailabilityZone returns an availability zone of the region where the cluster has worker nodes to reduce
+// the cross zone data transfer between the cluster and the DB instance, and the default availability zone of the
+// region if the cluster has no worker node in the region. The selection is reported in the ZonalPlacement condition.
+func (r *RDSInstanceReconciler) getPreferredAvailabilityZone(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	region string) (*string, error) {
+	nodes := &metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("NodeList"))
+	if e := r.List(ctx, nodes); e != nil {
+		return nil, fmt.Errorf("failed to list the nodes of the cluster for selecting the availability zone: %v", e)
+	}
+
+	if zone, workers := selectWorkerZone(getWorkerZones(nodes.Items, region)); len(zone) > 0 {
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionZonalPlacement,
+			Status:  metav1.ConditionTrue,
+			Reason:  instanceStatusReasonClusterZone,
+			Message: fmt.Sprintf(instanceStatusMessageClusterZone, zone, workers),
+		})
+		return pointer.String(zone), nil
+	}
+
+	az := getDefaultAvailabilityZone(region)
+	if az != nil {
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionZonalPlacement,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonDefaultZone,
+			Message: fmt.Sprintf(instanceStatusMessageDefaultZone, *az, region),
+		})
+	}
+	return az, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Instance zonal placement", func() {
+	node := func(zone string, roles ...string) metav1.PartialObjectMetadata {
+		labels := map[string]string{v1.LabelTopologyZone: zone}
+		for _, role := range roles {
+			labels[role] = ""
+		}
+		return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	}
+
+	It("should count the worker nodes in the availability zones of the region", func() {
+		zones := getWorkerZones([]metav1.PartialObjectMetadata{
+			node("us-east-1a", nodeRoleMasterLabel),
+			node("us-east-1b", nodeRoleWorkerLabel),
+			node("us-east-1b", nodeRoleWorkerLabel),
+			node("us-east-1c", nodeRoleWorkerLabel),
+			node("us-east-1d"),
+			node("us-west-2a", nodeRoleWorkerLabel),
+		}, "us-east-1")
+		Expect(zones).Should(Equal(map[string]int{"us-east-1b": 2, "us-east-1c": 1}))
+
+		zones = getWorkerZones([]metav1.PartialObjectMetadata{
+			node("us-east-1a", nodeRoleControlPlaneLabel),
+			node("us-east-1c"),
+		}, "us-east-1")
+		Expect(zones).Should(Equal(map[string]int{"us-east-1c": 1}))
+	})
+
+	It("should select the availability zone with the most worker nodes", func() {
+		zone, workers := selectWorkerZone(map[string]int{"us-east-1c": 2, "us-east-1b": 2, "us-east-1a": 1})
+		Expect(zone).Should(Equal("us-east-1b"))
+		Expect(workers).Should(Equal(2))
+		zone, _ = selectWorkerZone(nil)
+		Expect(zone).Should(BeEmpty())
+	})
+})
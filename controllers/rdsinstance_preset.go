@@ -0,0 +1,129 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// ProvisioningPresetsConfigMapName is the ConfigMap in the namespace of an Inventory holding the provisioning presets
+	// of its Instances, each key is the name of a preset and its value a YAML map of provisioning parameters, e.g.
+	// "prod: {MachineType: db.r6g.large, StorageGib: '100', DeletionProtection: 'true'}"
+	ProvisioningPresetsConfigMapName = "rds-dbaas-provisioning-presets"
+
+	// the provisioning parameter selecting the preset of an Instance
+	provisioningPreset = "Preset"
+
+	// the preset applied to the provisioning parameters of the Instance
+	presetAppliedAnnotation = "rds.dbaas.redhat.com/preset-applied"
+
+	eventReasonPresetApplied = "PresetApplied"
+)
+
+// getProvisioningPreset returns the provisioning parameters of the preset of the Inventory namespace
+func (r *RDSInstanceReconciler) getProvisioningPreset(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	name string) (map[dbaasv1beta1.ProvisioningParameterType]string, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	cm := &v1.ConfigMap{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: ProvisioningPresetsConfigMapName}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("preset %s not found, ConfigMap %s/%s does not exist", name, inventory.Namespace,
+				ProvisioningPresetsConfigMapName)
+		}
+		return nil, err
+	}
+	value, ok := cm.Data[name]
+	if !ok {
+		return nil, fmt.Errorf("preset %s not found in ConfigMap %s/%s", name, inventory.Namespace, ProvisioningPresetsConfigMapName)
+	}
+	preset := map[dbaasv1beta1.ProvisioningParameterType]string{}
+	if err := yaml.Unmarshal([]byte(value), &preset); err != nil {
+		return nil, fmt.Errorf("preset %s of ConfigMap %s/%s is invalid: %v", name, inventory.Namespace,
+			ProvisioningPresetsConfigMapName, err)
+	}
+	return preset, nil
+}
+
+// mergeProvisioningPreset sets the provisioning parameters of the preset that the Instance does not set, the values of
+// the Instance override the ones of the preset, and returns the parameters that are set
+func mergeProvisioningPreset(rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	preset map[dbaasv1beta1.ProvisioningParameterType]string) []string {
+	if rdsInstance.Spec.ProvisioningParameters == nil {
+		rdsInstance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{}
+	}
+	var merged []string
+	for k, v := range preset {
+		// a preset does not select another preset
+		if k == provisioningPreset {
+			continue
+		}
+		if _, ok := rdsInstance.Spec.ProvisioningParameters[k]; !ok {
+			rdsInstance.Spec.ProvisioningParameters[k] = v
+			merged = append(merged, string(k))
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// applyProvisioningPreset writes the provisioning parameters of the preset selected by the Instance to its spec once,
+// so that its DB instance is not modified by the later changes of the preset and the parameters applied are visible
+// in the Instance. It returns whether the Instance is updated.
+func (r *RDSInstanceReconciler) applyProvisioningPreset(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory) (bool, error) {
+	name, ok := rdsInstance.Spec.ProvisioningParameters[provisioningPreset]
+	if !ok || len(name) == 0 || rdsInstance.Annotations[presetAppliedAnnotation] == name {
+		return false, nil
+	}
+
+	preset, err := r.getProvisioningPreset(ctx, inventory, name)
+	if err != nil {
+		return false, err
+	}
+	var merged []string
+	if err := updateObject(ctx, r.Client, rdsInstance, func() error {
+		merged = mergeProvisioningPreset(rdsInstance, preset)
+		if rdsInstance.Annotations == nil {
+			rdsInstance.Annotations = map[string]string{}
+		}
+		rdsInstance.Annotations[presetAppliedAnnotation] = name
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Provisioning preset applied to Instance", "preset", name, "parameters", merged)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(rdsInstance, v1.EventTypeNormal, eventReasonPresetApplied,
+			"Provisioning parameters %v of preset %s applied", merged, name)
+	}
+	return true, nil
+}
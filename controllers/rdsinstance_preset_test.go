@@ -0,0 +1,68 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance provisioning preset", func() {
+	It("should override the preset with the provisioning parameters of the Instance", func() {
+		instance := &rdsdbaasv1alpha1.RDSInstance{}
+		instance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{
+			provisioningPreset:                   "prod",
+			dbaasv1beta1.ProvisioningMachineType: "db.m6g.large",
+		}
+		merged := mergeProvisioningPreset(instance, map[dbaasv1beta1.ProvisioningParameterType]string{
+			dbaasv1beta1.ProvisioningMachineType: "db.r6g.large",
+			dbaasv1beta1.ProvisioningStorageGib:  "100",
+			deletionProtection:                   "true",
+			provisioningPreset:                   "dev",
+		})
+		Expect(merged).Should(Equal([]string{string(deletionProtection), string(dbaasv1beta1.ProvisioningStorageGib)}))
+		Expect(instance.Spec.ProvisioningParameters).Should(Equal(map[dbaasv1beta1.ProvisioningParameterType]string{
+			provisioningPreset:                   "prod",
+			dbaasv1beta1.ProvisioningMachineType: "db.m6g.large",
+			dbaasv1beta1.ProvisioningStorageGib:  "100",
+			deletionProtection:                   "true",
+		}))
+	})
+
+	It("should apply the preset once", func() {
+		r := &RDSInstanceReconciler{}
+		instance := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{presetAppliedAnnotation: "prod"},
+		}}
+		instance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{provisioningPreset: "prod"}
+		updated, err := r.applyProvisioningPreset(context.TODO(), instance, &rdsdbaasv1alpha1.RDSInventory{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).Should(BeFalse())
+
+		delete(instance.Spec.ProvisioningParameters, provisioningPreset)
+		updated, err = r.applyProvisioningPreset(context.TODO(), instance, &rdsdbaasv1alpha1.RDSInventory{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updated).Should(BeFalse())
+	})
+})
@@ -0,0 +1,159 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	instanceConditionRecommendations = "RecommendationsAvailable"
+
+	instanceStatusReasonPendingMaintenanceActions = "PendingMaintenanceActions"
+	instanceStatusReasonNoRecommendations         = "NoRecommendations"
+
+	instanceStatusMessageNoRecommendations = "No recommended actions for the DB instance"
+
+	eventReasonRecommendation = "Recommendation"
+
+	// the recommended actions of the DB instances are synced at most once per interval
+	recommendationsSyncInterval = time.Hour
+)
+
+// formatRecommendation returns the description of the recommended action with the date it is applied at
+func formatRecommendation(action rdstypesv2.PendingMaintenanceAction) string {
+	description := pointer.StringDeref(action.Description, "")
+	if len(description) == 0 {
+		description = pointer.StringDeref(action.Action, "")
+	}
+	switch {
+	case action.CurrentApplyDate != nil:
+		return fmt.Sprintf("%s (applied after %s)", description, action.CurrentApplyDate.UTC().Format(time.RFC3339))
+	case action.ForcedApplyDate != nil:
+		return fmt.Sprintf("%s (forced after %s)", description, action.ForcedApplyDate.UTC().Format(time.RFC3339))
+	case action.AutoAppliedAfterDate != nil:
+		return fmt.Sprintf("%s (auto-applied after %s)", description, action.AutoAppliedAfterDate.UTC().Format(time.RFC3339))
+	default:
+		return description
+	}
+}
+
+// getRecommendations returns the recommended actions of the AWS resource, sorted by description
+func (r *RDSInstanceReconciler) getRecommendations(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory,
+	arn string) ([]string, error) {
+	secret := &v1.Secret{}
+	if e := getInventoryCredentials(ctx, r, inventory, secret); e != nil {
+		return nil, e
+	}
+	describePendingMaintenanceActions := r.GetDescribePendingMaintenanceActionsAPI(string(secret.Data[awsAccessKeyID]),
+		string(secret.Data[awsSecretAccessKey]), string(secret.Data[awsRegion]))
+	input := &rds.DescribePendingMaintenanceActionsInput{
+		ResourceIdentifier: pointer.String(arn),
+	}
+	var recommendations []string
+	for {
+		output, e := describePendingMaintenanceActions.DescribePendingMaintenanceActions(ctx, input)
+		if e != nil {
+			return nil, e
+		}
+		if output == nil {
+			break
+		}
+		for _, resource := range output.PendingMaintenanceActions {
+			for _, action := range resource.PendingMaintenanceActionDetails {
+				recommendations = append(recommendations, formatRecommendation(action))
+			}
+		}
+		if output.Marker == nil || len(*output.Marker) == 0 {
+			break
+		}
+		input.Marker = output.Marker
+	}
+	sort.Strings(recommendations)
+	return recommendations, nil
+}
+
+// syncRecommendationsCondition sets the RecommendationsAvailable condition of the Instance from the pending maintenance
+// actions recommended by RDS for its AWS resource, such as engine minor version and operating system upgrades, and
+// records an event for each new recommendation. It returns the time of the next sync.
+func (r *RDSInstanceReconciler) syncRecommendationsCondition(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	inventory *rdsdbaasv1alpha1.RDSInventory, arn string, now time.Time) (time.Time, error) {
+	if r.GetDescribePendingMaintenanceActionsAPI == nil || len(arn) == 0 {
+		return time.Time{}, nil
+	}
+	key := client.ObjectKeyFromObject(rdsInstance).String()
+	if t, ok := r.recommendationsSyncTimes.Load(key); ok && now.Before(t.(time.Time).Add(recommendationsSyncInterval)) &&
+		apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionRecommendations) != nil {
+		return t.(time.Time).Add(recommendationsSyncInterval), nil
+	}
+
+	recommendations, e := r.getRecommendations(ctx, inventory, arn)
+	if e != nil {
+		return time.Time{}, e
+	}
+	r.recommendationsSyncTimes.Store(key, now)
+
+	if len(recommendations) == 0 {
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionRecommendations,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonNoRecommendations,
+			Message: instanceStatusMessageNoRecommendations,
+		})
+		return now.Add(recommendationsSyncInterval), nil
+	}
+
+	var previous string
+	if condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionRecommendations); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		previous = condition.Message
+	}
+	if r.Recorder != nil {
+		for _, recommendation := range recommendations {
+			if !strings.Contains(previous, recommendation) {
+				r.Recorder.Eventf(rdsInstance, v1.EventTypeWarning, eventReasonRecommendation,
+					"Recommended action for the DB instance: %s", recommendation)
+			}
+		}
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    instanceConditionRecommendations,
+		Status:  metav1.ConditionTrue,
+		Reason:  instanceStatusReasonPendingMaintenanceActions,
+		Message: strings.Join(recommendations, "; "),
+	})
+	return now.Add(recommendationsSyncInterval), nil
+}
+
+// deleteRecommendationsSyncTime forgets the last sync of the recommended actions of the deleted Instance
+func (r *RDSInstanceReconciler) deleteRecommendationsSyncTime(rdsInstance *rdsdbaasv1alpha1.RDSInstance) {
+	r.recommendationsSyncTimes.Delete(client.ObjectKeyFromObject(rdsInstance).String())
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance recommendations", func() {
+	It("should describe the recommended actions with the date they are applied at", func() {
+		forced := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+		Expect(formatRecommendation(rdstypesv2.PendingMaintenanceAction{
+			Action:          pointer.String("db-upgrade"),
+			Description:     pointer.String("Upgrade to deprecated minor version replacement 14.6"),
+			ForcedApplyDate: &forced,
+		})).Should(Equal("Upgrade to deprecated minor version replacement 14.6 (forced after 2022-11-01T00:00:00Z)"))
+		Expect(formatRecommendation(rdstypesv2.PendingMaintenanceAction{
+			Action: pointer.String("system-update"),
+		})).Should(Equal("system-update"))
+	})
+
+	It("should not sync the recommended actions without the AWS API", func() {
+		r := &RDSInstanceReconciler{}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		next, err := r.syncRecommendationsCondition(context.TODO(), rdsInstance, &rdsdbaasv1alpha1.RDSInventory{},
+			"arn:aws:rds:us-east-1:123456789012:db:test", time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(next).Should(BeZero())
+		Expect(rdsInstance.Status.Conditions).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	dbInstanceStatusStorageFull = "storage-full"
+
+	// the percentage by which the allocated storage of a storage-full DB instance is increased, 0 disables the remediation
+	storageFullRemediationPercentAnnotation = "rds.dbaas.redhat.com/storage-full-remediation-percent"
+	// the allocated storage in GiB set by the remediation, it takes precedence over a smaller AllocatedStorage parameter
+	remediatedAllocatedStorageAnnotation = "rds.dbaas.redhat.com/remediated-allocated-storage"
+	storageFullRemediatedAtAnnotation    = "rds.dbaas.redhat.com/storage-full-remediated-at"
+
+	// RDS does not accept another storage modification of a DB instance within six hours
+	storageModificationInterval = 6 * time.Hour
+
+	degradedReasonStorageFull = "StorageFull"
+
+	degradedMessageStorageFull          = "The DB instance has reached its storage capacity allocation of %d GiB"
+	degradedMessageStorageFullRemediate = "The DB instance has reached its storage capacity allocation, allocated storage increased to %d GiB"
+
+	eventReasonStorageFull      = "StorageFull"
+	eventReasonStorageIncreased = "StorageIncreased"
+)
+
+// remediateStorageFull increases the allocated storage of a storage-full DB instance if the remediation is enabled for
+// the Instance, and returns the new allocated storage or 0 if it is not increased. The increase is kept in an annotation
+// of the Instance as the spec of the DB instance is set from the provisioning parameters.
+func (r *RDSInstanceReconciler) remediateStorageFull(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	dbInstance *rdsv1alpha1.DBInstance) (int64, error) {
+	logger := log.FromContext(ctx)
+
+	if pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "") != dbInstanceStatusStorageFull ||
+		dbInstance.Spec.AllocatedStorage == nil {
+		return 0, nil
+	}
+	if dbInstance.Status.PendingModifiedValues != nil && dbInstance.Status.PendingModifiedValues.AllocatedStorage != nil {
+		// a storage modification is already in progress
+		return 0, nil
+	}
+
+	percent := r.StorageFullRemediationPercent
+	if p, ok := rdsInstance.Annotations[storageFullRemediationPercentAnnotation]; ok {
+		i, e := strconv.ParseInt(p, 10, 64)
+		if e != nil || i < 0 {
+			return 0, fmt.Errorf("value of annotation %s is invalid", storageFullRemediationPercentAnnotation)
+		}
+		percent = i
+	}
+	if percent <= 0 {
+		return 0, nil
+	}
+	if isOutpostsDBInstance(dbInstance) {
+		logger.Info("Allocated storage of storage-full DB Instance on Outposts not increased")
+		return 0, nil
+	}
+
+	if at, ok := rdsInstance.Annotations[storageFullRemediatedAtAnnotation]; ok {
+		if t, e := time.Parse(time.RFC3339, at); e == nil && time.Since(t) < storageModificationInterval {
+			return 0, nil
+		}
+	}
+
+	allocatedStorage := increaseAllocatedStorage(*dbInstance.Spec.AllocatedStorage, dbInstance.Spec.MaxAllocatedStorage, percent)
+	if allocatedStorage <= *dbInstance.Spec.AllocatedStorage {
+		logger.Info("Allocated storage of storage-full DB Instance not increased as it reached its maximum",
+			"allocatedStorage", *dbInstance.Spec.AllocatedStorage)
+		return 0, nil
+	}
+
+	if e := updateObject(ctx, r.Client, rdsInstance, func() error {
+		if rdsInstance.Annotations == nil {
+			rdsInstance.Annotations = map[string]string{}
+		}
+		rdsInstance.Annotations[remediatedAllocatedStorageAnnotation] = strconv.FormatInt(allocatedStorage, 10)
+		rdsInstance.Annotations[storageFullRemediatedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	}); e != nil {
+		return 0, e
+	}
+	logger.Info("Allocated storage of storage-full DB Instance increased", "allocatedStorage", allocatedStorage)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(rdsInstance, v1.EventTypeNormal, eventReasonStorageIncreased,
+			"Allocated storage of DB instance increased from %d GiB to %d GiB", *dbInstance.Spec.AllocatedStorage, allocatedStorage)
+	}
+	return allocatedStorage, nil
+}
+
+// increaseAllocatedStorage returns the allocated storage increased by the percentage, rounded up and capped by the
+// maximum allocated storage of the storage autoscaling if set
+func increaseAllocatedStorage(allocatedStorage int64, maxAllocatedStorage *int64, percent int64) int64 {
+	increased := allocatedStorage + (allocatedStorage*percent+99)/100
+	if maxAllocatedStorage != nil && *maxAllocatedStorage > 0 && increased > *maxAllocatedStorage {
+		increased = *maxAllocatedStorage
+	}
+	return increased
+}
+
+// setStorageFullCondition sets the Degraded condition while the DB instance is storage-full and removes it otherwise
+func (r *RDSInstanceReconciler) setStorageFullCondition(rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	dbInstance *rdsv1alpha1.DBInstance, remediatedAllocatedStorage int64) {
+	if pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "") != dbInstanceStatusStorageFull {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, conditionDegraded)
+		return
+	}
+
+	var message string
+	if remediatedAllocatedStorage > 0 {
+		message = fmt.Sprintf(degradedMessageStorageFullRemediate, remediatedAllocatedStorage)
+	} else if isOutpostsDBInstance(dbInstance) {
+		message = fmt.Sprintf(degradedMessageStorageFullOutposts, pointer.Int64Deref(dbInstance.Spec.AllocatedStorage, 0))
+	} else {
+		message = fmt.Sprintf(degradedMessageStorageFull, pointer.Int64Deref(dbInstance.Spec.AllocatedStorage, 0))
+	}
+	if r.Recorder != nil && !apimeta.IsStatusConditionTrue(rdsInstance.Status.Conditions, conditionDegraded) {
+		r.Recorder.Event(rdsInstance, v1.EventTypeWarning, eventReasonStorageFull, message)
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+		Type:    conditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  degradedReasonStorageFull,
+		Message: message,
+	})
+}
+
+// getRemediatedAllocatedStorage returns the allocated storage set by the storage-full remediation of the Instance
+func getRemediatedAllocatedStorage(rdsInstance *rdsdbaasv1alpha1.RDSInstance) int64 {
+	if s, ok := rdsInstance.Annotations[remediatedAllocatedStorageAnnotation]; ok {
+		if i, e := strconv.ParseInt(s, 10, 64); e == nil {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+var _ = Describe("StorageFullRemediation", func() {
+	It("should increase the allocated storage by the percentage", func() {
+		Expect(increaseAllocatedStorage(20, nil, 10)).Should(Equal(int64(22)))
+		Expect(increaseAllocatedStorage(25, nil, 10)).Should(Equal(int64(28)))
+	})
+
+	It("should cap the allocated storage by the maximum allocated storage", func() {
+		Expect(increaseAllocatedStorage(100, pointer.Int64(110), 20)).Should(Equal(int64(110)))
+		Expect(increaseAllocatedStorage(110, pointer.Int64(110), 20)).Should(Equal(int64(110)))
+	})
+
+	It("should set the Degraded condition while the DB instance is storage-full", func() {
+		r := &RDSInstanceReconciler{}
+		instance := &rdsdbaasv1alpha1.RDSInstance{}
+		dbInstance := &rdsv1alpha1.DBInstance{}
+		dbInstance.Spec.AllocatedStorage = pointer.Int64(20)
+		dbInstance.Status.DBInstanceStatus = pointer.String(dbInstanceStatusStorageFull)
+
+		r.setStorageFullCondition(instance, dbInstance, 0)
+		condition := apimeta.FindStatusCondition(instance.Status.Conditions, conditionDegraded)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Reason).Should(Equal(degradedReasonStorageFull))
+		Expect(condition.Message).Should(Equal("The DB instance has reached its storage capacity allocation of 20 GiB"))
+
+		dbInstance.Status.DBInstanceStatus = pointer.String("available")
+		r.setStorageFullCondition(instance, dbInstance, 0)
+		Expect(apimeta.FindStatusCondition(instance.Status.Conditions, conditionDegraded)).Should(BeNil())
+	})
+})
@@ -0,0 +1,228 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+const (
+	storageTypeGP2 = "gp2"
+	storageTypeGP3 = "gp3"
+
+	// the gp2 storage has a baseline of 3 IOPS per GiB between 100 and 16000 IOPS
+	gp2IOPSPerGiB   = 3
+	gp2MinimumIOPS  = 100
+	gp2MaximumIOPS  = 16000
+	gp3BaselineIOPS = 3000
+	// the gp3 storage of at least the threshold has a higher baseline and its IOPS can be provisioned
+	gp3BaselineThreshold                = 400
+	gp3BaselineThresholdOracle          = 200
+	gp3BaselineIOPSAboveThreshold       = 12000
+	gp3BaselineThroughput               = 125
+	gp3BaselineThroughputAboveThreshold = 500
+
+	instanceConditionStorageTypeConversion = "StorageTypeConversion"
+
+	instanceStatusReasonConversionRecommended = "Recommended"
+	instanceStatusReasonConverting            = "Converting"
+	instanceStatusReasonConverted             = "Converted"
+
+	instanceStatusMessageConversionRecommended = "Convert the gp2 storage of %d GiB to gp3 by setting StorageType to gp3: baseline of %d IOPS and %d MiB/s instead of %d IOPS"
+	instanceStatusMessageConversionSavings     = ", estimated saving of %.2f per month"
+	instanceStatusMessageConverting            = "Storage type conversion from gp2 to gp3 in progress, the DB instance remains available"
+	instanceStatusMessageConverted             = "Storage type converted from gp2 to gp3"
+)
+
+// ParseStoragePrices parses the monthly prices per GiB of the storage types, for example "gp2=0.115,gp3=0.08"
+func ParseStoragePrices(value string) (map[string]float64, error) {
+	prices := map[string]float64{}
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); len(p) == 0 {
+			continue
+		}
+		fields := strings.SplitN(p, "=", 2)
+		if len(fields) != 2 || len(fields[0]) == 0 {
+			return nil, fmt.Errorf("storage price %q must be a storage type followed by a price", p)
+		}
+		price, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("price of storage price %q is invalid", p)
+		}
+		prices[fields[0]] = price
+	}
+	return prices, nil
+}
+
+// getGP2BaselineIOPS returns the baseline IOPS of the gp2 storage
+func getGP2BaselineIOPS(allocatedStorage int64) int64 {
+	iops := allocatedStorage * gp2IOPSPerGiB
+	if iops < gp2MinimumIOPS {
+		return gp2MinimumIOPS
+	}
+	if iops > gp2MaximumIOPS {
+		return gp2MaximumIOPS
+	}
+	return iops
+}
+
+// getGP3BaselineThreshold returns the allocated storage from which the gp3 storage of the engine has the higher
+// baseline, SQL Server does not have a higher baseline
+func getGP3BaselineThreshold(engine string) int64 {
+	switch {
+	case strings.HasPrefix(engine, "sqlserver"):
+		return 0
+	case strings.HasPrefix(engine, "oracle"):
+		return gp3BaselineThresholdOracle
+	default:
+		return gp3BaselineThreshold
+	}
+}
+
+// getGP3Baseline returns the baseline IOPS and throughput of the gp3 storage of the engine
+func getGP3Baseline(engine string, allocatedStorage int64) (int64, int64) {
+	if threshold := getGP3BaselineThreshold(engine); threshold > 0 && allocatedStorage >= threshold {
+		return gp3BaselineIOPSAboveThreshold, gp3BaselineThroughputAboveThreshold
+	}
+	return gp3BaselineIOPS, gp3BaselineThroughput
+}
+
+// validateGP3IOPS checks the IOPS of the gp3 storage are only provisioned from the baseline threshold of the engine,
+// and not below the baseline
+func validateGP3IOPS(dbInstance *rdsv1alpha1.DBInstance) error {
+	if pointer.StringDeref(dbInstance.Spec.StorageType, "") != storageTypeGP3 || dbInstance.Spec.IOPS == nil {
+		return nil
+	}
+	engine := pointer.StringDeref(dbInstance.Spec.Engine, "")
+	allocatedStorage := pointer.Int64Deref(dbInstance.Spec.AllocatedStorage, 0)
+	threshold := getGP3BaselineThreshold(engine)
+	if threshold > 0 && allocatedStorage < threshold {
+		return fmt.Errorf("parameter IOPS requires gp3 storage of at least %d GiB for engine %s", threshold, engine)
+	}
+	if baseline, _ := getGP3Baseline(engine, allocatedStorage); *dbInstance.Spec.IOPS < baseline {
+		return fmt.Errorf("parameter IOPS must be at least the gp3 baseline of %d IOPS", baseline)
+	}
+	return nil
+}
+
+// isOnlineModification returns true if the modification of a field causing a downtime does not cause one, the
+// conversion of the gp2 storage to gp3 is a storage optimization of the available DB instance
+func isOnlineModification(name, current, desired string) bool {
+	return name == "storageType" && current == storageTypeGP2 && desired == storageTypeGP3
+}
+
+// getStorageConversionSaving returns the estimated monthly saving of the conversion of the gp2 storage to gp3 from the
+// prices of the storage types, the storage of a Multi-AZ DB instance is billed twice
+func getStorageConversionSaving(prices map[string]float64, allocatedStorage int64, multiAZ bool) (float64, bool) {
+	gp2, ok2 := prices[storageTypeGP2]
+	gp3, ok3 := prices[storageTypeGP3]
+	if !ok2 || !ok3 {
+		return 0, false
+	}
+	saving := (gp2 - gp3) * float64(allocatedStorage)
+	if multiAZ {
+		saving *= 2
+	}
+	return saving, true
+}
+
+// setStorageTypeConversionCondition recommends the conversion of the gp2 storage of the DB instance to gp3 in the
+// StorageTypeConversion condition of the Instance with the estimated saving, and tracks the progress of the
+// conversion once the StorageType parameter is set to gp3
+func (r *RDSInstanceReconciler) setStorageTypeConversionCondition(rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	dbInstance *rdsv1alpha1.DBInstance) {
+	storageType := pointer.StringDeref(dbInstance.Spec.StorageType, "")
+	var pendingStorageType string
+	if dbInstance.Status.PendingModifiedValues != nil {
+		pendingStorageType = pointer.StringDeref(dbInstance.Status.PendingModifiedValues.StorageType, "")
+	}
+	current := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionStorageTypeConversion)
+	// the storage type of the spec is the desired one, the conversion completes once the RDS controller synced the
+	// DB instance and the storage optimization is over
+	converting := pendingStorageType == storageTypeGP3 ||
+		pointer.StringDeref(dbInstance.Status.DBInstanceStatus, "") == "storage-optimization" ||
+		!isACKResourceSynced(dbInstance.Status.Conditions)
+
+	switch {
+	case storageType == storageTypeGP3 && current == nil:
+		// the storage of the DB instance was not converted by the Instance
+		return
+	case storageType == storageTypeGP3 && converting:
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionStorageTypeConversion,
+			Status:  metav1.ConditionTrue,
+			Reason:  instanceStatusReasonConverting,
+			Message: instanceStatusMessageConverting,
+		})
+	case storageType == storageTypeGP3:
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionStorageTypeConversion,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonConverted,
+			Message: instanceStatusMessageConverted,
+		})
+	case storageType == storageTypeGP2 && pendingStorageType != storageTypeGP3:
+		allocatedStorage := pointer.Int64Deref(dbInstance.Spec.AllocatedStorage, 0)
+		iops, throughput := getGP3Baseline(pointer.StringDeref(dbInstance.Spec.Engine, ""), allocatedStorage)
+		message := fmt.Sprintf(instanceStatusMessageConversionRecommended, allocatedStorage, iops, throughput,
+			getGP2BaselineIOPS(allocatedStorage))
+		if saving, ok := getStorageConversionSaving(r.StoragePrices, allocatedStorage,
+			pointer.BoolDeref(dbInstance.Spec.MultiAZ, false)); ok && saving > 0 {
+			message += fmt.Sprintf(instanceStatusMessageConversionSavings, saving)
+		}
+		if r.Recorder != nil && (current == nil || current.Reason != instanceStatusReasonConversionRecommended) {
+			r.Recorder.Event(rdsInstance, v1.EventTypeNormal, eventReasonRecommendation, message)
+		}
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionStorageTypeConversion,
+			Status:  metav1.ConditionFalse,
+			Reason:  instanceStatusReasonConversionRecommended,
+			Message: message,
+		})
+	case storageType == storageTypeGP2:
+		// the conversion requested by the StorageType parameter is pending
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, metav1.Condition{
+			Type:    instanceConditionStorageTypeConversion,
+			Status:  metav1.ConditionTrue,
+			Reason:  instanceStatusReasonConverting,
+			Message: instanceStatusMessageConverting,
+		})
+	default:
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionStorageTypeConversion)
+	}
+}
+
+// isACKResourceSynced returns true if the RDS controller reports the AWS resource in sync with its spec
+func isACKResourceSynced(conditions []*ackv1alpha1.Condition) bool {
+	for _, c := range conditions {
+		if c != nil && c.Type == ackv1alpha1.ConditionTypeResourceSynced {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
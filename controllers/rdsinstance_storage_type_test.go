@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+var _ = Describe("Instance storage type conversion", func() {
+	It("should parse the storage prices", func() {
+		prices, err := ParseStoragePrices("gp2=0.115, gp3=0.08")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(prices).Should(Equal(map[string]float64{"gp2": 0.115, "gp3": 0.08}))
+		_, err = ParseStoragePrices("gp2")
+		Expect(err).Should(HaveOccurred())
+		_, err = ParseStoragePrices("gp2=-1")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should return the baselines of the storage types", func() {
+		Expect(getGP2BaselineIOPS(20)).Should(BeEquivalentTo(100))
+		Expect(getGP2BaselineIOPS(100)).Should(BeEquivalentTo(300))
+		Expect(getGP2BaselineIOPS(10000)).Should(BeEquivalentTo(16000))
+		iops, throughput := getGP3Baseline("postgres", 100)
+		Expect(iops).Should(BeEquivalentTo(3000))
+		Expect(throughput).Should(BeEquivalentTo(125))
+		iops, throughput = getGP3Baseline("oracle-ee", 200)
+		Expect(iops).Should(BeEquivalentTo(12000))
+		Expect(throughput).Should(BeEquivalentTo(500))
+		iops, _ = getGP3Baseline("sqlserver-se", 1000)
+		Expect(iops).Should(BeEquivalentTo(3000))
+	})
+
+	It("should only provision the IOPS of the gp3 storage from the baseline threshold", func() {
+		dbInstance := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			Engine:           pointer.String("postgres"),
+			StorageType:      pointer.String(storageTypeGP3),
+			AllocatedStorage: pointer.Int64(100),
+			IOPS:             pointer.Int64(12000),
+		}}
+		Expect(validateGP3IOPS(dbInstance)).Should(MatchError("parameter IOPS requires gp3 storage of at least 400 GiB for engine postgres"))
+		dbInstance.Spec.AllocatedStorage = pointer.Int64(400)
+		Expect(validateGP3IOPS(dbInstance)).Should(Succeed())
+		dbInstance.Spec.IOPS = pointer.Int64(6000)
+		Expect(validateGP3IOPS(dbInstance)).Should(MatchError("parameter IOPS must be at least the gp3 baseline of 12000 IOPS"))
+	})
+
+	It("should not defer the conversion of the gp2 storage to gp3", func() {
+		r := &RDSInstanceReconciler{}
+		existing := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			StorageType:                pointer.String(storageTypeGP2),
+			DBInstanceClass:            pointer.String("db.t3.micro"),
+			PreferredMaintenanceWindow: pointer.String("sun:05:00-sun:06:00"),
+		}}
+		dbInstance := &rdsv1alpha1.DBInstance{Spec: rdsv1alpha1.DBInstanceSpec{
+			StorageType:     pointer.String(storageTypeGP3),
+			DBInstanceClass: pointer.String("db.t3.small"),
+		}}
+		modified, _, err := r.deferDisruptiveModifications(dbInstance, existing, &rdsdbaasv1alpha1.RDSInstance{},
+			time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(modified).Should(Equal([]string{"dbInstanceClass"}))
+		Expect(*dbInstance.Spec.DBInstanceClass).Should(Equal("db.t3.micro"))
+		Expect(*dbInstance.Spec.StorageType).Should(Equal(storageTypeGP3))
+	})
+
+	It("should recommend the conversion of the gp2 storage and track its progress", func() {
+		r := &RDSInstanceReconciler{StoragePrices: map[string]float64{"gp2": 0.115, "gp3": 0.08}}
+		rdsInstance := &rdsdbaasv1alpha1.RDSInstance{}
+		dbInstance := &rdsv1alpha1.DBInstance{
+			Spec: rdsv1alpha1.DBInstanceSpec{
+				Engine:           pointer.String("postgres"),
+				StorageType:      pointer.String(storageTypeGP2),
+				AllocatedStorage: pointer.Int64(100),
+			},
+		}
+		r.setStorageTypeConversionCondition(rdsInstance, dbInstance)
+		condition := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionStorageTypeConversion)
+		Expect(condition).ShouldNot(BeNil())
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonConversionRecommended))
+		Expect(condition.Message).Should(Equal("Convert the gp2 storage of 100 GiB to gp3 by setting StorageType to gp3: " +
+			"baseline of 3000 IOPS and 125 MiB/s instead of 300 IOPS, estimated saving of 3.50 per month"))
+
+		dbInstance.Spec.StorageType = pointer.String(storageTypeGP3)
+		dbInstance.Status.DBInstanceStatus = pointer.String("storage-optimization")
+		r.setStorageTypeConversionCondition(rdsInstance, dbInstance)
+		Expect(apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionStorageTypeConversion).Reason).
+			Should(Equal(instanceStatusReasonConverting))
+
+		dbInstance.Status.DBInstanceStatus = pointer.String("available")
+		dbInstance.Status.Conditions = []*ackv1alpha1.Condition{
+			{Type: ackv1alpha1.ConditionTypeResourceSynced, Status: v1.ConditionTrue},
+		}
+		r.setStorageTypeConversionCondition(rdsInstance, dbInstance)
+		Expect(apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionStorageTypeConversion).Reason).
+			Should(Equal(instanceStatusReasonConverted))
+
+		created := &rdsdbaasv1alpha1.RDSInstance{}
+		r.setStorageTypeConversionCondition(created, dbInstance)
+		Expect(created.Status.Conditions).Should(BeEmpty())
+	})
+})
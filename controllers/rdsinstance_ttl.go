@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// the time to live of the Instance from its creation, for example 72h, the Instance is deleted once it expires and
+	// its DB instance is deleted or retained as for any deleted Instance
+	instanceTTL = "TTL"
+
+	// the expiry of an Instance is warned about for the duration before it expires, at most half of its time to live
+	instanceExpiryWarning = time.Hour
+
+	instanceConditionExpiring = "Expiring"
+
+	instanceStatusReasonExpiryScheduled = "Scheduled"
+	instanceStatusReasonExpiringSoon    = "ExpiringSoon"
+	instanceStatusReasonExpired         = "Expired"
+
+	instanceStatusMessageExpiryScheduled = "Instance expires at %s and is then deleted"
+	instanceStatusMessageExpiringSoon    = "Instance expires at %s and is then deleted, remove its TTL provisioning parameter to keep it"
+	instanceStatusMessageExpired         = "Instance expired at %s and is deleted"
+
+	eventReasonExpiringSoon = "ExpiringSoon"
+	eventReasonExpired      = "Expired"
+)
+
+// getInstanceExpiry returns the time the Instance expires and the time its expiry is warned about, zero is returned if
+// the Instance does not expire
+func getInstanceExpiry(rdsInstance *rdsdbaasv1alpha1.RDSInstance) (time.Time, time.Time, error) {
+	value, ok := rdsInstance.Spec.ProvisioningParameters[instanceTTL]
+	if !ok || len(value) == 0 {
+		return time.Time{}, time.Time{}, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf(invalidParameterErrorTemplate, instanceTTL)
+	}
+	warning := instanceExpiryWarning
+	if warning > ttl/2 {
+		warning = ttl / 2
+	}
+	expiry := rdsInstance.CreationTimestamp.Add(ttl)
+	return expiry, expiry.Add(-warning), nil
+}
+
+// checkInstanceExpiry sets the Expiring condition of the Instance and warns about its expiry with an event once, it
+// returns whether the Instance is expired and when to reconcile it again
+func (r *RDSInstanceReconciler) checkInstanceExpiry(rdsInstance *rdsdbaasv1alpha1.RDSInstance,
+	now time.Time) (bool, time.Time, error) {
+	expiry, warnAt, err := getInstanceExpiry(rdsInstance)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if expiry.IsZero() {
+		apimeta.RemoveStatusCondition(&rdsInstance.Status.Conditions, instanceConditionExpiring)
+		return false, time.Time{}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:    instanceConditionExpiring,
+		Status:  metav1.ConditionFalse,
+		Reason:  instanceStatusReasonExpiryScheduled,
+		Message: fmt.Sprintf(instanceStatusMessageExpiryScheduled, expiry.UTC().Format(time.RFC3339)),
+	}
+	requeueAt := warnAt
+	switch {
+	case !now.Before(expiry):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonExpired
+		condition.Message = fmt.Sprintf(instanceStatusMessageExpired, expiry.UTC().Format(time.RFC3339))
+		apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+		return true, time.Time{}, nil
+	case !now.Before(warnAt):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = instanceStatusReasonExpiringSoon
+		condition.Message = fmt.Sprintf(instanceStatusMessageExpiringSoon, expiry.UTC().Format(time.RFC3339))
+		if c := apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionExpiring); (c == nil ||
+			c.Reason != instanceStatusReasonExpiringSoon) && r.Recorder != nil {
+			r.Recorder.Event(rdsInstance, v1.EventTypeWarning, eventReasonExpiringSoon, condition.Message)
+		}
+		requeueAt = expiry
+	}
+	apimeta.SetStatusCondition(&rdsInstance.Status.Conditions, condition)
+	return false, requeueAt, nil
+}
+
+// deleteExpiredInstance deletes the expired Instance, its DB instance is then deleted or retained by its finalizer
+func (r *RDSInstanceReconciler) deleteExpiredInstance(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance) error {
+	if err := r.Delete(ctx, rdsInstance); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	log.FromContext(ctx).Info("Expired Instance deleted")
+	if r.Recorder != nil {
+		r.Recorder.Event(rdsInstance, v1.EventTypeNormal, eventReasonExpired,
+			apimeta.FindStatusCondition(rdsInstance.Status.Conditions, instanceConditionExpiring).Message)
+	}
+	return nil
+}
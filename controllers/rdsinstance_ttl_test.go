@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance time to live", func() {
+	created := time.Date(2022, 10, 3, 8, 0, 0, 0, time.UTC)
+	newInstance := func(ttl string) *rdsdbaasv1alpha1.RDSInstance {
+		instance := &rdsdbaasv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ci", Name: "preview", CreationTimestamp: metav1.NewTime(created),
+		}}
+		instance.Spec.ProvisioningParameters = map[dbaasv1beta1.ProvisioningParameterType]string{instanceTTL: ttl}
+		return instance
+	}
+
+	It("should warn about the expiry at most half of the time to live before", func() {
+		expiry, warnAt, err := getInstanceExpiry(newInstance("72h"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(expiry).Should(Equal(created.Add(72 * time.Hour)))
+		Expect(warnAt).Should(Equal(expiry.Add(-instanceExpiryWarning)))
+
+		expiry, warnAt, err = getInstanceExpiry(newInstance("30m"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(warnAt).Should(Equal(expiry.Add(-15 * time.Minute)))
+
+		_, _, err = getInstanceExpiry(newInstance("3d"))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should set the expiry of the Instance and warn about it once", func() {
+		recorder := record.NewFakeRecorder(10)
+		r := &RDSInstanceReconciler{Recorder: recorder}
+		instance := newInstance("2h")
+
+		expired, requeueAt, err := r.checkInstanceExpiry(instance, created.Add(10*time.Minute))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(expired).Should(BeFalse())
+		Expect(requeueAt).Should(Equal(created.Add(time.Hour)))
+		condition := apimeta.FindStatusCondition(instance.Status.Conditions, instanceConditionExpiring)
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonExpiryScheduled))
+		Expect(recorder.Events).Should(BeEmpty())
+
+		for i := 0; i < 2; i++ {
+			expired, requeueAt, err = r.checkInstanceExpiry(instance, created.Add(90*time.Minute))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expired).Should(BeFalse())
+			Expect(requeueAt).Should(Equal(created.Add(2 * time.Hour)))
+		}
+		Expect(apimeta.IsStatusConditionTrue(instance.Status.Conditions, instanceConditionExpiring)).Should(BeTrue())
+		Expect(recorder.Events).Should(HaveLen(1))
+
+		expired, _, err = r.checkInstanceExpiry(instance, created.Add(2*time.Hour))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(expired).Should(BeTrue())
+		condition = apimeta.FindStatusCondition(instance.Status.Conditions, instanceConditionExpiring)
+		Expect(condition.Reason).Should(Equal(instanceStatusReasonExpired))
+	})
+
+	It("should not expire the Instances without time to live", func() {
+		r := &RDSInstanceReconciler{}
+		instance := newInstance("")
+		instance.Status.Conditions = []metav1.Condition{{Type: instanceConditionExpiring, Status: metav1.ConditionTrue}}
+		expired, requeueAt, err := r.checkInstanceExpiry(instance, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(expired).Should(BeFalse())
+		Expect(requeueAt.IsZero()).Should(BeTrue())
+		Expect(instance.Status.Conditions).Should(BeEmpty())
+	})
+})
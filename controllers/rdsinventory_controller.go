@@ -18,11 +18,15 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -35,8 +39,10 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -46,7 +52,10 @@ import (
 
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersorganizations "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/organizations"
 	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	controllersvault "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
@@ -81,9 +90,32 @@ const (
 	fieldExportCRDFile     = "services.k8s.aws_fieldexports.yaml"
 	ackDeploymentName      = "ack-rds-controller"
 
+	// the fingerprint of the credentials of the RDS controller on its pod template, the RDS controller reads them from
+	// its environment and restarts when they change, for example when the credentials of a role are assumed again
+	ackCredentialsFingerprintAnnotation = "rds.dbaas.redhat.com/credentials-fingerprint"
+
 	adoptedDBResourceLabelKey   = "rds.dbaas.redhat.com/adopted"
 	adoptedDBResourceLabelValue = "true"
 
+	// the AWS region of the inventory, shown in the printer columns of the inventory
+	inventoryRegionLabelKey = "rds.dbaas.redhat.com/region"
+
+	// read the AWS credentials from and publish the connection credentials to the paths of HashiCorp Vault, the Vault
+	// server, auth path and role are configured for the operator
+	vaultCredentialsPathAnnotation = "rds.dbaas.redhat.com/vault-credentials-path"
+	vaultConnectionsPathAnnotation = "rds.dbaas.redhat.com/vault-connections-path"
+
+	connectionFailoverAnnotation = "rds.dbaas.redhat.com/failover-time"
+	failoverEventCategory        = "failover"
+	failoverEventLookback        = 1 * time.Hour
+	eventReasonFailover          = "Failover"
+
+	// the ConfigMap of the Inventory keeping the time and the keys of the last failover events notified, the events are
+	// not notified again once the operator restarts
+	failoverEventsConfigMapSuffix = "-failover-events"
+	failoverEventsKeyLastTime     = "lastEventTime"
+	failoverEventsKeyLastEvents   = "lastEvents"
+
 	inventoryConditionReady = "SpecSynced"
 
 	inventoryStatusReasonSyncOK       = "SyncOK"
@@ -107,6 +139,9 @@ const (
 	inventoryStatusMessageInstallError             = "Failed to install %s for RDS controller"
 	inventoryStatusMessageVerifyInstallError       = "Failed to verify %s ready for RDS controller"
 	inventoryStatusMessageUninstallError           = "Failed to uninstall RDS controller"
+	inventoryStatusMessageVaultError               = "Failed to read AWS credentials from Vault"
+	inventoryStatusMessageCircuitOpen              = "AWS calls suspended after consecutive failures"
+	inventoryStatusMessageAPIBudgetExceeded        = "AWS API budget of the day spent, sync deferred"
 
 	requiredCredentialErrorTemplate = "required credential %s is missing"
 )
@@ -121,10 +156,52 @@ type RDSInventoryReconciler struct {
 	GetDescribeDBClustersPaginatorAPI  func(accessKey, secretKey, region string) controllersrds.DescribeDBClustersPaginatorAPI
 	GetModifyDBClusterAPI              func(accessKey, secretKey, region string) controllersrds.ModifyDBClusterAPI
 	GetDescribeDBClustersAPI           func(accessKey, secretKey, region string) controllersrds.DescribeDBClustersAPI
-	ACKInstallNamespace                string
-	RDSCRDFilePath                     string
-	WaitForRDSControllerRetries        int
-	WaitForRDSControllerInterval       time.Duration
+	GetDescribeEventsAPI               func(accessKey, secretKey, region string) controllersrds.DescribeEventsAPI
+	GetDescribeDBSnapshotsAPI          func(accessKey, secretKey, region string) controllersrds.DescribeDBSnapshotsAPI
+	// the DB subnet groups of the Inventories are discovered with their tags for the selection of the DB subnet groups
+	// of the Instances
+	GetDescribeDBSubnetGroupsAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBSubnetGroupsAPI
+	GetListTagsForResourceAPI    func(accessKey, secretKey, region string) controllersrds.ListTagsForResourceAPI
+	// the AWS credentials of the Inventories are read from Vault if set
+	VaultClient                  controllersvault.Client
+	CircuitBreaker               *CircuitBreaker
+	APIBudget                    *APIBudget
+	ShardedSync                  *ShardedSync
+	Recorder                     record.EventRecorder
+	ACKInstallNamespace          string
+	RDSCRDFilePath               string
+	WaitForRDSControllerRetries  int
+	WaitForRDSControllerInterval time.Duration
+	// the time the DB services of the region of an Inventory are reported once its AWS APIs fail, the default is
+	// used if not set
+	RegionOutageStalenessTTL time.Duration
+	// the sync pauses between DB resources for the reconciles of the Connections in their first bind attempt if set
+	Priority *ReconcilePriority
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the Inventories are only reconciled in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+	// the DB services of the Inventories are exported to a ConfigMap with the history of the exports at the interval
+	// if set, the default history is kept if not set
+	ExportInterval time.Duration
+	ExportHistory  int
+	// the DB services of the member accounts of the organizational unit of an Inventory are discovered with the roles
+	// assumed from the organization management role of the Inventory if set
+	GetListAccountsForParentPaginatorAPI         func(accessKey, secretKey, region, roleARN, parentID string) controllersorganizations.ListAccountsForParentPaginatorAPI
+	GetAssumeRoleDescribeDBInstancesPaginatorAPI func(accessKey, secretKey, region string, roleARNs ...string) controllersrds.DescribeDBInstancesPaginatorAPI
+	GetAssumeRoleDescribeDBClustersPaginatorAPI  func(accessKey, secretKey, region string, roleARNs ...string) controllersrds.DescribeDBClustersPaginatorAPI
+	// the DB services of the Inventories are served to the spoke clusters authenticated as the user if set, the hub
+	// publishes the TLS requirements of the DB services and grants the user the read of their master passwords only
+	SpokeUser                         string
+	GetDescribeDBParametersAPI        func(accessKey, secretKey, region string) controllersrds.DescribeDBParametersAPI
+	GetDescribeDBClusterParametersAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBClusterParametersAPI
+
+	// the time until which the failover events have been processed for each Inventory
+	lastEventTimes sync.Map
+	// the fingerprint of the credentials last read for each Inventory
+	credentialsFingerprints sync.Map
+	// the TLS requirements of the parameter groups published to the spokes by region and name
+	tlsRequirements sync.Map
 }
 
 //+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsinventories,verbs=get;list;watch;create;update;patch;delete
@@ -135,9 +212,12 @@ type RDSInventoryReconciler struct {
 //+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbclusters,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups=rds.services.k8s.aws,resources=dbclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=services.k8s.aws,resources=adoptedresources,verbs=get;list;watch;create;delete;update
-//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;delete;update
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;delete;update;patch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update
-//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsconnections,verbs=get;list;watch;create;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -146,6 +226,7 @@ type RDSInventoryReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
+	ctx = withApplyRecorder(ctx, r.Recorder)
 
 	var syncStatus, syncStatusReason, syncStatusMessage string
 	var syncReset bool
@@ -154,6 +235,10 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	var credentialsRef v1.Secret
 
 	var accessKey, secretKey, region string
+	var credentialsExpiry, roleCredentialsExpiry time.Time
+	// the total wait of the sync for the high priority reconciles is bounded
+	priorityBudget := r.Priority.newLowPriorityBudget()
+	var tagLabelMapping map[string]string
 
 	returnRequeueSyncReset := func() {
 		result = ctrl.Result{Requeue: true}
@@ -169,6 +254,56 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		syncStatusMessage = message
 	}
 
+	returnCircuitOpen := func(openUntil time.Time) {
+		result = ctrl.Result{RequeueAfter: time.Until(openUntil)}
+		err = nil
+		// the sync condition of the last sync is kept while the region is unavailable
+		if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); c != nil &&
+			isRegionDegraded(&inventory, r.RegionOutageStalenessTTL, time.Now()) {
+			syncStatus, syncStatusReason, syncStatusMessage = string(c.Status), c.Reason, c.Message
+			return
+		}
+		syncStatus = string(metav1.ConditionFalse)
+		syncStatusReason = degradedReasonCircuitOpen
+		syncStatusMessage = inventoryStatusMessageCircuitOpen
+	}
+
+	returnBudgetExceeded := func(wait time.Duration) {
+		result = ctrl.Result{RequeueAfter: wait}
+		err = nil
+		// the DB services and the sync condition of the last sync are kept until the next sync
+		if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); c != nil {
+			syncStatus, syncStatusReason, syncStatusMessage = string(c.Status), c.Reason, c.Message
+		} else {
+			syncStatus = string(metav1.ConditionFalse)
+			syncStatusReason = apiBudgetReasonExceeded
+			syncStatusMessage = inventoryStatusMessageAPIBudgetExceeded
+		}
+	}
+
+	// the DB services and the sync condition of the last sync are kept while the AWS APIs of the region are failing
+	// and until they are stale
+	returnAWSError := func(e error, reason, message string) {
+		if !isRegionOutageError(e) {
+			returnError(e, reason, message)
+			return
+		}
+		if setRegionDegraded(&inventory, region, e, r.RegionOutageStalenessTTL, time.Now()) {
+			inventory.Status.DatabaseServices = nil
+			returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageRegionStale)
+			return
+		}
+		result = ctrl.Result{Requeue: true}
+		err = nil
+		if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); c != nil {
+			syncStatus, syncStatusReason, syncStatusMessage = string(c.Status), c.Reason, c.Message
+		} else {
+			syncStatus = string(metav1.ConditionFalse)
+			syncStatusReason = reason
+			syncStatusMessage = message
+		}
+	}
+
 	returnSyncReset := func() {
 		result = ctrl.Result{}
 		err = nil
@@ -191,8 +326,11 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	updateInventoryReadyCondition := func() {
 		if syncReset {
-			apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionReady)
+			if !servesCachedSync(&inventory) {
+				apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionReady)
+			}
 		} else {
+			apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionCached)
 			condition := metav1.Condition{
 				Type:    inventoryConditionReady,
 				Status:  metav1.ConditionStatus(syncStatus),
@@ -200,8 +338,25 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				Message: syncStatusMessage,
 			}
 			apimeta.SetStatusCondition(&inventory.Status.Conditions, condition)
+			recordInventorySyncResult(inventory.Namespace, inventory.Name, syncStatus == string(metav1.ConditionTrue), syncStatusReason, err)
 		}
-		if e := r.Status().Update(ctx, &inventory); e != nil {
+		r.CircuitBreaker.setDegradedCondition(&inventory.Status.Conditions, inventory.Namespace, inventory.Name)
+		if wait := setCredentialsExpiryCondition(&inventory.Status.Conditions, credentialsExpiry, time.Now()); wait > 0 &&
+			!result.Requeue && (result.RequeueAfter == 0 || wait < result.RequeueAfter) {
+			result.RequeueAfter = wait
+		}
+		// the credentials of the role are assumed again before they expire and passed to the RDS controller
+		if !roleCredentialsExpiry.IsZero() {
+			wait := time.Until(roleCredentialsExpiry)
+			if wait < time.Second {
+				wait = time.Second
+			}
+			if !result.Requeue && (result.RequeueAfter == 0 || wait < result.RequeueAfter) {
+				result.RequeueAfter = wait
+			}
+		}
+		// the DB services and the conditions of the sync cycle are written together
+		if e := applyInventoryStatus(ctx, r.Client, &inventory); e != nil {
 			if errors.IsConflict(e) {
 				logger.Info("Inventory modified, retry reconciling")
 				result = ctrl.Result{Requeue: true}
@@ -217,8 +372,10 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	checkFinalizer := func() bool {
 		if inventory.ObjectMeta.DeletionTimestamp.IsZero() {
 			if !controllerutil.ContainsFinalizer(&inventory, inventoryFinalizer) {
-				controllerutil.AddFinalizer(&inventory, inventoryFinalizer)
-				if e := r.Update(ctx, &inventory); e != nil {
+				if e := updateObject(ctx, r.Client, &inventory, func() error {
+					controllerutil.AddFinalizer(&inventory, inventoryFinalizer)
+					return nil
+				}); e != nil {
 					if errors.IsConflict(e) {
 						logger.Info("Inventory modified, retry reconciling")
 						returnRequeueSyncReset()
@@ -278,8 +435,10 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 					return true
 				}
 
-				controllerutil.RemoveFinalizer(&inventory, inventoryFinalizer)
-				if e := r.Update(ctx, &inventory); e != nil {
+				if e := updateObject(ctx, r.Client, &inventory, func() error {
+					controllerutil.RemoveFinalizer(&inventory, inventoryFinalizer)
+					return nil
+				}); e != nil {
 					if errors.IsConflict(e) {
 						logger.Info("Inventory modified, retry reconciling")
 						returnRequeueSyncReset()
@@ -301,6 +460,12 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	validateAWSParameter := func() bool {
+		if e := r.syncVaultCredentials(ctx, &inventory); e != nil {
+			logger.Error(e, "Failed to read AWS credentials from Vault for Inventory")
+			returnError(e, inventoryStatusReasonInputError, inventoryStatusMessageVaultError)
+			return true
+		}
+
 		if e := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace,
 			Name: inventory.Spec.CredentialsRef.Name}, &credentialsRef); e != nil {
 			logger.Error(e, "Failed to get credentials reference for Inventory")
@@ -310,28 +475,106 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			returnError(e, inventoryStatusReasonBackendError, fmt.Sprintf(inventoryStatusMessageGetError, "Credential"))
 			return true
 		}
+		if e := normalizeCredentials(&credentialsRef); e != nil {
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		}
+		credentialsExpiry = getCredentialsExpiry(&credentialsRef)
+		if expiry, e := assumeInventoryRole(ctx, &credentialsRef); e != nil {
+			logger.Error(e, "Failed to assume the role of the Inventory")
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		} else {
+			roleCredentialsExpiry = expiry
+		}
+		if r.credentialsRotated(&inventory, &credentialsRef) {
+			// the AWS calls failing with the previous credentials are no longer suspended
+			logger.Info("Credentials of the Inventory rotated")
+			r.CircuitBreaker.recordSuccess(inventory.Namespace, inventory.Name)
+		}
+		requiredCredentialError := func(credential string) error {
+			if e := getCredentialKeyNameError(credentialsRef.Data, credential); e != nil {
+				return e
+			}
+			return fmt.Errorf(requiredCredentialErrorTemplate, credential)
+		}
 
+		// the static credentials are set from the role of the Inventory if it is assumed with the web identity
 		if ak, ok := credentialsRef.Data[awsAccessKeyID]; !ok || len(ak) == 0 {
-			e := fmt.Errorf(requiredCredentialErrorTemplate, awsAccessKeyID)
+			e := requiredCredentialError(awsAccessKeyID)
 			returnError(e, inventoryStatusReasonInputError, e.Error())
 			return true
 		} else {
 			accessKey = string(ak)
 		}
 		if sk, ok := credentialsRef.Data[awsSecretAccessKey]; !ok || len(sk) == 0 {
-			e := fmt.Errorf(requiredCredentialErrorTemplate, awsSecretAccessKey)
+			e := requiredCredentialError(awsSecretAccessKey)
 			returnError(e, inventoryStatusReasonInputError, e.Error())
 			return true
 		} else {
 			secretKey = string(sk)
 		}
 		if r, ok := credentialsRef.Data[awsRegion]; !ok || len(r) == 0 {
-			e := fmt.Errorf(requiredCredentialErrorTemplate, awsRegion)
+			e := requiredCredentialError(awsRegion)
 			returnError(e, inventoryStatusReasonInputError, e.Error())
 			return true
 		} else {
 			region = string(r)
 		}
+		logger = logger.WithValues(logging.KeyRegion, region)
+		ctx = log.IntoContext(ctx, logger)
+
+		if _, e := getSTSRegionalEndpoints(credentialsRef.Data); e != nil {
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		}
+		if e := setProvisioningAllowedCondition(&inventory); e != nil {
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		}
+		c, e := withAWSEndpoints(ctx, &inventory)
+		if e != nil {
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		}
+		ctx = c
+
+		if inventory.Labels[inventoryRegionLabelKey] != region {
+			if e := updateObject(ctx, r.Client, &inventory, func() error {
+				if inventory.Labels == nil {
+					inventory.Labels = map[string]string{}
+				}
+				inventory.Labels[inventoryRegionLabelKey] = region
+				return nil
+			}); e != nil {
+				if errors.IsConflict(e) {
+					logger.Info("Inventory modified, retry reconciling")
+					returnRequeueSyncReset()
+					return true
+				}
+				logger.Error(e, "Failed to set the region label of the Inventory")
+				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateError)
+				return true
+			}
+			logger.Info("Region label set for Inventory")
+			returnSyncReset()
+			return true
+		}
+
+		if openUntil, open := r.CircuitBreaker.openUntil(inventory.Namespace, inventory.Name); open {
+			logger.Info("AWS calls of the Inventory suspended", "until", openUntil)
+			returnCircuitOpen(openUntil)
+			return true
+		}
+
+		if wait, e := r.APIBudget.throttle(&inventory, time.Now()); e != nil {
+			returnError(e, inventoryStatusReasonInputError, e.Error())
+			return true
+		} else if wait > 0 {
+			logger.Info("AWS API budget of the Inventory spent, sync deferred", "wait", wait)
+			returnBudgetExceeded(wait)
+			return true
+		}
 
 		describeDBInstances := r.GetDescribeDBInstancesAPI(accessKey, secretKey, region)
 		instanceInput := &rds.DescribeDBInstancesInput{
@@ -339,7 +582,10 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 		if _, e := describeDBInstances.DescribeDBInstances(ctx, instanceInput); e != nil {
 			logger.Error(e, "Failed to read the DB instances with the AWS service account")
-			returnError(e, inventoryStatusReasonInputError, inventoryStatusMessageCredentialsInstanceError)
+			if r.CircuitBreaker.recordFailure(inventory.Namespace, inventory.Name) {
+				logger.Info("Too many consecutive AWS call failures, suspending AWS calls of the Inventory")
+			}
+			returnAWSError(e, inventoryStatusReasonInputError, inventoryStatusMessageCredentialsInstanceError)
 			return true
 		}
 
@@ -349,9 +595,13 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 		if _, e := describeDBClusters.DescribeDBClusters(ctx, clusterInput); e != nil {
 			logger.Error(e, "Failed to read the DB clusters with the AWS service account")
-			returnError(e, inventoryStatusReasonInputError, inventoryStatusMessageCredentialsClusterError)
+			if r.CircuitBreaker.recordFailure(inventory.Namespace, inventory.Name) {
+				logger.Info("Too many consecutive AWS call failures, suspending AWS calls of the Inventory")
+			}
+			returnAWSError(e, inventoryStatusReasonInputError, inventoryStatusMessageCredentialsClusterError)
 			return true
 		}
+		r.CircuitBreaker.recordSuccess(inventory.Namespace, inventory.Name)
 
 		return false
 	}
@@ -368,7 +618,7 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return true
 		}
 
-		if e := r.startRDSController(ctx); e != nil {
+		if e := r.startRDSController(ctx, getCredentialsFingerprint(&credentialsRef)); e != nil {
 			logger.Error(e, "Failed to start RDS controller")
 			returnError(e, inventoryStatusReasonBackendError, fmt.Sprintf(inventoryStatusMessageInstallError, "Operator Deployment"))
 			return true
@@ -386,19 +636,19 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	adoptDBInstances := func() (bool, bool) {
-		var awsDBInstances []rdstypesv2.DBInstance
+		var awsDBInstances []awsDBInstance
 		describeDBInstancesPaginator := r.GetDescribeDBInstancesPaginatorAPI(accessKey, secretKey, region)
 		for describeDBInstancesPaginator.HasMorePages() {
 			if output, e := describeDBInstancesPaginator.NextPage(ctx); e != nil {
 				logger.Error(e, "Failed to read DB Instances of the Inventory from AWS")
-				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetInstancesError)
+				returnAWSError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetInstancesError)
 				return true, false
 			} else if output != nil {
-				awsDBInstances = append(awsDBInstances, output.DBInstances...)
+				awsDBInstances = append(awsDBInstances, projectDBInstances(output.DBInstances)...)
 			}
 		}
 
-		awsDBInstanceMap := make(map[string]rdstypesv2.DBInstance, len(awsDBInstances))
+		awsDBInstanceMap := make(map[string]awsDBInstance, len(awsDBInstances))
 		if len(awsDBInstances) > 0 {
 			// query all db instances in cluster
 			clusterDBInstanceList := &rdsv1alpha1.DBInstanceList{}
@@ -429,39 +679,61 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				}
 			}
 
+			var adoptingMutex sync.Mutex
 			adoptingResource := false
+			setAdoptingResource := func() {
+				adoptingMutex.Lock()
+				defer adoptingMutex.Unlock()
+				adoptingResource = true
+			}
+
+			identifiers := make([]string, len(awsDBInstances))
 			for i := range awsDBInstances {
+				if awsDBInstances[i].DBInstanceArn != nil {
+					awsDBInstanceMap[*awsDBInstances[i].DBInstanceArn] = awsDBInstances[i]
+				}
+				if awsDBInstances[i].DBInstanceIdentifier != nil {
+					identifiers[i] = *awsDBInstances[i].DBInstanceIdentifier
+				}
+			}
+
+			if e := r.ShardedSync.run(ctx, shardPhaseAdoptDBInstances, identifiers, func(ctx context.Context, i int) error {
+				if e := r.Priority.waitLow(ctx, priorityBudget); e != nil {
+					return e
+				}
 				dbInstance := awsDBInstances[i]
 
 				if dbInstance.DBInstanceArn == nil {
-					continue
+					return nil
 				}
-				awsDBInstanceMap[*dbInstance.DBInstanceArn] = dbInstance
 
 				if dbInstance.Engine == nil {
-					continue
+					return nil
 				} else {
 					switch *dbInstance.Engine {
 					case aurora, auroraMysql, auroraPostgresql, customOracleEe, customSqlserverEe, customSqlserverSe, customSqlserverWeb:
-						continue
+						return nil
 					default:
 					}
 				}
 				if dbInstance.DBClusterIdentifier != nil {
-					continue
+					return nil
 				}
 				if dbInstance.DBInstanceStatus != nil && *dbInstance.DBInstanceStatus == "deleting" {
-					continue
+					return nil
+				}
+				if dbInstance.ExternallyManaged {
+					return nil
 				}
 
 				if _, ok := dbInstanceMap[*dbInstance.DBInstanceArn]; ok {
-					continue
+					return nil
 				}
 
 				if adoptedDBInstance, ok := adoptedDBInstanceMap[*dbInstance.DBInstanceArn]; ok {
 					// Wait for the DB instances that are being adopted
 					if adoptedDBInstance.Status.Conditions == nil {
-						adoptingResource = true
+						setAdoptingResource()
 					} else {
 						adopted := false
 						for j := range adoptedDBInstance.Status.Conditions {
@@ -473,26 +745,28 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 							}
 						}
 						if !adopted {
-							adoptingResource = true
+							setAdoptingResource()
 						}
 					}
-					continue
+					return nil
 				}
 
-				adoptingResource = true
+				setAdoptingResource()
 				logger.Info("Adopting DB Instance", "DB Instance Identifier", *dbInstance.DBInstanceIdentifier, "ARN", *dbInstance.DBInstanceArn)
 
 				adoptedDBInstance := createAdoptedResource(dbInstance.DBInstanceIdentifier, dbInstance.DBInstanceArn, dbInstance.Engine, rdsInstanceKind, &inventory)
 				if e := ophandler.SetOwnerAnnotations(&inventory, adoptedDBInstance); e != nil {
 					logger.Error(e, "Failed to create adopted DB Instance in the cluster")
-					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageAdoptInstanceError)
-					return true, false
+					return e
 				}
 				if e := r.Create(ctx, adoptedDBInstance); e != nil {
 					logger.Error(e, "Failed to create adopted DB Instance in the cluster")
-					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageAdoptInstanceError)
-					return true, false
+					return e
 				}
+				return nil
+			}); e != nil {
+				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageAdoptInstanceError)
+				return true, false
 			}
 
 			if adoptingResource {
@@ -510,63 +784,71 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return true, false
 		}
 		modifyDBInstance := r.GetModifyDBInstanceAPI(accessKey, secretKey, region)
+		var waitMutex sync.Mutex
 		waitForAdoptedResource := false
+		identifiers := make([]string, len(adoptedDBInstanceList.Items))
 		for i := range adoptedDBInstanceList.Items {
+			if adoptedDBInstanceList.Items[i].Spec.DBInstanceIdentifier != nil {
+				identifiers[i] = *adoptedDBInstanceList.Items[i].Spec.DBInstanceIdentifier
+			}
+		}
+		if e := r.ShardedSync.run(ctx, shardPhaseResetDBInstanceCreds, identifiers, func(ctx context.Context, i int) error {
+			if e := r.Priority.waitLow(ctx, priorityBudget); e != nil {
+				return e
+			}
 			adoptedDBInstance := adoptedDBInstanceList.Items[i]
 			if adoptedDBInstance.Spec.Engine == nil {
-				continue
+				return nil
 			} else {
 				switch *adoptedDBInstance.Spec.Engine {
 				case aurora, auroraMysql, auroraPostgresql, customOracleEe, customSqlserverEe, customSqlserverSe, customSqlserverWeb:
-					continue
+					return nil
 				default:
 				}
 			}
 			if adoptedDBInstance.Spec.DBClusterIdentifier != nil {
-				continue
+				return nil
 			}
 			if adoptedDBInstance.Status.DBInstanceStatus != nil && *adoptedDBInstance.Status.DBInstanceStatus == "deleting" {
-				continue
+				return nil
 			}
 			if adoptedDBInstance.Status.ACKResourceMetadata == nil || adoptedDBInstance.Status.ACKResourceMetadata.ARN == nil {
-				continue
+				return nil
 			}
 			awsDBInstance, awsOk := awsDBInstanceMap[string(*adoptedDBInstance.Status.ACKResourceMetadata.ARN)]
 			if !awsOk {
-				continue
+				return nil
 			}
 
 			if adoptedDBInstance.Spec.MasterUsername == nil || adoptedDBInstance.Spec.DBName == nil {
-				update := false
-				if adoptedDBInstance.Spec.MasterUsername == nil && awsDBInstance.MasterUsername != nil {
-					adoptedDBInstance.Spec.MasterUsername = pointer.String(*awsDBInstance.MasterUsername)
-					update = true
-				}
-				if adoptedDBInstance.Spec.DBName == nil && awsDBInstance.DBName != nil {
-					adoptedDBInstance.Spec.DBName = pointer.String(*awsDBInstance.DBName)
-					update = true
-				}
-				if update {
-					if e := r.Update(ctx, &adoptedDBInstance); e != nil {
-						if errors.IsConflict(e) {
-							logger.Info("Adopted DB Instance modified, retry reconciling")
-							returnRequeueSyncReset()
-							return true, false
+				if (adoptedDBInstance.Spec.MasterUsername == nil && awsDBInstance.MasterUsername != nil) ||
+					(adoptedDBInstance.Spec.DBName == nil && awsDBInstance.DBName != nil) {
+					if e := updateObject(ctx, r.Client, &adoptedDBInstance, func() error {
+						if adoptedDBInstance.Spec.MasterUsername == nil && awsDBInstance.MasterUsername != nil {
+							adoptedDBInstance.Spec.MasterUsername = pointer.String(*awsDBInstance.MasterUsername)
 						}
-						logger.Error(e, "Failed to update connection info of the adopted DB Instance", "DB Instance", adoptedDBInstance)
-						returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateInstanceError)
-						return true, false
+						if adoptedDBInstance.Spec.DBName == nil && awsDBInstance.DBName != nil {
+							adoptedDBInstance.Spec.DBName = pointer.String(*awsDBInstance.DBName)
+						}
+						return nil
+					}); e != nil {
+						if !errors.IsConflict(e) {
+							logger.Error(e, "Failed to update connection info of the adopted DB Instance", "DB Instance", adoptedDBInstance)
+						}
+						return e
 					}
 				}
 			}
 
 			if adoptedDBInstance.Spec.MasterUserPassword == nil {
 				if adoptedDBInstance.Status.DBInstanceStatus == nil || *adoptedDBInstance.Status.DBInstanceStatus != "available" {
+					waitMutex.Lock()
 					waitForAdoptedResource = true
+					waitMutex.Unlock()
 					logger.Info("DB Instance is not available to reset credentials", "DB Instance Identifier", *adoptedDBInstance.Spec.DBInstanceIdentifier)
-					continue
+					return nil
 				}
-				s, e := setCredentials(ctx, r.Client, r.Scheme, adoptedDBInstance.GetName(), inventory.Namespace, &adoptedDBInstance, adoptedDBInstance.Kind,
+				s, e := setCredentials(ctx, r.Client, r.Scheme, adoptedDBInstance.GetName(), inventory.Namespace, &adoptedDBInstance, adoptedDBInstance.Kind, defaultPasswordPolicy,
 					func(secretName string) {
 						if adoptedDBInstance.Spec.MasterUsername == nil {
 							adoptedDBInstance.Spec.MasterUsername = pointer.String(generateUsername(*adoptedDBInstance.Spec.Engine))
@@ -581,8 +863,7 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 						}
 					})
 				if e != nil {
-					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateInstanceError)
-					return true, false
+					return e
 				}
 				password := s.Data["password"]
 				input := &rds.ModifyDBInstanceInput{
@@ -592,20 +873,29 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				}
 				if _, e := modifyDBInstance.ModifyDBInstance(ctx, input); e != nil {
 					logger.Error(e, "Failed to update credentials of the adopted DB Instance", "DB Instance", adoptedDBInstance)
-					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateInstanceError)
-					return true, false
+					return e
 				}
-				if e := r.Update(ctx, &adoptedDBInstance); e != nil {
-					if errors.IsConflict(e) {
-						logger.Info("Adopted DB Instance modified, retry reconciling")
-						returnRequeueSyncReset()
-						return true, false
+				masterUsername, masterUserPassword := adoptedDBInstance.Spec.MasterUsername, adoptedDBInstance.Spec.MasterUserPassword
+				if e := updateObject(ctx, r.Client, &adoptedDBInstance, func() error {
+					adoptedDBInstance.Spec.MasterUsername = masterUsername
+					adoptedDBInstance.Spec.MasterUserPassword = masterUserPassword
+					return nil
+				}); e != nil {
+					if !errors.IsConflict(e) {
+						logger.Error(e, "Failed to update credentials of the adopted DB Instance", "DB Instance", adoptedDBInstance)
 					}
-					logger.Error(e, "Failed to update credentials of the adopted DB Instance", "DB Instance", adoptedDBInstance)
-					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateInstanceError)
-					return true, false
+					return e
 				}
 			}
+			return nil
+		}); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Adopted DB Instance modified, retry reconciling")
+				returnRequeueSyncReset()
+				return true, false
+			}
+			returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateInstanceError)
+			return true, false
 		}
 		if waitForAdoptedResource {
 			logger.Info("DB Instance being adopted is not available, retry reconciling")
@@ -615,19 +905,19 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	adoptDBClusters := func() (bool, bool) {
-		var awsDBClusters []rdstypesv2.DBCluster
+		var awsDBClusters []awsDBCluster
 		describeDBClustersPaginator := r.GetDescribeDBClustersPaginatorAPI(accessKey, secretKey, region)
 		for describeDBClustersPaginator.HasMorePages() {
 			if output, e := describeDBClustersPaginator.NextPage(ctx); e != nil {
 				logger.Error(e, "Failed to read DB clusters of the Inventory from AWS")
-				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetClustersError)
+				returnAWSError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetClustersError)
 				return true, false
 			} else if output != nil {
-				awsDBClusters = append(awsDBClusters, output.DBClusters...)
+				awsDBClusters = append(awsDBClusters, projectDBClusters(output.DBClusters)...)
 			}
 		}
 
-		awsDBClusterMap := make(map[string]rdstypesv2.DBCluster, len(awsDBClusters))
+		awsDBClusterMap := make(map[string]awsDBCluster, len(awsDBClusters))
 		if len(awsDBClusters) > 0 {
 			// query all db clusters in cluster
 			clusterDBClusterList := &rdsv1alpha1.DBClusterList{}
@@ -762,17 +1052,17 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 
 			if adoptedDBCluster.Spec.MasterUsername == nil || adoptedDBCluster.Spec.DatabaseName == nil {
-				update := false
-				if adoptedDBCluster.Spec.MasterUsername == nil && awsDBCluster.MasterUsername != nil {
-					adoptedDBCluster.Spec.MasterUsername = pointer.String(*awsDBCluster.MasterUsername)
-					update = true
-				}
-				if adoptedDBCluster.Spec.DatabaseName == nil && awsDBCluster.DatabaseName != nil {
-					adoptedDBCluster.Spec.DatabaseName = pointer.String(*awsDBCluster.DatabaseName)
-					update = true
-				}
-				if update {
-					if e := r.Update(ctx, &adoptedDBCluster); e != nil {
+				if (adoptedDBCluster.Spec.MasterUsername == nil && awsDBCluster.MasterUsername != nil) ||
+					(adoptedDBCluster.Spec.DatabaseName == nil && awsDBCluster.DatabaseName != nil) {
+					if e := updateObject(ctx, r.Client, &adoptedDBCluster, func() error {
+						if adoptedDBCluster.Spec.MasterUsername == nil && awsDBCluster.MasterUsername != nil {
+							adoptedDBCluster.Spec.MasterUsername = pointer.String(*awsDBCluster.MasterUsername)
+						}
+						if adoptedDBCluster.Spec.DatabaseName == nil && awsDBCluster.DatabaseName != nil {
+							adoptedDBCluster.Spec.DatabaseName = pointer.String(*awsDBCluster.DatabaseName)
+						}
+						return nil
+					}); e != nil {
 						if errors.IsConflict(e) {
 							logger.Info("Adopted DB Cluster modified, retry reconciling")
 							returnRequeueSyncReset()
@@ -791,7 +1081,7 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 					logger.Info("DB Cluster is not available to reset credentials", "DB Cluster Identifier", *adoptedDBCluster.Spec.DBClusterIdentifier)
 					continue
 				}
-				s, e := setCredentials(ctx, r.Client, r.Scheme, adoptedDBCluster.GetName(), inventory.Namespace, &adoptedDBCluster, adoptedDBCluster.Kind,
+				s, e := setCredentials(ctx, r.Client, r.Scheme, adoptedDBCluster.GetName(), inventory.Namespace, &adoptedDBCluster, adoptedDBCluster.Kind, defaultPasswordPolicy,
 					func(secretName string) {
 						if adoptedDBCluster.Spec.MasterUsername == nil {
 							adoptedDBCluster.Spec.MasterUsername = pointer.String(generateUsername(*adoptedDBCluster.Spec.Engine))
@@ -820,7 +1110,12 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 					returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateClusterError)
 					return true, false
 				}
-				if e := r.Update(ctx, &adoptedDBCluster); e != nil {
+				masterUsername, masterUserPassword := adoptedDBCluster.Spec.MasterUsername, adoptedDBCluster.Spec.MasterUserPassword
+				if e := updateObject(ctx, r.Client, &adoptedDBCluster, func() error {
+					adoptedDBCluster.Spec.MasterUsername = masterUsername
+					adoptedDBCluster.Spec.MasterUserPassword = masterUserPassword
+					return nil
+				}); e != nil {
 					if errors.IsConflict(e) {
 						logger.Info("Adopted DB Cluster modified, retry reconciling")
 						returnRequeueSyncReset()
@@ -841,16 +1136,26 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	syncDBInstancesStatus := func() (bool, []dbaasv1beta1.DatabaseService) {
 		awsDBInstanceIdentifiers := map[string]string{}
+		awsDBInstanceTags := map[string]map[string]string{}
+		awsDBInstanceDeployments := map[string]map[string]string{}
+		var customServices []dbaasv1beta1.DatabaseService
 		describeDBInstancesPaginator := r.GetDescribeDBInstancesPaginatorAPI(accessKey, secretKey, region)
 		for describeDBInstancesPaginator.HasMorePages() {
 			if output, e := describeDBInstancesPaginator.NextPage(ctx); e != nil {
 				logger.Error(e, "Failed to read DB Instances of the Inventory from AWS")
-				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetInstancesError)
+				returnAWSError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetInstancesError)
 				return true, nil
 			} else if output != nil {
 				for _, instance := range output.DBInstances {
 					if instance.DBInstanceIdentifier != nil && instance.DBInstanceArn != nil {
 						awsDBInstanceIdentifiers[*instance.DBInstanceArn] = *instance.DBInstanceIdentifier
+						awsDBInstanceTags[*instance.DBInstanceArn] = selectMappedTags(instance.TagList, tagLabelMapping)
+						awsDBInstanceDeployments[*instance.DBInstanceArn] = getDeploymentServiceInfo(instance)
+						if awsDBInstanceDeployments[*instance.DBInstanceArn][serviceInfoDeploymentType] == deploymentTypeCustom {
+							service := newCustomDBInstanceService(instance, awsDBInstanceDeployments[*instance.DBInstanceArn])
+							setTagServiceInfo(service.ServiceInfo, awsDBInstanceTags[*instance.DBInstanceArn])
+							customServices = append(customServices, service)
+						}
 					}
 				}
 			}
@@ -863,8 +1168,19 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return true, nil
 		}
 
+		var snapshotTimes map[string]*dbSnapshotTimes
+		if r.GetDescribeDBSnapshotsAPI != nil {
+			if t, e := describeLatestDBSnapshotTimes(ctx, r.GetDescribeDBSnapshotsAPI(accessKey, secretKey, region)); e != nil {
+				// the backup times are reported again in the next sync
+				logger.Error(e, "Failed to read DB Snapshots of the Inventory from AWS")
+			} else {
+				snapshotTimes = t
+			}
+		}
+
 		serviceType := dbaasv1beta1.DatabaseServiceType(instanceType)
 		var services []dbaasv1beta1.DatabaseService
+		inCluster := map[string]bool{}
 		for i := range dbInstanceList.Items {
 			dbInstance := dbInstanceList.Items[i]
 			if dbInstance.Spec.DBInstanceIdentifier == nil ||
@@ -880,7 +1196,23 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				ServiceType: &serviceType,
 				ServiceInfo: parseDBInstanceStatus(&dbInstance),
 			}
+			var latestRestorableTime time.Time
+			if dbInstance.Status.LatestRestorableTime != nil {
+				latestRestorableTime = dbInstance.Status.LatestRestorableTime.Time
+			}
+			setBackupServiceInfo(service.ServiceInfo, snapshotTimes[*dbInstance.Spec.DBInstanceIdentifier], latestRestorableTime)
+			setTagServiceInfo(service.ServiceInfo, awsDBInstanceTags[string(*dbInstance.Status.ACKResourceMetadata.ARN)])
+			for k, v := range awsDBInstanceDeployments[string(*dbInstance.Status.ACKResourceMetadata.ARN)] {
+				service.ServiceInfo[k] = v
+			}
 			services = append(services, service)
+			inCluster[string(*dbInstance.Status.ACKResourceMetadata.ARN)] = true
+		}
+		// the RDS Custom DB instances are reported with the attributes read from AWS as they are not adopted
+		for _, service := range customServices {
+			if !inCluster[service.ServiceInfo["ackResourceMetadata.arn"]] {
+				services = append(services, service)
+			}
 		}
 
 		return false, services
@@ -888,16 +1220,18 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	syncDBClustersStatus := func() (bool, []dbaasv1beta1.DatabaseService) {
 		awsDBClusterIdentifiers := map[string]string{}
+		awsDBClusterTags := map[string]map[string]string{}
 		describeDBClustersPaginator := r.GetDescribeDBClustersPaginatorAPI(accessKey, secretKey, region)
 		for describeDBClustersPaginator.HasMorePages() {
 			if output, e := describeDBClustersPaginator.NextPage(ctx); e != nil {
 				logger.Error(e, "Failed to read DB Clusters of the Inventory from AWS")
-				returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetClustersError)
+				returnAWSError(e, inventoryStatusReasonBackendError, inventoryStatusMessageGetClustersError)
 				return true, nil
 			} else if output != nil {
 				for _, cluster := range output.DBClusters {
 					if cluster.DBClusterIdentifier != nil && cluster.DBClusterArn != nil {
 						awsDBClusterIdentifiers[*cluster.DBClusterArn] = *cluster.DBClusterIdentifier
+						awsDBClusterTags[*cluster.DBClusterArn] = selectMappedTags(cluster.TagList, tagLabelMapping)
 					}
 				}
 			}
@@ -927,6 +1261,7 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				ServiceType: &serviceType,
 				ServiceInfo: parseDBClusterStatus(&dbCluster),
 			}
+			setTagServiceInfo(service.ServiceInfo, awsDBClusterTags[string(*dbCluster.Status.ACKResourceMetadata.ARN)])
 			services = append(services, service)
 		}
 
@@ -936,14 +1271,30 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err = r.Get(ctx, req.NamespacedName, &inventory); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("RDS Inventory resource not found, has been deleted")
+			deleteLastBackupAge(req.Namespace, req.Name)
+			deleteDatabaseServiceChanges(req.Namespace, req.Name)
+			deleteInventorySyncResult(req.Namespace, req.Name)
+			r.APIBudget.deleteAPIUsage(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Error fetching RDS Inventory for reconcile")
 		return ctrl.Result{}, err
 	}
 
+	logger = logger.WithValues(logging.KeyInventory, req.NamespacedName.String())
+	ctx = log.IntoContext(ctx, logger)
+	ctx = r.APIBudget.withCallRecorder(ctx, inventory.Namespace, inventory.Name)
+
 	defer updateInventoryReadyCondition()
 
+	// the Inventory being deleted is cleaned up and its finalizer removed in any namespace
+	if inventory.ObjectMeta.DeletionTimestamp.IsZero() && !r.NamespacePolicy.isAllowed(inventory.Namespace) {
+		logger.Info("RDS Inventory not reconciled in namespace not allowed by the namespace policy")
+		inventory.Status.DatabaseServices = nil
+		returnError(nil, inventoryStatusReasonNamespaceNotAllowed, fmt.Sprintf(inventoryStatusMessageNamespaceNotAllowed, inventory.Namespace))
+		return
+	}
+
 	if checkFinalizer() {
 		return
 	}
@@ -964,6 +1315,13 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return
 	}
 
+	if m, e := getTagLabelMapping(&inventory); e != nil {
+		// the tags are not reported until the mapping is fixed
+		logger.Error(e, "Failed to parse AWS tag to label mapping of the Inventory")
+	} else {
+		tagLabelMapping = m
+	}
+
 	var services []dbaasv1beta1.DatabaseService
 	if rt, sv := syncDBClustersStatus(); rt {
 		return
@@ -977,16 +1335,44 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		services = append(services, sv...)
 	}
 
+	services = append(services, r.syncOrganizationAccounts(ctx, &inventory, accessKey, secretKey, region, services)...)
+
+	if e := r.serveSpokes(ctx, &inventory, services, accessKey, secretKey, region); e != nil {
+		// the DB services are served to the spokes again in the next sync
+		logger.Error(e, "Failed to serve DB services of the Inventory to the spoke clusters")
+	}
+
+	// the services of the first sync of the Inventory are not announced
+	if apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady) != nil {
+		r.recordDatabaseServiceChanges(&inventory, diffDatabaseServices(inventory.Status.DatabaseServices, services))
+	}
 	inventory.Status.DatabaseServices = services
-	if e := r.Status().Update(ctx, &inventory); e != nil {
-		if errors.IsConflict(e) {
-			logger.Info("Inventory modified, retry reconciling")
-			returnRequeueSyncReset()
-			return
-		}
-		logger.Error(e, "Failed to update Inventory status")
-		returnError(e, inventoryStatusReasonBackendError, inventoryStatusMessageUpdateError)
-		return
+	apimeta.RemoveStatusCondition(&inventory.Status.Conditions, inventoryConditionRegionDegraded)
+	recordLastBackupAge(inventory.Namespace, inventory.Name, services, time.Now())
+
+	if e := r.syncDBSubnetGroups(ctx, &inventory, accessKey, secretKey, region); e != nil {
+		// the DB subnet groups are discovered again in the next sync
+		logger.Error(e, "Failed to sync DB subnet groups of the Inventory")
+	}
+
+	if e := r.deleteUnusedDBSubnetGroups(ctx, &inventory, time.Now()); e != nil {
+		// the unused DB subnet groups are deleted in the next sync
+		logger.Error(e, "Failed to delete unused DB subnet groups of the Inventory")
+	}
+
+	if e := r.syncFailoverEvents(ctx, &inventory, accessKey, secretKey, region); e != nil {
+		// failover events are picked up again in the next sync
+		logger.Error(e, "Failed to sync failover events of the Inventory")
+	}
+
+	if e := r.importConnections(ctx, &inventory); e != nil {
+		// the missing Connections are imported again in the next sync
+		logger.Error(e, "Failed to import Connections of the Inventory")
+	}
+
+	if e := r.exportDatabaseServices(ctx, &inventory, time.Now()); e != nil {
+		// the DB services are exported in the next sync
+		logger.Error(e, "Failed to export DB services of the Inventory")
 	}
 
 	if rqi || rqc {
@@ -997,11 +1383,242 @@ func (r *RDSInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	return
 }
 
+// syncVaultCredentials copies the AWS credentials from the Vault path of the Inventory to the credentials Secret
+func (r *RDSInventoryReconciler) syncVaultCredentials(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	path, ok := inventory.Annotations[vaultCredentialsPathAnnotation]
+	if !ok || len(path) == 0 {
+		return nil
+	}
+	if r.VaultClient == nil {
+		return fmt.Errorf("annotation %s is set, but no Vault server is configured for the operator", vaultCredentialsPathAnnotation)
+	}
+
+	data, err := r.VaultClient.Read(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inventory.Spec.CredentialsRef.Name,
+			Namespace: inventory.Namespace,
+		},
+	}
+	_, err = createOrApply(ctx, r.Client, secret, func(existing client.Object) error {
+		// the Secret created by the user is not owned by the Inventory
+		if existing == nil || metav1.IsControlledBy(existing, inventory) {
+			if err := ctrl.SetControllerReference(inventory, secret, r.Scheme); err != nil {
+				return err
+			}
+		}
+		secret.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+		secret.Data = map[string][]byte{}
+		for _, key := range []string{awsAccessKeyID, awsSecretAccessKey, awsRegion, awsSessionToken, awsCredentialExpiration,
+			ackResourceTags, ackLogLevel} {
+			if v, ok := data[key]; ok {
+				secret.Data[key] = []byte(v)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// syncFailoverEvents reads the failover events of the DB services since the last sync, and notifies the Connections bound to the services.
+// The time of the last events notified is kept in a ConfigMap of the Inventory with their keys, the sync resumes from it
+// after a restart and skips the events of the same time notified already.
+func (r *RDSInventoryReconciler) syncFailoverEvents(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory, accessKey, secretKey, region string) error {
+	if r.GetDescribeEventsAPI == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	key := client.ObjectKeyFromObject(inventory).String()
+	endTime := time.Now()
+	startTime := endTime.Add(-failoverEventLookback)
+	if t, ok := r.lastEventTimes.Load(key); ok {
+		startTime = t.(time.Time)
+	}
+
+	configMap := &v1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: inventory.Name + failoverEventsConfigMapSuffix}, configMap); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	var lastEventTime time.Time
+	lastEvents := map[string]bool{}
+	if v, ok := configMap.Data[failoverEventsKeyLastTime]; ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err != nil {
+			// the events are read from the lookback again
+			logger.Info("Invalid time of the last failover event notified ignored", "ConfigMap", configMap.Name, "time", v)
+		} else {
+			lastEventTime = t
+			for _, k := range strings.Fields(configMap.Data[failoverEventsKeyLastEvents]) {
+				lastEvents[k] = true
+			}
+			if lastEventTime.After(startTime) {
+				startTime = lastEventTime
+			}
+		}
+	}
+	notifiedEventTime := lastEventTime
+	notifiedEvents := lastEvents
+
+	describeEvents := r.GetDescribeEventsAPI(accessKey, secretKey, region)
+	input := &rds.DescribeEventsInput{
+		StartTime:       &startTime,
+		EndTime:         &endTime,
+		EventCategories: []string{failoverEventCategory},
+		MaxRecords:      pointer.Int32(100),
+	}
+	changed := false
+	for {
+		output, err := describeEvents.DescribeEvents(ctx, input)
+		if err != nil {
+			return err
+		}
+		if output == nil {
+			break
+		}
+		for _, event := range output.Events {
+			eventKey := getFailoverEventKey(event)
+			// the events before the last events notified, and the last events, are notified already
+			if event.Date != nil && (event.Date.Before(lastEventTime) || (event.Date.Equal(lastEventTime) && lastEvents[eventKey])) {
+				continue
+			}
+			if err := r.notifyFailoverEvent(ctx, inventory, event); err != nil {
+				return err
+			}
+			if event.Date == nil {
+				continue
+			}
+			if event.Date.After(notifiedEventTime) {
+				notifiedEventTime = *event.Date
+				notifiedEvents = map[string]bool{}
+			}
+			if event.Date.Equal(notifiedEventTime) {
+				notifiedEvents[eventKey] = true
+				changed = true
+			}
+		}
+		if output.Marker == nil || len(*output.Marker) == 0 {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	if changed {
+		keys := make([]string, 0, len(notifiedEvents))
+		for k := range notifiedEvents {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      inventory.Name + failoverEventsConfigMapSuffix,
+				Namespace: inventory.Namespace,
+			},
+		}
+		if _, err := createOrApply(ctx, r.Client, configMap, func(client.Object) error {
+			// the ConfigMaps of the cache are selected by the label
+			configMap.Labels = map[string]string{dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue}
+			configMap.Data = map[string]string{
+				failoverEventsKeyLastTime:   notifiedEventTime.UTC().Format(time.RFC3339Nano),
+				failoverEventsKeyLastEvents: strings.Join(keys, "\n"),
+			}
+			return ctrl.SetControllerReference(inventory, configMap, r.Scheme)
+		}); err != nil {
+			return err
+		}
+	}
+	r.lastEventTimes.Store(key, endTime)
+	return nil
+}
+
+// getFailoverEventKey returns the key of the event among the events of the same time
+func getFailoverEventKey(event rdstypesv2.Event) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(event.SourceType), pointer.StringDeref(event.SourceIdentifier, ""),
+		pointer.StringDeref(event.Message, "")}, "\x00")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func (r *RDSInventoryReconciler) notifyFailoverEvent(ctx context.Context, inventory *rdsdbaasv1alpha1.RDSInventory, event rdstypesv2.Event) error {
+	logger := log.FromContext(ctx)
+
+	var serviceType string
+	switch event.SourceType {
+	case rdstypesv2.SourceTypeDbInstance:
+		serviceType = instanceType
+	case rdstypesv2.SourceTypeDbCluster:
+		serviceType = clusterType
+	default:
+		return nil
+	}
+	if event.SourceIdentifier == nil {
+		return nil
+	}
+	var message string
+	if event.Message != nil {
+		message = *event.Message
+	}
+	eventTime := time.Now()
+	if event.Date != nil {
+		eventTime = *event.Date
+	}
+
+	logger.Info("DB service failover detected", "Service ID", *event.SourceIdentifier, "Message", message)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(inventory, v1.EventTypeWarning, eventReasonFailover, "DB %s %s failover: %s", serviceType, *event.SourceIdentifier, message)
+	}
+
+	connectionList := &rdsdbaasv1alpha1.RDSConnectionList{}
+	if err := r.List(ctx, connectionList, client.MatchingFields{databaseServiceIDKey: *event.SourceIdentifier}); err != nil {
+		return err
+	}
+	for i := range connectionList.Items {
+		connection := &connectionList.Items[i]
+		inventoryNamespace := connection.Spec.InventoryRef.Namespace
+		if len(inventoryNamespace) == 0 {
+			inventoryNamespace = connection.Namespace
+		}
+		if inventoryNamespace != inventory.Namespace || connection.Spec.InventoryRef.Name != inventory.Name {
+			continue
+		}
+		connectionType := instanceType
+		if connection.Spec.DatabaseServiceType != nil {
+			connectionType = string(*connection.Spec.DatabaseServiceType)
+		}
+		if connectionType != serviceType {
+			continue
+		}
+
+		// updating the annotation triggers the Connection to refresh the binding Secret and ConfigMap
+		patch := client.MergeFrom(connection.DeepCopy())
+		if connection.Annotations == nil {
+			connection.Annotations = map[string]string{}
+		}
+		connection.Annotations[connectionFailoverAnnotation] = eventTime.UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, connection, patch); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(connection, v1.EventTypeWarning, eventReasonFailover, "DB %s %s failover: %s", serviceType, *event.SourceIdentifier, message)
+		}
+	}
+	return nil
+}
+
 func (r *RDSInventoryReconciler) createOrUpdateSecret(ctx context.Context, cli client.Client, credentialsRef *v1.Secret) error {
 	deployment := &appsv1.Deployment{}
 	if e := cli.Get(ctx, client.ObjectKey{Namespace: r.ACKInstallNamespace, Name: ackDeploymentName}, deployment); e != nil {
 		return e
 	}
+	// the typed client does not set the kind, the owner annotations are set from it
+	deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
 
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1009,19 +1626,20 @@ func (r *RDSInventoryReconciler) createOrUpdateSecret(ctx context.Context, cli c
 			Namespace: r.ACKInstallNamespace,
 		},
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, cli, secret, func() error {
+	_, err := createOrApply(ctx, cli, secret, func(client.Object) error {
 		secret.ObjectMeta.Labels = buildDBaaSLabels()
-		secret.ObjectMeta.Annotations = r.buildDBaaSAnnotations(&secret.ObjectMeta)
-		if len(deployment.GetObjectKind().GroupVersionKind().GroupKind().Kind) > 0 {
-			if e := ophandler.SetOwnerAnnotations(deployment, secret); e != nil {
-				return e
-			}
+		secret.ObjectMeta.Annotations = r.buildDBaaSAnnotations()
+		if e := ophandler.SetOwnerAnnotations(deployment, secret); e != nil {
+			return e
 		}
 		if credentialsRef != nil {
 			secret.Data = map[string][]byte{
 				awsAccessKeyID:     credentialsRef.Data[awsAccessKeyID],
 				awsSecretAccessKey: credentialsRef.Data[awsSecretAccessKey],
 			}
+			if t, ok := credentialsRef.Data[awsSessionToken]; ok && len(t) > 0 {
+				secret.Data[awsSessionToken] = t
+			}
 		} else {
 			secret.Data = map[string][]byte{
 				awsAccessKeyID:     []byte("dummy"),
@@ -1041,6 +1659,8 @@ func (r *RDSInventoryReconciler) createOrUpdateConfigMap(ctx context.Context, cl
 	if e := cli.Get(ctx, client.ObjectKey{Namespace: r.ACKInstallNamespace, Name: ackDeploymentName}, deployment); e != nil {
 		return e
 	}
+	// the typed client does not set the kind, the owner annotations are set from it
+	deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
 
 	cm := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1048,16 +1668,15 @@ func (r *RDSInventoryReconciler) createOrUpdateConfigMap(ctx context.Context, cl
 			Namespace: r.ACKInstallNamespace,
 		},
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, cli, cm, func() error {
+	_, err := createOrApply(ctx, cli, cm, func(client.Object) error {
 		cm.ObjectMeta.Labels = buildDBaaSLabels()
-		cm.ObjectMeta.Annotations = r.buildDBaaSAnnotations(&cm.ObjectMeta)
-		if len(deployment.GetObjectKind().GroupVersionKind().GroupKind().Kind) > 0 {
-			if e := ophandler.SetOwnerAnnotations(deployment, cm); e != nil {
-				return e
-			}
+		cm.ObjectMeta.Annotations = r.buildDBaaSAnnotations()
+		if e := ophandler.SetOwnerAnnotations(deployment, cm); e != nil {
+			return e
 		}
 		cm.Data = map[string]string{
 			awsEndpointUrl:              "",
+			awsSTSRegionalEndpoints:     defaultAWSSTSRegionalEndpoints,
 			ackEnableDevelopmentLogging: "false",
 			ackWatchNamespace:           "",
 			ackLogLevel:                 "info",
@@ -1071,6 +1690,9 @@ func (r *RDSInventoryReconciler) createOrUpdateConfigMap(ctx context.Context, cl
 			if t, ok := credentialsRef.Data[ackResourceTags]; ok {
 				cm.Data[ackResourceTags] = string(t)
 			}
+			if v, e := getSTSRegionalEndpoints(credentialsRef.Data); e == nil {
+				cm.Data[awsSTSRegionalEndpoints] = v
+			}
 		} else {
 			cm.Data[awsRegion] = "dummy"
 		}
@@ -1088,18 +1710,13 @@ func buildDBaaSLabels() map[string]string {
 	}
 }
 
-func (r *RDSInventoryReconciler) buildDBaaSAnnotations(obj *metav1.ObjectMeta) map[string]string {
-	annotations := map[string]string{}
-	if obj.Annotations != nil {
-		for key, value := range obj.Annotations {
-			annotations[key] = value
-		}
+func (r *RDSInventoryReconciler) buildDBaaSAnnotations() map[string]string {
+	return map[string]string{
+		"managed-by":      "rds-dbaas-operator",
+		"owner":           ackDeploymentName,
+		"owner.kind":      "Deployment",
+		"owner.namespace": r.ACKInstallNamespace,
 	}
-	annotations["managed-by"] = "rds-dbaas-operator"
-	annotations["owner"] = ackDeploymentName
-	annotations["owner.kind"] = "Deployment"
-	annotations["owner.namespace"] = r.ACKInstallNamespace
-	return annotations
 }
 
 func (r *RDSInventoryReconciler) installCRD(ctx context.Context, cli client.Client, file string) error {
@@ -1112,7 +1729,7 @@ func (r *RDSInventoryReconciler) installCRD(ctx context.Context, cli client.Clie
 			Name: crd.Name,
 		},
 	}
-	if _, err := controllerutil.CreateOrUpdate(ctx, cli, c, func() error {
+	if _, err := createOrApply(ctx, cli, c, func(client.Object) error {
 		c.Spec = crd.Spec
 		return nil
 	}); err != nil {
@@ -1179,13 +1796,18 @@ func (r *RDSInventoryReconciler) stopRDSController(ctx context.Context, cli clie
 	return nil
 }
 
-func (r *RDSInventoryReconciler) startRDSController(ctx context.Context) error {
+func (r *RDSInventoryReconciler) startRDSController(ctx context.Context, credentialsFingerprint string) error {
 	deployment := &appsv1.Deployment{}
 	if err := r.Get(ctx, client.ObjectKey{Namespace: r.ACKInstallNamespace, Name: ackDeploymentName}, deployment); err != nil {
 		return err
 	}
-	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 {
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 ||
+		deployment.Spec.Template.Annotations[ackCredentialsFingerprintAnnotation] != credentialsFingerprint {
 		deployment.Spec.Replicas = pointer.Int32(1)
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[ackCredentialsFingerprintAnnotation] = credentialsFingerprint
 		if err := r.Update(ctx, deployment); err != nil {
 			return err
 		}
@@ -1257,6 +1879,11 @@ func (r *RDSInventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &rdsdbaasv1alpha1.RDSInventory{}, inventoryCredentialsRefKey,
+		indexInventoryCredentialsRef); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&rdsdbaasv1alpha1.RDSInventory{}).
 		Watches(
@@ -1277,7 +1904,14 @@ func (r *RDSInventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return getACKDeploymentInventoryRequests(o, r.ACKInstallNamespace, mgr)
 			}),
 		).
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+				return getCredentialsInventoryRequests(o, mgr.GetClient())
+			}),
+			builder.WithPredicates(credentialsSecretChanged()),
+		).
+		Complete(r.GracefulShutdown.reconciler(r))
 }
 
 func getRDSObjectInventoryRequests(object client.Object, mgr ctrl.Manager) []reconcile.Request {
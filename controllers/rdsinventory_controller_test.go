@@ -1746,6 +1746,15 @@ var _ = Describe("RDSInventoryController", func() {
 							return r == region
 						}, timeout).Should(BeTrue())
 
+						By("checking if the region label of the Inventory is set")
+						Eventually(func() bool {
+							inv := &rdsdbaasv1alpha1.RDSInventory{}
+							if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(inventory), inv); err != nil {
+								return false
+							}
+							return inv.Labels["rds.dbaas.redhat.com/region"] == region
+						}, timeout).Should(BeTrue())
+
 						By("checking if the RDS controller is started")
 						deployment := &appsv1.Deployment{
 							ObjectMeta: metav1.ObjectMeta{
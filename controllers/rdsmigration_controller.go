@@ -0,0 +1,451 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
+)
+
+const (
+	databaseMigrationRequeueInterval = 1 * time.Minute
+
+	databaseMigrationConditionMigrated = "Migrated"
+
+	databaseMigrationStatusReasonPending      = "Pending"
+	databaseMigrationStatusReasonRunning      = "Running"
+	databaseMigrationStatusReasonSucceeded    = "Succeeded"
+	databaseMigrationStatusReasonFailed       = "Failed"
+	databaseMigrationStatusReasonUnsupported  = "Unsupported"
+	databaseMigrationStatusReasonBackendError = "BackendError"
+	databaseMigrationStatusReasonInputError   = "InputError"
+	databaseMigrationStatusReasonNotFound     = "NotFound"
+	databaseMigrationStatusReasonUnreachable  = "Unreachable"
+
+	databaseMigrationStatusMessagePending          = "Migration Job created"
+	databaseMigrationStatusMessageRunning          = "Migration Job running"
+	databaseMigrationStatusMessageSucceeded        = "Migration Job succeeded"
+	databaseMigrationStatusMessageFailed           = "Migration Job failed"
+	databaseMigrationStatusMessageUnsupported      = "Migration not supported for engine %s"
+	databaseMigrationStatusMessageCreateJobError   = "Failed to create migration Job"
+	databaseMigrationStatusMessageGetJobError      = "Failed to get migration Job"
+	databaseMigrationStatusMessageSourceNotFound   = "Source Secret not found"
+	databaseMigrationStatusMessageGetSourceError   = "Failed to get source Secret"
+	databaseMigrationStatusMessageTargetNotFound   = "Target Instance not found"
+	databaseMigrationStatusMessageTargetNotReady   = "Target Instance not ready"
+	databaseMigrationStatusMessageGetTargetError   = "Failed to get target Instance"
+	databaseMigrationStatusMessageTargetNotSet     = "Endpoint or master credentials of target Instance not set"
+	databaseMigrationStatusMessageTargetNamespace  = "Master credentials of target Instance not in the namespace of the Migration"
+	databaseMigrationStatusMessageTargetStored     = "Master credentials of target Instance only stored in AWS Secrets Manager"
+	databaseMigrationStatusMessageInventoryError   = "Failed to get Inventory of target Instance"
+	databaseMigrationStatusMessageGetDBInstanceErr = "Failed to get DB Instance of target Instance"
+)
+
+// RDSMigrationReconciler reconciles a RDSMigration object
+type RDSMigrationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// the images with the clients of the engines run by the migration Jobs, the images of the connection tests are
+	// used if not set
+	PostgreSQLImage string
+	MySQLImage      string
+	// the reconciles in progress complete once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+}
+
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsmigrations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsmigrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsmigrations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// Reconcile runs the Job dumping the source database and restoring it to the target Instance once the Instance is
+// ready, and reflects the progress of the Job in the status of the RDSMigration. The Job is run once, the RDSMigration
+// is recreated to migrate the database again.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
+func (r *RDSMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	var migrationStatus, migrationStatusReason, migrationStatusMessage string
+
+	var migration rdsdbaasv1alpha1.RDSMigration
+
+	returnError := func(e error, reason, message string) {
+		result = ctrl.Result{}
+		err = e
+		migrationStatus = string(metav1.ConditionFalse)
+		migrationStatusReason = reason
+		migrationStatusMessage = message
+	}
+
+	returnRequeue := func(reason, message string) {
+		result = ctrl.Result{RequeueAfter: databaseMigrationRequeueInterval}
+		err = nil
+		migrationStatus = string(metav1.ConditionFalse)
+		migrationStatusReason = reason
+		migrationStatusMessage = message
+	}
+
+	returnStatus := func(status metav1.ConditionStatus, reason, message string) {
+		result = ctrl.Result{}
+		err = nil
+		migrationStatus = string(status)
+		migrationStatusReason = reason
+		migrationStatusMessage = message
+	}
+
+	updateMigratedCondition := func() {
+		if len(migrationStatusReason) == 0 {
+			return
+		}
+		condition := metav1.Condition{
+			Type:               databaseMigrationConditionMigrated,
+			Status:             metav1.ConditionStatus(migrationStatus),
+			Reason:             migrationStatusReason,
+			Message:            migrationStatusMessage,
+			ObservedGeneration: migration.Generation,
+		}
+		apimeta.SetStatusCondition(&migration.Status.Conditions, condition)
+		if e := updateStatus(ctx, r.Client, &migration); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Migration modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+			} else {
+				logger.Error(e, "Failed to update Migration status")
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+
+	if err = r.Get(ctx, req.NamespacedName, &migration); err != nil {
+		if errors.IsNotFound(err) {
+			// CR deleted since request queued, child objects getting GC'd, no requeue
+			logger.Info("RDS Migration resource not found, has been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get RDS Migration")
+		return ctrl.Result{}, err
+	}
+
+	switch migration.Status.Phase {
+	case rdsdbaasv1alpha1.RDSMigrationPhaseSucceeded, rdsdbaasv1alpha1.RDSMigrationPhaseFailed:
+		return ctrl.Result{}, nil
+	}
+
+	defer updateMigratedCondition()
+
+	job := &batchv1.Job{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: migration.Namespace, Name: getMigrationJobName(&migration)}, job); e != nil {
+		if !errors.IsNotFound(e) {
+			logger.Error(e, "Failed to get migration Job")
+			returnError(e, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageGetJobError)
+			return
+		}
+
+		source := &v1.Secret{}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: migration.Namespace, Name: migration.Spec.SourceSecretRef.Name}, source); e != nil {
+			if errors.IsNotFound(e) {
+				logger.Info("Source Secret not found")
+				returnRequeue(databaseMigrationStatusReasonNotFound, databaseMigrationStatusMessageSourceNotFound)
+				return
+			}
+			logger.Error(e, "Failed to get source Secret")
+			returnError(e, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageGetSourceError)
+			return
+		}
+
+		target, reason, message, e := r.getMigrationTarget(ctx, &migration)
+		if e != nil {
+			logger.Error(e, message)
+			returnError(e, reason, message)
+			return
+		} else if target == nil {
+			logger.Info(message)
+			returnRequeue(reason, message)
+			return
+		}
+
+		container, ok := r.getMigrationContainer(&migration, target)
+		if !ok {
+			migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhaseFailed
+			returnStatus(metav1.ConditionFalse, databaseMigrationStatusReasonUnsupported,
+				fmt.Sprintf(databaseMigrationStatusMessageUnsupported, target.engine))
+			return
+		}
+		job = newMigrationJob(&migration, container)
+		if e := ctrl.SetControllerReference(&migration, job, r.Scheme); e != nil {
+			returnError(e, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageCreateJobError)
+			return
+		}
+		if e := r.Create(ctx, job); e != nil && !errors.IsAlreadyExists(e) {
+			logger.Error(e, "Failed to create migration Job")
+			returnError(e, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageCreateJobError)
+			return
+		}
+		logger.Info("Migration Job created", "Job", job.Name)
+		migration.Status.JobName = job.Name
+		migration.Status.Engine = target.engine
+		migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhasePending
+		returnStatus(metav1.ConditionFalse, databaseMigrationStatusReasonPending, databaseMigrationStatusMessagePending)
+		return
+	}
+
+	setMigrationJobStatus(&migration, job)
+	switch migration.Status.Phase {
+	case rdsdbaasv1alpha1.RDSMigrationPhaseSucceeded:
+		logger.Info("Migration Job succeeded", "Job", job.Name)
+		returnStatus(metav1.ConditionTrue, databaseMigrationStatusReasonSucceeded, databaseMigrationStatusMessageSucceeded)
+	case rdsdbaasv1alpha1.RDSMigrationPhaseFailed:
+		logger.Info("Migration Job failed", "Job", job.Name)
+		message := databaseMigrationStatusMessageFailed
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == v1.ConditionTrue && len(c.Message) > 0 {
+				message = fmt.Sprintf("%s: %s", message, c.Message)
+			}
+		}
+		returnStatus(metav1.ConditionFalse, databaseMigrationStatusReasonFailed, message)
+	case rdsdbaasv1alpha1.RDSMigrationPhaseRunning:
+		returnStatus(metav1.ConditionFalse, databaseMigrationStatusReasonRunning, databaseMigrationStatusMessageRunning)
+	default:
+		returnStatus(metav1.ConditionFalse, databaseMigrationStatusReasonPending, databaseMigrationStatusMessagePending)
+	}
+	return
+}
+
+// migrationTarget is the database of the target Instance a database is restored to
+type migrationTarget struct {
+	engine, host, port, username, database string
+	password                               v1.SecretKeySelector
+}
+
+// getMigrationTarget returns the endpoint and the master credentials of the target Instance, or nil with the reason
+// of the condition if the Instance is not ready to be migrated to
+func (r *RDSMigrationReconciler) getMigrationTarget(ctx context.Context, migration *rdsdbaasv1alpha1.RDSMigration) (*migrationTarget,
+	string, string, error) {
+	instance := &rdsdbaasv1alpha1.RDSInstance{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: migration.Namespace, Name: migration.Spec.TargetInstanceRef.Name}, instance); e != nil {
+		if errors.IsNotFound(e) {
+			return nil, databaseMigrationStatusReasonNotFound, databaseMigrationStatusMessageTargetNotFound, nil
+		}
+		return nil, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageGetTargetError, e
+	}
+	if condition := apimeta.FindStatusCondition(instance.Status.Conditions, instanceConditionReady); condition == nil ||
+		condition.Status != metav1.ConditionTrue {
+		return nil, databaseMigrationStatusReasonUnreachable, databaseMigrationStatusMessageTargetNotReady, nil
+	}
+
+	inventory := &rdsdbaasv1alpha1.RDSInventory{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: instance.Spec.InventoryRef.Namespace,
+		Name: instance.Spec.InventoryRef.Name}, inventory); e != nil {
+		return nil, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageInventoryError, e
+	}
+	dbInstance := &rdsv1alpha1.DBInstance{}
+	if e := r.Get(ctx, client.ObjectKey{Namespace: inventory.Namespace, Name: instance.Name}, dbInstance); e != nil {
+		return nil, databaseMigrationStatusReasonBackendError, databaseMigrationStatusMessageGetDBInstanceErr, e
+	}
+
+	if dbInstance.Spec.MasterUserPassword == nil && dbInstance.Annotations[masterCredentialsStoredAnnotation] == "true" {
+		// the Job reads the master password from a Secret, which is deleted once stored in AWS Secrets Manager
+		return nil, databaseMigrationStatusReasonInputError, databaseMigrationStatusMessageTargetStored,
+			fmt.Errorf(databaseMigrationStatusMessageTargetStored)
+	}
+	if dbInstance.Spec.Engine == nil || dbInstance.Spec.MasterUsername == nil || dbInstance.Spec.MasterUserPassword == nil ||
+		dbInstance.Status.Endpoint == nil || dbInstance.Status.Endpoint.Address == nil || dbInstance.Status.Endpoint.Port == nil {
+		return nil, databaseMigrationStatusReasonUnreachable, databaseMigrationStatusMessageTargetNotSet, nil
+	}
+	if dbInstance.Spec.MasterUserPassword.Namespace != migration.Namespace {
+		// the Job can only read the Secrets of its namespace
+		return nil, databaseMigrationStatusReasonInputError, databaseMigrationStatusMessageTargetNamespace,
+			fmt.Errorf(databaseMigrationStatusMessageTargetNamespace)
+	}
+
+	target := &migrationTarget{
+		engine:   *dbInstance.Spec.Engine,
+		host:     *dbInstance.Status.Endpoint.Address,
+		port:     strconv.FormatInt(*dbInstance.Status.Endpoint.Port, 10),
+		username: *dbInstance.Spec.MasterUsername,
+		database: migration.Spec.TargetDatabase,
+		password: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: dbInstance.Spec.MasterUserPassword.Name},
+			Key:                  dbInstance.Spec.MasterUserPassword.Key,
+		},
+	}
+	if len(target.database) == 0 {
+		target.database = pointer.StringDeref(dbInstance.Spec.DBName, pointer.StringDeref(getDefaultDBName(target.engine), ""))
+	}
+	return target, "", "", nil
+}
+
+// setMigrationJobStatus reflects the progress of the migration Job in the status of the Migration
+func setMigrationJobStatus(migration *rdsdbaasv1alpha1.RDSMigration, job *batchv1.Job) {
+	migration.Status.JobName = job.Name
+	migration.Status.FailedAttempts = job.Status.Failed
+	migration.Status.StartTime = job.Status.StartTime
+	migration.Status.CompletionTime = job.Status.CompletionTime
+
+	migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhasePending
+	if job.Status.Active > 0 {
+		migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhaseRunning
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhaseSucceeded
+		case batchv1.JobFailed:
+			migration.Status.Phase = rdsdbaasv1alpha1.RDSMigrationPhaseFailed
+			if migration.Status.CompletionTime == nil {
+				migration.Status.CompletionTime = &c.LastTransitionTime
+			}
+		}
+	}
+}
+
+func getMigrationJobName(migration *rdsdbaasv1alpha1.RDSMigration) string {
+	return fmt.Sprintf("%s-migration", migration.Name)
+}
+
+// getMigrationContainer returns the container piping the dump of the source database to the restore of the target
+// database with the clients of the engine, the source is read from the Secret of the Migration
+func (r *RDSMigrationReconciler) getMigrationContainer(migration *rdsdbaasv1alpha1.RDSMigration, target *migrationTarget) (v1.Container, bool) {
+	fromSource := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: migration.Spec.SourceSecretRef,
+					Key:                  key,
+				},
+			},
+		}
+	}
+	env := []v1.EnvVar{
+		fromSource("SOURCE_HOST", "host"),
+		fromSource("SOURCE_PORT", "port"),
+		fromSource("SOURCE_DATABASE", "database"),
+		fromSource("SOURCE_USER", "username"),
+		fromSource("SOURCE_PASSWORD", "password"),
+		{Name: "TARGET_HOST", Value: target.host},
+		{Name: "TARGET_PORT", Value: target.port},
+		{Name: "TARGET_DATABASE", Value: target.database},
+		{Name: "TARGET_USER", Value: target.username},
+		{Name: "TARGET_PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: target.password.DeepCopy()}},
+	}
+
+	container := v1.Container{
+		Name:                     "migration",
+		Env:                      env,
+		TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
+		SecurityContext: &v1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsNonRoot:             pointer.Bool(true),
+			Capabilities: &v1.Capabilities{
+				Drop: []v1.Capability{"ALL"},
+			},
+		},
+	}
+	switch generateBindingType(target.engine) {
+	case "postgresql":
+		container.Image = r.PostgreSQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestPostgreSQLImage
+		}
+		restoreOptions := "--no-owner --no-privileges --exit-on-error"
+		if migration.Spec.Clean {
+			restoreOptions += " --clean --if-exists"
+		}
+		container.Command = []string{"/bin/bash", "-c", "set -eo pipefail; " +
+			`PGPASSWORD="$SOURCE_PASSWORD" pg_dump --host="$SOURCE_HOST" --port="$SOURCE_PORT" --username="$SOURCE_USER" ` +
+			`--dbname="$SOURCE_DATABASE" --format=custom --no-owner --no-privileges | ` +
+			`PGPASSWORD="$TARGET_PASSWORD" pg_restore --host="$TARGET_HOST" --port="$TARGET_PORT" --username="$TARGET_USER" ` +
+			`--dbname="$TARGET_DATABASE" ` + restoreOptions}
+	case "mysql":
+		container.Image = r.MySQLImage
+		if len(container.Image) == 0 {
+			container.Image = DefaultConnectionTestMySQLImage
+		}
+		dumpOptions := "--single-transaction --routines --triggers --set-gtid-purged=OFF"
+		if !migration.Spec.Clean {
+			dumpOptions += " --skip-add-drop-table"
+		}
+		container.Command = []string{"/bin/bash", "-c", "set -eo pipefail; " +
+			`MYSQL_PWD="$SOURCE_PASSWORD" mysqldump --host="$SOURCE_HOST" --port="$SOURCE_PORT" --user="$SOURCE_USER" ` +
+			dumpOptions + ` "$SOURCE_DATABASE" | ` +
+			`MYSQL_PWD="$TARGET_PASSWORD" mysql --host="$TARGET_HOST" --port="$TARGET_PORT" --user="$TARGET_USER" "$TARGET_DATABASE"`}
+	default:
+		return container, false
+	}
+	return container, true
+}
+
+func newMigrationJob(migration *rdsdbaasv1alpha1.RDSMigration, container v1.Container) *batchv1.Job {
+	backoffLimit := migration.Spec.BackoffLimit
+	if backoffLimit == nil {
+		backoffLimit = pointer.Int32(0)
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getMigrationJobName(migration),
+			Namespace: migration.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          backoffLimit,
+			ActiveDeadlineSeconds: migration.Spec.ActiveDeadlineSeconds,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy:                v1.RestartPolicyNever,
+					AutomountServiceAccountToken: pointer.Bool(false),
+					SecurityContext: &v1.PodSecurityContext{
+						SeccompProfile: &v1.SeccompProfile{
+							Type: v1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []v1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RDSMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rdsdbaasv1alpha1.RDSMigration{}).
+		Owns(&batchv1.Job{}).
+		Complete(r.GracefulShutdown.reconciler(r))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("RDSMigrationController", func() {
+	Context("when Migration is created", func() {
+		migrationName := "rds-migration-controller"
+		sourceSecretName := "rds-migration-controller-source"
+
+		migration := &rdsdbaasv1alpha1.RDSMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      migrationName,
+				Namespace: testNamespace,
+			},
+			Spec: rdsdbaasv1alpha1.RDSMigrationSpec{
+				SourceSecretRef:   v1.LocalObjectReference{Name: sourceSecretName},
+				TargetInstanceRef: v1.LocalObjectReference{Name: "rds-instance-migration-controller"},
+			},
+		}
+		BeforeEach(assertResourceCreation(migration))
+		AfterEach(assertResourceDeletion(migration))
+
+		assertMigratedReason := func(reason string) func() {
+			return func() {
+				m := &rdsdbaasv1alpha1.RDSMigration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      migrationName,
+						Namespace: testNamespace,
+					},
+				}
+				Eventually(func() bool {
+					if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), m); err != nil {
+						return false
+					}
+					condition := apimeta.FindStatusCondition(m.Status.Conditions, "Migrated")
+					if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != reason {
+						return false
+					}
+					return len(m.Status.JobName) == 0
+				}, timeout).Should(BeTrue())
+			}
+		}
+
+		Context("when source Secret is not created", func() {
+			It("should wait for the source Secret", assertMigratedReason("NotFound"))
+		})
+
+		Context("when target Instance is not created", func() {
+			source := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      sourceSecretName,
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"host":     "source.example.com",
+					"port":     "5432",
+					"database": "postgres",
+					"username": "postgres",
+					"password": "password",
+				},
+			}
+			BeforeEach(assertResourceCreation(source))
+			AfterEach(assertResourceDeletion(source))
+
+			It("should wait for the target Instance", assertMigratedReason("NotFound"))
+		})
+	})
+})
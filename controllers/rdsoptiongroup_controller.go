@@ -0,0 +1,637 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	optionGroupFinalizer = "rds.dbaas.redhat.com/optiongroup"
+
+	optionGroupRequeueInterval = 1 * time.Minute
+	optionGroupSyncInterval    = 10 * time.Minute
+
+	optionGroupConditionReady = "OptionGroupReady"
+
+	optionGroupStatusReasonReady        = "Ready"
+	optionGroupStatusReasonCreating     = "Creating"
+	optionGroupStatusReasonFreezeWindow = "FreezeWindow"
+	optionGroupStatusReasonInUse        = "InUse"
+	optionGroupStatusReasonBackendError = "BackendError"
+	optionGroupStatusReasonInputError   = "InputError"
+	optionGroupStatusReasonNotFound     = "NotFound"
+	optionGroupStatusReasonUnreachable  = "Unreachable"
+	optionGroupStatusReasonNotAllowed   = "NotAllowed"
+
+	optionGroupStatusMessageCreateError          = "Failed to create DB option group"
+	optionGroupStatusMessageCreating             = "Creating DB option group"
+	optionGroupStatusMessageGetError             = "Failed to get DB option group"
+	optionGroupStatusMessageGetOptionsError      = "Failed to get available options of DB option group engine"
+	optionGroupStatusMessageModifyError          = "Failed to modify options of DB option group"
+	optionGroupStatusMessageDeleteError          = "Failed to delete DB option group"
+	optionGroupStatusMessageInUse                = "DB option group is used by DB instances or snapshots and cannot be deleted"
+	optionGroupStatusMessageEngineChanged        = "The engine of DB option group %s is %s %s and cannot be changed"
+	optionGroupStatusMessageNotCreated           = "DB option group %s already exists, the Option Groups of namespace %s only manage the DB option groups they create"
+	optionGroupStatusMessageUnknownOption        = "Option %s is not available for engine %s version %s"
+	optionGroupStatusMessageDuplicateOption      = "Option %s is set more than once"
+	optionGroupStatusMessageOptionDependency     = "Option %s requires option %s"
+	optionGroupStatusMessageOptionConflict       = "Option %s conflicts with option %s"
+	optionGroupStatusMessagePortRequired         = "Option %s requires a port"
+	optionGroupStatusMessageUnknownVersion       = "Version %s is not a version of option %s"
+	optionGroupStatusMessageUnknownSetting       = "Setting %s is not a setting of option %s"
+	optionGroupStatusMessageSettingRequired      = "Option %s requires setting %s"
+	optionGroupStatusMessageSettingNotModifiable = "Setting %s of option %s cannot be modified"
+	optionGroupStatusMessagePermanentOption      = "Option %s is permanent and cannot be removed"
+	optionGroupStatusMessageFreezeWindow         = "Modifications of options are deferred until %s by freeze window %q"
+	optionGroupStatusMessageUpdateError          = "Failed to update Option Group"
+	optionGroupStatusMessageInventoryNotFound    = "Inventory not found"
+	optionGroupStatusMessageInventoryNotReady    = "Inventory not ready"
+	optionGroupStatusMessageGetInventoryError    = "Failed to get Inventory"
+	optionGroupStatusMessageCredentialsError     = "Failed to get credentials of Inventory"
+)
+
+// RDSOptionGroupReconciler reconciles a RDSOptionGroup object
+type RDSOptionGroupReconciler struct {
+	client.Client
+	Scheme                           *runtime.Scheme
+	GetCreateOptionGroupAPI          func(accessKey, secretKey, region string) controllersrds.CreateOptionGroupAPI
+	GetDescribeOptionGroupsAPI       func(accessKey, secretKey, region string) controllersrds.DescribeOptionGroupsAPI
+	GetModifyOptionGroupAPI          func(accessKey, secretKey, region string) controllersrds.ModifyOptionGroupAPI
+	GetDeleteOptionGroupAPI          func(accessKey, secretKey, region string) controllersrds.DeleteOptionGroupAPI
+	GetDescribeOptionGroupOptionsAPI func(accessKey, secretKey, region string) controllersrds.DescribeOptionGroupOptionsAPI
+	// the options are not modified during the freeze windows of the operator and of the Inventory
+	FreezeWindows []FreezeWindow
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the Option Groups may only use the Inventories in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+}
+
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsoptiongroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsoptiongroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsoptiongroups/finalizers,verbs=update
+
+// Reconcile creates the DB option group and sets its options to the options of the spec, once the options are
+// validated against the options available for the engine version, including their dependencies and conflicts. The
+// DB option group is deleted from AWS when the RDSOptionGroup is deleted.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
+func (r *RDSOptionGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	var optionGroupStatus, optionGroupStatusReason, optionGroupStatusMessage string
+
+	var optionGroup rdsdbaasv1alpha1.RDSOptionGroup
+	var inventory rdsdbaasv1alpha1.RDSInventory
+	var accessKey, secretKey, region string
+	var groupName string
+
+	returnError := func(e error, reason, message string) {
+		result = ctrl.Result{}
+		err = e
+		optionGroupStatus = string(metav1.ConditionFalse)
+		optionGroupStatusReason = reason
+		optionGroupStatusMessage = message
+	}
+
+	returnRequeue := func(reason, message string) {
+		result = ctrl.Result{RequeueAfter: optionGroupRequeueInterval}
+		err = nil
+		optionGroupStatus = string(metav1.ConditionFalse)
+		optionGroupStatusReason = reason
+		optionGroupStatusMessage = message
+	}
+
+	returnReady := func(reason, message string, requeueAfter time.Duration) {
+		result = ctrl.Result{RequeueAfter: requeueAfter}
+		err = nil
+		optionGroupStatus = string(metav1.ConditionTrue)
+		optionGroupStatusReason = reason
+		optionGroupStatusMessage = message
+	}
+
+	updateOptionGroupReadyCondition := func() {
+		// the status is not updated once the finalizer is removed or when the resource is requeued
+		if len(optionGroupStatusReason) == 0 {
+			return
+		}
+		condition := metav1.Condition{
+			Type:    optionGroupConditionReady,
+			Status:  metav1.ConditionStatus(optionGroupStatus),
+			Reason:  optionGroupStatusReason,
+			Message: optionGroupStatusMessage,
+		}
+		apimeta.SetStatusCondition(&optionGroup.Status.Conditions, condition)
+		if e := updateStatus(ctx, r.Client, &optionGroup); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Option Group modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+			} else {
+				logger.Error(e, "Failed to update Option Group status")
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+
+	checkInventory := func() bool {
+		if e := r.Get(ctx, client.ObjectKey{Namespace: optionGroup.Spec.InventoryRef.Namespace,
+			Name: optionGroup.Spec.InventoryRef.Name}, &inventory); e != nil {
+			if errors.IsNotFound(e) {
+				logger.Info("RDS Inventory resource not found, may have been deleted")
+				returnError(e, optionGroupStatusReasonNotFound, optionGroupStatusMessageInventoryNotFound)
+				return true
+			}
+			logger.Error(e, "Failed to get RDS Inventory")
+			returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageGetInventoryError)
+			return true
+		}
+		if e := checkInventoryReference(r.NamespacePolicy, optionGroup.Namespace, &inventory); e != nil {
+			logger.Info("RDS Inventory not allowed for the Option Group", "reason", e.Error())
+			returnError(e, optionGroupStatusReasonNotAllowed, e.Error())
+			return true
+		}
+
+		if condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); condition == nil || condition.Status != metav1.ConditionTrue {
+			logger.Info("RDS Inventory not ready")
+			returnRequeue(optionGroupStatusReasonUnreachable, optionGroupStatusMessageInventoryNotReady)
+			return true
+		}
+
+		secret := &v1.Secret{}
+		if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
+			logger.Error(e, "Failed to get credentials of RDS Inventory")
+			returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageCredentialsError)
+			return true
+		}
+		accessKey = string(secret.Data[awsAccessKeyID])
+		secretKey = string(secret.Data[awsSecretAccessKey])
+		region = string(secret.Data[awsRegion])
+		logger = logger.WithValues(logging.KeyRegion, region)
+		ctx = log.IntoContext(ctx, logger)
+		return false
+	}
+
+	checkFinalizer := func() bool {
+		if optionGroup.DeletionTimestamp.IsZero() {
+			if !controllerutil.ContainsFinalizer(&optionGroup, optionGroupFinalizer) {
+				if e := updateObject(ctx, r.Client, &optionGroup, func() error {
+					controllerutil.AddFinalizer(&optionGroup, optionGroupFinalizer)
+					return nil
+				}); e != nil {
+					if errors.IsConflict(e) {
+						logger.Info("Option Group modified, retry reconciling")
+						result = ctrl.Result{Requeue: true}
+						return true
+					}
+					logger.Error(e, "Failed to add finalizer to Option Group")
+					returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageUpdateError)
+					return true
+				}
+				logger.Info("Finalizer added to Option Group")
+			}
+			return false
+		}
+
+		if !controllerutil.ContainsFinalizer(&optionGroup, optionGroupFinalizer) {
+			return true
+		}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: optionGroup.Spec.InventoryRef.Namespace,
+			Name: optionGroup.Spec.InventoryRef.Name}, &inventory); e != nil && errors.IsNotFound(e) {
+			// the DB option group cannot be deleted without the credentials of the Inventory
+			logger.Info("RDS Inventory resource not found, DB option group kept in AWS")
+		} else if e == nil && (checkInventoryReference(r.NamespacePolicy, optionGroup.Namespace, &inventory) != nil ||
+			(optionGroup.Namespace != inventory.Namespace && len(optionGroup.Status.OptionGroupARN) == 0)) {
+			// the DB option group is only deleted by the namespaces allowed to use the Inventory, and by the other
+			// namespaces than the one of the Inventory only if they created it
+			logger.Info("RDS Inventory not allowed for the Option Group, DB option group kept in AWS")
+		} else {
+			if checkInventory() {
+				return true
+			}
+			deleteOptionGroup := r.GetDeleteOptionGroupAPI(accessKey, secretKey, region)
+			if _, e := deleteOptionGroup.DeleteOptionGroup(ctx, &rds.DeleteOptionGroupInput{
+				OptionGroupName: pointer.String(groupName),
+			}); e != nil {
+				var notFoundErr *rdstypesv2.OptionGroupNotFoundFault
+				var stateErr *rdstypesv2.InvalidOptionGroupStateFault
+				if goerrors.As(e, &stateErr) {
+					logger.Info("DB option group in use, retry deleting")
+					returnRequeue(optionGroupStatusReasonInUse, optionGroupStatusMessageInUse)
+					return true
+				} else if !goerrors.As(e, &notFoundErr) {
+					logger.Error(e, "Failed to delete DB option group")
+					returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageDeleteError)
+					return true
+				}
+			}
+			logger.Info("DB option group deleted")
+		}
+
+		if e := updateObject(ctx, r.Client, &optionGroup, func() error {
+			controllerutil.RemoveFinalizer(&optionGroup, optionGroupFinalizer)
+			return nil
+		}); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Option Group modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+				return true
+			}
+			logger.Error(e, "Failed to remove finalizer from Option Group")
+			returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageUpdateError)
+			return true
+		}
+		logger.Info("Finalizer removed from Option Group")
+		return true
+	}
+
+	validateOptions := func() bool {
+		available := map[string]rdstypesv2.OptionGroupOption{}
+		paginator := rds.NewDescribeOptionGroupOptionsPaginator(r.GetDescribeOptionGroupOptionsAPI(accessKey, secretKey, region),
+			&rds.DescribeOptionGroupOptionsInput{
+				EngineName:         pointer.String(optionGroup.Spec.EngineName),
+				MajorEngineVersion: pointer.String(optionGroup.Spec.MajorEngineVersion),
+			})
+		for paginator.HasMorePages() {
+			output, e := paginator.NextPage(ctx)
+			if e != nil {
+				logger.Error(e, "Failed to get available options of DB option group engine")
+				returnError(e, getAWSErrorReason(e, optionGroupStatusReasonBackendError), optionGroupStatusMessageGetOptionsError)
+				return true
+			}
+			for _, o := range output.OptionGroupOptions {
+				if o.Name != nil {
+					available[*o.Name] = o
+				}
+			}
+		}
+
+		if e := validateOptionGroupOptions(&optionGroup, available); e != nil {
+			returnError(e, optionGroupStatusReasonInputError, e.Error())
+			return true
+		}
+		return false
+	}
+
+	syncOptionGroup := func() bool {
+		describeOptionGroups := r.GetDescribeOptionGroupsAPI(accessKey, secretKey, region)
+		output, e := describeOptionGroups.DescribeOptionGroups(ctx, &rds.DescribeOptionGroupsInput{
+			OptionGroupName: pointer.String(groupName),
+		})
+		var notFoundErr *rdstypesv2.OptionGroupNotFoundFault
+		if e != nil && !goerrors.As(e, &notFoundErr) {
+			logger.Error(e, "Failed to get DB option group")
+			returnError(e, optionGroupStatusReasonBackendError, optionGroupStatusMessageGetError)
+			return true
+		}
+
+		var group *rdstypesv2.OptionGroup
+		if e == nil && len(output.OptionGroupsList) > 0 {
+			if optionGroup.Namespace != inventory.Namespace && len(optionGroup.Status.OptionGroupARN) == 0 {
+				// the DB option groups of the Inventory are not adopted by the other namespaces
+				e := fmt.Errorf(optionGroupStatusMessageNotCreated, groupName, optionGroup.Namespace)
+				returnError(e, optionGroupStatusReasonNotAllowed, e.Error())
+				return true
+			}
+			group = &output.OptionGroupsList[0]
+		} else {
+			description := optionGroup.Spec.Description
+			if len(description) == 0 {
+				description = fmt.Sprintf("Managed by RDSOptionGroup %s/%s", optionGroup.Namespace, optionGroup.Name)
+			}
+			createOptionGroup := r.GetCreateOptionGroupAPI(accessKey, secretKey, region)
+			created, e := createOptionGroup.CreateOptionGroup(ctx, &rds.CreateOptionGroupInput{
+				OptionGroupName:        pointer.String(groupName),
+				EngineName:             pointer.String(optionGroup.Spec.EngineName),
+				MajorEngineVersion:     pointer.String(optionGroup.Spec.MajorEngineVersion),
+				OptionGroupDescription: pointer.String(description),
+			})
+			var existsErr *rdstypesv2.OptionGroupAlreadyExistsFault
+			if goerrors.As(e, &existsErr) {
+				// the name is the idempotency key of the creation, this is a retry of a request that timed out
+				logger.Info("DB option group already created, retry syncing")
+				returnRequeue(optionGroupStatusReasonCreating, optionGroupStatusMessageCreating)
+				return true
+			}
+			if e != nil {
+				logger.Error(e, "Failed to create DB option group")
+				returnError(e, getAWSErrorReason(e, optionGroupStatusReasonBackendError), optionGroupStatusMessageCreateError)
+				return true
+			}
+			logger.Info("DB option group created")
+			group = created.OptionGroup
+		}
+		if group == nil {
+			return false
+		}
+
+		if group.OptionGroupArn != nil {
+			optionGroup.Status.OptionGroupARN = *group.OptionGroupArn
+		}
+		engine := pointer.StringDeref(group.EngineName, "")
+		version := pointer.StringDeref(group.MajorEngineVersion, "")
+		if (len(engine) > 0 && engine != optionGroup.Spec.EngineName) || (len(version) > 0 && version != optionGroup.Spec.MajorEngineVersion) {
+			e := fmt.Errorf(optionGroupStatusMessageEngineChanged, groupName, engine, version)
+			returnError(e, optionGroupStatusReasonInputError, e.Error())
+			return true
+		}
+
+		include, remove, e := getOptionGroupChanges(optionGroup.Spec.Options, group.Options)
+		if e != nil {
+			returnError(e, optionGroupStatusReasonInputError, e.Error())
+			return true
+		}
+		if len(include) > 0 || len(remove) > 0 {
+			now := time.Now()
+			until, window, e := getActiveFreezeWindow(r.FreezeWindows, &inventory, now)
+			if e != nil {
+				returnError(e, optionGroupStatusReasonInputError, e.Error())
+				return true
+			}
+			if !until.IsZero() {
+				logger.Info("Modifications of DB option group options deferred by freeze window", "until", until)
+				returnReady(optionGroupStatusReasonFreezeWindow, fmt.Sprintf(optionGroupStatusMessageFreezeWindow,
+					until.Format(time.RFC3339), window), until.Sub(now))
+				return true
+			}
+
+			modifyOptionGroup := r.GetModifyOptionGroupAPI(accessKey, secretKey, region)
+			if _, e := modifyOptionGroup.ModifyOptionGroup(ctx, &rds.ModifyOptionGroupInput{
+				OptionGroupName:  pointer.String(groupName),
+				OptionsToInclude: include,
+				OptionsToRemove:  remove,
+				ApplyImmediately: true,
+			}); e != nil {
+				logger.Error(e, "Failed to modify options of DB option group")
+				returnError(e, getAWSErrorReason(e, optionGroupStatusReasonBackendError), optionGroupStatusMessageModifyError)
+				return true
+			}
+			logger.Info("DB option group options modified", "Included", len(include), "Removed", len(remove))
+		}
+		return false
+	}
+
+	if err = r.Get(ctx, req.NamespacedName, &optionGroup); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("RDS Option Group resource not found, has been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error fetching RDS Option Group for reconcile")
+		return ctrl.Result{}, err
+	}
+
+	groupName = getOptionGroupName(&optionGroup)
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", optionGroup.Spec.InventoryRef.Namespace, optionGroup.Spec.InventoryRef.Name),
+		"DB Option Group", groupName)
+	ctx = log.IntoContext(ctx, logger)
+
+	defer updateOptionGroupReadyCondition()
+
+	if checkFinalizer() {
+		return
+	}
+
+	if checkInventory() {
+		return
+	}
+
+	if validateOptions() {
+		return
+	}
+
+	if syncOptionGroup() {
+		return
+	}
+
+	returnReady(optionGroupStatusReasonReady, "", optionGroupSyncInterval)
+	return
+}
+
+// getOptionGroupName returns the name of the DB option group, defaults to the name of the RDSOptionGroup
+func getOptionGroupName(optionGroup *rdsdbaasv1alpha1.RDSOptionGroup) string {
+	if len(optionGroup.Spec.OptionGroupName) > 0 {
+		return optionGroup.Spec.OptionGroupName
+	}
+	return optionGroup.Name
+}
+
+// getReferencedOptionGroupName returns the name of the DB option group of the RDSOptionGroup referenced by the
+// Instance, the RDSOptionGroup must be ready, of the Inventory of the Instance and of its engine version
+func (r *RDSInstanceReconciler) getReferencedOptionGroupName(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance, engine, version, name string) (string, error) {
+	optionGroup := &rdsdbaasv1alpha1.RDSOptionGroup{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Namespace, Name: name}, optionGroup); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("RDSOptionGroup %s not found", name)
+		}
+		return "", err
+	}
+	if optionGroup.Spec.InventoryRef != rdsInstance.Spec.InventoryRef {
+		return "", fmt.Errorf("RDSOptionGroup %s is not of Inventory %s/%s", name, rdsInstance.Spec.InventoryRef.Namespace,
+			rdsInstance.Spec.InventoryRef.Name)
+	}
+	if optionGroup.Spec.EngineName != engine || (len(version) > 0 && version != optionGroup.Spec.MajorEngineVersion &&
+		!strings.HasPrefix(version, optionGroup.Spec.MajorEngineVersion+".")) {
+		return "", fmt.Errorf("RDSOptionGroup %s is of engine %s version %s, not of engine %s version %s", name,
+			optionGroup.Spec.EngineName, optionGroup.Spec.MajorEngineVersion, engine, version)
+	}
+	if !apimeta.IsStatusConditionTrue(optionGroup.Status.Conditions, optionGroupConditionReady) {
+		return "", fmt.Errorf("RDSOptionGroup %s is not ready", name)
+	}
+	return getOptionGroupName(optionGroup), nil
+}
+
+// validateOptionGroupOptions checks the options of the spec are available for the engine version of the DB option
+// group, with the options they depend on, without the options they conflict with, and with valid versions, ports and
+// settings
+func validateOptionGroupOptions(optionGroup *rdsdbaasv1alpha1.RDSOptionGroup, available map[string]rdstypesv2.OptionGroupOption) error {
+	names := map[string]struct{}{}
+	for _, o := range optionGroup.Spec.Options {
+		if _, ok := names[o.OptionName]; ok {
+			return fmt.Errorf(optionGroupStatusMessageDuplicateOption, o.OptionName)
+		}
+		names[o.OptionName] = struct{}{}
+	}
+
+	for _, o := range optionGroup.Spec.Options {
+		a, ok := available[o.OptionName]
+		if !ok {
+			return fmt.Errorf(optionGroupStatusMessageUnknownOption, o.OptionName, optionGroup.Spec.EngineName, optionGroup.Spec.MajorEngineVersion)
+		}
+		for _, d := range a.OptionsDependedOn {
+			if _, ok := names[d]; !ok {
+				return fmt.Errorf(optionGroupStatusMessageOptionDependency, o.OptionName, d)
+			}
+		}
+		for _, c := range a.OptionsConflictsWith {
+			if _, ok := names[c]; ok {
+				return fmt.Errorf(optionGroupStatusMessageOptionConflict, o.OptionName, c)
+			}
+		}
+		if a.PortRequired && o.Port == nil && a.DefaultPort == nil {
+			return fmt.Errorf(optionGroupStatusMessagePortRequired, o.OptionName)
+		}
+		if len(o.OptionVersion) > 0 {
+			found := false
+			for _, v := range a.OptionGroupOptionVersions {
+				if pointer.StringDeref(v.Version, "") == o.OptionVersion {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf(optionGroupStatusMessageUnknownVersion, o.OptionVersion, o.OptionName)
+			}
+		}
+		settings := map[string]rdstypesv2.OptionGroupOptionSetting{}
+		for _, s := range a.OptionGroupOptionSettings {
+			settings[pointer.StringDeref(s.SettingName, "")] = s
+		}
+		for _, name := range sortedKeys(o.OptionSettings) {
+			s, ok := settings[name]
+			if !ok {
+				return fmt.Errorf(optionGroupStatusMessageUnknownSetting, name, o.OptionName)
+			}
+			if !s.IsModifiable {
+				return fmt.Errorf(optionGroupStatusMessageSettingNotModifiable, name, o.OptionName)
+			}
+		}
+		for _, s := range a.OptionGroupOptionSettings {
+			if _, ok := o.OptionSettings[pointer.StringDeref(s.SettingName, "")]; s.IsRequired && !ok && s.DefaultValue == nil {
+				return fmt.Errorf(optionGroupStatusMessageSettingRequired, o.OptionName, pointer.StringDeref(s.SettingName, ""))
+			}
+		}
+	}
+	return nil
+}
+
+// getOptionGroupChanges returns the options of the spec to add to or modify in the DB option group, and the names
+// of the options of the DB option group not in the spec to remove
+func getOptionGroupChanges(options []rdsdbaasv1alpha1.RDSOption, current []rdstypesv2.Option) ([]rdstypesv2.OptionConfiguration, []string, error) {
+	currentOptions := map[string]rdstypesv2.Option{}
+	for _, o := range current {
+		currentOptions[pointer.StringDeref(o.OptionName, "")] = o
+	}
+
+	var include []rdstypesv2.OptionConfiguration
+	names := map[string]struct{}{}
+	for _, o := range options {
+		names[o.OptionName] = struct{}{}
+		if c, ok := currentOptions[o.OptionName]; ok && !isOptionModified(o, c) {
+			continue
+		}
+		configuration := rdstypesv2.OptionConfiguration{
+			OptionName:                  pointer.String(o.OptionName),
+			Port:                        o.Port,
+			VpcSecurityGroupMemberships: o.VpcSecurityGroupMemberships,
+		}
+		if len(o.OptionVersion) > 0 {
+			configuration.OptionVersion = pointer.String(o.OptionVersion)
+		}
+		for _, name := range sortedKeys(o.OptionSettings) {
+			configuration.OptionSettings = append(configuration.OptionSettings, rdstypesv2.OptionSetting{
+				Name:  pointer.String(name),
+				Value: pointer.String(o.OptionSettings[name]),
+			})
+		}
+		include = append(include, configuration)
+	}
+
+	var remove []string
+	for name, c := range currentOptions {
+		if _, ok := names[name]; ok {
+			continue
+		}
+		if c.Permanent {
+			return nil, nil, fmt.Errorf(optionGroupStatusMessagePermanentOption, name)
+		}
+		remove = append(remove, name)
+	}
+	sort.Strings(remove)
+	return include, remove, nil
+}
+
+// isOptionModified returns true if the version, port, security groups or settings of the option of the spec differ
+// from the option of the DB option group, the fields not set in the spec are not compared
+func isOptionModified(option rdsdbaasv1alpha1.RDSOption, current rdstypesv2.Option) bool {
+	if len(option.OptionVersion) > 0 && option.OptionVersion != pointer.StringDeref(current.OptionVersion, "") {
+		return true
+	}
+	if option.Port != nil && *option.Port != pointer.Int32Deref(current.Port, 0) {
+		return true
+	}
+	if len(option.VpcSecurityGroupMemberships) > 0 {
+		var groups []string
+		for _, m := range current.VpcSecurityGroupMemberships {
+			groups = append(groups, pointer.StringDeref(m.VpcSecurityGroupId, ""))
+		}
+		if strings.Join(mergeStrings(option.VpcSecurityGroupMemberships, nil), ",") != strings.Join(mergeStrings(groups, nil), ",") {
+			return true
+		}
+	}
+	settings := map[string]string{}
+	for _, s := range current.OptionSettings {
+		settings[pointer.StringDeref(s.Name, "")] = pointer.StringDeref(s.Value, "")
+	}
+	for name, value := range option.OptionSettings {
+		if settings[name] != value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RDSOptionGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rdsdbaasv1alpha1.RDSOptionGroup{}).
+		Complete(r.GracefulShutdown.reconciler(r))
+}
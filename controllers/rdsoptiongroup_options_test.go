@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var _ = Describe("Option Group options", func() {
+	available := map[string]rdstypesv2.OptionGroupOption{
+		"APEX":     {Name: pointer.String("APEX")},
+		"APEX-DEV": {Name: pointer.String("APEX-DEV"), OptionsDependedOn: []string{"APEX"}},
+		"NATIVE_NETWORK_ENCRYPTION": {Name: pointer.String("NATIVE_NETWORK_ENCRYPTION"),
+			OptionsConflictsWith: []string{"SSL"}},
+		"SSL": {Name: pointer.String("SSL"), PortRequired: true},
+		"SQLSERVER_BACKUP_RESTORE": {Name: pointer.String("SQLSERVER_BACKUP_RESTORE"),
+			OptionGroupOptionSettings: []rdstypesv2.OptionGroupOptionSetting{
+				{SettingName: pointer.String("IAM_ROLE_ARN"), IsModifiable: true, IsRequired: true},
+			}},
+	}
+	optionGroup := func(options ...rdsdbaasv1alpha1.RDSOption) *rdsdbaasv1alpha1.RDSOptionGroup {
+		return &rdsdbaasv1alpha1.RDSOptionGroup{Spec: rdsdbaasv1alpha1.RDSOptionGroupSpec{
+			EngineName:         "oracle-ee",
+			MajorEngineVersion: "19",
+			Options:            options,
+		}}
+	}
+
+	It("should validate the options against the options available for the engine version", func() {
+		Expect(validateOptionGroupOptions(optionGroup(
+			rdsdbaasv1alpha1.RDSOption{OptionName: "APEX"},
+			rdsdbaasv1alpha1.RDSOption{OptionName: "APEX-DEV"},
+		), available)).Should(Succeed())
+
+		Expect(validateOptionGroupOptions(optionGroup(rdsdbaasv1alpha1.RDSOption{OptionName: "OLS"}), available)).
+			Should(MatchError("Option OLS is not available for engine oracle-ee version 19"))
+		Expect(validateOptionGroupOptions(optionGroup(rdsdbaasv1alpha1.RDSOption{OptionName: "APEX-DEV"}), available)).
+			Should(MatchError("Option APEX-DEV requires option APEX"))
+		Expect(validateOptionGroupOptions(optionGroup(
+			rdsdbaasv1alpha1.RDSOption{OptionName: "NATIVE_NETWORK_ENCRYPTION"},
+			rdsdbaasv1alpha1.RDSOption{OptionName: "SSL", Port: pointer.Int32(2484)},
+		), available)).Should(MatchError("Option NATIVE_NETWORK_ENCRYPTION conflicts with option SSL"))
+		Expect(validateOptionGroupOptions(optionGroup(rdsdbaasv1alpha1.RDSOption{OptionName: "SSL"}), available)).
+			Should(MatchError("Option SSL requires a port"))
+		Expect(validateOptionGroupOptions(optionGroup(rdsdbaasv1alpha1.RDSOption{OptionName: "SQLSERVER_BACKUP_RESTORE"}), available)).
+			Should(MatchError("Option SQLSERVER_BACKUP_RESTORE requires setting IAM_ROLE_ARN"))
+	})
+
+	It("should return the options to include and remove", func() {
+		include, remove, err := getOptionGroupChanges([]rdsdbaasv1alpha1.RDSOption{
+			{OptionName: "APEX", OptionVersion: "21.1.v1"},
+			{OptionName: "SSL", Port: pointer.Int32(2484)},
+		}, []rdstypesv2.Option{
+			{OptionName: pointer.String("APEX"), OptionVersion: pointer.String("21.1.v1")},
+			{OptionName: pointer.String("SSL"), Port: pointer.Int32(2485)},
+			{OptionName: pointer.String("OEM")},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(include).Should(HaveLen(1))
+		Expect(*include[0].OptionName).Should(Equal("SSL"))
+		Expect(remove).Should(Equal([]string{"OEM"}))
+
+		_, _, err = getOptionGroupChanges(nil, []rdstypesv2.Option{{OptionName: pointer.String("TDE"), Permanent: true}})
+		Expect(err).Should(MatchError("Option TDE is permanent and cannot be removed"))
+	})
+})
@@ -0,0 +1,587 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	parameterGroupFinalizer = "rds.dbaas.redhat.com/parametergroup"
+
+	parameterApplyTypeDynamic         = "dynamic"
+	parameterApplyStatusPendingReboot = "pending-reboot"
+	parameterApplyStatusApplying      = "applying"
+
+	// the maximum number of parameters modified by a call
+	maxModifiedParameters = 20
+
+	parameterGroupRequeueInterval = 1 * time.Minute
+	parameterGroupSyncInterval    = 10 * time.Minute
+
+	parameterGroupConditionReady = "ParameterGroupReady"
+
+	parameterGroupStatusReasonReady         = "Ready"
+	parameterGroupStatusReasonCreating      = "Creating"
+	parameterGroupStatusReasonPendingReboot = "PendingReboot"
+	parameterGroupStatusReasonFreezeWindow  = "FreezeWindow"
+	parameterGroupStatusReasonInUse         = "InUse"
+	parameterGroupStatusReasonBackendError  = "BackendError"
+	parameterGroupStatusReasonInputError    = "InputError"
+	parameterGroupStatusReasonNotFound      = "NotFound"
+	parameterGroupStatusReasonUnreachable   = "Unreachable"
+	parameterGroupStatusReasonNotAllowed    = "NotAllowed"
+
+	parameterGroupStatusMessageCreateError        = "Failed to create DB parameter group"
+	parameterGroupStatusMessageCreating           = "Creating DB parameter group"
+	parameterGroupStatusMessageGetError           = "Failed to get DB parameter group"
+	parameterGroupStatusMessageGetParametersError = "Failed to get parameters of DB parameter group"
+	parameterGroupStatusMessageModifyError        = "Failed to modify parameters of DB parameter group"
+	parameterGroupStatusMessageDeleteError        = "Failed to delete DB parameter group"
+	parameterGroupStatusMessageGetInstancesError  = "Failed to get DB instances of DB parameter group"
+	parameterGroupStatusMessageInUse              = "DB parameter group is used by DB instances and cannot be deleted"
+	parameterGroupStatusMessageFamilyChanged      = "The family of DB parameter group %s is %s and cannot be changed"
+	parameterGroupStatusMessageNotCreated         = "DB parameter group %s already exists, the Parameter Groups of namespace %s only manage the DB parameter groups they create"
+	parameterGroupStatusMessageUnknownParameter   = "Parameter %s is not a parameter of DB parameter group family %s"
+	parameterGroupStatusMessageNotModifiable      = "Parameter %s cannot be modified"
+	parameterGroupStatusMessagePendingReboot      = "Parameters %s are applied once DB instances %s are rebooted"
+	parameterGroupStatusMessageFreezeWindow       = "Modifications of parameters %s are deferred until %s by freeze window %q"
+	parameterGroupStatusMessageUpdateError        = "Failed to update Parameter Group"
+	parameterGroupStatusMessageInventoryNotFound  = "Inventory not found"
+	parameterGroupStatusMessageInventoryNotReady  = "Inventory not ready"
+	parameterGroupStatusMessageGetInventoryError  = "Failed to get Inventory"
+	parameterGroupStatusMessageCredentialsError   = "Failed to get credentials of Inventory"
+)
+
+// RDSParameterGroupReconciler reconciles a RDSParameterGroup object
+type RDSParameterGroupReconciler struct {
+	client.Client
+	Scheme                             *runtime.Scheme
+	GetCreateDBParameterGroupAPI       func(accessKey, secretKey, region string) controllersrds.CreateDBParameterGroupAPI
+	GetDescribeDBParameterGroupsAPI    func(accessKey, secretKey, region string) controllersrds.DescribeDBParameterGroupsAPI
+	GetModifyDBParameterGroupAPI       func(accessKey, secretKey, region string) controllersrds.ModifyDBParameterGroupAPI
+	GetDeleteDBParameterGroupAPI       func(accessKey, secretKey, region string) controllersrds.DeleteDBParameterGroupAPI
+	GetDescribeDBParametersAPI         func(accessKey, secretKey, region string) controllersrds.DescribeDBParametersAPI
+	GetDescribeDBInstancesPaginatorAPI func(accessKey, secretKey, region string) controllersrds.DescribeDBInstancesPaginatorAPI
+	// the parameters are not modified during the freeze windows of the operator and of the Inventory
+	FreezeWindows []FreezeWindow
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the Parameter Groups may only use the Inventories in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+}
+
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsparametergroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsparametergroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdsparametergroups/finalizers,verbs=update
+
+// Reconcile creates the DB parameter group and sets its parameters to the values of the spec, the static parameters
+// are applied once the DB instances using the DB parameter group are rebooted. The DB parameter group is deleted
+// from AWS when the RDSParameterGroup is deleted.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
+func (r *RDSParameterGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	var parameterGroupStatus, parameterGroupStatusReason, parameterGroupStatusMessage string
+
+	var parameterGroup rdsdbaasv1alpha1.RDSParameterGroup
+	var inventory rdsdbaasv1alpha1.RDSInventory
+	var accessKey, secretKey, region string
+	var groupName string
+
+	returnError := func(e error, reason, message string) {
+		result = ctrl.Result{}
+		err = e
+		parameterGroupStatus = string(metav1.ConditionFalse)
+		parameterGroupStatusReason = reason
+		parameterGroupStatusMessage = message
+	}
+
+	returnRequeue := func(reason, message string) {
+		result = ctrl.Result{RequeueAfter: parameterGroupRequeueInterval}
+		err = nil
+		parameterGroupStatus = string(metav1.ConditionFalse)
+		parameterGroupStatusReason = reason
+		parameterGroupStatusMessage = message
+	}
+
+	returnReady := func(reason, message string, requeueAfter time.Duration) {
+		result = ctrl.Result{RequeueAfter: requeueAfter}
+		err = nil
+		parameterGroupStatus = string(metav1.ConditionTrue)
+		parameterGroupStatusReason = reason
+		parameterGroupStatusMessage = message
+	}
+
+	updateParameterGroupReadyCondition := func() {
+		// the status is not updated once the finalizer is removed or when the resource is requeued
+		if len(parameterGroupStatusReason) == 0 {
+			return
+		}
+		condition := metav1.Condition{
+			Type:    parameterGroupConditionReady,
+			Status:  metav1.ConditionStatus(parameterGroupStatus),
+			Reason:  parameterGroupStatusReason,
+			Message: parameterGroupStatusMessage,
+		}
+		apimeta.SetStatusCondition(&parameterGroup.Status.Conditions, condition)
+		if e := updateStatus(ctx, r.Client, &parameterGroup); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Parameter Group modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+			} else {
+				logger.Error(e, "Failed to update Parameter Group status")
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+
+	checkInventory := func() bool {
+		if e := r.Get(ctx, client.ObjectKey{Namespace: parameterGroup.Spec.InventoryRef.Namespace,
+			Name: parameterGroup.Spec.InventoryRef.Name}, &inventory); e != nil {
+			if errors.IsNotFound(e) {
+				logger.Info("RDS Inventory resource not found, may have been deleted")
+				returnError(e, parameterGroupStatusReasonNotFound, parameterGroupStatusMessageInventoryNotFound)
+				return true
+			}
+			logger.Error(e, "Failed to get RDS Inventory")
+			returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageGetInventoryError)
+			return true
+		}
+		if e := checkInventoryReference(r.NamespacePolicy, parameterGroup.Namespace, &inventory); e != nil {
+			logger.Info("RDS Inventory not allowed for the Parameter Group", "reason", e.Error())
+			returnError(e, parameterGroupStatusReasonNotAllowed, e.Error())
+			return true
+		}
+
+		if condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); condition == nil || condition.Status != metav1.ConditionTrue {
+			logger.Info("RDS Inventory not ready")
+			returnRequeue(parameterGroupStatusReasonUnreachable, parameterGroupStatusMessageInventoryNotReady)
+			return true
+		}
+
+		secret := &v1.Secret{}
+		if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
+			logger.Error(e, "Failed to get credentials of RDS Inventory")
+			returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageCredentialsError)
+			return true
+		}
+		accessKey = string(secret.Data[awsAccessKeyID])
+		secretKey = string(secret.Data[awsSecretAccessKey])
+		region = string(secret.Data[awsRegion])
+		logger = logger.WithValues(logging.KeyRegion, region)
+		ctx = log.IntoContext(ctx, logger)
+		return false
+	}
+
+	checkFinalizer := func() bool {
+		if parameterGroup.DeletionTimestamp.IsZero() {
+			if !controllerutil.ContainsFinalizer(&parameterGroup, parameterGroupFinalizer) {
+				if e := updateObject(ctx, r.Client, &parameterGroup, func() error {
+					controllerutil.AddFinalizer(&parameterGroup, parameterGroupFinalizer)
+					return nil
+				}); e != nil {
+					if errors.IsConflict(e) {
+						logger.Info("Parameter Group modified, retry reconciling")
+						result = ctrl.Result{Requeue: true}
+						return true
+					}
+					logger.Error(e, "Failed to add finalizer to Parameter Group")
+					returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageUpdateError)
+					return true
+				}
+				logger.Info("Finalizer added to Parameter Group")
+			}
+			return false
+		}
+
+		if !controllerutil.ContainsFinalizer(&parameterGroup, parameterGroupFinalizer) {
+			return true
+		}
+		if e := r.Get(ctx, client.ObjectKey{Namespace: parameterGroup.Spec.InventoryRef.Namespace,
+			Name: parameterGroup.Spec.InventoryRef.Name}, &inventory); e != nil && errors.IsNotFound(e) {
+			// the DB parameter group cannot be deleted without the credentials of the Inventory
+			logger.Info("RDS Inventory resource not found, DB parameter group kept in AWS")
+		} else if e == nil && (checkInventoryReference(r.NamespacePolicy, parameterGroup.Namespace, &inventory) != nil ||
+			(parameterGroup.Namespace != inventory.Namespace && len(parameterGroup.Status.ParameterGroupARN) == 0)) {
+			// the DB parameter group is only deleted by the namespaces allowed to use the Inventory, and by the other
+			// namespaces than the one of the Inventory only if they created it
+			logger.Info("RDS Inventory not allowed for the Parameter Group, DB parameter group kept in AWS")
+		} else {
+			if checkInventory() {
+				return true
+			}
+			deleteDBParameterGroup := r.GetDeleteDBParameterGroupAPI(accessKey, secretKey, region)
+			if _, e := deleteDBParameterGroup.DeleteDBParameterGroup(ctx, &rds.DeleteDBParameterGroupInput{
+				DBParameterGroupName: pointer.String(groupName),
+			}); e != nil {
+				var notFoundErr *rdstypesv2.DBParameterGroupNotFoundFault
+				var stateErr *rdstypesv2.InvalidDBParameterGroupStateFault
+				if goerrors.As(e, &stateErr) {
+					logger.Info("DB parameter group in use, retry deleting")
+					returnRequeue(parameterGroupStatusReasonInUse, parameterGroupStatusMessageInUse)
+					return true
+				} else if !goerrors.As(e, &notFoundErr) {
+					logger.Error(e, "Failed to delete DB parameter group")
+					returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageDeleteError)
+					return true
+				}
+			}
+			logger.Info("DB parameter group deleted")
+		}
+
+		if e := updateObject(ctx, r.Client, &parameterGroup, func() error {
+			controllerutil.RemoveFinalizer(&parameterGroup, parameterGroupFinalizer)
+			return nil
+		}); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Parameter Group modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+				return true
+			}
+			logger.Error(e, "Failed to remove finalizer from Parameter Group")
+			returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageUpdateError)
+			return true
+		}
+		logger.Info("Finalizer removed from Parameter Group")
+		return true
+	}
+
+	syncDBParameterGroup := func() bool {
+		describeDBParameterGroups := r.GetDescribeDBParameterGroupsAPI(accessKey, secretKey, region)
+		output, e := describeDBParameterGroups.DescribeDBParameterGroups(ctx, &rds.DescribeDBParameterGroupsInput{
+			DBParameterGroupName: pointer.String(groupName),
+		})
+		var notFoundErr *rdstypesv2.DBParameterGroupNotFoundFault
+		if e != nil && !goerrors.As(e, &notFoundErr) {
+			logger.Error(e, "Failed to get DB parameter group")
+			returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageGetError)
+			return true
+		}
+
+		var group *rdstypesv2.DBParameterGroup
+		if e == nil && len(output.DBParameterGroups) > 0 {
+			if parameterGroup.Namespace != inventory.Namespace && len(parameterGroup.Status.ParameterGroupARN) == 0 {
+				// the DB parameter groups of the Inventory are not adopted by the other namespaces
+				e := fmt.Errorf(parameterGroupStatusMessageNotCreated, groupName, parameterGroup.Namespace)
+				returnError(e, parameterGroupStatusReasonNotAllowed, e.Error())
+				return true
+			}
+			group = &output.DBParameterGroups[0]
+		} else {
+			description := parameterGroup.Spec.Description
+			if len(description) == 0 {
+				description = fmt.Sprintf("Managed by RDSParameterGroup %s/%s", parameterGroup.Namespace, parameterGroup.Name)
+			}
+			createDBParameterGroup := r.GetCreateDBParameterGroupAPI(accessKey, secretKey, region)
+			created, e := createDBParameterGroup.CreateDBParameterGroup(ctx, &rds.CreateDBParameterGroupInput{
+				DBParameterGroupName:   pointer.String(groupName),
+				DBParameterGroupFamily: pointer.String(parameterGroup.Spec.Family),
+				Description:            pointer.String(description),
+			})
+			var existsErr *rdstypesv2.DBParameterGroupAlreadyExistsFault
+			if goerrors.As(e, &existsErr) {
+				// the name is the idempotency key of the creation, this is a retry of a request that timed out
+				logger.Info("DB parameter group already created, retry syncing")
+				returnRequeue(parameterGroupStatusReasonCreating, parameterGroupStatusMessageCreating)
+				return true
+			}
+			if e != nil {
+				logger.Error(e, "Failed to create DB parameter group")
+				returnError(e, getAWSErrorReason(e, parameterGroupStatusReasonBackendError), parameterGroupStatusMessageCreateError)
+				return true
+			}
+			logger.Info("DB parameter group created")
+			group = created.DBParameterGroup
+		}
+
+		if group != nil {
+			if group.DBParameterGroupArn != nil {
+				parameterGroup.Status.ParameterGroupARN = *group.DBParameterGroupArn
+			}
+			if family := pointer.StringDeref(group.DBParameterGroupFamily, ""); len(family) > 0 && family != parameterGroup.Spec.Family {
+				e := fmt.Errorf(parameterGroupStatusMessageFamilyChanged, groupName, family)
+				returnError(e, parameterGroupStatusReasonInputError, e.Error())
+				return true
+			}
+		}
+		return false
+	}
+
+	syncParameters := func() (bool, []string) {
+		current := map[string]rdstypesv2.Parameter{}
+		paginator := rds.NewDescribeDBParametersPaginator(r.GetDescribeDBParametersAPI(accessKey, secretKey, region),
+			&rds.DescribeDBParametersInput{DBParameterGroupName: pointer.String(groupName)})
+		for paginator.HasMorePages() {
+			output, e := paginator.NextPage(ctx)
+			if e != nil {
+				logger.Error(e, "Failed to get parameters of DB parameter group")
+				returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageGetParametersError)
+				return true, nil
+			}
+			for _, p := range output.Parameters {
+				if p.ParameterName != nil {
+					current[*p.ParameterName] = p
+				}
+			}
+		}
+
+		modified, static, e := getModifiedParameters(parameterGroup.Spec.Family, parameterGroup.Spec.Parameters, current)
+		if e != nil {
+			returnError(e, parameterGroupStatusReasonInputError, e.Error())
+			return true, nil
+		}
+
+		if len(modified) > 0 {
+			now := time.Now()
+			until, window, e := getActiveFreezeWindow(r.FreezeWindows, &inventory, now)
+			if e != nil {
+				returnError(e, parameterGroupStatusReasonInputError, e.Error())
+				return true, nil
+			}
+			if !until.IsZero() {
+				var names []string
+				for _, p := range modified {
+					names = append(names, *p.ParameterName)
+				}
+				logger.Info("Modifications of DB parameter group parameters deferred by freeze window", "until", until)
+				returnReady(parameterGroupStatusReasonFreezeWindow, fmt.Sprintf(parameterGroupStatusMessageFreezeWindow,
+					strings.Join(names, ","), until.Format(time.RFC3339), window), until.Sub(now))
+				return true, nil
+			}
+		}
+
+		modifyDBParameterGroup := r.GetModifyDBParameterGroupAPI(accessKey, secretKey, region)
+		for i := 0; i < len(modified); i += maxModifiedParameters {
+			end := i + maxModifiedParameters
+			if end > len(modified) {
+				end = len(modified)
+			}
+			if _, e := modifyDBParameterGroup.ModifyDBParameterGroup(ctx, &rds.ModifyDBParameterGroupInput{
+				DBParameterGroupName: pointer.String(groupName),
+				Parameters:           modified[i:end],
+			}); e != nil {
+				logger.Error(e, "Failed to modify parameters of DB parameter group")
+				returnError(e, getAWSErrorReason(e, parameterGroupStatusReasonBackendError), parameterGroupStatusMessageModifyError)
+				return true, nil
+			}
+		}
+		if len(modified) > 0 {
+			logger.Info("DB parameter group parameters modified", "Parameters", len(modified))
+		}
+		return false, static
+	}
+
+	syncPendingReboot := func(static []string) bool {
+		var pendingInstances []string
+		applying := false
+		paginator := r.GetDescribeDBInstancesPaginatorAPI(accessKey, secretKey, region)
+		for paginator.HasMorePages() {
+			output, e := paginator.NextPage(ctx)
+			if e != nil {
+				logger.Error(e, "Failed to get DB instances of DB parameter group")
+				returnError(e, parameterGroupStatusReasonBackendError, parameterGroupStatusMessageGetInstancesError)
+				return true
+			}
+			for _, instance := range output.DBInstances {
+				for _, group := range instance.DBParameterGroups {
+					if pointer.StringDeref(group.DBParameterGroupName, "") != groupName {
+						continue
+					}
+					switch pointer.StringDeref(group.ParameterApplyStatus, "") {
+					case parameterApplyStatusPendingReboot:
+						pendingInstances = append(pendingInstances, pointer.StringDeref(instance.DBInstanceIdentifier, ""))
+					case parameterApplyStatusApplying:
+						applying = true
+					}
+				}
+			}
+		}
+
+		// the static parameters are pending until the DB instances report the apply status of the modification
+		pendingParameters := static
+		if len(pendingInstances) > 0 || applying {
+			pendingParameters = mergeStrings(parameterGroup.Status.PendingRebootParameters, static)
+		}
+		sort.Strings(pendingInstances)
+		parameterGroup.Status.PendingRebootParameters = pendingParameters
+		parameterGroup.Status.PendingRebootDBInstances = pendingInstances
+
+		if len(pendingInstances) > 0 {
+			parameters := "-"
+			if len(pendingParameters) > 0 {
+				parameters = strings.Join(pendingParameters, ",")
+			}
+			returnReady(parameterGroupStatusReasonPendingReboot, fmt.Sprintf(parameterGroupStatusMessagePendingReboot,
+				parameters, strings.Join(pendingInstances, ",")), parameterGroupRequeueInterval)
+		} else if applying || len(static) > 0 {
+			returnReady(parameterGroupStatusReasonReady, "", parameterGroupRequeueInterval)
+		} else {
+			returnReady(parameterGroupStatusReasonReady, "", parameterGroupSyncInterval)
+		}
+		return false
+	}
+
+	if err = r.Get(ctx, req.NamespacedName, &parameterGroup); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("RDS Parameter Group resource not found, has been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error fetching RDS Parameter Group for reconcile")
+		return ctrl.Result{}, err
+	}
+
+	groupName = getDBParameterGroupName(&parameterGroup)
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", parameterGroup.Spec.InventoryRef.Namespace, parameterGroup.Spec.InventoryRef.Name),
+		"DB Parameter Group", groupName)
+	ctx = log.IntoContext(ctx, logger)
+
+	defer updateParameterGroupReadyCondition()
+
+	if checkFinalizer() {
+		return
+	}
+
+	if checkInventory() {
+		return
+	}
+
+	if syncDBParameterGroup() {
+		return
+	}
+
+	rt, static := syncParameters()
+	if rt {
+		return
+	}
+
+	syncPendingReboot(static)
+	return
+}
+
+// getDBParameterGroupName returns the name of the DB parameter group, defaults to the name of the RDSParameterGroup
+func getDBParameterGroupName(parameterGroup *rdsdbaasv1alpha1.RDSParameterGroup) string {
+	if len(parameterGroup.Spec.ParameterGroupName) > 0 {
+		return parameterGroup.Spec.ParameterGroupName
+	}
+	return parameterGroup.Name
+}
+
+// getReferencedDBParameterGroupName returns the name of the DB parameter group of the RDSParameterGroup referenced by
+// the Instance, the RDSParameterGroup must be ready and of the Inventory of the Instance
+func (r *RDSInstanceReconciler) getReferencedDBParameterGroupName(ctx context.Context, rdsInstance *rdsdbaasv1alpha1.RDSInstance, name string) (string, error) {
+	parameterGroup := &rdsdbaasv1alpha1.RDSParameterGroup{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: rdsInstance.Namespace, Name: name}, parameterGroup); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("RDSParameterGroup %s not found", name)
+		}
+		return "", err
+	}
+	if parameterGroup.Spec.InventoryRef != rdsInstance.Spec.InventoryRef {
+		return "", fmt.Errorf("RDSParameterGroup %s is not of Inventory %s/%s", name, rdsInstance.Spec.InventoryRef.Namespace,
+			rdsInstance.Spec.InventoryRef.Name)
+	}
+	if !apimeta.IsStatusConditionTrue(parameterGroup.Status.Conditions, parameterGroupConditionReady) {
+		return "", fmt.Errorf("RDSParameterGroup %s is not ready", name)
+	}
+	return getDBParameterGroupName(parameterGroup), nil
+}
+
+// getModifiedParameters returns the parameters of the spec whose current value differs and the names of the static
+// ones, which are applied once the DB instances are rebooted
+func getModifiedParameters(family string, parameters map[string]string, current map[string]rdstypesv2.Parameter) ([]rdstypesv2.Parameter, []string, error) {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var modified []rdstypesv2.Parameter
+	var static []string
+	for _, name := range names {
+		value := parameters[name]
+		p, ok := current[name]
+		if !ok {
+			return nil, nil, fmt.Errorf(parameterGroupStatusMessageUnknownParameter, name, family)
+		}
+		if pointer.StringDeref(p.ParameterValue, "") == value {
+			continue
+		}
+		if !p.IsModifiable {
+			return nil, nil, fmt.Errorf(parameterGroupStatusMessageNotModifiable, name)
+		}
+		applyMethod := rdstypesv2.ApplyMethodPendingReboot
+		if pointer.StringDeref(p.ApplyType, "") == parameterApplyTypeDynamic {
+			applyMethod = rdstypesv2.ApplyMethodImmediate
+		} else {
+			static = append(static, name)
+		}
+		modified = append(modified, rdstypesv2.Parameter{
+			ParameterName:  pointer.String(name),
+			ParameterValue: pointer.String(value),
+			ApplyMethod:    applyMethod,
+		})
+	}
+	return modified, static, nil
+}
+
+// mergeStrings returns the sorted union of the strings
+func mergeStrings(a, b []string) []string {
+	set := map[string]struct{}{}
+	for _, s := range append(append([]string{}, a...), b...) {
+		set[s] = struct{}{}
+	}
+	merged := make([]string, 0, len(set))
+	for s := range set {
+		merged = append(merged, s)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RDSParameterGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rdsdbaasv1alpha1.RDSParameterGroup{}).
+		Complete(r.GracefulShutdown.reconciler(r))
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypesv2 "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	snapshotStatusAvailable = "available"
+
+	exportTaskStatusComplete = "COMPLETE"
+	exportTaskStatusFailed   = "FAILED"
+	exportTaskStatusCanceled = "CANCELED"
+
+	snapshotRequeueInterval = 1 * time.Minute
+
+	snapshotConditionReady    = "SnapshotReady"
+	snapshotConditionExported = "Exported"
+
+	snapshotStatusReasonReady        = "Ready"
+	snapshotStatusReasonCreating     = "Creating"
+	snapshotStatusReasonExporting    = "Exporting"
+	snapshotStatusReasonBackendError = "BackendError"
+	snapshotStatusReasonNotFound     = "NotFound"
+	snapshotStatusReasonUnreachable  = "Unreachable"
+	snapshotStatusReasonNotAllowed   = "NotAllowed"
+
+	snapshotStatusMessageCreating          = "Creating DB snapshot"
+	snapshotStatusMessageCreateError       = "Failed to create DB snapshot"
+	snapshotStatusMessageGetError          = "Failed to get DB snapshot"
+	snapshotStatusMessageNotFound          = "DB snapshot not found"
+	snapshotStatusMessageShareError        = "Failed to share DB snapshot"
+	snapshotStatusMessageExporting         = "Exporting DB snapshot to Amazon S3"
+	snapshotStatusMessageExported          = "DB snapshot exported to Amazon S3"
+	snapshotStatusMessageExportError       = "Failed to export DB snapshot to Amazon S3"
+	snapshotStatusMessageExportFailed      = "Export of DB snapshot to Amazon S3 failed"
+	snapshotStatusMessageInventoryNotFound = "Inventory not found"
+	snapshotStatusMessageInventoryNotReady = "Inventory not ready"
+	snapshotStatusMessageGetInventoryError = "Failed to get Inventory"
+	snapshotStatusMessageCredentialsError  = "Failed to get credentials of Inventory"
+)
+
+// RDSSnapshotReconciler reconciles a RDSSnapshot object
+type RDSSnapshotReconciler struct {
+	client.Client
+	Scheme                          *runtime.Scheme
+	GetCreateDBSnapshotAPI          func(accessKey, secretKey, region string) controllersrds.CreateDBSnapshotAPI
+	GetDescribeDBSnapshotsAPI       func(accessKey, secretKey, region string) controllersrds.DescribeDBSnapshotsAPI
+	GetModifyDBSnapshotAttributeAPI func(accessKey, secretKey, region string) controllersrds.ModifyDBSnapshotAttributeAPI
+	GetStartExportTaskAPI           func(accessKey, secretKey, region string) controllersrds.StartExportTaskAPI
+	GetDescribeExportTasksAPI       func(accessKey, secretKey, region string) controllersrds.DescribeExportTasksAPI
+	// the reconciles in progress complete their AWS calls once the operator is stopped if set
+	GracefulShutdown *GracefulShutdown
+	// the Snapshots may only use the Inventories in the namespaces allowed by the policy if set
+	NamespacePolicy *NamespacePolicy
+}
+
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdssnapshots,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdssnapshots/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dbaas.redhat.com,resources=rdssnapshots/finalizers,verbs=update
+
+// Reconcile creates the manual DB snapshot of the source instance, shares it with the given AWS accounts
+// and exports it to Amazon S3. The DB snapshot is kept in AWS when the RDSSnapshot is deleted.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
+func (r *RDSSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	var snapshotStatus, snapshotStatusReason, snapshotStatusMessage string
+
+	var snapshot rdsdbaasv1alpha1.RDSSnapshot
+	var inventory rdsdbaasv1alpha1.RDSInventory
+	var accessKey, secretKey, region string
+
+	returnError := func(e error, reason, message string) {
+		result = ctrl.Result{}
+		err = e
+		snapshotStatus = string(metav1.ConditionFalse)
+		snapshotStatusReason = reason
+		snapshotStatusMessage = message
+	}
+
+	returnRequeue := func(reason, message string) {
+		result = ctrl.Result{RequeueAfter: snapshotRequeueInterval}
+		err = nil
+		snapshotStatus = string(metav1.ConditionFalse)
+		snapshotStatusReason = reason
+		snapshotStatusMessage = message
+	}
+
+	returnReady := func() {
+		result = ctrl.Result{}
+		err = nil
+		snapshotStatus = string(metav1.ConditionTrue)
+		snapshotStatusReason = snapshotStatusReasonReady
+	}
+
+	updateSnapshotReadyCondition := func() {
+		condition := metav1.Condition{
+			Type:    snapshotConditionReady,
+			Status:  metav1.ConditionStatus(snapshotStatus),
+			Reason:  snapshotStatusReason,
+			Message: snapshotStatusMessage,
+		}
+		apimeta.SetStatusCondition(&snapshot.Status.Conditions, condition)
+		if e := updateStatus(ctx, r.Client, &snapshot); e != nil {
+			if errors.IsConflict(e) {
+				logger.Info("Snapshot modified, retry reconciling")
+				result = ctrl.Result{Requeue: true}
+			} else {
+				logger.Error(e, "Failed to update Snapshot status")
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+
+	checkInventory := func() bool {
+		if e := r.Get(ctx, client.ObjectKey{Namespace: snapshot.Spec.InventoryRef.Namespace,
+			Name: snapshot.Spec.InventoryRef.Name}, &inventory); e != nil {
+			if errors.IsNotFound(e) {
+				logger.Info("RDS Inventory resource not found, may have been deleted")
+				returnError(e, snapshotStatusReasonNotFound, snapshotStatusMessageInventoryNotFound)
+				return true
+			}
+			logger.Error(e, "Failed to get RDS Inventory")
+			returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageGetInventoryError)
+			return true
+		}
+		if e := checkInventoryReference(r.NamespacePolicy, snapshot.Namespace, &inventory); e != nil {
+			logger.Info("RDS Inventory not allowed for the Snapshot", "reason", e.Error())
+			returnError(e, snapshotStatusReasonNotAllowed, e.Error())
+			return true
+		}
+		if e := checkSnapshotDestinations(&snapshot, &inventory); e != nil {
+			logger.Info("Destination of the DB snapshot not allowed by the RDS Inventory", "reason", e.Error())
+			returnError(e, snapshotStatusReasonNotAllowed, e.Error())
+			return true
+		}
+		if e := checkDBInstanceReference(ctx, r, snapshot.Namespace, &inventory, snapshot.Spec.DBInstanceID); e != nil {
+			logger.Info("DB instance not allowed for the Snapshot", "reason", e.Error())
+			returnError(e, snapshotStatusReasonNotAllowed, e.Error())
+			return true
+		}
+
+		if condition := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); condition == nil || condition.Status != metav1.ConditionTrue {
+			logger.Info("RDS Inventory not ready")
+			returnRequeue(snapshotStatusReasonUnreachable, snapshotStatusMessageInventoryNotReady)
+			return true
+		}
+
+		secret := &v1.Secret{}
+		if e := getInventoryCredentials(ctx, r, &inventory, secret); e != nil {
+			logger.Error(e, "Failed to get credentials of RDS Inventory")
+			returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageCredentialsError)
+			return true
+		}
+		accessKey = string(secret.Data[awsAccessKeyID])
+		secretKey = string(secret.Data[awsSecretAccessKey])
+		region = string(secret.Data[awsRegion])
+		logger = logger.WithValues(logging.KeyRegion, region)
+		ctx = log.IntoContext(ctx, logger)
+		return false
+	}
+
+	syncDBSnapshot := func() bool {
+		snapshotID := getDBSnapshotIdentifier(&snapshot)
+
+		describeDBSnapshots := r.GetDescribeDBSnapshotsAPI(accessKey, secretKey, region)
+		output, e := describeDBSnapshots.DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{
+			DBSnapshotIdentifier: pointer.String(snapshotID),
+		})
+		var notFoundErr *rdstypesv2.DBSnapshotNotFoundFault
+		if e != nil && !goerrors.As(e, &notFoundErr) {
+			logger.Error(e, "Failed to get DB snapshot")
+			returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageGetError)
+			return true
+		}
+		if e != nil || len(output.DBSnapshots) == 0 {
+			if len(snapshot.Status.SnapshotARN) > 0 {
+				e := fmt.Errorf("DB snapshot %s not found", snapshotID)
+				logger.Error(e, "DB snapshot deleted from AWS")
+				returnError(e, snapshotStatusReasonNotFound, snapshotStatusMessageNotFound)
+				return true
+			}
+			createDBSnapshot := r.GetCreateDBSnapshotAPI(accessKey, secretKey, region)
+			created, e := createDBSnapshot.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+				DBInstanceIdentifier: pointer.String(snapshot.Spec.DBInstanceID),
+				DBSnapshotIdentifier: pointer.String(snapshotID),
+			})
+			var existsErr *rdstypesv2.DBSnapshotAlreadyExistsFault
+			if goerrors.As(e, &existsErr) {
+				// the identifier is the idempotency key of the creation, this is a retry of a request that timed out
+				logger.Info("DB snapshot already created, retry syncing", "DB Snapshot", snapshotID)
+				returnRequeue(snapshotStatusReasonCreating, snapshotStatusMessageCreating)
+				return true
+			}
+			if e != nil {
+				logger.Error(e, "Failed to create DB snapshot")
+				returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageCreateError)
+				return true
+			}
+			logger.Info("DB snapshot created", "DB Snapshot", snapshotID)
+			setDBSnapshotStatus(&snapshot, created.DBSnapshot)
+		} else {
+			setDBSnapshotStatus(&snapshot, &output.DBSnapshots[0])
+		}
+
+		if snapshot.Status.SnapshotStatus != snapshotStatusAvailable {
+			returnRequeue(snapshotStatusReasonCreating, snapshotStatusMessageCreating)
+			return true
+		}
+
+		if len(snapshot.Spec.ShareWithAccounts) > 0 {
+			modifyDBSnapshotAttribute := r.GetModifyDBSnapshotAttributeAPI(accessKey, secretKey, region)
+			if _, e := modifyDBSnapshotAttribute.ModifyDBSnapshotAttribute(ctx, &rds.ModifyDBSnapshotAttributeInput{
+				DBSnapshotIdentifier: pointer.String(snapshotID),
+				AttributeName:        pointer.String("restore"),
+				ValuesToAdd:          snapshot.Spec.ShareWithAccounts,
+			}); e != nil {
+				logger.Error(e, "Failed to share DB snapshot")
+				returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageShareError)
+				return true
+			}
+		}
+		return false
+	}
+
+	exportDBSnapshot := func() bool {
+		if snapshot.Spec.Export == nil {
+			return false
+		}
+
+		if len(snapshot.Status.ExportTaskID) == 0 {
+			exportTaskID := fmt.Sprintf("%s-export", getDBSnapshotIdentifier(&snapshot))
+			startExportTask := r.GetStartExportTaskAPI(accessKey, secretKey, region)
+			input := &rds.StartExportTaskInput{
+				ExportTaskIdentifier: pointer.String(exportTaskID),
+				SourceArn:            pointer.String(snapshot.Status.SnapshotARN),
+				S3BucketName:         pointer.String(snapshot.Spec.Export.S3BucketName),
+				IamRoleArn:           pointer.String(snapshot.Spec.Export.IAMRoleARN),
+				KmsKeyId:             pointer.String(snapshot.Spec.Export.KMSKeyID),
+				ExportOnly:           snapshot.Spec.Export.ExportOnly,
+			}
+			if len(snapshot.Spec.Export.S3Prefix) > 0 {
+				input.S3Prefix = pointer.String(snapshot.Spec.Export.S3Prefix)
+			}
+			if _, e := startExportTask.StartExportTask(ctx, input); e != nil {
+				var existsErr *rdstypesv2.ExportTaskAlreadyExistsFault
+				if !goerrors.As(e, &existsErr) {
+					logger.Error(e, "Failed to export DB snapshot to Amazon S3")
+					returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageExportError)
+					return true
+				}
+			}
+			logger.Info("DB snapshot export started", "Export Task", exportTaskID)
+			snapshot.Status.ExportTaskID = exportTaskID
+		}
+
+		describeExportTasks := r.GetDescribeExportTasksAPI(accessKey, secretKey, region)
+		output, e := describeExportTasks.DescribeExportTasks(ctx, &rds.DescribeExportTasksInput{
+			ExportTaskIdentifier: pointer.String(snapshot.Status.ExportTaskID),
+		})
+		if e != nil {
+			logger.Error(e, "Failed to get export task of DB snapshot")
+			returnError(e, snapshotStatusReasonBackendError, snapshotStatusMessageExportError)
+			return true
+		}
+		if len(output.ExportTasks) == 0 {
+			returnRequeue(snapshotStatusReasonExporting, snapshotStatusMessageExporting)
+			return true
+		}
+		task := output.ExportTasks[0]
+		if task.Status != nil {
+			snapshot.Status.ExportStatus = *task.Status
+		}
+		snapshot.Status.ExportPercentProgress = task.PercentProgress
+		if task.S3Bucket != nil {
+			snapshot.Status.ExportS3Location = fmt.Sprintf("s3://%s/%s", *task.S3Bucket, pointer.StringDeref(task.S3Prefix, ""))
+		}
+
+		switch snapshot.Status.ExportStatus {
+		case exportTaskStatusComplete:
+			apimeta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:    snapshotConditionExported,
+				Status:  metav1.ConditionTrue,
+				Reason:  snapshotStatusReasonReady,
+				Message: snapshotStatusMessageExported,
+			})
+			return false
+		case exportTaskStatusFailed, exportTaskStatusCanceled:
+			message := snapshotStatusMessageExportFailed
+			if task.FailureCause != nil {
+				message = fmt.Sprintf("%s: %s", message, *task.FailureCause)
+			}
+			apimeta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:    snapshotConditionExported,
+				Status:  metav1.ConditionFalse,
+				Reason:  snapshotStatusReasonBackendError,
+				Message: message,
+			})
+			// the DB snapshot itself is still usable for restore
+			return false
+		default:
+			apimeta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:    snapshotConditionExported,
+				Status:  metav1.ConditionFalse,
+				Reason:  snapshotStatusReasonExporting,
+				Message: snapshotStatusMessageExporting,
+			})
+			returnRequeue(snapshotStatusReasonExporting, snapshotStatusMessageExporting)
+			return true
+		}
+	}
+
+	if err = r.Get(ctx, req.NamespacedName, &snapshot); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("RDS Snapshot resource not found, has been deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error fetching RDS Snapshot for reconcile")
+		return ctrl.Result{}, err
+	}
+
+	logger = logger.WithValues(logging.KeyInventory, fmt.Sprintf("%s/%s", snapshot.Spec.InventoryRef.Namespace, snapshot.Spec.InventoryRef.Name),
+		logging.KeyDBInstanceID, snapshot.Spec.DBInstanceID)
+	ctx = log.IntoContext(ctx, logger)
+
+	defer updateSnapshotReadyCondition()
+
+	if checkInventory() {
+		return
+	}
+
+	if syncDBSnapshot() {
+		return
+	}
+
+	if exportDBSnapshot() {
+		return
+	}
+
+	returnReady()
+	return
+}
+
+// getDBSnapshotIdentifier returns the identifier of the manual DB snapshot, defaults to the name of the RDSSnapshot
+func getDBSnapshotIdentifier(snapshot *rdsdbaasv1alpha1.RDSSnapshot) string {
+	if len(snapshot.Spec.SnapshotID) > 0 {
+		return snapshot.Spec.SnapshotID
+	}
+	return snapshot.Name
+}
+
+func setDBSnapshotStatus(snapshot *rdsdbaasv1alpha1.RDSSnapshot, dbSnapshot *rdstypesv2.DBSnapshot) {
+	if dbSnapshot == nil {
+		return
+	}
+	if dbSnapshot.DBSnapshotArn != nil {
+		snapshot.Status.SnapshotARN = *dbSnapshot.DBSnapshotArn
+	}
+	if dbSnapshot.Status != nil {
+		snapshot.Status.SnapshotStatus = *dbSnapshot.Status
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RDSSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rdsdbaasv1alpha1.RDSSnapshot{}).
+		Complete(r.GracefulShutdown.reconciler(r))
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var (
+	priorityInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_high_priority_reconciles",
+			Help: "The number of high priority reconciles, of the Connections in their first bind attempt, in progress.",
+		},
+	)
+	priorityWaitDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rds_dbaas_low_priority_wait_seconds",
+			Help:    "The time the background work of the Inventory sync waited for the high priority reconciles.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+		},
+	)
+	priorityDeferredRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_deferred_reconcile_requests_total",
+			Help: "The number of low priority reconcile requests deferred while high priority reconciles are in progress.",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(priorityInFlight, priorityWaitDuration, priorityDeferredRequests)
+}
+
+// ReconcilePriority lets the reconciles of the Connections in their first bind attempt go ahead of the background
+// work: while they are in progress, the Inventory sync pauses between DB resources and the requests of the
+// Connections triggered by the changes of the DB services are deferred
+type ReconcilePriority struct {
+	// the maximum time the background work of a sync waits in total for the high priority reconciles, which bounds
+	// its starvation
+	maxWait  time.Duration
+	mutex    sync.Mutex
+	inFlight int
+	// closed once no high priority reconcile is in progress
+	idle chan struct{}
+}
+
+// NewReconcilePriority returns nil if the maximum wait is not positive, which disables the priorities
+func NewReconcilePriority(maxWait time.Duration) *ReconcilePriority {
+	if maxWait <= 0 {
+		return nil
+	}
+	idle := make(chan struct{})
+	close(idle)
+	return &ReconcilePriority{
+		maxWait: maxWait,
+		idle:    idle,
+	}
+}
+
+// beginHigh marks a high priority reconcile in progress until the returned function is called
+func (p *ReconcilePriority) beginHigh() func() {
+	if p == nil {
+		return func() {}
+	}
+	p.mutex.Lock()
+	if p.inFlight == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.inFlight++
+	p.mutex.Unlock()
+	priorityInFlight.Inc()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			p.inFlight--
+			if p.inFlight == 0 {
+				close(p.idle)
+			}
+			p.mutex.Unlock()
+			priorityInFlight.Dec()
+		})
+	}
+}
+
+// highInFlight returns true if a high priority reconcile is in progress
+func (p *ReconcilePriority) highInFlight() bool {
+	if p == nil {
+		return false
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.inFlight > 0
+}
+
+// lowPriorityBudget is the time left for the background work of a sync to wait for the high priority reconciles
+type lowPriorityBudget struct {
+	mutex     sync.Mutex
+	remaining time.Duration
+}
+
+// newLowPriorityBudget returns the budget of the waits of a sync of the background work
+func (p *ReconcilePriority) newLowPriorityBudget() *lowPriorityBudget {
+	if p == nil {
+		return nil
+	}
+	return &lowPriorityBudget{remaining: p.maxWait}
+}
+
+// waitLow waits until no high priority reconcile is in progress, for at most the time left in the budget of the sync
+func (p *ReconcilePriority) waitLow(ctx context.Context, budget *lowPriorityBudget) error {
+	if p == nil || budget == nil {
+		return nil
+	}
+	p.mutex.Lock()
+	idle := p.idle
+	p.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	default:
+	}
+	budget.mutex.Lock()
+	remaining := budget.remaining
+	budget.mutex.Unlock()
+	if remaining <= 0 {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		waited := time.Since(start)
+		budget.mutex.Lock()
+		budget.remaining -= waited
+		budget.mutex.Unlock()
+		priorityWaitDuration.Observe(waited.Seconds())
+	}()
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-idle:
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// isFirstBindAttempt returns true if the Connection has never been bound and its reconciles have not failed yet, the
+// Connections failing to bind are not reconciled ahead of the background work
+func isFirstBindAttempt(connection *rdsdbaasv1alpha1.RDSConnection) bool {
+	if connection.Status.CredentialsRef != nil {
+		return false
+	}
+	condition := apimeta.FindStatusCondition(connection.Status.Conditions, connectionConditionReady)
+	return condition == nil || condition.Reason == connectionStatusReasonUpdating ||
+		condition.Reason == connectionStatusReasonInventoryNotReady
+}
+
+// lowPriority returns the event handler enqueuing the requests of the handler after the maximum wait while high
+// priority reconciles are in progress, so that they do not get ahead of the requests of the Connections to bind
+func (p *ReconcilePriority) lowPriority(h handler.EventHandler) handler.EventHandler {
+	if p == nil {
+		return h
+	}
+	return &lowPriorityHandler{EventHandler: h, priority: p}
+}
+
+type lowPriorityHandler struct {
+	handler.EventHandler
+	priority *ReconcilePriority
+}
+
+func (h *lowPriorityHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Create(e, &lowPriorityQueue{RateLimitingInterface: q, priority: h.priority})
+}
+
+func (h *lowPriorityHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Update(e, &lowPriorityQueue{RateLimitingInterface: q, priority: h.priority})
+}
+
+func (h *lowPriorityHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Delete(e, &lowPriorityQueue{RateLimitingInterface: q, priority: h.priority})
+}
+
+func (h *lowPriorityHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Generic(e, &lowPriorityQueue{RateLimitingInterface: q, priority: h.priority})
+}
+
+// lowPriorityQueue defers the requests added while high priority reconciles are in progress
+type lowPriorityQueue struct {
+	workqueue.RateLimitingInterface
+	priority *ReconcilePriority
+}
+
+func (q *lowPriorityQueue) Add(item interface{}) {
+	if q.priority.highInFlight() {
+		priorityDeferredRequests.Inc()
+		q.RateLimitingInterface.AddAfter(item, q.priority.maxWait)
+		return
+	}
+	q.RateLimitingInterface.Add(item)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("ReconcilePriority", func() {
+	It("should be disabled if the maximum wait is not positive", func() {
+		p := NewReconcilePriority(0)
+		Expect(p).Should(BeNil())
+		done := p.beginHigh()
+		Expect(p.highInFlight()).Should(BeFalse())
+		Expect(p.waitLow(context.Background(), p.newLowPriorityBudget())).Should(Succeed())
+		done()
+	})
+
+	It("should make the background work wait for the high priority reconciles", func() {
+		p := NewReconcilePriority(time.Minute)
+		Expect(p.waitLow(context.Background(), p.newLowPriorityBudget())).Should(Succeed())
+
+		done := p.beginHigh()
+		Expect(p.highInFlight()).Should(BeTrue())
+		waited := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			Expect(p.waitLow(context.Background(), p.newLowPriorityBudget())).Should(Succeed())
+			close(waited)
+		}()
+		Consistently(waited, 100*time.Millisecond).ShouldNot(BeClosed())
+		done()
+		done()
+		Eventually(waited, time.Second).Should(BeClosed())
+		Expect(p.highInFlight()).Should(BeFalse())
+	})
+
+	It("should bound the total wait of a sync of the background work", func() {
+		p := NewReconcilePriority(50 * time.Millisecond)
+		defer p.beginHigh()()
+		budget := p.newLowPriorityBudget()
+		start := time.Now()
+		Expect(p.waitLow(context.Background(), budget)).Should(Succeed())
+		Expect(time.Since(start)).Should(BeNumerically(">=", 50*time.Millisecond))
+
+		// the budget of the sync is spent, the next waits do not wait
+		start = time.Now()
+		Expect(p.waitLow(context.Background(), budget)).Should(Succeed())
+		Expect(time.Since(start)).Should(BeNumerically("<", 50*time.Millisecond))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(p.waitLow(ctx, p.newLowPriorityBudget())).Should(MatchError(context.Canceled))
+	})
+
+	It("should only prioritize the Connections in their first bind attempt", func() {
+		connection := &rdsdbaasv1alpha1.RDSConnection{}
+		Expect(isFirstBindAttempt(connection)).Should(BeTrue())
+		connection.Status.Conditions = []metav1.Condition{{Type: connectionConditionReady, Status: metav1.ConditionFalse,
+			Reason: connectionStatusReasonUpdating}}
+		Expect(isFirstBindAttempt(connection)).Should(BeTrue())
+
+		// the Connection failed to bind
+		connection.Status.Conditions[0].Reason = connectionStatusReasonBackendError
+		Expect(isFirstBindAttempt(connection)).Should(BeFalse())
+
+		// the Connection was bound before
+		connection.Status.Conditions[0].Reason = connectionStatusReasonUpdating
+		connection.Status.CredentialsRef = &v1.LocalObjectReference{Name: "credentials"}
+		Expect(isFirstBindAttempt(connection)).Should(BeFalse())
+	})
+
+	It("should defer the low priority requests while high priority reconciles are in progress", func() {
+		p := NewReconcilePriority(100 * time.Millisecond)
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer q.ShutDown()
+		low := &lowPriorityQueue{RateLimitingInterface: q, priority: p}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "priority", Name: "connection"}}
+
+		low.Add(request)
+		Expect(q.Len()).Should(Equal(1))
+		item, _ := q.Get()
+		q.Done(item)
+
+		done := p.beginHigh()
+		low.Add(request)
+		Expect(q.Len()).Should(BeZero())
+		Eventually(q.Len, time.Second).Should(Equal(1))
+		done()
+	})
+})
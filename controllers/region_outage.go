@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultRegionOutageStalenessTTL is the default time the DB services of an unavailable region are kept in the
+	// Inventory status
+	DefaultRegionOutageStalenessTTL = time.Hour
+
+	inventoryConditionRegionDegraded = "RegionDegraded"
+
+	regionDegradedReasonUnavailable = "RegionUnavailable"
+
+	regionDegradedMessageUnavailable = "AWS APIs of region %s failing: %v, its DB services synced last are reported until %s"
+	regionDegradedMessageStale       = "AWS APIs of region %s failing: %v, its DB services are excluded as stale since %s"
+
+	inventoryStatusMessageRegionStale = "The DB services of the unavailable region of the Inventory are stale and excluded"
+)
+
+// the API error codes of the AWS services failing in a region
+var awsServiceUnavailableErrorCodes = map[string]struct{}{
+	"InternalFailure":             {},
+	"InternalError":               {},
+	"InternalServerError":         {},
+	"ServiceUnavailable":          {},
+	"ServiceUnavailableException": {},
+}
+
+// isRegionOutageError returns true if the AWS call failed as the endpoints of the region are unreachable or failing,
+// rather than for the credentials or the request of the Inventory
+func isRegionOutageError(err error) bool {
+	if err == nil || goerrors.Is(err, context.Canceled) {
+		return false
+	}
+	if (retry.RetryableConnectionError{}).IsErrorRetryable(err) == aws.TrueTernary {
+		return true
+	}
+	if (retry.RetryableHTTPStatusCode{Codes: retry.DefaultRetryableHTTPStatusCodes}).IsErrorRetryable(err) == aws.TrueTernary {
+		return true
+	}
+	var apiErr smithy.APIError
+	if goerrors.As(err, &apiErr) {
+		_, ok := awsServiceUnavailableErrorCodes[apiErr.ErrorCode()]
+		return ok
+	}
+	return false
+}
+
+// isRegionDegraded returns true if the region of the Inventory is unavailable and its DB services are not stale yet
+func isRegionDegraded(inventory *rdsdbaasv1alpha1.RDSInventory, stalenessTTL time.Duration, now time.Time) bool {
+	if stalenessTTL <= 0 {
+		stalenessTTL = DefaultRegionOutageStalenessTTL
+	}
+	c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionRegionDegraded)
+	return c != nil && c.Status == metav1.ConditionTrue && now.Before(c.LastTransitionTime.Add(stalenessTTL))
+}
+
+// setRegionDegraded marks the region of the Inventory as degraded and returns true once the region is unavailable
+// for longer than the staleness TTL, the time of the transition of the condition is the start of the outage
+func setRegionDegraded(inventory *rdsdbaasv1alpha1.RDSInventory, region string, err error, stalenessTTL time.Duration, now time.Time) bool {
+	if stalenessTTL <= 0 {
+		stalenessTTL = DefaultRegionOutageStalenessTTL
+	}
+	since := now
+	if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionRegionDegraded); c != nil && c.Status == metav1.ConditionTrue {
+		since = c.LastTransitionTime.Time
+	}
+	staleAt := since.Add(stalenessTTL)
+	stale := !now.Before(staleAt)
+
+	message := fmt.Sprintf(regionDegradedMessageUnavailable, region, err, staleAt.Format(time.RFC3339))
+	if stale {
+		message = fmt.Sprintf(regionDegradedMessageStale, region, err, staleAt.Format(time.RFC3339))
+	}
+	apimeta.SetStatusCondition(&inventory.Status.Conditions, metav1.Condition{
+		Type:               inventoryConditionRegionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             regionDegradedReasonUnavailable,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(since),
+	})
+	return stale
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+var _ = Describe("Region outage", func() {
+	It("should classify the AWS errors of an unavailable region", func() {
+		Expect(isRegionOutageError(&smithyhttp.RequestSendError{Err: fmt.Errorf("dial tcp: i/o timeout")})).Should(BeTrue())
+		Expect(isRegionOutageError(&awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+			Err:      fmt.Errorf("service unavailable"),
+		}})).Should(BeTrue())
+		Expect(isRegionOutageError(&smithy.GenericAPIError{Code: "InternalFailure"})).Should(BeTrue())
+
+		Expect(isRegionOutageError(&smithy.GenericAPIError{Code: "InvalidClientTokenId"})).Should(BeFalse())
+		Expect(isRegionOutageError(context.Canceled)).Should(BeFalse())
+		Expect(isRegionOutageError(nil)).Should(BeFalse())
+	})
+
+	It("should exclude the DB services of the region once they are stale", func() {
+		now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		inventory := &rdsdbaasv1alpha1.RDSInventory{}
+		outage := &smithy.GenericAPIError{Code: "ServiceUnavailable"}
+
+		Expect(setRegionDegraded(inventory, "us-east-1", outage, time.Hour, now)).Should(BeFalse())
+		Expect(isRegionDegraded(inventory, time.Hour, now)).Should(BeTrue())
+		c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionRegionDegraded)
+		Expect(c).ShouldNot(BeNil())
+		Expect(c.Reason).Should(Equal(regionDegradedReasonUnavailable))
+		Expect(c.Message).Should(ContainSubstring("us-east-1"))
+
+		// the outage starts at the first failure
+		Expect(setRegionDegraded(inventory, "us-east-1", outage, time.Hour, now.Add(30*time.Minute))).Should(BeFalse())
+		Expect(setRegionDegraded(inventory, "us-east-1", outage, time.Hour, now.Add(time.Hour))).Should(BeTrue())
+		Expect(isRegionDegraded(inventory, time.Hour, now.Add(time.Hour))).Should(BeFalse())
+		Expect(apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionRegionDegraded).LastTransitionTime.Time).
+			Should(Equal(now))
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+)
+
+type CreateSecretAPI interface {
+	CreateSecret(context.Context, *secretsmanager.CreateSecretInput, ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+}
+
+type sdkV2CreateSecret struct {
+	client *secretsmanager.Client
+}
+
+func NewCreateSecret(accessKey, secretKey, region string) CreateSecretAPI {
+	awsClient := secretsmanager.New(secretsmanager.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2CreateSecret{
+		client: awsClient,
+	}
+}
+
+func (c *sdkV2CreateSecret) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	return c.client.CreateSecret(ctx, params, optFns...)
+}
+
+type PutSecretValueAPI interface {
+	PutSecretValue(context.Context, *secretsmanager.PutSecretValueInput, ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+}
+
+type sdkV2PutSecretValue struct {
+	client *secretsmanager.Client
+}
+
+func NewPutSecretValue(accessKey, secretKey, region string) PutSecretValueAPI {
+	awsClient := secretsmanager.New(secretsmanager.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2PutSecretValue{
+		client: awsClient,
+	}
+}
+
+func (p *sdkV2PutSecretValue) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	return p.client.PutSecretValue(ctx, params, optFns...)
+}
+
+type GetSecretValueAPI interface {
+	GetSecretValue(context.Context, *secretsmanager.GetSecretValueInput, ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+type sdkV2GetSecretValue struct {
+	client *secretsmanager.Client
+}
+
+func NewGetSecretValue(accessKey, secretKey, region string) GetSecretValueAPI {
+	awsClient := secretsmanager.New(secretsmanager.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2GetSecretValue{
+		client: awsClient,
+	}
+}
+
+func (g *sdkV2GetSecretValue) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	return g.client.GetSecretValue(ctx, params, optFns...)
+}
+
+type DeleteSecretAPI interface {
+	DeleteSecret(context.Context, *secretsmanager.DeleteSecretInput, ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+type sdkV2DeleteSecret struct {
+	client *secretsmanager.Client
+}
+
+func NewDeleteSecret(accessKey, secretKey, region string) DeleteSecretAPI {
+	awsClient := secretsmanager.New(secretsmanager.Options{
+		Region:      region,
+		Credentials: controllerssts.NewCredentials(accessKey, secretKey, region),
+	})
+	return &sdkV2DeleteSecret{
+		client: awsClient,
+	}
+}
+
+func (d *sdkV2DeleteSecret) DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	return d.client.DeleteSecret(ctx, params, optFns...)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/utils/pointer"
+
+	controllerssecretsmanager "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// secrets holds the secret values of the mock secrets manager by secret name
+var secrets sync.Map
+
+func secretArn(name string) string {
+	return fmt.Sprintf("arn:aws:secretsmanager:us-east-1:123456789012:secret:%s", name)
+}
+
+type mockCreateSecret struct {
+	accessKey, secretKey, region string
+}
+
+func NewCreateSecret(accessKey, secretKey, region string) controllerssecretsmanager.CreateSecretAPI {
+	return &mockCreateSecret{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (c *mockCreateSecret) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	if _, loaded := secrets.LoadOrStore(*params.Name, pointer.StringDeref(params.SecretString, "")); loaded {
+		return nil, &types.ResourceExistsException{Message: pointer.String("secret already exists")}
+	}
+	return &secretsmanager.CreateSecretOutput{Name: params.Name, ARN: pointer.String(secretArn(*params.Name))}, nil
+}
+
+type mockPutSecretValue struct {
+	accessKey, secretKey, region string
+}
+
+func NewPutSecretValue(accessKey, secretKey, region string) controllerssecretsmanager.PutSecretValueAPI {
+	return &mockPutSecretValue{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (p *mockPutSecretValue) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	if _, ok := secrets.Load(*params.SecretId); !ok {
+		return nil, &types.ResourceNotFoundException{Message: pointer.String("secret not found")}
+	}
+	secrets.Store(*params.SecretId, pointer.StringDeref(params.SecretString, ""))
+	return &secretsmanager.PutSecretValueOutput{Name: params.SecretId, ARN: pointer.String(secretArn(*params.SecretId))}, nil
+}
+
+type mockGetSecretValue struct {
+	accessKey, secretKey, region string
+}
+
+func NewGetSecretValue(accessKey, secretKey, region string) controllerssecretsmanager.GetSecretValueAPI {
+	return &mockGetSecretValue{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (g *mockGetSecretValue) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	v, ok := secrets.Load(*params.SecretId)
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: pointer.String("secret not found")}
+	}
+	return &secretsmanager.GetSecretValueOutput{Name: params.SecretId, SecretString: pointer.String(v.(string))}, nil
+}
+
+type mockDeleteSecret struct {
+	accessKey, secretKey, region string
+}
+
+func NewDeleteSecret(accessKey, secretKey, region string) controllerssecretsmanager.DeleteSecretAPI {
+	return &mockDeleteSecret{accessKey: accessKey, secretKey: secretKey, region: region}
+}
+
+func (d *mockDeleteSecret) DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	secrets.Delete(*params.SecretId)
+	return &secretsmanager.DeleteSecretOutput{Name: params.SecretId}, nil
+}
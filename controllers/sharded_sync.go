@@ -0,0 +1,160 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	shardPhaseAdoptDBInstances     = "adopt_db_instances"
+	shardPhaseResetDBInstanceCreds = "reset_db_instance_credentials"
+)
+
+var (
+	shardSyncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rds_dbaas_inventory_shard_sync_duration_seconds",
+			Help:    "The time taken by a shard to sync the DB resources of an Inventory.",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+		},
+		[]string{"phase", "shard"},
+	)
+	shardSyncItems = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_inventory_shard_sync_items_total",
+			Help: "The number of DB resources of Inventories synced by a shard.",
+		},
+		[]string{"phase", "shard"},
+	)
+	shardSyncErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_inventory_shard_sync_errors_total",
+			Help: "The number of failed syncs of the DB resources of Inventories by a shard.",
+		},
+		[]string{"phase", "shard"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(shardSyncDuration, shardSyncItems, shardSyncErrors)
+}
+
+// ShardedSync spreads the per-resource work of the Inventory sync across shards, a DB resource is always assigned to
+// the same shard by hashing its identifier, and each shard runs in its own goroutine with a separate AWS call budget
+type ShardedSync struct {
+	shards int
+	qps    float64
+}
+
+// NewShardedSync returns nil if the number of shards is less than 2, which runs the sync in the reconciling goroutine,
+// a qps that is not positive does not limit the AWS calls of the shards
+func NewShardedSync(shards int, qps float64) *ShardedSync {
+	if shards < 2 {
+		return nil
+	}
+	return &ShardedSync{
+		shards: shards,
+		qps:    qps,
+	}
+}
+
+// shardOf returns the shard of the DB resource identifier
+func shardOf(identifier string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(identifier))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// run calls syncItem for the index of each identifier, once all the shards complete it returns the first error in shard order,
+// a shard stops at its first error
+func (s *ShardedSync) run(ctx context.Context, phase string, identifiers []string, syncItem func(ctx context.Context, i int) error) error {
+	if s == nil {
+		return runShard(ctx, phase, 0, nil, indexes(len(identifiers)), syncItem)
+	}
+
+	shardIndexes := make([][]int, s.shards)
+	for i, identifier := range identifiers {
+		shard := shardOf(identifier, s.shards)
+		shardIndexes[shard] = append(shardIndexes[shard], i)
+	}
+
+	errs := make([]error, s.shards)
+	var wg sync.WaitGroup
+	for shard := range shardIndexes {
+		if len(shardIndexes[shard]) == 0 {
+			continue
+		}
+		var limiter *rate.Limiter
+		if s.qps > 0 {
+			limiter = rate.NewLimiter(rate.Limit(s.qps), 1)
+		}
+		shard := shard
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[shard] = runShard(ctx, phase, shard, limiter, shardIndexes[shard], syncItem)
+		}()
+	}
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func runShard(ctx context.Context, phase string, shard int, limiter *rate.Limiter, items []int, syncItem func(ctx context.Context, i int) error) error {
+	shardLabel := strconv.Itoa(shard)
+	start := time.Now()
+	defer func() {
+		shardSyncDuration.WithLabelValues(phase, shardLabel).Observe(time.Since(start).Seconds())
+	}()
+
+	for _, i := range items {
+		if limiter != nil {
+			if e := limiter.Wait(ctx); e != nil {
+				shardSyncErrors.WithLabelValues(phase, shardLabel).Inc()
+				return e
+			}
+		}
+		if e := syncItem(ctx, i); e != nil {
+			shardSyncErrors.WithLabelValues(phase, shardLabel).Inc()
+			return e
+		}
+		shardSyncItems.WithLabelValues(phase, shardLabel).Inc()
+	}
+	return nil
+}
+
+func indexes(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShardedSync", func() {
+	identifiers := func(n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("db-instance-%d", i)
+		}
+		return ids
+	}
+
+	Context("when the number of shards is less than 2", func() {
+		It("should sync all the items in order", func() {
+			s := NewShardedSync(1, 0)
+			Expect(s).Should(BeNil())
+			var synced []int
+			err := s.run(context.Background(), "test", identifiers(5), func(ctx context.Context, i int) error {
+				synced = append(synced, i)
+				return nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(synced).Should(Equal([]int{0, 1, 2, 3, 4}))
+		})
+	})
+
+	Context("when the items are sharded", func() {
+		It("should assign an identifier to the same shard", func() {
+			for _, id := range identifiers(100) {
+				shard := shardOf(id, 8)
+				Expect(shard).Should(BeNumerically(">=", 0))
+				Expect(shard).Should(BeNumerically("<", 8))
+				Expect(shardOf(id, 8)).Should(Equal(shard))
+			}
+		})
+
+		It("should sync each item once", func() {
+			var mutex sync.Mutex
+			synced := map[int]int{}
+			err := NewShardedSync(4, 0).run(context.Background(), "test", identifiers(1000), func(ctx context.Context, i int) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				synced[i]++
+				return nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(synced).Should(HaveLen(1000))
+			for _, count := range synced {
+				Expect(count).Should(Equal(1))
+			}
+		})
+
+		It("should return the error of a failed shard", func() {
+			err := NewShardedSync(4, 0).run(context.Background(), "test", identifiers(100), func(ctx context.Context, i int) error {
+				if i == 42 {
+					return fmt.Errorf("sync failed")
+				}
+				return nil
+			})
+			Expect(err).Should(MatchError("sync failed"))
+		})
+
+		It("should sync 5000 DB instances with AWS call latency within the time budget", func() {
+			s := NewShardedSync(16, 0)
+			start := time.Now()
+			err := s.run(context.Background(), "test", identifiers(5000), func(ctx context.Context, i int) error {
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(time.Since(start)).Should(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
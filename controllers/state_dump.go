@@ -0,0 +1,207 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// StateDumpPath is the path of the state dump on the metrics endpoint, which is protected by the auth proxy
+const StateDumpPath = "/dump-state"
+
+const redactedValue = "<redacted>"
+
+// the kinds of the resources owned by the operator and the RDS controller that are dumped
+var stateDumpKinds = []schema.GroupVersionKind{
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSInventory"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSInstance"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSConnection"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSMigration"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSParameterGroup"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSOptionGroup"},
+	{Group: "dbaas.redhat.com", Version: "v1alpha1", Kind: "RDSSnapshot"},
+	{Group: "rds.services.k8s.aws", Version: "v1alpha1", Kind: "DBInstance"},
+	{Group: "rds.services.k8s.aws", Version: "v1alpha1", Kind: "DBCluster"},
+	{Group: "rds.services.k8s.aws", Version: "v1alpha1", Kind: "DBSubnetGroup"},
+	{Group: "services.k8s.aws", Version: "v1alpha1", Kind: "AdoptedResource"},
+}
+
+// the groups of the objects whose events are dumped
+var stateDumpEventGroups = map[string]bool{"dbaas.redhat.com": true, "rds.services.k8s.aws": true, "services.k8s.aws": true}
+
+// the flags whose values are redacted from the dumped configuration
+var sensitiveFlagRegex = regexp.MustCompile(`(?i)(password|secret|token|credential|key)`)
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+// StateDump collects the resources of the operator and of the RDS controller with their conditions, the recent
+// events of these resources, and the redacted configuration of the operator into a gzipped tarball for the support
+// cases. The Secrets are never collected.
+type StateDump struct {
+	// the reader of the API server, the resources are read directly rather than from the cache of the manager
+	Reader client.Reader
+	// the configuration of the operator, the sensitive values are redacted by RedactedFlags
+	Config map[string]string
+	// the events older than the age are not dumped, all the events if not set
+	EventsMaxAge time.Duration
+}
+
+// RedactedFlags returns the values of the flags of the operator, with the values of the sensitive flags redacted
+func RedactedFlags(fs *flag.FlagSet) map[string]string {
+	config := map[string]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if v := f.Value.String(); sensitiveFlagRegex.MatchString(f.Name) && len(v) > 0 {
+			config[f.Name] = redactedValue
+		} else {
+			config[f.Name] = v
+		}
+	})
+	return config
+}
+
+func (d *StateDump) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// the tarball is buffered to report the errors with the status code
+	var b bytes.Buffer
+	if err := d.Write(req.Context(), &b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", StateDumpFileName(time.Now())))
+	_, _ = w.Write(b.Bytes())
+}
+
+// StateDumpFileName returns the name of the tarball of the state dumped at the time
+func StateDumpFileName(now time.Time) string {
+	return fmt.Sprintf("rds-dbaas-operator-state-%s.tar.gz", now.UTC().Format("20060102T150405Z"))
+}
+
+// Write writes the gzipped tarball of the state, the resources that cannot be read are reported in errors.txt
+func (d *StateDump) Write(ctx context.Context, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+
+	var errs []string
+	for _, gvk := range stateDumpKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := listInChunks(ctx, d.Reader, list); err != nil {
+			// the CRDs of the RDS controller are not installed until an Inventory is created
+			errs = append(errs, fmt.Sprintf("%s: %v", gvk.Kind, err))
+			continue
+		}
+		for i := range list.Items {
+			o := &list.Items[i]
+			unstructured.RemoveNestedField(o.Object, "metadata", "managedFields")
+			if err := writeStateDumpYAML(tw, path.Join(strings.ToLower(gvk.Kind), o.GetNamespace(), o.GetName()+".yaml"), o.Object, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	eventList := &v1.EventList{}
+	if err := listInChunks(ctx, d.Reader, eventList); err != nil {
+		errs = append(errs, fmt.Sprintf("Event: %v", err))
+	} else {
+		events := filterStateDumpEvents(eventList.Items, d.EventsMaxAge, now)
+		if err := writeStateDumpYAML(tw, "events.yaml", events, now); err != nil {
+			return err
+		}
+	}
+
+	if err := writeStateDumpYAML(tw, "config.yaml", d.Config, now); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		if err := writeStateDumpFile(tw, "errors.txt", []byte(strings.Join(errs, "\n")+"\n"), now); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// filterStateDumpEvents returns the events of the resources of the operator and of the RDS controller, the latest first
+func filterStateDumpEvents(events []v1.Event, maxAge time.Duration, now time.Time) []v1.Event {
+	var filtered []v1.Event
+	for _, e := range events {
+		gv, err := schema.ParseGroupVersion(e.InvolvedObject.APIVersion)
+		if err != nil || !stateDumpEventGroups[gv.Group] {
+			continue
+		}
+		last := e.LastTimestamp.Time
+		if last.IsZero() {
+			last = e.EventTime.Time
+		}
+		if maxAge > 0 && now.Sub(last) > maxAge {
+			continue
+		}
+		e.ManagedFields = nil
+		filtered = append(filtered, e)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].LastTimestamp.After(filtered[j].LastTimestamp.Time)
+	})
+	return filtered
+}
+
+func writeStateDumpYAML(tw *tar.Writer, name string, o interface{}, now time.Time) error {
+	b, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return writeStateDumpFile(tw, name, b, now)
+}
+
+func writeStateDumpFile(tw *tar.Writer, name string, b []byte, now time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(b)
+	return err
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// unavailableReader fails to read all the resources
+type unavailableReader struct{}
+
+func (unavailableReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return fmt.Errorf("unavailable")
+}
+
+func (unavailableReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return fmt.Errorf("unavailable")
+}
+
+var _ = Describe("StateDump", func() {
+	It("should redact the sensitive flags", func() {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("vault-token", "", "")
+		fs.String("aws-secret-access-key", "", "")
+		fs.String("log-level", "info", "")
+		Expect(fs.Parse([]string{"--vault-token=s.abc", "--log-level=debug"})).Should(Succeed())
+		Expect(RedactedFlags(fs)).Should(Equal(map[string]string{
+			"vault-token":           redactedValue,
+			"aws-secret-access-key": "",
+			"log-level":             "debug",
+		}))
+	})
+
+	It("should only dump the recent events of the operator resources", func() {
+		now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+		event := func(name, apiVersion string, age time.Duration) v1.Event {
+			return v1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: name},
+				InvolvedObject: v1.ObjectReference{APIVersion: apiVersion},
+				LastTimestamp:  metav1.NewTime(now.Add(-age)),
+			}
+		}
+		events := filterStateDumpEvents([]v1.Event{
+			event("old", "dbaas.redhat.com/v1alpha1", 2*time.Hour),
+			event("pod", "v1", time.Minute),
+			event("inventory", "dbaas.redhat.com/v1alpha1", 10*time.Minute),
+			event("db-instance", "rds.services.k8s.aws/v1alpha1", time.Minute),
+		}, time.Hour, now)
+		Expect(events).Should(HaveLen(2))
+		Expect(events[0].Name).Should(Equal("db-instance"))
+		Expect(events[1].Name).Should(Equal("inventory"))
+	})
+
+	It("should report the resources that cannot be read", func() {
+		dump := &StateDump{Reader: unavailableReader{}, Config: map[string]string{"log-level": "info"}}
+		var b bytes.Buffer
+		Expect(dump.Write(context.Background(), &b)).Should(Succeed())
+
+		gr, e := gzip.NewReader(&b)
+		Expect(e).ShouldNot(HaveOccurred())
+		tr := tar.NewReader(gr)
+		files := map[string]string{}
+		for {
+			h, e := tr.Next()
+			if e == io.EOF {
+				break
+			}
+			Expect(e).ShouldNot(HaveOccurred())
+			content, e := io.ReadAll(tr)
+			Expect(e).ShouldNot(HaveOccurred())
+			files[h.Name] = string(content)
+		}
+		Expect(files).Should(HaveKeyWithValue("config.yaml", "log-level: info\n"))
+		Expect(files).Should(HaveKey("errors.txt"))
+		Expect(files["errors.txt"]).Should(ContainSubstring("RDSInventory: unavailable"))
+		Expect(files["errors.txt"]).Should(ContainSubstring("Event: unavailable"))
+	})
+})
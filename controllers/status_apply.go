@@ -0,0 +1,53 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+// applyInventoryStatus writes the status of the Inventory with a server-side apply patch, which only sends the status
+// and is not rejected when the Inventory has been modified since it was read. The conditions and the DB services are
+// always sent, so an empty list removes the entries owned by the previous updates of the status.
+func applyInventoryStatus(ctx context.Context, c client.Client, inventory *rdsdbaasv1alpha1.RDSInventory) error {
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&inventory.Status)
+	if err != nil {
+		return err
+	}
+	if _, ok := status["conditions"]; !ok {
+		status["conditions"] = []interface{}{}
+	}
+	if _, ok := status["databaseServices"]; !ok {
+		status["databaseServices"] = []interface{}{}
+	}
+
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+	patch.SetGroupVersionKind(rdsdbaasv1alpha1.GroupVersion.WithKind("RDSInventory"))
+	patch.SetNamespace(inventory.Namespace)
+	patch.SetName(inventory.Name)
+	if err := c.Status().Patch(ctx, patch, client.Apply, client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+		return err
+	}
+	inventory.ResourceVersion = patch.GetResourceVersion()
+	return nil
+}
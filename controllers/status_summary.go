@@ -0,0 +1,191 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+)
+
+// StatusSummaryPath is the path of the status summary on the metrics endpoint, which is protected by the auth proxy
+const StatusSummaryPath = "/status-summary"
+
+// inventorySyncRecord is the sync history of an Inventory since the operator started
+type inventorySyncRecord struct {
+	lastSync time.Time
+	failures int
+}
+
+// the sync history of the Inventories by namespace and name
+var inventorySyncRecords sync.Map
+
+func recordInventorySync(namespace, name string, synced bool, now time.Time) {
+	key := namespace + "/" + name
+	r := inventorySyncRecord{}
+	if v, ok := inventorySyncRecords.Load(key); ok {
+		r = v.(inventorySyncRecord)
+	}
+	if synced {
+		r.lastSync = now
+	} else {
+		r.failures++
+	}
+	inventorySyncRecords.Store(key, r)
+}
+
+func deleteInventorySync(namespace, name string) {
+	inventorySyncRecords.Delete(namespace + "/" + name)
+}
+
+// InventorySummary is the summary of the status of an Inventory
+type InventorySummary struct {
+	Namespace                string     `json:"namespace"`
+	Name                     string     `json:"name"`
+	Ready                    string     `json:"ready"`
+	Reason                   string     `json:"reason,omitempty"`
+	LastSyncTime             *time.Time `json:"lastSyncTime,omitempty"`
+	SyncFailures             int        `json:"syncFailures"`
+	DatabaseServices         int        `json:"databaseServices"`
+	ManagedInstances         int        `json:"managedInstances"`
+	ManagedInstancesNotReady int        `json:"managedInstancesNotReady"`
+	Connections              int        `json:"connections"`
+	ConnectionsNotReady      int        `json:"connectionsNotReady"`
+}
+
+// StatusSummary renders the summary of the Inventories, their last sync, their sync failures since the operator
+// started, and their Instances and Connections, as a table or as JSON with the format=json query parameter
+type StatusSummary struct {
+	Client client.Reader
+}
+
+func (s *StatusSummary) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	summaries, err := s.summarize(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(summaries); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	writeStatusSummary(w, summaries, time.Now())
+}
+
+func (s *StatusSummary) summarize(req *http.Request) ([]InventorySummary, error) {
+	ctx := req.Context()
+	inventoryList := &rdsdbaasv1alpha1.RDSInventoryList{}
+	if err := s.Client.List(ctx, inventoryList); err != nil {
+		return nil, fmt.Errorf("failed to list Inventories: %w", err)
+	}
+	instanceList := &rdsdbaasv1alpha1.RDSInstanceList{}
+	if err := s.Client.List(ctx, instanceList); err != nil {
+		return nil, fmt.Errorf("failed to list Instances: %w", err)
+	}
+	connectionList := &rdsdbaasv1alpha1.RDSConnectionList{}
+	if err := s.Client.List(ctx, connectionList); err != nil {
+		return nil, fmt.Errorf("failed to list Connections: %w", err)
+	}
+
+	summaries := make([]InventorySummary, len(inventoryList.Items))
+	index := make(map[string]int, len(inventoryList.Items))
+	for i := range inventoryList.Items {
+		inventory := &inventoryList.Items[i]
+		summary := InventorySummary{
+			Namespace:        inventory.Namespace,
+			Name:             inventory.Name,
+			Ready:            "Unknown",
+			DatabaseServices: len(inventory.Status.DatabaseServices),
+		}
+		if c := apimeta.FindStatusCondition(inventory.Status.Conditions, inventoryConditionReady); c != nil {
+			summary.Ready = string(c.Status)
+			summary.Reason = c.Reason
+		}
+		if v, ok := inventorySyncRecords.Load(inventory.Namespace + "/" + inventory.Name); ok {
+			r := v.(inventorySyncRecord)
+			if !r.lastSync.IsZero() {
+				lastSync := r.lastSync.UTC()
+				summary.LastSyncTime = &lastSync
+			}
+			summary.SyncFailures = r.failures
+		}
+		summaries[i] = summary
+		index[inventory.Namespace+"/"+inventory.Name] = i
+	}
+
+	for i := range instanceList.Items {
+		instance := &instanceList.Items[i]
+		if j, ok := index[instance.Spec.InventoryRef.Namespace+"/"+instance.Spec.InventoryRef.Name]; ok {
+			summaries[j].ManagedInstances++
+			if !apimeta.IsStatusConditionTrue(instance.Status.Conditions, instanceConditionReady) {
+				summaries[j].ManagedInstancesNotReady++
+			}
+		}
+	}
+	for i := range connectionList.Items {
+		connection := &connectionList.Items[i]
+		if j, ok := index[connection.Spec.InventoryRef.Namespace+"/"+connection.Spec.InventoryRef.Name]; ok {
+			summaries[j].Connections++
+			if !apimeta.IsStatusConditionTrue(connection.Status.Conditions, connectionConditionReady) {
+				summaries[j].ConnectionsNotReady++
+			}
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// writeStatusSummary writes the summaries as a table, the instances and connections columns show the ready and the
+// total counts
+func writeStatusSummary(w http.ResponseWriter, summaries []InventorySummary, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tINVENTORY\tREADY\tREASON\tLAST SYNC\tSYNC FAILURES\tDB SERVICES\tINSTANCES\tCONNECTIONS")
+	for _, s := range summaries {
+		lastSync := "-"
+		if s.LastSyncTime != nil {
+			lastSync = fmt.Sprintf("%s (%s ago)", s.LastSyncTime.Format(time.RFC3339), now.Sub(*s.LastSyncTime).Truncate(time.Second))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d/%d\t%d/%d\n", s.Namespace, s.Name, s.Ready, s.Reason, lastSync,
+			s.SyncFailures, s.DatabaseServices, s.ManagedInstances-s.ManagedInstancesNotReady, s.ManagedInstances,
+			s.Connections-s.ConnectionsNotReady, s.Connections)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StatusSummary", func() {
+	It("should record the sync history of the Inventories", func() {
+		now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+		recordInventorySync("summary", "inventory", true, now)
+		recordInventorySync("summary", "inventory", false, now.Add(time.Minute))
+		recordInventorySync("summary", "inventory", false, now.Add(2*time.Minute))
+
+		v, ok := inventorySyncRecords.Load("summary/inventory")
+		Expect(ok).Should(BeTrue())
+		r := v.(inventorySyncRecord)
+		Expect(r.lastSync).Should(Equal(now))
+		Expect(r.failures).Should(Equal(2))
+
+		deleteInventorySync("summary", "inventory")
+		_, ok = inventorySyncRecords.Load("summary/inventory")
+		Expect(ok).Should(BeFalse())
+	})
+
+	It("should write the summaries as a table", func() {
+		lastSync := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+		w := httptest.NewRecorder()
+		writeStatusSummary(w, []InventorySummary{
+			{
+				Namespace:                "summary",
+				Name:                     "inventory",
+				Ready:                    "True",
+				Reason:                   inventoryStatusReasonSyncOK,
+				LastSyncTime:             &lastSync,
+				SyncFailures:             1,
+				DatabaseServices:         3,
+				ManagedInstances:         2,
+				ManagedInstancesNotReady: 1,
+				Connections:              4,
+			},
+			{Namespace: "summary", Name: "not-synced", Ready: "Unknown"},
+		}, lastSync.Add(90*time.Second))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		Expect(lines).Should(HaveLen(3))
+		Expect(strings.Fields(lines[0])).Should(Equal([]string{"NAMESPACE", "INVENTORY", "READY", "REASON", "LAST", "SYNC",
+			"SYNC", "FAILURES", "DB", "SERVICES", "INSTANCES", "CONNECTIONS"}))
+		Expect(strings.Fields(lines[1])).Should(Equal([]string{"summary", "inventory", "True", "SyncOK",
+			"2023-03-01T10:00:00Z", "(1m30s", "ago)", "1", "3", "1/2", "4/4"}))
+		Expect(strings.Fields(lines[2])).Should(Equal([]string{"summary", "not-synced", "Unknown", "-", "0", "0", "0/0", "0/0"}))
+	})
+})
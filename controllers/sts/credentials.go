@@ -0,0 +1,67 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const roleSessionName = "rds-dbaas-operator"
+
+// the session tokens of the temporary static credentials by access key ID
+var sessionTokens sync.Map
+
+// SetSessionToken sets the session token of the temporary static credentials of the access key for the AWS clients
+// created afterwards, or removes it if empty. The access key IDs of temporary credentials are unique to their session,
+// the clients created with the new temporary credentials of an updated Secret use the session token set for them.
+func SetSessionToken(accessKey, sessionToken string) {
+	if len(accessKey) == 0 {
+		return
+	}
+	if len(sessionToken) == 0 {
+		sessionTokens.Delete(accessKey)
+		return
+	}
+	sessionTokens.Store(accessKey, sessionToken)
+}
+
+func getSessionToken(accessKey string) string {
+	if v, ok := sessionTokens.Load(accessKey); ok {
+		return v.(string)
+	}
+	return ""
+}
+
+// NewAssumeRoleCredentials returns the credentials of the last role of the chain of roles assumed from the static
+// credentials, each role is assumed with the credentials of the previous one
+func NewAssumeRoleCredentials(accessKey, secretKey, region string, roleARNs ...string) aws.CredentialsProvider {
+	provider := NewCredentials(accessKey, secretKey, region)
+	for _, roleARN := range roleARNs {
+		stsClient := sts.New(sts.Options{
+			Region:      region,
+			Credentials: aws.NewCredentialsCache(provider),
+		})
+		provider = stscreds.NewAssumeRoleProvider(stsClient, roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+		})
+	}
+	return aws.NewCredentialsCache(provider)
+}
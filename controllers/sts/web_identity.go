@@ -0,0 +1,319 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// the credentials are refreshed between 5 and 2.5 minutes before they expire, so that the clients do not all
+	// refresh them at once
+	webIdentityExpiryWindow           = 5 * time.Minute
+	webIdentityExpiryWindowJitterFrac = 0.5
+
+	// the credentials of the role of an Inventory are assumed again 10 minutes before they expire
+	roleCredentialsExpiryWindow = 10 * time.Minute
+
+	// the reasons of the refresh failures of the web identity credentials
+	refreshFailureTokenRead        = "TokenRead"
+	refreshFailureTokenInvalid     = "TokenInvalid"
+	refreshFailureTokenExpired     = "TokenExpired"
+	refreshFailureAudienceMismatch = "AudienceMismatch"
+	refreshFailureAssumeRole       = "AssumeRole"
+)
+
+var (
+	webIdentityRefreshFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rds_dbaas_web_identity_refresh_failures_total",
+			Help: "The number of failed refreshes of the credentials assumed with the projected service account token, by reason.",
+		},
+		[]string{"reason"},
+	)
+	webIdentityTokenExpiry = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rds_dbaas_web_identity_token_expiry_timestamp_seconds",
+			Help: "The expiry time of the projected service account token last read.",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(webIdentityRefreshFailures, webIdentityTokenExpiry)
+}
+
+// WebIdentity assumes a role with the projected service account token of the operator, the roles of the Inventories
+// whose credentials Secret has no static credentials are assumed with its credentials. The role of the operator is
+// never used by the AWS clients of an Inventory, each Inventory names its own role, which the operator administrator
+// allows with the role ARN patterns of the web identity.
+type WebIdentity struct {
+	roleARN string
+	token   *projectedToken
+	// the role ARN patterns the Inventories are allowed to assume, no role is allowed if empty
+	allowedRoleARNs []string
+	// the cached credentials by region, so that the role is not assumed again for every client
+	providers sync.Map
+	// the cached credentials of the roles of the Inventories by region and role ARN
+	roleProviders sync.Map
+	// the STS endpoint, the regional endpoint if empty
+	endpoint string
+}
+
+// the web identity of the AWS clients, it is set once when the operator starts, before any AWS client is created
+var webIdentity *WebIdentity
+
+// NewWebIdentity returns the web identity assuming the role with the token of the file, which is read again at every
+// refresh as the kubelet rotates it. The audience of the token is checked if set. The Inventories may assume the
+// roles matching the patterns of allowedRoleARNs, in the syntax of path.Match, e.g. arn:aws:iam::123456789012:role/rds-*.
+func NewWebIdentity(roleARN, tokenFile, audience string, allowedRoleARNs []string) (*WebIdentity, error) {
+	if len(roleARN) == 0 {
+		return nil, fmt.Errorf("web identity role ARN is required")
+	}
+	if len(tokenFile) == 0 {
+		return nil, fmt.Errorf("web identity token file is required")
+	}
+	for _, pattern := range allowedRoleARNs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("allowed role ARN pattern %s is invalid: %w", pattern, err)
+		}
+	}
+	return &WebIdentity{
+		roleARN:         roleARN,
+		token:           &projectedToken{path: tokenFile, audience: audience, now: time.Now},
+		allowedRoleARNs: allowedRoleARNs,
+	}, nil
+}
+
+// EnableWebIdentity assumes the roles of the Inventories without static credentials with the web identity
+func EnableWebIdentity(w *WebIdentity) {
+	webIdentity = w
+}
+
+// WebIdentityEnabled returns whether the roles of the Inventories without static credentials are assumed with the web
+// identity
+func WebIdentityEnabled() bool {
+	return webIdentity != nil
+}
+
+// NewCredentials returns the static credentials with the session token set for the access key if they are temporary
+func NewCredentials(accessKey, secretKey, region string) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, getSessionToken(accessKey)))
+}
+
+// AssumeRole returns the temporary credentials of the role of an Inventory in the region, assumed with the credentials
+// of the web identity. The role must match one of the allowed role ARN patterns of the web identity. The credentials
+// are cached, their expiry time is the time they are assumed again, before the credentials of the role expire.
+func AssumeRole(ctx context.Context, roleARN, region string) (aws.Credentials, error) {
+	w := webIdentity
+	if w == nil {
+		return aws.Credentials{}, fmt.Errorf("role %s cannot be assumed, the web identity of the operator is not enabled", roleARN)
+	}
+	return w.assumeRole(ctx, roleARN, region)
+}
+
+// roleAllowed returns whether the role ARN matches one of the allowed role ARN patterns
+func (w *WebIdentity) roleAllowed(roleARN string) bool {
+	for _, pattern := range w.allowedRoleARNs {
+		if ok, _ := path.Match(pattern, roleARN); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *WebIdentity) assumeRole(ctx context.Context, roleARN, region string) (aws.Credentials, error) {
+	if !w.roleAllowed(roleARN) {
+		return aws.Credentials{}, fmt.Errorf("role %s is not allowed to be assumed with the web identity of the operator", roleARN)
+	}
+	key := region + "/" + roleARN
+	p, ok := w.roleProviders.Load(key)
+	if !ok {
+		stsClient := sts.New(w.stsOptions(region, w.credentials(region)))
+		provider := stscreds.NewAssumeRoleProvider(stsClient, roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+		})
+		p, _ = w.roleProviders.LoadOrStore(key, aws.NewCredentialsCache(&failureCountingProvider{provider: provider},
+			func(o *aws.CredentialsCacheOptions) {
+				o.ExpiryWindow = roleCredentialsExpiryWindow
+			}))
+	}
+	return p.(aws.CredentialsProvider).Retrieve(ctx)
+}
+
+// credentials returns the cached credentials of the web identity in the region
+func (w *WebIdentity) credentials(region string) aws.CredentialsProvider {
+	if p, ok := w.providers.Load(region); ok {
+		return p.(aws.CredentialsProvider)
+	}
+	// AssumeRoleWithWebIdentity is not signed, the STS client has no credentials
+	stsClient := sts.New(w.stsOptions(region, nil))
+	provider := stscreds.NewWebIdentityRoleProvider(stsClient, w.roleARN, w.token, func(o *stscreds.WebIdentityRoleOptions) {
+		o.RoleSessionName = roleSessionName
+	})
+	p, _ := w.providers.LoadOrStore(region, aws.NewCredentialsCache(&failureCountingProvider{provider: provider},
+		func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = webIdentityExpiryWindow
+			o.ExpiryWindowJitterFrac = webIdentityExpiryWindowJitterFrac
+		}))
+	return p.(aws.CredentialsProvider)
+}
+
+func (w *WebIdentity) stsOptions(region string, credentials aws.CredentialsProvider) sts.Options {
+	o := sts.Options{
+		Region:      region,
+		Credentials: credentials,
+	}
+	if len(w.endpoint) > 0 {
+		o.EndpointResolver = sts.EndpointResolverFromURL(w.endpoint)
+	}
+	return o
+}
+
+// failureCountingProvider counts the failures of the provider to assume the role
+type failureCountingProvider struct {
+	provider aws.CredentialsProvider
+}
+
+func (p *failureCountingProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	c, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		var tokenErr *tokenError
+		if !errors.As(err, &tokenErr) {
+			webIdentityRefreshFailures.WithLabelValues(refreshFailureAssumeRole).Inc()
+		}
+	}
+	return c, err
+}
+
+// tokenError is the error of the token file, whose failures are counted when the token is read
+type tokenError struct {
+	err error
+}
+
+func (e *tokenError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tokenError) Unwrap() error {
+	return e.err
+}
+
+// projectedToken reads the projected service account token, the last valid token is used while it is not expired if the
+// file cannot be read, for example while the kubelet swaps the projected files
+type projectedToken struct {
+	path     string
+	audience string
+	now      func() time.Time
+
+	mu         sync.Mutex
+	last       []byte
+	lastExpiry time.Time
+}
+
+// GetIdentityToken implements stscreds.IdentityTokenRetriever
+func (t *projectedToken) GetIdentityToken() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, err := os.ReadFile(t.path)
+	if err != nil {
+		webIdentityRefreshFailures.WithLabelValues(refreshFailureTokenRead).Inc()
+		if len(t.last) > 0 && t.now().Before(t.lastExpiry) {
+			return t.last, nil
+		}
+		return nil, &tokenError{err: fmt.Errorf("failed to read web identity token file %s: %w", t.path, err)}
+	}
+	token = bytes.TrimSpace(token)
+
+	claims, err := parseTokenClaims(token)
+	if err != nil {
+		webIdentityRefreshFailures.WithLabelValues(refreshFailureTokenInvalid).Inc()
+		return nil, &tokenError{err: fmt.Errorf("web identity token file %s is invalid: %w", t.path, err)}
+	}
+	if len(t.audience) > 0 && !claims.hasAudience(t.audience) {
+		webIdentityRefreshFailures.WithLabelValues(refreshFailureAudienceMismatch).Inc()
+		return nil, &tokenError{err: fmt.Errorf("web identity token file %s is not issued for audience %s", t.path, t.audience)}
+	}
+	expiry := time.Unix(claims.Expiry, 0)
+	if claims.Expiry > 0 && !t.now().Before(expiry) {
+		// the kubelet failed to rotate the token
+		webIdentityRefreshFailures.WithLabelValues(refreshFailureTokenExpired).Inc()
+		return nil, &tokenError{err: fmt.Errorf("web identity token file %s expired at %s", t.path, expiry.UTC().Format(time.RFC3339))}
+	}
+
+	t.last, t.lastExpiry = token, expiry
+	webIdentityTokenExpiry.Set(float64(claims.Expiry))
+	return token, nil
+}
+
+// tokenClaims are the claims of the service account token checked before it is exchanged
+type tokenClaims struct {
+	Expiry   int64           `json:"exp"`
+	Audience json.RawMessage `json:"aud"`
+}
+
+// hasAudience returns whether the audience claim, a string or an array of strings, has the audience
+func (c *tokenClaims) hasAudience(audience string) bool {
+	var audiences []string
+	if err := json.Unmarshal(c.Audience, &audiences); err != nil {
+		var a string
+		if err := json.Unmarshal(c.Audience, &a); err != nil {
+			return false
+		}
+		audiences = []string{a}
+	}
+	for _, a := range audiences {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTokenClaims returns the claims of the JWT, its signature is verified by STS
+func parseTokenClaims(token []byte) (*tokenClaims, error) {
+	parts := bytes.Split(token, []byte("."))
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(string(bytes.TrimRight(parts[1], "=")))
+	if err != nil {
+		return nil, err
+	}
+	claims := &tokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
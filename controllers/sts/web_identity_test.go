@@ -0,0 +1,205 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// stsServer answers AssumeRoleWithWebIdentity with the credentials of the operator role and AssumeRole with the
+// credentials of the assumed role, it records the actions and the access key signing them
+type stsServer struct {
+	mutex    sync.Mutex
+	requests []string
+}
+
+func (s *stsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	action := r.PostForm.Get("Action")
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	var body string
+	switch action {
+	case "AssumeRoleWithWebIdentity":
+		body = fmt.Sprintf(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>operator-access-key</AccessKeyId>
+      <SecretAccessKey>operator-secret-key</SecretAccessKey>
+      <SessionToken>operator-session-token</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`, expiration)
+	case "AssumeRole":
+		body = fmt.Sprintf(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>%s-access-key</AccessKeyId>
+      <SecretAccessKey>inventory-secret-key</SecretAccessKey>
+      <SessionToken>inventory-session-token</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, r.PostForm.Get("RoleArn"), expiration)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	signer := ""
+	if auth := r.Header.Get("Authorization"); strings.Contains(auth, "Credential=") {
+		signer = strings.SplitN(strings.SplitN(auth, "Credential=", 2)[1], "/", 2)[0]
+	}
+	s.requests = append(s.requests, action+" "+r.PostForm.Get("RoleArn")+" "+signer)
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = w.Write([]byte(body))
+}
+
+func (s *stsServer) getRequests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+var _ = Describe("WebIdentity", func() {
+	var server *stsServer
+	var httpServer *httptest.Server
+	var dir string
+	var w *WebIdentity
+
+	BeforeEach(func() {
+		server = &stsServer{}
+		httpServer = httptest.NewServer(server)
+
+		var err error
+		dir, err = os.MkdirTemp("", "web-identity")
+		Expect(err).ShouldNot(HaveOccurred())
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d,"aud":"sts.amazonaws.com"}`,
+			time.Now().Add(time.Hour).Unix())))
+		tokenFile := filepath.Join(dir, "token")
+		Expect(os.WriteFile(tokenFile, []byte("header."+payload+".signature"), 0600)).Should(Succeed())
+
+		w, err = NewWebIdentity("arn:aws:iam::111111111111:role/operator", tokenFile, "sts.amazonaws.com",
+			[]string{"arn:aws:iam::222222222222:role/rds-*"})
+		Expect(err).ShouldNot(HaveOccurred())
+		w.endpoint = httpServer.URL
+	})
+
+	AfterEach(func() {
+		httpServer.Close()
+		Expect(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	It("should reject the invalid allowed role ARN patterns", func() {
+		_, err := NewWebIdentity("arn:aws:iam::111111111111:role/operator", "token", "", []string{"arn:aws:iam::*:role/["})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should assume the allowed role of the Inventory with the credentials of the web identity", func() {
+		credentials, err := w.assumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-inventory", "us-east-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(credentials.AccessKeyID).Should(Equal("arn:aws:iam::222222222222:role/rds-inventory-access-key"))
+		Expect(credentials.SecretAccessKey).Should(Equal("inventory-secret-key"))
+		Expect(credentials.SessionToken).Should(Equal("inventory-session-token"))
+		// the credentials are assumed again before they expire
+		Expect(credentials.Expires).Should(BeTemporally("~", time.Now().Add(time.Hour-roleCredentialsExpiryWindow), time.Minute))
+
+		Expect(server.getRequests()).Should(Equal([]string{
+			"AssumeRoleWithWebIdentity arn:aws:iam::111111111111:role/operator ",
+			"AssumeRole arn:aws:iam::222222222222:role/rds-inventory operator-access-key",
+		}))
+	})
+
+	It("should cache the credentials of the role of the Inventory", func() {
+		for i := 0; i < 2; i++ {
+			_, err := w.assumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-inventory", "us-east-1")
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		_, err := w.assumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-other", "us-east-1")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(server.getRequests()).Should(Equal([]string{
+			"AssumeRoleWithWebIdentity arn:aws:iam::111111111111:role/operator ",
+			"AssumeRole arn:aws:iam::222222222222:role/rds-inventory operator-access-key",
+			"AssumeRole arn:aws:iam::222222222222:role/rds-other operator-access-key",
+		}))
+	})
+
+	It("should not assume the roles that are not allowed", func() {
+		for _, roleARN := range []string{
+			"arn:aws:iam::111111111111:role/operator",
+			"arn:aws:iam::333333333333:role/rds-inventory",
+			"arn:aws:iam::222222222222:role/admin",
+		} {
+			_, err := w.assumeRole(context.Background(), roleARN, "us-east-1")
+			Expect(err).Should(MatchError(ContainSubstring("is not allowed")))
+		}
+		Expect(server.getRequests()).Should(BeEmpty())
+	})
+
+	It("should not allow any role without allowed role ARN patterns", func() {
+		w.allowedRoleARNs = nil
+		_, err := w.assumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-inventory", "us-east-1")
+		Expect(err).Should(MatchError(ContainSubstring("is not allowed")))
+		Expect(server.getRequests()).Should(BeEmpty())
+	})
+
+	Context("when the web identity is enabled", func() {
+		BeforeEach(func() {
+			EnableWebIdentity(w)
+		})
+
+		AfterEach(func() {
+			EnableWebIdentity(nil)
+		})
+
+		It("should not use the role of the operator without static credentials", func() {
+			_, err := NewCredentials("", "", "us-east-1").Retrieve(context.Background())
+			Expect(err).Should(HaveOccurred())
+			Expect(server.getRequests()).Should(BeEmpty())
+		})
+
+		It("should assume the role of the Inventory", func() {
+			credentials, err := AssumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-inventory", "us-east-1")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(credentials.SessionToken).Should(Equal("inventory-session-token"))
+		})
+	})
+
+	It("should not assume the role of the Inventory if the web identity is not enabled", func() {
+		_, err := AssumeRole(context.Background(), "arn:aws:iam::222222222222:role/rds-inventory", "us-east-1")
+		Expect(err).Should(MatchError(ContainSubstring("web identity of the operator is not enabled")))
+	})
+})
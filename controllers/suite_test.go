@@ -18,6 +18,7 @@ package controllers_test
 
 import (
 	"context"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -44,7 +45,11 @@ import (
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
 	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers"
+	controllerscloudwatchtest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/cloudwatch/test"
+	controllersec2test "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/ec2/test"
 	controllersrdstest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds/test"
+	controllerssecretsmanagertest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager/test"
+	controllersvaulttest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault/test"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	//+kubebuilder:scaffold:imports
@@ -178,10 +183,12 @@ var _ = BeforeSuite(func() {
 	Expect(err).ToNot(HaveOccurred())
 
 	providerReconciler := &controllers.DBaaSProviderReconciler{
-		Client:                  mgr.GetClient(),
-		Scheme:                  mgr.GetScheme(),
-		Clientset:               clientset,
-		DBaaSProviderCRFilePath: filepath.Join("..", "rds", "dbaas", "dbaasprovider"),
+		Client:                                   mgr.GetClient(),
+		Scheme:                                   mgr.GetScheme(),
+		Clientset:                                clientset,
+		DBaaSProviderCRFilePath:                  filepath.Join("..", "rds", "dbaas", "dbaasprovider"),
+		GetDescribeDBEngineVersionsAPI:           controllersrdstest.NewDescribeDBEngineVersions,
+		GetDescribeOrderableDBInstanceOptionsAPI: controllersrdstest.NewDescribeOrderableDBInstanceOptions,
 	}
 	err = providerReconciler.SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
@@ -195,6 +202,13 @@ var _ = BeforeSuite(func() {
 		GetDescribeDBClustersPaginatorAPI:  controllersrdstest.NewDescribeDBClustersPaginator,
 		GetModifyDBClusterAPI:              controllersrdstest.NewModifyDBCluster,
 		GetDescribeDBClustersAPI:           controllersrdstest.NewDescribeDBClusters,
+		GetDescribeEventsAPI:               controllersrdstest.NewDescribeEvents,
+		GetDescribeDBSnapshotsAPI:          controllersrdstest.NewDescribeDBSnapshots,
+		GetDescribeDBSubnetGroupsAPI:       controllersrdstest.NewDescribeDBSubnetGroups,
+		GetListTagsForResourceAPI:          controllersrdstest.NewListTagsForResource,
+		VaultClient:                        controllersvaulttest.NewClient(),
+		ShardedSync:                        controllers.NewShardedSync(4, 0),
+		Recorder:                           mgr.GetEventRecorderFor("rdsinventory-controller"),
 		ACKInstallNamespace:                testNamespace,
 		RDSCRDFilePath:                     filepath.Join("..", "rds", "config", "common", "bases"),
 		WaitForRDSControllerRetries:        10,
@@ -204,17 +218,96 @@ var _ = BeforeSuite(func() {
 	Expect(err).ToNot(HaveOccurred())
 
 	connectionReconciler := &controllers.RDSConnectionReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                            mgr.GetClient(),
+		Scheme:                            mgr.GetScheme(),
+		Recorder:                          mgr.GetEventRecorderFor("rdsconnection-controller"),
+		GetGetSecretValueAPI:              controllerssecretsmanagertest.NewGetSecretValue,
+		VaultClient:                       controllersvaulttest.NewClient(),
+		GetDescribeDBParametersAPI:        controllersrdstest.NewDescribeDBParameters,
+		GetDescribeDBClusterParametersAPI: controllersrdstest.NewDescribeDBClusterParameters,
+		GetGetMetricDataAPI:               controllerscloudwatchtest.NewGetMetricData,
+		LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.10")}}, nil
+		},
 	}
 	err = connectionReconciler.SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
 	instanceReconciler := &controllers.RDSInstanceReconciler{
+		Client:                               mgr.GetClient(),
+		Scheme:                               mgr.GetScheme(),
+		GetDescribeDBSubnetGroupsAPI:         controllersrdstest.NewDescribeDBSubnetGroups,
+		GetDescribeSecurityGroupsAPI:         controllersec2test.NewDescribeSecurityGroups,
+		GetDescribeSubnetsAPI:                controllersec2test.NewDescribeSubnets,
+		GetCreateSecretAPI:                   controllerssecretsmanagertest.NewCreateSecret,
+		GetPutSecretValueAPI:                 controllerssecretsmanagertest.NewPutSecretValue,
+		GetDeleteSecretAPI:                   controllerssecretsmanagertest.NewDeleteSecret,
+		GetRestoreDBInstanceFromS3API:        controllersrdstest.NewRestoreDBInstanceFromS3,
+		GetRestoreDBInstanceToPointInTimeAPI: controllersrdstest.NewRestoreDBInstanceToPointInTime,
+		GetModifyDBInstanceAPI:               controllersrdstest.NewModifyDBInstance,
+		GetDescribeDBInstancesAPI:            controllersrdstest.NewDescribeDBInstances,
+		EnableExtraParameters:                true,
+		ExtraParametersAllowList:             []string{"multiAZ", "performanceInsightsEnabled"},
+		Recorder:                             mgr.GetEventRecorderFor("rdsinstance-controller"),
+	}
+	err = instanceReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	snapshotReconciler := &controllers.RDSSnapshotReconciler{
+		Client:                          mgr.GetClient(),
+		Scheme:                          mgr.GetScheme(),
+		GetCreateDBSnapshotAPI:          controllersrdstest.NewCreateDBSnapshot,
+		GetDescribeDBSnapshotsAPI:       controllersrdstest.NewDescribeDBSnapshots,
+		GetModifyDBSnapshotAttributeAPI: controllersrdstest.NewModifyDBSnapshotAttribute,
+		GetStartExportTaskAPI:           controllersrdstest.NewStartExportTask,
+		GetDescribeExportTasksAPI:       controllersrdstest.NewDescribeExportTasks,
+	}
+	err = snapshotReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	parameterGroupReconciler := &controllers.RDSParameterGroupReconciler{
+		Client:                             mgr.GetClient(),
+		Scheme:                             mgr.GetScheme(),
+		GetCreateDBParameterGroupAPI:       controllersrdstest.NewCreateDBParameterGroup,
+		GetDescribeDBParameterGroupsAPI:    controllersrdstest.NewDescribeDBParameterGroups,
+		GetModifyDBParameterGroupAPI:       controllersrdstest.NewModifyDBParameterGroup,
+		GetDeleteDBParameterGroupAPI:       controllersrdstest.NewDeleteDBParameterGroup,
+		GetDescribeDBParametersAPI:         controllersrdstest.NewDescribeDBParameters,
+		GetDescribeDBInstancesPaginatorAPI: controllersrdstest.NewDescribeDBInstancesPaginator,
+	}
+	err = parameterGroupReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	optionGroupReconciler := &controllers.RDSOptionGroupReconciler{
+		Client:                           mgr.GetClient(),
+		Scheme:                           mgr.GetScheme(),
+		GetCreateOptionGroupAPI:          controllersrdstest.NewCreateOptionGroup,
+		GetDescribeOptionGroupsAPI:       controllersrdstest.NewDescribeOptionGroups,
+		GetModifyOptionGroupAPI:          controllersrdstest.NewModifyOptionGroup,
+		GetDeleteOptionGroupAPI:          controllersrdstest.NewDeleteOptionGroup,
+		GetDescribeOptionGroupOptionsAPI: controllersrdstest.NewDescribeOptionGroupOptions,
+	}
+	err = optionGroupReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	eventSubscriptionReconciler := &controllers.RDSEventSubscriptionReconciler{
+		Client:                                       mgr.GetClient(),
+		Scheme:                                       mgr.GetScheme(),
+		GetCreateEventSubscriptionAPI:                controllersrdstest.NewCreateEventSubscription,
+		GetDescribeEventSubscriptionsAPI:             controllersrdstest.NewDescribeEventSubscriptions,
+		GetModifyEventSubscriptionAPI:                controllersrdstest.NewModifyEventSubscription,
+		GetDeleteEventSubscriptionAPI:                controllersrdstest.NewDeleteEventSubscription,
+		GetAddSourceIdentifierToSubscriptionAPI:      controllersrdstest.NewAddSourceIdentifierToSubscription,
+		GetRemoveSourceIdentifierFromSubscriptionAPI: controllersrdstest.NewRemoveSourceIdentifierFromSubscription,
+	}
+	err = eventSubscriptionReconciler.SetupWithManager(mgr)
+	Expect(err).ToNot(HaveOccurred())
+
+	migrationReconciler := &controllers.RDSMigrationReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}
-	err = instanceReconciler.SetupWithManager(mgr)
+	err = migrationReconciler.SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = k8sClient.Get(ctx, client.ObjectKeyFromObject(rdsDeployment), rdsDeployment)
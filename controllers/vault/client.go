@@ -0,0 +1,200 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token" //#nosec G101
+
+	// the token is renewed by logging in again before it expires
+	tokenRenewBefore = 30 * time.Second
+)
+
+// errForbidden is returned when the Vault server rejects the token or the role of the operator
+var errForbidden = errors.New("permission denied")
+
+type Client interface {
+	Read(ctx context.Context, path string) (map[string]string, error)
+	Write(ctx context.Context, path string, data map[string]string) error
+}
+
+type httpClient struct {
+	address   string
+	authPath  string
+	role      string
+	tokenFile string
+	client    *http.Client
+	now       func() time.Time
+
+	mutex  sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewClient returns the client of the Vault server at the address configured for the operator, it logs in with the
+// Kubernetes auth method on the auth path with the role and the service account token of the operator. The Vault
+// token is cached until it expires or is rejected by the Vault server.
+func NewClient(address, authPath, role string) Client {
+	return &httpClient{
+		address:   strings.TrimSuffix(address, "/"),
+		authPath:  strings.Trim(authPath, "/"),
+		role:      role,
+		tokenFile: serviceAccountTokenFile,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}
+}
+
+// getToken returns the cached Vault token, it logs in if the token is missing or expires soon
+func (c *httpClient) getToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.token) > 0 && (c.expiry.IsZero() || c.now().Before(c.expiry.Add(-tokenRenewBefore))) {
+		return c.token, nil
+	}
+
+	jwt, err := os.ReadFile(filepath.Clean(c.tokenFile))
+	if err != nil {
+		return "", err
+	}
+	var login struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", c.authPath), "",
+		map[string]string{"role": c.role, "jwt": string(jwt)}, &login); err != nil {
+		return "", err
+	}
+	if len(login.Auth.ClientToken) == 0 {
+		return "", fmt.Errorf("vault login with role %s returned no token", c.role)
+	}
+	c.token = login.Auth.ClientToken
+	c.expiry = time.Time{}
+	if login.Auth.LeaseDuration > 0 {
+		c.expiry = c.now().Add(time.Duration(login.Auth.LeaseDuration) * time.Second)
+	}
+	return c.token, nil
+}
+
+// resetToken drops the cached Vault token rejected by the Vault server, the next request logs in again
+func (c *httpClient) resetToken(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+// request sends the request with the Vault token, and once again with a new token if the token is rejected
+func (c *httpClient) request(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		token, err := c.getToken(ctx)
+		if err != nil {
+			return err
+		}
+		err = c.do(ctx, method, path, token, body, out)
+		if !errors.Is(err, errForbidden) || attempt > 0 {
+			return err
+		}
+		c.resetToken(token)
+	}
+}
+
+func (c *httpClient) Read(ctx context.Context, path string) (map[string]string, error) {
+	p, err := dataPath(path)
+	if err != nil {
+		return nil, err
+	}
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := c.request(ctx, http.MethodGet, p, nil, &secret); err != nil {
+		return nil, err
+	}
+	return secret.Data.Data, nil
+}
+
+func (c *httpClient) Write(ctx context.Context, path string, data map[string]string) error {
+	p, err := dataPath(path)
+	if err != nil {
+		return err
+	}
+	return c.request(ctx, http.MethodPost, p, map[string]interface{}{"data": data}, nil)
+}
+
+func (c *httpClient) do(ctx context.Context, method, path, token string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", c.address, path), reader)
+	if err != nil {
+		return err
+	}
+	if len(token) > 0 {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("vault request %s %s: %w", method, path, errForbidden)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("vault request %s %s failed with status %d", method, path, resp.StatusCode)
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// dataPath converts <mount>/<path> to the API path <mount>/data/<path> of the KV version 2 secrets engine
+func dataPath(path string) (string, error) {
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", fmt.Errorf("vault path %s not valid", path)
+	}
+	return fmt.Sprintf("%s/data/%s", parts[0], parts[1]), nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// vaultServer is a Vault server with the Kubernetes auth method on the kubernetes path and a KV v2 secrets engine
+type vaultServer struct {
+	logins  int32
+	revoked int32
+	secrets map[string]interface{}
+}
+
+func (v *vaultServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v1/auth/kubernetes/login" && r.Method == http.MethodPost:
+		var login map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&login); err != nil || login["role"] != "operator" || login["jwt"] != "service-account-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		atomic.AddInt32(&v.logins, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "token", "lease_duration": 3600},
+		})
+	case r.Header.Get("X-Vault-Token") != "token" || atomic.LoadInt32(&v.revoked) > 0:
+		atomic.StoreInt32(&v.revoked, 0)
+		w.WriteHeader(http.StatusForbidden)
+	case r.URL.Path == "/v1/secret/data/team-a/aws" && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": v.secrets}})
+	case r.URL.Path == "/v1/secret/data/team-a/aws" && r.Method == http.MethodPost:
+		var secret map[string]map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.secrets = secret["data"]
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+var _ = Describe("Client", func() {
+	var server *vaultServer
+	var httpServer *httptest.Server
+	var client *httpClient
+	var now time.Time
+	var dir string
+
+	BeforeEach(func() {
+		server = &vaultServer{secrets: map[string]interface{}{"AWS_ACCESS_KEY_ID": "key"}}
+		httpServer = httptest.NewServer(server)
+		var err error
+		dir, err = os.MkdirTemp("", "vault")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		tokenFile := filepath.Join(dir, "token")
+		Expect(os.WriteFile(tokenFile, []byte("service-account-token"), 0600)).Should(Succeed())
+		now = time.Now()
+		client = NewClient(httpServer.URL+"/", "/kubernetes/", "operator").(*httpClient)
+		client.tokenFile = tokenFile
+		client.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		httpServer.Close()
+		Expect(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	It("should log in once and reuse the Vault token", func() {
+		for i := 0; i < 3; i++ {
+			data, err := client.Read(context.Background(), "secret/team-a/aws")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(data).Should(HaveKeyWithValue("AWS_ACCESS_KEY_ID", "key"))
+		}
+		Expect(client.Write(context.Background(), "secret/team-a/aws", map[string]string{"username": "user"})).Should(Succeed())
+		Expect(server.secrets).Should(HaveKeyWithValue("username", "user"))
+		Expect(atomic.LoadInt32(&server.logins)).Should(BeEquivalentTo(1))
+	})
+
+	It("should log in again once the Vault token expires", func() {
+		_, err := client.Read(context.Background(), "secret/team-a/aws")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		now = now.Add(time.Hour)
+		_, err = client.Read(context.Background(), "secret/team-a/aws")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(atomic.LoadInt32(&server.logins)).Should(BeEquivalentTo(2))
+	})
+
+	It("should log in again once the Vault token is rejected", func() {
+		_, err := client.Read(context.Background(), "secret/team-a/aws")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		atomic.StoreInt32(&server.revoked, 1)
+		_, err = client.Read(context.Background(), "secret/team-a/aws")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(atomic.LoadInt32(&server.logins)).Should(BeEquivalentTo(2))
+	})
+
+	It("should fail if the role of the operator is rejected", func() {
+		client.role = "admin"
+		_, err := client.Read(context.Background(), "secret/team-a/aws")
+		Expect(err).Should(HaveOccurred())
+		Expect(atomic.LoadInt32(&server.logins)).Should(BeZero())
+	})
+
+	It("should reject the paths without a secrets engine", func() {
+		_, err := client.Read(context.Background(), "aws")
+		Expect(err).Should(HaveOccurred())
+	})
+})
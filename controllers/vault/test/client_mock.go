@@ -0,0 +1,47 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	controllersvault "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault"
+)
+
+// Secrets holds the secrets of the mock Vault by path
+var Secrets sync.Map
+
+type mockClient struct{}
+
+func NewClient() controllersvault.Client {
+	return &mockClient{}
+}
+
+func (c *mockClient) Read(ctx context.Context, path string) (map[string]string, error) {
+	v, ok := Secrets.Load(path)
+	if !ok {
+		return nil, fmt.Errorf("vault path %s not found", path)
+	}
+	return v.(map[string]string), nil
+}
+
+func (c *mockClient) Write(ctx context.Context, path string, data map[string]string) error {
+	Secrets.Store(path, data)
+	return nil
+}
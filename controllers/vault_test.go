@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	controllersvaulttest "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Vault", func() {
+	var inventory *rdsdbaasv1alpha1.RDSInventory
+
+	BeforeEach(func() {
+		inventory = &rdsdbaasv1alpha1.RDSInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team-a",
+				Name:      "inventory",
+				Annotations: map[string]string{
+					vaultCredentialsPathAnnotation: "secret/team-a/aws",
+					vaultConnectionsPathAnnotation: "secret/team-a/connections",
+				},
+			},
+		}
+	})
+
+	It("should not read the AWS credentials from Vault if no Vault server is configured for the operator", func() {
+		r := &RDSInventoryReconciler{}
+		Expect(r.syncVaultCredentials(context.Background(), inventory)).ShouldNot(Succeed())
+
+		delete(inventory.Annotations, vaultCredentialsPathAnnotation)
+		Expect(r.syncVaultCredentials(context.Background(), inventory)).Should(Succeed())
+	})
+
+	It("should publish the connection credentials to the Vault path of the Inventory", func() {
+		r := &RDSConnectionReconciler{VaultClient: controllersvaulttest.NewClient()}
+		connection := &rdsdbaasv1alpha1.RDSConnection{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "connection"}}
+		configMap := &v1.ConfigMap{Data: map[string]string{"host": "db.example.com"}}
+
+		Expect(r.publishVaultCredentials(context.Background(), connection, inventory, pointer.String("user"), []byte("password"), configMap)).Should(Succeed())
+		data, ok := controllersvaulttest.Secrets.Load("secret/team-a/connections/team-a/connection")
+		Expect(ok).Should(BeTrue())
+		Expect(data).Should(Equal(map[string]string{"username": "user", "password": "password", "host": "db.example.com"}))
+
+		r = &RDSConnectionReconciler{}
+		Expect(r.publishVaultCredentials(context.Background(), connection, inventory, pointer.String("user"), []byte("password"), configMap)).ShouldNot(Succeed())
+	})
+})
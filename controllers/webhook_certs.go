@@ -0,0 +1,257 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	webhookCertCAKey   = "ca.crt"
+	webhookCertFile    = "tls.crt"
+	webhookCertKeyFile = "tls.key"
+
+	webhookCertValidity               = 365 * 24 * time.Hour
+	webhookCertRotationWindow         = 30 * 24 * time.Hour
+	defaultWebhookCertRefreshInterval = time.Hour
+)
+
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update
+
+// WebhookCertManager issues the self-signed serving certificate of the webhooks when the operator is not installed by OLM,
+// the certificate is kept in a Secret shared by the replicas, written to the certificate directory of the webhook server
+// and its CA is injected in the webhook configuration. The certificate is rotated before it expires.
+type WebhookCertManager struct {
+	Client client.Client
+	// APIReader reads the Secret and the webhook configuration that are not in the cache
+	APIReader                client.Reader
+	Namespace                string
+	SecretName               string
+	ServiceName              string
+	WebhookConfigurationName string
+	CertDir                  string
+	RefreshInterval          time.Duration
+}
+
+// NeedLeaderElection makes all the replicas write the certificate of their webhook server
+func (m *WebhookCertManager) NeedLeaderElection() bool {
+	return false
+}
+
+// Start rotates the certificate until the manager stops, Provision must be called before the webhook server starts
+func (m *WebhookCertManager) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("webhook-certs")
+	interval := m.RefreshInterval
+	if interval <= 0 {
+		interval = defaultWebhookCertRefreshInterval
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.Provision(ctx); err != nil {
+			logger.Error(err, "Failed to rotate webhook serving certificate")
+		}
+	}, interval)
+	return nil
+}
+
+// Provision makes sure the webhook server has a valid certificate that is trusted by the webhook configuration
+func (m *WebhookCertManager) Provision(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("webhook-certs")
+
+	secret := &v1.Secret{}
+	key := client.ObjectKey{Namespace: m.Namespace, Name: m.SecretName}
+	found := true
+	if err := m.APIReader.Get(ctx, key, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		found = false
+	}
+
+	if !found || webhookCertNeedsRotation(secret.Data, m.dnsNames(), time.Now()) {
+		caPEM, certPEM, keyPEM, err := generateWebhookCert(m.dnsNames(), time.Now())
+		if err != nil {
+			return err
+		}
+		data := map[string][]byte{
+			webhookCertCAKey:   caPEM,
+			webhookCertFile:    certPEM,
+			webhookCertKeyFile: keyPEM,
+		}
+		if found {
+			secret.Data = data
+			err = m.Client.Update(ctx, secret)
+		} else {
+			secret = &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      m.SecretName,
+					Namespace: m.Namespace,
+				},
+				Type: v1.SecretTypeTLS,
+				Data: data,
+			}
+			err = m.Client.Create(ctx, secret)
+		}
+		if err != nil {
+			if !errors.IsAlreadyExists(err) && !errors.IsConflict(err) {
+				return err
+			}
+			// another replica has rotated the certificate
+			if err := m.APIReader.Get(ctx, key, secret); err != nil {
+				return err
+			}
+		} else {
+			logger.Info("Webhook serving certificate issued", "expiration", time.Now().Add(webhookCertValidity))
+		}
+	}
+
+	if err := writeFileIfChanged(filepath.Join(m.CertDir, webhookCertFile), secret.Data[webhookCertFile]); err != nil {
+		return err
+	}
+	if err := writeFileIfChanged(filepath.Join(m.CertDir, webhookCertKeyFile), secret.Data[webhookCertKeyFile]); err != nil {
+		return err
+	}
+	return m.injectCABundle(ctx, secret.Data[webhookCertCAKey])
+}
+
+func (m *WebhookCertManager) dnsNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", m.ServiceName, m.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", m.ServiceName, m.Namespace),
+	}
+}
+
+func (m *WebhookCertManager) injectCABundle(ctx context.Context, caPEM []byte) error {
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := m.APIReader.Get(ctx, client.ObjectKey{Name: m.WebhookConfigurationName}, webhookConfiguration); err != nil {
+		return err
+	}
+	update := false
+	for i := range webhookConfiguration.Webhooks {
+		if !bytes.Equal(webhookConfiguration.Webhooks[i].ClientConfig.CABundle, caPEM) {
+			webhookConfiguration.Webhooks[i].ClientConfig.CABundle = caPEM
+			update = true
+		}
+	}
+	if !update {
+		return nil
+	}
+	return m.Client.Update(ctx, webhookConfiguration)
+}
+
+// webhookCertNeedsRotation checks if the certificate is missing, not valid for the DNS names or expires within the rotation window
+func webhookCertNeedsRotation(data map[string][]byte, dnsNames []string, now time.Time) bool {
+	pair, err := tls.X509KeyPair(data[webhookCertFile], data[webhookCertKeyFile])
+	if err != nil || len(data[webhookCertCAKey]) == 0 {
+		return true
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return true
+	}
+	if now.Add(webhookCertRotationWindow).After(cert.NotAfter) {
+		return true
+	}
+	for _, name := range dnsNames {
+		if cert.VerifyHostname(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateWebhookCert returns a self-signed CA and the serving certificate and key signed by it, PEM encoded
+func generateWebhookCert(dnsNames []string, now time.Time) ([]byte, []byte, []byte, error) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: "rds-dbaas-operator-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(webhookCertValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(webhookCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return caPEM, certPEM, keyPEM, nil
+}
+
+// writeFileIfChanged replaces the file atomically, so the certificate watcher of the webhook server never reads a partial file
+func writeFileIfChanged(path string, content []byte) error {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, content) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
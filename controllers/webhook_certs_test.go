@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookCertManager", func() {
+	dnsNames := []string{"webhook-service.ns.svc", "webhook-service.ns.svc.cluster.local"}
+
+	It("should issue a serving certificate signed by the CA", func() {
+		caPEM, certPEM, keyPEM, err := generateWebhookCert(dnsNames, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keyPEM).ShouldNot(BeEmpty())
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(caPEM)).Should(BeTrue())
+		block, _ := pem.Decode(certPEM)
+		Expect(block).ShouldNot(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = cert.Verify(x509.VerifyOptions{
+			DNSName:   dnsNames[0],
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		data := map[string][]byte{
+			webhookCertCAKey:   caPEM,
+			webhookCertFile:    certPEM,
+			webhookCertKeyFile: keyPEM,
+		}
+		Expect(webhookCertNeedsRotation(data, dnsNames, time.Now())).Should(BeFalse())
+	})
+
+	It("should rotate the certificate before it expires", func() {
+		caPEM, certPEM, keyPEM, err := generateWebhookCert(dnsNames, time.Now().Add(-webhookCertValidity+webhookCertRotationWindow/2))
+		Expect(err).ShouldNot(HaveOccurred())
+		data := map[string][]byte{
+			webhookCertCAKey:   caPEM,
+			webhookCertFile:    certPEM,
+			webhookCertKeyFile: keyPEM,
+		}
+		Expect(webhookCertNeedsRotation(data, dnsNames, time.Now())).Should(BeTrue())
+	})
+
+	It("should rotate the certificate if the service changes", func() {
+		caPEM, certPEM, keyPEM, err := generateWebhookCert(dnsNames, time.Now())
+		Expect(err).ShouldNot(HaveOccurred())
+		data := map[string][]byte{
+			webhookCertCAKey:   caPEM,
+			webhookCertFile:    certPEM,
+			webhookCertKeyFile: keyPEM,
+		}
+		Expect(webhookCertNeedsRotation(data, []string{"other-service.ns.svc"}, time.Now())).Should(BeTrue())
+		Expect(webhookCertNeedsRotation(map[string][]byte{}, dnsNames, time.Now())).Should(BeTrue())
+	})
+})
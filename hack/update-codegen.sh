@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+
+# Generates the typed clientset, listers and informers of the APIs of the operator in pkg/client
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+MODULE=github.com/RHEcosystemAppEng/rds-dbaas-operator
+OUTPUT_PACKAGE=${MODULE}/pkg/client
+LOCALBIN=${LOCALBIN:-$(pwd)/bin}
+HEADER=hack/boilerplate.go.txt
+
+# the generators take the group of the APIs from the directory of their package, and the api directory is taken for
+# the core group, the package is then generated from a link named after the group
+INPUT_DIR=.codegen
+trap 'rm -rf "${INPUT_DIR}" "${OUTPUT_BASE}"' EXIT
+mkdir -p "${INPUT_DIR}"
+ln -s ../api "${INPUT_DIR}/dbaas"
+INPUT_PACKAGE=${MODULE}/${INPUT_DIR}/dbaas/v1alpha1
+OUTPUT_BASE=$(mktemp -d)
+
+"${LOCALBIN}/client-gen" --go-header-file "${HEADER}" --output-base "${OUTPUT_BASE}" \
+  --clientset-name versioned --input-base "" --input "${INPUT_PACKAGE}" --output-package "${OUTPUT_PACKAGE}/clientset"
+"${LOCALBIN}/lister-gen" --go-header-file "${HEADER}" --output-base "${OUTPUT_BASE}" \
+  --input-dirs "${INPUT_PACKAGE}" --output-package "${OUTPUT_PACKAGE}/listers"
+"${LOCALBIN}/informer-gen" --go-header-file "${HEADER}" --output-base "${OUTPUT_BASE}" \
+  --input-dirs "${INPUT_PACKAGE}" --versioned-clientset-package "${OUTPUT_PACKAGE}/clientset/versioned" \
+  --listers-package "${OUTPUT_PACKAGE}/listers" --output-package "${OUTPUT_PACKAGE}/informers"
+
+rm -rf pkg/client
+mkdir -p pkg
+cp -r "${OUTPUT_BASE}/${OUTPUT_PACKAGE}" pkg/
+grep -rl "${INPUT_PACKAGE}" pkg/client | xargs sed -i "s|${INPUT_PACKAGE}|${MODULE}/api/v1alpha1|g"
@@ -20,8 +20,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
 	"go.uber.org/zap/zapcore"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -38,13 +41,22 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	dbaasv1beta1 "github.com/RHEcosystemAppEng/dbaas-operator/api/v1beta1"
 	rdsdbaasv1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
 	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers"
+	controllerscloudwatch "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/cloudwatch"
+	controllersec2 "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/ec2"
+	"github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/logging"
+	controllersorganizations "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/organizations"
 	controllersrds "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/rds"
+	controllerssecretsmanager "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/secretsmanager"
+	controllerssts "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/sts"
+	controllersvault "github.com/RHEcosystemAppEng/rds-dbaas-operator/controllers/vault"
 	rdsv1alpha1 "github.com/aws-controllers-k8s/rds-controller/apis/v1alpha1"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	//+kubebuilder:scaffold:imports
@@ -52,6 +64,13 @@ import (
 
 const (
 	InstallNamespaceEnvVar = "INSTALL_NAMESPACE"
+
+	// the events of the last day are dumped with the state
+	stateDumpEventsMaxAge = 24 * time.Hour
+
+	defaultExtraParametersAllowList = "autoMinorVersionUpgrade,backupRetentionPeriod,enableCloudwatchLogsExports," +
+		"enableIAMDatabaseAuthentication,monitoringInterval,monitoringRoleARN,multiAZ,networkType,performanceInsightsEnabled," +
+		"performanceInsightsKMSKeyID,performanceInsightsRetentionPeriod,preferredBackupWindow,preferredMaintenanceWindow"
 )
 
 var (
@@ -68,6 +87,7 @@ func init() {
 	utilruntime.Must(ackv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(appsv1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(operatorsv2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -79,6 +99,61 @@ func main() {
 	var logLevel string
 	var rdsControllerRetries int
 	var rdsControllerInterval time.Duration
+	var provisioningOptionsRefreshInterval time.Duration
+	var circuitBreakerFailureThreshold int
+	var circuitBreakerCoolDown time.Duration
+	var awsAPIDailyBudget int64
+	var awsAPIBudgetSyncInterval time.Duration
+	var regionOutageStalenessTTL time.Duration
+	var inventorySyncShards int
+	var inventorySyncShardQPS float64
+	var enableExtraParameters bool
+	var extraParametersAllowList string
+	var connectionTestPostgreSQLImage string
+	var connectionTestMySQLImage string
+	var connectionPoolerImage string
+	var cloudWatchMetricsInterval time.Duration
+	var highPriorityMaxWait time.Duration
+	var hubKubeconfig string
+	var spokeUser string
+	var webhookSelfSignedCerts bool
+	var webhookCertDir string
+	var webhookServiceName string
+	var webhookConfigurationName string
+	var operatorDeploymentName string
+	var ownerClusterRoleName string
+	var providerRegistrationDir string
+	var storageFullRemediationPercent int64
+	var dbInstanceIdentifierStrategy string
+	var dbInstanceIdentifierPrefix string
+	var freezeWindowsValue string
+	var enableMonitoringResources bool
+	var operatorHealthInterval time.Duration
+	var grafanaInstanceSelector string
+	var monitoringSyncFailureFor time.Duration
+	var gracefulShutdownTimeout time.Duration
+	var deletionProtectionPolicy string
+	var namespaceAllowList, namespaceDenyList, namespaceDefaultPolicy string
+	var enableFaultInjection bool
+	var dumpState string
+	var faultInjectionErrorRate, faultInjectionThrottlingRate float64
+	var faultInjectionLatency time.Duration
+	var faultInjectionOperations string
+	var enableMigrations bool
+	var monitoringThrottlingRate float64
+	var engineVersionEndOfLifeValue string
+	var engineVersionEndOfLifeWarning time.Duration
+	var storagePricesValue string
+	var webIdentityRoleARN string
+	var webIdentityTokenFile string
+	var webIdentityAudience string
+	var webIdentityAllowedRoleARNs string
+	var proposeEngineUpgrades bool
+	var inventoryExportInterval time.Duration
+	var inventoryExportHistory int
+	var vaultAddress string
+	var vaultAuthPath string
+	var vaultRole string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -88,45 +163,184 @@ func main() {
 	flag.StringVar(&logLevel, "log-level", "info", "Log level.")
 	flag.IntVar(&rdsControllerRetries, "wait-for-rds-controller-retries", 15, "The maximum times to check if the RDS controller is ready to run before setting up the Inventory controller.")
 	flag.DurationVar(&rdsControllerInterval, "wait-for-rds-controller-interval", 30*time.Second, "The interval at which to check if the RDS controller is ready to run before setting up the Inventory controller.")
-
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
-		//default to info level
-		level = zapcore.InfoLevel
-	}
+	flag.DurationVar(&provisioningOptionsRefreshInterval, "provisioning-options-refresh-interval", 24*time.Hour, "The interval at which to refresh the provisioning options of the provider registration from AWS (0 to disable).")
+	flag.IntVar(&circuitBreakerFailureThreshold, "aws-circuit-breaker-failure-threshold", 5, "The number of consecutive AWS call failures of an Inventory after which its AWS calls are suspended (0 to disable).")
+	flag.DurationVar(&circuitBreakerCoolDown, "aws-circuit-breaker-cool-down", 5*time.Minute, "The period for which the AWS calls of an Inventory are suspended once the circuit breaker trips.")
+	flag.Int64Var(&awsAPIDailyBudget, "aws-api-daily-budget", 0, "The number of AWS API calls made for an Inventory per day (UTC) above which it is synced at the minimum rate, unless overridden by the annotation of the Inventory (0 to disable).")
+	flag.DurationVar(&awsAPIBudgetSyncInterval, "aws-api-budget-sync-interval", time.Hour, "The minimum interval at which an Inventory is synced once its daily AWS API budget is spent.")
+	flag.DurationVar(&regionOutageStalenessTTL, "region-outage-staleness-ttl", controllers.DefaultRegionOutageStalenessTTL, "The time the DB services last synced for the region of an Inventory are reported once its AWS APIs fail, before they are excluded as stale.")
+	flag.IntVar(&inventorySyncShards, "inventory-sync-shards", 8, "The number of shards across which the DB instances of an Inventory are synced concurrently (1 to disable sharding).")
+	flag.Float64Var(&inventorySyncShardQPS, "inventory-sync-shard-qps", 10, "The maximum number of DB instances synced per second by each shard of the Inventory sync (0 for no limit).")
+	flag.BoolVar(&enableExtraParameters, "enable-extra-parameters", false, "Enable the ExtraParameters provisioning parameter of Instances to pass DB Instance spec fields through.")
+	flag.StringVar(&connectionTestPostgreSQLImage, "connection-test-postgresql-image", controllers.DefaultConnectionTestPostgreSQLImage, "The image with the psql client of the connection test Jobs of PostgreSQL Connections.")
+	flag.StringVar(&connectionTestMySQLImage, "connection-test-mysql-image", controllers.DefaultConnectionTestMySQLImage, "The image with the mysql client of the connection test Jobs of MySQL and MariaDB Connections.")
+	flag.StringVar(&connectionPoolerImage, "connection-pooler-image", controllers.DefaultConnectionPoolerImage, "The pgbouncer image of the connection poolers of PostgreSQL Connections.")
+	flag.DurationVar(&cloudWatchMetricsInterval, "cloudwatch-metrics-interval", 0, "The interval at which to relay the CloudWatch metrics of the DB services of ready Connections as Prometheus metrics (0 to disable).")
+	flag.DurationVar(&highPriorityMaxWait, "high-priority-max-wait", 10*time.Second, "The maximum time each Inventory sync pauses in total and the requests of ready Connections triggered by DB service changes are deferred while Connections in their first bind attempt are reconciled (0 to disable).")
+	flag.StringVar(&hubKubeconfig, "hub-kubeconfig", "", "The kubeconfig of the hub cluster running the Inventories, if set the operator runs in spoke mode and only reconciles the Connections against the hub.")
+	flag.StringVar(&spokeUser, "spoke-user", "", "The user of the spoke operators in the hub cluster, if set the hub publishes the TLS requirements of the DB services and grants the user the read of their master password Secrets only.")
+	flag.BoolVar(&webhookSelfSignedCerts, "webhook-self-signed-certs", false, "Issue and rotate a self-signed serving certificate for the webhooks, for the installs without OLM or cert-manager.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory of the serving certificate of the webhook server.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "rds-dbaas-operator-webhook-service", "The Service of the webhook server, used for the self-signed serving certificate.")
+	flag.StringVar(&webhookConfigurationName, "webhook-configuration-name", "rds-dbaas-operator-validating-webhook-configuration", "The ValidatingWebhookConfiguration in which the CA of the self-signed serving certificate is injected.")
+	flag.StringVar(&operatorDeploymentName, "operator-deployment-name", "rds-dbaas-operator-controller-manager", "The Deployment of the operator when it is not installed by OLM (OPERATOR_CONDITION_NAME not set).")
+	flag.StringVar(&ownerClusterRoleName, "owner-cluster-role-name", "rds-dbaas-operator-manager-role", "The ClusterRole owning the provider registration when the operator is not installed by OLM, the registration has no owner if not found.")
+	flag.StringVar(&providerRegistrationDir, "provider-registration-dir", "", "The directory of the provider registration file rds_registration.yaml, the registration is updated when the file changes.")
+	flag.Int64Var(&storageFullRemediationPercent, "storage-full-remediation-percent", 0, "The percentage by which the allocated storage of a storage-full DB instance is increased, unless overridden by the annotation of its Instance (0 to disable).")
+	flag.StringVar(&dbInstanceIdentifierStrategy, "db-instance-identifier-strategy", controllers.DBInstanceIdentifierStrategyUUID, "The strategy of the identifiers generated for the DB instances of the Instances without the Name provisioning parameter, uuid (prefix, engine and UUID) or name (prefix, namespace and name of the Instance, and a hash).")
+	flag.StringVar(&dbInstanceIdentifierPrefix, "db-instance-identifier-prefix", controllers.DefaultDBInstanceIdentifierPrefix, "The prefix of the identifiers generated for the DB instances.")
+	flag.StringVar(&freezeWindowsValue, "freeze-windows", "", "The semicolon-separated freeze windows during which the DB instances, parameter groups and option groups are not modified, each a cron expression (UTC) followed by a duration, e.g. \"0 8 * * mon-fri 10h\", in addition to the freeze windows of the annotation of the Inventories.")
+	flag.BoolVar(&enableMonitoringResources, "enable-monitoring-resources", false, "Create a GrafanaDashboard and a PrometheusRule with the alerts of the operator metrics in the install namespace.")
+	flag.DurationVar(&operatorHealthInterval, "operator-health-interval", controllers.DefaultOperatorHealthInterval, "The interval at which the Inventories failing to sync and the failed webhook requests are set in the Degraded condition of the OperatorCondition of the operator installed by OLM (0 to disable).")
+	flag.StringVar(&grafanaInstanceSelector, "grafana-instance-selector", "dashboards=grafana", "The comma-separated labels (key=value) of the Grafana instances importing the dashboard of the operator metrics.")
+	flag.DurationVar(&monitoringSyncFailureFor, "monitoring-sync-failure-for", controllers.DefaultMonitoringSyncFailureFor, "The time an Inventory fails to sync before it is alerted on.")
+	flag.Float64Var(&monitoringThrottlingRate, "monitoring-throttling-rate", controllers.DefaultMonitoringThrottlingRate, "The rate of throttled AWS calls per second of an Inventory above which it is alerted on.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second, "The time the reconciles in progress are given to complete their AWS calls and record them in the status once the operator is stopped (0 to cancel them immediately).")
+	flag.StringVar(&deletionProtectionPolicy, "deletion-protection-policy", controllers.DeletionProtectionPolicyRetain, "The policy of the deletion protected DB instances of the deleted Instances, retain (kept in AWS) or delete (the deletion protection is lifted to delete them), the annotation of the Instance can only narrow delete to retain.")
+	flag.StringVar(&namespaceAllowList, "namespace-allow-list", "", "The comma-separated namespaces, or glob patterns such as team-*, in which the Inventories and Connections are reconciled when the namespace default policy is deny.")
+	flag.StringVar(&namespaceDenyList, "namespace-deny-list", "", "The comma-separated namespaces, or glob patterns such as team-*, in which the Inventories and Connections are not reconciled, it takes precedence over the allow list.")
+	flag.StringVar(&namespaceDefaultPolicy, "namespace-default-policy", controllers.NamespacePolicyAllow, "The policy of the namespaces not in the namespace allow and deny lists, allow (the Inventories and Connections are reconciled) or deny (they are not).")
+	flag.BoolVar(&enableFaultInjection, "enable-fault-injection", false, "Inject errors, throttling and latency in the RDS API calls to validate the alerting and the backoff of the operator, for non-production clusters only.")
+	flag.Float64Var(&faultInjectionErrorRate, "fault-injection-error-rate", 0, "The rate (0 to 1) of the RDS API call attempts failing with an injected error when the fault injection is enabled.")
+	flag.Float64Var(&faultInjectionThrottlingRate, "fault-injection-throttling-rate", 0, "The rate (0 to 1) of the RDS API call attempts failing with an injected throttling error when the fault injection is enabled.")
+	flag.DurationVar(&faultInjectionLatency, "fault-injection-latency", 0, "The latency added to the RDS API call attempts when the fault injection is enabled.")
+	flag.StringVar(&faultInjectionOperations, "fault-injection-operations", "", "The comma-separated RDS API operations the faults are injected in, e.g. DescribeDBInstances, all the operations if empty.")
+	flag.StringVar(&dumpState, "dump-state", "", "Dump the resources of the operator and of the RDS controller, their recent events and the redacted configuration of the operator into a gzipped tarball and exit, the tarball is written to the directory (e.g. the mount of a PVC) or to the standard output if -.")
+	flag.BoolVar(&enableMigrations, "enable-migrations", false, "Enable the RDSMigrations running the Jobs that dump source databases and restore them to Instances, with the images of the connection tests.")
+	flag.StringVar(&engineVersionEndOfLifeValue, "engine-version-end-of-life", "", "The comma-separated end of life dates of engine major versions, e.g. \"postgres:10=2023-04-17,mysql:5.7=2024-02-29\", the DB instances on a major version reaching its end of life soon are flagged like the ones on a deprecated engine version.")
+	flag.StringVar(&storagePricesValue, "storage-prices", "", "The comma-separated monthly prices per GiB of the storage types, e.g. \"gp2=0.115,gp3=0.08\", the saving of the conversion of the gp2 storage of the DB instances to gp3 is estimated with them.")
+	flag.StringVar(&webIdentityRoleARN, "web-identity-role-arn", os.Getenv("AWS_ROLE_ARN"), "The role assumed with the projected service account token of the operator, from which the roles of the Inventories whose credentials Secret has no static credentials are assumed (AWS_ROLE_ARN if not set, disabled if empty).")
+	flag.StringVar(&webIdentityTokenFile, "web-identity-token-file", os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), "The projected service account token file of the operator, read again at every refresh of the credentials as it is rotated (AWS_WEB_IDENTITY_TOKEN_FILE if not set).")
+	flag.StringVar(&webIdentityAudience, "web-identity-audience", "", "The audience the projected service account token must be issued for, e.g. sts.amazonaws.com, not checked if empty.")
+	flag.StringVar(&webIdentityAllowedRoleARNs, "web-identity-allowed-role-arns", "", "The comma-separated role ARN patterns the Inventories without static credentials may assume with the web identity, in their AWS_ROLE_ARN credential, e.g. arn:aws:iam::123456789012:role/rds-* (none if empty).")
+	flag.DurationVar(&engineVersionEndOfLifeWarning, "engine-version-end-of-life-warning", controllers.DefaultEngineVersionEndOfLifeWarning, "The time before the end of life of an engine major version its DB instances are flagged.")
+	flag.BoolVar(&proposeEngineUpgrades, "propose-engine-upgrades", false, "Propose the latest minor version upgrade of the DB instances on a deprecated engine version in the annotation of their Instance, the upgrade is applied once approved by the annotation.")
+	flag.DurationVar(&inventoryExportInterval, "inventory-export-interval", 0, "The interval at which the DB services of an Inventory are exported as JSON to the <inventory>-export ConfigMap of its namespace, keeping a timestamped history for audit (0 to disable).")
+	flag.IntVar(&inventoryExportHistory, "inventory-export-history", controllers.DefaultInventoryExportHistory, "The number of exports of the DB services of an Inventory kept in its export ConfigMap, the oldest are also pruned to keep the ConfigMap below its maximum size.")
+	flag.StringVar(&vaultAddress, "vault-address", "", "The address of the HashiCorp Vault server the AWS credentials of the Inventories are read from and the connection credentials are published to, at the paths of the annotations of the Inventories (disabled if empty).")
+	flag.StringVar(&vaultAuthPath, "vault-auth-path", "kubernetes", "The path of the Kubernetes auth method of the Vault server the operator logs in with its service account token.")
+	flag.StringVar(&vaultRole, "vault-role", "", "The role of the operator in the Kubernetes auth method of the Vault server.")
+	flag.StringVar(&extraParametersAllowList, "extra-parameters-allow-list", defaultExtraParametersAllowList, "The comma-separated DB Instance spec fields that are allowed in the ExtraParameters provisioning parameter of Instances.")
 
 	opts := zap.Options{
 		Development: true,
-		Level:       level,
 		TimeEncoder: zapcore.RFC3339TimeEncoder,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	level, err := logging.ParseLevel(logLevel)
+	if err != nil {
+		//default to info level
+		level = zapcore.InfoLevel
+	}
+	// the levels by controller filter the entries, which are adjusted at runtime on the changes of the log levels ConfigMap
+	logLevels := logging.NewLevels(level)
+	if opts.Level == nil {
+		opts.Level = zapcore.Level(-127)
+	}
+	opts.ZapOpts = append(opts.ZapOpts, logging.WrapCore(logLevels))
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if len(dumpState) > 0 {
+		if err := runStateDump(dumpState); err != nil {
+			setupLog.Error(err, "unable to dump state")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	freezeWindows, err := controllers.ParseFreezeWindows(freezeWindowsValue)
+	if err != nil {
+		setupLog.Error(err, "invalid freeze windows")
+		os.Exit(1)
+	}
+
+	engineVersionEndOfLife, err := controllers.ParseEngineVersionEndOfLife(engineVersionEndOfLifeValue)
+	if err != nil {
+		setupLog.Error(err, "invalid engine version end of life")
+		os.Exit(1)
+	}
+
+	storagePrices, err := controllers.ParseStoragePrices(storagePricesValue)
+	if err != nil {
+		setupLog.Error(err, "invalid storage prices")
+		os.Exit(1)
+	}
+
+	namespacePolicy, err := controllers.NewNamespacePolicy(namespaceAllowList, namespaceDenyList, namespaceDefaultPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid namespace policy")
+		os.Exit(1)
+	}
+
+	var vaultClient controllersvault.Client
+	if len(vaultAddress) > 0 {
+		if len(vaultRole) == 0 {
+			setupLog.Error(fmt.Errorf("--vault-role is required with --vault-address"), "invalid Vault configuration")
+			os.Exit(1)
+		}
+		vaultClient = controllersvault.NewClient(vaultAddress, vaultAuthPath, vaultRole)
+		setupLog.Info("Vault enabled for the Inventories with Vault paths", "address", vaultAddress, "authPath", vaultAuthPath)
+	}
+
+	if len(webIdentityRoleARN) > 0 {
+		var allowedRoleARNs []string
+		for _, pattern := range strings.Split(webIdentityAllowedRoleARNs, ",") {
+			if pattern = strings.TrimSpace(pattern); len(pattern) > 0 {
+				allowedRoleARNs = append(allowedRoleARNs, pattern)
+			}
+		}
+		webIdentity, err := controllerssts.NewWebIdentity(webIdentityRoleARN, webIdentityTokenFile, webIdentityAudience,
+			allowedRoleARNs)
+		if err != nil {
+			setupLog.Error(err, "invalid web identity")
+			os.Exit(1)
+		}
+		controllerssts.EnableWebIdentity(webIdentity)
+		setupLog.Info("web identity enabled for the Inventories without static credentials", "roleARN", webIdentityRoleARN,
+			"tokenFile", webIdentityTokenFile, "allowedRoleARNs", allowedRoleARNs)
+	}
+
+	if enableFaultInjection {
+		faultInjection, err := controllersrds.NewFaultInjection(faultInjectionErrorRate, faultInjectionThrottlingRate,
+			faultInjectionLatency, faultInjectionOperations)
+		if err != nil {
+			setupLog.Error(err, "invalid fault injection")
+			os.Exit(1)
+		}
+		controllersrds.EnableFaultInjection(faultInjection)
+		setupLog.Info("fault injection enabled for the RDS API calls", "errorRate", faultInjectionErrorRate,
+			"throttlingRate", faultInjectionThrottlingRate, "latency", faultInjectionLatency)
+	}
+
+	newCache := cache.BuilderWithOptions(cache.Options{
+		SelectorsByObject: cache.SelectorsByObject{
+			&v1.Secret{}: {
+				Label: labels.SelectorFromSet(labels.Set{
+					dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue,
+				}),
+			},
+			&v1.ConfigMap{}: {
+				Label: labels.SelectorFromSet(labels.Set{
+					dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue,
+				}),
+			},
+		},
+	})
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
+		CertDir:                webhookCertDir,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "47bdf935.redhat.com",
 		SyncPeriod:             &syncPeriod,
-		NewCache: cache.BuilderWithOptions(cache.Options{
-			SelectorsByObject: cache.SelectorsByObject{
-				&v1.Secret{}: {
-					Label: labels.SelectorFromSet(labels.Set{
-						dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue,
-					}),
-				},
-				&v1.ConfigMap{}: {
-					Label: labels.SelectorFromSet(labels.Set{
-						dbaasv1beta1.TypeLabelKey: dbaasv1beta1.TypeLabelValue,
-					}),
-				},
-			},
-		}),
+		NewCache:               newCache,
+
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -145,52 +359,339 @@ func main() {
 		setupLog.Error(err, "unable to retrieve install namespace")
 	}
 
-	if err = (&controllers.RDSInventoryReconciler{
-		Client:                             mgr.GetClient(),
-		Scheme:                             mgr.GetScheme(),
-		GetDescribeDBInstancesPaginatorAPI: controllersrds.NewDescribeDBInstancesPaginator,
-		GetModifyDBInstanceAPI:             controllersrds.NewModifyDBInstance,
-		GetDescribeDBInstancesAPI:          controllersrds.NewDescribeDBInstances,
-		GetDescribeDBClustersPaginatorAPI:  controllersrds.NewDescribeDBClustersPaginator,
-		GetModifyDBClusterAPI:              controllersrds.NewModifyDBCluster,
-		GetDescribeDBClustersAPI:           controllersrds.NewDescribeDBClusters,
-		ACKInstallNamespace:                installNamespace,
-		WaitForRDSControllerInterval:       rdsControllerInterval,
-		WaitForRDSControllerRetries:        rdsControllerRetries,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "RDSInventory")
-		os.Exit(1)
+	ctx := ctrl.SetupSignalHandler()
+
+	circuitBreaker := controllers.NewCircuitBreaker(circuitBreakerFailureThreshold, circuitBreakerCoolDown)
+	apiBudget := controllers.NewAPIBudget(awsAPIDailyBudget, awsAPIBudgetSyncInterval)
+	priority := controllers.NewReconcilePriority(highPriorityMaxWait)
+	gracefulShutdown := controllers.NewGracefulShutdown(ctx, gracefulShutdownTimeout)
+
+	var extraParametersAllowed []string
+	for _, key := range strings.Split(extraParametersAllowList, ",") {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			extraParametersAllowed = append(extraParametersAllowed, key)
+		}
+	}
+
+	// in spoke mode the Inventories and the DB services are read from the hub, which is the only cluster with AWS credentials
+	var hub cluster.Cluster
+	if len(hubKubeconfig) > 0 {
+		if hub, err = controllers.NewHubCluster(hubKubeconfig, scheme, newCache); err != nil {
+			setupLog.Error(err, "unable to create hub cluster")
+			os.Exit(1)
+		}
+		if err = mgr.Add(hub); err != nil {
+			setupLog.Error(err, "unable to add hub cluster")
+			os.Exit(1)
+		}
+		setupLog.Info("running in spoke mode, only the Connection controller is started")
+	}
+
+	if hub == nil {
+		if err = (&controllers.RDSInventoryReconciler{
+			Client:                               mgr.GetClient(),
+			Scheme:                               mgr.GetScheme(),
+			GetDescribeDBInstancesPaginatorAPI:   controllersrds.NewDescribeDBInstancesPaginator,
+			GetModifyDBInstanceAPI:               controllersrds.NewModifyDBInstance,
+			GetDescribeDBInstancesAPI:            controllersrds.NewDescribeDBInstances,
+			GetDescribeDBClustersPaginatorAPI:    controllersrds.NewDescribeDBClustersPaginator,
+			GetModifyDBClusterAPI:                controllersrds.NewModifyDBCluster,
+			GetDescribeDBClustersAPI:             controllersrds.NewDescribeDBClusters,
+			GetDescribeEventsAPI:                 controllersrds.NewDescribeEvents,
+			GetDescribeDBSnapshotsAPI:            controllersrds.NewDescribeDBSnapshots,
+			GetDescribeDBSubnetGroupsAPI:         controllersrds.NewDescribeDBSubnetGroups,
+			GetListTagsForResourceAPI:            controllersrds.NewListTagsForResource,
+			VaultClient:                          vaultClient,
+			CircuitBreaker:                       circuitBreaker,
+			APIBudget:                            apiBudget,
+			ShardedSync:                          controllers.NewShardedSync(inventorySyncShards, inventorySyncShardQPS),
+			Recorder:                             mgr.GetEventRecorderFor("rdsinventory-controller"),
+			ACKInstallNamespace:                  installNamespace,
+			WaitForRDSControllerInterval:         rdsControllerInterval,
+			RegionOutageStalenessTTL:             regionOutageStalenessTTL,
+			WaitForRDSControllerRetries:          rdsControllerRetries,
+			Priority:                             priority,
+			GracefulShutdown:                     gracefulShutdown,
+			NamespacePolicy:                      namespacePolicy,
+			ExportInterval:                       inventoryExportInterval,
+			ExportHistory:                        inventoryExportHistory,
+			GetListAccountsForParentPaginatorAPI: controllersorganizations.NewListAccountsForParentPaginator,
+			GetAssumeRoleDescribeDBInstancesPaginatorAPI: controllersrds.NewAssumeRoleDescribeDBInstancesPaginator,
+			GetAssumeRoleDescribeDBClustersPaginatorAPI:  controllersrds.NewAssumeRoleDescribeDBClustersPaginator,
+			SpokeUser:                         spokeUser,
+			GetDescribeDBParametersAPI:        controllersrds.NewDescribeDBParameters,
+			GetDescribeDBClusterParametersAPI: controllersrds.NewDescribeDBClusterParameters,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSInventory")
+			os.Exit(1)
+		}
 	}
 	if err = (&controllers.RDSConnectionReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                            mgr.GetClient(),
+		Scheme:                            mgr.GetScheme(),
+		Recorder:                          mgr.GetEventRecorderFor("rdsconnection-controller"),
+		GetGetSecretValueAPI:              controllerssecretsmanager.NewGetSecretValue,
+		VaultClient:                       vaultClient,
+		CircuitBreaker:                    circuitBreaker,
+		APIBudget:                         apiBudget,
+		GetDescribeDBParametersAPI:        controllersrds.NewDescribeDBParameters,
+		GetDescribeDBClusterParametersAPI: controllersrds.NewDescribeDBClusterParameters,
+		Hub:                               hub,
+		ConnectionTestPostgreSQLImage:     connectionTestPostgreSQLImage,
+		ConnectionTestMySQLImage:          connectionTestMySQLImage,
+		ConnectionPoolerImage:             connectionPoolerImage,
+		GetGetMetricDataAPI:               controllerscloudwatch.NewGetMetricData,
+		CloudWatchMetricsInterval:         cloudWatchMetricsInterval,
+		Priority:                          priority,
+		NamespacePolicy:                   namespacePolicy,
+		JobNamespace:                      installNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RDSConnection")
 		os.Exit(1)
 	}
-	if err = (&controllers.RDSInstanceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "RDSInstance")
-		os.Exit(1)
+	if hub == nil {
+		if err = (&controllers.RDSInstanceReconciler{
+			Client:                                  mgr.GetClient(),
+			Scheme:                                  mgr.GetScheme(),
+			APIReader:                               mgr.GetAPIReader(),
+			GetDescribeDBSubnetGroupsAPI:            controllersrds.NewDescribeDBSubnetGroups,
+			GetDescribeSecurityGroupsAPI:            controllersec2.NewDescribeSecurityGroups,
+			GetDescribeSubnetsAPI:                   controllersec2.NewDescribeSubnets,
+			GetCreateSecretAPI:                      controllerssecretsmanager.NewCreateSecret,
+			GetPutSecretValueAPI:                    controllerssecretsmanager.NewPutSecretValue,
+			GetDeleteSecretAPI:                      controllerssecretsmanager.NewDeleteSecret,
+			GetRestoreDBInstanceFromS3API:           controllersrds.NewRestoreDBInstanceFromS3,
+			GetRestoreDBInstanceToPointInTimeAPI:    controllersrds.NewRestoreDBInstanceToPointInTime,
+			GetModifyDBInstanceAPI:                  controllersrds.NewModifyDBInstance,
+			GetDescribeDBInstancesAPI:               controllersrds.NewDescribeDBInstances,
+			GetDescribePendingMaintenanceActionsAPI: controllersrds.NewDescribePendingMaintenanceActions,
+			EnableExtraParameters:                   enableExtraParameters,
+			ExtraParametersAllowList:                extraParametersAllowed,
+			Recorder:                                mgr.GetEventRecorderFor("rdsinstance-controller"),
+			StorageFullRemediationPercent:           storageFullRemediationPercent,
+			DBInstanceIdentifierStrategy:            dbInstanceIdentifierStrategy,
+			DBInstanceIdentifierPrefix:              dbInstanceIdentifierPrefix,
+			FreezeWindows:                           freezeWindows,
+			GracefulShutdown:                        gracefulShutdown,
+			DeletionProtectionPolicy:                deletionProtectionPolicy,
+			GetDescribeDBEngineVersionsAPI:          controllersrds.NewDescribeDBEngineVersions,
+			EngineVersionEndOfLife:                  engineVersionEndOfLife,
+			EngineVersionEndOfLifeWarning:           engineVersionEndOfLifeWarning,
+			StoragePrices:                           storagePrices,
+			ProposeEngineUpgrades:                   proposeEngineUpgrades,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSInstance")
+			os.Exit(1)
+		}
+		if err = (&controllers.RDSSnapshotReconciler{
+			Client:                          mgr.GetClient(),
+			Scheme:                          mgr.GetScheme(),
+			GetCreateDBSnapshotAPI:          controllersrds.NewCreateDBSnapshot,
+			GetDescribeDBSnapshotsAPI:       controllersrds.NewDescribeDBSnapshots,
+			GetModifyDBSnapshotAttributeAPI: controllersrds.NewModifyDBSnapshotAttribute,
+			GetStartExportTaskAPI:           controllersrds.NewStartExportTask,
+			GetDescribeExportTasksAPI:       controllersrds.NewDescribeExportTasks,
+			GracefulShutdown:                gracefulShutdown,
+			NamespacePolicy:                 namespacePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSSnapshot")
+			os.Exit(1)
+		}
+		if err = (&controllers.RDSParameterGroupReconciler{
+			Client:                             mgr.GetClient(),
+			Scheme:                             mgr.GetScheme(),
+			GetCreateDBParameterGroupAPI:       controllersrds.NewCreateDBParameterGroup,
+			GetDescribeDBParameterGroupsAPI:    controllersrds.NewDescribeDBParameterGroups,
+			GetModifyDBParameterGroupAPI:       controllersrds.NewModifyDBParameterGroup,
+			GetDeleteDBParameterGroupAPI:       controllersrds.NewDeleteDBParameterGroup,
+			GetDescribeDBParametersAPI:         controllersrds.NewDescribeDBParameters,
+			GetDescribeDBInstancesPaginatorAPI: controllersrds.NewDescribeDBInstancesPaginator,
+			FreezeWindows:                      freezeWindows,
+			GracefulShutdown:                   gracefulShutdown,
+			NamespacePolicy:                    namespacePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSParameterGroup")
+			os.Exit(1)
+		}
+		if err = (&controllers.RDSOptionGroupReconciler{
+			Client:                           mgr.GetClient(),
+			Scheme:                           mgr.GetScheme(),
+			GetCreateOptionGroupAPI:          controllersrds.NewCreateOptionGroup,
+			GetDescribeOptionGroupsAPI:       controllersrds.NewDescribeOptionGroups,
+			GetModifyOptionGroupAPI:          controllersrds.NewModifyOptionGroup,
+			GetDeleteOptionGroupAPI:          controllersrds.NewDeleteOptionGroup,
+			GetDescribeOptionGroupOptionsAPI: controllersrds.NewDescribeOptionGroupOptions,
+			FreezeWindows:                    freezeWindows,
+			GracefulShutdown:                 gracefulShutdown,
+			NamespacePolicy:                  namespacePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSOptionGroup")
+			os.Exit(1)
+		}
+		if err = (&controllers.RDSEventSubscriptionReconciler{
+			Client:                                       mgr.GetClient(),
+			Scheme:                                       mgr.GetScheme(),
+			GetCreateEventSubscriptionAPI:                controllersrds.NewCreateEventSubscription,
+			GetDescribeEventSubscriptionsAPI:             controllersrds.NewDescribeEventSubscriptions,
+			GetModifyEventSubscriptionAPI:                controllersrds.NewModifyEventSubscription,
+			GetDeleteEventSubscriptionAPI:                controllersrds.NewDeleteEventSubscription,
+			GetAddSourceIdentifierToSubscriptionAPI:      controllersrds.NewAddSourceIdentifierToSubscription,
+			GetRemoveSourceIdentifierFromSubscriptionAPI: controllersrds.NewRemoveSourceIdentifierFromSubscription,
+			GracefulShutdown:                             gracefulShutdown,
+			NamespacePolicy:                              namespacePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RDSEventSubscription")
+			os.Exit(1)
+		}
+		if enableMigrations {
+			if err = (&controllers.RDSMigrationReconciler{
+				Client:           mgr.GetClient(),
+				Scheme:           mgr.GetScheme(),
+				PostgreSQLImage:  connectionTestPostgreSQLImage,
+				MySQLImage:       connectionTestMySQLImage,
+				GracefulShutdown: gracefulShutdown,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "RDSMigration")
+				os.Exit(1)
+			}
+		}
+		if err = (&controllers.DBaaSProviderReconciler{
+			Client:                                   mgr.GetClient(),
+			Scheme:                                   mgr.GetScheme(),
+			Clientset:                                clientSet,
+			GetDescribeDBEngineVersionsAPI:           controllersrds.NewDescribeDBEngineVersions,
+			GetDescribeOrderableDBInstanceOptionsAPI: controllersrds.NewDescribeOrderableDBInstanceOptions,
+			ProvisioningOptionsRefreshInterval:       provisioningOptionsRefreshInterval,
+			DBaaSProviderCRFilePath:                  providerRegistrationDir,
+			DeploymentName:                           operatorDeploymentName,
+			OwnerClusterRoleName:                     ownerClusterRoleName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DBaaSProvider")
+			os.Exit(1)
+		}
+
+		if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+			if err = (&rdsdbaasv1alpha1.RDSInventory{}).SetupWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "RDSInventory")
+				os.Exit(1)
+			}
+			if err = (&rdsdbaasv1alpha1.RDSInstance{}).SetupWebhookWithManager(mgr, enableExtraParameters, extraParametersAllowed); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "RDSInstance")
+				os.Exit(1)
+			}
+		}
 	}
-	if err = (&controllers.DBaaSProviderReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Clientset: clientSet,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "DBaaSProvider")
+	//+kubebuilder:scaffold:builder
+
+	if hub == nil {
+		if err := mgr.Add(&controllers.Migrator{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("rds-dbaas-migration"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up migration")
+			os.Exit(1)
+		}
+	}
+
+	// the state dump is served on the metrics endpoint, behind the auth proxy
+	if err := mgr.AddMetricsExtraHandler(controllers.StateDumpPath, &controllers.StateDump{
+		Reader:       mgr.GetAPIReader(),
+		Config:       controllers.RedactedFlags(flag.CommandLine),
+		EventsMaxAge: stateDumpEventsMaxAge,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up state dump")
 		os.Exit(1)
 	}
 
-	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = (&rdsdbaasv1alpha1.RDSInventory{}).SetupWebhookWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create webhook", "webhook", "RDSInventory")
+	if hub == nil {
+		// the status summary is served on the metrics endpoint, behind the auth proxy
+		if err := mgr.AddMetricsExtraHandler(controllers.StatusSummaryPath, &controllers.StatusSummary{
+			Client: mgr.GetClient(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up status summary")
+			os.Exit(1)
+		}
+	}
+
+	if enableMonitoringResources && hub == nil {
+		if len(installNamespace) == 0 {
+			setupLog.Error(fmt.Errorf("%s must be set", InstallNamespaceEnvVar), "unable to set up monitoring resources")
+			os.Exit(1)
+		}
+		selector, err := labels.ConvertSelectorToLabelsMap(grafanaInstanceSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to parse Grafana instance selector")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&controllers.MonitoringResources{
+			Client:                  mgr.GetClient(),
+			Namespace:               installNamespace,
+			GrafanaInstanceSelector: selector,
+			SyncFailureFor:          monitoringSyncFailureFor,
+			ThrottlingRate:          monitoringThrottlingRate,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up monitoring resources")
+			os.Exit(1)
+		}
+	}
+
+	if operatorConditionName, found := os.LookupEnv("OPERATOR_CONDITION_NAME"); found && operatorHealthInterval > 0 &&
+		len(installNamespace) > 0 {
+		if err := mgr.Add(&controllers.OperatorHealth{
+			Client:                mgr.GetClient(),
+			APIReader:             mgr.GetAPIReader(),
+			Namespace:             installNamespace,
+			OperatorConditionName: operatorConditionName,
+			Interval:              operatorHealthInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up operator health")
+			os.Exit(1)
+		}
+	}
+
+	if len(installNamespace) > 0 {
+		watcher, err := client.NewWithWatch(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		if err != nil {
+			setupLog.Error(err, "unable to create log levels watch client")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&controllers.LogLevelsWatcher{
+			APIReader:    mgr.GetAPIReader(),
+			Watcher:      watcher,
+			Namespace:    installNamespace,
+			Levels:       logLevels,
+			DefaultLevel: level,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up log levels watcher")
+			os.Exit(1)
+		}
+	}
+
+	if webhookSelfSignedCerts && hub == nil && os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if len(installNamespace) == 0 {
+			setupLog.Error(fmt.Errorf("%s must be set", InstallNamespaceEnvVar), "unable to set up webhook serving certificate")
+			os.Exit(1)
+		}
+		webhookCertManager := &controllers.WebhookCertManager{
+			Client:                   mgr.GetClient(),
+			APIReader:                mgr.GetAPIReader(),
+			Namespace:                installNamespace,
+			SecretName:               "rds-dbaas-operator-webhook-self-signed-cert",
+			ServiceName:              webhookServiceName,
+			WebhookConfigurationName: webhookConfigurationName,
+			CertDir:                  webhookCertDir,
+		}
+		// the certificate must be in place before the webhook server starts
+		if err := webhookCertManager.Provision(ctx); err != nil {
+			setupLog.Error(err, "unable to provision webhook serving certificate")
+			os.Exit(1)
+		}
+		if err := mgr.Add(webhookCertManager); err != nil {
+			setupLog.Error(err, "unable to set up webhook serving certificate rotation")
 			os.Exit(1)
 		}
 	}
-	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -202,7 +703,7 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -215,3 +716,30 @@ func getInstallNamespace() (string, error) {
 	}
 	return ns, nil
 }
+
+// runStateDump writes the state dump to the directory, or to the standard output if dir is -
+func runStateDump(dir string) error {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	dump := &controllers.StateDump{
+		Reader:       c,
+		Config:       controllers.RedactedFlags(flag.CommandLine),
+		EventsMaxAge: stateDumpEventsMaxAge,
+	}
+	ctx := ctrl.SetupSignalHandler()
+	if dir == "-" {
+		return dump.Write(ctx, os.Stdout)
+	}
+	f, err := os.Create(filepath.Join(dir, controllers.StateDumpFileName(time.Now())))
+	if err != nil {
+		return err
+	}
+	if err := dump.Write(ctx, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	setupLog.Info("state dumped", "file", f.Name())
+	return f.Close()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	internalinterfaces "github.com/RHEcosystemAppEng/rds-dbaas-operator/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// RDSConnections returns a RDSConnectionInformer.
+	RDSConnections() RDSConnectionInformer
+	// RDSInstances returns a RDSInstanceInformer.
+	RDSInstances() RDSInstanceInformer
+	// RDSInventories returns a RDSInventoryInformer.
+	RDSInventories() RDSInventoryInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// RDSConnections returns a RDSConnectionInformer.
+func (v *version) RDSConnections() RDSConnectionInformer {
+	return &rDSConnectionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// RDSInstances returns a RDSInstanceInformer.
+func (v *version) RDSInstances() RDSInstanceInformer {
+	return &rDSInstanceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// RDSInventories returns a RDSInventoryInformer.
+func (v *version) RDSInventories() RDSInventoryInformer {
+	return &rDSInventoryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RDSConnectionLister helps list RDSConnections.
+// All objects returned here must be treated as read-only.
+type RDSConnectionLister interface {
+	// List lists all RDSConnections in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSConnection, err error)
+	// RDSConnections returns an object that can list and get RDSConnections.
+	RDSConnections(namespace string) RDSConnectionNamespaceLister
+	RDSConnectionListerExpansion
+}
+
+// rDSConnectionLister implements the RDSConnectionLister interface.
+type rDSConnectionLister struct {
+	indexer cache.Indexer
+}
+
+// NewRDSConnectionLister returns a new RDSConnectionLister.
+func NewRDSConnectionLister(indexer cache.Indexer) RDSConnectionLister {
+	return &rDSConnectionLister{indexer: indexer}
+}
+
+// List lists all RDSConnections in the indexer.
+func (s *rDSConnectionLister) List(selector labels.Selector) (ret []*v1alpha1.RDSConnection, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSConnection))
+	})
+	return ret, err
+}
+
+// RDSConnections returns an object that can list and get RDSConnections.
+func (s *rDSConnectionLister) RDSConnections(namespace string) RDSConnectionNamespaceLister {
+	return rDSConnectionNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RDSConnectionNamespaceLister helps list and get RDSConnections.
+// All objects returned here must be treated as read-only.
+type RDSConnectionNamespaceLister interface {
+	// List lists all RDSConnections in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSConnection, err error)
+	// Get retrieves the RDSConnection from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RDSConnection, error)
+	RDSConnectionNamespaceListerExpansion
+}
+
+// rDSConnectionNamespaceLister implements the RDSConnectionNamespaceLister
+// interface.
+type rDSConnectionNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RDSConnections in the indexer for a given namespace.
+func (s rDSConnectionNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RDSConnection, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSConnection))
+	})
+	return ret, err
+}
+
+// Get retrieves the RDSConnection from the indexer for a given namespace and name.
+func (s rDSConnectionNamespaceLister) Get(name string) (*v1alpha1.RDSConnection, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("rdsconnection"), name)
+	}
+	return obj.(*v1alpha1.RDSConnection), nil
+}
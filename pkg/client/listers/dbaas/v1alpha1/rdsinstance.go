@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RDSInstanceLister helps list RDSInstances.
+// All objects returned here must be treated as read-only.
+type RDSInstanceLister interface {
+	// List lists all RDSInstances in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSInstance, err error)
+	// RDSInstances returns an object that can list and get RDSInstances.
+	RDSInstances(namespace string) RDSInstanceNamespaceLister
+	RDSInstanceListerExpansion
+}
+
+// rDSInstanceLister implements the RDSInstanceLister interface.
+type rDSInstanceLister struct {
+	indexer cache.Indexer
+}
+
+// NewRDSInstanceLister returns a new RDSInstanceLister.
+func NewRDSInstanceLister(indexer cache.Indexer) RDSInstanceLister {
+	return &rDSInstanceLister{indexer: indexer}
+}
+
+// List lists all RDSInstances in the indexer.
+func (s *rDSInstanceLister) List(selector labels.Selector) (ret []*v1alpha1.RDSInstance, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSInstance))
+	})
+	return ret, err
+}
+
+// RDSInstances returns an object that can list and get RDSInstances.
+func (s *rDSInstanceLister) RDSInstances(namespace string) RDSInstanceNamespaceLister {
+	return rDSInstanceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RDSInstanceNamespaceLister helps list and get RDSInstances.
+// All objects returned here must be treated as read-only.
+type RDSInstanceNamespaceLister interface {
+	// List lists all RDSInstances in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSInstance, err error)
+	// Get retrieves the RDSInstance from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RDSInstance, error)
+	RDSInstanceNamespaceListerExpansion
+}
+
+// rDSInstanceNamespaceLister implements the RDSInstanceNamespaceLister
+// interface.
+type rDSInstanceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RDSInstances in the indexer for a given namespace.
+func (s rDSInstanceNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RDSInstance, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSInstance))
+	})
+	return ret, err
+}
+
+// Get retrieves the RDSInstance from the indexer for a given namespace and name.
+func (s rDSInstanceNamespaceLister) Get(name string) (*v1alpha1.RDSInstance, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("rdsinstance"), name)
+	}
+	return obj.(*v1alpha1.RDSInstance), nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/RHEcosystemAppEng/rds-dbaas-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RDSInventoryLister helps list RDSInventories.
+// All objects returned here must be treated as read-only.
+type RDSInventoryLister interface {
+	// List lists all RDSInventories in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSInventory, err error)
+	// RDSInventories returns an object that can list and get RDSInventories.
+	RDSInventories(namespace string) RDSInventoryNamespaceLister
+	RDSInventoryListerExpansion
+}
+
+// rDSInventoryLister implements the RDSInventoryLister interface.
+type rDSInventoryLister struct {
+	indexer cache.Indexer
+}
+
+// NewRDSInventoryLister returns a new RDSInventoryLister.
+func NewRDSInventoryLister(indexer cache.Indexer) RDSInventoryLister {
+	return &rDSInventoryLister{indexer: indexer}
+}
+
+// List lists all RDSInventories in the indexer.
+func (s *rDSInventoryLister) List(selector labels.Selector) (ret []*v1alpha1.RDSInventory, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSInventory))
+	})
+	return ret, err
+}
+
+// RDSInventories returns an object that can list and get RDSInventories.
+func (s *rDSInventoryLister) RDSInventories(namespace string) RDSInventoryNamespaceLister {
+	return rDSInventoryNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RDSInventoryNamespaceLister helps list and get RDSInventories.
+// All objects returned here must be treated as read-only.
+type RDSInventoryNamespaceLister interface {
+	// List lists all RDSInventories in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RDSInventory, err error)
+	// Get retrieves the RDSInventory from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RDSInventory, error)
+	RDSInventoryNamespaceListerExpansion
+}
+
+// rDSInventoryNamespaceLister implements the RDSInventoryNamespaceLister
+// interface.
+type rDSInventoryNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RDSInventories in the indexer for a given namespace.
+func (s rDSInventoryNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RDSInventory, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RDSInventory))
+	})
+	return ret, err
+}
+
+// Get retrieves the RDSInventory from the indexer for a given namespace and name.
+func (s rDSInventoryNamespaceLister) Get(name string) (*v1alpha1.RDSInventory, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("rdsinventory"), name)
+	}
+	return obj.(*v1alpha1.RDSInventory), nil
+}